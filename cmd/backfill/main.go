@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/leaderboard-redis/internal/backfill"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/ids"
+	"github.com/leaderboard-redis/internal/postgres"
+	"github.com/leaderboard-redis/internal/redis"
+	"github.com/leaderboard-redis/internal/service"
+)
+
+// backfill replays historical scores from a legacy system into the
+// leaderboards. Progress is checkpointed after every batch; running the same
+// job again resumes after the last applied record.
+func main() {
+	configPath := flag.String("config", "config.yaml", "Path to the server configuration file")
+	jobPath := flag.String("job", "backfill.yaml", "Path to the backfill job file")
+	restart := flag.Bool("restart", false, "Ignore the checkpoint and read the source from the start")
+	dryRun := flag.Bool("dry-run", false, "Map and count records without submitting them")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading config: %v\n", err)
+		os.Exit(1)
+	}
+	job, err := backfill.LoadConfig(*jobPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	checkpoint := &backfill.Checkpoint{Source: job.Source.SourceID()}
+	if !*restart {
+		checkpoint, err = backfill.LoadCheckpoint(job)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if checkpoint.Done {
+			fmt.Printf("✅ Backfill already complete (%d records); pass -restart to run it again\n", checkpoint.Read)
+			return
+		}
+		if checkpoint.Position != "" {
+			fmt.Printf("Resuming after position %s (%d records read)\n", checkpoint.Position, checkpoint.Read)
+		}
+	}
+
+	// Ctrl-C stops after the batch in flight, leaving a checkpoint to resume from
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	svc, cleanup, err := setupService(ctx, cfg, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	source, err := backfill.NewSource(ctx, &job.Source, checkpoint.Position)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer source.Close()
+
+	runner := backfill.NewRunner(svc, job, logger)
+	runner.DryRun = *dryRun
+	err = runner.Run(ctx, source, checkpoint)
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("Stopped after position %s; run again to resume\n", checkpoint.Position)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Backfill stopped after position %s: %v\n", checkpoint.Position, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Read %d records: %d applied, %d duplicates, %d rejected, %d unmapped\n",
+		checkpoint.Read, checkpoint.Applied, checkpoint.Duplicates, checkpoint.Rejected, checkpoint.Unmapped)
+}
+
+// setupService wires the service the way the server does for ingestion, so
+// backfilled scores go through the same validation and transforms
+func setupService(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*service.LeaderboardService, func(), error) {
+	redisService, err := redis.NewLeaderboardService(&cfg.Redis, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	repo, err := postgres.NewRepository(&cfg.Postgres, logger)
+	if err != nil {
+		redisService.Close()
+		return nil, nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+
+	svc := service.NewLeaderboardService(redisService, repo, &cfg.Leaderboard, logger)
+	idPolicy, err := ids.New(&cfg.Leaderboard.IDs)
+	if err != nil {
+		repo.Close()
+		redisService.Close()
+		return nil, nil, fmt.Errorf("invalid leaderboard id policy: %w", err)
+	}
+	svc.SetIDPolicy(idPolicy)
+	if cfg.Leaderboard.Encryption.Enabled() {
+		cipher, err := service.NewMetadataCipher(&cfg.Leaderboard.Encryption)
+		if err != nil {
+			repo.Close()
+			redisService.Close()
+			return nil, nil, fmt.Errorf("invalid metadata encryption config: %w", err)
+		}
+		svc.SetMetadataCipher(cipher)
+	}
+
+	recorderCtx, stopRecorder := context.WithCancel(context.WithoutCancel(ctx))
+	recorder := service.NewEventRecorder(repo, &cfg.Events, logger)
+	svc.SetEventRecorder(recorder)
+	recorderDone := make(chan struct{})
+	go func() {
+		recorder.Run(recorderCtx)
+		close(recorderDone)
+	}()
+
+	cleanup := func() {
+		stopRecorder()
+		<-recorderDone
+		repo.Close()
+		redisService.Close()
+	}
+	return svc, cleanup, nil
+}
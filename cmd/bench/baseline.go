@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// benchResult is the per-operation cost of one benchmark
+type benchResult struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+}
+
+func newBenchResult(r testing.BenchmarkResult) benchResult {
+	return benchResult{
+		NsPerOp:     r.NsPerOp(),
+		AllocsPerOp: r.AllocsPerOp(),
+		BytesPerOp:  r.AllocedBytesPerOp(),
+	}
+}
+
+// loadBaseline reads stored results keyed by benchmark name
+func loadBaseline(path string) (map[string]benchResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline map[string]benchResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// saveBaseline writes results as the new baseline
+func saveBaseline(path string, results map[string]benchResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating baseline directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing baseline: %w", err)
+	}
+	return nil
+}
+
+// compare reports every benchmark whose time or allocations per operation grew
+// by more than threshold percent. Benchmarks missing from either side are ignored.
+func compare(baseline, results map[string]benchResult, threshold float64) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var regressions []string
+	for _, name := range names {
+		base, ok := baseline[name]
+		if !ok {
+			continue
+		}
+		got := results[name]
+		if exceeds(base.NsPerOp, got.NsPerOp, threshold) {
+			regressions = append(regressions, fmt.Sprintf("%s: %d ns/op, baseline %d ns/op", name, got.NsPerOp, base.NsPerOp))
+		}
+		if exceeds(base.AllocsPerOp, got.AllocsPerOp, threshold) {
+			regressions = append(regressions, fmt.Sprintf("%s: %d allocs/op, baseline %d allocs/op", name, got.AllocsPerOp, base.AllocsPerOp))
+		}
+	}
+	return regressions
+}
+
+// exceeds reports whether got is more than threshold percent above base
+func exceeds(base, got int64, threshold float64) bool {
+	if base <= 0 {
+		return false
+	}
+	return float64(got) > float64(base)*(1+threshold/100)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/kafka"
+	"github.com/leaderboard-redis/internal/postgres"
+	"github.com/leaderboard-redis/internal/redis"
+	"github.com/leaderboard-redis/internal/service"
+	"github.com/leaderboard-redis/internal/websocket"
+)
+
+// benchKeyPrefix keeps benchmark keys apart from the data of a shared Redis
+const benchKeyPrefix = "bench"
+
+// benchPlayers is how many players the benchmark leaderboard is seeded with
+const benchPlayers = 10000
+
+// benchCase is one named benchmark
+type benchCase struct {
+	name string
+	fn   func(b *testing.B)
+}
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	baselinePath := flag.String("baseline", "bench/baseline.json", "Baseline file to compare against or write")
+	update := flag.Bool("update", false, "Write the results as the new baseline instead of comparing")
+	threshold := flag.Float64("threshold", 15, "Allowed slowdown over the baseline, in percent")
+	only := flag.String("run", "", "Only run benchmarks whose name matches this regular expression")
+	count := flag.Int("count", 3, "Runs per benchmark; the fastest is kept to damp noise")
+	offline := flag.Bool("offline", false, "Skip benchmarks that need Redis and PostgreSQL")
+	flag.Parse()
+
+	filter, err := regexp.Compile(*only)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -run pattern: %v\n", err)
+		os.Exit(2)
+	}
+
+	cases := []benchCase{
+		{name: "HubBroadcast10k", fn: websocket.BenchmarkBroadcast(10000, websocket.EncodingJSON)},
+		{name: "HubBroadcast10kCompact", fn: websocket.BenchmarkBroadcast(10000, websocket.EncodingCompact)},
+	}
+	if !*offline {
+		svc, leaderboardID, cleanup, err := setupService(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v (use -offline to skip service benchmarks)\n", err)
+			os.Exit(1)
+		}
+		defer cleanup()
+		cases = append(cases,
+			benchCase{name: "SubmitScore", fn: benchSubmitScore(svc, leaderboardID)},
+			benchCase{name: "KafkaBatchFlush", fn: kafka.BenchmarkApplyBatch(svc, leaderboardID, 4, 100, benchPlayers)},
+			benchCase{name: "GetTopN", fn: benchGetTopN(svc, leaderboardID)},
+		)
+	}
+
+	results := make(map[string]benchResult)
+	for _, c := range cases {
+		if !filter.MatchString(c.name) {
+			continue
+		}
+		var best testing.BenchmarkResult
+		for i := 0; i < max(*count, 1); i++ {
+			r := testing.Benchmark(c.fn)
+			if best.N == 0 || r.NsPerOp() < best.NsPerOp() {
+				best = r
+			}
+		}
+		results[c.name] = newBenchResult(best)
+		fmt.Printf("%-24s %s\t%s\n", c.name, best.String(), best.MemString())
+	}
+
+	if *update {
+		if err := saveBaseline(*baselinePath, results); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Baseline written to %s\n", *baselinePath)
+		return
+	}
+
+	baseline, err := loadBaseline(*baselinePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("No baseline at %s; run with -update to record one\n", *baselinePath)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	regressions := compare(baseline, results, *threshold)
+	for _, r := range regressions {
+		fmt.Println("❌ " + r)
+	}
+	if len(regressions) > 0 {
+		os.Exit(1)
+	}
+	fmt.Printf("✅ No benchmark regressed by more than %.0f%%\n", *threshold)
+}
+
+// setupService wires the service against the configured Redis and PostgreSQL
+// and seeds a temporary leaderboard, returning a cleanup that deletes it
+func setupService(configPath string) (*service.LeaderboardService, string, func(), error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("loading config: %w", err)
+	}
+	cfg.Redis.KeyPrefix = benchKeyPrefix
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	redisService, err := redis.NewLeaderboardService(&cfg.Redis, logger)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	repo, err := postgres.NewRepository(&cfg.Postgres, logger)
+	if err != nil {
+		redisService.Close()
+		return nil, "", nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+	if err := repo.RunMigrations(ctx); err != nil {
+		redisService.Close()
+		repo.Close()
+		return nil, "", nil, fmt.Errorf("running migrations: %w", err)
+	}
+
+	svc := service.NewLeaderboardService(redisService, repo, &cfg.Leaderboard, logger)
+	recorderCtx, stopRecorder := context.WithCancel(ctx)
+	recorder := service.NewEventRecorder(repo, &cfg.Events, logger)
+	svc.SetEventRecorder(recorder)
+	go recorder.Run(recorderCtx)
+
+	leaderboardID := fmt.Sprintf("bench-%d", time.Now().UnixNano())
+	cleanup := func() {
+		if err := svc.DeleteLeaderboard(ctx, leaderboardID); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to delete %s: %v\n", leaderboardID, err)
+		}
+		stopRecorder()
+		repo.Close()
+		redisService.Close()
+	}
+
+	if _, err := svc.CreateLeaderboard(ctx, domain.CreateLeaderboardRequest{ID: leaderboardID, Name: "Benchmark"}); err != nil {
+		cleanup()
+		return nil, "", nil, fmt.Errorf("creating leaderboard: %w", err)
+	}
+	seed := make([]domain.ScoreSubmission, benchPlayers)
+	for i := range seed {
+		seed[i] = domain.ScoreSubmission{LeaderboardID: leaderboardID, PlayerID: fmt.Sprintf("player-%d", i), Score: int64(i)}
+	}
+	if err := svc.SubmitScoreBatch(ctx, domain.BatchScoreSubmission{Scores: seed}); err != nil {
+		cleanup()
+		return nil, "", nil, fmt.Errorf("seeding leaderboard: %w", err)
+	}
+	return svc, leaderboardID, cleanup, nil
+}
+
+// benchSubmitScore measures one HTTP-path score submission
+func benchSubmitScore(svc *service.LeaderboardService, leaderboardID string) func(b *testing.B) {
+	return func(b *testing.B) {
+		ctx := context.Background()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			err := svc.SubmitScore(ctx, domain.ScoreSubmission{
+				LeaderboardID: leaderboardID,
+				PlayerID:      fmt.Sprintf("player-%d", i%benchPlayers),
+				Score:         int64(benchPlayers + i),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// benchGetTopN measures reading the top 100 with ghost names and provisional flags filled in
+func benchGetTopN(svc *service.LeaderboardService, leaderboardID string) func(b *testing.B) {
+	return func(b *testing.B) {
+		ctx := context.Background()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := svc.GetTopN(ctx, leaderboardID, 100); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
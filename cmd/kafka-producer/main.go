@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -9,23 +9,13 @@ import (
 	"os"
 	"os/signal"
 	"strings"
-	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/pkg/producer"
 )
 
-// ScoreSubmission represents a score submission message
-type ScoreSubmission struct {
-	PlayerID      string                 `json:"player_id"`
-	LeaderboardID string                 `json:"leaderboard_id"`
-	Score         int64                  `json:"score"`
-	GameID        string                 `json:"game_id,omitempty"`
-	Metadata      map[string]interface{} `json:"metadata,omitempty"`
-}
-
 var playerPrefixes = []string{
 	"Phoenix", "Shadow", "Thunder", "Storm", "Blaze", "Ninja", "Dragon", "Wolf", "Hawk", "Viper",
 	"Ghost", "Titan", "Frost", "Cyber", "Nova", "Raven", "Omega", "Alpha", "Delta", "Sigma",
@@ -64,68 +54,43 @@ func main() {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
-	// Configure Sarama producer
-	config := sarama.NewConfig()
-	config.Producer.RequiredAcks = sarama.WaitForLocal
-	config.Producer.Compression = sarama.CompressionSnappy
-	config.Producer.Flush.Frequency = 100 * time.Millisecond
-	config.Producer.Flush.Messages = 100
-	config.Producer.Return.Successes = true
-	config.Producer.Return.Errors = true
-
-	// Create producer
-	producer, err := sarama.NewAsyncProducer(brokerList, config)
-	if err != nil {
-		log.Fatalf("Failed to create producer: %v", err)
-	}
-	defer producer.Close()
-
-	// Handle producer errors and successes
+	// Count deliveries as the producer reports them
 	var successCount, errorCount int64
-	var wg sync.WaitGroup
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for range producer.Successes() {
-			atomic.AddInt64(&successCount, 1)
-		}
-	}()
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for err := range producer.Errors() {
+	p, err := producer.New(producer.Config{
+		Brokers: brokerList,
+		Topic:   *topic,
+		OnDelivery: func(d producer.Delivery) {
+			if d.Err == nil {
+				atomic.AddInt64(&successCount, 1)
+				return
+			}
 			atomic.AddInt64(&errorCount, 1)
-			log.Printf("Producer error: %v", err)
-		}
-	}()
+			log.Printf("Producer error: %v", d.Err)
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create producer: %v", err)
+	}
 
 	// Handle shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	// Send message helper
-	sendMessage := func(submission ScoreSubmission) {
-		data, err := json.Marshal(submission)
-		if err != nil {
-			log.Printf("Failed to marshal message: %v", err)
-			return
-		}
-
-		msg := &sarama.ProducerMessage{
-			Topic: *topic,
-			Key:   sarama.StringEncoder(submission.PlayerID),
-			Value: sarama.ByteEncoder(data),
+	sendMessage := func(submission producer.ScoreSubmission) {
+		if err := p.Submit(ctx, submission); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to submit score: %v", err)
 		}
+	}
 
-		select {
-		case producer.Input() <- msg:
-		case <-done:
-			return
-		}
+	shutdown := func() {
+		cancel()
+		p.Close()
+		fmt.Printf("\n✓ Completed. Sent: %d, Errors: %d\n", atomic.LoadInt64(&successCount), atomic.LoadInt64(&errorCount))
 	}
 
 	// Create initial players in batches
@@ -137,7 +102,7 @@ func main() {
 		}
 
 		for j := i; j < end; j++ {
-			submission := ScoreSubmission{
+			submission := producer.ScoreSubmission{
 				PlayerID:      getPlayerName(j),
 				LeaderboardID: *leaderboardID,
 				Score:         int64(rand.Intn(5000) + 1000),
@@ -152,10 +117,7 @@ func main() {
 
 	if *initialOnly {
 		fmt.Println("Initial-only mode: Exiting after creating players")
-		close(done)
-		producer.AsyncClose()
-		wg.Wait()
-		fmt.Printf("\n✓ Completed. Sent: %d, Errors: %d\n", atomic.LoadInt64(&successCount), atomic.LoadInt64(&errorCount))
+		shutdown()
 		return
 	}
 
@@ -186,19 +148,13 @@ func main() {
 		select {
 		case <-sigChan:
 			fmt.Println("\n\nShutting down...")
-			close(done)
-			producer.AsyncClose()
-			wg.Wait()
-			fmt.Printf("\n✓ Completed. Sent: %d, Errors: %d\n", atomic.LoadInt64(&successCount), atomic.LoadInt64(&errorCount))
+			shutdown()
 			return
 
 		case <-ticker.C:
 			if *duration > 0 && time.Now().After(endTime) {
 				fmt.Println("\n\nDuration reached, shutting down...")
-				close(done)
-				producer.AsyncClose()
-				wg.Wait()
-				fmt.Printf("\n✓ Completed. Sent: %d, Errors: %d\n", atomic.LoadInt64(&successCount), atomic.LoadInt64(&errorCount))
+				shutdown()
 				return
 			}
 
@@ -220,7 +176,7 @@ func main() {
 				score = int64(rand.Intn(400) + 200)
 			}
 
-			submission := ScoreSubmission{
+			submission := producer.ScoreSubmission{
 				PlayerID:      getPlayerName(playerIdx),
 				LeaderboardID: *leaderboardID,
 				Score:         score,
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/redis"
+)
+
+// keymigrate moves existing Redis keys into the namespace configured by
+// redis.key_prefix and redis.tenant. Run it once, with the server stopped,
+// after changing either setting.
+func main() {
+	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	fromPrefix := flag.String("from-prefix", "", "Key prefix the keys are currently stored under")
+	fromTenant := flag.String("from-tenant", "", "Tenant segment the keys are currently stored under")
+	dryRun := flag.Bool("dry-run", false, "Report what would be renamed without changing anything")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	svc, err := redis.NewLeaderboardService(&cfg.Redis, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer svc.Close()
+
+	from := config.KeyNamespace(*fromPrefix, *fromTenant)
+	fmt.Printf("Moving keys from namespace %q to %q\n", from, cfg.Redis.KeyNamespace())
+
+	renamed, skipped, err := svc.MigrateNamespace(context.Background(), from, *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Migration stopped after %d keys: %v\n", renamed, err)
+		os.Exit(1)
+	}
+
+	verb := "Renamed"
+	if *dryRun {
+		verb = "Would rename"
+	}
+	fmt.Printf("✅ %s %d keys, %d already present in the target namespace\n", verb, renamed, skipped)
+}
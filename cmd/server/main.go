@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -11,12 +12,28 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/leaderboard-redis/internal/auth"
+	"github.com/leaderboard-redis/internal/blobstore"
+	"github.com/leaderboard-redis/internal/chaos"
+	"github.com/leaderboard-redis/internal/clock"
 	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/features"
 	"github.com/leaderboard-redis/internal/handler"
+	"github.com/leaderboard-redis/internal/i18n"
+	"github.com/leaderboard-redis/internal/ids"
+	"github.com/leaderboard-redis/internal/jsonenc"
 	"github.com/leaderboard-redis/internal/kafka"
+	"github.com/leaderboard-redis/internal/logging"
+	"github.com/leaderboard-redis/internal/migration"
 	"github.com/leaderboard-redis/internal/postgres"
+	"github.com/leaderboard-redis/internal/postgres/eventbuffer"
+	"github.com/leaderboard-redis/internal/purge"
+	"github.com/leaderboard-redis/internal/ratelimit"
 	"github.com/leaderboard-redis/internal/redis"
+	"github.com/leaderboard-redis/internal/replication"
 	"github.com/leaderboard-redis/internal/service"
+	"github.com/leaderboard-redis/internal/telemetry"
 	"github.com/leaderboard-redis/internal/websocket"
 	"github.com/leaderboard-redis/internal/worker"
 )
@@ -24,38 +41,88 @@ import (
 func main() {
 	// Parse command line flags
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	simulate := flag.Bool("simulate", false, "Run on a simulated clock advanced through /api/v1/admin/clock")
 	flag.Parse()
 
+	// Load configuration
+	cfg, configErr := config.Load(*configPath)
+	if configErr != nil {
+		cfg = config.DefaultConfig()
+	}
+
 	// Setup structured logging
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	logManager, err := logging.New(&cfg.Logging)
+	if err != nil {
+		slog.Error("failed to configure logging", "error", err)
+		os.Exit(1)
+	}
+	defer logManager.Close()
+	logger := logManager.Logger()
 	slog.SetDefault(logger)
 
-	// Load configuration
-	cfg, err := config.Load(*configPath)
+	if configErr != nil {
+		logger.Warn("failed to load config file, using defaults", "error", configErr)
+	}
+
+	// Initialize error reporting
+	reporter, err := telemetry.New(&cfg.Errors, logManager.For("telemetry"))
 	if err != nil {
-		logger.Warn("failed to load config file, using defaults", "error", err)
-		cfg = config.DefaultConfig()
+		logger.Error("failed to configure error reporting", "error", err)
+		os.Exit(1)
 	}
+	defer reporter.Flush(5 * time.Second)
 
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Wall clock, or a simulated one that only moves when advanced
+	appClock := clock.Real()
+	var simClock *clock.Simulated
+	if *simulate {
+		simClock = clock.NewSimulated(time.Now())
+		appClock = simClock
+		logger.Warn("running on a simulated clock")
+	}
+
+	// Fault injection for resilience game-days
+	var faults *chaos.Injector
+	if cfg.Chaos.Enabled {
+		faults = chaos.NewInjector()
+		logger.Warn("chaos fault injection enabled")
+	}
+
 	// Initialize Redis
 	logger.Info("connecting to Redis", "addr", cfg.Redis.Addr)
-	redisService, err := redis.NewLeaderboardService(&cfg.Redis, logger)
+	redisService, err := redis.NewLeaderboardService(&cfg.Redis, logManager.For("redis"))
 	if err != nil {
 		logger.Error("failed to connect to Redis", "error", err)
 		os.Exit(1)
 	}
 	defer redisService.Close()
+	if faults != nil {
+		redisService.Client().AddHook(chaos.NewRedisHook(faults))
+	}
 	logger.Info("connected to Redis")
 
+	// Feature flags, with runtime overrides shared through Redis
+	featureFlags, err := features.NewProvider(redisService, &cfg.Features, cfg.Redis.Tenant, logManager.For("features"))
+	if err != nil {
+		logger.Error("invalid feature flag config", "error", err)
+		os.Exit(1)
+	}
+	if err := featureFlags.Start(ctx); err != nil {
+		logger.Error("failed to start feature flags", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize PostgreSQL
 	logger.Info("connecting to PostgreSQL", "host", cfg.Postgres.Host, "database", cfg.Postgres.Database)
-	postgresRepo, err := postgres.NewRepository(&cfg.Postgres, logger)
+	var postgresOpts []postgres.Option
+	if faults != nil {
+		postgresOpts = append(postgresOpts, postgres.WithTracer(chaos.NewPostgresTracer(faults)))
+	}
+	postgresRepo, err := postgres.NewRepository(&cfg.Postgres, logManager.For("postgres"), postgresOpts...)
 	if err != nil {
 		logger.Error("failed to connect to PostgreSQL", "error", err)
 		os.Exit(1)
@@ -70,7 +137,16 @@ func main() {
 	}
 
 	// Initialize WebSocket hub
-	wsHub := websocket.NewHub(logger)
+	wsHub := websocket.NewHub(logManager.For("websocket"))
+	wsHub.SetErrorReporter(reporter)
+	wsHub.SetFaultInjector(faults)
+	wsHub.SetFeatures(featureFlags)
+	if cfg.WebSocket.Limits.Enabled() {
+		wsHub.SetConnectionLimiter(websocket.NewConnectionLimiter(&cfg.WebSocket.Limits))
+	}
+	if cfg.WebSocket.Coalesce.Enabled() {
+		wsHub.SetCoalescing(&cfg.WebSocket.Coalesce)
+	}
 	go wsHub.Run()
 	logger.Info("WebSocket hub initialized")
 
@@ -79,19 +155,101 @@ func main() {
 		redisService,
 		postgresRepo,
 		&cfg.Leaderboard,
-		logger,
+		logManager.For("service"),
 	)
 
+	// Reset period boundaries shared by the scheduler and next_reset_at
+	resetSchedule, err := domain.NewResetSchedule(cfg.Reset.Timezone, cfg.Reset.WeekStart, cfg.Reset.TimeOfDay)
+	if err != nil {
+		logger.Error("invalid reset schedule", "error", err)
+		os.Exit(1)
+	}
+	leaderboardService.SetResetSchedule(resetSchedule)
+
+	// Leaderboard ID validation and generation, shared by HTTP and Kafka
+	idPolicy, err := ids.New(&cfg.Leaderboard.IDs)
+	if err != nil {
+		logger.Error("invalid leaderboard id policy", "error", err)
+		os.Exit(1)
+	}
+	leaderboardService.SetIDPolicy(idPolicy)
+
+	// Encryption of sensitive metadata fields before they reach PostgreSQL
+	if cfg.Leaderboard.Encryption.Enabled() {
+		metadataCipher, err := service.NewMetadataCipher(&cfg.Leaderboard.Encryption)
+		if err != nil {
+			logger.Error("invalid metadata encryption config", "error", err)
+			os.Exit(1)
+		}
+		leaderboardService.SetMetadataCipher(metadataCipher)
+	}
+
 	// Set the WebSocket hub on the service for broadcasting
 	leaderboardService.SetHub(wsHub)
+	leaderboardService.SetGlobalEvents(cfg.WebSocket.Global.Events)
+	leaderboardService.SetClock(appClock)
+	wsHub.SetQueryHandler(leaderboardService)
+
+	// Cross-region replication: a primary publishes applied changes, a replica
+	// only applies them and rejects local writes
+	var replicationPublisher *replication.Publisher
+	var replicationConsumer *replication.Consumer
+	replica := cfg.Replication.Mode == config.ReplicationReplica
+	switch cfg.Replication.Mode {
+	case config.ReplicationPrimary:
+		replicationPublisher, err = replication.NewPublisher(&cfg.Replication, logManager.For("replication"))
+		if err != nil {
+			logger.Error("failed to create replication publisher", "error", err)
+			os.Exit(1)
+		}
+		if err := replicationPublisher.Start(ctx); err != nil {
+			logger.Error("failed to start replication publisher", "error", err)
+			os.Exit(1)
+		}
+		leaderboardService.SetReplicator(replicationPublisher)
+	case config.ReplicationReplica:
+		leaderboardService.SetReadOnly(true)
+		if cfg.Replication.Transport == config.ReplicationTransportKafka {
+			replicationConsumer, err = replication.NewConsumer(&cfg.Replication, leaderboardService, logManager.For("replication"))
+			if err != nil {
+				logger.Error("failed to create replication consumer", "error", err)
+				os.Exit(1)
+			}
+			if err := replicationConsumer.Start(); err != nil {
+				logger.Error("failed to start replication consumer", "error", err)
+				os.Exit(1)
+			}
+		}
+		logger.Info("running as read-only replica", "region", cfg.Replication.Region)
+	}
+
+	// Initialize score event recorder with sampling policy
+	eventRecorder := service.NewEventRecorder(postgresRepo, &cfg.Events, logManager.For("service"))
+	eventRecorder.SetClock(appClock)
+	leaderboardService.SetEventRecorder(eventRecorder)
+
+	// Write-behind buffering keeps score event inserts off the submission path
+	var eventBuffer *eventbuffer.Buffer
+	if cfg.Events.Buffer.Enabled {
+		eventBuffer = eventbuffer.New(postgresRepo, &cfg.Events.Buffer, logManager.For("postgres"))
+		eventBuffer.SetClock(appClock)
+		if err := eventBuffer.Start(ctx); err != nil {
+			logger.Error("failed to start score event buffer", "error", err)
+			os.Exit(1)
+		}
+		eventRecorder.SetWriter(eventBuffer)
+	}
+	go eventRecorder.Run(ctx)
 
 	// Initialize sync worker
 	syncWorker := worker.NewSyncWorker(
 		redisService,
 		postgresRepo,
 		&cfg.Sync,
-		logger,
+		logManager.For("worker"),
 	)
+	syncWorker.SetClock(appClock)
+	syncWorker.SetTrimmer(leaderboardService)
 
 	// Sync from database to Redis on startup (recovery)
 	logger.Info("syncing leaderboards from database to Redis")
@@ -107,18 +265,171 @@ func main() {
 		}
 	}
 
+	// Announce submission windows opening and closing on the global channel
+	windowWorker := worker.NewWindowWorker(leaderboardService, postgresRepo, &cfg.WebSocket.Global, logManager.For("worker"))
+	windowWorker.SetClock(appClock)
+	globalCfg := cfg.WebSocket.Global
+	if (globalCfg.Forwards(string(domain.FeedWindowOpen)) || globalCfg.Forwards(string(domain.FeedWindowClose))) && !replica {
+		if err := windowWorker.Start(ctx); err != nil {
+			logger.Error("failed to start window worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize retention worker for orphaned score events
+	retentionWorker := worker.NewRetentionWorker(postgresRepo, &cfg.Retention, logManager.For("worker"))
+	retentionWorker.SetClock(appClock)
+	if cfg.Retention.Enabled {
+		if err := retentionWorker.Start(ctx); err != nil {
+			logger.Error("failed to start retention worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize expiry worker for boards that drop inactive players
+	expiryWorker := worker.NewExpiryWorker(leaderboardService, postgresRepo, &cfg.Expiry, logManager.For("worker"))
+	expiryWorker.SetClock(appClock)
+	if cfg.Expiry.Enabled && !replica {
+		if err := expiryWorker.Start(ctx); err != nil {
+			logger.Error("failed to start expiry worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Staleness alerts for watched boards that stopped receiving scores
+	var stalenessWorker *worker.StalenessWorker
+	if cfg.Staleness.Enabled && !replica {
+		stalenessWorker = worker.NewStalenessWorker(redisService, wsHub, postgresRepo, &cfg.Staleness, logManager.For("worker"))
+		stalenessWorker.SetClock(appClock)
+		if err := stalenessWorker.Start(ctx); err != nil {
+			logger.Error("failed to start staleness worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// CDN purging: boards whose top entries change or reset are purged in batches
+	var edgePurger *purge.Purger
+	if cfg.Purge.Enabled {
+		edgePurger, err = purge.NewPurger(&cfg.Purge, logManager.For("purge"))
+		if err != nil {
+			logger.Error("failed to create cdn purger", "error", err)
+			os.Exit(1)
+		}
+		if err := edgePurger.Start(ctx); err != nil {
+			logger.Error("failed to start cdn purger", "error", err)
+			os.Exit(1)
+		}
+		leaderboardService.SetEdgePurger(edgePurger)
+	}
+
+	// Migration from the legacy leaderboard service: mirror submissions to it
+	// and compare its top entries with ours
+	var legacyForwarder *migration.Forwarder
+	if cfg.Migration.DualWrite.Enabled && !replica {
+		legacyForwarder, err = migration.NewForwarder(&cfg.Migration.DualWrite, logManager.For("migration"))
+		if err != nil {
+			logger.Error("failed to create legacy forwarder", "error", err)
+			os.Exit(1)
+		}
+		if err := legacyForwarder.Start(ctx); err != nil {
+			logger.Error("failed to start legacy forwarder", "error", err)
+			os.Exit(1)
+		}
+		leaderboardService.SetLegacyForwarder(legacyForwarder)
+	}
+	var compareWorker *worker.CompareWorker
+	if cfg.Migration.Compare.Enabled {
+		legacyClient, err := migration.NewLegacyClient(&cfg.Migration.Compare)
+		if err != nil {
+			logger.Error("failed to create legacy comparison client", "error", err)
+			os.Exit(1)
+		}
+		compareWorker = worker.NewCompareWorker(leaderboardService, legacyClient, postgresRepo, &cfg.Migration.Compare, logManager.For("worker"))
+		compareWorker.SetClock(appClock)
+		if err := compareWorker.Start(ctx); err != nil {
+			logger.Error("failed to start compare worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize publish worker for static standings served from a CDN
+	var publishStore blobstore.Store
+	if cfg.Publish.Enabled {
+		publishStore, err = blobstore.New(&cfg.Publish.Store)
+		if err != nil {
+			logger.Error("failed to create publish store", "error", err)
+			os.Exit(1)
+		}
+	}
+	publishWorker := worker.NewPublishWorker(leaderboardService, publishStore, postgresRepo, &cfg.Publish, logManager.For("worker"))
+	publishWorker.SetClock(appClock)
+	if edgePurger != nil {
+		publishWorker.SetPurger(edgePurger)
+	}
+	if cfg.Publish.Enabled {
+		if err := publishWorker.Start(ctx); err != nil {
+			logger.Error("failed to start publish worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize cache sweep worker for player info keys written without a TTL
+	cacheSweepWorker := worker.NewCacheSweepWorker(redisService, &cfg.Redis.Cache, logManager.For("worker"))
+	cacheSweepWorker.SetClock(appClock)
+	if cfg.Redis.Cache.SweepEnabled() && !replica {
+		if err := cacheSweepWorker.Start(ctx); err != nil {
+			logger.Error("failed to start cache sweep worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize reset scheduler for daily, weekly, and monthly leaderboards
+	resetWorker := worker.NewResetWorker(leaderboardService, postgresRepo, &cfg.Reset, logManager.For("worker"))
+	resetWorker.SetClock(appClock)
+	resetWorker.SetSchedule(resetSchedule)
+	if cfg.Reset.Enabled && !replica {
+		if err := resetWorker.Start(ctx); err != nil {
+			logger.Error("failed to start reset worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize CSV score file ingestion
+	ingestWorker := worker.NewIngestWorker(leaderboardService, &cfg.Ingest, logManager.For("worker"))
+	ingestWorker.SetClock(appClock)
+	if cfg.Ingest.Enabled && !replica {
+		if err := ingestWorker.Start(ctx); err != nil {
+			logger.Error("failed to start ingest worker", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	// Initialize Kafka consumer for high-load score ingestion
 	var kafkaConsumer *kafka.Consumer
-	if cfg.Kafka.Enabled {
+	var deadLetterProducer *kafka.DeadLetterProducer
+	if cfg.Kafka.Enabled && !replica {
 		logger.Info("initializing Kafka consumer",
 			"brokers", cfg.Kafka.Brokers,
 			"topic", cfg.Kafka.Topic,
 		)
 		var err error
-		kafkaConsumer, err = kafka.NewConsumer(&cfg.Kafka, leaderboardService, logger)
+		kafkaConsumer, err = kafka.NewConsumer(&cfg.Kafka, leaderboardService, logManager.For("kafka"))
+		if errors.Is(err, kafka.ErrTopicNotFound) {
+			logger.Error("kafka topic missing", "error", err)
+			os.Exit(1)
+		}
 		if err != nil {
 			logger.Warn("failed to create Kafka consumer, continuing without Kafka", "error", err)
 		} else {
+			kafkaConsumer.SetErrorReporter(reporter)
+			if cfg.Kafka.DeadLetter.Enabled {
+				deadLetterProducer, err = kafka.NewDeadLetterProducer(cfg.Kafka.Brokers, cfg.Kafka.DeadLetter.Topic)
+				if err != nil {
+					logger.Warn("failed to create dead-letter producer, failed messages will be skipped", "error", err)
+				} else {
+					kafkaConsumer.SetDeadLetterPublisher(deadLetterProducer)
+				}
+			}
 			if err := kafkaConsumer.Start(); err != nil {
 				logger.Warn("failed to start Kafka consumer, continuing without Kafka", "error", err)
 				kafkaConsumer = nil
@@ -128,8 +439,78 @@ func main() {
 		}
 	}
 
+	// Publish computed prize payouts for reward systems
+	var payoutProducer *kafka.PayoutProducer
+	if cfg.Leaderboard.Prizes.Topic != "" {
+		payoutProducer, err = kafka.NewPayoutProducer(cfg.Kafka.Brokers, cfg.Leaderboard.Prizes.Topic)
+		if err != nil {
+			logger.Warn("failed to create payout producer, payouts will not be published", "error", err)
+		} else {
+			leaderboardService.SetPayoutPublisher(payoutProducer)
+		}
+	}
+
 	// Initialize HTTP handler with WebSocket hub
-	httpHandler := handler.NewHandler(leaderboardService, wsHub, logger)
+	httpHandler := handler.NewHandler(leaderboardService, wsHub, logManager.For("http"))
+	httpHandler.SetSyncWorker(syncWorker)
+	httpHandler.SetLogManager(logManager)
+	httpHandler.SetAccessLog(&cfg.Server.AccessLog)
+	httpHandler.SetRequestBodyLimits(&cfg.Server.RequestBody)
+	httpHandler.SetErrorReporter(reporter)
+	httpHandler.SetFaultInjector(faults)
+	catalogue, err := i18n.Load()
+	if err != nil {
+		logger.Error("failed to load error message catalogue", "error", err)
+		os.Exit(1)
+	}
+	httpHandler.SetMessageCatalogue(catalogue)
+	jsonEncoder, err := jsonenc.Get(cfg.Server.JSONEncoder)
+	if err != nil {
+		logger.Error("failed to configure json encoder", "error", err)
+		os.Exit(1)
+	}
+	httpHandler.SetJSONEncoder(jsonEncoder)
+	if cfg.RateLimit.Enabled {
+		limiter, err := ratelimit.NewLimiter(redisService.Client(), cfg.Redis.KeyNamespace(), &cfg.RateLimit)
+		if err != nil {
+			logger.Error("failed to configure rate limits", "error", err)
+			os.Exit(1)
+		}
+		httpHandler.SetRateLimiter(limiter)
+		logger.Info("score submission rate limits enabled",
+			"window", cfg.RateLimit.Window,
+			"per_player", cfg.RateLimit.PerPlayer,
+			"per_api_key", cfg.RateLimit.PerAPIKey,
+		)
+	}
+	if cfg.Auth.Enabled {
+		verifier, err := auth.NewVerifier(&cfg.Auth)
+		if err != nil {
+			logger.Error("failed to configure auth", "error", err)
+			os.Exit(1)
+		}
+		httpHandler.SetAuthVerifier(verifier, &cfg.Auth)
+		logger.Info("bearer token auth enabled for score submissions", "issuer", cfg.Auth.Issuer)
+	}
+	if replicationPublisher != nil {
+		httpHandler.SetReplicationPublisher(replicationPublisher)
+	}
+	if replica {
+		httpHandler.SetReplicaApply(&cfg.Replication)
+	}
+	if simClock != nil {
+		httpHandler.SetSimulatedClock(simClock)
+	}
+	if legacyForwarder != nil {
+		httpHandler.SetLegacyForwarder(legacyForwarder)
+	}
+	if compareWorker != nil {
+		httpHandler.SetCompareWorker(compareWorker)
+	}
+	if stalenessWorker != nil {
+		httpHandler.SetStalenessWorker(stalenessWorker)
+	}
+	httpHandler.SetFeatures(featureFlags)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -170,16 +551,112 @@ func main() {
 			logger.Error("failed to stop Kafka consumer", "error", err)
 		}
 	}
+	if deadLetterProducer != nil {
+		if err := deadLetterProducer.Close(); err != nil {
+			logger.Error("failed to close dead-letter producer", "error", err)
+		}
+	}
+
+	if payoutProducer != nil {
+		if err := payoutProducer.Close(); err != nil {
+			logger.Error("failed to close payout producer", "error", err)
+		}
+	}
+
+	// Stop replication, flushing changes still queued for the replica
+	if replicationConsumer != nil {
+		if err := replicationConsumer.Stop(); err != nil {
+			logger.Error("failed to stop replication consumer", "error", err)
+		}
+	}
+	if replicationPublisher != nil {
+		if err := replicationPublisher.Stop(); err != nil {
+			logger.Error("failed to stop replication publisher", "error", err)
+		}
+	}
 
 	// Stop sync worker
 	if err := syncWorker.Stop(); err != nil {
 		logger.Error("failed to stop sync worker", "error", err)
 	}
 
+	// Stop ingest worker
+	if err := ingestWorker.Stop(); err != nil {
+		logger.Error("failed to stop ingest worker", "error", err)
+	}
+
+	// Stop reset worker
+	if err := resetWorker.Stop(); err != nil {
+		logger.Error("failed to stop reset worker", "error", err)
+	}
+
+	// Stop retention worker
+	if err := retentionWorker.Stop(); err != nil {
+		logger.Error("failed to stop retention worker", "error", err)
+	}
+
+	// Stop expiry worker
+	if err := expiryWorker.Stop(); err != nil {
+		logger.Error("failed to stop expiry worker", "error", err)
+	}
+
+	// Stop staleness worker
+	if stalenessWorker != nil {
+		if err := stalenessWorker.Stop(); err != nil {
+			logger.Error("failed to stop staleness worker", "error", err)
+		}
+	}
+
+	// Stop reloading feature flag overrides
+	if err := featureFlags.Stop(); err != nil {
+		logger.Error("failed to stop feature flags", "error", err)
+	}
+
+	// Stop publish worker
+	if err := publishWorker.Stop(); err != nil {
+		logger.Error("failed to stop publish worker", "error", err)
+	}
+
+	// Stop cache sweep worker
+	if err := cacheSweepWorker.Stop(); err != nil {
+		logger.Error("failed to stop cache sweep worker", "error", err)
+	}
+
+	// Stop window worker
+	if err := windowWorker.Stop(); err != nil {
+		logger.Error("failed to stop window worker", "error", err)
+	}
+
+	// Stop migration tools, forwarding submissions still queued
+	if compareWorker != nil {
+		if err := compareWorker.Stop(); err != nil {
+			logger.Error("failed to stop compare worker", "error", err)
+		}
+	}
+	if legacyForwarder != nil {
+		if err := legacyForwarder.Stop(); err != nil {
+			logger.Error("failed to stop legacy forwarder", "error", err)
+		}
+	}
+
+	// Stop cdn purger, sending purges still queued
+	if edgePurger != nil {
+		if err := edgePurger.Stop(); err != nil {
+			logger.Error("failed to stop cdn purger", "error", err)
+		}
+	}
+
 	// Shutdown HTTP server
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("failed to shutdown server", "error", err)
 	}
 
+	// Stop the event buffer last, writing events still queued
+	if eventBuffer != nil {
+		if err := eventBuffer.Stop(); err != nil {
+			logger.Error("failed to stop score event buffer", "error", err)
+		}
+	}
+
 	logger.Info("server stopped")
 }
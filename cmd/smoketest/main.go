@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/gorilla/websocket"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// apiResponse mirrors the server's response envelope
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// wsMessage is the subset of a WebSocket message the smoke test inspects
+type wsMessage struct {
+	Type          string `json:"type"`
+	LeaderboardID string `json:"leaderboard_id"`
+}
+
+// smokeStep is a named check run in order
+type smokeStep struct {
+	name string
+	fn   func() error
+}
+
+// smokeTest runs the golden-path checks against one deployment
+type smokeTest struct {
+	api           string
+	brokers       []string
+	topic         string
+	timeout       time.Duration
+	client        *http.Client
+	leaderboardID string
+}
+
+func main() {
+	api := flag.String("api", "http://localhost:8080", "Base URL of the deployed server")
+	brokers := flag.String("brokers", "localhost:9094", "Kafka brokers (comma-separated, empty to skip Kafka)")
+	topic := flag.String("topic", "leaderboard-scores", "Kafka topic consumed by the server")
+	timeout := flag.Duration("timeout", 30*time.Second, "How long to wait for each asynchronous step")
+	skipSync := flag.Bool("skip-sync", false, "Skip the PostgreSQL sync check")
+	flag.Parse()
+
+	st := &smokeTest{
+		api:           strings.TrimRight(*api, "/"),
+		topic:         *topic,
+		timeout:       *timeout,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		leaderboardID: fmt.Sprintf("smoke-%d", time.Now().UnixNano()),
+	}
+	if *brokers != "" {
+		st.brokers = strings.Split(*brokers, ",")
+	}
+
+	fmt.Printf("Smoke testing %s with leaderboard %s\n", st.api, st.leaderboardID)
+
+	ok := st.run(*skipSync)
+	if !ok {
+		fmt.Println("❌ Smoke test failed")
+		os.Exit(1)
+	}
+	fmt.Println("✅ Smoke test passed")
+}
+
+// run executes every step, always cleaning up the temporary leaderboard
+func (st *smokeTest) run(skipSync bool) bool {
+	if !st.step("create leaderboard", st.createLeaderboard) {
+		return false
+	}
+	defer st.step("delete leaderboard", st.deleteLeaderboard)
+
+	updates, closeWS, err := st.subscribe()
+	if !st.report("subscribe over WebSocket", err) {
+		return false
+	}
+	defer closeWS()
+
+	steps := []smokeStep{
+		{"submit scores over HTTP", st.submitHTTP},
+		{"verify top-N", func() error { return st.verifyTop([]string{"smoke-b", "smoke-a"}) }},
+		{"receive WebSocket update", func() error { return st.awaitUpdate(updates) }},
+	}
+	if len(st.brokers) > 0 {
+		steps = append(steps, smokeStep{"submit score over Kafka", st.submitKafka})
+	}
+	if !skipSync {
+		steps = append(steps, smokeStep{"sync to PostgreSQL", st.verifySync})
+	}
+	steps = append(steps,
+		smokeStep{"extreme scores", st.verifyExtremeScores},
+		smokeStep{"concurrent best scores", st.verifyConcurrentBest},
+	)
+
+	for _, s := range steps {
+		if !st.step(s.name, s.fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// step runs fn and prints its outcome
+func (st *smokeTest) step(name string, fn func() error) bool {
+	return st.report(name, fn())
+}
+
+// report prints a step outcome and returns whether it passed
+func (st *smokeTest) report(name string, err error) bool {
+	if err != nil {
+		fmt.Printf("  ❌ %s: %v\n", name, err)
+		return false
+	}
+	fmt.Printf("  ✅ %s\n", name)
+	return true
+}
+
+func (st *smokeTest) createLeaderboard() error {
+	return st.call(http.MethodPost, "/api/v1/leaderboards", domain.CreateLeaderboardRequest{
+		ID:        st.leaderboardID,
+		Name:      "Smoke test " + st.leaderboardID,
+		SortOrder: domain.SortOrderDesc,
+	}, nil)
+}
+
+func (st *smokeTest) deleteLeaderboard() error {
+	return st.call(http.MethodDelete, "/api/v1/leaderboards/"+st.leaderboardID, nil, nil)
+}
+
+func (st *smokeTest) submitHTTP() error {
+	for playerID, score := range map[string]int64{"smoke-a": 100, "smoke-b": 200} {
+		err := st.call(http.MethodPost, "/api/v1/scores", domain.ScoreSubmission{
+			PlayerID:      playerID,
+			LeaderboardID: st.leaderboardID,
+			Score:         score,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyTop checks that the leaderboard's top entries are the expected players in order
+func (st *smokeTest) verifyTop(want []string) error {
+	var entries []domain.LeaderboardEntry
+	path := fmt.Sprintf("/api/v1/leaderboards/%s/top?limit=%d", st.leaderboardID, len(want))
+	if err := st.call(http.MethodGet, path, nil, &entries); err != nil {
+		return err
+	}
+	if len(entries) != len(want) {
+		return fmt.Errorf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if entry.PlayerID != want[i] || entry.Rank != int64(i+1) {
+			return fmt.Errorf("rank %d is %s (rank %d), want %s", i+1, entry.PlayerID, entry.Rank, want[i])
+		}
+	}
+	return nil
+}
+
+func (st *smokeTest) submitKafka() error {
+	cfg := sarama.NewConfig()
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(st.brokers, cfg)
+	if err != nil {
+		return fmt.Errorf("creating producer: %w", err)
+	}
+	defer producer.Close()
+
+	value, err := json.Marshal(domain.ScoreSubmission{
+		PlayerID:      "smoke-kafka",
+		LeaderboardID: st.leaderboardID,
+		Score:         300,
+	})
+	if err != nil {
+		return err
+	}
+	_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+		Topic: st.topic,
+		Key:   sarama.StringEncoder("smoke-kafka"),
+		Value: sarama.ByteEncoder(value),
+	})
+	if err != nil {
+		return fmt.Errorf("producing message: %w", err)
+	}
+
+	return st.eventually(func() error {
+		return st.verifyTop([]string{"smoke-kafka", "smoke-b", "smoke-a"})
+	})
+}
+
+// verifyExtremeScores checks that the largest safe score round-trips exactly
+// and that one past it is rejected instead of losing precision
+func (st *smokeTest) verifyExtremeScores() error {
+	submit := func(playerID string, score int64) error {
+		return st.call(http.MethodPost, "/api/v1/scores", domain.ScoreSubmission{
+			PlayerID:      playerID,
+			LeaderboardID: st.leaderboardID,
+			Score:         score,
+		}, nil)
+	}
+
+	if err := submit("smoke-max", domain.MaxSafeScore); err != nil {
+		return err
+	}
+	var entries []domain.LeaderboardEntry
+	path := fmt.Sprintf("/api/v1/leaderboards/%s/top?limit=1", st.leaderboardID)
+	if err := st.call(http.MethodGet, path, nil, &entries); err != nil {
+		return err
+	}
+	if len(entries) != 1 || entries[0].PlayerID != "smoke-max" || entries[0].Score != domain.MaxSafeScore {
+		return fmt.Errorf("top entry is %+v, want smoke-max with %d", entries, domain.MaxSafeScore)
+	}
+
+	for _, score := range []int64{domain.MaxSafeScore + 1, -domain.MaxSafeScore - 1} {
+		err := submit("smoke-overflow", score)
+		if err == nil {
+			return fmt.Errorf("score %d was accepted", score)
+		}
+		if !strings.Contains(err.Error(), "status 400") {
+			return fmt.Errorf("score %d: %w", score, err)
+		}
+	}
+	return nil
+}
+
+// verifyConcurrentBest submits many scores for one player at once and checks
+// that the best of them stands, so a slower worse score never overwrites it
+func (st *smokeTest) verifyConcurrentBest() error {
+	const submissions = 100
+	errs := make(chan error, submissions)
+	var wg sync.WaitGroup
+	for i := 1; i <= submissions; i++ {
+		wg.Add(1)
+		go func(score int64) {
+			defer wg.Done()
+			errs <- st.call(http.MethodPost, "/api/v1/scores", domain.ScoreSubmission{
+				PlayerID:      "smoke-race",
+				LeaderboardID: st.leaderboardID,
+				Score:         score,
+				UpdateMode:    domain.UpdateModeBest,
+			}, nil)
+		}(int64(i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	var entry domain.LeaderboardEntry
+	path := fmt.Sprintf("/api/v1/leaderboards/%s/player/smoke-race", st.leaderboardID)
+	if err := st.call(http.MethodGet, path, nil, &entry); err != nil {
+		return err
+	}
+	if entry.Score != submissions {
+		return fmt.Errorf("smoke-race holds %d, want the best score %d", entry.Score, submissions)
+	}
+	return nil
+}
+
+// verifySync triggers a sync of the leaderboard and checks the synced player count
+func (st *smokeTest) verifySync() error {
+	var board struct {
+		PlayerCount int `json:"player_count"`
+	}
+	path := "/api/v1/admin/sync/leaderboards/" + st.leaderboardID
+	if err := st.call(http.MethodPost, path, nil, &board); err != nil {
+		return err
+	}
+	if board.PlayerCount < 2 {
+		return fmt.Errorf("synced %d players, want at least 2", board.PlayerCount)
+	}
+	return nil
+}
+
+// subscribe opens a WebSocket, subscribes to the leaderboard, and streams its updates
+func (st *smokeTest) subscribe() (<-chan wsMessage, func(), error) {
+	wsURL, err := url.Parse(st.api + "/ws")
+	if err != nil {
+		return nil, nil, err
+	}
+	wsURL.Scheme = strings.Replace(wsURL.Scheme, "http", "ws", 1)
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dialing %s: %w", wsURL, err)
+	}
+	err = conn.WriteJSON(map[string]string{
+		"type":           "subscribe",
+		"leaderboard_id": st.leaderboardID,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("subscribing: %w", err)
+	}
+
+	updates := make(chan wsMessage, 16)
+	go func() {
+		defer close(updates)
+		for {
+			var msg wsMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.LeaderboardID == st.leaderboardID {
+				select {
+				case updates <- msg:
+				default:
+				}
+			}
+		}
+	}()
+	return updates, func() { conn.Close() }, nil
+}
+
+// awaitUpdate waits for a leaderboard or player update for the test leaderboard
+func (st *smokeTest) awaitUpdate(updates <-chan wsMessage) error {
+	deadline := time.After(st.timeout)
+	for {
+		select {
+		case msg, ok := <-updates:
+			if !ok {
+				return fmt.Errorf("connection closed before an update arrived")
+			}
+			if msg.Type == "leaderboard_update" || msg.Type == "player_update" {
+				return nil
+			}
+		case <-deadline:
+			return fmt.Errorf("no update within %s", st.timeout)
+		}
+	}
+}
+
+// eventually retries check until it passes or the timeout expires
+func (st *smokeTest) eventually(check func() error) error {
+	deadline := time.Now().Add(st.timeout)
+	for {
+		err := check()
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// call sends a JSON request and decodes the response envelope's data into out
+func (st *smokeTest) call(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, st.api+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := st.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s %s: decoding response (status %d): %w", method, path, resp.StatusCode, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest || !envelope.Success {
+		return fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, envelope.Error)
+	}
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("%s %s: decoding data: %w", method, path, err)
+		}
+	}
+	return nil
+}
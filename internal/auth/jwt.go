@@ -0,0 +1,245 @@
+// Package auth verifies JWT bearer tokens issued to players
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// ErrInvalidToken is returned for every token that fails verification; the
+// wrapped message says why
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the verified claims of a token
+type Claims struct {
+	Subject  string
+	PlayerID string
+	Scopes   []string
+	Expires  time.Time
+	Raw      map[string]any
+}
+
+// HasScope reports whether the token was granted the scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier checks token signatures and standard claims
+type Verifier struct {
+	config *config.AuthConfig
+	keys   *KeySet
+	now    func() time.Time
+}
+
+// NewVerifier creates a verifier for the configured issuer. At least one of
+// a JWKS URL or an HMAC secret is required.
+func NewVerifier(cfg *config.AuthConfig) (*Verifier, error) {
+	if cfg.JWKSURL == "" && cfg.HMACSecret == "" {
+		return nil, fmt.Errorf("auth needs a jwks_url or hmac_secret")
+	}
+	v := &Verifier{config: cfg, now: time.Now}
+	if cfg.JWKSURL != "" {
+		v.keys = NewKeySet(cfg.JWKSURL, cfg.JWKSRefresh)
+	}
+	return v, nil
+}
+
+// Verify checks the token and returns its claims
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if err := v.verifySignature(ctx, header.Alg, header.Kid, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var raw map[string]any
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	claims, err := v.checkClaims(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// verifySignature checks the signature with the key the algorithm calls for.
+// HS* tokens are only accepted with a configured secret, so a public JWKS key
+// can never be used as an HMAC secret.
+func (v *Verifier) verifySignature(ctx context.Context, alg, kid, signed string, sig []byte) error {
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		if v.config.HMACSecret == "" {
+			return fmt.Errorf("algorithm %s not accepted", alg)
+		}
+		mac := hmac.New(hashFunc(alg), []byte(v.config.HMACSecret))
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	case "RS256", "RS384", "RS512", "ES256", "ES384", "ES512":
+		if v.keys == nil {
+			return fmt.Errorf("algorithm %s not accepted", alg)
+		}
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			return err
+		}
+		h := hashFunc(alg)()
+		h.Write([]byte(signed))
+		digest := h.Sum(nil)
+
+		switch pub := key.(type) {
+		case *rsa.PublicKey:
+			if alg[:2] != "RS" {
+				return fmt.Errorf("key %q is not an %s key", kid, alg)
+			}
+			if err := rsa.VerifyPKCS1v15(pub, cryptoHash(alg), digest, sig); err != nil {
+				return fmt.Errorf("signature mismatch")
+			}
+			return nil
+		case *ecdsa.PublicKey:
+			if alg[:2] != "ES" {
+				return fmt.Errorf("key %q is not an %s key", kid, alg)
+			}
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			if len(sig) != 2*size {
+				return fmt.Errorf("signature mismatch")
+			}
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			if !ecdsa.Verify(pub, digest, r, s) {
+				return fmt.Errorf("signature mismatch")
+			}
+			return nil
+		default:
+			return fmt.Errorf("unsupported key type for %q", kid)
+		}
+	default:
+		return fmt.Errorf("algorithm %q not accepted", alg)
+	}
+}
+
+// checkClaims validates exp, nbf, iss and aud and extracts the player ID
+func (v *Verifier) checkClaims(raw map[string]any) (*Claims, error) {
+	now := v.now()
+	leeway := v.config.Leeway
+
+	exp, ok := raw["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("missing exp claim")
+	}
+	expires := time.Unix(int64(exp), 0)
+	if now.After(expires.Add(leeway)) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if nbf, ok := raw["nbf"].(float64); ok && now.Add(leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("token not valid yet")
+	}
+	if v.config.Issuer != "" {
+		if iss, _ := raw["iss"].(string); iss != v.config.Issuer {
+			return nil, fmt.Errorf("unexpected issuer")
+		}
+	}
+	if v.config.Audience != "" && !hasAudience(raw["aud"], v.config.Audience) {
+		return nil, fmt.Errorf("unexpected audience")
+	}
+
+	claims := &Claims{Expires: expires, Raw: raw}
+	claims.Subject, _ = raw["sub"].(string)
+	claims.PlayerID, _ = raw[v.config.PlayerClaim].(string)
+	// "scope" is a space-separated string per RFC 8693; some issuers send "scp" as a list
+	if scope, ok := raw["scope"].(string); ok {
+		claims.Scopes = strings.Fields(scope)
+	} else if scp, ok := raw["scp"].([]any); ok {
+		for _, s := range scp {
+			if str, ok := s.(string); ok {
+				claims.Scopes = append(claims.Scopes, str)
+			}
+		}
+	}
+	return claims, nil
+}
+
+// hasAudience matches aud as either a single string or a list
+func hasAudience(aud any, want string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == want
+	case []any:
+		for _, entry := range a {
+			if s, ok := entry.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url JSON segment
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// hashFunc returns the hash an algorithm signs with
+func hashFunc(alg string) func() hash.Hash {
+	switch alg[2:] {
+	case "384":
+		return sha512.New384
+	case "512":
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+// cryptoHash is hashFunc as a crypto.Hash, for PKCS #1 v1.5
+func cryptoHash(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
@@ -0,0 +1,159 @@
+// Package backfill replays historical scores from another system into the
+// leaderboard service at a controlled rate, checkpointing its progress so an
+// interrupted run resumes where it stopped
+package backfill
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source types
+const (
+	SourceCSV      = "csv"
+	SourcePostgres = "postgres"
+	SourceKafka    = "kafka"
+)
+
+// Config is a backfill job, loaded from its own YAML file
+type Config struct {
+	Source  SourceConfig  `yaml:"source"`
+	Mapping MappingConfig `yaml:"mapping"`
+	// Rate caps submissions per second; 0 replays as fast as the service allows
+	Rate      int `yaml:"rate"`
+	BatchSize int `yaml:"batch_size"`
+	// Checkpoint is the file progress is saved to after every batch
+	Checkpoint string `yaml:"checkpoint"`
+	// ProgressInterval is how often progress is logged
+	ProgressInterval time.Duration `yaml:"progress_interval"`
+}
+
+// SourceConfig selects where historical scores are read from
+type SourceConfig struct {
+	Type     string               `yaml:"type"`
+	CSV      CSVSourceConfig      `yaml:"csv"`
+	Postgres PostgresSourceConfig `yaml:"postgres"`
+	Kafka    KafkaSourceConfig    `yaml:"kafka"`
+}
+
+// CSVSourceConfig reads a CSV file whose first row names the columns
+type CSVSourceConfig struct {
+	Path string `yaml:"path"`
+	// Delimiter is a single character; empty means a comma
+	Delimiter string `yaml:"delimiter"`
+}
+
+// PostgresSourceConfig pages through a query. The query takes the last cursor
+// value as $1 and the page size as $2, and must order by the cursor column,
+// e.g. SELECT id, user_id, points FROM scores WHERE id > $1 ORDER BY id LIMIT $2
+type PostgresSourceConfig struct {
+	DSN          string `yaml:"dsn"`
+	Query        string `yaml:"query"`
+	CursorColumn string `yaml:"cursor_column"`
+	// CursorStart is $1 for the first page, below every cursor value
+	CursorStart string `yaml:"cursor_start"`
+	PageSize    int    `yaml:"page_size"`
+}
+
+// KafkaSourceConfig reads a topic from offset 0, one partition after another,
+// up to the end offsets seen when the run starts. Messages are JSON objects.
+type KafkaSourceConfig struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+}
+
+// MappingConfig names the source fields that make up a submission. Nested
+// Kafka fields are addressed with dots, e.g. "player.id".
+type MappingConfig struct {
+	PlayerID      string `yaml:"player_id"`
+	LeaderboardID string `yaml:"leaderboard_id"`
+	Score         string `yaml:"score"`
+	// Leaderboard is the target board for every record when LeaderboardID is
+	// empty or missing from a record
+	Leaderboard string `yaml:"leaderboard"`
+	// ScoreScale multiplies source scores before rounding, e.g. 100 for
+	// scores stored with two decimals; 0 means 1
+	ScoreScale float64 `yaml:"score_scale"`
+	// Metadata copies source fields into submission metadata, keyed
+	// by the metadata key
+	Metadata map[string]string `yaml:"metadata"`
+	// IdempotencyKey names a field that identifies each record. Without it
+	// the source and the record's position are used, so a resumed run never
+	// applies a record twice within the dedup TTL.
+	IdempotencyKey string `yaml:"idempotency_key"`
+}
+
+// LoadConfig reads a backfill job file, expanding environment variables
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading backfill config: %w", err)
+	}
+	data = []byte(os.ExpandEnv(string(data)))
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing backfill config: %w", err)
+	}
+	cfg.applyDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// applyDefaults sets default values for unset fields
+func (c *Config) applyDefaults() {
+	if c.BatchSize == 0 {
+		c.BatchSize = 500
+	}
+	if c.Checkpoint == "" {
+		c.Checkpoint = "backfill.checkpoint.json"
+	}
+	if c.ProgressInterval == 0 {
+		c.ProgressInterval = 5 * time.Second
+	}
+	if c.Mapping.ScoreScale == 0 {
+		c.Mapping.ScoreScale = 1
+	}
+	if c.Source.Postgres.CursorStart == "" {
+		c.Source.Postgres.CursorStart = "0"
+	}
+	if c.Source.Postgres.PageSize == 0 {
+		c.Source.Postgres.PageSize = 1000
+	}
+}
+
+// validate checks the settings a run cannot start without
+func (c *Config) validate() error {
+	if c.Mapping.PlayerID == "" || c.Mapping.Score == "" {
+		return fmt.Errorf("backfill mapping needs player_id and score")
+	}
+	if c.Mapping.LeaderboardID == "" && c.Mapping.Leaderboard == "" {
+		return fmt.Errorf("backfill mapping needs leaderboard_id or leaderboard")
+	}
+	switch c.Source.Type {
+	case SourceCSV:
+		if c.Source.CSV.Path == "" {
+			return fmt.Errorf("csv source needs a path")
+		}
+		if len([]rune(c.Source.CSV.Delimiter)) > 1 {
+			return fmt.Errorf("csv delimiter must be one character")
+		}
+	case SourcePostgres:
+		pg := c.Source.Postgres
+		if pg.DSN == "" || pg.Query == "" || pg.CursorColumn == "" {
+			return fmt.Errorf("postgres source needs a dsn, query and cursor_column")
+		}
+	case SourceKafka:
+		if len(c.Source.Kafka.Brokers) == 0 || c.Source.Kafka.Topic == "" {
+			return fmt.Errorf("kafka source needs brokers and a topic")
+		}
+	default:
+		return fmt.Errorf("unknown backfill source type %q", c.Source.Type)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package backfill
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// csvSource reads a CSV file with a header row. Positions are line numbers.
+type csvSource struct {
+	file   *os.File
+	reader *csv.Reader
+	header []string
+	line   int
+}
+
+func newCSVSource(cfg *CSVSourceConfig, position string) (*csvSource, error) {
+	skip := 0
+	if position != "" {
+		n, err := strconv.Atoi(position)
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv checkpoint position %q", position)
+		}
+		skip = n
+	}
+
+	file, err := os.Open(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("opening csv source: %w", err)
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	if cfg.Delimiter != "" {
+		reader.Comma = []rune(cfg.Delimiter)[0]
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	s := &csvSource{file: file, reader: reader, header: append([]string(nil), header...), line: 1}
+
+	// Skip the rows applied before the checkpoint
+	for s.line < skip {
+		if _, err := reader.Read(); err != nil {
+			file.Close()
+			if err == io.EOF {
+				return nil, fmt.Errorf("csv source has fewer lines than checkpoint line %d", skip)
+			}
+			return nil, fmt.Errorf("skipping to checkpoint: %w", err)
+		}
+		s.line++
+	}
+	return s, nil
+}
+
+func (s *csvSource) Next(_ context.Context) (Record, error) {
+	row, err := s.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("reading csv line %d: %w", s.line+1, err)
+	}
+	s.line++
+
+	fields := make(map[string]any, len(s.header))
+	for i, name := range s.header {
+		if i < len(row) {
+			fields[name] = row[i]
+		}
+	}
+	return Record{Fields: fields, Position: strconv.Itoa(s.line)}, nil
+}
+
+func (s *csvSource) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,145 @@
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/IBM/sarama"
+)
+
+// kafkaSource reads a topic's partitions in order, each from its oldest
+// offset to the end offset seen at start. Positions are "partition:offset".
+type kafkaSource struct {
+	client     sarama.Client
+	consumer   sarama.Consumer
+	topic      string
+	partitions []int32
+	ends       map[int32]int64
+
+	index    int // into partitions
+	resumeAt int64
+	current  sarama.PartitionConsumer
+}
+
+func newKafkaSource(cfg *KafkaSourceConfig, position string) (*kafkaSource, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+	saramaConfig.Consumer.Return.Errors = true
+
+	client, err := sarama.NewClient(cfg.Brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to kafka source: %w", err)
+	}
+	s := &kafkaSource{client: client, topic: cfg.Topic, ends: make(map[int32]int64), resumeAt: sarama.OffsetOldest}
+
+	partitions, err := client.Partitions(cfg.Topic)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("listing partitions of %s: %w", cfg.Topic, err)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	s.partitions = partitions
+	for _, p := range partitions {
+		end, err := client.GetOffset(cfg.Topic, p, sarama.OffsetNewest)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("reading end offset of partition %d: %w", p, err)
+		}
+		s.ends[p] = end
+	}
+
+	if position != "" {
+		var partition int32
+		var offset int64
+		if _, err := fmt.Sscanf(position, "%d:%d", &partition, &offset); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("invalid kafka checkpoint position %q", position)
+		}
+		for s.index < len(partitions) && partitions[s.index] < partition {
+			s.index++
+		}
+		s.resumeAt = offset + 1
+	}
+
+	s.consumer, err = sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("creating kafka consumer: %w", err)
+	}
+	return s, nil
+}
+
+func (s *kafkaSource) Next(ctx context.Context) (Record, error) {
+	for {
+		if s.current == nil {
+			if err := s.openPartition(); err != nil {
+				return Record{}, err
+			}
+		}
+		partition := s.partitions[s.index]
+
+		select {
+		case <-ctx.Done():
+			return Record{}, ctx.Err()
+		case err := <-s.current.Errors():
+			return Record{}, fmt.Errorf("reading partition %d: %w", partition, err)
+		case message := <-s.current.Messages():
+			if message.Offset+1 >= s.ends[partition] {
+				s.finishPartition()
+			}
+			decoder := json.NewDecoder(bytes.NewReader(message.Value))
+			decoder.UseNumber()
+			var fields map[string]any
+			if err := decoder.Decode(&fields); err != nil {
+				// Unreadable messages are reported by mapping as missing fields
+				fields = map[string]any{}
+			}
+			return Record{Fields: fields, Position: fmt.Sprintf("%d:%d", partition, message.Offset)}, nil
+		}
+	}
+}
+
+// openPartition starts consuming the next partition that has records left
+func (s *kafkaSource) openPartition() error {
+	for ; s.index < len(s.partitions); s.index++ {
+		partition := s.partitions[s.index]
+		start := s.resumeAt
+		if start == sarama.OffsetOldest {
+			oldest, err := s.client.GetOffset(s.topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return fmt.Errorf("reading oldest offset of partition %d: %w", partition, err)
+			}
+			start = oldest
+		}
+		s.resumeAt = sarama.OffsetOldest
+		if start >= s.ends[partition] {
+			continue
+		}
+		pc, err := s.consumer.ConsumePartition(s.topic, partition, start)
+		if err != nil {
+			return fmt.Errorf("consuming partition %d: %w", partition, err)
+		}
+		s.current = pc
+		return nil
+	}
+	return io.EOF
+}
+
+// finishPartition moves on once a partition's end offset is reached
+func (s *kafkaSource) finishPartition() {
+	s.current.Close()
+	s.current = nil
+	s.index++
+}
+
+func (s *kafkaSource) Close() error {
+	if s.current != nil {
+		s.current.Close()
+	}
+	s.consumer.Close()
+	return s.client.Close()
+}
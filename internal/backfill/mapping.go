@@ -0,0 +1,102 @@
+package backfill
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Map turns a record into a submission using the configured field names
+func (m *MappingConfig) Map(record Record) (domain.ScoreSubmission, error) {
+	submission := domain.ScoreSubmission{
+		PlayerID:      stringify(lookup(record.Fields, m.PlayerID)),
+		LeaderboardID: m.Leaderboard,
+	}
+	if submission.PlayerID == "" {
+		return submission, fmt.Errorf("missing %s", m.PlayerID)
+	}
+	if m.LeaderboardID != "" {
+		if id := stringify(lookup(record.Fields, m.LeaderboardID)); id != "" {
+			submission.LeaderboardID = id
+		}
+	}
+	if submission.LeaderboardID == "" {
+		return submission, fmt.Errorf("missing %s", m.LeaderboardID)
+	}
+
+	raw := stringify(lookup(record.Fields, m.Score))
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return submission, fmt.Errorf("invalid score %q in %s", raw, m.Score)
+	}
+	scaled := math.Round(value * m.ScoreScale)
+	if math.IsNaN(scaled) || math.IsInf(scaled, 0) || math.Abs(scaled) > math.MaxInt64/2 {
+		return submission, fmt.Errorf("score %q out of range", raw)
+	}
+	submission.Score = int64(scaled)
+
+	for key, field := range m.Metadata {
+		value := lookup(record.Fields, field)
+		if value == nil {
+			continue
+		}
+		if submission.Metadata == nil {
+			submission.Metadata = make(map[string]interface{}, len(m.Metadata))
+		}
+		submission.Metadata[key] = value
+	}
+
+	if m.IdempotencyKey != "" {
+		if key := stringify(lookup(record.Fields, m.IdempotencyKey)); key != "" {
+			submission.IdempotencyKey = "backfill:" + key
+		}
+	}
+	return submission, nil
+}
+
+// lookup finds a field, following dots into nested objects
+func lookup(fields map[string]any, name string) any {
+	if v, ok := fields[name]; ok {
+		return v
+	}
+	var current any = fields
+	for _, part := range strings.Split(name, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
+
+// stringify renders a source value as text; times use RFC 3339 so they read
+// back as the same instant
+func stringify(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case driver.Valuer:
+		// pgx types such as numeric render through their driver value
+		if dv, err := value.Value(); err == nil {
+			return stringify(dv)
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,74 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// postgresSource pages through a keyset-paginated query. Positions are
+// cursor column values.
+type postgresSource struct {
+	conn   *pgx.Conn
+	config *PostgresSourceConfig
+	cursor string
+	page   []map[string]any
+	done   bool
+}
+
+func newPostgresSource(ctx context.Context, cfg *PostgresSourceConfig, position string) (*postgresSource, error) {
+	conn, err := pgx.Connect(ctx, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to postgres source: %w", err)
+	}
+	cursor := cfg.CursorStart
+	if position != "" {
+		cursor = position
+	}
+	return &postgresSource{conn: conn, config: cfg, cursor: cursor}, nil
+}
+
+func (s *postgresSource) Next(ctx context.Context) (Record, error) {
+	if len(s.page) == 0 {
+		if s.done {
+			return Record{}, io.EOF
+		}
+		if err := s.fetch(ctx); err != nil {
+			return Record{}, err
+		}
+		if len(s.page) == 0 {
+			return Record{}, io.EOF
+		}
+	}
+
+	fields := s.page[0]
+	s.page = s.page[1:]
+	cursor, ok := fields[s.config.CursorColumn]
+	if !ok || cursor == nil {
+		return Record{}, fmt.Errorf("query result has no %s column", s.config.CursorColumn)
+	}
+	s.cursor = stringify(cursor)
+	return Record{Fields: fields, Position: s.cursor}, nil
+}
+
+// fetch reads the page after the current cursor
+func (s *postgresSource) fetch(ctx context.Context) error {
+	rows, err := s.conn.Query(ctx, s.config.Query, s.cursor, s.config.PageSize)
+	if err != nil {
+		return fmt.Errorf("querying postgres source: %w", err)
+	}
+	page, err := pgx.CollectRows(rows, pgx.RowToMap)
+	if err != nil {
+		return fmt.Errorf("reading postgres source: %w", err)
+	}
+	s.page = page
+	// A short page is the last one
+	s.done = len(page) < s.config.PageSize
+	return nil
+}
+
+func (s *postgresSource) Close() error {
+	return s.conn.Close(context.Background())
+}
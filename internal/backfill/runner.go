@@ -0,0 +1,265 @@
+package backfill
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// maxLoggedErrors caps the rejected records logged per batch
+const maxLoggedErrors = 5
+
+// Submitter applies batches of score submissions
+type Submitter interface {
+	SubmitScoreBatchWithResult(ctx context.Context, batch domain.BatchScoreSubmission) domain.BatchResult
+}
+
+// Checkpoint is a run's progress, saved after every applied batch
+type Checkpoint struct {
+	// Source identifies what was read, so a checkpoint is never resumed
+	// against a different source
+	Source     string    `json:"source"`
+	Position   string    `json:"position"`
+	Read       int64     `json:"read"`
+	Applied    int64     `json:"applied"`
+	Duplicates int64     `json:"duplicates"`
+	Rejected   int64     `json:"rejected"`
+	Unmapped   int64     `json:"unmapped"`
+	Done       bool      `json:"done"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SourceID describes a source for checkpoint matching
+func (c *SourceConfig) SourceID() string {
+	switch c.Type {
+	case SourceCSV:
+		return "csv:" + c.CSV.Path
+	case SourcePostgres:
+		return "postgres:" + c.Postgres.Query
+	case SourceKafka:
+		return "kafka:" + c.Kafka.Topic
+	default:
+		return c.Type
+	}
+}
+
+// LoadCheckpoint reads the checkpoint for the configured source, or returns
+// an empty one when the file does not exist
+func LoadCheckpoint(cfg *Config) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{Source: cfg.Source.SourceID()}
+	data, err := os.ReadFile(cfg.Checkpoint)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	var saved Checkpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", cfg.Checkpoint, err)
+	}
+	if saved.Source != checkpoint.Source {
+		return nil, fmt.Errorf("checkpoint %s belongs to source %q, not %q; pass -restart to start over", cfg.Checkpoint, saved.Source, checkpoint.Source)
+	}
+	return &saved, nil
+}
+
+// save writes the checkpoint through a temporary file, so a crash never
+// leaves a partial one
+func (c *Checkpoint) save(path string) error {
+	c.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Runner replays a source into the service in paced batches
+type Runner struct {
+	submitter Submitter
+	config    *Config
+	logger    *slog.Logger
+	// DryRun maps and counts records without submitting or checkpointing them
+	DryRun bool
+}
+
+// NewRunner creates a runner for a backfill job
+func NewRunner(submitter Submitter, cfg *Config, logger *slog.Logger) *Runner {
+	return &Runner{submitter: submitter, config: cfg, logger: logger}
+}
+
+// Run reads the source to its end, continuing the checkpoint. Cancelling ctx
+// stops after the batch in flight, with the checkpoint saved.
+func (r *Runner) Run(ctx context.Context, source Source, checkpoint *Checkpoint) error {
+	started := time.Now()
+	startRead := checkpoint.Read
+	lastProgress := started
+	// next is when the following batch may be submitted under the rate cap
+	next := started
+
+	// Position keys are scoped to the source, since two CSV files share line numbers
+	sum := sha256.Sum256([]byte(checkpoint.Source))
+	sourceTag := "backfill:" + hex.EncodeToString(sum[:4]) + ":"
+
+	batch := make([]domain.ScoreSubmission, 0, r.config.BatchSize)
+	var position string
+	flush := func() error {
+		if len(batch) > 0 {
+			if err := r.pace(ctx, &next, len(batch)); err != nil {
+				return err
+			}
+			r.apply(ctx, batch, checkpoint)
+			batch = batch[:0]
+		}
+		if position == "" || r.DryRun {
+			return nil
+		}
+		checkpoint.Position = position
+		return checkpoint.save(r.config.Checkpoint)
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		record, err := source.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Records read so far are applied and checkpointed before stopping
+			if flushErr := flush(); flushErr != nil {
+				return flushErr
+			}
+			return err
+		}
+		checkpoint.Read++
+		position = record.Position
+
+		submission, err := r.config.Mapping.Map(record)
+		if err != nil {
+			checkpoint.Unmapped++
+			if checkpoint.Unmapped <= maxLoggedErrors {
+				r.logger.Warn("skipping unmappable record", "position", record.Position, "error", err)
+			}
+		} else {
+			if submission.IdempotencyKey == "" {
+				submission.IdempotencyKey = sourceTag + record.Position
+			}
+			batch = append(batch, submission)
+		}
+
+		if len(batch) >= r.config.BatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if time.Since(lastProgress) >= r.config.ProgressInterval {
+			lastProgress = time.Now()
+			r.logProgress("backfill progress", checkpoint, startRead, started)
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+	checkpoint.Done = true
+	if !r.DryRun {
+		if err := checkpoint.save(r.config.Checkpoint); err != nil {
+			return err
+		}
+	}
+	r.logProgress("backfill complete", checkpoint, startRead, started)
+	return nil
+}
+
+// pace waits until the rate cap allows a batch of n submissions
+func (r *Runner) pace(ctx context.Context, next *time.Time, n int) error {
+	if r.config.Rate <= 0 {
+		return nil
+	}
+	if wait := time.Until(*next); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	// A slow batch does not earn credit for a burst afterwards
+	base := *next
+	if now := time.Now(); now.After(base) {
+		base = now
+	}
+	*next = base.Add(time.Duration(n) * time.Second / time.Duration(r.config.Rate))
+	return nil
+}
+
+// apply submits a batch and counts the outcome. Rejected submissions are
+// counted and logged but do not stop the run, matching Kafka ingestion.
+func (r *Runner) apply(ctx context.Context, batch []domain.ScoreSubmission, checkpoint *Checkpoint) {
+	if r.DryRun {
+		checkpoint.Applied += int64(len(batch))
+		return
+	}
+	// The batch is applied in full even if the run is cancelled meanwhile,
+	// so the checkpoint that follows is accurate
+	result := r.submitter.SubmitScoreBatchWithResult(context.WithoutCancel(ctx), domain.BatchScoreSubmission{Scores: batch})
+	checkpoint.Applied += int64(result.Accepted + result.Pending)
+	checkpoint.Duplicates += int64(result.Duplicates)
+	checkpoint.Rejected += int64(len(result.Failed))
+	for i, failure := range result.Failed {
+		if i == maxLoggedErrors {
+			r.logger.Warn("more submissions rejected in batch", "count", len(result.Failed)-i)
+			break
+		}
+		r.logger.Warn("submission rejected",
+			"player_id", failure.PlayerID,
+			"leaderboard_id", failure.LeaderboardID,
+			"error", failure.Error,
+		)
+	}
+}
+
+// logProgress logs the counts and the read rate of this run
+func (r *Runner) logProgress(msg string, checkpoint *Checkpoint, startRead int64, started time.Time) {
+	elapsed := time.Since(started)
+	rate := float64(checkpoint.Read-startRead) / elapsed.Seconds()
+	r.logger.Info(msg,
+		"read", checkpoint.Read,
+		"applied", checkpoint.Applied,
+		"duplicates", checkpoint.Duplicates,
+		"rejected", checkpoint.Rejected,
+		"unmapped", checkpoint.Unmapped,
+		"position", checkpoint.Position,
+		"records_per_second", int64(rate),
+		"elapsed", elapsed.Round(time.Second),
+	)
+}
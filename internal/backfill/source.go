@@ -0,0 +1,35 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+)
+
+// Record is one source row. Position identifies the record in the source;
+// resuming from it continues with the record after it.
+type Record struct {
+	Fields   map[string]any
+	Position string
+}
+
+// Source reads records in a stable order. Next returns io.EOF after the last
+// record.
+type Source interface {
+	Next(ctx context.Context) (Record, error)
+	Close() error
+}
+
+// NewSource opens the configured source, resuming after position when it is
+// not empty
+func NewSource(ctx context.Context, cfg *SourceConfig, position string) (Source, error) {
+	switch cfg.Type {
+	case SourceCSV:
+		return newCSVSource(&cfg.CSV, position)
+	case SourcePostgres:
+		return newPostgresSource(ctx, &cfg.Postgres, position)
+	case SourceKafka:
+		return newKafkaSource(&cfg.Kafka, position)
+	default:
+		return nil, fmt.Errorf("unknown backfill source type %q", cfg.Type)
+	}
+}
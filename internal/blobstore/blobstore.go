@@ -0,0 +1,35 @@
+// Package blobstore writes published files to object storage
+package blobstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// Object is a file to write, with the headers a CDN serves it with
+type Object struct {
+	Key          string
+	Body         []byte
+	ContentType  string
+	CacheControl string
+}
+
+// Store writes objects, replacing any object already at the key
+type Store interface {
+	Put(ctx context.Context, obj Object) error
+}
+
+// New creates the store selected by the config. Object keys are prefixed with
+// the configured prefix.
+func New(cfg *config.BlobStoreConfig) (Store, error) {
+	switch cfg.Type {
+	case config.BlobStoreS3:
+		return NewS3Store(cfg)
+	case config.BlobStoreDir:
+		return NewDirStore(cfg)
+	default:
+		return nil, fmt.Errorf("unknown blob store type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,58 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// DirStore writes objects as files under a directory, for serving with a
+// plain web server or testing without object storage. Headers are not kept.
+type DirStore struct {
+	root   string
+	prefix string
+}
+
+// NewDirStore creates a store rooted at the configured directory
+func NewDirStore(cfg *config.BlobStoreConfig) (*DirStore, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("dir blob store needs a dir")
+	}
+	return &DirStore{root: cfg.Dir, prefix: cfg.Prefix}, nil
+}
+
+// Put writes the object through a temporary file, so readers never see a partial file
+func (s *DirStore) Put(_ context.Context, obj Object) error {
+	key := s.prefix + obj.Key
+	if strings.Contains(key, "..") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating object directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".publish-*")
+	if err != nil {
+		return fmt.Errorf("creating object file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(obj.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing object file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing object file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("writing object file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing object file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Fault injection targets
+const (
+	TargetRedis     = "redis"
+	TargetPostgres  = "postgres"
+	TargetBroadcast = "broadcast"
+)
+
+// ErrInjected is returned by calls failed on purpose
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Rule describes the faults applied to one target
+type Rule struct {
+	// LatencyMs is added before every call
+	LatencyMs int `json:"latency_ms"`
+	// ErrorRate is the fraction of calls, 0 to 1, that fail with ErrInjected
+	ErrorRate float64 `json:"error_rate"`
+	// DropRate is the fraction of broadcasts, 0 to 1, that are silently dropped
+	DropRate float64 `json:"drop_rate"`
+}
+
+// Validate checks that rates are fractions and latency is not negative
+func (r Rule) Validate() error {
+	if r.LatencyMs < 0 {
+		return fmt.Errorf("latency_ms must not be negative")
+	}
+	if r.ErrorRate < 0 || r.ErrorRate > 1 {
+		return fmt.Errorf("error_rate must be between 0 and 1")
+	}
+	if r.DropRate < 0 || r.DropRate > 1 {
+		return fmt.Errorf("drop_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// Injector holds the active fault rules. A nil Injector injects nothing.
+type Injector struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewInjector creates an injector with no active rules
+func NewInjector() *Injector {
+	return &Injector{rules: make(map[string]Rule)}
+}
+
+// IsTarget reports whether target names a known injection point
+func IsTarget(target string) bool {
+	switch target {
+	case TargetRedis, TargetPostgres, TargetBroadcast:
+		return true
+	}
+	return false
+}
+
+// Set replaces the rule for a target
+func (i *Injector) Set(target string, rule Rule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules[target] = rule
+}
+
+// Clear removes the rule for a target, or all rules when target is empty
+func (i *Injector) Clear(target string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if target == "" {
+		i.rules = make(map[string]Rule)
+		return
+	}
+	delete(i.rules, target)
+}
+
+// Rules returns a copy of the active rules
+func (i *Injector) Rules() map[string]Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	rules := make(map[string]Rule, len(i.rules))
+	for target, rule := range i.rules {
+		rules[target] = rule
+	}
+	return rules
+}
+
+// rule returns the rule for a target
+func (i *Injector) rule(target string) (Rule, bool) {
+	if i == nil {
+		return Rule{}, false
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	rule, ok := i.rules[target]
+	return rule, ok
+}
+
+// Inject applies the target's latency and returns ErrInjected for the configured share of calls
+func (i *Injector) Inject(ctx context.Context, target string) error {
+	rule, ok := i.rule(target)
+	if !ok {
+		return nil
+	}
+
+	if rule.LatencyMs > 0 {
+		timer := time.NewTimer(time.Duration(rule.LatencyMs) * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if rule.ErrorRate > 0 && rand.Float64() < rule.ErrorRate {
+		return fmt.Errorf("%s: %w", target, ErrInjected)
+	}
+	return nil
+}
+
+// Drop reports whether a message for the target should be discarded
+func (i *Injector) Drop(target string) bool {
+	rule, ok := i.rule(target)
+	return ok && rule.DropRate > 0 && rand.Float64() < rule.DropRate
+}
@@ -0,0 +1,48 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresTracer injects faults into pgx queries and batches. pgx tracers cannot
+// return errors, so a failure is injected by handing pgx an already-cancelled
+// context, which fails the call before anything is sent to the server.
+type PostgresTracer struct {
+	injector *Injector
+}
+
+// NewPostgresTracer creates a pgx tracer backed by the injector
+func NewPostgresTracer(injector *Injector) *PostgresTracer {
+	return &PostgresTracer{injector: injector}
+}
+
+// inject applies the postgres rule and returns the context pgx should continue with
+func (t *PostgresTracer) inject(ctx context.Context) context.Context {
+	if err := t.injector.Inject(ctx, TargetPostgres); err != nil {
+		ctx, cancel := context.WithCancelCause(ctx)
+		cancel(err)
+		return ctx
+	}
+	return ctx
+}
+
+// TraceQueryStart injects faults before a query
+func (t *PostgresTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return t.inject(ctx)
+}
+
+// TraceQueryEnd is a no-op
+func (t *PostgresTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+// TraceBatchStart injects faults before a batch
+func (t *PostgresTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return t.inject(ctx)
+}
+
+// TraceBatchQuery is a no-op
+func (t *PostgresTracer) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+// TraceBatchEnd is a no-op
+func (t *PostgresTracer) TraceBatchEnd(context.Context, *pgx.Conn, pgx.TraceBatchEndData) {}
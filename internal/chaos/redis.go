@@ -0,0 +1,49 @@
+package chaos
+
+import (
+	"context"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook injects faults into Redis commands and pipelines
+type RedisHook struct {
+	injector *Injector
+}
+
+// NewRedisHook creates a go-redis hook backed by the injector
+func NewRedisHook(injector *Injector) *RedisHook {
+	return &RedisHook{injector: injector}
+}
+
+// DialHook leaves connection setup untouched
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook injects faults before single commands
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.injector.Inject(ctx, TargetRedis); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook injects faults before pipelines and transactions
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.injector.Inject(ctx, TargetRedis); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,151 @@
+// Package clock abstracts wall time so resets, retention, and other
+// time-driven behaviour can be fast-forwarded in tests and simulation mode.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates tickers
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at an interval, like time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real returns a Clock backed by the system clock
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t realTicker) Stop()               { t.ticker.Stop() }
+
+// Simulated is a Clock that only moves when told to. Tickers fire as the
+// clock is advanced past their next tick; like time.Ticker, ticks are dropped
+// when the receiver is not keeping up.
+type Simulated struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*simulatedTicker
+}
+
+// NewSimulated creates a simulated clock starting at start
+func NewSimulated(start time.Time) *Simulated {
+	return &Simulated{now: start}
+}
+
+// Now returns the simulated time
+func (s *Simulated) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Since returns the simulated time elapsed since t
+func (s *Simulated) Since(t time.Time) time.Duration {
+	return s.Now().Sub(t)
+}
+
+// NewTicker creates a ticker driven by Advance
+func (s *Simulated) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &simulatedTicker{
+		clock:    s,
+		c:        make(chan time.Time, 1),
+		interval: d,
+		next:     s.now.Add(d),
+	}
+	s.tickers = append(s.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing every tick that falls in between in time order
+func (s *Simulated) Advance(d time.Duration) {
+	s.Set(s.Now().Add(d))
+}
+
+// Set moves the clock to t. Moving backwards changes the time without firing tickers.
+func (s *Simulated) Set(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		next := s.nextTicker(t)
+		if next == nil {
+			break
+		}
+		s.now = next.next
+		next.next = next.next.Add(next.interval)
+		select {
+		case next.c <- s.now:
+		default:
+		}
+	}
+	s.now = t
+}
+
+// nextTicker returns the ticker due soonest at or before t. Callers must hold s.mu.
+func (s *Simulated) nextTicker(t time.Time) *simulatedTicker {
+	due := make([]*simulatedTicker, 0, len(s.tickers))
+	for _, ticker := range s.tickers {
+		if !ticker.next.After(t) {
+			due = append(due, ticker)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].next.Before(due[j].next) })
+	return due[0]
+}
+
+// remove stops delivering ticks to t
+func (s *Simulated) remove(t *simulatedTicker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, ticker := range s.tickers {
+		if ticker == t {
+			s.tickers = append(s.tickers[:i], s.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+type simulatedTicker struct {
+	clock    *Simulated
+	c        chan time.Time
+	interval time.Duration
+	next     time.Time
+}
+
+func (t *simulatedTicker) C() <-chan time.Time { return t.c }
+func (t *simulatedTicker) Stop()               { t.clock.remove(t) }
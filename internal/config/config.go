@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"time"
 
@@ -10,12 +11,29 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server      ServerConfig      `yaml:"server"`
-	Redis       RedisConfig       `yaml:"redis"`
-	Postgres    PostgresConfig    `yaml:"postgres"`
-	Kafka       KafkaConfig       `yaml:"kafka"`
-	Sync        SyncConfig        `yaml:"sync"`
-	Leaderboard LeaderboardConfig `yaml:"leaderboard"`
+	Server      ServerConfig         `yaml:"server"`
+	Auth        AuthConfig           `yaml:"auth"`
+	RateLimit   RateLimitConfig      `yaml:"rate_limit"`
+	Redis       RedisConfig          `yaml:"redis"`
+	Postgres    PostgresConfig       `yaml:"postgres"`
+	Kafka       KafkaConfig          `yaml:"kafka"`
+	Sync        SyncConfig           `yaml:"sync"`
+	Leaderboard LeaderboardConfig    `yaml:"leaderboard"`
+	Events      EventsConfig         `yaml:"events"`
+	Retention   RetentionConfig      `yaml:"retention"`
+	Expiry      ExpiryConfig         `yaml:"expiry"`
+	Staleness   StalenessConfig      `yaml:"staleness"`
+	Publish     PublishConfig        `yaml:"publish"`
+	Purge       PurgeConfig          `yaml:"purge"`
+	Logging     LoggingConfig        `yaml:"logging"`
+	Errors      ErrorReportingConfig `yaml:"error_reporting"`
+	Chaos       ChaosConfig          `yaml:"chaos"`
+	Features    FeaturesConfig       `yaml:"features"`
+	Reset       ResetConfig          `yaml:"reset"`
+	Ingest      IngestConfig         `yaml:"ingest"`
+	Replication ReplicationConfig    `yaml:"replication"`
+	Migration   MigrationConfig      `yaml:"migration"`
+	WebSocket   WebSocketConfig      `yaml:"websocket"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -24,6 +42,77 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	AccessLog   AccessLogConfig   `yaml:"access_log"`
+	RequestBody RequestBodyConfig `yaml:"request_body"`
+
+	// JSONEncoder names the response encoder: std, or jsoniter / sonic when
+	// the binary is built with the matching tag
+	JSONEncoder string `yaml:"json_encoder"`
+}
+
+// AuthConfig holds JWT bearer-token validation for player-facing endpoints.
+// When enabled, score submissions need a token whose player claim matches the
+// submitted player_id.
+type AuthConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// JWKSURL publishes the RS* and ES* signing keys; keys are refetched every
+	// JWKSRefresh and when a token names an unknown key
+	JWKSURL     string        `yaml:"jwks_url"`
+	JWKSRefresh time.Duration `yaml:"jwks_refresh"`
+	// HMACSecret verifies HS256 tokens; leave empty to accept only JWKS keys
+	HMACSecret string `yaml:"hmac_secret"`
+	// PlayerClaim names the claim holding the player ID
+	PlayerClaim string `yaml:"player_claim"`
+	// ServerScope lets tokens carrying this scope submit for any player, for
+	// trusted game servers; empty disables it
+	ServerScope string `yaml:"server_scope"`
+	// AdminScope lets tokens carrying this scope call the /me endpoints as
+	// another player with X-On-Behalf-Of; each such request is written to the
+	// admin audit log. Empty disables it.
+	AdminScope string        `yaml:"admin_scope"`
+	Leeway     time.Duration `yaml:"leeway"`
+}
+
+// RateLimitConfig holds the sliding-window limits on HTTP score submissions
+// and reads, counted in Redis so they hold across instances. A negative limit disables it.
+type RateLimitConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"`
+	// PerPlayer caps one player's submissions to one leaderboard per window
+	PerPlayer int `yaml:"per_player"`
+	// PerAPIKey caps all submissions of one client per window: a registered
+	// X-API-Key, or the client IP for requests without one
+	PerAPIKey int `yaml:"per_api_key"`
+	// Leaderboards overrides PerPlayer by leaderboard ID
+	Leaderboards map[string]int `yaml:"leaderboards"`
+	// Keys registers API keys, mapping a key ID to the hex SHA-256 of the key,
+	// so secrets stay out of the config. Unregistered keys are ignored.
+	Keys map[string]string `yaml:"keys"`
+	// APIKeys overrides PerAPIKey by key ID
+	APIKeys map[string]int `yaml:"api_keys"`
+	// Reads caps the leaderboard and player reads of one client per window,
+	// keyed the same way as PerAPIKey
+	Reads int `yaml:"reads"`
+	// TrustedProxies lists the addresses or CIDR ranges of proxies in front of
+	// the server. A client IP is the connection's address unless that is a
+	// trusted proxy, in which case the X-Forwarded-For entry it added is used.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// RequestBodyConfig holds limits for compressed request bodies
+type RequestBodyConfig struct {
+	// MaxDecompressedBytes caps a gzip or zstd body after decompression
+	MaxDecompressedBytes int64 `yaml:"max_decompressed_bytes"`
+}
+
+// AccessLogConfig holds HTTP access log configuration
+type AccessLogConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// SampleRates logs 1 in N successful requests per route pattern, e.g. /api/v1/scores: 100
+	SampleRates map[string]int `yaml:"sample_rates"`
 }
 
 // RedisConfig holds Redis connection configuration
@@ -36,6 +125,55 @@ type RedisConfig struct {
 	DialTimeout  time.Duration `yaml:"dial_timeout"`
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
+
+	// KeyPrefix and Tenant namespace every key, e.g. "staging:acme:leaderboard:...",
+	// so several environments can share one Redis instance. Both empty keeps the
+	// unprefixed layout.
+	KeyPrefix string `yaml:"key_prefix"`
+	Tenant    string `yaml:"tenant"`
+
+	Cache CacheConfig `yaml:"cache"`
+}
+
+// KeyNamespace returns the prefix put in front of every Redis key, ending in
+// ":" unless it is empty
+func (c RedisConfig) KeyNamespace() string {
+	return KeyNamespace(c.KeyPrefix, c.Tenant)
+}
+
+// KeyNamespace joins a key prefix and tenant segment into a key namespace
+func KeyNamespace(prefix, tenant string) string {
+	var ns string
+	for _, segment := range []string{prefix, tenant} {
+		if segment != "" {
+			ns += segment + ":"
+		}
+	}
+	return ns
+}
+
+// CacheConfig bounds how long cache keys live in Redis. Reads refresh a key's
+// TTL, so only entries nobody asks for expire.
+type CacheConfig struct {
+	// PlayerInfoTTL expires player:{id}:info hashes idle for this long; a negative TTL keeps them forever
+	PlayerInfoTTL time.Duration `yaml:"player_info_ttl"`
+	// SweepInterval is how often cache keys written without a TTL are given one; negative disables sweeping
+	SweepInterval time.Duration `yaml:"sweep_interval"`
+	// SweepBatch is the SCAN count used per sweep step
+	SweepBatch int `yaml:"sweep_batch"`
+
+	// PageMinStart is the first rank offset served from materialized page
+	// windows instead of the live sorted set; 0 disables the page cache
+	PageMinStart int `yaml:"page_min_start"`
+	// PageSize is how many ranks one materialized window holds
+	PageSize int `yaml:"page_size"`
+	// PageTTL bounds how stale a materialized window can be
+	PageTTL time.Duration `yaml:"page_ttl"`
+}
+
+// SweepEnabled reports whether keys left without a TTL are swept
+func (c CacheConfig) SweepEnabled() bool {
+	return c.PlayerInfoTTL > 0 && c.SweepInterval > 0
 }
 
 // PostgresConfig holds PostgreSQL connection configuration
@@ -74,6 +212,81 @@ type KafkaConfig struct {
 	BatchTimeout  time.Duration `yaml:"batch_timeout"`
 	RetryAttempts int           `yaml:"retry_attempts"`
 	RetryDelay    time.Duration `yaml:"retry_delay"`
+	// InitialOffset is where a group with no committed offset starts: newest or oldest
+	InitialOffset string `yaml:"initial_offset"`
+	// CommitMode is auto (offsets committed every CommitInterval as messages are
+	// read) or manual (committed synchronously after each batch is applied)
+	CommitMode     string        `yaml:"commit_mode"`
+	CommitInterval time.Duration `yaml:"commit_interval"`
+	// AutoCreateTopics creates missing consumed topics at startup with Partitions
+	// and ReplicationFactor; otherwise a missing topic stops startup
+	AutoCreateTopics  bool  `yaml:"auto_create_topics"`
+	Partitions        int32 `yaml:"partitions"`
+	ReplicationFactor int16 `yaml:"replication_factor"`
+	// Workers applies each batch with this many goroutines, sharded by player
+	// so one player's scores are still applied in order
+	Workers int `yaml:"workers"`
+
+	// Topics consumes several topics with their own batching, e.g. a priority
+	// lane for tournament boards. When empty, Topic is consumed with the
+	// batch settings above.
+	Topics []KafkaTopicConfig `yaml:"topics"`
+
+	// DeadLetter republishes messages the consumer cannot apply instead of
+	// only logging them
+	DeadLetter KafkaDeadLetterConfig `yaml:"dead_letter"`
+}
+
+// KafkaDeadLetterConfig configures the dead-letter topic. Unparseable and
+// invalid messages, submissions rejected for good (e.g. an unknown
+// leaderboard) and submissions still failing after RetryAttempts are
+// republished to Topic with headers describing the failure.
+type KafkaDeadLetterConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Topic   string `yaml:"topic"`
+}
+
+// Kafka initial offsets and commit modes
+const (
+	KafkaOffsetNewest = "newest"
+	KafkaOffsetOldest = "oldest"
+
+	KafkaCommitAuto   = "auto"
+	KafkaCommitManual = "manual"
+)
+
+// KafkaTopicConfig is one consumed topic. Zero batch settings fall back to the
+// consumer-wide ones; a priority topic flushes as soon as it has no more
+// messages waiting instead of filling a batch.
+type KafkaTopicConfig struct {
+	Name         string        `yaml:"name"`
+	BatchSize    int           `yaml:"batch_size"`
+	BatchTimeout time.Duration `yaml:"batch_timeout"`
+	Priority     bool          `yaml:"priority"`
+
+	// Routing for the topic's messages: LeaderboardID fills in messages without
+	// one, and UpdateMode (replace, increment, best) overrides the board's mode
+	LeaderboardID string `yaml:"leaderboard_id"`
+	UpdateMode    string `yaml:"update_mode"`
+}
+
+// TopicSpecs returns the topics to consume with their batch settings resolved
+func (c *KafkaConfig) TopicSpecs() []KafkaTopicConfig {
+	if len(c.Topics) == 0 {
+		return []KafkaTopicConfig{{Name: c.Topic, BatchSize: c.BatchSize, BatchTimeout: c.BatchTimeout}}
+	}
+
+	specs := make([]KafkaTopicConfig, len(c.Topics))
+	for i, topic := range c.Topics {
+		if topic.BatchSize == 0 {
+			topic.BatchSize = c.BatchSize
+		}
+		if topic.BatchTimeout == 0 {
+			topic.BatchTimeout = c.BatchTimeout
+		}
+		specs[i] = topic
+	}
+	return specs
 }
 
 // SyncConfig holds synchronization worker configuration
@@ -81,12 +294,567 @@ type SyncConfig struct {
 	Interval  time.Duration `yaml:"interval"`
 	BatchSize int           `yaml:"batch_size"`
 	Enabled   bool          `yaml:"enabled"`
+
+	// SpreadWindow staggers board syncs evenly across this window (0 = back-to-back)
+	SpreadWindow time.Duration `yaml:"spread_window"`
+	// Jitter adds a random delay of up to this duration to each board's slot
+	Jitter time.Duration `yaml:"jitter"`
+	// Concurrency limits how many boards sync at the same time
+	Concurrency int `yaml:"concurrency"`
+
+	// ConflictPolicy decides whether sync may overwrite rows changed in PostgreSQL
+	ConflictPolicy string `yaml:"conflict_policy"`
 }
 
+// Sync conflict policies
+const (
+	SyncConflictOverwrite = "overwrite"
+	SyncConflictNewerWins = "newer_wins"
+)
+
 // LeaderboardConfig holds leaderboard-specific configuration
 type LeaderboardConfig struct {
 	DefaultLimit int `yaml:"default_limit"`
 	MaxLimit     int `yaml:"max_limit"`
+	// VersionWaitTimeout bounds how long a read with ?min_version= waits for that version
+	VersionWaitTimeout time.Duration `yaml:"version_wait_timeout"`
+	// StatsCacheTTL is how long computed stats are reused; StatsSampleSize caps how
+	// many scores are read for average and stddev before sampling kicks in
+	StatsCacheTTL   time.Duration `yaml:"stats_cache_ttl"`
+	StatsSampleSize int           `yaml:"stats_sample_size"`
+	Feed            FeedConfig    `yaml:"feed"`
+	// DedupTTL is how long an idempotency key suppresses copies of its submission
+	DedupTTL time.Duration `yaml:"dedup_ttl"`
+	Scripts  ScriptConfig  `yaml:"scripts"`
+	// DerivedRefresh is how long a parent's derived leaderboard definitions are
+	// cached before other instances' changes are picked up
+	DerivedRefresh time.Duration `yaml:"derived_refresh"`
+	// OverviewCacheTTL is how long the cross-leaderboard overview is reused
+	OverviewCacheTTL time.Duration       `yaml:"overview_cache_ttl"`
+	Anonymization    AnonymizationConfig `yaml:"anonymization"`
+	Encryption       EncryptionConfig    `yaml:"encryption"`
+	Prizes           PrizesConfig        `yaml:"prizes"`
+	IDs              IDPolicyConfig      `yaml:"ids"`
+}
+
+// Leaderboard ID policies
+const (
+	IDPolicyFree    = "free"
+	IDPolicyUUID    = "uuid"
+	IDPolicySlug    = "slug"
+	IDPolicyPattern = "pattern"
+)
+
+// IDPolicyConfig sets how leaderboard IDs are validated, on create and on every
+// HTTP and Kafka submission, and how they are generated when a create request
+// omits one
+type IDPolicyConfig struct {
+	Policy string `yaml:"policy"`
+	// Prefix is required on every ID and prepended to generated ones, e.g. "lb_".
+	// The policy checks the rest of the ID.
+	Prefix string `yaml:"prefix"`
+	// Pattern is the regular expression IDs must match under the pattern policy
+	Pattern   string `yaml:"pattern"`
+	MaxLength int    `yaml:"max_length"`
+}
+
+// PrizesConfig holds named prize structures for payout calculation. Every
+// computed payout is published to Topic on kafka.brokers; an empty topic
+// disables publishing.
+type PrizesConfig struct {
+	Structures map[string]PrizeStructureConfig `yaml:"structures"`
+	Topic      string                          `yaml:"topic"`
+}
+
+// PrizeStructureConfig maps rank brackets to rewards. TiePolicy is split,
+// shared or rank_order; empty means split.
+type PrizeStructureConfig struct {
+	TiePolicy string               `yaml:"tie_policy"`
+	Brackets  []PrizeBracketConfig `yaml:"brackets"`
+}
+
+// PrizeBracketConfig pays Amount, and optionally Label, to each position from
+// FromRank to ToRank; a zero ToRank pays FromRank only
+type PrizeBracketConfig struct {
+	FromRank int64  `yaml:"from_rank"`
+	ToRank   int64  `yaml:"to_rank"`
+	Amount   int64  `yaml:"amount"`
+	Label    string `yaml:"label"`
+}
+
+// EncryptionConfig keys the AES-GCM encryption of sensitive metadata fields
+// before they are stored in PostgreSQL. Keys maps key IDs to base64-encoded
+// 16, 24 or 32 byte keys. New values are sealed with ActiveKey; retired keys
+// stay listed so older rows can still be read. Callers presenting
+// DecryptToken read the plaintext.
+type EncryptionConfig struct {
+	ActiveKey    string            `yaml:"active_key"`
+	Keys         map[string]string `yaml:"keys"`
+	DecryptToken string            `yaml:"decrypt_token"`
+}
+
+// Enabled reports whether an encryption key is configured
+func (c EncryptionConfig) Enabled() bool {
+	return c.ActiveKey != ""
+}
+
+// AnonymizationConfig keys the pseudonyms that replace player IDs in reads
+// made with ?anonymize=true. Salt is the tenant's secret; reads cannot be
+// anonymized until it is set.
+type AnonymizationConfig struct {
+	Salt string `yaml:"salt"`
+}
+
+// ScriptConfig sandboxes Lua scoring scripts. Timeout bounds each run's wall
+// time; the call stack and registry caps bound recursion depth and the values
+// on the Lua stack. gopher-lua has no heap limit, so memory is bounded only
+// approximately: MaxInstructions also caps how many table entries a run can
+// create, and MaxAllocBytes caps the bytes of strings it builds.
+type ScriptConfig struct {
+	Timeout         time.Duration `yaml:"timeout"`
+	MaxSourceBytes  int           `yaml:"max_source_bytes"`
+	CallStackSize   int           `yaml:"call_stack_size"`
+	RegistryMaxSize int           `yaml:"registry_max_size"`
+	MaxInstructions int           `yaml:"max_instructions"`
+	MaxAllocBytes   int           `yaml:"max_alloc_bytes"`
+}
+
+// FeedConfig controls the per-leaderboard activity feed of notable events
+type FeedConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxLength caps each feed; older events are trimmed approximately
+	MaxLength int64 `yaml:"max_length"`
+	// TopN is the rank a player must reach for a top_entry event
+	TopN int64 `yaml:"top_n"`
+}
+
+// Event sampling modes
+const (
+	EventSamplingAll       = "all"
+	EventSamplingSample    = "sample"
+	EventSamplingAggregate = "aggregate"
+)
+
+// EventsConfig holds score event recording configuration
+type EventsConfig struct {
+	Sampling  EventSamplingConfig            `yaml:"sampling"`
+	Overrides map[string]EventSamplingConfig `yaml:"overrides"`
+	Buffer    EventBufferConfig              `yaml:"buffer"`
+}
+
+// EventBufferConfig controls write-behind buffering of score events. When
+// enabled, submissions queue their event and return; a background writer
+// inserts queued events in batches.
+type EventBufferConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Size is how many events can wait in the queue; once it is full,
+	// submissions write their event synchronously
+	Size int `yaml:"size"`
+	// BatchSize caps the events written by one COPY
+	BatchSize int `yaml:"batch_size"`
+	// FlushInterval is the longest a queued event waits before it is written
+	FlushInterval time.Duration `yaml:"flush_interval"`
+}
+
+// EventSamplingConfig controls how submit events are written to score_events
+type EventSamplingConfig struct {
+	Mode            string        `yaml:"mode"`
+	SampleRate      int           `yaml:"sample_rate"`
+	AggregateWindow time.Duration `yaml:"aggregate_window"`
+}
+
+// ForLeaderboard returns the sampling configuration that applies to a leaderboard
+func (c *EventsConfig) ForLeaderboard(leaderboardID string) EventSamplingConfig {
+	if override, ok := c.Overrides[leaderboardID]; ok {
+		if override.Mode == "" {
+			override.Mode = c.Sampling.Mode
+		}
+		if override.SampleRate == 0 {
+			override.SampleRate = c.Sampling.SampleRate
+		}
+		if override.AggregateWindow == 0 {
+			override.AggregateWindow = c.Sampling.AggregateWindow
+		}
+		return override
+	}
+	return c.Sampling
+}
+
+// Orphaned score event policies
+const (
+	OrphanPolicyKeep    = "keep"
+	OrphanPolicyDelete  = "delete"
+	OrphanPolicyArchive = "archive"
+)
+
+// RetentionConfig holds retention worker configuration
+type RetentionConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Interval     time.Duration `yaml:"interval"`
+	OrphanPolicy string        `yaml:"orphan_policy"`
+	BatchSize    int           `yaml:"batch_size"`
+}
+
+// ExpiryConfig holds the worker that drops players inactive longer than their
+// board's inactivity_days
+type ExpiryConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+}
+
+// StalenessConfig holds the worker that flags leaderboards with WebSocket
+// subscribers but no applied submission for a while, which usually means the
+// ingest pipeline feeding them broke
+type StalenessConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+	// After is how long a watched board may go without a write
+	After time.Duration `yaml:"after"`
+	// Leaderboards overrides After per board, e.g. for boards fed hourly
+	Leaderboards map[string]time.Duration `yaml:"leaderboards"`
+	// MinSubscribers is how many subscribers make a board watched
+	MinSubscribers int `yaml:"min_subscribers"`
+
+	Webhook StalenessWebhookConfig `yaml:"webhook"`
+}
+
+// StalenessWebhookConfig holds the endpoint notified when a board goes stale
+// and when it recovers; an empty URL only logs and counts alerts
+type StalenessWebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout time.Duration     `yaml:"timeout"`
+}
+
+// StaleAfter returns how long a board may go without a write before it is flagged
+func (c *StalenessConfig) StaleAfter(leaderboardID string) time.Duration {
+	if after, ok := c.Leaderboards[leaderboardID]; ok {
+		return after
+	}
+	return c.After
+}
+
+// PublishConfig holds the worker that renders leaderboard standings to static
+// JSON files in a blob store, so public reads can be served by a CDN
+type PublishConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+	// Leaderboards lists the boards to publish; empty publishes every board
+	Leaderboards []string `yaml:"leaderboards"`
+	TopN         int      `yaml:"top_n"`
+	// CacheMaxAge is the max-age of the Cache-Control header sent with each
+	// file; it defaults to the interval
+	CacheMaxAge time.Duration   `yaml:"cache_max_age"`
+	Store       BlobStoreConfig `yaml:"store"`
+}
+
+// Blob store types
+const (
+	BlobStoreS3  = "s3"
+	BlobStoreDir = "dir"
+)
+
+// BlobStoreConfig selects where published files are written
+type BlobStoreConfig struct {
+	Type string `yaml:"type"`
+	// Prefix is put in front of every object key, e.g. public/
+	Prefix string `yaml:"prefix"`
+
+	// Dir is the root directory of a dir store
+	Dir string `yaml:"dir"`
+
+	// Bucket and Region locate an s3 store. Endpoint overrides the AWS endpoint
+	// for S3-compatible services and switches to path-style URLs. Empty
+	// credentials fall back to the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+	// AWS_SESSION_TOKEN environment variables.
+	Bucket          string        `yaml:"bucket"`
+	Region          string        `yaml:"region"`
+	Endpoint        string        `yaml:"endpoint"`
+	AccessKeyID     string        `yaml:"access_key_id"`
+	SecretAccessKey string        `yaml:"secret_access_key"`
+	SessionToken    string        `yaml:"session_token"`
+	Timeout         time.Duration `yaml:"timeout"`
+}
+
+// CDN purge providers
+const (
+	PurgeProviderWebhook    = "webhook"
+	PurgeProviderFastly     = "fastly"
+	PurgeProviderCloudFront = "cloudfront"
+)
+
+// PurgeConfig holds the hooks that purge CDN copies of a leaderboard when its
+// top entries change or it is reset
+type PurgeConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Provider string `yaml:"provider"`
+	// Paths are purged for every changed board, with {id} replaced by the
+	// leaderboard ID. Fastly purges them as surrogate keys.
+	Paths []string `yaml:"paths"`
+	// TopN is how deep in the standings a score change must reach to purge
+	TopN int `yaml:"top_n"`
+	// Interval is the minimum time between purge requests; boards changed in
+	// between are collected into the next request
+	Interval time.Duration `yaml:"interval"`
+	// MaxBatch caps the paths sent in one request; the rest wait for the next
+	MaxBatch int           `yaml:"max_batch"`
+	Timeout  time.Duration `yaml:"timeout"`
+
+	Webhook    PurgeWebhookConfig `yaml:"webhook"`
+	Fastly     FastlyConfig       `yaml:"fastly"`
+	CloudFront CloudFrontConfig   `yaml:"cloudfront"`
+}
+
+// PurgeWebhookConfig holds the endpoint the webhook provider posts paths to
+type PurgeWebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// FastlyConfig holds the Fastly service whose surrogate keys are purged
+type FastlyConfig struct {
+	ServiceID string `yaml:"service_id"`
+	APIToken  string `yaml:"api_token"`
+	// SoftPurge marks content stale instead of evicting it
+	SoftPurge bool `yaml:"soft_purge"`
+}
+
+// CloudFrontConfig holds the distribution invalidated by the cloudfront
+// provider. Empty credentials fall back to the AWS environment variables.
+type CloudFrontConfig struct {
+	DistributionID  string `yaml:"distribution_id"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	SessionToken    string `yaml:"session_token"`
+}
+
+// Log output formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// LoggingConfig holds structured logging configuration
+type LoggingConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	// Output is "stdout", "stderr", or a file path
+	Output string `yaml:"output"`
+	// Modules overrides the level per module, e.g. websocket: debug
+	Modules map[string]string `yaml:"modules"`
+}
+
+// Error reporter providers
+const (
+	ErrorReporterLog    = "log"
+	ErrorReporterSentry = "sentry"
+)
+
+// ErrorReportingConfig holds panic and error reporting configuration
+type ErrorReportingConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Provider    string `yaml:"provider"`
+	DSN         string `yaml:"dsn"`
+	Environment string `yaml:"environment"`
+	Release     string `yaml:"release"`
+}
+
+// ChaosConfig controls the fault injection endpoints used for resilience testing
+type ChaosConfig struct {
+	// Enabled exposes /api/v1/admin/chaos; never enable in production
+	Enabled bool `yaml:"enabled"`
+}
+
+// FeaturesConfig holds the rollout rules of feature flags. Rules set through
+// /api/v1/admin/features are kept in Redis, override these, and are picked up
+// by every instance within RefreshInterval.
+type FeaturesConfig struct {
+	RefreshInterval time.Duration          `yaml:"refresh_interval"`
+	Flags           map[string]FeatureRule `yaml:"flags"`
+}
+
+// FeatureRule decides for which leaderboards a flag is on: everywhere when
+// Enabled, otherwise on the listed tenants and leaderboards and on Percent of
+// the remaining leaderboards, picked by a hash of the leaderboard ID. Exclude
+// turns the flag off on leaderboards regardless of the rest.
+type FeatureRule struct {
+	Enabled      bool     `yaml:"enabled" json:"enabled"`
+	Percent      int      `yaml:"percent" json:"percent"`
+	Tenants      []string `yaml:"tenants" json:"tenants,omitempty"`
+	Leaderboards []string `yaml:"leaderboards" json:"leaderboards,omitempty"`
+	Exclude      []string `yaml:"exclude" json:"exclude,omitempty"`
+}
+
+// WebSocketConfig holds WebSocket channel configuration
+type WebSocketConfig struct {
+	Global   GlobalChannelConfig    `yaml:"global"`
+	Limits   ConnectionLimitsConfig `yaml:"limits"`
+	Coalesce CoalesceConfig         `yaml:"coalesce"`
+}
+
+// CoalesceConfig caps how often the hub sends leaderboard updates per board.
+// Updates arriving faster are held and only the latest is sent, so v2 deltas
+// span everything that changed in between. A zero rate sends every update.
+type CoalesceConfig struct {
+	MaxUpdatesPerSecond float64 `yaml:"max_updates_per_second"`
+	// Leaderboards overrides the rate for single boards; 0 sends every update
+	Leaderboards map[string]float64 `yaml:"leaderboards"`
+}
+
+// Enabled reports whether any board's updates are coalesced
+func (c CoalesceConfig) Enabled() bool {
+	if c.MaxUpdatesPerSecond > 0 {
+		return true
+	}
+	for _, rate := range c.Leaderboards {
+		if rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Interval returns the minimum time between updates of a board, 0 when uncapped
+func (c CoalesceConfig) Interval(leaderboardID string) time.Duration {
+	rate, ok := c.Leaderboards[leaderboardID]
+	if !ok {
+		rate = c.MaxUpdatesPerSecond
+	}
+	if rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// ConnectionLimitsConfig bounds WebSocket connections; a zero limit is not enforced
+type ConnectionLimitsConfig struct {
+	MaxConnections int `yaml:"max_connections"`
+	MaxPerIP       int `yaml:"max_per_ip"`
+	// HandshakeRate is the sustained upgrades per second allowed from one IP,
+	// with bursts of up to HandshakeBurst
+	HandshakeRate  float64 `yaml:"handshake_rate"`
+	HandshakeBurst int     `yaml:"handshake_burst"`
+	// TrustForwardedFor keys per-IP limits on X-Forwarded-For; enable only behind a proxy
+	TrustForwardedFor bool `yaml:"trust_forwarded_for"`
+}
+
+// Enabled reports whether any connection limit is set
+func (c ConnectionLimitsConfig) Enabled() bool {
+	return c.MaxConnections > 0 || c.MaxPerIP > 0 || c.HandshakeRate > 0
+}
+
+// GlobalChannelConfig controls the service-wide "global" channel
+type GlobalChannelConfig struct {
+	// Events lists the event types forwarded to the channel: record, top_entry,
+	// reset, leaderboard_created, window_open, window_close
+	Events []string `yaml:"events"`
+	// WindowCheckInterval is how often submission windows are checked for opening and closing
+	WindowCheckInterval time.Duration `yaml:"window_check_interval"`
+}
+
+// Forwards reports whether events of the given type are sent to the global channel
+func (g GlobalChannelConfig) Forwards(eventType string) bool {
+	for _, t := range g.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ResetConfig holds the periodic leaderboard reset scheduler configuration
+type ResetConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	CheckInterval time.Duration `yaml:"check_interval"`
+	// Period boundaries: tz database name, first day of the week, and HH:MM local time
+	Timezone  string `yaml:"timezone"`
+	WeekStart string `yaml:"week_start"`
+	TimeOfDay string `yaml:"time_of_day"`
+}
+
+// IngestConfig holds the CSV score file ingestion worker configuration
+type IngestConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Dir          string        `yaml:"dir"`
+	ArchiveDir   string        `yaml:"archive_dir"`
+	Pattern      string        `yaml:"pattern"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// SettleTime skips files modified more recently than this, so partial uploads are not read
+	SettleTime time.Duration `yaml:"settle_time"`
+	BatchSize  int           `yaml:"batch_size"`
+}
+
+// MigrationConfig holds the tools used while moving off a legacy leaderboard
+// service: mirroring submissions to it, and comparing its standings with ours
+type MigrationConfig struct {
+	DualWrite DualWriteConfig `yaml:"dual_write"`
+	Compare   CompareConfig   `yaml:"compare"`
+}
+
+// DualWriteConfig forwards every accepted submission, as received, to the
+// legacy service. Forwarding is queued and never slows the write path.
+type DualWriteConfig struct {
+	Enabled   bool              `yaml:"enabled"`
+	URL       string            `yaml:"url"`
+	Headers   map[string]string `yaml:"headers"`
+	Timeout   time.Duration     `yaml:"timeout"`
+	QueueSize int               `yaml:"queue_size"`
+	Workers   int               `yaml:"workers"`
+}
+
+// CompareConfig periodically diffs each leaderboard's top entries with the
+// legacy service's and reports mismatches
+type CompareConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// URL is the legacy top-N endpoint, with {id} and {n} replaced by the
+	// leaderboard ID and TopN
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	// EntriesPath is the dotted path to the entry array in the legacy
+	// response, "data" by default; "." means the response is the array
+	EntriesPath string `yaml:"entries_path"`
+	PlayerField string `yaml:"player_field"`
+	ScoreField  string `yaml:"score_field"`
+	// Leaderboards lists the boards to compare; empty compares every board
+	Leaderboards []string      `yaml:"leaderboards"`
+	TopN         int           `yaml:"top_n"`
+	Interval     time.Duration `yaml:"interval"`
+	Timeout      time.Duration `yaml:"timeout"`
+	// MaxMismatches caps the mismatches kept in each board's report
+	MaxMismatches int `yaml:"max_mismatches"`
+}
+
+// Replication modes
+const (
+	ReplicationOff     = "off"
+	ReplicationPrimary = "primary"
+	ReplicationReplica = "replica"
+)
+
+// Replication transports
+const (
+	ReplicationTransportKafka = "kafka"
+	ReplicationTransportHTTP  = "http"
+)
+
+// ReplicationConfig holds cross-region score replication configuration
+type ReplicationConfig struct {
+	// Mode is off, primary (publish applied changes) or replica (apply only, reject local writes)
+	Mode      string `yaml:"mode"`
+	Region    string `yaml:"region"`
+	Transport string `yaml:"transport"`
+	// Brokers and Topic are used by the kafka transport; Brokers defaults to kafka.brokers
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+	GroupID string   `yaml:"group_id"`
+	// Endpoint is the replica's apply URL for the http transport
+	Endpoint string `yaml:"endpoint"`
+	// Token is a shared secret sent by the primary and required by the replica's apply endpoint
+	Token         string        `yaml:"token"`
+	BatchSize     int           `yaml:"batch_size"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	QueueSize     int           `yaml:"queue_size"`
 }
 
 // Load reads configuration from a YAML file
@@ -125,6 +893,9 @@ func (c *Config) applyDefaults() {
 	if c.Server.IdleTimeout == 0 {
 		c.Server.IdleTimeout = 120 * time.Second
 	}
+	if c.Server.RequestBody.MaxDecompressedBytes == 0 {
+		c.Server.RequestBody.MaxDecompressedBytes = 32 << 20
+	}
 
 	// Redis defaults
 	if c.Redis.Addr == "" {
@@ -145,6 +916,21 @@ func (c *Config) applyDefaults() {
 	if c.Redis.WriteTimeout == 0 {
 		c.Redis.WriteTimeout = 3 * time.Second
 	}
+	if c.Redis.Cache.PlayerInfoTTL == 0 {
+		c.Redis.Cache.PlayerInfoTTL = 7 * 24 * time.Hour
+	}
+	if c.Redis.Cache.SweepInterval == 0 {
+		c.Redis.Cache.SweepInterval = 1 * time.Hour
+	}
+	if c.Redis.Cache.SweepBatch == 0 {
+		c.Redis.Cache.SweepBatch = 1000
+	}
+	if c.Redis.Cache.PageSize == 0 {
+		c.Redis.Cache.PageSize = 1000
+	}
+	if c.Redis.Cache.PageTTL == 0 {
+		c.Redis.Cache.PageTTL = 10 * time.Second
+	}
 
 	// PostgreSQL defaults
 	if c.Postgres.Host == "" {
@@ -188,6 +974,27 @@ func (c *Config) applyDefaults() {
 	if c.Kafka.RetryDelay == 0 {
 		c.Kafka.RetryDelay = 1 * time.Second
 	}
+	if c.Kafka.Workers == 0 {
+		c.Kafka.Workers = 1
+	}
+	if c.Kafka.InitialOffset == "" {
+		c.Kafka.InitialOffset = KafkaOffsetNewest
+	}
+	if c.Kafka.CommitMode == "" {
+		c.Kafka.CommitMode = KafkaCommitAuto
+	}
+	if c.Kafka.CommitInterval == 0 {
+		c.Kafka.CommitInterval = 1 * time.Second
+	}
+	if c.Kafka.Partitions == 0 {
+		c.Kafka.Partitions = 3
+	}
+	if c.Kafka.ReplicationFactor == 0 {
+		c.Kafka.ReplicationFactor = 1
+	}
+	if c.Kafka.DeadLetter.Topic == "" {
+		c.Kafka.DeadLetter.Topic = c.Kafka.Topic + "-dlq"
+	}
 
 	// Sync defaults
 	if c.Sync.Interval == 0 {
@@ -196,6 +1003,12 @@ func (c *Config) applyDefaults() {
 	if c.Sync.BatchSize == 0 {
 		c.Sync.BatchSize = 1000
 	}
+	if c.Sync.Concurrency == 0 {
+		c.Sync.Concurrency = 1
+	}
+	if c.Sync.ConflictPolicy == "" {
+		c.Sync.ConflictPolicy = SyncConflictNewerWins
+	}
 
 	// Leaderboard defaults
 	if c.Leaderboard.DefaultLimit == 0 {
@@ -204,6 +1017,293 @@ func (c *Config) applyDefaults() {
 	if c.Leaderboard.MaxLimit == 0 {
 		c.Leaderboard.MaxLimit = 1000
 	}
+	if c.Leaderboard.VersionWaitTimeout == 0 {
+		c.Leaderboard.VersionWaitTimeout = 2 * time.Second
+	}
+	if c.Leaderboard.StatsCacheTTL == 0 {
+		c.Leaderboard.StatsCacheTTL = 5 * time.Second
+	}
+	if c.Leaderboard.StatsSampleSize == 0 {
+		c.Leaderboard.StatsSampleSize = 10000
+	}
+	if c.Leaderboard.Feed.MaxLength == 0 {
+		c.Leaderboard.Feed.MaxLength = 1000
+	}
+	if c.Leaderboard.Feed.TopN == 0 {
+		c.Leaderboard.Feed.TopN = 10
+	}
+	if c.Leaderboard.DedupTTL == 0 {
+		c.Leaderboard.DedupTTL = 24 * time.Hour
+	}
+	if c.Leaderboard.OverviewCacheTTL == 0 {
+		c.Leaderboard.OverviewCacheTTL = 5 * time.Second
+	}
+	if c.Leaderboard.DerivedRefresh == 0 {
+		c.Leaderboard.DerivedRefresh = 10 * time.Second
+	}
+	if c.Leaderboard.Scripts.Timeout == 0 {
+		c.Leaderboard.Scripts.Timeout = 10 * time.Millisecond
+	}
+	if c.Leaderboard.Scripts.MaxSourceBytes == 0 {
+		c.Leaderboard.Scripts.MaxSourceBytes = 16 << 10
+	}
+	if c.Leaderboard.Scripts.CallStackSize == 0 {
+		c.Leaderboard.Scripts.CallStackSize = 64
+	}
+	if c.Leaderboard.Scripts.RegistryMaxSize == 0 {
+		c.Leaderboard.Scripts.RegistryMaxSize = 64 << 10
+	}
+	if c.Leaderboard.Scripts.MaxInstructions == 0 {
+		c.Leaderboard.Scripts.MaxInstructions = 100000
+	}
+	if c.Leaderboard.Scripts.MaxAllocBytes == 0 {
+		c.Leaderboard.Scripts.MaxAllocBytes = 1 << 20
+	}
+	if c.Leaderboard.IDs.Policy == "" {
+		c.Leaderboard.IDs.Policy = IDPolicyFree
+	}
+	if c.Leaderboard.IDs.MaxLength == 0 {
+		c.Leaderboard.IDs.MaxLength = 128
+	}
+
+	// Events defaults
+	if c.Events.Sampling.Mode == "" {
+		c.Events.Sampling.Mode = EventSamplingAll
+	}
+	if c.Events.Sampling.SampleRate == 0 {
+		c.Events.Sampling.SampleRate = 1
+	}
+	if c.Events.Sampling.AggregateWindow == 0 {
+		c.Events.Sampling.AggregateWindow = 1 * time.Minute
+	}
+	if c.Events.Buffer.Size == 0 {
+		c.Events.Buffer.Size = 10000
+	}
+	if c.Events.Buffer.BatchSize == 0 {
+		c.Events.Buffer.BatchSize = 500
+	}
+	if c.Events.Buffer.FlushInterval == 0 {
+		c.Events.Buffer.FlushInterval = 1 * time.Second
+	}
+
+	// Retention defaults
+	if c.Retention.Interval == 0 {
+		c.Retention.Interval = 1 * time.Hour
+	}
+	if c.Retention.OrphanPolicy == "" {
+		c.Retention.OrphanPolicy = OrphanPolicyKeep
+	}
+	if c.Retention.BatchSize == 0 {
+		c.Retention.BatchSize = 10000
+	}
+
+	// Expiry defaults
+	if c.Expiry.Interval == 0 {
+		c.Expiry.Interval = 1 * time.Hour
+	}
+	if c.Expiry.BatchSize == 0 {
+		c.Expiry.BatchSize = 1000
+	}
+
+	// Staleness defaults
+	if c.Staleness.Interval == 0 {
+		c.Staleness.Interval = 1 * time.Minute
+	}
+	if c.Staleness.After == 0 {
+		c.Staleness.After = 10 * time.Minute
+	}
+	if c.Staleness.MinSubscribers == 0 {
+		c.Staleness.MinSubscribers = 1
+	}
+	if c.Staleness.Webhook.Timeout == 0 {
+		c.Staleness.Webhook.Timeout = 10 * time.Second
+	}
+
+	// Feature flag defaults
+	if c.Features.RefreshInterval == 0 {
+		c.Features.RefreshInterval = 10 * time.Second
+	}
+
+	// Publish defaults
+	if c.Publish.Interval == 0 {
+		c.Publish.Interval = 30 * time.Second
+	}
+	if c.Publish.TopN == 0 {
+		c.Publish.TopN = 100
+	}
+	if c.Publish.CacheMaxAge == 0 {
+		c.Publish.CacheMaxAge = c.Publish.Interval
+	}
+	if c.Publish.Store.Type == "" {
+		c.Publish.Store.Type = BlobStoreS3
+	}
+	if c.Publish.Store.Timeout == 0 {
+		c.Publish.Store.Timeout = 10 * time.Second
+	}
+
+	// Rate limit defaults
+	if c.RateLimit.Window == 0 {
+		c.RateLimit.Window = time.Minute
+	}
+	if c.RateLimit.PerPlayer == 0 {
+		c.RateLimit.PerPlayer = 60
+	}
+	if c.RateLimit.PerAPIKey == 0 {
+		c.RateLimit.PerAPIKey = 6000
+	}
+	if c.RateLimit.Reads == 0 {
+		c.RateLimit.Reads = 1200
+	}
+
+	// Auth defaults
+	if c.Auth.JWKSRefresh == 0 {
+		c.Auth.JWKSRefresh = time.Hour
+	}
+	if c.Auth.PlayerClaim == "" {
+		c.Auth.PlayerClaim = "sub"
+	}
+	if c.Auth.Leeway == 0 {
+		c.Auth.Leeway = 30 * time.Second
+	}
+
+	// Purge defaults
+	if c.Purge.Provider == "" {
+		c.Purge.Provider = PurgeProviderWebhook
+	}
+	if len(c.Purge.Paths) == 0 {
+		c.Purge.Paths = []string{"/api/v1/leaderboards/{id}/*"}
+	}
+	if c.Purge.TopN == 0 {
+		c.Purge.TopN = 100
+	}
+	if c.Purge.Interval == 0 {
+		c.Purge.Interval = 5 * time.Second
+	}
+	if c.Purge.MaxBatch == 0 {
+		c.Purge.MaxBatch = 100
+	}
+	if c.Purge.Timeout == 0 {
+		c.Purge.Timeout = 10 * time.Second
+	}
+
+	// Migration defaults
+	if c.Migration.DualWrite.Timeout == 0 {
+		c.Migration.DualWrite.Timeout = 5 * time.Second
+	}
+	if c.Migration.DualWrite.QueueSize == 0 {
+		c.Migration.DualWrite.QueueSize = 10000
+	}
+	if c.Migration.DualWrite.Workers == 0 {
+		c.Migration.DualWrite.Workers = 4
+	}
+	if c.Migration.Compare.EntriesPath == "" {
+		c.Migration.Compare.EntriesPath = "data"
+	}
+	if c.Migration.Compare.PlayerField == "" {
+		c.Migration.Compare.PlayerField = "player_id"
+	}
+	if c.Migration.Compare.ScoreField == "" {
+		c.Migration.Compare.ScoreField = "score"
+	}
+	if c.Migration.Compare.TopN == 0 {
+		c.Migration.Compare.TopN = 100
+	}
+	if c.Migration.Compare.Interval == 0 {
+		c.Migration.Compare.Interval = 10 * time.Minute
+	}
+	if c.Migration.Compare.Timeout == 0 {
+		c.Migration.Compare.Timeout = 10 * time.Second
+	}
+	if c.Migration.Compare.MaxMismatches == 0 {
+		c.Migration.Compare.MaxMismatches = 100
+	}
+
+	// Logging defaults
+	if c.Logging.Level == "" {
+		c.Logging.Level = "info"
+	}
+	if c.Logging.Format == "" {
+		c.Logging.Format = LogFormatJSON
+	}
+	if c.Logging.Output == "" {
+		c.Logging.Output = "stdout"
+	}
+
+	// WebSocket defaults; an explicit empty list turns the global channel off
+	if c.WebSocket.Global.Events == nil {
+		c.WebSocket.Global.Events = []string{"record", "leaderboard_created", "window_open"}
+	}
+	if c.WebSocket.Global.WindowCheckInterval == 0 {
+		c.WebSocket.Global.WindowCheckInterval = 30 * time.Second
+	}
+	if c.WebSocket.Limits.HandshakeRate > 0 && c.WebSocket.Limits.HandshakeBurst == 0 {
+		c.WebSocket.Limits.HandshakeBurst = int(math.Ceil(c.WebSocket.Limits.HandshakeRate))
+	}
+
+	// Reset scheduler defaults
+	if c.Reset.CheckInterval == 0 {
+		c.Reset.CheckInterval = 1 * time.Minute
+	}
+	if c.Reset.Timezone == "" {
+		c.Reset.Timezone = "UTC"
+	}
+	if c.Reset.WeekStart == "" {
+		c.Reset.WeekStart = "monday"
+	}
+	if c.Reset.TimeOfDay == "" {
+		c.Reset.TimeOfDay = "00:00"
+	}
+
+	// Ingest defaults
+	if c.Ingest.Dir == "" {
+		c.Ingest.Dir = "ingest/incoming"
+	}
+	if c.Ingest.ArchiveDir == "" {
+		c.Ingest.ArchiveDir = "ingest/archive"
+	}
+	if c.Ingest.Pattern == "" {
+		c.Ingest.Pattern = "*.csv"
+	}
+	if c.Ingest.PollInterval == 0 {
+		c.Ingest.PollInterval = 1 * time.Minute
+	}
+	if c.Ingest.SettleTime == 0 {
+		c.Ingest.SettleTime = 30 * time.Second
+	}
+	if c.Ingest.BatchSize == 0 {
+		c.Ingest.BatchSize = 500
+	}
+
+	// Replication defaults
+	if c.Replication.Mode == "" {
+		c.Replication.Mode = ReplicationOff
+	}
+	if c.Replication.Transport == "" {
+		c.Replication.Transport = ReplicationTransportKafka
+	}
+	if len(c.Replication.Brokers) == 0 {
+		c.Replication.Brokers = c.Kafka.Brokers
+	}
+	if c.Replication.Topic == "" {
+		c.Replication.Topic = "leaderboard-replication"
+	}
+	if c.Replication.GroupID == "" {
+		c.Replication.GroupID = "leaderboard-replica"
+	}
+	if c.Replication.BatchSize == 0 {
+		c.Replication.BatchSize = 500
+	}
+	if c.Replication.FlushInterval == 0 {
+		c.Replication.FlushInterval = 200 * time.Millisecond
+	}
+	if c.Replication.QueueSize == 0 {
+		c.Replication.QueueSize = 100000
+	}
+
+	// Error reporting defaults
+	if c.Errors.Provider == "" {
+		c.Errors.Provider = ErrorReporterLog
+	}
 }
 
 // DefaultConfig returns a configuration with all defaults
@@ -211,6 +1311,8 @@ func DefaultConfig() *Config {
 	cfg := &Config{}
 	cfg.applyDefaults()
 	cfg.Sync.Enabled = true
+	cfg.Server.AccessLog.Enabled = true
+	cfg.Reset.Enabled = true
 	return cfg
 }
 
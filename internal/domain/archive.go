@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Season name layouts, in the local time of the board's reset schedule
+const (
+	seasonDayLayout   = "2006-01-02"
+	seasonMonthLayout = "2006-01"
+	// seasonResetLayout names seasons of boards without a period after the reset time
+	seasonResetLayout = "20060102T150405Z"
+)
+
+// SeasonArchive is the final standings of one leaderboard season, stored when
+// the board is reset. Archives outlive the leaderboard itself.
+type SeasonArchive struct {
+	ID            int64       `json:"id"`
+	LeaderboardID string      `json:"leaderboard_id"`
+	Season        string      `json:"season"`
+	ResetPeriod   ResetPeriod `json:"reset_period"`
+	PeriodStart   *time.Time  `json:"period_start,omitempty"`
+	Version       int64       `json:"version"`
+	PlayerCount   int64       `json:"player_count"`
+	ArchivedAt    time.Time   `json:"archived_at"`
+
+	// Entries are only loaded when a single season is requested
+	Entries []LeaderboardEntry `json:"entries,omitempty"`
+}
+
+// SeasonName identifies the season a leaderboard's current scores belong to.
+// Periodic boards are named after the local start of the period, 2006-01-02
+// for daily and weekly boards and 2006-01 for monthly ones; other boards after
+// the reset time. periodStart is zero for boards that never reset.
+func (s ResetSchedule) SeasonName(p ResetPeriod, periodStart, resetAt time.Time) string {
+	if periodStart.IsZero() {
+		return resetAt.UTC().Format(seasonResetLayout)
+	}
+	local := periodStart.In(s.location())
+	if p == ResetPeriodMonthly {
+		return local.Format(seasonMonthLayout)
+	}
+	return local.Format(seasonDayLayout)
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// Bulk delete limits
+const (
+	MaxBulkDeleteIDs     = 100000
+	BulkDeletePreviewIDs = 100
+)
+
+// BulkDeleteStatus is the state of a bulk delete job
+type BulkDeleteStatus string
+
+const (
+	BulkDeleteRunning   BulkDeleteStatus = "running"
+	BulkDeleteCompleted BulkDeleteStatus = "completed"
+	BulkDeleteFailed    BulkDeleteStatus = "failed"
+)
+
+// BulkDeleteFilter selects players by their standing. Set conditions must all
+// hold for a player to match.
+type BulkDeleteFilter struct {
+	// ScoreAbove matches players whose score is strictly greater
+	ScoreAbove *int64 `json:"score_above,omitempty"`
+	// Flagged matches players with a suspicious score event on the board,
+	// recorded when anti-cheat bounds clamped one of their submissions
+	Flagged bool `json:"flagged,omitempty"`
+}
+
+// BulkDeleteRequest removes many players from a leaderboard, named either by
+// PlayerIDs or by Filter. A dry run only reports what would be removed.
+type BulkDeleteRequest struct {
+	PlayerIDs []string          `json:"player_ids,omitempty"`
+	Filter    *BulkDeleteFilter `json:"filter,omitempty"`
+	DryRun    bool              `json:"dry_run,omitempty"`
+}
+
+// Validate checks that the request names players one way or the other
+func (r *BulkDeleteRequest) Validate() error {
+	switch {
+	case len(r.PlayerIDs) > 0 && r.Filter != nil:
+		return NewValidationError(ErrInvalidRequest, "filter", "cannot be combined with player_ids")
+	case len(r.PlayerIDs) > MaxBulkDeleteIDs:
+		return NewValidationError(ErrInvalidRequest, "player_ids", "has too many entries")
+	case len(r.PlayerIDs) > 0:
+		return nil
+	case r.Filter == nil:
+		return NewValidationError(ErrInvalidRequest, "player_ids", "or filter is required")
+	case r.Filter.ScoreAbove == nil && !r.Filter.Flagged:
+		return NewValidationError(ErrInvalidRequest, "filter", "needs score_above or flagged")
+	}
+	return nil
+}
+
+// BulkDeletePreview is what a dry run would remove. Sample holds up to
+// BulkDeletePreviewIDs of the matched players.
+type BulkDeletePreview struct {
+	LeaderboardID string   `json:"leaderboard_id"`
+	Matched       int      `json:"matched"`
+	Sample        []string `json:"sample"`
+}
+
+// BulkDeleteJob tracks a bulk delete running in the background. Matched is
+// set once the players are resolved; Removed grows as batches are deleted.
+type BulkDeleteJob struct {
+	ID            string            `json:"id"`
+	LeaderboardID string            `json:"leaderboard_id"`
+	Request       BulkDeleteRequest `json:"-"`
+	Status        BulkDeleteStatus  `json:"status"`
+	Matched       int               `json:"matched"`
+	Removed       int               `json:"removed"`
+	Error         string            `json:"error,omitempty"`
+	StartedAt     time.Time         `json:"started_at"`
+	FinishedAt    *time.Time        `json:"finished_at,omitempty"`
+}
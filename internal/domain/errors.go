@@ -1,6 +1,11 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Domain errors
 var (
@@ -12,10 +17,176 @@ var (
 	ErrRateLimited         = errors.New("rate limit exceeded")
 	ErrInvalidRequest      = errors.New("invalid request")
 	ErrInternalError       = errors.New("internal server error")
+	ErrReadOnlyReplica     = errors.New("writes are not accepted by a replica region")
+	ErrVersionNotVisible   = errors.New("requested leaderboard version is not visible yet")
+	ErrSubmissionWindow    = errors.New("leaderboard is not accepting submissions")
+	ErrGhostNotFound       = errors.New("ghost entry not found")
+	ErrPendingNotFound     = errors.New("pending score not found")
+	ErrAlreadyReviewed     = errors.New("pending score has already been reviewed")
+	ErrScriptNotFound      = errors.New("scoring script version not found")
+	ErrSnapshotNotFound    = errors.New("snapshot not found")
+	ErrSnapshotExists      = errors.New("snapshot already exists")
+	ErrSeasonNotFound      = errors.New("season archive not found")
+	ErrSeasonExists        = errors.New("season archive already exists")
+	ErrSeedingNotFound     = errors.New("seeding not found")
+	ErrSeedingExists       = errors.New("seeding already exists")
+	ErrDivisionNotFound    = errors.New("division not found")
+	ErrProfileNotFound     = errors.New("player profile not found")
+	ErrProfileExists       = errors.New("player profile already exists")
+	ErrUnauthorized        = errors.New("missing or invalid bearer token")
+	ErrPlayerMismatch      = errors.New("token does not belong to the submitted player")
+	ErrQuotaExceeded       = errors.New("attempt quota used up for this period")
+	ErrJobNotFound         = errors.New("bulk delete job not found")
+	ErrNoPlayerClaim       = errors.New("token does not name a player")
+	ErrAdminScopeRequired  = errors.New("token lacks the admin scope needed to act on behalf of a player")
+)
+
+// SubmissionWindowError reports a submission outside a leaderboard's window.
+// OpensAt is set when the window has not opened yet, ClosedAt once it has closed.
+type SubmissionWindowError struct {
+	LeaderboardID string     `json:"leaderboard_id"`
+	OpensAt       *time.Time `json:"opens_at,omitempty"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+}
+
+func (e *SubmissionWindowError) Error() string {
+	if e.OpensAt != nil {
+		return fmt.Sprintf("%s: opens at %s", ErrSubmissionWindow, e.OpensAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s: closed at %s", ErrSubmissionWindow, e.ClosedAt.Format(time.RFC3339))
+}
+
+// Is lets errors.Is match SubmissionWindowError against ErrSubmissionWindow
+func (e *SubmissionWindowError) Is(target error) bool {
+	return target == ErrSubmissionWindow
+}
+
+// QuotaExceededError reports a submission beyond a player's attempt quota.
+// ResetsAt is when the quota renews; nil for a quota that never does.
+type QuotaExceededError struct {
+	LeaderboardID string     `json:"leaderboard_id"`
+	Quota         int        `json:"quota"`
+	ResetsAt      *time.Time `json:"resets_at,omitempty"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	if e.ResetsAt == nil {
+		return fmt.Sprintf("%s: %d attempts", ErrQuotaExceeded, e.Quota)
+	}
+	return fmt.Sprintf("%s: %d attempts, renews at %s", ErrQuotaExceeded, e.Quota, e.ResetsAt.Format(time.RFC3339))
+}
+
+// Is lets errors.Is match QuotaExceededError against ErrQuotaExceeded
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Resources named by NotFoundError
+const (
+	ResourceLeaderboard = "leaderboard"
+	ResourcePlayer      = "player"
+	ResourceGhost       = "ghost"
+	ResourcePending     = "pending"
+	ResourceScript      = "script"
+	ResourceSnapshot    = "snapshot"
+	ResourceSeason      = "season"
+	ResourceSeeding     = "seeding"
+	ResourceDivision    = "division"
+	ResourceProfile     = "profile"
+	ResourceJob         = "job"
 )
 
-// IsNotFoundError checks if an error is a not-found type error
+// notFoundErrors are the sentinels each resource's NotFoundError matches
+var notFoundErrors = map[string]error{
+	ResourceLeaderboard: ErrLeaderboardNotFound,
+	ResourcePlayer:      ErrPlayerNotFound,
+	ResourceGhost:       ErrGhostNotFound,
+	ResourcePending:     ErrPendingNotFound,
+	ResourceScript:      ErrScriptNotFound,
+	ResourceSnapshot:    ErrSnapshotNotFound,
+	ResourceSeason:      ErrSeasonNotFound,
+	ResourceSeeding:     ErrSeedingNotFound,
+	ResourceDivision:    ErrDivisionNotFound,
+	ResourceProfile:     ErrProfileNotFound,
+	ResourceJob:         ErrJobNotFound,
+}
+
+// NotFoundError reports a missing resource and which one was asked for.
+// errors.Is matches it against the resource's sentinel, such as
+// ErrLeaderboardNotFound, so callers may check either.
+type NotFoundError struct {
+	Resource string `json:"resource"`
+	ID       string `json:"id,omitempty"`
+}
+
+// NewNotFoundError returns a NotFoundError for a resource and its ID
+func NewNotFoundError(resource, id string) *NotFoundError {
+	return &NotFoundError{Resource: resource, ID: id}
+}
+
+func (e *NotFoundError) Error() string {
+	msg := e.Resource + " not found"
+	if sentinel, ok := notFoundErrors[e.Resource]; ok {
+		msg = sentinel.Error()
+	}
+	if e.ID == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, e.ID)
+}
+
+// Is lets errors.Is match NotFoundError against its resource's sentinel
+func (e *NotFoundError) Is(target error) bool {
+	sentinel, ok := notFoundErrors[e.Resource]
+	return ok && target == sentinel
+}
+
+// FieldError is one invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports the fields that made a request invalid. Err is the
+// sentinel it refines, ErrInvalidRequest unless set, so errors.Is still
+// matches ErrInvalidRequest, ErrInvalidScore or ErrInvalidLeaderboard.
+type ValidationError struct {
+	Err    error        `json:"-"`
+	Fields []FieldError `json:"fields"`
+}
+
+// NewValidationError returns a ValidationError refining err for one field
+func NewValidationError(err error, field, message string) *ValidationError {
+	return &ValidationError{Err: err, Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	if len(parts) == 0 {
+		return e.Unwrap().Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Unwrap(), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the sentinel the validation error refines
+func (e *ValidationError) Unwrap() error {
+	if e.Err == nil {
+		return ErrInvalidRequest
+	}
+	return e.Err
+}
+
+// IsNotFoundError checks if an error is a not-found type error: a
+// NotFoundError or any resource's sentinel, however deeply wrapped
 func IsNotFoundError(err error) bool {
-	return errors.Is(err, ErrPlayerNotFound) || errors.Is(err, ErrLeaderboardNotFound)
+	for _, sentinel := range notFoundErrors {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	return false
 }
 
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// wrap nests err under two layers of context, as a repository error
+// surfaces through the service
+func wrap(err error) error {
+	return fmt.Errorf("service: %w", fmt.Errorf("repository: %w", err))
+}
+
+func TestNotFoundErrorWrapping(t *testing.T) {
+	for resource, sentinel := range notFoundErrors {
+		t.Run(resource, func(t *testing.T) {
+			err := wrap(NewNotFoundError(resource, "id-1"))
+
+			if !errors.Is(err, sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, sentinel)
+			}
+			var missing *NotFoundError
+			if !errors.As(err, &missing) {
+				t.Fatalf("errors.As(%v, *NotFoundError) = false", err)
+			}
+			if missing.Resource != resource || missing.ID != "id-1" {
+				t.Errorf("errors.As gave %+v, want resource %q and ID id-1", missing, resource)
+			}
+			if !IsNotFoundError(err) {
+				t.Errorf("IsNotFoundError(%v) = false", err)
+			}
+			if !IsNotFoundError(wrap(sentinel)) {
+				t.Errorf("IsNotFoundError(wrapped %v) = false", sentinel)
+			}
+			for other, otherSentinel := range notFoundErrors {
+				if other != resource && errors.Is(err, otherSentinel) {
+					t.Errorf("errors.Is(%v, %v) = true", err, otherSentinel)
+				}
+			}
+		})
+	}
+
+	if IsNotFoundError(wrap(ErrInvalidRequest)) {
+		t.Error("IsNotFoundError(ErrInvalidRequest) = true")
+	}
+	if IsNotFoundError(NewNotFoundError("unknown", "x")) {
+		t.Error("IsNotFoundError matched a resource without a sentinel")
+	}
+}
+
+func TestValidationErrorWrapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *ValidationError
+		sentinel error
+	}{
+		{"default", &ValidationError{Fields: []FieldError{{Field: "player_id", Message: "required"}}}, ErrInvalidRequest},
+		{"request", NewValidationError(ErrInvalidRequest, "limit", "too large"), ErrInvalidRequest},
+		{"score", NewValidationError(ErrInvalidScore, "score", "out of range"), ErrInvalidScore},
+		{"leaderboard", NewValidationError(ErrInvalidLeaderboard, "sort_order", "must be asc or desc"), ErrInvalidLeaderboard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrap(tt.err)
+
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("errors.As(%v, *ValidationError) = false", err)
+			}
+			if len(invalid.Fields) != 1 || invalid.Fields[0] != tt.err.Fields[0] {
+				t.Errorf("errors.As gave fields %+v, want %+v", invalid.Fields, tt.err.Fields)
+			}
+			if IsNotFoundError(err) {
+				t.Errorf("IsNotFoundError(%v) = true", err)
+			}
+		})
+	}
+}
+
+func TestWindowAndQuotaErrorWrapping(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	window := wrap(&SubmissionWindowError{LeaderboardID: "lb", ClosedAt: &at})
+	if !errors.Is(window, ErrSubmissionWindow) {
+		t.Errorf("errors.Is(%v, ErrSubmissionWindow) = false", window)
+	}
+	var windowErr *SubmissionWindowError
+	if !errors.As(window, &windowErr) || windowErr.LeaderboardID != "lb" {
+		t.Errorf("errors.As(%v, *SubmissionWindowError) failed", window)
+	}
+
+	quota := wrap(&QuotaExceededError{LeaderboardID: "lb", Quota: 3, ResetsAt: &at})
+	if !errors.Is(quota, ErrQuotaExceeded) {
+		t.Errorf("errors.Is(%v, ErrQuotaExceeded) = false", quota)
+	}
+	var quotaErr *QuotaExceededError
+	if !errors.As(quota, &quotaErr) || quotaErr.Quota != 3 {
+		t.Errorf("errors.As(%v, *QuotaExceededError) failed", quota)
+	}
+}
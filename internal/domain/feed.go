@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// FeedEventType identifies a notable leaderboard event
+type FeedEventType string
+
+const (
+	// FeedTopEntry is a player moving into the top ranks from outside them
+	FeedTopEntry FeedEventType = "top_entry"
+	// FeedRecord is a new first-place score
+	FeedRecord FeedEventType = "record"
+	// FeedReset is the board being cleared
+	FeedReset FeedEventType = "reset"
+
+	// Service-wide events that are only carried on the global WebSocket channel
+
+	// FeedLeaderboardCreated is a new leaderboard being created
+	FeedLeaderboardCreated FeedEventType = "leaderboard_created"
+	// FeedWindowOpen is a leaderboard's submission window opening, e.g. a tournament starting
+	FeedWindowOpen FeedEventType = "window_open"
+	// FeedWindowClose is a leaderboard's submission window closing
+	FeedWindowClose FeedEventType = "window_close"
+)
+
+// FeedEvent is one entry of a leaderboard's activity feed. ID is assigned when
+// the event is stored and orders the feed.
+type FeedEvent struct {
+	ID            string        `json:"id,omitempty"`
+	Type          FeedEventType `json:"type"`
+	LeaderboardID string        `json:"leaderboard_id"`
+	PlayerID      string        `json:"player_id,omitempty"`
+	Score         int64         `json:"score,omitempty"`
+	Rank          int64         `json:"rank,omitempty"`
+	PreviousRank  int64         `json:"previous_rank,omitempty"`
+	Timestamp     time.Time     `json:"timestamp"`
+}
+
+// FeedPage is a page of feed events, newest first. NextBefore continues the
+// feed with older events and is empty on the last page.
+type FeedPage struct {
+	Events     []FeedEvent `json:"events"`
+	NextBefore string      `json:"next_before,omitempty"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// GhostIDPrefix namespaces ghost entries so they never collide with real player IDs
+const GhostIDPrefix = "ghost:"
+
+// IsGhostID reports whether a leaderboard member is a ghost entry
+func IsGhostID(playerID string) bool {
+	return strings.HasPrefix(playerID, GhostIDPrefix)
+}
+
+// Ghost is a system-owned leaderboard entry, such as a developer time or an NPC
+// benchmark. Ghosts are ranked like players but left out of stats.
+type Ghost struct {
+	LeaderboardID string    `json:"leaderboard_id"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Score         int64     `json:"score"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreateGhostRequest adds or replaces a ghost entry. The ID is prefixed with
+// GhostIDPrefix when it does not carry it already; the score is stored as given.
+type CreateGhostRequest struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Score int64  `json:"score"`
+}
+
+// GhostID returns the namespaced member ID for the request
+func (r *CreateGhostRequest) GhostID() string {
+	if IsGhostID(r.ID) {
+		return r.ID
+	}
+	return GhostIDPrefix + r.ID
+}
@@ -0,0 +1,8 @@
+package domain
+
+// HistoryPage is a page of a leaderboard's score events. NextCursor continues
+// the listing in the same order and is empty on the last page.
+type HistoryPage struct {
+	Events     []ScoreEvent `json:"events"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"time"
 )
 
@@ -12,6 +15,16 @@ const (
 	SortOrderAsc  SortOrder = "asc"
 )
 
+// QuotaExceeded is what happens to a submission beyond a player's attempt quota
+type QuotaExceeded string
+
+const (
+	// QuotaExceededReject fails the submission with a QuotaExceededError
+	QuotaExceededReject QuotaExceeded = "reject"
+	// QuotaExceededIgnore accepts the submission without applying it
+	QuotaExceededIgnore QuotaExceeded = "ignore"
+)
+
 // ResetPeriod represents how often a leaderboard resets
 type ResetPeriod string
 
@@ -31,6 +44,23 @@ const (
 	UpdateModeBest      UpdateMode = "best"
 )
 
+// Valid reports whether m is a known update mode
+func (m UpdateMode) Valid() bool {
+	return m == UpdateModeReplace || m == UpdateModeIncrement || m == UpdateModeBest
+}
+
+// ScoreRounding controls how transformed scores are rounded back to integers
+type ScoreRounding string
+
+const (
+	ScoreRoundingRound ScoreRounding = "round"
+	ScoreRoundingFloor ScoreRounding = "floor"
+	ScoreRoundingCeil  ScoreRounding = "ceil"
+)
+
+// maxScoreUnitLength bounds the unit label echoed to clients
+const maxScoreUnitLength = 32
+
 // LeaderboardConfig represents the configuration for a leaderboard
 type LeaderboardConfig struct {
 	ID          string      `json:"id"`
@@ -39,8 +69,80 @@ type LeaderboardConfig struct {
 	ResetPeriod ResetPeriod `json:"reset_period"`
 	MaxEntries  int         `json:"max_entries"`
 	UpdateMode  UpdateMode  `json:"update_mode"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	ShadowID    string      `json:"shadow_id,omitempty"`
+
+	// TieBreak orders players with equal scores; it cannot change after creation
+	TieBreak TieBreak `json:"tie_break,omitempty"`
+
+	// A derived board receives every submission to ParentID that matches
+	// Filter, e.g. metadata.map == 'dust'. An empty filter matches everything.
+	ParentID string `json:"parent_id,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+
+	// Broadcast significance: 0 disables each check
+	UpdateThrottleMs int64 `json:"update_throttle_ms,omitempty"`
+	MinRankChange    int64 `json:"min_rank_change,omitempty"`
+	MinScoreChange   int64 `json:"min_score_change,omitempty"`
+
+	// Score transform applied on ingestion: rounding(score * multiplier) + offset.
+	// ScoreUnit is a display label only, e.g. points, ms, meters.
+	ScoreUnit       string        `json:"score_unit,omitempty"`
+	ScoreMultiplier float64       `json:"score_multiplier"`
+	ScoreOffset     int64         `json:"score_offset,omitempty"`
+	ScoreRounding   ScoreRounding `json:"score_rounding"`
+
+	// ScoreFormula derives the submitted score from its metadata before the
+	// transform above, e.g. kills*100 + assists*50; score is the raw submitted score
+	ScoreFormula string `json:"score_formula,omitempty"`
+
+	// ScriptVersion is the active Lua scoring script version; 0 means none.
+	// An active script replaces ScoreFormula.
+	ScriptVersion int `json:"script_version,omitempty"`
+
+	// Accepted range for transformed submissions; nil leaves that side open.
+	// Zero and negative scores are valid unless excluded here.
+	MinScore *int64 `json:"min_score,omitempty"`
+	MaxScore *int64 `json:"max_score,omitempty"`
+
+	// Range an increment board's running total is clamped to; nil leaves that
+	// side open. Clamped submissions are recorded as suspicious events.
+	ClampMin *int64 `json:"clamp_min,omitempty"`
+	ClampMax *int64 `json:"clamp_max,omitempty"`
+
+	// Timezone overrides the server's reset timezone for this board, e.g. Asia/Tokyo
+	Timezone string `json:"timezone,omitempty"`
+
+	// Submission window; nil leaves that side open
+	OpenAt  *time.Time `json:"open_at,omitempty"`
+	CloseAt *time.Time `json:"close_at,omitempty"`
+
+	// MinSubmissions hides players from public rankings until they have submitted this many scores
+	MinSubmissions int64 `json:"min_submissions,omitempty"`
+
+	// InactivityDays drops players who have not submitted for this many days; 0 keeps everyone
+	InactivityDays int `json:"inactivity_days,omitempty"`
+
+	// AttemptQuota caps each player's submissions per QuotaPeriod, e.g. best
+	// of 10 daily attempts; 0 leaves submissions uncapped. QuotaExceeded says
+	// whether submissions beyond it are rejected or silently ignored.
+	AttemptQuota  int           `json:"attempt_quota,omitempty"`
+	QuotaPeriod   ResetPeriod   `json:"quota_period,omitempty"`
+	QuotaExceeded QuotaExceeded `json:"quota_exceeded,omitempty"`
+
+	// ReviewThreshold holds scores that beat it, by sort order, for manual review
+	ReviewThreshold *int64 `json:"review_threshold,omitempty"`
+
+	// SensitiveFields lists top-level metadata keys that are encrypted before
+	// submissions are stored in PostgreSQL
+	SensitiveFields []string `json:"sensitive_fields,omitempty"`
+
+	// LastResetAt is the start of the period the scheduler last reset the board into
+	LastResetAt *time.Time `json:"last_reset_at,omitempty"`
+	// NextResetAt is computed on read and never stored
+	NextResetAt *time.Time `json:"next_reset_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // LeaderboardEntry represents a single entry in the leaderboard
@@ -49,17 +151,42 @@ type LeaderboardEntry struct {
 	PlayerID string `json:"player_id"`
 	Score    int64  `json:"score"`
 	Username string `json:"username,omitempty"`
+
+	// AvatarURL comes from the player's profile, when one exists
+	AvatarURL string `json:"avatar_url,omitempty"`
+
+	// Provisional marks players below the board's min_submissions threshold
+	Provisional bool `json:"provisional,omitempty"`
+
+	// IsGhost marks system-owned entries such as developer times
+	IsGhost bool `json:"is_ghost,omitempty"`
+
+	// PendingID is set on unapproved submissions listed with include=pending
+	PendingID int64 `json:"pending_id,omitempty"`
+
+	// Proof links the evidence behind the player's standing score, with include=proof
+	Proof *ScoreProof `json:"proof,omitempty"`
+}
+
+// PlayerStanding is a player's entry together with the entries immediately above and below
+type PlayerStanding struct {
+	Entry LeaderboardEntry  `json:"entry"`
+	Above *LeaderboardEntry `json:"above,omitempty"`
+	Below *LeaderboardEntry `json:"below,omitempty"`
 }
 
 // ScoreEvent represents a score submission event
 type ScoreEvent struct {
+	ID            int64                  `json:"id,omitempty"`
 	PlayerID      string                 `json:"player_id"`
 	LeaderboardID string                 `json:"leaderboard_id"`
 	Score         int64                  `json:"score"`
 	GameID        string                 `json:"game_id,omitempty"`
 	EventType     string                 `json:"event_type"`
+	SampleRate    int                    `json:"sample_rate,omitempty"`
 	Timestamp     time.Time              `json:"timestamp"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Proof         *ScoreProof            `json:"proof,omitempty"`
 }
 
 // ScoreSubmission represents a request to submit a score
@@ -69,6 +196,42 @@ type ScoreSubmission struct {
 	Score         int64                  `json:"score"`
 	GameID        string                 `json:"game_id,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+
+	// Optional evidence for the score, such as a replay
+	ReplayURL string `json:"replay_url,omitempty"`
+	ProofRef  string `json:"proof_ref,omitempty"`
+
+	// IdempotencyKey identifies one logical submission so copies arriving over
+	// HTTP and Kafka are applied once
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// UpdateMode overrides the leaderboard's update mode for this submission.
+	// It is set by ingestion routing, never by clients.
+	UpdateMode UpdateMode `json:"-"`
+}
+
+// Proof returns the submission's evidence reference, or nil when it has none
+func (s *ScoreSubmission) Proof() *ScoreProof {
+	if s.ReplayURL == "" && s.ProofRef == "" {
+		return nil
+	}
+	return &ScoreProof{ReplayURL: s.ReplayURL, ProofRef: s.ProofRef}
+}
+
+// ScoreValidation reports the outcome of a dry-run score submission
+type ScoreValidation struct {
+	Valid          bool     `json:"valid"`
+	Errors         []string `json:"errors,omitempty"`
+	LeaderboardID  string   `json:"leaderboard_id"`
+	PlayerID       string   `json:"player_id"`
+	UpdateMode     string   `json:"update_mode,omitempty"`
+	CurrentScore   *int64   `json:"current_score,omitempty"`
+	CurrentRank    *int64   `json:"current_rank,omitempty"`
+	ProjectedScore int64    `json:"projected_score"`
+	ProjectedRank  int64    `json:"projected_rank,omitempty"`
+	WouldChange    bool     `json:"would_change"`
+	NeedsReview    bool     `json:"needs_review,omitempty"`
+	AttemptsLeft   *int     `json:"attempts_left,omitempty"`
 }
 
 // BatchScoreSubmission represents multiple score submissions
@@ -76,6 +239,30 @@ type BatchScoreSubmission struct {
 	Scores []ScoreSubmission `json:"scores"`
 }
 
+// BatchResult reports how many submissions of a batch were applied and which failed
+type BatchResult struct {
+	Accepted int            `json:"accepted"`
+	Failed   []BatchFailure `json:"failed,omitempty"`
+	// Pending counts submissions held for review; they are not included in Accepted
+	Pending int `json:"pending,omitempty"`
+	// Duplicates counts submissions skipped because their idempotency key was already applied
+	Duplicates int `json:"duplicates,omitempty"`
+	// Ignored counts submissions dropped because the player's attempt quota was used up
+	Ignored int `json:"ignored,omitempty"`
+	// Versions maps each updated leaderboard to the version that includes the batch
+	Versions map[string]int64 `json:"versions,omitempty"`
+}
+
+// BatchFailure describes one rejected submission of a batch. Err is the
+// error behind Error, for callers deciding whether to retry.
+type BatchFailure struct {
+	Index         int    `json:"index"`
+	PlayerID      string `json:"player_id"`
+	LeaderboardID string `json:"leaderboard_id"`
+	Error         string `json:"error"`
+	Err           error  `json:"-"`
+}
+
 // CreateLeaderboardRequest represents a request to create a new leaderboard
 type CreateLeaderboardRequest struct {
 	ID          string      `json:"id"`
@@ -84,6 +271,46 @@ type CreateLeaderboardRequest struct {
 	ResetPeriod ResetPeriod `json:"reset_period,omitempty"`
 	MaxEntries  int         `json:"max_entries,omitempty"`
 	UpdateMode  UpdateMode  `json:"update_mode,omitempty"`
+	ShadowID    string      `json:"shadow_id,omitempty"`
+	TieBreak    TieBreak    `json:"tie_break,omitempty"`
+
+	ParentID string `json:"parent_id,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+
+	UpdateThrottleMs int64 `json:"update_throttle_ms,omitempty"`
+	MinRankChange    int64 `json:"min_rank_change,omitempty"`
+	MinScoreChange   int64 `json:"min_score_change,omitempty"`
+
+	ScoreUnit       string        `json:"score_unit,omitempty"`
+	ScoreMultiplier float64       `json:"score_multiplier,omitempty"`
+	ScoreOffset     int64         `json:"score_offset,omitempty"`
+	ScoreRounding   ScoreRounding `json:"score_rounding,omitempty"`
+	ScoreFormula    string        `json:"score_formula,omitempty"`
+
+	// Script is the Lua source of the board's first scoring script version
+	Script string `json:"script,omitempty"`
+
+	MinScore *int64 `json:"min_score,omitempty"`
+	MaxScore *int64 `json:"max_score,omitempty"`
+
+	ClampMin *int64 `json:"clamp_min,omitempty"`
+	ClampMax *int64 `json:"clamp_max,omitempty"`
+
+	Timezone string `json:"timezone,omitempty"`
+
+	OpenAt  *time.Time `json:"open_at,omitempty"`
+	CloseAt *time.Time `json:"close_at,omitempty"`
+
+	MinSubmissions int64 `json:"min_submissions,omitempty"`
+	InactivityDays int   `json:"inactivity_days,omitempty"`
+
+	AttemptQuota  int           `json:"attempt_quota,omitempty"`
+	QuotaPeriod   ResetPeriod   `json:"quota_period,omitempty"`
+	QuotaExceeded QuotaExceeded `json:"quota_exceeded,omitempty"`
+
+	ReviewThreshold *int64 `json:"review_threshold,omitempty"`
+
+	SensitiveFields []string `json:"sensitive_fields,omitempty"`
 }
 
 // ToConfig converts a CreateLeaderboardRequest to a LeaderboardConfig with defaults
@@ -95,8 +322,40 @@ func (r *CreateLeaderboardRequest) ToConfig() LeaderboardConfig {
 		ResetPeriod: r.ResetPeriod,
 		MaxEntries:  r.MaxEntries,
 		UpdateMode:  r.UpdateMode,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ShadowID:    r.ShadowID,
+		TieBreak:    r.TieBreak,
+
+		ParentID: r.ParentID,
+		Filter:   r.Filter,
+
+		UpdateThrottleMs: r.UpdateThrottleMs,
+		MinRankChange:    r.MinRankChange,
+		MinScoreChange:   r.MinScoreChange,
+
+		ScoreUnit:       r.ScoreUnit,
+		ScoreMultiplier: r.ScoreMultiplier,
+		ScoreOffset:     r.ScoreOffset,
+		ScoreRounding:   r.ScoreRounding,
+		ScoreFormula:    r.ScoreFormula,
+
+		MinScore: r.MinScore,
+		MaxScore: r.MaxScore,
+		ClampMin: r.ClampMin,
+		ClampMax: r.ClampMax,
+		Timezone: r.Timezone,
+		OpenAt:   r.OpenAt,
+		CloseAt:  r.CloseAt,
+
+		MinSubmissions:  r.MinSubmissions,
+		InactivityDays:  r.InactivityDays,
+		AttemptQuota:    r.AttemptQuota,
+		QuotaPeriod:     r.QuotaPeriod,
+		QuotaExceeded:   r.QuotaExceeded,
+		ReviewThreshold: r.ReviewThreshold,
+		SensitiveFields: r.SensitiveFields,
+
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
 	// Apply defaults
@@ -112,15 +371,311 @@ func (r *CreateLeaderboardRequest) ToConfig() LeaderboardConfig {
 	if config.UpdateMode == "" {
 		config.UpdateMode = UpdateModeReplace
 	}
+	if config.ScoreMultiplier == 0 {
+		config.ScoreMultiplier = 1
+	}
+	if config.ScoreRounding == "" {
+		config.ScoreRounding = ScoreRoundingRound
+	}
+	if config.AttemptQuota > 0 && config.QuotaPeriod == "" {
+		config.QuotaPeriod = ResetPeriodDaily
+	}
+	if config.AttemptQuota > 0 && config.QuotaExceeded == "" {
+		config.QuotaExceeded = QuotaExceededReject
+	}
 
 	return config
 }
 
-// LeaderboardStats contains statistics about a leaderboard
+// ValidateParticipation checks the minimum submission threshold and inactivity window
+func (c *LeaderboardConfig) ValidateParticipation() error {
+	if c.MinSubmissions < 0 {
+		return NewValidationError(ErrInvalidLeaderboard, "min_submissions", "must not be negative")
+	}
+	if c.InactivityDays < 0 {
+		return NewValidationError(ErrInvalidLeaderboard, "inactivity_days", "must not be negative")
+	}
+	return nil
+}
+
+// ValidateQuota checks the attempt quota, its period, and what happens beyond it
+func (c *LeaderboardConfig) ValidateQuota() error {
+	if c.AttemptQuota < 0 {
+		return NewValidationError(ErrInvalidLeaderboard, "attempt_quota", "must not be negative")
+	}
+	if c.AttemptQuota == 0 {
+		if c.QuotaPeriod != "" || c.QuotaExceeded != "" {
+			return NewValidationError(ErrInvalidLeaderboard, "attempt_quota", "is required with quota_period or quota_exceeded")
+		}
+		return nil
+	}
+	switch c.QuotaPeriod {
+	case ResetPeriodDaily, ResetPeriodWeekly, ResetPeriodMonthly, ResetPeriodNever:
+	default:
+		return NewValidationError(ErrInvalidLeaderboard, "quota_period", "must be daily, weekly, monthly or never")
+	}
+	switch c.QuotaExceeded {
+	case QuotaExceededReject, QuotaExceededIgnore:
+	default:
+		return NewValidationError(ErrInvalidLeaderboard, "quota_exceeded", "must be reject or ignore")
+	}
+	return nil
+}
+
+// InactivityWindow returns how long a player may go without submitting before
+// their entry is dropped; 0 means entries never expire
+func (c *LeaderboardConfig) InactivityWindow() time.Duration {
+	return time.Duration(c.InactivityDays) * 24 * time.Hour
+}
+
+// ValidateSensitiveFields checks that sensitive metadata keys are named and listed once
+func (c *LeaderboardConfig) ValidateSensitiveFields() error {
+	seen := make(map[string]bool, len(c.SensitiveFields))
+	for _, field := range c.SensitiveFields {
+		if field == "" || strings.Contains(field, ",") || seen[field] {
+			return NewValidationError(ErrInvalidLeaderboard, "sensitive_fields", "keys must be named, without commas, and listed once")
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// IsSensitive reports whether a top-level metadata key is encrypted at rest
+func (c *LeaderboardConfig) IsSensitive(field string) bool {
+	for _, f := range c.SensitiveFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWindow checks that the submission window closes after it opens
+func (c *LeaderboardConfig) ValidateWindow() error {
+	if c.OpenAt != nil && c.CloseAt != nil && !c.CloseAt.After(*c.OpenAt) {
+		return NewValidationError(ErrInvalidLeaderboard, "close_at", "must be after open_at")
+	}
+	return nil
+}
+
+// CheckWindow returns a *SubmissionWindowError when now is outside the submission window
+func (c *LeaderboardConfig) CheckWindow(now time.Time) error {
+	if c.OpenAt != nil && now.Before(*c.OpenAt) {
+		return &SubmissionWindowError{LeaderboardID: c.ID, OpensAt: c.OpenAt}
+	}
+	if c.CloseAt != nil && !now.Before(*c.CloseAt) {
+		return &SubmissionWindowError{LeaderboardID: c.ID, ClosedAt: c.CloseAt}
+	}
+	return nil
+}
+
+// ValidateScoring checks the score transform, unit label, and score bounds
+func (c *LeaderboardConfig) ValidateScoring() error {
+	if c.MinScore != nil && c.MaxScore != nil && *c.MinScore > *c.MaxScore {
+		return NewValidationError(ErrInvalidLeaderboard, "min_score", "must not exceed max_score")
+	}
+	bounds := []struct {
+		field string
+		value *int64
+	}{{"min_score", c.MinScore}, {"max_score", c.MaxScore}, {"clamp_min", c.ClampMin}, {"clamp_max", c.ClampMax}}
+	for _, bound := range bounds {
+		if bound.value != nil && !IsSafeScore(*bound.value) {
+			return NewValidationError(ErrInvalidLeaderboard, bound.field, "is beyond the largest safe score")
+		}
+	}
+	if c.HasClamp() {
+		// Only increment boards keep a running total to clamp
+		if c.UpdateMode != UpdateModeIncrement {
+			return NewValidationError(ErrInvalidLeaderboard, "update_mode", "must be increment to clamp totals")
+		}
+		if c.ClampMin != nil && c.ClampMax != nil && *c.ClampMin > *c.ClampMax {
+			return NewValidationError(ErrInvalidLeaderboard, "clamp_min", "must not exceed clamp_max")
+		}
+	}
+	switch c.ScoreRounding {
+	case "", ScoreRoundingRound, ScoreRoundingFloor, ScoreRoundingCeil:
+	default:
+		return NewValidationError(ErrInvalidLeaderboard, "score_rounding", "must be round, floor or ceil")
+	}
+	if len(c.ScoreUnit) > maxScoreUnitLength {
+		return NewValidationError(ErrInvalidLeaderboard, "score_unit", fmt.Sprintf("must be at most %d characters", maxScoreUnitLength))
+	}
+	return nil
+}
+
+// TransformScore applies the leaderboard's score transform to a submitted score.
+// It returns ErrInvalidScore when the result does not fit in an int64.
+func (c *LeaderboardConfig) TransformScore(score int64) (int64, error) {
+	multiplier := c.ScoreMultiplier
+	if multiplier == 0 || multiplier == 1 {
+		return addScoreOffset(score, c.ScoreOffset)
+	}
+
+	scaled := float64(score) * multiplier
+	switch c.ScoreRounding {
+	case ScoreRoundingFloor:
+		scaled = math.Floor(scaled)
+	case ScoreRoundingCeil:
+		scaled = math.Ceil(scaled)
+	default:
+		scaled = math.Round(scaled)
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, which no longer fits
+	if math.IsNaN(scaled) || scaled >= math.MaxInt64 || scaled < math.MinInt64 {
+		return 0, ErrInvalidScore
+	}
+
+	return addScoreOffset(int64(scaled), c.ScoreOffset)
+}
+
+// MaxSafeScore is the largest magnitude a score may have. Redis stores sorted
+// set scores as float64, which represents every integer only up to 2^53.
+const MaxSafeScore int64 = 1<<53 - 1
+
+// IsSafeScore reports whether a score survives the float64 round trip exactly
+func IsSafeScore(score int64) bool {
+	return score >= -MaxSafeScore && score <= MaxSafeScore
+}
+
+// CheckScoreBounds rejects a transformed score outside the leaderboard's range
+// or outside the range Redis stores exactly
+func (c *LeaderboardConfig) CheckScoreBounds(score int64) error {
+	if !IsSafeScore(score) {
+		return ErrInvalidScore
+	}
+	if c.MinScore != nil && score < *c.MinScore {
+		return ErrInvalidScore
+	}
+	if c.MaxScore != nil && score > *c.MaxScore {
+		return ErrInvalidScore
+	}
+	return nil
+}
+
+// HasClamp reports whether the leaderboard clamps its running totals
+func (c *LeaderboardConfig) HasClamp() bool {
+	return c.ClampMin != nil || c.ClampMax != nil
+}
+
+// ClampTotal limits an increment board's running total to its clamp range and
+// reports whether the total had to be adjusted
+func (c *LeaderboardConfig) ClampTotal(total int64) (int64, bool) {
+	if c.ClampMin != nil && total < *c.ClampMin {
+		return *c.ClampMin, true
+	}
+	if c.ClampMax != nil && total > *c.ClampMax {
+		return *c.ClampMax, true
+	}
+	return total, false
+}
+
+// NeedsReview reports whether a transformed score beats the review threshold
+func (c *LeaderboardConfig) NeedsReview(score int64) bool {
+	if c.ReviewThreshold == nil {
+		return false
+	}
+	if c.HigherIsBetter() {
+		return score > *c.ReviewThreshold
+	}
+	return score < *c.ReviewThreshold
+}
+
+// HigherIsBetter reports whether larger scores rank higher
+func (c *LeaderboardConfig) HigherIsBetter() bool {
+	return c.SortOrder != SortOrderAsc
+}
+
+// addScoreOffset adds the offset to a score, rejecting overflow
+func addScoreOffset(score, offset int64) (int64, error) {
+	result := score + offset
+	if (offset > 0 && result < score) || (offset < 0 && result > score) {
+		return 0, ErrInvalidScore
+	}
+	return result, nil
+}
+
+// HasSignificanceThreshold reports whether broadcasts are limited to significant changes
+func (c *LeaderboardConfig) HasSignificanceThreshold() bool {
+	return c.MinRankChange > 0 || c.MinScoreChange > 0
+}
+
+// RankingResponse bundles ranking entries with the leaderboard's configuration
+// and size, returned by ranking endpoints with ?include=meta
+type RankingResponse struct {
+	Leaderboard  *LeaderboardConfig `json:"leaderboard"`
+	TotalPlayers int64              `json:"total_players"`
+	Entries      []LeaderboardEntry `json:"entries"`
+}
+
+// SetShadowRequest represents a request to attach a shadow leaderboard
+type SetShadowRequest struct {
+	ShadowID string `json:"shadow_id"`
+}
+
+// LeaderboardStats contains statistics about a leaderboard.
+// TopScore belongs to the first-ranked player and LowestScore to the last-ranked
+// one, so on ascending boards TopScore is the smallest value. Both are omitted
+// for empty boards; zero is a valid score.
 type LeaderboardStats struct {
-	LeaderboardID string `json:"leaderboard_id"`
-	TotalPlayers  int64  `json:"total_players"`
-	TopScore      int64  `json:"top_score,omitempty"`
-	LowestScore   int64  `json:"lowest_score,omitempty"`
+	LeaderboardID string    `json:"leaderboard_id"`
+	SortOrder     SortOrder `json:"sort_order"`
+	TotalPlayers  int64     `json:"total_players"`
+	TopScore      *int64    `json:"top_score,omitempty"`
+	LowestScore   *int64    `json:"lowest_score,omitempty"`
+
+	// Average and StdDev (population) are estimated from a random sample of
+	// SampleSize scores when Sampled is set
+	Average    *float64  `json:"average,omitempty"`
+	StdDev     *float64  `json:"stddev,omitempty"`
+	Sampled    bool      `json:"sampled,omitempty"`
+	SampleSize int64     `json:"sample_size,omitempty"`
+	Version    int64     `json:"version"`
+	ComputedAt time.Time `json:"computed_at"`
+
+	NextResetAt *time.Time `json:"next_reset_at,omitempty"`
+
+	// LastWriteAt is when a submission was last applied and IdleSeconds how
+	// long ago that was at ComputedAt; a long idle time on a busy board can
+	// mean the ingest pipeline stopped. Both are absent until the first write.
+	LastWriteAt *time.Time `json:"last_write_at,omitempty"`
+	IdleSeconds *int64     `json:"idle_seconds,omitempty"`
 }
 
+// LeaderboardView is a dashboard snapshot of one leaderboard: its settings,
+// counts, and the entries at the top and bottom of the ranking
+type LeaderboardView struct {
+	Leaderboard  *LeaderboardConfig `json:"leaderboard"`
+	TotalPlayers int64              `json:"total_players"`
+	TopScore     *int64             `json:"top_score,omitempty"`
+	LowestScore  *int64             `json:"lowest_score,omitempty"`
+	Version      int64              `json:"version"`
+	Top          []LeaderboardEntry `json:"top"`
+	Bottom       []LeaderboardEntry `json:"bottom"`
+}
+
+// ReplicationOp identifies the kind of replicated change
+type ReplicationOp string
+
+const (
+	ReplicationOpSet    ReplicationOp = "set"
+	ReplicationOpRemove ReplicationOp = "remove"
+	ReplicationOpReset  ReplicationOp = "reset"
+)
+
+// ReplicatedChange is a score change applied on the primary region, carrying
+// the resulting absolute score so replicas never re-run update modes
+type ReplicatedChange struct {
+	Op            ReplicationOp `json:"op"`
+	LeaderboardID string        `json:"leaderboard_id"`
+	PlayerID      string        `json:"player_id,omitempty"`
+	Score         int64         `json:"score,omitempty"`
+	Tie           float64       `json:"tie,omitempty"` // the score's tie-break component
+	Version       int64         `json:"version,omitempty"`
+	Region        string        `json:"region,omitempty"`
+	AppliedAt     time.Time     `json:"applied_at"`
+}
+
+// ReplicationBatch is the payload posted to a replica's apply endpoint
+type ReplicationBatch struct {
+	Changes []ReplicatedChange `json:"changes"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Overview summarizes every leaderboard for an operations dashboard
+type Overview struct {
+	Leaderboards     []LeaderboardOverview `json:"leaderboards"`
+	TotalConnections int                   `json:"total_connections"`
+	GeneratedAt      time.Time             `json:"generated_at"`
+}
+
+// LeaderboardOverview is one leaderboard's row of the overview. TopPlayer is
+// nil on an empty board; ghosts are never reported as the top player.
+type LeaderboardOverview struct {
+	LeaderboardID       string            `json:"leaderboard_id"`
+	Name                string            `json:"name"`
+	TotalPlayers        int64             `json:"total_players"`
+	SubmissionsLastHour int64             `json:"submissions_last_hour"`
+	TopPlayer           *LeaderboardEntry `json:"top_player,omitempty"`
+	Subscribers         int               `json:"subscribers"`
+}
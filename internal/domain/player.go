@@ -4,18 +4,67 @@ import "time"
 
 // Player represents a player in the system
 type Player struct {
-	ID        string    `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email,omitempty"`
-	AvatarURL string    `json:"avatar_url,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string                 `json:"id"`
+	Username  string                 `json:"username"`
+	Email     string                 `json:"email,omitempty"`
+	AvatarURL string                 `json:"avatar_url,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
 }
 
 // PlayerInfo is a lightweight player information struct used for caching
 type PlayerInfo struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// Info returns the cached subset of a player's profile
+func (p *Player) Info() PlayerInfo {
+	return PlayerInfo{ID: p.ID, Username: p.Username, AvatarURL: p.AvatarURL}
+}
+
+// CreatePlayerRequest creates a player profile
+type CreatePlayerRequest struct {
+	ID        string                 `json:"id"`
+	Username  string                 `json:"username"`
+	Email     string                 `json:"email,omitempty"`
+	AvatarURL string                 `json:"avatar_url,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// UpdatePlayerRequest changes the fields of a player profile that are set.
+// Metadata replaces the stored metadata as a whole.
+type UpdatePlayerRequest struct {
+	Username  *string                `json:"username,omitempty"`
+	Email     *string                `json:"email,omitempty"`
+	AvatarURL *string                `json:"avatar_url,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Apply returns the player with the request's changes
+func (r UpdatePlayerRequest) Apply(player Player) Player {
+	if r.Username != nil {
+		player.Username = *r.Username
+	}
+	if r.Email != nil {
+		player.Email = *r.Email
+	}
+	if r.AvatarURL != nil {
+		player.AvatarURL = *r.AvatarURL
+	}
+	if r.Metadata != nil {
+		player.Metadata = r.Metadata
+	}
+	return player
+}
+
+// PlayerPage is a page of player profiles ordered by ID. NextCursor continues
+// the listing and is empty on the last page.
+type PlayerPage struct {
+	Players    []Player `json:"players"`
+	NextCursor string   `json:"next_cursor,omitempty"`
 }
 
 // PlayerScore represents a player's score in a specific leaderboard
@@ -28,3 +77,14 @@ type PlayerScore struct {
 	UpdatedAt     time.Time              `json:"updated_at"`
 }
 
+// AdminAuditEntry records an admin acting on behalf of a player through
+// X-On-Behalf-Of
+type AdminAuditEntry struct {
+	ID         int64     `json:"id"`
+	Actor      string    `json:"actor"`
+	OnBehalfOf string    `json:"on_behalf_of"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	RequestID  string    `json:"request_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// MaxPrizeRank bounds the positions a prize structure can pay, so a payout
+// never reads more of a board than a real prize table needs
+const MaxPrizeRank = 100000
+
+// TiePolicy decides how players tied on score share prizes
+type TiePolicy string
+
+const (
+	// TiePolicySplit pools the prizes of the positions a tied group covers and
+	// splits them equally; indivisible units go to the group's first players
+	TiePolicySplit TiePolicy = "split"
+	// TiePolicyShared pays every tied player the prize of the group's best position
+	TiePolicyShared TiePolicy = "shared"
+	// TiePolicyRankOrder ignores ties and pays each position as the board orders it
+	TiePolicyRankOrder TiePolicy = "rank_order"
+)
+
+// PrizeBracket pays Amount, and optionally a Label such as an item name, to
+// each position from FromRank to ToRank. A zero ToRank pays FromRank only.
+type PrizeBracket struct {
+	FromRank int64  `json:"from_rank"`
+	ToRank   int64  `json:"to_rank,omitempty"`
+	Amount   int64  `json:"amount"`
+	Label    string `json:"label,omitempty"`
+}
+
+// last returns the bracket's final position
+func (b PrizeBracket) last() int64 {
+	if b.ToRank == 0 {
+		return b.FromRank
+	}
+	return b.ToRank
+}
+
+// PrizeStructure maps payout positions to rewards. Positions count players
+// only: ghost entries are ranked on the board but never paid.
+type PrizeStructure struct {
+	TiePolicy TiePolicy      `json:"tie_policy,omitempty"`
+	Brackets  []PrizeBracket `json:"brackets"`
+}
+
+// Validate checks the brackets are positive, in range and do not overlap,
+// and that the tie policy is known. An empty policy means split.
+func (p *PrizeStructure) Validate() error {
+	switch p.TiePolicy {
+	case "", TiePolicySplit, TiePolicyShared, TiePolicyRankOrder:
+	default:
+		return ErrInvalidRequest
+	}
+	if len(p.Brackets) == 0 {
+		return ErrInvalidRequest
+	}
+
+	brackets := append([]PrizeBracket(nil), p.Brackets...)
+	sort.Slice(brackets, func(i, j int) bool { return brackets[i].FromRank < brackets[j].FromRank })
+	var previous int64
+	for _, b := range brackets {
+		if b.FromRank < 1 || b.last() < b.FromRank || b.last() > MaxPrizeRank || b.Amount < 0 {
+			return ErrInvalidRequest
+		}
+		if b.FromRank <= previous {
+			return ErrInvalidRequest
+		}
+		previous = b.last()
+	}
+	return nil
+}
+
+// MaxRank returns the last paid position
+func (p *PrizeStructure) MaxRank() int64 {
+	var max int64
+	for _, b := range p.Brackets {
+		if b.last() > max {
+			max = b.last()
+		}
+	}
+	return max
+}
+
+// prizeAt returns the bracket paying a position, if any
+func (p *PrizeStructure) prizeAt(position int64) (PrizeBracket, bool) {
+	for _, b := range p.Brackets {
+		if position >= b.FromRank && position <= b.last() {
+			return b, true
+		}
+	}
+	return PrizeBracket{}, false
+}
+
+// Distribute computes the payouts for players listed best first. Callers pass
+// at least MaxRank players, plus every player tied with the last of them.
+func (p *PrizeStructure) Distribute(entries []LeaderboardEntry) []Payout {
+	payouts := []Payout{}
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Score == entries[start].Score {
+			end++
+		}
+		payouts = p.distributeGroup(payouts, entries[start:end], int64(start)+1)
+		start = end
+	}
+	return payouts
+}
+
+// distributeGroup pays one group of players tied on score whose first member
+// holds position first
+func (p *PrizeStructure) distributeGroup(payouts []Payout, group []LeaderboardEntry, first int64) []Payout {
+	tied := len(group) > 1
+	pay := func(i int, amount int64, label string) {
+		if amount == 0 && label == "" {
+			return
+		}
+		payouts = append(payouts, Payout{
+			Position: first + int64(i),
+			Rank:     group[i].Rank,
+			PlayerID: group[i].PlayerID,
+			Score:    group[i].Score,
+			Amount:   amount,
+			Label:    label,
+			Tied:     tied,
+		})
+	}
+
+	best, paid := p.prizeAt(first)
+	switch {
+	case !tied || p.TiePolicy == TiePolicyRankOrder:
+		for i := range group {
+			if b, ok := p.prizeAt(first + int64(i)); ok {
+				pay(i, b.Amount, b.Label)
+			}
+		}
+	case p.TiePolicy == TiePolicyShared:
+		if paid {
+			for i := range group {
+				pay(i, best.Amount, best.Label)
+			}
+		}
+	default:
+		var pool int64
+		for i := range group {
+			if b, ok := p.prizeAt(first + int64(i)); ok {
+				pool += b.Amount
+			}
+		}
+		n := int64(len(group))
+		share, remainder := pool/n, pool%n
+		for i := range group {
+			amount := share
+			if int64(i) < remainder {
+				amount++
+			}
+			pay(i, amount, best.Label)
+		}
+	}
+	return payouts
+}
+
+// Payout is one player's prize. Position counts players only; Rank is the
+// player's rank on the board, which also counts ghost entries.
+type Payout struct {
+	Position int64  `json:"position"`
+	Rank     int64  `json:"rank"`
+	PlayerID string `json:"player_id"`
+	Score    int64  `json:"score"`
+	Amount   int64  `json:"amount"`
+	Label    string `json:"label,omitempty"`
+	Tied     bool   `json:"tied,omitempty"`
+}
+
+// PayoutRequest computes payouts with a configured structure, named by
+// Structure, or with an inline one. Snapshot selects a frozen snapshot;
+// without it the live standings are used.
+type PayoutRequest struct {
+	Structure string `json:"structure,omitempty"`
+	PrizeStructure
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// PayoutReport is the computed distribution of a leaderboard's prizes
+type PayoutReport struct {
+	LeaderboardID string    `json:"leaderboard_id"`
+	Structure     string    `json:"structure,omitempty"`
+	Snapshot      string    `json:"snapshot,omitempty"`
+	Version       int64     `json:"version"`
+	TiePolicy     TiePolicy `json:"tie_policy"`
+	Total         int64     `json:"total"`
+	Payouts       []Payout  `json:"payouts"`
+	ComputedAt    time.Time `json:"computed_at"`
+	// Published reports whether the payout event reached Kafka
+	Published bool `json:"published"`
+}
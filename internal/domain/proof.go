@@ -0,0 +1,31 @@
+package domain
+
+import "net/url"
+
+const (
+	maxReplayURLLength = 2048
+	maxProofRefLength  = 512
+)
+
+// ScoreProof references evidence for a score: a replay to watch or an opaque
+// reference into blob storage. The service stores references, never the blobs.
+type ScoreProof struct {
+	ReplayURL string `json:"replay_url,omitempty"`
+	ProofRef  string `json:"proof_ref,omitempty"`
+}
+
+// Validate checks that the replay URL is an absolute http(s) URL and that both
+// references fit their columns
+func (p *ScoreProof) Validate() error {
+	if len(p.ReplayURL) > maxReplayURLLength || len(p.ProofRef) > maxProofRefLength {
+		return ErrInvalidRequest
+	}
+	if p.ReplayURL == "" {
+		return nil
+	}
+	u, err := url.Parse(p.ReplayURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidRequest
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// PublishedStandings is the static document the publisher writes for a
+// leaderboard, so public clients can read it from a CDN instead of the API.
+// Version is read before the entries, which are never older than it.
+type PublishedStandings struct {
+	LeaderboardID string             `json:"leaderboard_id"`
+	Name          string             `json:"name"`
+	SortOrder     SortOrder          `json:"sort_order"`
+	ScoreUnit     string             `json:"score_unit,omitempty"`
+	Version       int64              `json:"version"`
+	TotalPlayers  int64              `json:"total_players"`
+	Entries       []LeaderboardEntry `json:"entries"`
+	PublishedAt   time.Time          `json:"published_at"`
+}
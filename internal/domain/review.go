@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+// ReviewStatus is the state of a submission held for manual verification
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+// IsValid checks if the review status is valid
+func (s ReviewStatus) IsValid() bool {
+	switch s {
+	case ReviewPending, ReviewApproved, ReviewRejected:
+		return true
+	}
+	return false
+}
+
+// PendingScore is a submission held back until an admin approves or rejects it.
+// Score is the transformed value that approval applies; RawScore is as submitted.
+type PendingScore struct {
+	ID            int64                  `json:"id"`
+	LeaderboardID string                 `json:"leaderboard_id"`
+	PlayerID      string                 `json:"player_id"`
+	Score         int64                  `json:"score"`
+	RawScore      int64                  `json:"raw_score"`
+	GameID        string                 `json:"game_id,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Proof         *ScoreProof            `json:"proof,omitempty"`
+	Status        ReviewStatus           `json:"status"`
+	SubmittedAt   time.Time              `json:"submitted_at"`
+	ReviewedAt    *time.Time             `json:"reviewed_at,omitempty"`
+	Reviewer      string                 `json:"reviewer,omitempty"`
+	Reason        string                 `json:"reason,omitempty"`
+}
+
+// Submission rebuilds the original submission for the pending score
+func (p *PendingScore) Submission() ScoreSubmission {
+	submission := ScoreSubmission{
+		PlayerID:      p.PlayerID,
+		LeaderboardID: p.LeaderboardID,
+		Score:         p.RawScore,
+		GameID:        p.GameID,
+		Metadata:      p.Metadata,
+	}
+	if p.Proof != nil {
+		submission.ReplayURL = p.Proof.ReplayURL
+		submission.ProofRef = p.Proof.ProofRef
+	}
+	return submission
+}
+
+// ReviewRequest records who reviewed a pending score and why
+type ReviewRequest struct {
+	Reviewer string `json:"reviewer,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// SubmitReceipt describes the outcome of a single score submission
+type SubmitReceipt struct {
+	// Version is the leaderboard version at which the score is visible; 0 while pending
+	Version int64 `json:"version,omitempty"`
+	// PendingID identifies a submission held for review
+	PendingID int64 `json:"pending_id,omitempty"`
+	// Duplicate is set when the submission's idempotency key was already applied
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Ignored is set when the submission was beyond the player's attempt quota
+	// on a board that drops such submissions
+	Ignored bool `json:"ignored,omitempty"`
+	// AttemptsLeft is how many submissions the player has left in the quota
+	// period; nil on boards without a quota
+	AttemptsLeft *int `json:"attempts_left,omitempty"`
+}
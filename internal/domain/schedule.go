@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// locations caches loaded timezones, since time.LoadLocation reads the tz database on every call
+var locations sync.Map
+
+// LoadLocation returns the timezone for a tz database name, e.g. Asia/Tokyo
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// ResetSchedule anchors reset periods to a timezone, a first day of the week,
+// and a time of day
+type ResetSchedule struct {
+	Location  *time.Location
+	WeekStart time.Weekday
+	Hour      int
+	Minute    int
+}
+
+// DefaultResetSchedule resets daily at 00:00 UTC with weeks starting on Monday
+func DefaultResetSchedule() ResetSchedule {
+	return ResetSchedule{Location: time.UTC, WeekStart: time.Monday}
+}
+
+// NewResetSchedule parses a tz database name (e.g. Asia/Tokyo), a weekday name,
+// and an HH:MM time of day into a schedule. Empty values keep the defaults.
+func NewResetSchedule(timezone, weekStart, timeOfDay string) (ResetSchedule, error) {
+	schedule := DefaultResetSchedule()
+
+	if timezone != "" {
+		loc, err := LoadLocation(timezone)
+		if err != nil {
+			return schedule, fmt.Errorf("loading reset timezone: %w", err)
+		}
+		schedule.Location = loc
+	}
+
+	if weekStart != "" {
+		day, ok := parseWeekday(weekStart)
+		if !ok {
+			return schedule, fmt.Errorf("invalid reset week start %q", weekStart)
+		}
+		schedule.WeekStart = day
+	}
+
+	if timeOfDay != "" {
+		t, err := time.Parse("15:04", timeOfDay)
+		if err != nil {
+			return schedule, fmt.Errorf("parsing reset time of day: %w", err)
+		}
+		schedule.Hour, schedule.Minute = t.Hour(), t.Minute()
+	}
+
+	return schedule, nil
+}
+
+// parseWeekday matches a full English weekday name, case-insensitively
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// For returns the schedule with the leaderboard's own timezone, if it has one.
+// The week start and time of day still come from s.
+func (s ResetSchedule) For(lb *LeaderboardConfig) ResetSchedule {
+	if lb.Timezone == "" {
+		return s
+	}
+	loc, err := LoadLocation(lb.Timezone)
+	if err != nil {
+		return s
+	}
+	s.Location = loc
+	return s
+}
+
+func (s ResetSchedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// boundary returns the reset instant on a local calendar day; out-of-range
+// days and months are normalized as in time.Date
+func (s ResetSchedule) boundary(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, s.Hour, s.Minute, 0, 0, s.location())
+}
+
+// PeriodStart returns the start, in UTC, of the period containing t.
+// It returns false for periods that never reset.
+func (s ResetSchedule) PeriodStart(p ResetPeriod, t time.Time) (time.Time, bool) {
+	local := t.In(s.location())
+
+	// Before today's reset time, t still belongs to yesterday
+	day := s.boundary(local.Year(), local.Month(), local.Day())
+	if local.Before(day) {
+		day = s.boundary(local.Year(), local.Month(), local.Day()-1)
+	}
+
+	switch p {
+	case ResetPeriodDaily:
+		return day.UTC(), true
+	case ResetPeriodWeekly:
+		offset := (int(day.Weekday()) - int(s.WeekStart) + 7) % 7
+		return s.boundary(day.Year(), day.Month(), day.Day()-offset).UTC(), true
+	case ResetPeriodMonthly:
+		return s.boundary(day.Year(), day.Month(), 1).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// NextReset returns when the period containing t ends, in UTC.
+// It returns false for periods that never reset.
+func (s ResetSchedule) NextReset(p ResetPeriod, t time.Time) (time.Time, bool) {
+	start, ok := s.PeriodStart(p, t)
+	if !ok {
+		return time.Time{}, false
+	}
+	local := start.In(s.location())
+
+	switch p {
+	case ResetPeriodDaily:
+		return s.boundary(local.Year(), local.Month(), local.Day()+1).UTC(), true
+	case ResetPeriodWeekly:
+		return s.boundary(local.Year(), local.Month(), local.Day()+7).UTC(), true
+	default:
+		return s.boundary(local.Year(), local.Month()+1, 1).UTC(), true
+	}
+}
+
+// ScoresPeriodStart returns the start of the period a leaderboard's current
+// scores belong to: its last scheduled reset, or the period it was created in
+func (s ResetSchedule) ScoresPeriodStart(lb *LeaderboardConfig) time.Time {
+	if lb.LastResetAt != nil {
+		return *lb.LastResetAt
+	}
+	// Never reset: the scores date from the period the board was created in
+	start, _ := s.For(lb).PeriodStart(lb.ResetPeriod, lb.CreatedAt)
+	return start
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIsSafeScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		score int64
+		want  bool
+	}{
+		{"zero", 0, true},
+		{"max safe", MaxSafeScore, true},
+		{"min safe", -MaxSafeScore, true},
+		{"2^53", 1 << 53, false},
+		{"-2^53", -(1 << 53), false},
+		{"2^53+1", 1<<53 + 1, false},
+		{"-(2^53+1)", -(1<<53 + 1), false},
+		{"max int64", math.MaxInt64, false},
+		{"min int64", math.MinInt64, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSafeScore(tt.score); got != tt.want {
+				t.Errorf("IsSafeScore(%d) = %v, want %v", tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScoreBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		score int64
+		want  error
+	}{
+		{"max safe", MaxSafeScore, nil},
+		{"min safe", -MaxSafeScore, nil},
+		{"2^53", 1 << 53, ErrInvalidScore},
+		{"-2^53", -(1 << 53), ErrInvalidScore},
+		{"2^53+1", 1<<53 + 1, ErrInvalidScore},
+		{"-(2^53+1)", -(1<<53 + 1), ErrInvalidScore},
+		{"max int64", math.MaxInt64, ErrInvalidScore},
+		{"min int64", math.MinInt64, ErrInvalidScore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LeaderboardConfig{}
+			if err := c.CheckScoreBounds(tt.score); !errors.Is(err, tt.want) {
+				t.Errorf("CheckScoreBounds(%d) = %v, want %v", tt.score, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformScoreExtremes(t *testing.T) {
+	tests := []struct {
+		name       string
+		score      int64
+		multiplier float64
+		offset     int64
+		want       int64
+		wantErr    bool
+	}{
+		{"identity max int64", math.MaxInt64, 1, 0, math.MaxInt64, false},
+		{"identity min int64", math.MinInt64, 1, 0, math.MinInt64, false},
+		{"offset overflows max int64", math.MaxInt64, 1, 1, 0, true},
+		{"offset overflows min int64", math.MinInt64, 1, -1, 0, true},
+		{"offset past max safe", MaxSafeScore, 1, 1, 1 << 53, false},
+		{"scaled max int64", math.MaxInt64, 2, 0, 0, true},
+		{"scaled min int64", math.MinInt64, 2, 0, 0, true},
+		{"scaled max safe", MaxSafeScore, 0.5, 0, 1 << 52, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LeaderboardConfig{ScoreMultiplier: tt.multiplier, ScoreOffset: tt.offset}
+			got, err := c.TransformScore(tt.score)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidScore) {
+					t.Fatalf("TransformScore(%d) error = %v, want ErrInvalidScore", tt.score, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TransformScore(%d) error = %v", tt.score, err)
+			}
+			if got != tt.want {
+				t.Errorf("TransformScore(%d) = %d, want %d", tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateScoringRejectsUnsafeBounds(t *testing.T) {
+	for _, bound := range []int64{1 << 53, -(1 << 53), 1<<53 + 1, -(1<<53 + 1), math.MaxInt64, math.MinInt64} {
+		c := &LeaderboardConfig{MaxScore: &bound}
+		if err := c.ValidateScoring(); !errors.Is(err, ErrInvalidLeaderboard) {
+			t.Errorf("ValidateScoring with max_score %d = %v, want ErrInvalidLeaderboard", bound, err)
+		}
+	}
+	safe := MaxSafeScore
+	c := &LeaderboardConfig{MaxScore: &safe}
+	if err := c.ValidateScoring(); err != nil {
+		t.Errorf("ValidateScoring with max_score %d = %v", safe, err)
+	}
+}
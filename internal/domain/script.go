@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// ScoringScript is one version of a leaderboard's Lua scoring script. Versions
+// are immutable; the leaderboard's ScriptVersion selects the active one.
+type ScoringScript struct {
+	LeaderboardID string    `json:"leaderboard_id"`
+	Version       int       `json:"version"`
+	Source        string    `json:"source"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreateScriptRequest adds a new script version, which becomes the active one
+type CreateScriptRequest struct {
+	Source string `json:"source"`
+}
+
+// ActivateScriptRequest selects the active script version; 0 disables scripting
+type ActivateScriptRequest struct {
+	Version int `json:"version"`
+}
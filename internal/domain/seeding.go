@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// SeedingFormat is how a seeding splits the seeded players
+type SeedingFormat string
+
+const (
+	// SeedingDivisions splits players into tiers of consecutive seeds, division 1 holding the top seeds
+	SeedingDivisions SeedingFormat = "divisions"
+	// SeedingBracket pairs players into the first round of a single-elimination bracket
+	SeedingBracket SeedingFormat = "bracket"
+)
+
+// Seeding limits
+const (
+	MaxDivisions       = 1000
+	MaxSeedingPlayers  = 100000
+	MaxBracketPlayers  = 4096
+	defaultBracketSize = 64
+)
+
+// SeedingRequest asks for the current standings to be split into divisions or a
+// bracket. Players caps how many of the top players are seeded; 0 seeds every
+// player into divisions and the top 64 into a bracket. An empty name uses the
+// seeding time.
+type SeedingRequest struct {
+	Name      string        `json:"name"`
+	Format    SeedingFormat `json:"format"`
+	Divisions int           `json:"divisions,omitempty"`
+	Players   int           `json:"players,omitempty"`
+}
+
+// Validate checks the request and fills in the default player cap
+func (r *SeedingRequest) Validate() error {
+	if r.Players < 0 {
+		return ErrInvalidRequest
+	}
+	switch r.Format {
+	case SeedingDivisions:
+		if r.Divisions < 1 || r.Divisions > MaxDivisions || r.Players > MaxSeedingPlayers {
+			return ErrInvalidRequest
+		}
+		if r.Players == 0 {
+			r.Players = MaxSeedingPlayers
+		}
+	case SeedingBracket:
+		if r.Divisions != 0 || r.Players == 1 || r.Players > MaxBracketPlayers {
+			return ErrInvalidRequest
+		}
+		if r.Players == 0 {
+			r.Players = defaultBracketSize
+		}
+	default:
+		return ErrInvalidRequest
+	}
+	return nil
+}
+
+// SeededPlayer is one player's place in a seeding. Seed is the player's
+// position among the seeded players at seeding time, 1 being the best.
+type SeededPlayer struct {
+	Seed     int64  `json:"seed"`
+	PlayerID string `json:"player_id"`
+	Score    int64  `json:"score"`
+	Division int    `json:"division,omitempty"`
+}
+
+// Seeding is a stored split of a leaderboard's standings into divisions or a
+// single-elimination bracket, used to seed a tournament
+type Seeding struct {
+	ID            int64         `json:"id"`
+	LeaderboardID string        `json:"leaderboard_id"`
+	Name          string        `json:"name"`
+	Format        SeedingFormat `json:"format"`
+	DivisionCount int           `json:"division_count,omitempty"`
+	Version       int64         `json:"version"`
+	PlayerCount   int64         `json:"player_count"`
+	CreatedAt     time.Time     `json:"created_at"`
+
+	// Filled in when a single seeding is requested
+	Divisions []DivisionAssignment `json:"divisions,omitempty"`
+	Matches   []BracketMatch       `json:"matches,omitempty"`
+}
+
+// DivisionAssignment lists the players seeded into one division
+type DivisionAssignment struct {
+	Division int            `json:"division"`
+	Players  []SeededPlayer `json:"players"`
+}
+
+// BracketMatch is a first-round pairing. Low is nil when High has a bye.
+type BracketMatch struct {
+	Match int           `json:"match"`
+	High  SeededPlayer  `json:"high"`
+	Low   *SeededPlayer `json:"low,omitempty"`
+}
+
+// DivisionEntry is a seeded player's live standing within their division.
+// Removed marks players no longer on the leaderboard, who are listed last.
+type DivisionEntry struct {
+	Rank     int64  `json:"rank"`
+	Seed     int64  `json:"seed"`
+	PlayerID string `json:"player_id"`
+	Score    int64  `json:"score"`
+	Removed  bool   `json:"removed,omitempty"`
+}
+
+// DivisionView is the live standings of one division of a seeding
+type DivisionView struct {
+	LeaderboardID string          `json:"leaderboard_id"`
+	Seeding       string          `json:"seeding"`
+	Division      int             `json:"division"`
+	Entries       []DivisionEntry `json:"entries"`
+}
+
+// AssignDivisions splits players, in seed order, into n divisions of
+// consecutive seeds. Sizes differ by at most one, the larger divisions first.
+func AssignDivisions(players []SeededPlayer, n int) {
+	size, extra := len(players)/n, len(players)%n
+	i := 0
+	for division := 1; division <= n; division++ {
+		count := size
+		if division <= extra {
+			count++
+		}
+		for end := i + count; i < end; i++ {
+			players[i].Division = division
+		}
+	}
+}
+
+// GroupDivisions groups players by division, in seed order within each
+func GroupDivisions(players []SeededPlayer, n int) []DivisionAssignment {
+	divisions := make([]DivisionAssignment, n)
+	for i := range divisions {
+		divisions[i] = DivisionAssignment{Division: i + 1, Players: []SeededPlayer{}}
+	}
+	for _, p := range players {
+		if p.Division >= 1 && p.Division <= n {
+			divisions[p.Division-1].Players = append(divisions[p.Division-1].Players, p)
+		}
+	}
+	return divisions
+}
+
+// BracketMatches pairs players, in seed order, for the first round of a
+// single-elimination bracket. The bracket is the next power of two, filled
+// with byes for the top seeds, and seeds meet as in standard seeding: 1 plays
+// the lowest seed, and 1 and 2 can only meet in the final.
+func BracketMatches(players []SeededPlayer) []BracketMatch {
+	size := 1
+	for size < len(players) {
+		size *= 2
+	}
+	if size < 2 {
+		size = 2
+	}
+
+	order := []int{1}
+	for n := 2; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+
+	matches := make([]BracketMatch, 0, size/2)
+	for i := 0; i+1 < len(order); i += 2 {
+		high, low := order[i], order[i+1]
+		if high > low {
+			high, low = low, high
+		}
+		if high > len(players) {
+			continue
+		}
+		match := BracketMatch{Match: len(matches) + 1, High: players[high-1]}
+		if low <= len(players) {
+			opponent := players[low-1]
+			match.Low = &opponent
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// RankDivision orders a division's players by live score and ranks them.
+// scores holds the players still on the leaderboard; the others keep their
+// seeding score, are marked removed, and follow in seed order. Ties share
+// the better rank.
+func RankDivision(players []SeededPlayer, scores map[string]int64, higherIsBetter bool) []DivisionEntry {
+	entries := make([]DivisionEntry, len(players))
+	for i, p := range players {
+		score, ok := scores[p.PlayerID]
+		if !ok {
+			score = p.Score
+		}
+		entries[i] = DivisionEntry{Seed: p.Seed, PlayerID: p.PlayerID, Score: score, Removed: !ok}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Removed != b.Removed {
+			return !a.Removed
+		}
+		if a.Removed || a.Score == b.Score {
+			return a.Seed < b.Seed
+		}
+		if higherIsBetter {
+			return a.Score > b.Score
+		}
+		return a.Score < b.Score
+	})
+
+	for i := range entries {
+		if i > 0 && !entries[i].Removed && !entries[i-1].Removed && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+			continue
+		}
+		entries[i].Rank = int64(i + 1)
+	}
+	return entries
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"regexp"
+	"time"
+)
+
+// snapshotNamePattern limits snapshot names to URL-safe labels such as season-3-final
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// Snapshot is an immutable copy of a leaderboard's full standings frozen at one
+// moment, kept for prize payouts and dispute resolution. Checksum is the hex
+// SHA-256 of the entries, each written as "rank\tplayer_id\tscore\n" in rank order.
+type Snapshot struct {
+	ID            int64     `json:"id"`
+	LeaderboardID string    `json:"leaderboard_id"`
+	Name          string    `json:"name"`
+	Version       int64     `json:"version"`
+	PlayerCount   int64     `json:"player_count"`
+	Checksum      string    `json:"checksum"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Entries are only loaded when the snapshot is downloaded
+	Entries []LeaderboardEntry `json:"entries,omitempty"`
+}
+
+// FreezeRequest names a new snapshot; an empty name uses the freeze time
+type FreezeRequest struct {
+	Name string `json:"name"`
+}
+
+// ValidSnapshotName reports whether name can identify a snapshot
+func ValidSnapshotName(name string) bool {
+	return snapshotNamePattern.MatchString(name)
+}
+
+// SnapshotChecksum accumulates the checksum of snapshot entries fed in rank order
+type SnapshotChecksum struct {
+	h hash.Hash
+}
+
+// NewSnapshotChecksum starts an empty checksum
+func NewSnapshotChecksum() *SnapshotChecksum {
+	return &SnapshotChecksum{h: sha256.New()}
+}
+
+// Add feeds the next entry
+func (c *SnapshotChecksum) Add(entry LeaderboardEntry) {
+	fmt.Fprintf(c.h, "%d\t%s\t%d\n", entry.Rank, entry.PlayerID, entry.Score)
+}
+
+// Sum returns the hex checksum of the entries added so far
+func (c *SnapshotChecksum) Sum() string {
+	return hex.EncodeToString(c.h.Sum(nil))
+}
+
+// SnapshotDiff compares the standings of two snapshots of one leaderboard.
+// Added lists players only in To, in To's rank order; Removed lists players
+// only in From, in From's rank order; Changed lists players in both whose rank
+// or score differs, in To's rank order. Counts cover every player even when
+// the lists are cut short by a limit.
+type SnapshotDiff struct {
+	LeaderboardID string              `json:"leaderboard_id"`
+	From          SnapshotRef         `json:"from"`
+	To            SnapshotRef         `json:"to"`
+	Counts        SnapshotDiffCounts  `json:"counts"`
+	Added         []LeaderboardEntry  `json:"added"`
+	Removed       []LeaderboardEntry  `json:"removed"`
+	Changed       []SnapshotEntryDiff `json:"changed"`
+}
+
+// SnapshotRef identifies one side of a snapshot diff
+type SnapshotRef struct {
+	Name        string    `json:"name"`
+	Version     int64     `json:"version"`
+	PlayerCount int64     `json:"player_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SnapshotDiffCounts totals the players in each category of a diff
+type SnapshotDiffCounts struct {
+	Added     int64 `json:"added"`
+	Removed   int64 `json:"removed"`
+	Changed   int64 `json:"changed"`
+	Unchanged int64 `json:"unchanged"`
+}
+
+// SnapshotEntryDiff is one player's change between two snapshots. RankChange
+// is positive when the player climbed.
+type SnapshotEntryDiff struct {
+	PlayerID    string `json:"player_id"`
+	OldRank     int64  `json:"old_rank"`
+	Rank        int64  `json:"rank"`
+	RankChange  int64  `json:"rank_change"`
+	OldScore    int64  `json:"old_score"`
+	Score       int64  `json:"score"`
+	ScoreChange int64  `json:"score_change"`
+}
+
+// Ref returns the reference to a snapshot used in diffs
+func (s *Snapshot) Ref() SnapshotRef {
+	return SnapshotRef{Name: s.Name, Version: s.Version, PlayerCount: s.PlayerCount, CreatedAt: s.CreatedAt}
+}
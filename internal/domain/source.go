@@ -0,0 +1,25 @@
+package domain
+
+import "context"
+
+// Paths a score submission can arrive on
+const (
+	SourceHTTP  = "http"
+	SourceKafka = "kafka"
+)
+
+type sourceKey struct{}
+
+// WithSubmissionSource tags submissions made with ctx with the path they arrived on
+func WithSubmissionSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SubmissionSource returns the path a submission arrived on; untagged
+// submissions count as SourceHTTP
+func SubmissionSource(ctx context.Context) string {
+	if source, ok := ctx.Value(sourceKey{}).(string); ok {
+		return source
+	}
+	return SourceHTTP
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TieBreak decides the order of players with equal scores
+type TieBreak string
+
+const (
+	// TieBreakNone leaves equal scores in the order Redis keeps them: by player
+	// ID, last ID first on boards where higher is better
+	TieBreakNone TieBreak = ""
+	// TieBreakEarliest ranks the player who reached the score first higher
+	TieBreakEarliest TieBreak = "earliest"
+	// TieBreakLatest ranks the player who reached the score most recently higher
+	TieBreakLatest TieBreak = "latest"
+	// TieBreakAlphabetical ranks equal scores by ascending player ID. Redis
+	// orders equal scores that way natively, so it needs an asc board.
+	TieBreakAlphabetical TieBreak = "alphabetical"
+)
+
+// tieSlots is how many submission times a score's tie-break component can
+// tell apart: the 20 bits below the score in the stored sorted set score
+const tieSlots = 1 << 20
+
+// MaxTieBreakScore and MinTieBreakScore bound the scores that keep a tie-break
+// component. A float64 holds 53 bits, 20 of which the component takes, so
+// larger scores are stored whole and fall back to player ID order.
+const (
+	MaxTieBreakScore int64 = 1<<33 - 1
+	MinTieBreakScore int64 = -(1 << 33)
+)
+
+// ValidateTieBreak checks the tie-break against the board's sort order and,
+// for time-based tie-breaks, against its score bounds
+func (c *LeaderboardConfig) ValidateTieBreak() error {
+	switch c.TieBreak {
+	case TieBreakNone:
+		return nil
+	case TieBreakEarliest, TieBreakLatest:
+		if c.MinScore != nil && *c.MinScore < MinTieBreakScore {
+			return NewValidationError(ErrInvalidLeaderboard, "min_score", fmt.Sprintf("must be at least %d with a time-based tie_break", MinTieBreakScore))
+		}
+		if c.MaxScore != nil && *c.MaxScore > MaxTieBreakScore {
+			return NewValidationError(ErrInvalidLeaderboard, "max_score", fmt.Sprintf("must be at most %d with a time-based tie_break", MaxTieBreakScore))
+		}
+		return nil
+	case TieBreakAlphabetical:
+		if c.HigherIsBetter() {
+			return ErrInvalidLeaderboard
+		}
+		return nil
+	default:
+		return ErrInvalidLeaderboard
+	}
+}
+
+// TieComponent returns the fraction added to a score written at the given
+// time, so equal scores order by submission time. It counts whole seconds
+// since the board was created, which tells times apart for about 12 days;
+// later submissions share the last slot. The component is 0 on boards without
+// a time-based tie-break, so their scores stay whole numbers.
+func (c *LeaderboardConfig) TieComponent(at time.Time) float64 {
+	if c.TieBreak != TieBreakEarliest && c.TieBreak != TieBreakLatest {
+		return 0
+	}
+	slot := int64(at.Sub(c.CreatedAt) / time.Second)
+	slot = max(0, min(slot, tieSlots-1))
+
+	// A larger component sorts higher, which ranks better only where higher is better
+	if (c.TieBreak == TieBreakEarliest) == c.HigherIsBetter() {
+		slot = tieSlots - 1 - slot
+	}
+	return float64(slot) / tieSlots
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTieComponentSeparatesSeconds(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &LeaderboardConfig{TieBreak: TieBreakLatest, CreatedAt: created}
+
+	first := c.TieComponent(created.Add(10 * time.Second))
+	second := c.TieComponent(created.Add(11 * time.Second))
+	if first >= second {
+		t.Errorf("latest: component at 10s = %v, not below 11s = %v", first, second)
+	}
+	if same := c.TieComponent(created.Add(10*time.Second + 900*time.Millisecond)); same != first {
+		t.Errorf("latest: component within one second = %v, want %v", same, first)
+	}
+
+	c.TieBreak = TieBreakEarliest
+	if earlier, later := c.TieComponent(created.Add(10*time.Second)), c.TieComponent(created.Add(11*time.Second)); earlier <= later {
+		t.Errorf("earliest: component at 10s = %v, not above 11s = %v", earlier, later)
+	}
+
+	// Past the last slot every submission shares it
+	end := created.Add(tieSlots * time.Second)
+	if a, b := c.TieComponent(end), c.TieComponent(end.Add(time.Hour)); a != b {
+		t.Errorf("components past the last slot differ: %v, %v", a, b)
+	}
+}
+
+func TestValidateTieBreakBounds(t *testing.T) {
+	bound := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name     string
+		tieBreak TieBreak
+		min, max *int64
+		field    string
+	}{
+		{"within bounds", TieBreakEarliest, bound(MinTieBreakScore), bound(MaxTieBreakScore), ""},
+		{"unbounded", TieBreakLatest, nil, nil, ""},
+		{"max too large", TieBreakLatest, nil, bound(MaxTieBreakScore + 1), "max_score"},
+		{"min too small", TieBreakEarliest, bound(MinTieBreakScore - 1), nil, "min_score"},
+		{"no time tie-break", TieBreakNone, nil, bound(MaxSafeScore), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &LeaderboardConfig{TieBreak: tt.tieBreak, MinScore: tt.min, MaxScore: tt.max}
+			err := c.ValidateTieBreak()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("ValidateTieBreak() = %v, want nil", err)
+				}
+				return
+			}
+			var validation *ValidationError
+			if !errors.As(err, &validation) || validation.Fields[0].Field != tt.field {
+				t.Errorf("ValidateTieBreak() = %v, want a validation error on %s", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package features
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// Feature flags. Each gates a subsystem that is rolled out gradually.
+const (
+	// DeltaBroadcasts sends WebSocket protocol v2 clients leaderboard_delta
+	// messages; when off they get a full leaderboard_update every time
+	DeltaBroadcasts = "delta_broadcasts"
+)
+
+// defaults tells whether each known flag is on when no rule is set for it
+var defaults = map[string]bool{
+	DeltaBroadcasts: true,
+}
+
+// IsFlag reports whether name is a known feature flag
+func IsFlag(name string) bool {
+	_, ok := defaults[name]
+	return ok
+}
+
+// Rule decides for which leaderboards a flag is on; see config.FeatureRule
+type Rule config.FeatureRule
+
+// Validate checks that the percentage is between 0 and 100
+func (r Rule) Validate() error {
+	if r.Percent < 0 || r.Percent > 100 {
+		return fmt.Errorf("percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// matches reports whether the rule turns flag on for a leaderboard of tenant.
+// An empty leaderboard ID only matches rules that enable the flag everywhere
+// or for the tenant.
+func (r Rule) matches(flag, tenant, leaderboardID string) bool {
+	if leaderboardID != "" && slices.Contains(r.Exclude, leaderboardID) {
+		return false
+	}
+	if r.Enabled || (tenant != "" && slices.Contains(r.Tenants, tenant)) {
+		return true
+	}
+	if leaderboardID == "" {
+		return false
+	}
+	if slices.Contains(r.Leaderboards, leaderboardID) {
+		return true
+	}
+	return r.Percent > 0 && bucket(flag, leaderboardID) < r.Percent
+}
+
+// bucket places a leaderboard in one of 100 buckets per flag, so raising a
+// flag's percentage only ever adds leaderboards
+func bucket(flag, leaderboardID string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(flag))
+	hash.Write([]byte{0})
+	hash.Write([]byte(leaderboardID))
+	return int(hash.Sum32() % 100)
+}
+
+// Store keeps the rules set at runtime, shared by every instance
+type Store interface {
+	GetFeatureOverrides(ctx context.Context) (map[string]string, error)
+	SetFeatureOverride(ctx context.Context, flag, rule string) error
+	DeleteFeatureOverride(ctx context.Context, flag string) error
+}
+
+// FlagStatus describes one flag: its built-in default, the rule from the
+// config file, the override set at runtime and, when asked for a leaderboard,
+// whether the flag is on there
+type FlagStatus struct {
+	Name     string `json:"name"`
+	Default  bool   `json:"default"`
+	Config   *Rule  `json:"config,omitempty"`
+	Override *Rule  `json:"override,omitempty"`
+	Enabled  *bool  `json:"enabled,omitempty"`
+}
+
+// Provider answers whether a flag is on for a leaderboard. Overrides take
+// precedence over config rules, which take precedence over the defaults.
+// Overrides are kept in the store and reloaded every refresh interval, so a
+// change made on one instance reaches the others. A nil Provider answers with
+// the defaults.
+type Provider struct {
+	store      Store
+	configured map[string]Rule
+	tenant     string
+	config     *config.FeaturesConfig
+	logger     *slog.Logger
+	clock      clock.Clock
+
+	overrides  map[string]Rule
+	overrideMu sync.RWMutex
+
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	mu      sync.Mutex
+	running bool
+}
+
+// NewProvider creates a provider for the configured rules; tenant is the
+// deployment's Redis tenant matched against rules' tenant lists
+func NewProvider(store Store, cfg *config.FeaturesConfig, tenant string, logger *slog.Logger) (*Provider, error) {
+	configured := make(map[string]Rule, len(cfg.Flags))
+	for name, rule := range cfg.Flags {
+		if !IsFlag(name) {
+			return nil, fmt.Errorf("features: unknown flag %q", name)
+		}
+		if err := Rule(rule).Validate(); err != nil {
+			return nil, fmt.Errorf("features: flag %q: %w", name, err)
+		}
+		configured[name] = Rule(rule)
+	}
+
+	return &Provider{
+		store:      store,
+		configured: configured,
+		tenant:     tenant,
+		config:     cfg,
+		logger:     logger,
+		clock:      clock.Real(),
+		overrides:  make(map[string]Rule),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}, nil
+}
+
+// SetClock replaces the wall clock; call before Start
+func (p *Provider) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// Enabled reports whether a flag is on for a leaderboard. Unknown flags are off.
+func (p *Provider) Enabled(flag, leaderboardID string) bool {
+	if p == nil {
+		return defaults[flag]
+	}
+	rule, ok := p.rule(flag)
+	if !ok {
+		return defaults[flag]
+	}
+	return rule.matches(flag, p.tenant, leaderboardID)
+}
+
+// rule returns the rule in effect for a flag
+func (p *Provider) rule(flag string) (Rule, bool) {
+	p.overrideMu.RLock()
+	rule, ok := p.overrides[flag]
+	p.overrideMu.RUnlock()
+	if ok {
+		return rule, true
+	}
+	rule, ok = p.configured[flag]
+	return rule, ok
+}
+
+// Start loads the overrides and begins reloading them in the background
+func (p *Provider) Start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = true
+	p.mu.Unlock()
+
+	if err := p.Refresh(ctx); err != nil {
+		p.logger.Warn("failed to load feature overrides, using configured rules", "error", err)
+	}
+	p.logger.Info("feature flags loaded", "refresh_interval", p.config.RefreshInterval)
+
+	go p.run(ctx)
+	return nil
+}
+
+// Stop stops reloading overrides
+func (p *Provider) Stop() error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	close(p.stopCh)
+	<-p.doneCh
+
+	p.mu.Lock()
+	p.running = false
+	p.mu.Unlock()
+	return nil
+}
+
+// run is the reload loop
+func (p *Provider) run(ctx context.Context) {
+	defer close(p.doneCh)
+
+	ticker := p.clock.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopCh:
+			return
+		case <-ticker.C():
+			if err := p.Refresh(ctx); err != nil {
+				p.logger.Warn("failed to reload feature overrides", "error", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads the overrides from the store. Malformed or unknown entries
+// are skipped.
+func (p *Provider) Refresh(ctx context.Context) error {
+	stored, err := p.store.GetFeatureOverrides(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]Rule, len(stored))
+	for name, data := range stored {
+		var rule Rule
+		if err := json.Unmarshal([]byte(data), &rule); err != nil || !IsFlag(name) {
+			p.logger.Warn("skipping invalid feature override", "flag", name, "error", err)
+			continue
+		}
+		overrides[name] = rule
+	}
+
+	p.overrideMu.Lock()
+	p.overrides = overrides
+	p.overrideMu.Unlock()
+	return nil
+}
+
+// Set stores an override for a flag and applies it on this instance at once
+func (p *Provider) Set(ctx context.Context, flag string, rule Rule) error {
+	if !IsFlag(flag) {
+		return fmt.Errorf("features: unknown flag %q", flag)
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("marshaling feature rule: %w", err)
+	}
+	if err := p.store.SetFeatureOverride(ctx, flag, string(data)); err != nil {
+		return err
+	}
+
+	p.overrideMu.Lock()
+	p.overrides[flag] = rule
+	p.overrideMu.Unlock()
+	return nil
+}
+
+// Clear removes a flag's override, returning it to its configured rule
+func (p *Provider) Clear(ctx context.Context, flag string) error {
+	if err := p.store.DeleteFeatureOverride(ctx, flag); err != nil {
+		return err
+	}
+
+	p.overrideMu.Lock()
+	delete(p.overrides, flag)
+	p.overrideMu.Unlock()
+	return nil
+}
+
+// Status describes every known flag, sorted by name. With a leaderboard ID,
+// each status also tells whether the flag is on for that leaderboard.
+func (p *Provider) Status(leaderboardID string) []FlagStatus {
+	p.overrideMu.RLock()
+	defer p.overrideMu.RUnlock()
+
+	statuses := make([]FlagStatus, 0, len(defaults))
+	for name, def := range defaults {
+		status := FlagStatus{Name: name, Default: def}
+		if rule, ok := p.configured[name]; ok {
+			status.Config = &rule
+		}
+		if rule, ok := p.overrides[name]; ok {
+			status.Override = &rule
+		}
+		if leaderboardID != "" {
+			enabled := def
+			if status.Override != nil {
+				enabled = status.Override.matches(name, p.tenant, leaderboardID)
+			} else if status.Config != nil {
+				enabled = status.Config.matches(name, p.tenant, leaderboardID)
+			}
+			status.Enabled = &enabled
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,443 @@
+// Package filter implements the boolean expressions that select which
+// submissions feed a derived leaderboard, e.g.
+//
+//	metadata.map == 'dust' && metadata.mode in ['ranked', 'tournament']
+//
+// Operands are paths, string literals in single or double quotes, numbers,
+// true and false. Paths are resolved by the caller. Comparisons are == != < <=
+// > >= and in, combined with && (and), || (or), ! (not) and parentheses.
+// A path that is not set compares unequal to everything and fails every
+// ordering comparison.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxLength caps the source length of a filter
+const maxLength = 1024
+
+// Resolver looks up the value of a path. Values are float64, string or bool;
+// other types compare unequal to every literal.
+type Resolver func(path string) (interface{}, bool)
+
+// Filter is a compiled filter expression, safe for concurrent use
+type Filter struct {
+	source string
+	root   node
+}
+
+// Source returns the filter as written
+func (f *Filter) Source() string {
+	return f.source
+}
+
+// Match evaluates the filter. An empty filter matches everything.
+func (f *Filter) Match(resolve Resolver) bool {
+	if f.root == nil {
+		return true
+	}
+	return truthy(f.root.eval(resolve))
+}
+
+// Compile parses a filter expression
+func Compile(source string) (*Filter, error) {
+	if len(source) > maxLength {
+		return nil, fmt.Errorf("filter longer than %d characters", maxLength)
+	}
+	if strings.TrimSpace(source) == "" {
+		return &Filter{source: source}, nil
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+	}
+	return &Filter{source: source, root: root}, nil
+}
+
+// missing is the value of a path that is not set
+type missing struct{}
+
+// node is one element of a parsed filter
+type node interface {
+	eval(resolve Resolver) interface{}
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(Resolver) interface{} {
+	return l.value
+}
+
+type path string
+
+func (p path) eval(resolve Resolver) interface{} {
+	if value, ok := resolve(string(p)); ok {
+		return value
+	}
+	return missing{}
+}
+
+type list []node
+
+func (l list) eval(Resolver) interface{} {
+	return nil
+}
+
+type not struct {
+	operand node
+}
+
+func (n not) eval(resolve Resolver) interface{} {
+	return !truthy(n.operand.eval(resolve))
+}
+
+type logical struct {
+	and         bool
+	left, right node
+}
+
+func (l logical) eval(resolve Resolver) interface{} {
+	left := truthy(l.left.eval(resolve))
+	if l.and != left {
+		return left
+	}
+	return truthy(l.right.eval(resolve))
+}
+
+type compare struct {
+	op          string
+	left, right node
+}
+
+func (c compare) eval(resolve Resolver) interface{} {
+	left := c.left.eval(resolve)
+	if c.op == "in" {
+		for _, item := range c.right.(list) {
+			if equal(left, item.eval(resolve)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	right := c.right.eval(resolve)
+	switch c.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+
+	order, ok := ordering(left, right)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+// truthy reports whether a value counts as true: only the boolean true does
+func truthy(value interface{}) bool {
+	b, ok := value.(bool)
+	return ok && b
+}
+
+// equal compares two values; missing values equal nothing
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	}
+	return false
+}
+
+// ordering compares two numbers or two strings
+func ordering(a, b interface{}) (int, bool) {
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(a, b), true
+	}
+	return 0, false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// Operators, longest first so == is not read as =
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","}
+
+// tokenize splits a filter into literals, paths and operators
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(source) && unicode.IsDigit(rune(source[i+1]))):
+			start := i
+			i++
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start})
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(source[i+1:], source[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: source[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(source) && isPathChar(rune(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isPathChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '-'
+}
+
+// parser is a recursive descent parser over the token stream
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators or keywords
+func (p *parser) accept(texts ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOp && tok.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// or := and (('||' | 'or') and)*
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{left: left, right: right}
+	}
+}
+
+// and := not (('&&' | 'and') not)*
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{and: true, left: left, right: right}
+	}
+}
+
+// not := ('!' | 'not') not | comparison
+func (p *parser) not() (node, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+// comparison := operand (op operand | 'in' '[' operand (',' operand)* ']')?
+func (p *parser) comparison() (node, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.accept("in"); ok {
+		items, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+		return compare{op: "in", left: left, right: items}, nil
+	}
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return compare{op: op, left: left, right: right}, nil
+}
+
+// list := '[' operand (',' operand)* ']'
+func (p *parser) list() (node, error) {
+	if _, ok := p.accept("["); !ok {
+		return nil, fmt.Errorf("expected [ at offset %d", p.peek().pos)
+	}
+	var items list
+	for {
+		item, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if _, ok := p.accept("]"); ok {
+			return items, nil
+		}
+		if _, ok := p.accept(","); !ok {
+			return nil, fmt.Errorf("expected , or ] at offset %d", p.peek().pos)
+		}
+	}
+}
+
+// operand := number | string | true | false | path | '(' or ')'
+func (p *parser) operand() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		return literal{value: value}, nil
+	case tokenString:
+		return literal{value: tok.text}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "and", "or", "not", "in":
+			return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+		}
+		return path(tok.text), nil
+	case tokenOp:
+		if tok.text == "(" {
+			inner, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("missing ) at offset %d", p.peek().pos)
+			}
+			return inner, nil
+		}
+	case tokenEOF:
+		return nil, errors.New("unexpected end of filter")
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+}
@@ -0,0 +1,378 @@
+// Package formula implements the small arithmetic language used to derive
+// scores from submission metadata, e.g. kills*100 + assists*50.
+//
+// An expression combines numbers and variables with + - * / %, unary minus,
+// parentheses and the functions min, max, abs, floor, ceil and round.
+// Variables are identifiers such as kills or dotted paths such as stats.kills.
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrUnknownVariable is returned when an expression reads a variable that is not set
+var ErrUnknownVariable = errors.New("unknown variable")
+
+// ErrDivisionByZero is returned when an expression divides by zero
+var ErrDivisionByZero = errors.New("division by zero")
+
+// maxLength caps the source length of an expression
+const maxLength = 1024
+
+// Vars resolves variables while an expression is evaluated
+type Vars func(name string) (float64, bool)
+
+// Expr is a compiled expression, safe for concurrent use
+type Expr struct {
+	source string
+	root   node
+	vars   []string
+}
+
+// Source returns the expression as written
+func (e *Expr) Source() string {
+	return e.source
+}
+
+// Variables returns the distinct variables the expression reads, in order of appearance
+func (e *Expr) Variables() []string {
+	return e.vars
+}
+
+// Eval evaluates the expression
+func (e *Expr) Eval(vars Vars) (float64, error) {
+	return e.root.eval(vars)
+}
+
+// Compile parses an expression
+func Compile(source string) (*Expr, error) {
+	if len(source) > maxLength {
+		return nil, fmt.Errorf("formula longer than %d characters", maxLength)
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, seen: make(map[string]bool)}
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+	}
+	return &Expr{source: source, root: root, vars: p.vars}, nil
+}
+
+// node is one element of a parsed expression
+type node interface {
+	eval(vars Vars) (float64, error)
+}
+
+type number float64
+
+func (n number) eval(Vars) (float64, error) {
+	return float64(n), nil
+}
+
+type variable string
+
+func (v variable) eval(vars Vars) (float64, error) {
+	if vars != nil {
+		if value, ok := vars(string(v)); ok {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownVariable, string(v))
+}
+
+type negate struct {
+	operand node
+}
+
+func (n negate) eval(vars Vars) (float64, error) {
+	value, err := n.operand.eval(vars)
+	return -value, err
+}
+
+type binary struct {
+	op          byte
+	left, right node
+}
+
+func (b binary) eval(vars Vars) (float64, error) {
+	left, err := b.left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	right, err := b.right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+
+	switch b.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return left / right, nil
+	default:
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return math.Mod(left, right), nil
+	}
+}
+
+type call struct {
+	fn   function
+	args []node
+}
+
+func (c call) eval(vars Vars) (float64, error) {
+	args := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		value, err := arg.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = value
+	}
+	return c.fn.apply(args), nil
+}
+
+// function is a built-in function; variadic functions take at least minArgs
+type function struct {
+	minArgs  int
+	variadic bool
+	apply    func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"min": {minArgs: 1, variadic: true, apply: func(args []float64) float64 {
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Min(result, arg)
+		}
+		return result
+	}},
+	"max": {minArgs: 1, variadic: true, apply: func(args []float64) float64 {
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Max(result, arg)
+		}
+		return result
+	}},
+	"abs":   {minArgs: 1, apply: func(args []float64) float64 { return math.Abs(args[0]) }},
+	"floor": {minArgs: 1, apply: func(args []float64) float64 { return math.Floor(args[0]) }},
+	"ceil":  {minArgs: 1, apply: func(args []float64) float64 { return math.Ceil(args[0]) }},
+	"round": {minArgs: 1, apply: func(args []float64) float64 { return math.Round(args[0]) }},
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize splits an expression into numbers, identifiers and operators
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(source) && isIdentChar(rune(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+		case strings.ContainsRune("+-*/%(),", c):
+			tokens = append(tokens, token{kind: tokenOp, text: string(c), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isIdentChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.'
+}
+
+// parser is a recursive descent parser over the token stream
+type parser struct {
+	tokens []token
+	pos    int
+	vars   []string
+	seen   map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator op
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOp && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expr := term (('+' | '-') term)*
+func (p *parser) expr() (node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOp || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: tok.text[0], left: left, right: right}
+	}
+}
+
+// term := unary (('*' | '/' | '%') unary)*
+func (p *parser) term() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOp || (tok.text != "*" && tok.text != "/" && tok.text != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: tok.text[0], left: left, right: right}
+	}
+}
+
+// unary := '-' unary | primary
+func (p *parser) unary() (node, error) {
+	if p.accept("-") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negate{operand: operand}, nil
+	}
+	return p.primary()
+}
+
+// primary := number | identifier | identifier '(' args ')' | '(' expr ')'
+func (p *parser) primary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		return number(value), nil
+	case tokenIdent:
+		if p.accept("(") {
+			return p.call(tok)
+		}
+		if !p.seen[tok.text] {
+			p.seen[tok.text] = true
+			p.vars = append(p.vars, tok.text)
+		}
+		return variable(tok.text), nil
+	case tokenOp:
+		if tok.text == "(" {
+			inner, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, fmt.Errorf("missing ) at offset %d", p.peek().pos)
+			}
+			return inner, nil
+		}
+	case tokenEOF:
+		return nil, errors.New("unexpected end of formula")
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+}
+
+// call parses the arguments of a function call after its opening parenthesis
+func (p *parser) call(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at offset %d", name.text, name.pos)
+	}
+
+	var args []node
+	if !p.accept(")") {
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(")") {
+				break
+			}
+			if !p.accept(",") {
+				return nil, fmt.Errorf("expected , or ) at offset %d", p.peek().pos)
+			}
+		}
+	}
+
+	if len(args) < fn.minArgs || (!fn.variadic && len(args) > fn.minArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s at offset %d", name.text, name.pos)
+	}
+	return call{fn: fn, args: args}, nil
+}
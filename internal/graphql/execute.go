@@ -0,0 +1,306 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Schema is the set of fields a query can select, starting from Query
+type Schema struct {
+	Query *Object
+
+	// MaxDepth rejects queries nested deeper than this many fields; 0 allows any depth
+	MaxDepth int
+	// MaxCost rejects queries estimated to resolve more than this many fields,
+	// counting list fields by their Size; 0 allows any cost
+	MaxCost int
+}
+
+// Object is a named type whose fields are resolved on request
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Field resolves one field of an object. Type is the object type of the
+// result, or nil for a scalar. A resolver may return a slice of Type values,
+// which is returned as a list; a nil result is returned as null. Size
+// estimates how many values a list field returns for the given arguments; a
+// field without one counts as a single value.
+type Field struct {
+	Type    *Object
+	Resolve func(ctx context.Context, p Params) (interface{}, error)
+	Size    func(args Args) int
+}
+
+// Params are the inputs of a resolver: the parent value and the arguments
+// with variables substituted
+type Params struct {
+	Source interface{}
+	Args   Args
+}
+
+// Request is a query as posted by a client
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of a query. Data is omitted when the query could
+// not be executed at all.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+// Error is a query or field error. Path locates a failed field in the data.
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute parses and runs a query. Fields whose resolver fails are returned
+// as null, with the failure listed in Errors.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	op, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	if req.OperationName != "" && req.OperationName != op.Name {
+		return &Response{Errors: []Error{{Message: fmt.Sprintf("unknown operation %q", req.OperationName)}}}
+	}
+	if s.MaxDepth > 0 && depth(op.Selections) > s.MaxDepth {
+		return &Response{Errors: []Error{{Message: fmt.Sprintf("query nested deeper than %d fields", s.MaxDepth)}}}
+	}
+
+	variables := make(map[string]interface{}, len(op.Variables))
+	for name, def := range op.Variables {
+		if value, ok := req.Variables[name]; ok {
+			variables[name] = value
+		} else {
+			variables[name] = def
+		}
+	}
+
+	e := &executor{variables: variables}
+	if s.MaxCost > 0 && e.cost(s.Query, op.Selections, s.MaxCost) > s.MaxCost {
+		return &Response{Errors: []Error{{Message: fmt.Sprintf("query would resolve more than %d fields", s.MaxCost)}}}
+	}
+	data := e.object(ctx, s.Query, nil, op.Selections, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// depth returns how deeply a selection set nests
+func depth(selections []Selection) int {
+	deepest := 0
+	for _, s := range selections {
+		deepest = max(deepest, depth(s.Selections))
+	}
+	if len(selections) == 0 {
+		return 0
+	}
+	return deepest + 1
+}
+
+// cost estimates how many fields resolving selections on one value of t
+// takes: each selected field counts once, and an object field adds the cost
+// of its selections for every value its Size says it returns. Counting stops
+// at the first total past limit, which is returned as limit+1.
+func (e *executor) cost(t *Object, selections []Selection, limit int) int {
+	total := 0
+	for _, s := range selections {
+		total++
+		if field, ok := t.Fields[s.Name]; ok && field.Type != nil {
+			n := 1
+			if field.Size != nil {
+				n = max(0, field.Size(e.arguments(s)))
+			}
+			if n > 0 {
+				per := e.cost(field.Type, s.Selections, limit)
+				if per > (limit-total)/n {
+					return limit + 1
+				}
+				total += n * per
+			}
+		}
+		if total > limit {
+			return limit + 1
+		}
+	}
+	return total
+}
+
+type executor struct {
+	variables map[string]interface{}
+	errors    []Error
+}
+
+func (e *executor) fail(path []interface{}, err error) {
+	e.errors = append(e.errors, Error{Message: err.Error(), Path: path})
+}
+
+// object resolves the selected fields of one value of an object type
+func (e *executor) object(ctx context.Context, t *Object, source interface{}, selections []Selection, path []interface{}) *result {
+	out := &result{}
+	for _, s := range selections {
+		fieldPath := append(append([]interface{}(nil), path...), s.Key())
+		if s.Name == "__typename" {
+			out.set(s.Key(), t.Name)
+			continue
+		}
+		field, ok := t.Fields[s.Name]
+		if !ok {
+			e.fail(fieldPath, fmt.Errorf("field %q does not exist on %s", s.Name, t.Name))
+			out.set(s.Key(), nil)
+			continue
+		}
+		out.set(s.Key(), e.field(ctx, field, source, s, fieldPath))
+	}
+	return out
+}
+
+// field resolves one selected field and completes its value
+func (e *executor) field(ctx context.Context, field *Field, source interface{}, s Selection, path []interface{}) interface{} {
+	if field.Type == nil && len(s.Selections) > 0 {
+		e.fail(path, fmt.Errorf("field %q is a scalar and has no fields to select", s.Name))
+		return nil
+	}
+	if field.Type != nil && len(s.Selections) == 0 {
+		e.fail(path, fmt.Errorf("field %q of type %s needs a selection of fields", s.Name, field.Type.Name))
+		return nil
+	}
+
+	value, err := field.Resolve(ctx, Params{Source: source, Args: e.arguments(s)})
+	if err != nil {
+		e.fail(path, err)
+		return nil
+	}
+	if field.Type == nil || isNil(value) {
+		return value
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return e.object(ctx, field.Type, value, s.Selections, path)
+	}
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		itemPath := append(append([]interface{}(nil), path...), i)
+		list[i] = e.object(ctx, field.Type, v.Index(i).Interface(), s.Selections, itemPath)
+	}
+	return list
+}
+
+// arguments returns the arguments of a selection with variables substituted
+func (e *executor) arguments(s Selection) Args {
+	args := make(Args, len(s.Arguments))
+	for name, value := range s.Arguments {
+		args[name] = e.substitute(value)
+	}
+	return args
+}
+
+// substitute replaces variables in an argument value with their values
+func (e *executor) substitute(value Value) interface{} {
+	switch v := value.(type) {
+	case Variable:
+		return e.variables[string(v)]
+	case Enum:
+		return string(v)
+	case []Value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = e.substitute(item)
+		}
+		return list
+	}
+	return value
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Args are the arguments of a field with variables substituted. Values are
+// as decoded from JSON variables or query literals.
+type Args map[string]interface{}
+
+// String returns a string argument, or def when it is absent or null
+func (a Args) String(name, def string) (string, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return def, nil
+	case string:
+		return v, nil
+	}
+	return "", fmt.Errorf("argument %q must be a string", name)
+}
+
+// Int returns an integer argument, or def when it is absent or null
+func (a Args) Int(name string, def int) (int, error) {
+	switch v := a[name].(type) {
+	case nil:
+		return def, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		// JSON variables decode as floats
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("argument %q must be an integer", name)
+}
+
+// result is an object's resolved fields, encoded in the order they were selected
+type result struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (r *result) set(key string, value interface{}) {
+	if r.values == nil {
+		r.values = make(map[string]interface{})
+	}
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+// MarshalJSON encodes the fields in selection order
+func (r *result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// costSchema serves Query.items(n) of Item { name children(n) }, counting
+// how many fields it resolves
+func costSchema(maxCost int, resolved *int) *Schema {
+	item := &Object{Name: "Item"}
+	items := &Field{Type: item, Resolve: func(_ context.Context, p Params) (interface{}, error) {
+		*resolved++
+		n, err := p.Args.Int("n", 3)
+		if err != nil {
+			return nil, err
+		}
+		return make([]struct{}, n), nil
+	}, Size: func(args Args) int {
+		n, _ := args.Int("n", 3)
+		return n
+	}}
+	item.Fields = map[string]*Field{
+		"name": {Resolve: func(context.Context, Params) (interface{}, error) {
+			*resolved++
+			return "item", nil
+		}},
+		"children": items,
+	}
+	return &Schema{Query: &Object{Name: "Query", Fields: map[string]*Field{"items": items}}, MaxCost: maxCost}
+}
+
+func TestExecuteCostBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		cost      int
+	}{
+		{"scalars", `{ items(n: 2) { name } }`, nil, 1 + 2},
+		{"default size", `{ items { name } }`, nil, 1 + 3},
+		{"nested lists", `{ items(n: 2) { name children(n: 4) { name } } }`, nil, 1 + 2*(2+4)},
+		{"variables", `query($n: Int) { items(n: $n) { name } }`, map[string]interface{}{"n": float64(5)}, 1 + 5},
+		{"empty list", `{ items(n: 0) { name } }`, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolved int
+			resp := costSchema(tt.cost, &resolved).Execute(context.Background(), Request{Query: tt.query, Variables: tt.variables})
+			if resp.Data == nil || len(resp.Errors) > 0 {
+				t.Fatalf("query at its budget of %d failed: %+v", tt.cost, resp.Errors)
+			}
+			if resolved != tt.cost {
+				t.Errorf("resolved %d fields, estimated %d", resolved, tt.cost)
+			}
+
+			if tt.cost == 1 {
+				// A budget of 0 allows any cost
+				return
+			}
+			resolved = 0
+			resp = costSchema(tt.cost-1, &resolved).Execute(context.Background(), Request{Query: tt.query, Variables: tt.variables})
+			if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "more than") {
+				t.Fatalf("query over its budget of %d ran: %+v", tt.cost-1, resp)
+			}
+			if resolved != 0 {
+				t.Errorf("rejected query resolved %d fields", resolved)
+			}
+		})
+	}
+}
+
+func TestExecuteCostDoesNotOverflow(t *testing.T) {
+	var resolved int
+	query := `{ items(n: 2147483647) { children(n: 2147483647) { children(n: 2147483647) { name } } } }`
+	resp := costSchema(1000, &resolved).Execute(context.Background(), Request{Query: query})
+	if resp.Data != nil || resolved != 0 {
+		t.Errorf("query with huge lists ran: %+v", resp.Errors)
+	}
+}
@@ -0,0 +1,402 @@
+// Package graphql implements the read-only subset of GraphQL served by the
+// /graphql endpoint: a single query operation with fields, aliases, arguments
+// and variables, e.g.
+//
+//	query Dashboard($id: String!) {
+//	  leaderboard(id: $id) {
+//	    name
+//	    top(limit: 10) { rank score player { username scores { leaderboardId rank } } }
+//	  }
+//	}
+//
+// Mutations, subscriptions, fragments and directives are rejected. Types are
+// checked at execution time by the resolvers rather than against a schema.
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxLength caps the source length of a query
+const maxLength = 16 * 1024
+
+// Operation is a parsed query
+type Operation struct {
+	Name       string
+	Variables  map[string]Value // defaults of declared variables, nil when none
+	Selections []Selection
+}
+
+// Selection is one requested field
+type Selection struct {
+	Alias      string
+	Name       string
+	Arguments  map[string]Value
+	Selections []Selection
+}
+
+// Key returns the name the field is returned under
+func (s Selection) Key() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+// Value is an argument value as written: a literal, a list or a variable
+type Value interface{}
+
+// Variable refers to a query variable by name
+type Variable string
+
+// Enum is a bare enum value such as ASC
+type Enum string
+
+// Parse parses a query document holding one query operation
+func Parse(source string) (*Operation, error) {
+	if len(source) > maxLength {
+		return nil, fmt.Errorf("query longer than %d characters", maxLength)
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	op, err := p.operation()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d; only one operation is supported", tok.text, tok.pos)
+	}
+	return op, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize splits a query into tokens, dropping whitespace, commas and comments
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case strings.IndexByte("{}()[]:$!=@", c) >= 0:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), pos: i})
+			i++
+		case c == '.':
+			if !strings.HasPrefix(source[i:], "...") {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: "...", pos: i})
+			i += 3
+		case c == '"':
+			text, n, err := readString(source[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at offset %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i += n
+		case c == '-' || isDigit(c):
+			start := i
+			i++
+			kind := tokenInt
+			for i < len(source) && (isDigit(source[i]) || strings.IndexByte(".eE+-", source[i]) >= 0) {
+				if !isDigit(source[i]) {
+					kind = tokenFloat
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: kind, text: source[start:i], pos: start})
+		case isNameStart(c):
+			start := i
+			for i < len(source) && (isNameStart(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenName, text: source[start:i], pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+// readString reads a double-quoted string, returning its value and length
+func readString(s string) (string, int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '\n':
+			return "", 0, fmt.Errorf("unterminated string")
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid string %s", s[:i+1])
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the given punctuation
+func (p *parser) accept(punct string) bool {
+	if tok := p.peek(); tok.kind == tokenPunct && tok.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.accept(punct) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at offset %d, found %q", punct, tok.pos, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenName {
+		return "", fmt.Errorf("expected a name at offset %d, found %q", tok.pos, tok.text)
+	}
+	return tok.text, nil
+}
+
+// operation parses `{ ... }` or `query Name($v: Type = default) { ... }`
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{}
+	if tok := p.peek(); tok.kind == tokenName {
+		if tok.text != "query" {
+			return nil, fmt.Errorf("%s operations are not supported", tok.text)
+		}
+		p.next()
+		if p.peek().kind == tokenName {
+			op.Name = p.next().text
+		}
+		if p.accept("(") {
+			op.Variables = make(map[string]Value)
+			for !p.accept(")") {
+				name, def, err := p.variableDefinition()
+				if err != nil {
+					return nil, err
+				}
+				op.Variables[name] = def
+			}
+		}
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selections = selections
+	return op, nil
+}
+
+// variableDefinition parses `$name: Type = default`; the type is not checked
+func (p *parser) variableDefinition() (string, Value, error) {
+	if err := p.expect("$"); err != nil {
+		return "", nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return "", nil, err
+	}
+	if err := p.typeRef(); err != nil {
+		return "", nil, err
+	}
+	if !p.accept("=") {
+		return name, nil, nil
+	}
+	def, err := p.value(true)
+	return name, def, err
+}
+
+// typeRef skips a type such as String!, [Int] or [ID!]!
+func (p *parser) typeRef() error {
+	if p.accept("[") {
+		if err := p.typeRef(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.name(); err != nil {
+		return err
+	}
+	p.accept("!")
+	return nil
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []Selection
+	for !p.accept("}") {
+		if tok := p.peek(); tok.kind == tokenPunct {
+			switch tok.text {
+			case "...":
+				return nil, fmt.Errorf("fragments are not supported (offset %d)", tok.pos)
+			case "@":
+				return nil, fmt.Errorf("directives are not supported (offset %d)", tok.pos)
+			}
+		}
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+	return selections, nil
+}
+
+// selection parses `alias: name(arg: value) { ... }`
+func (p *parser) selection() (Selection, error) {
+	var s Selection
+	name, err := p.name()
+	if err != nil {
+		return s, err
+	}
+	if p.accept(":") {
+		s.Alias = name
+		if name, err = p.name(); err != nil {
+			return s, err
+		}
+	}
+	s.Name = name
+
+	if p.accept("(") {
+		s.Arguments = make(map[string]Value)
+		for !p.accept(")") {
+			arg, err := p.name()
+			if err != nil {
+				return s, err
+			}
+			if err := p.expect(":"); err != nil {
+				return s, err
+			}
+			if s.Arguments[arg], err = p.value(false); err != nil {
+				return s, err
+			}
+		}
+	}
+	if tok := p.peek(); tok.kind == tokenPunct && tok.text == "@" {
+		return s, fmt.Errorf("directives are not supported (offset %d)", tok.pos)
+	}
+
+	if tok := p.peek(); tok.kind == tokenPunct && tok.text == "{" {
+		if s.Selections, err = p.selectionSet(); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// value parses an argument value. Defaults of variables must be constant.
+func (p *parser) value(constant bool) (Value, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenInt:
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at offset %d", tok.text, tok.pos)
+		}
+		return n, nil
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		return f, nil
+	case tokenString:
+		return tok.text, nil
+	case tokenName:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return Enum(tok.text), nil
+	case tokenPunct:
+		switch tok.text {
+		case "$":
+			if constant {
+				return nil, fmt.Errorf("variable not allowed at offset %d", tok.pos)
+			}
+			name, err := p.name()
+			return Variable(name), err
+		case "[":
+			list := []Value{}
+			for !p.accept("]") {
+				item, err := p.value(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("expected a value at offset %d, found %q", tok.pos, tok.text)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// accessLogger writes one structured log line per request
+type accessLogger struct {
+	logger   *slog.Logger
+	config   *config.AccessLogConfig
+	counters map[string]*atomic.Uint64
+	// apiKeyID names the request's registered API key, if any
+	apiKeyID func(*http.Request) string
+}
+
+// newAccessLogger creates an access logger; counters are created up front so
+// the request path never writes to the map
+func newAccessLogger(logger *slog.Logger, cfg *config.AccessLogConfig, apiKeyID func(*http.Request) string) *accessLogger {
+	counters := make(map[string]*atomic.Uint64, len(cfg.SampleRates))
+	for pattern := range cfg.SampleRates {
+		counters[pattern] = new(atomic.Uint64)
+	}
+	return &accessLogger{
+		logger:   logger,
+		config:   cfg,
+		counters: counters,
+		apiKeyID: apiKeyID,
+	}
+}
+
+// middleware returns the access log middleware
+func (a *accessLogger) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			route := chi.RouteContext(r.Context()).RoutePattern()
+			if route == "" {
+				route = r.URL.Path
+			}
+			if !a.sampled(route, status) {
+				return
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("request_bytes", r.ContentLength),
+				slog.Int("response_bytes", ww.BytesWritten()),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			}
+			if keyID := a.apiKeyID(r); keyID != "" {
+				attrs = append(attrs, slog.String("api_key_id", keyID))
+			}
+			if rate := a.config.SampleRates[route]; rate > 1 {
+				attrs = append(attrs, slog.Int("sample_rate", rate))
+			}
+
+			a.logger.LogAttrs(r.Context(), level, "http request", attrs...)
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// sampled reports whether a request should be logged. Failed requests are always logged.
+func (a *accessLogger) sampled(route string, status int) bool {
+	if status >= http.StatusBadRequest {
+		return true
+	}
+	rate := a.config.SampleRates[route]
+	if rate <= 1 {
+		return true
+	}
+	return a.counters[route].Add(1)%uint64(rate) == 1
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// API versions served under /api/v1 and /api/v2. Both share the same handlers;
+// the version only changes the response envelope.
+const (
+	apiV1 = 1
+	apiV2 = 2
+)
+
+// APIResponseV2 is the /api/v2 envelope: data on success, a coded error on
+// failure, and pagination cursors in meta
+type APIResponseV2 struct {
+	Data  interface{}   `json:"data,omitempty"`
+	Error *APIError     `json:"error,omitempty"`
+	Meta  *ResponseMeta `json:"meta,omitempty"`
+}
+
+// APIError is a machine-readable error. Details carries structured context,
+// such as a submission window's bounds. LocalizedMessage is the code's message
+// from the catalogue in the negotiated language, for showing to players.
+type APIError struct {
+	Code             string      `json:"code"`
+	Message          string      `json:"message"`
+	LocalizedMessage string      `json:"localized_message,omitempty"`
+	Details          interface{} `json:"details,omitempty"`
+}
+
+// ResponseMeta holds response metadata; NextCursor continues a paged listing
+type ResponseMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// errorCodes maps domain errors to stable v2 error codes
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{domain.ErrPlayerNotFound, "player_not_found"},
+	{domain.ErrLeaderboardNotFound, "leaderboard_not_found"},
+	{domain.ErrLeaderboardExists, "leaderboard_exists"},
+	{domain.ErrInvalidScore, "invalid_score"},
+	{domain.ErrInvalidLeaderboard, "invalid_leaderboard"},
+	{domain.ErrRateLimited, "rate_limited"},
+	{domain.ErrInvalidRequest, "invalid_request"},
+	{domain.ErrInternalError, "internal_error"},
+	{domain.ErrReadOnlyReplica, "read_only_replica"},
+	{domain.ErrVersionNotVisible, "version_not_visible"},
+	{domain.ErrSubmissionWindow, "submission_window_closed"},
+	{domain.ErrGhostNotFound, "ghost_not_found"},
+	{domain.ErrPendingNotFound, "pending_not_found"},
+	{domain.ErrAlreadyReviewed, "already_reviewed"},
+	{domain.ErrScriptNotFound, "script_not_found"},
+	{domain.ErrSnapshotNotFound, "snapshot_not_found"},
+	{domain.ErrSnapshotExists, "snapshot_exists"},
+	{domain.ErrSeasonNotFound, "season_not_found"},
+	{domain.ErrSeedingNotFound, "seeding_not_found"},
+	{domain.ErrSeedingExists, "seeding_exists"},
+	{domain.ErrDivisionNotFound, "division_not_found"},
+	{domain.ErrProfileNotFound, "profile_not_found"},
+	{domain.ErrJobNotFound, "job_not_found"},
+	{domain.ErrProfileExists, "profile_exists"},
+	{domain.ErrUnauthorized, "unauthorized"},
+	{domain.ErrPlayerMismatch, "player_mismatch"},
+	{domain.ErrQuotaExceeded, "quota_exceeded"},
+	{domain.ErrNoPlayerClaim, "no_player"},
+	{domain.ErrAdminScopeRequired, "admin_scope_required"},
+}
+
+// statusCodes are the fallback error codes for errors without a domain mapping
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          "invalid_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusConflict:            "conflict",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusServiceUnavailable:  "unavailable",
+	http.StatusInternalServerError: "internal_error",
+}
+
+// errorCode returns the v2 error code for an error written with the given status
+func errorCode(err error, status int) string {
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return mapping.code
+		}
+	}
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return "error"
+}
+
+// versionedWriter tags a response with the API version of its route group and
+// the language negotiated for its error message
+type versionedWriter struct {
+	http.ResponseWriter
+	version int
+	lang    string
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer, so
+// streaming handlers can flush
+func (vw *versionedWriter) Unwrap() http.ResponseWriter {
+	return vw.ResponseWriter
+}
+
+// withAPIVersion marks responses of a route group with its API version
+func withAPIVersion(version int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&versionedWriter{ResponseWriter: w, version: version}, r)
+		})
+	}
+}
+
+// deprecateV1 advertises on every /api/v1 response that /api/v2 supersedes it
+func deprecateV1(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		successor := strings.Replace(r.URL.Path, "/api/v1", "/api/v2", 1)
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiVersion returns the API version a response is written for
+func apiVersion(w http.ResponseWriter) int {
+	if vw, ok := w.(*versionedWriter); ok {
+		return vw.version
+	}
+	return apiV1
+}
+
+// toV2 converts a v1 response into the v2 envelope
+func toV2(resp APIResponse) APIResponseV2 {
+	if resp.Success {
+		v2 := APIResponseV2{Data: resp.Data}
+		if resp.cursor != "" {
+			v2.Meta = &ResponseMeta{NextCursor: resp.cursor}
+		}
+		return v2
+	}
+	return APIResponseV2{Error: &APIError{
+		Code:    resp.code,
+		Message: resp.Error,
+		Details: resp.Data,
+	}}
+}
+
+// writePage writes one page of a v2 listing. The cursor goes in meta.next_cursor
+// and is empty on the last page.
+func (h *Handler) writePage(w http.ResponseWriter, items interface{}, cursor string) {
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    items,
+		cursor:  cursor,
+	})
+}
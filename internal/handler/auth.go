@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/leaderboard-redis/internal/auth"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// SetAuthVerifier requires a bearer token on score submissions and restricts
+// each submission to the token's player
+func (h *Handler) SetAuthVerifier(verifier *auth.Verifier, cfg *config.AuthConfig) {
+	h.verifier = verifier
+	h.authConfig = cfg
+}
+
+// requireAuth verifies the bearer token and stores its claims in the request context
+func (h *Handler) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="leaderboard"`)
+			h.writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+			return
+		}
+
+		claims, err := h.verifier.Verify(r.Context(), strings.TrimSpace(token))
+		if err != nil {
+			h.logger.Debug("rejected bearer token", "error", err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="leaderboard", error="invalid_token"`)
+			h.writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+	})
+}
+
+// authorizePlayer checks that the authenticated token may submit for the
+// player. Without auth configured every player is allowed.
+func (h *Handler) authorizePlayer(r *http.Request, playerID string) error {
+	if h.verifier == nil {
+		return nil
+	}
+	claims := auth.ClaimsFrom(r.Context())
+	if claims == nil {
+		return domain.ErrUnauthorized
+	}
+	if h.authConfig.ServerScope != "" && claims.HasScope(h.authConfig.ServerScope) {
+		return nil
+	}
+	if claims.PlayerID == "" || claims.PlayerID != playerID {
+		return domain.ErrPlayerMismatch
+	}
+	return nil
+}
+
+// headerOnBehalfOf names the player an admin token calls the /me endpoints as
+const headerOnBehalfOf = "X-On-Behalf-Of"
+
+// resolveMe picks the player the /me endpoints act as and sets it as the
+// playerID URL parameter, so they share the player handlers. That is the
+// token's player, or with X-On-Behalf-Of the named player when the token
+// carries the admin scope. Impersonated requests are written to the admin
+// audit log with both identities before they are served, and refused when
+// the entry cannot be stored.
+func (h *Handler) resolveMe(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := auth.ClaimsFrom(r.Context())
+		if claims == nil {
+			h.writeError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+			return
+		}
+
+		playerID := claims.PlayerID
+		if target := strings.TrimSpace(r.Header.Get(headerOnBehalfOf)); target != "" {
+			if h.authConfig.AdminScope == "" || !claims.HasScope(h.authConfig.AdminScope) {
+				h.writeError(w, http.StatusForbidden, domain.ErrAdminScopeRequired)
+				return
+			}
+			entry := domain.AdminAuditEntry{
+				Actor:      claims.Subject,
+				OnBehalfOf: target,
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				RequestID:  middleware.GetReqID(r.Context()),
+			}
+			if err := h.service.RecordAdminAudit(r.Context(), entry); err != nil {
+				h.logger.Error("failed to record impersonation", "admin", claims.Subject, "player_id", target, "error", err)
+				h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+				return
+			}
+			h.logger.Info("admin acting on behalf of player", "admin", claims.Subject, "player_id", target,
+				"method", r.Method, "path", r.URL.Path)
+			playerID = target
+		}
+		if playerID == "" {
+			h.writeError(w, http.StatusForbidden, domain.ErrNoPlayerClaim)
+			return
+		}
+
+		chi.RouteContext(r.Context()).URLParams.Add("playerID", playerID)
+		next.ServeHTTP(w, r)
+	})
+}
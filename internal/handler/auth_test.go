@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/auth"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// meRouter serves /me/{leaderboardID} through resolveMe with the given
+// claims, echoing the player and leaderboard it resolved to
+func meRouter(h *Handler, claims *auth.Claims) http.Handler {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims)))
+		})
+	})
+	r.Route("/me", func(r chi.Router) {
+		r.Use(h.resolveMe)
+		r.Get("/{leaderboardID}", func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, chi.URLParam(r, "playerID")+"/"+chi.URLParam(r, "leaderboardID"))
+		})
+	})
+	return r
+}
+
+func TestResolveMe(t *testing.T) {
+	h := NewHandler(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h.authConfig = &config.AuthConfig{AdminScope: "support"}
+
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		onBehalfOf string
+		status     int
+		body       string
+		err        error
+	}{
+		{"token player", &auth.Claims{Subject: "p1", PlayerID: "p1"}, "", http.StatusOK, "p1/game1", nil},
+		{"no player claim", &auth.Claims{Subject: "svc"}, "", http.StatusForbidden, "", domain.ErrNoPlayerClaim},
+		{"impersonation without admin scope", &auth.Claims{Subject: "p1", PlayerID: "p1"}, "p2", http.StatusForbidden, "", domain.ErrAdminScopeRequired},
+		{"other scopes", &auth.Claims{Subject: "srv", Scopes: []string{"scores:any"}}, "p2", http.StatusForbidden, "", domain.ErrAdminScopeRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me/game1", nil)
+			if tt.onBehalfOf != "" {
+				req.Header.Set(headerOnBehalfOf, tt.onBehalfOf)
+			}
+			rec := httptest.NewRecorder()
+			meRouter(h, tt.claims).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.err == nil {
+				if rec.Body.String() != tt.body {
+					t.Errorf("body = %q, want %q", rec.Body.String(), tt.body)
+				}
+				return
+			}
+			var resp APIResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if resp.Error != tt.err.Error() {
+				t.Errorf("error = %q, want %q", resp.Error, tt.err)
+			}
+		})
+	}
+}
+
+func TestResolveMeWithoutAdminScopeConfigured(t *testing.T) {
+	h := NewHandler(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h.authConfig = &config.AuthConfig{}
+
+	req := httptest.NewRequest(http.MethodGet, "/me/game1", nil)
+	req.Header.Set(headerOnBehalfOf, "p2")
+	rec := httptest.NewRecorder()
+	meRouter(h, &auth.Claims{Subject: "admin", Scopes: []string{""}}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
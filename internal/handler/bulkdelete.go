@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// BulkDeletePlayers removes the players named by an ID list or matched by a
+// filter. A dry run returns what would be removed; otherwise the removal runs
+// as a background job and its status is returned with 202.
+func (h *Handler) BulkDeletePlayers(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.BulkDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	if req.DryRun {
+		preview, err := h.service.PreviewBulkDelete(r.Context(), leaderboardID, req)
+		if err != nil {
+			h.writeBulkDeleteError(w, err)
+			return
+		}
+		h.writeSuccess(w, preview)
+		return
+	}
+
+	job, err := h.service.StartBulkDelete(r.Context(), leaderboardID, req)
+	if err != nil {
+		h.writeBulkDeleteError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Data:    job,
+	})
+}
+
+// ListBulkDeleteJobs returns the bulk delete jobs started on this instance, newest first
+func (h *Handler) ListBulkDeleteJobs(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.service.ListBulkDeleteJobs())
+}
+
+// GetBulkDeleteJob returns the progress of a bulk delete job
+func (h *Handler) GetBulkDeleteJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.BulkDeleteJob(chi.URLParam(r, "jobID"))
+	if err != nil {
+		h.writeBulkDeleteError(w, err)
+		return
+	}
+	h.writeSuccess(w, job)
+}
+
+// writeBulkDeleteError maps bulk delete errors to HTTP responses
+func (h *Handler) writeBulkDeleteError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrReadOnlyReplica):
+		h.writeError(w, http.StatusForbidden, err)
+	default:
+		h.logger.Error("bulk delete failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/chaos"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// chaosRoutes registers the fault injection endpoints
+func (h *Handler) chaosRoutes(r chi.Router) {
+	r.Get("/", h.GetChaosRules)
+	r.Delete("/", h.ClearChaosRules)
+	r.Put("/{target}", h.SetChaosRule)
+	r.Delete("/{target}", h.ClearChaosRules)
+}
+
+// GetChaosRules returns the active fault injection rules
+func (h *Handler) GetChaosRules(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.faults.Rules())
+}
+
+// SetChaosRule sets the fault injection rule for redis, postgres, or broadcast
+func (h *Handler) SetChaosRule(w http.ResponseWriter, r *http.Request) {
+	target := chi.URLParam(r, "target")
+	if !chaos.IsTarget(target) {
+		h.writeError(w, http.StatusNotFound, domain.ErrInvalidRequest)
+		return
+	}
+
+	var rule chaos.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.faults.Set(target, rule)
+	h.logger.Warn("chaos rule set",
+		"target", target,
+		"latency_ms", rule.LatencyMs,
+		"error_rate", rule.ErrorRate,
+		"drop_rate", rule.DropRate,
+	)
+	h.writeSuccess(w, h.faults.Rules())
+}
+
+// ClearChaosRules removes one target's rule, or all rules when no target is given
+func (h *Handler) ClearChaosRules(w http.ResponseWriter, r *http.Request) {
+	target := chi.URLParam(r, "target")
+	h.faults.Clear(target)
+	h.logger.Warn("chaos rules cleared", "target", target)
+	h.writeSuccess(w, h.faults.Rules())
+}
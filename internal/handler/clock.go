@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// AdvanceClockRequest moves the simulated clock forward by a duration or to an absolute time
+type AdvanceClockRequest struct {
+	Duration string     `json:"duration,omitempty"`
+	Time     *time.Time `json:"time,omitempty"`
+}
+
+// clockRoutes registers the simulated clock endpoints
+func (h *Handler) clockRoutes(r chi.Router) {
+	r.Get("/", h.GetClock)
+	r.Post("/advance", h.AdvanceClock)
+}
+
+// GetClock returns the simulated time
+func (h *Handler) GetClock(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, map[string]time.Time{"now": h.simClock.Now()})
+}
+
+// AdvanceClock fast-forwards the simulated clock, firing any scheduled work in between
+func (h *Handler) AdvanceClock(w http.ResponseWriter, r *http.Request) {
+	var req AdvanceClockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	switch {
+	case req.Time != nil:
+		h.simClock.Set(*req.Time)
+	case req.Duration != "":
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d < 0 {
+			h.writeError(w, http.StatusBadRequest, errors.New("duration must be a positive Go duration such as 24h"))
+			return
+		}
+		h.simClock.Advance(d)
+	default:
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	h.logger.Info("simulated clock moved", "now", h.simClock.Now())
+	h.writeSuccess(w, map[string]time.Time{"now": h.simClock.Now()})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// errUnsupportedEncoding is returned for request bodies in an encoding other than gzip or zstd
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// errBodyTooLarge is returned when a decompressed request body exceeds its cap
+var errBodyTooLarge = errors.New("request body too large after decompression")
+
+// SetRequestBodyLimits sets the cap applied to decompressed request bodies
+func (h *Handler) SetRequestBodyLimits(cfg *config.RequestBodyConfig) {
+	h.requestBody = cfg
+}
+
+// decompressBody transparently decodes gzip and zstd request bodies, capping
+// the decompressed size so a small payload cannot expand without bound
+func (h *Handler) decompressBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		if encoding == "" || encoding == "identity" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var maxBytes int64 = 32 << 20
+		if h.requestBody != nil {
+			maxBytes = h.requestBody.MaxDecompressedBytes
+		}
+
+		var body io.ReadCloser
+		switch encoding {
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+				return
+			}
+			defer gz.Close()
+			body = gz
+		case "zstd":
+			zr, err := zstd.NewReader(r.Body, zstd.WithDecoderMaxMemory(uint64(maxBytes)))
+			if err != nil {
+				h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+				return
+			}
+			defer zr.Close()
+			body = zr.IOReadCloser()
+		default:
+			h.writeError(w, http.StatusUnsupportedMediaType, errUnsupportedEncoding)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, body, maxBytes)
+		r.Header.Del("Content-Encoding")
+		r.ContentLength = -1
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isBodyTooLarge reports whether a body read failed on the decompressed size cap
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// notFoundResources lists every resource a NotFoundError can name
+var notFoundResources = []string{
+	domain.ResourceLeaderboard, domain.ResourcePlayer, domain.ResourceGhost,
+	domain.ResourcePending, domain.ResourceScript, domain.ResourceSnapshot,
+	domain.ResourceSeason, domain.ResourceSeeding, domain.ResourceDivision,
+	domain.ResourceProfile, domain.ResourceJob,
+}
+
+// wrap nests err under two layers of context, as a repository error
+// surfaces through the service
+func wrap(err error) error {
+	return fmt.Errorf("service: %w", fmt.Errorf("repository: %w", err))
+}
+
+func TestErrorWritersMapWrappedErrors(t *testing.T) {
+	h := NewHandler(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	writers := map[string]func(http.ResponseWriter, error){
+		"bulk delete": h.writeBulkDeleteError,
+		"ghost":       h.writeGhostError,
+		"player":      h.writePlayerError,
+		"review":      h.writeReviewError,
+		"script":      h.writeScriptError,
+		"seeding":     h.writeSeedingError,
+		"snapshot":    h.writeSnapshotError,
+	}
+
+	type errorCase struct {
+		name   string
+		err    error
+		status int
+	}
+	cases := []errorCase{
+		{"validation", wrap(domain.NewValidationError(domain.ErrInvalidRequest, "limit", "too large")), http.StatusBadRequest},
+		{"invalid request", wrap(domain.ErrInvalidRequest), http.StatusBadRequest},
+		{"unexpected", wrap(errors.New("connection reset")), http.StatusInternalServerError},
+	}
+	for _, resource := range notFoundResources {
+		cases = append(cases, errorCase{resource + " not found", wrap(domain.NewNotFoundError(resource, "id-1")), http.StatusNotFound})
+	}
+
+	for name, write := range writers {
+		for _, tc := range cases {
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				write(rec, tc.err)
+				if rec.Code != tc.status {
+					t.Errorf("status = %d, want %d", rec.Code, tc.status)
+				}
+			})
+		}
+	}
+}
+
+func TestWriteErrorAttachesTypedDetails(t *testing.T) {
+	h := NewHandler(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]interface{}
+	}{
+		{
+			"not found",
+			wrap(domain.NewNotFoundError(domain.ResourceSnapshot, "week-1")),
+			map[string]interface{}{"resource": "snapshot", "id": "week-1"},
+		},
+		{
+			"validation",
+			wrap(domain.NewValidationError(domain.ErrInvalidScore, "score", "out of range")),
+			map[string]interface{}{"fields": []interface{}{map[string]interface{}{"field": "score", "message": "out of range"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.writeError(rec, http.StatusBadRequest, tt.err)
+
+			var resp struct {
+				Success bool                   `json:"success"`
+				Data    map[string]interface{} `json:"data"`
+				Error   string                 `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if resp.Success || resp.Error != tt.err.Error() {
+				t.Errorf("response = %+v, want the error %q", resp, tt.err)
+			}
+			got, _ := json.Marshal(resp.Data)
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Errorf("data = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestErrorCodeMatchesWrappedErrors(t *testing.T) {
+	for _, mapping := range errorCodes {
+		if got := errorCode(wrap(mapping.err), http.StatusInternalServerError); got != mapping.code {
+			t.Errorf("errorCode(wrapped %v) = %q, want %q", mapping.err, got, mapping.code)
+		}
+	}
+	for _, resource := range notFoundResources {
+		err := wrap(domain.NewNotFoundError(resource, "id-1"))
+		if got := errorCode(err, http.StatusNotFound); got == statusCodes[http.StatusNotFound] {
+			t.Errorf("errorCode(%v) fell back to %q", err, got)
+		}
+	}
+}
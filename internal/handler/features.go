@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/features"
+)
+
+// featureRoutes registers the feature flag endpoints
+func (h *Handler) featureRoutes(r chi.Router) {
+	r.Get("/", h.GetFeatures)
+	r.Put("/{flag}", h.SetFeature)
+	r.Delete("/{flag}", h.ClearFeature)
+}
+
+// SetFeatures enables the feature flag admin endpoints backed by the provider
+func (h *Handler) SetFeatures(flags *features.Provider) {
+	h.features = flags
+}
+
+// GetFeatures lists the feature flags with their rules; ?leaderboard_id=
+// also reports whether each flag is on for that leaderboard
+func (h *Handler) GetFeatures(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.features.Status(r.URL.Query().Get("leaderboard_id")))
+}
+
+// SetFeature overrides a flag's rule on every instance
+func (h *Handler) SetFeature(w http.ResponseWriter, r *http.Request) {
+	flag := chi.URLParam(r, "flag")
+	if !features.IsFlag(flag) {
+		h.writeError(w, http.StatusNotFound, domain.ErrInvalidRequest)
+		return
+	}
+
+	var rule features.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+	if err := rule.Validate(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.features.Set(r.Context(), flag, rule); err != nil {
+		h.logger.Error("failed to set feature flag", "flag", flag, "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+	h.logger.Warn("feature flag overridden",
+		"flag", flag,
+		"enabled", rule.Enabled,
+		"percent", rule.Percent,
+		"tenants", rule.Tenants,
+		"leaderboards", rule.Leaderboards,
+		"exclude", rule.Exclude,
+	)
+	h.writeSuccess(w, h.features.Status(""))
+}
+
+// ClearFeature removes a flag's override, returning it to its configured rule
+func (h *Handler) ClearFeature(w http.ResponseWriter, r *http.Request) {
+	flag := chi.URLParam(r, "flag")
+	if !features.IsFlag(flag) {
+		h.writeError(w, http.StatusNotFound, domain.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.features.Clear(r.Context(), flag); err != nil {
+		h.logger.Error("failed to clear feature flag", "flag", flag, "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+	h.logger.Warn("feature flag override cleared", "flag", flag)
+	h.writeSuccess(w, h.features.Status(""))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// GetFeed returns a leaderboard's notable events, newest first, paginated with ?before=<id>
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	page, err := h.service.GetFeed(r.Context(), leaderboardID, r.URL.Query().Get("before"), limit)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidRequest) {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error("failed to get feed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	// v2 moves the cursor into the envelope
+	if apiVersion(w) >= apiV2 {
+		h.writePage(w, page.Events, page.NextBefore)
+		return
+	}
+	h.writeSuccess(w, page)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// SetGhost adds or replaces a ghost entry on a leaderboard
+func (h *Handler) SetGhost(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.CreateGhostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	ghost, err := h.service.SetGhost(r.Context(), leaderboardID, req)
+	if err != nil {
+		h.writeGhostError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    ghost,
+	})
+}
+
+// ListGhosts returns a leaderboard's ghost entries
+func (h *Handler) ListGhosts(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	ghosts, err := h.service.ListGhosts(r.Context(), leaderboardID)
+	if err != nil {
+		h.writeGhostError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, ghosts)
+}
+
+// RemoveGhost deletes a ghost entry
+func (h *Handler) RemoveGhost(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	ghostID := chi.URLParam(r, "ghostID")
+	if leaderboardID == "" || ghostID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.service.RemoveGhost(r.Context(), leaderboardID, ghostID); err != nil {
+		h.writeGhostError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, map[string]string{"status": "removed"})
+}
+
+// writeGhostError maps ghost operation errors to HTTP responses
+func (h *Handler) writeGhostError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrReadOnlyReplica):
+		h.writeError(w, http.StatusForbidden, err)
+	default:
+		h.logger.Error("ghost operation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
@@ -0,0 +1,355 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/graphql"
+	"github.com/leaderboard-redis/internal/service"
+)
+
+// GraphQL query limits: the deepest nesting accepted, the most fields a query
+// may be estimated to resolve and the largest request body. Lists of boards,
+// whose length the query does not state, are assumed to hold graphQLBoards.
+const (
+	graphQLMaxDepth = 8
+	graphQLMaxCost  = 10000
+	graphQLMaxBody  = 64 << 10
+	graphQLBoards   = 20
+)
+
+// GraphQL runs a read-only query against the leaderboard schema, so dashboards
+// can fetch a board, its top entries, their profiles and their standings on
+// other boards in one round trip. Queries are POSTed as JSON or sent as GET
+// ?query= with optional ?variables= JSON.
+func (h *Handler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				h.writeJSON(w, http.StatusBadRequest, graphQLError("variables must be a JSON object"))
+				return
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphQLMaxBody)).Decode(&req); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, graphQLError("body must be a JSON object with a query"))
+		return
+	}
+	if req.Query == "" {
+		h.writeJSON(w, http.StatusBadRequest, graphQLError("missing query"))
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphQLLoaderKey{}, &graphQLLoader{h: h})
+	resp := h.graphQLSchema().Execute(ctx, req)
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	h.writeJSON(w, status, resp)
+}
+
+func graphQLError(message string) *graphql.Response {
+	return &graphql.Response{Errors: []graphql.Error{{Message: message}}}
+}
+
+// graphQLLoaderKey carries the request's graphQLLoader in its context
+type graphQLLoaderKey struct{}
+
+// graphQLLoader caches the profiles and cross-board standings loaded while
+// executing one query, since the same player often appears on several boards
+type graphQLLoader struct {
+	h *Handler
+
+	mu       sync.Mutex
+	profiles map[string]*domain.Player
+	scores   map[string][]domain.PlayerScore
+}
+
+func loaderFrom(ctx context.Context) *graphQLLoader {
+	return ctx.Value(graphQLLoaderKey{}).(*graphQLLoader)
+}
+
+// player returns a player's profile, or nil when the player has none
+func (l *graphQLLoader) player(ctx context.Context, playerID string) (*domain.Player, error) {
+	l.mu.Lock()
+	player, ok := l.profiles[playerID]
+	l.mu.Unlock()
+	if ok {
+		return player, nil
+	}
+
+	player, err := l.h.service.GetPlayer(ctx, playerID)
+	if err != nil && !errors.Is(err, domain.ErrProfileNotFound) {
+		return nil, l.h.graphQLFailure(err)
+	}
+	l.mu.Lock()
+	if l.profiles == nil {
+		l.profiles = make(map[string]*domain.Player)
+	}
+	l.profiles[playerID] = player
+	l.mu.Unlock()
+	return player, nil
+}
+
+// playerScores returns a player's standings on every board
+func (l *graphQLLoader) playerScores(ctx context.Context, playerID string) ([]domain.PlayerScore, error) {
+	l.mu.Lock()
+	scores, ok := l.scores[playerID]
+	l.mu.Unlock()
+	if ok {
+		return scores, nil
+	}
+
+	scores, err := l.h.service.GetPlayerScores(ctx, playerID)
+	if err != nil {
+		return nil, l.h.graphQLFailure(err)
+	}
+	l.mu.Lock()
+	if l.scores == nil {
+		l.scores = make(map[string][]domain.PlayerScore)
+	}
+	l.scores[playerID] = scores
+	l.mu.Unlock()
+	return scores, nil
+}
+
+// graphQLFailure returns the error reported for a failed field: not-found and
+// validation errors as they are, anything else logged and hidden
+func (h *Handler) graphQLFailure(err error) error {
+	var invalid *domain.ValidationError
+	if domain.IsNotFoundError(err) || errors.As(err, &invalid) || errors.Is(err, domain.ErrInvalidRequest) {
+		return err
+	}
+	h.logger.Error("graphql field failed", "error", err)
+	return domain.ErrInternalError
+}
+
+// graphQLSchema builds the query schema, once per handler
+func (h *Handler) graphQLSchema() *graphql.Schema {
+	h.graphQLOnce.Do(func() {
+		h.graphQL = newGraphQLSchema(h)
+	})
+	return h.graphQL
+}
+
+// newGraphQLSchema defines the readable types:
+//
+//	Query       leaderboard(id) leaderboards player(id)
+//	Leaderboard id name sortOrder resetPeriod updateMode maxEntries playerCount
+//	            top(limit) range(start, end) around(playerId, count) entry(playerId)
+//	Entry       rank playerId score username avatarUrl provisional isGhost player
+//	Player      id username avatarUrl createdAt updatedAt scores
+//	PlayerScore leaderboardId rank score leaderboard
+//
+// Missing leaderboards, players and profiles resolve to null rather than errors.
+func newGraphQLSchema(h *Handler) *graphql.Schema {
+	leaderboard := &graphql.Object{Name: "Leaderboard"}
+	entry := &graphql.Object{Name: "Entry"}
+	player := &graphql.Object{Name: "Player"}
+	playerScore := &graphql.Object{Name: "PlayerScore"}
+
+	getLeaderboard := func(ctx context.Context, leaderboardID string) (interface{}, error) {
+		lb, err := h.service.GetLeaderboard(ctx, leaderboardID)
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, h.graphQLFailure(err)
+		}
+		return lb, nil
+	}
+	entries := func(entries []domain.LeaderboardEntry, err error) (interface{}, error) {
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, h.graphQLFailure(err)
+		}
+		return entries, nil
+	}
+
+	leaderboard.Fields = map[string]*graphql.Field{
+		"id":          leaderboardField(func(lb *domain.LeaderboardConfig) interface{} { return lb.ID }),
+		"name":        leaderboardField(func(lb *domain.LeaderboardConfig) interface{} { return lb.Name }),
+		"sortOrder":   leaderboardField(func(lb *domain.LeaderboardConfig) interface{} { return lb.SortOrder }),
+		"resetPeriod": leaderboardField(func(lb *domain.LeaderboardConfig) interface{} { return lb.ResetPeriod }),
+		"updateMode":  leaderboardField(func(lb *domain.LeaderboardConfig) interface{} { return lb.UpdateMode }),
+		"maxEntries":  leaderboardField(func(lb *domain.LeaderboardConfig) interface{} { return lb.MaxEntries }),
+		"playerCount": {Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			count, err := h.service.GetCount(ctx, p.Source.(*domain.LeaderboardConfig).ID)
+			if err != nil {
+				return nil, h.graphQLFailure(err)
+			}
+			return count, nil
+		}},
+		"top": {Type: entry, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			limit, err := p.Args.Int("limit", 10)
+			if err != nil {
+				return nil, err
+			}
+			return entries(h.service.GetTopN(ctx, p.Source.(*domain.LeaderboardConfig).ID, limit))
+		}, Size: func(args graphql.Args) int {
+			limit, _ := args.Int("limit", 10)
+			return h.service.ListLimit(limit)
+		}},
+		"range": {Type: entry, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			start, err := p.Args.Int("start", 1)
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.Args.Int("end", start+9)
+			if err != nil {
+				return nil, err
+			}
+			return entries(h.service.GetRange(ctx, p.Source.(*domain.LeaderboardConfig).ID, start, end))
+		}, Size: func(args graphql.Args) int {
+			start, _ := args.Int("start", 1)
+			end, _ := args.Int("end", start+9)
+			return h.service.ListLimit(max(end-max(start, 0), 0) + 1)
+		}},
+		"around": {Type: entry, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			playerID, err := p.Args.String("playerId", "")
+			if err != nil {
+				return nil, err
+			}
+			count, err := p.Args.Int("count", 5)
+			if err != nil {
+				return nil, err
+			}
+			result, err := h.service.GetAroundPlayer(ctx, p.Source.(*domain.LeaderboardConfig).ID, playerID, count)
+			if errors.Is(err, domain.ErrPlayerNotFound) {
+				return nil, nil
+			}
+			return entries(result, err)
+		}, Size: func(args graphql.Args) int {
+			count, _ := args.Int("count", 5)
+			return 2*service.AroundCount(count) + 1
+		}},
+		"entry": {Type: entry, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			playerID, err := p.Args.String("playerId", "")
+			if err != nil {
+				return nil, err
+			}
+			result, err := h.service.GetPlayerRank(ctx, p.Source.(*domain.LeaderboardConfig).ID, playerID)
+			if errors.Is(err, domain.ErrPlayerNotFound) || errors.Is(err, domain.ErrLeaderboardNotFound) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, h.graphQLFailure(err)
+			}
+			return result, nil
+		}},
+	}
+
+	entry.Fields = map[string]*graphql.Field{
+		"rank":        entryField(func(e domain.LeaderboardEntry) interface{} { return e.Rank }),
+		"playerId":    entryField(func(e domain.LeaderboardEntry) interface{} { return e.PlayerID }),
+		"score":       entryField(func(e domain.LeaderboardEntry) interface{} { return e.Score }),
+		"username":    entryField(func(e domain.LeaderboardEntry) interface{} { return e.Username }),
+		"avatarUrl":   entryField(func(e domain.LeaderboardEntry) interface{} { return e.AvatarURL }),
+		"provisional": entryField(func(e domain.LeaderboardEntry) interface{} { return e.Provisional }),
+		"isGhost":     entryField(func(e domain.LeaderboardEntry) interface{} { return e.IsGhost }),
+		"player": {Type: player, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			e := asEntry(p.Source)
+			if e.IsGhost {
+				return nil, nil
+			}
+			return loaderFrom(ctx).player(ctx, e.PlayerID)
+		}},
+	}
+
+	player.Fields = map[string]*graphql.Field{
+		"id":        playerField(func(pl *domain.Player) interface{} { return pl.ID }),
+		"username":  playerField(func(pl *domain.Player) interface{} { return pl.Username }),
+		"avatarUrl": playerField(func(pl *domain.Player) interface{} { return pl.AvatarURL }),
+		"createdAt": playerField(func(pl *domain.Player) interface{} { return pl.CreatedAt }),
+		"updatedAt": playerField(func(pl *domain.Player) interface{} { return pl.UpdatedAt }),
+		"scores": {Type: playerScore, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			return loaderFrom(ctx).playerScores(ctx, p.Source.(*domain.Player).ID)
+		}, Size: boardsSize},
+	}
+
+	playerScore.Fields = map[string]*graphql.Field{
+		"leaderboardId": playerScoreField(func(s domain.PlayerScore) interface{} { return s.LeaderboardID }),
+		"rank":          playerScoreField(func(s domain.PlayerScore) interface{} { return s.Rank }),
+		"score":         playerScoreField(func(s domain.PlayerScore) interface{} { return s.Score }),
+		"leaderboard": {Type: leaderboard, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			return getLeaderboard(ctx, p.Source.(domain.PlayerScore).LeaderboardID)
+		}},
+	}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"leaderboard": {Type: leaderboard, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			leaderboardID, err := p.Args.String("id", "")
+			if err != nil {
+				return nil, err
+			}
+			return getLeaderboard(ctx, leaderboardID)
+		}},
+		"leaderboards": {Type: leaderboard, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			leaderboards, err := h.service.ListLeaderboards(ctx)
+			if err != nil {
+				return nil, h.graphQLFailure(err)
+			}
+			list := make([]*domain.LeaderboardConfig, len(leaderboards))
+			for i := range leaderboards {
+				list[i] = &leaderboards[i]
+			}
+			return list, nil
+		}, Size: boardsSize},
+		"player": {Type: player, Resolve: func(ctx context.Context, p graphql.Params) (interface{}, error) {
+			playerID, err := p.Args.String("id", "")
+			if err != nil {
+				return nil, err
+			}
+			return loaderFrom(ctx).player(ctx, playerID)
+		}},
+	}}
+
+	return &graphql.Schema{Query: query, MaxDepth: graphQLMaxDepth, MaxCost: graphQLMaxCost}
+}
+
+// boardsSize is the size assumed for lists with one value per leaderboard
+func boardsSize(graphql.Args) int {
+	return graphQLBoards
+}
+
+func leaderboardField(get func(*domain.LeaderboardConfig) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: func(_ context.Context, p graphql.Params) (interface{}, error) {
+		return get(p.Source.(*domain.LeaderboardConfig)), nil
+	}}
+}
+
+func entryField(get func(domain.LeaderboardEntry) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: func(_ context.Context, p graphql.Params) (interface{}, error) {
+		return get(asEntry(p.Source)), nil
+	}}
+}
+
+func playerField(get func(*domain.Player) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: func(_ context.Context, p graphql.Params) (interface{}, error) {
+		return get(p.Source.(*domain.Player)), nil
+	}}
+}
+
+func playerScoreField(get func(domain.PlayerScore) interface{}) *graphql.Field {
+	return &graphql.Field{Resolve: func(_ context.Context, p graphql.Params) (interface{}, error) {
+		return get(p.Source.(domain.PlayerScore)), nil
+	}}
+}
+
+// asEntry accepts entries from lists and from single lookups
+func asEntry(source interface{}) domain.LeaderboardEntry {
+	if e, ok := source.(*domain.LeaderboardEntry); ok {
+		return *e
+	}
+	return source.(domain.LeaderboardEntry)
+}
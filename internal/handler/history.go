@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// decryptTokenHeader carries the token that grants the plaintext of sensitive metadata fields
+const decryptTokenHeader = "X-Decrypt-Token"
+
+// canDecrypt reports whether the request may read sensitive metadata fields in the clear
+func (h *Handler) canDecrypt(r *http.Request) bool {
+	return h.service.CanDecrypt(r.Header.Get(decryptTokenHeader))
+}
+
+// GetHistory returns a page of a leaderboard's recorded score events
+func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	// Newest first unless order=asc
+	descending := true
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		descending = false
+	default:
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	page, err := h.service.GetHistory(r.Context(), leaderboardID, query.Get("player_id"), query.Get("cursor"), limit, descending)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidRequest) {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error("failed to get history", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+	if h.canDecrypt(r) {
+		h.service.DecryptEvents(page.Events)
+	}
+	if wantsAnonymized(r) {
+		if err := h.service.AnonymizeEvents(page.Events); err != nil {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// v2 moves the cursor into the envelope
+	if apiVersion(w) >= apiV2 {
+		h.writePage(w, page.Events, page.NextCursor)
+		return
+	}
+	h.writeSuccess(w, page)
+}
@@ -1,39 +1,149 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/leaderboard-redis/internal/auth"
+	"github.com/leaderboard-redis/internal/chaos"
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
 	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/features"
+	"github.com/leaderboard-redis/internal/graphql"
+	"github.com/leaderboard-redis/internal/i18n"
+	"github.com/leaderboard-redis/internal/jsonenc"
+	"github.com/leaderboard-redis/internal/logging"
+	"github.com/leaderboard-redis/internal/migration"
+	"github.com/leaderboard-redis/internal/ratelimit"
+	"github.com/leaderboard-redis/internal/replication"
 	"github.com/leaderboard-redis/internal/service"
+	"github.com/leaderboard-redis/internal/telemetry"
 	"github.com/leaderboard-redis/internal/websocket"
+	"github.com/leaderboard-redis/internal/worker"
+)
+
+var (
+	errSyncUnavailable = errors.New("sync worker not configured")
+	errNoStartupReport = errors.New("no startup reconciliation has run")
+	errLogUnavailable  = errors.New("log manager not configured")
 )
 
 // Handler provides HTTP handlers for the leaderboard API
 type Handler struct {
-	service *service.LeaderboardService
-	hub     *websocket.Hub
-	logger  *slog.Logger
+	service     *service.LeaderboardService
+	hub         *websocket.Hub
+	syncWorker  *worker.SyncWorker
+	logManager  *logging.Manager
+	accessLog   *config.AccessLogConfig
+	requestBody *config.RequestBodyConfig
+	reporter    telemetry.Reporter
+	faults      *chaos.Injector
+	simClock    *clock.Simulated
+	replicator  *replication.Publisher
+	replica     *config.ReplicationConfig
+	encoder     jsonenc.Encoder
+	catalogue   *i18n.Catalogue
+	verifier    *auth.Verifier
+	authConfig  *config.AuthConfig
+	limiter     *ratelimit.Limiter
+	forwarder   *migration.Forwarder
+	comparer    *worker.CompareWorker
+	staleness   *worker.StalenessWorker
+	features    *features.Provider
+	logger      *slog.Logger
+
+	graphQLOnce sync.Once
+	graphQL     *graphql.Schema
 }
 
 // NewHandler creates a new HTTP handler
 func NewHandler(service *service.LeaderboardService, hub *websocket.Hub, logger *slog.Logger) *Handler {
 	return &Handler{
-		service: service,
-		hub:     hub,
-		logger:  logger,
+		service:  service,
+		hub:      hub,
+		reporter: telemetry.Nop(),
+		encoder:  jsonenc.Default(),
+		logger:   logger,
 	}
 }
 
+// SetJSONEncoder sets the encoder used for every JSON response
+func (h *Handler) SetJSONEncoder(encoder jsonenc.Encoder) {
+	h.encoder = encoder
+}
+
+// SetSyncWorker sets the sync worker reported by the admin endpoints
+func (h *Handler) SetSyncWorker(syncWorker *worker.SyncWorker) {
+	h.syncWorker = syncWorker
+}
+
+// SetLogManager sets the log manager whose levels the admin endpoints control
+func (h *Handler) SetLogManager(logManager *logging.Manager) {
+	h.logManager = logManager
+}
+
+// SetAccessLog sets the access log configuration used by Router
+func (h *Handler) SetAccessLog(cfg *config.AccessLogConfig) {
+	h.accessLog = cfg
+}
+
+// SetErrorReporter sets the reporter that receives recovered panics
+func (h *Handler) SetErrorReporter(reporter telemetry.Reporter) {
+	h.reporter = reporter
+}
+
+// SetFaultInjector enables the chaos admin endpoints backed by the injector
+func (h *Handler) SetFaultInjector(faults *chaos.Injector) {
+	h.faults = faults
+}
+
+// SetSimulatedClock enables the clock admin endpoints for simulation mode
+func (h *Handler) SetSimulatedClock(c *clock.Simulated) {
+	h.simClock = c
+}
+
+// SetReplicationPublisher enables the replication status endpoint on a primary region
+func (h *Handler) SetReplicationPublisher(publisher *replication.Publisher) {
+	h.replicator = publisher
+}
+
+// SetReplicaApply enables the replication apply endpoint on a replica region
+func (h *Handler) SetReplicaApply(cfg *config.ReplicationConfig) {
+	h.replica = cfg
+}
+
+// SetMessageCatalogue enables localized v2 error messages and the catalogue endpoint
+func (h *Handler) SetMessageCatalogue(catalogue *i18n.Catalogue) {
+	h.catalogue = catalogue
+}
+
 // APIResponse represents a standard API response
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+
+	// Only rendered by the v2 envelope
+	code   string
+	cursor string
+}
+
+// recordPeerAddr keeps the connection address for the WebSocket connection
+// limits before RealIP replaces RemoteAddr with forwarded headers
+func recordPeerAddr(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(websocket.WithPeerAddr(r.Context(), r.RemoteAddr)))
+	})
 }
 
 // Router creates and configures the HTTP router
@@ -42,9 +152,12 @@ func (h *Handler) Router() http.Handler {
 
 	// Middleware
 	r.Use(middleware.RequestID)
+	r.Use(recordPeerAddr)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	if h.accessLog != nil && h.accessLog.Enabled {
+		r.Use(newAccessLogger(h.logger, h.accessLog, h.apiKeyID).middleware)
+	}
+	r.Use(h.recoverer)
 	r.Use(middleware.Compress(5))
 	r.Use(corsMiddleware)
 
@@ -55,11 +168,45 @@ func (h *Handler) Router() http.Handler {
 	// WebSocket endpoint
 	r.Get("/ws", h.HandleWebSocket)
 
-	// API v1 routes
+	// Nested dashboard reads in one round trip, counted as reads when rate limited
+	r.Group(func(r chi.Router) {
+		if h.limiter != nil {
+			r.Use(h.limitQueries)
+		}
+		r.Get("/graphql", h.GraphQL)
+		r.Post("/graphql", h.GraphQL)
+	})
+
+	// API v1 stays stable; v2 serves the same handlers with the newer envelope
 	r.Route("/api/v1", func(r chi.Router) {
-		// Score operations
+		r.Use(deprecateV1)
+		h.apiRoutes(r)
+	})
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Use(withAPIVersion(apiV2), h.negotiateLanguage)
+		h.apiRoutes(r)
+	})
+
+	return r
+}
+
+// apiRoutes registers the API routes shared by every API version
+func (h *Handler) apiRoutes(r chi.Router) {
+	// Score operations, limited to the token's player when auth is enabled
+	r.Group(func(r chi.Router) {
+		if h.verifier != nil {
+			r.Use(h.requireAuth)
+		}
 		r.Post("/scores", h.SubmitScore)
-		r.Post("/scores/batch", h.SubmitScoreBatch)
+		r.With(h.decompressBody).Post("/scores/batch", h.SubmitScoreBatch)
+		r.Post("/scores:validate", h.ValidateScore)
+	})
+
+	// Reads share a per-client limit when rate limiting is enabled
+	r.Group(func(r chi.Router) {
+		if h.limiter != nil {
+			r.Use(h.limitReads)
+		}
 
 		// Leaderboard operations
 		r.Route("/leaderboards", func(r chi.Router) {
@@ -71,6 +218,42 @@ func (h *Handler) Router() http.Handler {
 				r.Delete("/", h.DeleteLeaderboard)
 				r.Post("/reset", h.ResetLeaderboard)
 				r.Get("/stats", h.GetStats)
+				r.Get("/view", h.GetView)
+				r.Get("/feed", h.GetFeed)
+				r.Get("/stream", h.StreamLeaderboard)
+				r.Get("/history", h.GetHistory)
+				r.Put("/shadow", h.SetShadow)
+				r.Delete("/shadow", h.RemoveShadow)
+
+				// System-owned ghost entries
+				r.Get("/ghosts", h.ListGhosts)
+				r.Post("/ghosts", h.SetGhost)
+				r.Delete("/ghosts/{ghostID}", h.RemoveGhost)
+
+				// Versioned Lua scoring scripts
+				r.Get("/scripts", h.ListScripts)
+				r.Post("/scripts", h.CreateScript)
+				r.Put("/scripts/active", h.ActivateScript)
+
+				// Immutable standings snapshots for prize payouts and disputes
+				r.Post("/freeze", h.FreezeLeaderboard)
+				r.Get("/snapshots", h.ListSnapshots)
+				r.Get("/snapshots/{name}", h.GetSnapshot)
+				r.Get("/diff", h.DiffSnapshots)
+				r.Post("/payouts", h.ComputePayouts)
+
+				// Final standings of past seasons, archived on reset
+				r.Get("/seasons", h.ListSeasons)
+				r.Get("/seasons/{season}", h.GetSeason)
+
+				// Divisions and brackets seeded from the standings
+				r.Post("/seedings", h.CreateSeeding)
+				r.Get("/seedings", h.ListSeedings)
+				r.Get("/seedings/{name}", h.GetSeeding)
+				r.Get("/seedings/{name}/divisions/{division}", h.GetDivision)
+
+				// Submissions held for manual review
+				r.Get("/pending", h.ListPendingScores)
 
 				// Rankings
 				r.Get("/top", h.GetTop)
@@ -81,19 +264,102 @@ func (h *Handler) Router() http.Handler {
 			})
 		})
 
-		// WebSocket info endpoint
-		r.Get("/ws/stats", h.GetWebSocketStats)
+		// Player profiles shown on leaderboard entries
+		r.Route("/players", func(r chi.Router) {
+			r.Post("/", h.CreatePlayer)
+			r.Get("/", h.ListPlayers)
+			r.Get("/{playerID}", h.GetPlayer)
+			r.Patch("/{playerID}", h.UpdatePlayer)
+			r.Delete("/{playerID}", h.DeletePlayer)
+		})
+
+		// Cross-leaderboard summary for dashboards
+		r.Get("/overview", h.GetOverview)
+
+		// The token's own player, or with X-On-Behalf-Of the player an admin
+		// is troubleshooting for
+		if h.verifier != nil {
+			r.Route("/me", func(r chi.Router) {
+				r.Use(h.requireAuth, h.resolveMe)
+				r.Get("/", h.GetPlayer)
+				r.Get("/scores", h.GetPlayerScores)
+				r.Get("/leaderboards/{leaderboardID}", h.GetPlayerRank)
+				r.Get("/leaderboards/{leaderboardID}/around", h.GetAroundPlayer)
+			})
+		}
 	})
 
-	return r
+	// Error message translations for clients to sync
+	if h.catalogue != nil {
+		r.Get("/errors/catalogue", h.GetErrorCatalogue)
+	}
+
+	// WebSocket info endpoint
+	r.Get("/ws/stats", h.GetWebSocketStats)
+
+	// Changes streamed from the primary region are only accepted by replicas
+	if h.replica != nil {
+		r.Post("/replication/apply", h.ApplyReplication)
+	}
+
+	// Admin operations
+	r.Route("/admin", func(r chi.Router) {
+		r.Get("/sync/status", h.GetSyncStatus)
+		r.Post("/sync/leaderboards/{leaderboardID}", h.SyncLeaderboard)
+		r.Get("/startup-report", h.GetStartupReport)
+		r.Get("/log-level", h.GetLogLevels)
+		r.Put("/log-level", h.SetLogLevel)
+		r.Post("/pending/{pendingID}/approve", h.ApprovePendingScore)
+		r.Post("/pending/{pendingID}/reject", h.RejectPendingScore)
+		r.Get("/dedup", h.GetDedupStats)
+
+		// Bulk removal of players, such as fake accounts after an attack
+		r.With(h.decompressBody).Post("/leaderboards/{leaderboardID}/players:bulkDelete", h.BulkDeletePlayers)
+		r.Get("/bulk-deletes", h.ListBulkDeleteJobs)
+		r.Get("/bulk-deletes/{jobID}", h.GetBulkDeleteJob)
+
+		// Fault injection is only routed when chaos testing is enabled
+		if h.faults != nil {
+			r.Route("/chaos", h.chaosRoutes)
+		}
+
+		// Replication status is only routed on a publishing primary
+		if h.replicator != nil {
+			r.Get("/replication", h.GetReplicationStatus)
+		}
+
+		// Migration status is only routed while dual writes or comparison run
+		if h.forwarder != nil || h.comparer != nil {
+			r.Get("/migration", h.GetMigrationStatus)
+		}
+		if h.comparer != nil {
+			r.Post("/migration/compare", h.RunComparison)
+		}
+
+		// Staleness alerts are only routed when the worker runs
+		if h.staleness != nil {
+			r.Get("/staleness", h.GetStaleness)
+		}
+
+		// Feature flags are only routed when the provider is set
+		if h.features != nil {
+			r.Route("/features", h.featureRoutes)
+		}
+
+		// Clock control is only routed in simulation mode
+		if h.simClock != nil {
+			r.Route("/clock", h.clockRoutes)
+		}
+	})
 }
 
 // corsMiddleware adds CORS headers
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Request-ID")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Encoding, Content-Type, Idempotency-Key, X-Decrypt-Token, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -106,9 +372,46 @@ func corsMiddleware(next http.Handler) http.Handler {
 
 // writeJSON writes a JSON response
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	if resp, ok := data.(APIResponse); ok && apiVersion(w) >= apiV2 {
+		if !resp.Success && resp.code == "" {
+			resp.code = errorCode(nil, status)
+		}
+		v2 := toV2(resp)
+		if v2.Error != nil {
+			h.localizeError(w, v2.Error)
+		}
+		data = v2
+	}
+
+	// Encode into a pooled buffer so a failed encode can still become a 500
+	// and hot read paths don't grow a fresh buffer per response
+	buf := responseBuffers.Get().(*bytes.Buffer)
+	defer putResponseBuffer(buf)
+	if err := h.encoder.Encode(buf, data); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	w.Write(buf.Bytes())
+}
+
+// maxPooledResponse keeps the occasional huge export from pinning memory in the pool
+const maxPooledResponse = 1 << 20
+
+// responseBuffers recycles response encoding buffers across requests
+var responseBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func putResponseBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledResponse {
+		return
+	}
+	buf.Reset()
+	responseBuffers.Put(buf)
 }
 
 // writeSuccess writes a successful JSON response
@@ -119,11 +422,52 @@ func (h *Handler) writeSuccess(w http.ResponseWriter, data interface{}) {
 	})
 }
 
-// writeError writes an error JSON response
+// writeError writes an error JSON response. Typed domain errors, however
+// deeply wrapped, are attached as details: the invalid fields of a
+// ValidationError, or the resource of a NotFoundError.
 func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
-	h.writeJSON(w, status, APIResponse{
+	resp := APIResponse{
+		Success: false,
+		Error:   err.Error(),
+		code:    errorCode(err, status),
+	}
+	var invalid *domain.ValidationError
+	var missing *domain.NotFoundError
+	switch {
+	case errors.As(err, &invalid):
+		resp.Data = invalid
+	case errors.As(err, &missing):
+		resp.Data = missing
+	}
+	h.writeJSON(w, status, resp)
+}
+
+// writeWindowError writes a 403 carrying the window bounds so clients know when to retry
+func (h *Handler) writeWindowError(w http.ResponseWriter, err *domain.SubmissionWindowError) {
+	h.writeJSON(w, http.StatusForbidden, APIResponse{
+		Success: false,
+		Data:    err,
+		Error:   err.Error(),
+		code:    errorCode(err, http.StatusForbidden),
+	})
+}
+
+// writeQuotaError writes a 429 carrying the quota and, when it renews, a
+// Retry-After until then
+func (h *Handler) writeQuotaError(w http.ResponseWriter, err *domain.QuotaExceededError) {
+	if err.ResetsAt != nil {
+		now := time.Now()
+		if h.simClock != nil {
+			now = h.simClock.Now()
+		}
+		retry := int(math.Ceil(err.ResetsAt.Sub(now).Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(retry, 1)))
+	}
+	h.writeJSON(w, http.StatusTooManyRequests, APIResponse{
 		Success: false,
+		Data:    err,
 		Error:   err.Error(),
+		code:    errorCode(err, http.StatusTooManyRequests),
 	})
 }
 
@@ -135,10 +479,122 @@ func (h *Handler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 // GetWebSocketStats returns WebSocket connection statistics
 func (h *Handler) GetWebSocketStats(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, map[string]interface{}{
-		"total_connections": h.hub.GetTotalConnections(),
+		"total_connections":  h.hub.GetTotalConnections(),
+		"global_subscribers": h.hub.GetGlobalSubscriberCount(),
+		"coalesced_updates":  h.hub.GetCoalescedUpdates(),
+		"event_streams":      h.hub.GetStreamCount(),
 	})
 }
 
+// GetOverview returns a summary of every leaderboard for an operations dashboard
+func (h *Handler) GetOverview(w http.ResponseWriter, r *http.Request) {
+	overview, err := h.service.GetOverview(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get overview", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	h.writeSuccess(w, overview)
+}
+
+// GetDedupStats reports how many duplicate submissions were suppressed per source
+func (h *Handler) GetDedupStats(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.service.DedupStats())
+}
+
+// SetStalenessWorker enables the staleness admin endpoint
+func (h *Handler) SetStalenessWorker(staleness *worker.StalenessWorker) {
+	h.staleness = staleness
+}
+
+// GetStaleness returns the boards flagged as stale by the latest check and the alert counts
+func (h *Handler) GetStaleness(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.staleness.Status())
+}
+
+// GetSyncStatus returns the sync worker's recent activity
+func (h *Handler) GetSyncStatus(w http.ResponseWriter, r *http.Request) {
+	if h.syncWorker == nil {
+		h.writeError(w, http.StatusServiceUnavailable, errSyncUnavailable)
+		return
+	}
+
+	h.writeSuccess(w, h.syncWorker.Status())
+}
+
+// SyncLeaderboard immediately syncs one leaderboard from Redis to PostgreSQL
+func (h *Handler) SyncLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if h.syncWorker == nil {
+		h.writeError(w, http.StatusServiceUnavailable, errSyncUnavailable)
+		return
+	}
+
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if err := h.syncWorker.SyncToDatabase(r.Context(), leaderboardID); err != nil {
+		h.logger.Error("failed to sync leaderboard", "leaderboard_id", leaderboardID, "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	h.writeSuccess(w, h.syncWorker.Status().Leaderboards[leaderboardID])
+}
+
+// GetStartupReport returns the reconciliation report from the last boot
+func (h *Handler) GetStartupReport(w http.ResponseWriter, r *http.Request) {
+	if h.syncWorker == nil {
+		h.writeError(w, http.StatusServiceUnavailable, errSyncUnavailable)
+		return
+	}
+
+	report := h.syncWorker.StartupReport()
+	if report == nil {
+		h.writeError(w, http.StatusNotFound, errNoStartupReport)
+		return
+	}
+	h.writeSuccess(w, report)
+}
+
+// LogLevelRequest changes the root log level or a module's level
+type LogLevelRequest struct {
+	Module string `json:"module,omitempty"`
+	Level  string `json:"level"`
+}
+
+// GetLogLevels returns the current root and per-module log levels
+func (h *Handler) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	if h.logManager == nil {
+		h.writeError(w, http.StatusServiceUnavailable, errLogUnavailable)
+		return
+	}
+
+	h.writeSuccess(w, h.logManager.Levels())
+}
+
+// SetLogLevel changes a log level without restarting
+func (h *Handler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	if h.logManager == nil {
+		h.writeError(w, http.StatusServiceUnavailable, errLogUnavailable)
+		return
+	}
+
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.logManager.SetLevel(req.Module, level)
+	h.logger.Info("log level changed", "module", req.Module, "level", level)
+	h.writeSuccess(w, h.logManager.Levels())
+}
+
 // HealthCheck returns service health status
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, map[string]string{"status": "healthy"})
@@ -161,24 +617,122 @@ func (h *Handler) SubmitScore(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
+	if err := h.authorizePlayer(r, submission.PlayerID); err != nil {
+		h.writeError(w, http.StatusForbidden, err)
+		return
+	}
+	if !h.allowSubmissions(w, r, []domain.ScoreSubmission{submission}) {
+		return
+	}
+	if submission.IdempotencyKey == "" {
+		submission.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	}
 
-	if err := h.service.SubmitScore(r.Context(), submission); err != nil {
+	receipt, err := h.service.SubmitScoreWithReceipt(r.Context(), submission)
+	if err != nil {
 		if domain.IsNotFoundError(err) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, domain.ErrReadOnlyReplica) {
+			h.writeError(w, http.StatusForbidden, err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidScore) || errors.Is(err, domain.ErrInvalidRequest) {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		var windowErr *domain.SubmissionWindowError
+		if errors.As(err, &windowErr) {
+			h.writeWindowError(w, windowErr)
+			return
+		}
+		var quotaErr *domain.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			h.writeQuotaError(w, quotaErr)
+			return
+		}
 		h.logger.Error("failed to submit score", "error", err)
 		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
 		return
 	}
 
-	h.writeSuccess(w, map[string]string{"status": "accepted"})
+	if receipt.Duplicate {
+		h.writeSuccess(w, map[string]interface{}{
+			"status":          "duplicate",
+			"idempotency_key": submission.IdempotencyKey,
+		})
+		return
+	}
+
+	if receipt.Ignored {
+		h.writeSuccess(w, map[string]interface{}{
+			"status":        "ignored",
+			"attempts_left": receipt.AttemptsLeft,
+		})
+		return
+	}
+
+	if receipt.PendingID != 0 {
+		data := map[string]interface{}{
+			"status":     "pending_review",
+			"pending_id": receipt.PendingID,
+		}
+		if receipt.AttemptsLeft != nil {
+			data["attempts_left"] = *receipt.AttemptsLeft
+		}
+		h.writeJSON(w, http.StatusAccepted, APIResponse{
+			Success: true,
+			Data:    data,
+		})
+		return
+	}
+
+	w.Header().Set(versionHeader, strconv.FormatInt(receipt.Version, 10))
+	data := map[string]interface{}{
+		"status":  "accepted",
+		"version": receipt.Version,
+	}
+	if receipt.AttemptsLeft != nil {
+		data["attempts_left"] = *receipt.AttemptsLeft
+	}
+	h.writeSuccess(w, data)
+}
+
+// ValidateScore runs a dry-run score submission and reports the projected rank
+func (h *Handler) ValidateScore(w http.ResponseWriter, r *http.Request) {
+	var submission domain.ScoreSubmission
+	if err := json.NewDecoder(r.Body).Decode(&submission); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+	if err := h.authorizePlayer(r, submission.PlayerID); err != nil {
+		h.writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	result, err := h.service.ValidateScore(r.Context(), submission)
+	if err != nil {
+		h.logger.Error("failed to validate score", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+	if !h.wouldAllowSubmissions(r, []domain.ScoreSubmission{submission}) {
+		result.Valid = false
+		result.Errors = append(result.Errors, domain.ErrRateLimited.Error())
+	}
+
+	h.writeSuccess(w, result)
 }
 
 // SubmitScoreBatch handles batch score submission
 func (h *Handler) SubmitScoreBatch(w http.ResponseWriter, r *http.Request) {
 	var batch domain.BatchScoreSubmission
 	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		if isBodyTooLarge(err) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+			return
+		}
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
@@ -188,15 +742,30 @@ func (h *Handler) SubmitScoreBatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.SubmitScoreBatch(r.Context(), batch); err != nil {
-		h.logger.Error("failed to submit score batch", "error", err)
-		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	// One foreign player rejects the whole batch, so nothing is half-applied
+	for _, score := range batch.Scores {
+		if err := h.authorizePlayer(r, score.PlayerID); err != nil {
+			h.writeError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	if h.service.ReadOnly() {
+		h.writeError(w, http.StatusForbidden, domain.ErrReadOnlyReplica)
 		return
 	}
+	if !h.allowSubmissions(w, r, batch.Scores) {
+		return
+	}
+
+	result := h.service.SubmitScoreBatchWithResult(r.Context(), batch)
 
 	h.writeSuccess(w, map[string]interface{}{
-		"status":   "accepted",
-		"received": len(batch.Scores),
+		"status":     "accepted",
+		"received":   len(batch.Scores),
+		"duplicates": result.Duplicates,
+		"ignored":    result.Ignored,
+		"versions":   result.Versions,
 	})
 }
 
@@ -210,11 +779,11 @@ func (h *Handler) CreateLeaderboard(w http.ResponseWriter, r *http.Request) {
 
 	config, err := h.service.CreateLeaderboard(r.Context(), req)
 	if err != nil {
-		if err == domain.ErrLeaderboardExists {
+		if errors.Is(err, domain.ErrLeaderboardExists) {
 			h.writeError(w, http.StatusConflict, err)
 			return
 		}
-		if err == domain.ErrInvalidLeaderboard {
+		if errors.Is(err, domain.ErrInvalidLeaderboard) {
 			h.writeError(w, http.StatusBadRequest, err)
 			return
 		}
@@ -251,7 +820,7 @@ func (h *Handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 
 	config, err := h.service.GetLeaderboard(r.Context(), leaderboardID)
 	if err != nil {
-		if err == domain.ErrLeaderboardNotFound {
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
@@ -272,7 +841,7 @@ func (h *Handler) DeleteLeaderboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteLeaderboard(r.Context(), leaderboardID); err != nil {
-		if err == domain.ErrLeaderboardNotFound {
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
@@ -284,7 +853,7 @@ func (h *Handler) DeleteLeaderboard(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, map[string]string{"status": "deleted"})
 }
 
-// ResetLeaderboard clears all scores from a leaderboard
+// ResetLeaderboard archives a leaderboard's season and clears all of its scores
 func (h *Handler) ResetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	leaderboardID := chi.URLParam(r, "leaderboardID")
 	if leaderboardID == "" {
@@ -293,10 +862,14 @@ func (h *Handler) ResetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.ResetLeaderboard(r.Context(), leaderboardID); err != nil {
-		if err == domain.ErrLeaderboardNotFound {
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, domain.ErrReadOnlyReplica) {
+			h.writeError(w, http.StatusForbidden, err)
+			return
+		}
 		h.logger.Error("failed to reset leaderboard", "error", err)
 		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
 		return
@@ -305,6 +878,54 @@ func (h *Handler) ResetLeaderboard(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, map[string]string{"status": "reset"})
 }
 
+// SetShadow attaches a shadow leaderboard that mirrors live submissions
+func (h *Handler) SetShadow(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.SetShadowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ShadowID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	h.updateShadow(w, r, leaderboardID, req.ShadowID)
+}
+
+// RemoveShadow detaches the shadow leaderboard
+func (h *Handler) RemoveShadow(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	h.updateShadow(w, r, leaderboardID, "")
+}
+
+// updateShadow applies a shadow change and writes the updated config
+func (h *Handler) updateShadow(w http.ResponseWriter, r *http.Request, leaderboardID, shadowID string) {
+	config, err := h.service.SetShadow(r.Context(), leaderboardID, shadowID)
+	if err != nil {
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLeaderboard) {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error("failed to set shadow leaderboard", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	h.writeSuccess(w, config)
+}
+
 // GetStats returns statistics for a leaderboard
 func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	leaderboardID := chi.URLParam(r, "leaderboardID")
@@ -312,9 +933,17 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
+	minVersion, ok := h.awaitMinVersion(w, r, leaderboardID)
+	if !ok {
+		return
+	}
 
-	stats, err := h.service.GetStats(r.Context(), leaderboardID)
+	stats, err := h.service.GetStats(r.Context(), leaderboardID, minVersion)
 	if err != nil {
+		if domain.IsNotFoundError(err) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
 		h.logger.Error("failed to get stats", "error", err)
 		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
 		return
@@ -323,6 +952,46 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	h.writeSuccess(w, stats)
 }
 
+// GetView returns a leaderboard's settings, counts, and top and bottom entries
+func (h *Handler) GetView(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+	if _, ok := h.awaitMinVersion(w, r, leaderboardID); !ok {
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	view, err := h.service.GetLeaderboardView(r.Context(), leaderboardID, limit)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error("failed to get leaderboard view", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	if wantsAnonymized(r) {
+		for _, entries := range [][]domain.LeaderboardEntry{view.Top, view.Bottom} {
+			if err := h.service.AnonymizeEntries(entries); err != nil {
+				h.writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+	}
+	h.writeSuccess(w, view)
+}
+
 // GetTop returns top N players from a leaderboard
 func (h *Handler) GetTop(w http.ResponseWriter, r *http.Request) {
 	leaderboardID := chi.URLParam(r, "leaderboardID")
@@ -330,6 +999,9 @@ func (h *Handler) GetTop(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
+	if _, ok := h.awaitMinVersion(w, r, leaderboardID); !ok {
+		return
+	}
 
 	limit := 10
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -338,14 +1010,21 @@ func (h *Handler) GetTop(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	entries, err := h.service.GetTopN(r.Context(), leaderboardID, limit)
+	getTopN := h.service.GetTopN
+	if includes(r, "provisional") {
+		getTopN = h.service.GetTopNWithProvisional
+	}
+	entries, err := getTopN(r.Context(), leaderboardID, limit)
+	if err == nil && includes(r, "pending") {
+		entries, err = h.service.MergePending(r.Context(), leaderboardID, entries, limit)
+	}
 	if err != nil {
 		h.logger.Error("failed to get top", "error", err)
 		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
 		return
 	}
 
-	h.writeSuccess(w, entries)
+	h.writeRanking(w, r, leaderboardID, entries)
 }
 
 // GetRange returns players within a specific rank range
@@ -355,6 +1034,9 @@ func (h *Handler) GetRange(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
+	if _, ok := h.awaitMinVersion(w, r, leaderboardID); !ok {
+		return
+	}
 
 	start := 0
 	end := 10
@@ -369,14 +1051,18 @@ func (h *Handler) GetRange(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	entries, err := h.service.GetRange(r.Context(), leaderboardID, start, end)
+	getRange := h.service.GetRange
+	if includes(r, "provisional") {
+		getRange = h.service.GetRangeWithProvisional
+	}
+	entries, err := getRange(r.Context(), leaderboardID, start, end)
 	if err != nil {
 		h.logger.Error("failed to get range", "error", err)
 		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
 		return
 	}
 
-	h.writeSuccess(w, entries)
+	h.writeRanking(w, r, leaderboardID, entries)
 }
 
 // GetAroundPlayer returns players around a specific player's rank
@@ -387,6 +1073,9 @@ func (h *Handler) GetAroundPlayer(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
+	if _, ok := h.awaitMinVersion(w, r, leaderboardID); !ok {
+		return
+	}
 
 	count := 5
 	if rangeStr := r.URL.Query().Get("range"); rangeStr != "" {
@@ -397,7 +1086,7 @@ func (h *Handler) GetAroundPlayer(w http.ResponseWriter, r *http.Request) {
 
 	entries, err := h.service.GetAroundPlayer(r.Context(), leaderboardID, playerID, count)
 	if err != nil {
-		if err == domain.ErrPlayerNotFound {
+		if errors.Is(err, domain.ErrPlayerNotFound) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
@@ -406,7 +1095,7 @@ func (h *Handler) GetAroundPlayer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeSuccess(w, entries)
+	h.writeRanking(w, r, leaderboardID, entries)
 }
 
 // GetPlayerRank returns a player's rank and score
@@ -417,10 +1106,18 @@ func (h *Handler) GetPlayerRank(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
 		return
 	}
+	if _, ok := h.awaitMinVersion(w, r, leaderboardID); !ok {
+		return
+	}
 
 	entry, err := h.service.GetPlayerRank(r.Context(), leaderboardID, playerID)
+	if err == nil && wantsProof(r) {
+		entries := []domain.LeaderboardEntry{*entry}
+		err = h.service.WithProofs(r.Context(), leaderboardID, entries)
+		entry = &entries[0]
+	}
 	if err != nil {
-		if err == domain.ErrPlayerNotFound {
+		if errors.Is(err, domain.ErrPlayerNotFound) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
@@ -442,10 +1139,14 @@ func (h *Handler) RemovePlayer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.RemovePlayer(r.Context(), leaderboardID, playerID); err != nil {
-		if err == domain.ErrPlayerNotFound {
+		if errors.Is(err, domain.ErrPlayerNotFound) {
 			h.writeError(w, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, domain.ErrReadOnlyReplica) {
+			h.writeError(w, http.StatusForbidden, err)
+			return
+		}
 		h.logger.Error("failed to remove player", "error", err)
 		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
 		return
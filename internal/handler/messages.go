@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/i18n"
+)
+
+// catalogueMaxAge is how long clients and caches may keep the catalogue without revalidating
+const catalogueMaxAge = 3600
+
+// ErrorCatalogue is the error message catalogue as served to clients
+type ErrorCatalogue struct {
+	Version         string                       `json:"version"`
+	DefaultLanguage string                       `json:"default_language"`
+	Languages       []string                     `json:"languages"`
+	Messages        map[string]map[string]string `json:"messages"`
+}
+
+// negotiateLanguage records the language error messages are localized into:
+// the lang query parameter when the catalogue has it, otherwise the best
+// match for Accept-Language
+func (h *Handler) negotiateLanguage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if vw, ok := w.(*versionedWriter); ok && h.catalogue != nil {
+			lang := r.URL.Query().Get("lang")
+			if !h.catalogue.Has(lang) {
+				lang = h.catalogue.Negotiate(r.Header.Get("Accept-Language"))
+			}
+			vw.lang = lang
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// localizeError adds the catalogue message for an error's code in the
+// negotiated language
+func (h *Handler) localizeError(w http.ResponseWriter, apiErr *APIError) {
+	vw, ok := w.(*versionedWriter)
+	if !ok || vw.lang == "" || h.catalogue == nil {
+		return
+	}
+	message, ok := h.catalogue.Message(vw.lang, apiErr.Code)
+	if !ok {
+		return
+	}
+	apiErr.LocalizedMessage = message
+	w.Header().Set("Content-Language", vw.lang)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// GetErrorCatalogue returns the error messages of every language, or of the
+// language given by lang. The ETag is the catalogue version, so clients can
+// sync with If-None-Match.
+func (h *Handler) GetErrorCatalogue(w http.ResponseWriter, r *http.Request) {
+	catalogue := ErrorCatalogue{
+		Version:         h.catalogue.Version(),
+		DefaultLanguage: i18n.DefaultLanguage,
+		Languages:       h.catalogue.Languages(),
+		Messages:        make(map[string]map[string]string),
+	}
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if !h.catalogue.Has(lang) {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("%w: no messages for language %q", domain.ErrInvalidRequest, lang))
+			return
+		}
+		catalogue.Messages[lang] = h.catalogue.Messages(lang)
+	} else {
+		for _, lang := range catalogue.Languages {
+			catalogue.Messages[lang] = h.catalogue.Messages(lang)
+		}
+	}
+
+	etag := `"` + catalogue.Version + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", catalogueMaxAge))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.writeSuccess(w, catalogue)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/leaderboard-redis/internal/migration"
+	"github.com/leaderboard-redis/internal/worker"
+)
+
+// MigrationStatus reports dual writes and the latest comparison with the
+// legacy service; each part is omitted when it is not configured
+type MigrationStatus struct {
+	DualWrite *migration.ForwarderStats `json:"dual_write,omitempty"`
+	Reports   []migration.Report        `json:"reports,omitempty"`
+}
+
+// SetLegacyForwarder enables dual-write stats on the migration endpoint
+func (h *Handler) SetLegacyForwarder(forwarder *migration.Forwarder) {
+	h.forwarder = forwarder
+}
+
+// SetCompareWorker enables comparison reports on the migration endpoint
+func (h *Handler) SetCompareWorker(comparer *worker.CompareWorker) {
+	h.comparer = comparer
+}
+
+// GetMigrationStatus returns dual-write stats and the latest comparison reports
+func (h *Handler) GetMigrationStatus(w http.ResponseWriter, r *http.Request) {
+	var status MigrationStatus
+	if h.forwarder != nil {
+		stats := h.forwarder.Stats()
+		status.DualWrite = &stats
+	}
+	if h.comparer != nil {
+		status.Reports = h.comparer.Reports()
+	}
+	h.writeSuccess(w, status)
+}
+
+// RunComparison compares every configured leaderboard with the legacy service
+// now, rather than waiting for the next interval
+func (h *Handler) RunComparison(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.comparer.RunOnce(r.Context()))
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreatePlayer creates a player profile
+func (h *Handler) CreatePlayer(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreatePlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	player, err := h.service.CreatePlayer(r.Context(), req)
+	if err != nil {
+		h.writePlayerError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    player,
+	})
+}
+
+// ListPlayers returns a page of player profiles
+func (h *Handler) ListPlayers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	page, err := h.service.ListPlayers(r.Context(), query.Get("cursor"), limit)
+	if err != nil {
+		h.writePlayerError(w, err)
+		return
+	}
+
+	// v2 moves the cursor into the envelope
+	if apiVersion(w) >= apiV2 {
+		h.writePage(w, page.Players, page.NextCursor)
+		return
+	}
+	h.writeSuccess(w, page)
+}
+
+// GetPlayer returns a player profile
+func (h *Handler) GetPlayer(w http.ResponseWriter, r *http.Request) {
+	playerID := chi.URLParam(r, "playerID")
+	if playerID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	player, err := h.service.GetPlayer(r.Context(), playerID)
+	if err != nil {
+		h.writePlayerError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, player)
+}
+
+// GetPlayerScores returns a player's score and rank on every leaderboard the player is on
+func (h *Handler) GetPlayerScores(w http.ResponseWriter, r *http.Request) {
+	playerID := chi.URLParam(r, "playerID")
+	if playerID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	scores, err := h.service.GetPlayerScores(r.Context(), playerID)
+	if err != nil {
+		h.writePlayerError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, scores)
+}
+
+// UpdatePlayer changes the fields of a player profile present in the body
+func (h *Handler) UpdatePlayer(w http.ResponseWriter, r *http.Request) {
+	playerID := chi.URLParam(r, "playerID")
+	if playerID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.UpdatePlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	player, err := h.service.UpdatePlayer(r.Context(), playerID, req)
+	if err != nil {
+		h.writePlayerError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, player)
+}
+
+// DeletePlayer removes a player profile
+func (h *Handler) DeletePlayer(w http.ResponseWriter, r *http.Request) {
+	playerID := chi.URLParam(r, "playerID")
+	if playerID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	if err := h.service.DeletePlayer(r.Context(), playerID); err != nil {
+		h.writePlayerError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, map[string]string{"status": "deleted"})
+}
+
+// writePlayerError maps player profile errors to HTTP responses
+func (h *Handler) writePlayerError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrProfileExists):
+		h.writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	default:
+		h.logger.Error("player operation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// ComputePayouts distributes a prize structure over a leaderboard's live or
+// frozen standings. With ?format=csv the report is downloaded as a CSV file.
+func (h *Handler) ComputePayouts(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.PayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	report, err := h.service.ComputePayouts(r.Context(), leaderboardID, req)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		h.writePayoutCSV(w, report)
+		return
+	}
+	h.writeSuccess(w, report)
+}
+
+// writePayoutCSV writes a payout report as a CSV attachment, one row per paid player
+func (h *Handler) writePayoutCSV(w http.ResponseWriter, report *domain.PayoutReport) {
+	name := "live"
+	if report.Snapshot != "" {
+		name = report.Snapshot
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s-%s-payouts.csv"`, report.LeaderboardID, name))
+	w.Header().Set("X-Snapshot-Version", strconv.FormatInt(report.Version, 10))
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"position", "rank", "player_id", "score", "amount", "label", "tied"})
+	for _, payout := range report.Payouts {
+		out.Write([]string{
+			strconv.FormatInt(payout.Position, 10),
+			strconv.FormatInt(payout.Rank, 10),
+			payout.PlayerID,
+			strconv.FormatInt(payout.Score, 10),
+			strconv.FormatInt(payout.Amount, 10),
+			payout.Label,
+			strconv.FormatBool(payout.Tied),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		h.logger.Error("failed to write payout report", "leaderboard_id", report.LeaderboardID, "error", err)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// includes reports whether the comma-separated ?include= parameter lists part
+func includes(r *http.Request, part string) bool {
+	for _, p := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if strings.TrimSpace(p) == part {
+			return true
+		}
+	}
+	return false
+}
+
+// wantsProof reports whether entries should carry their proof references,
+// requested with ?include=proof or as part of ?include=meta
+func wantsProof(r *http.Request) bool {
+	return includes(r, "proof") || includes(r, "meta")
+}
+
+// wantsAnonymized reports whether player IDs should be pseudonymized, requested with ?anonymize=true
+func wantsAnonymized(r *http.Request) bool {
+	anonymize, _ := strconv.ParseBool(r.URL.Query().Get("anonymize"))
+	return anonymize
+}
+
+// writeRanking writes ranking entries, wrapped with leaderboard metadata when
+// the request asks for ?include=meta
+func (h *Handler) writeRanking(w http.ResponseWriter, r *http.Request, leaderboardID string, entries []domain.LeaderboardEntry) {
+	if wantsProof(r) {
+		if err := h.service.WithProofs(r.Context(), leaderboardID, entries); err != nil {
+			h.logger.Error("failed to get score proofs", "error", err)
+			h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+			return
+		}
+	}
+
+	if wantsAnonymized(r) {
+		if err := h.service.AnonymizeEntries(entries); err != nil {
+			h.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if !includes(r, "meta") {
+		h.writeSuccess(w, entries)
+		return
+	}
+
+	response, err := h.service.WithMeta(r.Context(), leaderboardID, entries)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error("failed to get leaderboard meta", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	h.writeSuccess(w, response)
+}
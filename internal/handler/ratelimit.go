@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/ratelimit"
+	"github.com/leaderboard-redis/internal/websocket"
+)
+
+// SetRateLimiter enables the per-player and per-API-key submission limits
+// and the per-client read limit
+func (h *Handler) SetRateLimiter(limiter *ratelimit.Limiter) {
+	h.limiter = limiter
+}
+
+// allowSubmissions counts submissions against the rate limits and writes a
+// 429 with Retry-After when they are exhausted. If Redis cannot be reached the
+// submissions are let through, since the limits protect the store rather than
+// guard access.
+func (h *Handler) allowSubmissions(w http.ResponseWriter, r *http.Request, submissions []domain.ScoreSubmission) bool {
+	if h.limiter == nil {
+		return true
+	}
+	decision, err := h.limiter.AllowSubmissions(r.Context(), h.rateClient(r), submissions)
+	if err != nil {
+		h.logger.Warn("rate limit check failed, allowing submission", "error", err)
+		return true
+	}
+	return h.applyDecision(w, decision)
+}
+
+// wouldAllowSubmissions reports whether the submission limits have room for
+// submissions, without counting them. A failed check allows, as for real
+// submissions.
+func (h *Handler) wouldAllowSubmissions(r *http.Request, submissions []domain.ScoreSubmission) bool {
+	if h.limiter == nil {
+		return true
+	}
+	decision, err := h.limiter.CheckSubmissions(r.Context(), h.rateClient(r), submissions)
+	if err != nil {
+		h.logger.Warn("rate limit check failed, allowing submission", "error", err)
+		return true
+	}
+	return decision.Allowed
+}
+
+// limitReads counts GET requests against the client's read limit. Other
+// methods pass through; submissions have their own limits.
+func (h *Handler) limitReads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if h.allowRead(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// limitQueries counts every request against the client's read limit, for
+// endpoints such as /graphql that read over POST
+func (h *Handler) limitQueries(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.allowRead(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allowRead checks the client's read limit, writing a 429 when it is used up
+func (h *Handler) allowRead(w http.ResponseWriter, r *http.Request) bool {
+	decision, err := h.limiter.AllowRead(r.Context(), h.rateClient(r))
+	if err != nil {
+		h.logger.Warn("rate limit check failed, allowing read", "error", err)
+		return true
+	}
+	return h.applyDecision(w, decision)
+}
+
+// applyDecision sets the X-RateLimit headers so clients can pace themselves,
+// and writes a 429 with Retry-After when the request is denied
+func (h *Handler) applyDecision(w http.ResponseWriter, decision ratelimit.Decision) bool {
+	if decision.Limit >= 0 {
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(decision.Reset.Seconds()))))
+	}
+	if decision.Allowed {
+		return true
+	}
+
+	retry := int(math.Ceil(decision.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retry))
+	h.writeError(w, http.StatusTooManyRequests, domain.ErrRateLimited)
+	return false
+}
+
+// rateClient identifies the caller for client limits: its API key when the
+// key is registered, or its IP address. Unregistered keys are not trusted, so
+// a made-up key cannot earn a fresh bucket. The IP comes from the connection
+// rather than RemoteAddr, which RealIP has rewritten from forwarding headers;
+// those are only honoured when added by a trusted proxy.
+func (h *Handler) rateClient(r *http.Request) string {
+	if id := h.apiKeyID(r); id != "" {
+		return ratelimit.KeyClient(id)
+	}
+	return ratelimit.IPClient(h.limiter.ClientIP(websocket.PeerIP(r), r.Header.Values("X-Forwarded-For")))
+}
+
+// apiKeyID returns the ID of the request's API key when it is registered
+// under rate_limit.keys, and "" otherwise
+func (h *Handler) apiKeyID(r *http.Request) string {
+	if h.limiter == nil {
+		return ""
+	}
+	id, _ := h.limiter.Identify(r.Header.Get("X-API-Key"))
+	return id
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/ratelimit"
+)
+
+// clientOf runs a request through the router's address middleware and
+// returns the client identity its submissions would be counted against
+func clientOf(h *Handler, req *http.Request) string {
+	var client string
+	r := chi.NewRouter()
+	r.Use(recordPeerAddr)
+	r.Use(middleware.RealIP)
+	r.Post("/scores", func(w http.ResponseWriter, r *http.Request) {
+		client = h.rateClient(r)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	return client
+}
+
+func TestRateClientIgnoresClientSuppliedAddresses(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret-key"))
+	limiter, err := ratelimit.NewLimiter(nil, "", &config.RateLimitConfig{
+		Keys:           map[string]string{"game-server": hex.EncodeToString(sum[:])},
+		TrustedProxies: []string{"10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("NewLimiter: %v", err)
+	}
+	h := NewHandler(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h.SetRateLimiter(limiter)
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"plain", "203.0.113.5:4000", nil, ratelimit.IPClient("203.0.113.5")},
+		{"spoofed forwarded for", "203.0.113.5:4000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, ratelimit.IPClient("203.0.113.5")},
+		{"spoofed real ip", "203.0.113.5:4000", map[string]string{"X-Real-IP": "198.51.100.2"}, ratelimit.IPClient("203.0.113.5")},
+		{"unregistered key", "203.0.113.5:4000", map[string]string{"X-API-Key": "made-up"}, ratelimit.IPClient("203.0.113.5")},
+		{"registered key", "203.0.113.5:4000", map[string]string{"X-API-Key": "secret-key"}, ratelimit.KeyClient("game-server")},
+		{"trusted proxy", "10.0.0.1:4000", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.9"}, ratelimit.IPClient("203.0.113.9")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/scores", nil)
+			req.RemoteAddr = tt.remote
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			if got := clientOf(h, req); got != tt.want {
+				t.Errorf("client = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// recoverer turns handler panics into 500 responses and reports them with request context
+func (h *Handler) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			if value == http.ErrAbortHandler {
+				// Let net/http abort the response as intended
+				panic(value)
+			}
+
+			tags := map[string]string{
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"request_id": middleware.GetReqID(r.Context()),
+			}
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				tags["route"] = rctx.RoutePattern()
+				if id := rctx.URLParam("leaderboardID"); id != "" {
+					tags["leaderboard_id"] = id
+				}
+			}
+			h.reporter.CapturePanic(r.Context(), value, debug.Stack(), tags)
+
+			h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/replication"
+)
+
+var errReplicationUnauthorized = errors.New("invalid replication token")
+
+// ApplyReplication applies a batch of changes posted by the primary region
+func (h *Handler) ApplyReplication(w http.ResponseWriter, r *http.Request) {
+	if h.replica.Token != "" {
+		token := r.Header.Get(replication.TokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.replica.Token)) != 1 {
+			h.writeError(w, http.StatusUnauthorized, errReplicationUnauthorized)
+			return
+		}
+	}
+
+	var batch domain.ReplicationBatch
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	h.writeSuccess(w, h.service.ApplyReplicatedChanges(r.Context(), batch.Changes))
+}
+
+// GetReplicationStatus returns the replication publisher's counters
+func (h *Handler) GetReplicationStatus(w http.ResponseWriter, r *http.Request) {
+	h.writeSuccess(w, h.replicator.Stats())
+}
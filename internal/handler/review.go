@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// ListPendingScores returns a leaderboard's review queue
+func (h *Handler) ListPendingScores(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	status := domain.ReviewStatus(r.URL.Query().Get("status"))
+
+	scores, cursor, err := h.service.ListPendingScores(r.Context(), leaderboardID, status, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		h.writeReviewError(w, err)
+		return
+	}
+	if h.canDecrypt(r) {
+		h.service.DecryptPendingScores(scores)
+	}
+
+	// v1 keeps its plain list; v2 adds the cursor of the next page
+	if apiVersion(w) >= apiV2 {
+		h.writePage(w, scores, cursor)
+		return
+	}
+	h.writeSuccess(w, scores)
+}
+
+// ApprovePendingScore applies a held submission to its leaderboard
+func (h *Handler) ApprovePendingScore(w http.ResponseWriter, r *http.Request) {
+	id, review, ok := h.decodeReview(w, r)
+	if !ok {
+		return
+	}
+
+	pending, version, err := h.service.ApprovePendingScore(r.Context(), id, review)
+	if err != nil {
+		h.writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set(versionHeader, strconv.FormatInt(version, 10))
+	h.writeSuccess(w, pending)
+}
+
+// RejectPendingScore discards a held submission
+func (h *Handler) RejectPendingScore(w http.ResponseWriter, r *http.Request) {
+	id, review, ok := h.decodeReview(w, r)
+	if !ok {
+		return
+	}
+
+	pending, err := h.service.RejectPendingScore(r.Context(), id, review)
+	if err != nil {
+		h.writeReviewError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, pending)
+}
+
+// decodeReview reads the pending ID and the optional review body
+func (h *Handler) decodeReview(w http.ResponseWriter, r *http.Request) (int64, domain.ReviewRequest, bool) {
+	var review domain.ReviewRequest
+	id, err := strconv.ParseInt(chi.URLParam(r, "pendingID"), 10, 64)
+	if err != nil || id <= 0 {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return 0, review, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil && err != io.EOF {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return 0, review, false
+	}
+	return id, review, true
+}
+
+// writeReviewError maps review errors to HTTP responses
+func (h *Handler) writeReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrAlreadyReviewed):
+		h.writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrReadOnlyReplica):
+		h.writeError(w, http.StatusForbidden, err)
+	default:
+		h.logger.Error("review operation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateScript stores a new scoring script version for a leaderboard and activates it
+func (h *Handler) CreateScript(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.CreateScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	script, err := h.service.CreateScript(r.Context(), leaderboardID, req)
+	if err != nil {
+		h.writeScriptError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    script,
+	})
+}
+
+// ListScripts returns every scoring script version of a leaderboard
+func (h *Handler) ListScripts(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	scripts, err := h.service.ListScripts(r.Context(), leaderboardID)
+	if err != nil {
+		h.writeScriptError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, scripts)
+}
+
+// ActivateScript selects the active scoring script version; version 0 disables scripting
+func (h *Handler) ActivateScript(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.ActivateScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	config, err := h.service.ActivateScript(r.Context(), leaderboardID, req.Version)
+	if err != nil {
+		h.writeScriptError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, config)
+}
+
+// writeScriptError maps scoring script errors to HTTP responses
+func (h *Handler) writeScriptError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrReadOnlyReplica):
+		h.writeError(w, http.StatusForbidden, err)
+	default:
+		h.logger.Error("scoring script operation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// ListSeasons returns a leaderboard's archived seasons, newest first, without their standings
+func (h *Handler) ListSeasons(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	seasons, err := h.service.ListSeasons(r.Context(), leaderboardID)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, seasons)
+}
+
+// GetSeason returns the final standings of an archived season
+func (h *Handler) GetSeason(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	season := chi.URLParam(r, "season")
+	if leaderboardID == "" || season == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	archive, err := h.service.GetSeason(r.Context(), leaderboardID, season)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, archive)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateSeeding splits a leaderboard's current standings into divisions or a bracket
+func (h *Handler) CreateSeeding(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.SeedingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	seeding, err := h.service.CreateSeeding(r.Context(), leaderboardID, req)
+	if err != nil {
+		h.writeSeedingError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    seeding,
+	})
+}
+
+// ListSeedings returns a leaderboard's seedings, newest first, without their players
+func (h *Handler) ListSeedings(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	seedings, err := h.service.ListSeedings(r.Context(), leaderboardID)
+	if err != nil {
+		h.writeSeedingError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, seedings)
+}
+
+// GetSeeding returns a seeding with its divisions or first-round matches
+func (h *Handler) GetSeeding(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	name := chi.URLParam(r, "name")
+	if leaderboardID == "" || name == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	seeding, err := h.service.GetSeeding(r.Context(), leaderboardID, name)
+	if err != nil {
+		h.writeSeedingError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, seeding)
+}
+
+// GetDivision returns the live standings of one division of a seeding
+func (h *Handler) GetDivision(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	name := chi.URLParam(r, "name")
+	division, err := strconv.Atoi(chi.URLParam(r, "division"))
+	if leaderboardID == "" || name == "" || err != nil {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	view, err := h.service.GetDivision(r.Context(), leaderboardID, name, division)
+	if err != nil {
+		h.writeSeedingError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, view)
+}
+
+// writeSeedingError maps seeding errors to HTTP responses
+func (h *Handler) writeSeedingError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrSeedingExists):
+		h.writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrReadOnlyReplica):
+		h.writeError(w, http.StatusForbidden, err)
+	default:
+		h.logger.Error("seeding operation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
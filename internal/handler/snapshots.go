@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// FreezeLeaderboard stores an immutable snapshot of a leaderboard's current standings.
+// The body is optional; {"name": "..."} names the snapshot.
+func (h *Handler) FreezeLeaderboard(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	var req domain.FreezeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	snapshot, err := h.service.FreezeLeaderboard(r.Context(), leaderboardID, req)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    snapshot,
+	})
+}
+
+// ListSnapshots returns a leaderboard's snapshots, newest first, without their entries
+func (h *Handler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	snapshots, err := h.service.ListSnapshots(r.Context(), leaderboardID)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, snapshots)
+}
+
+// GetSnapshot downloads a snapshot with all of its entries, as JSON or, with
+// ?format=csv, as a CSV attachment
+func (h *Handler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	name := chi.URLParam(r, "name")
+	if leaderboardID == "" || name == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+
+	snapshot, err := h.service.GetSnapshot(r.Context(), leaderboardID, name)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		h.writeSnapshotCSV(w, r, snapshot)
+		return
+	}
+
+	snapshot.Entries = make([]domain.LeaderboardEntry, 0, snapshot.PlayerCount)
+	err = h.service.EachSnapshotEntry(r.Context(), snapshot, func(entry domain.LeaderboardEntry) error {
+		snapshot.Entries = append(snapshot.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, snapshot)
+}
+
+// DiffSnapshots compares two snapshots named by ?from_snapshot= and
+// ?to_snapshot=, with an optional ?limit= on each list of players
+func (h *Handler) DiffSnapshots(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	query := r.URL.Query()
+	from, to := query.Get("from_snapshot"), query.Get("to_snapshot")
+	if leaderboardID == "" || from == "" || to == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 0 {
+			h.writeError(w, http.StatusBadRequest, domain.NewValidationError(domain.ErrInvalidRequest, "limit", "must be a non-negative integer"))
+			return
+		}
+		limit = l
+	}
+
+	diff, err := h.service.DiffSnapshots(r.Context(), leaderboardID, from, to, limit)
+	if err != nil {
+		h.writeSnapshotError(w, err)
+		return
+	}
+
+	h.writeSuccess(w, diff)
+}
+
+// writeSnapshotCSV streams a snapshot's entries as CSV. The checksum and version
+// travel in headers so the file can be verified on its own.
+func (h *Handler) writeSnapshotCSV(w http.ResponseWriter, r *http.Request, snapshot *domain.Snapshot) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s-%s.csv"`, snapshot.LeaderboardID, snapshot.Name))
+	w.Header().Set("X-Snapshot-Version", strconv.FormatInt(snapshot.Version, 10))
+	w.Header().Set("X-Snapshot-Checksum", snapshot.Checksum)
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"rank", "player_id", "score"})
+	err := h.service.EachSnapshotEntry(r.Context(), snapshot, func(entry domain.LeaderboardEntry) error {
+		return out.Write([]string{
+			strconv.FormatInt(entry.Rank, 10),
+			entry.PlayerID,
+			strconv.FormatInt(entry.Score, 10),
+		})
+	})
+	out.Flush()
+	if err == nil {
+		err = out.Error()
+	}
+	if err != nil {
+		// The status is already sent, so a failure can only cut the download short
+		h.logger.Error("failed to stream snapshot", "leaderboard_id", snapshot.LeaderboardID, "snapshot", snapshot.Name, "error", err)
+	}
+}
+
+// writeSnapshotError maps snapshot errors to HTTP responses
+func (h *Handler) writeSnapshotError(w http.ResponseWriter, err error) {
+	switch {
+	case domain.IsNotFoundError(err):
+		h.writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, domain.ErrInvalidRequest):
+		h.writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domain.ErrSnapshotExists):
+		h.writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domain.ErrReadOnlyReplica):
+		h.writeError(w, http.StatusForbidden, err)
+	default:
+		h.logger.Error("snapshot operation failed", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+	}
+}
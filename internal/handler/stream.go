@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/websocket"
+)
+
+const (
+	// streamHeartbeat is how often an idle event stream is sent a comment so
+	// proxies do not close it
+	streamHeartbeat = 15 * time.Second
+
+	// streamRetry is the reconnect delay suggested to EventSource clients
+	streamRetry = 3 * time.Second
+
+	// streamSnapshotSize is how many top entries a fresh stream starts with,
+	// the same as WebSocket broadcasts
+	streamSnapshotSize = 10
+)
+
+var errStreamUnavailable = errors.New("event streams unavailable")
+
+// StreamLeaderboard serves a leaderboard's updates as server-sent events, for
+// clients that cannot open a WebSocket. Events carry the same messages as the
+// WebSocket protocol v1. A client reconnecting with Last-Event-ID (or
+// ?last_event_id=) is sent the events it missed when they are still buffered,
+// and a fresh leaderboard_update snapshot otherwise.
+func (h *Handler) StreamLeaderboard(w http.ResponseWriter, r *http.Request) {
+	leaderboardID := chi.URLParam(r, "leaderboardID")
+	if leaderboardID == "" {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return
+	}
+	if _, err := h.service.GetLeaderboard(r.Context(), leaderboardID); err != nil {
+		if domain.IsNotFoundError(err) {
+			h.writeError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error("failed to get leaderboard for stream", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	stream, missed, resumed, err := h.hub.OpenStream(leaderboardID, lastEventID)
+	if err != nil {
+		h.writeError(w, http.StatusServiceUnavailable, errStreamUnavailable)
+		return
+	}
+	defer h.hub.CloseStream(stream)
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to clear stream write deadline", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	if !resumed {
+		if err := h.writeStreamSnapshot(w, r, leaderboardID); err != nil {
+			h.logger.Warn("failed to send stream snapshot", "leaderboard_id", leaderboardID, "error", err)
+			return
+		}
+	}
+	for _, event := range missed {
+		writeStreamEvent(w, event)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event, ok := <-stream.Events():
+			if !ok {
+				// Closed by the hub; the client resumes from its last event
+				return
+			}
+			writeStreamEvent(w, event)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamSnapshot sends the board's current top entries as a
+// leaderboard_update without an event ID, so a client's Last-Event-ID still
+// points at the last live event it saw
+func (h *Handler) writeStreamSnapshot(w http.ResponseWriter, r *http.Request, leaderboardID string) error {
+	entries, err := h.service.GetTopN(r.Context(), leaderboardID, streamSnapshotSize)
+	if err != nil {
+		return err
+	}
+	count, err := h.service.GetCount(r.Context(), leaderboardID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(websocket.NewLeaderboardUpdateMessage(leaderboardID, entries, count))
+	if err != nil {
+		return err
+	}
+	writeStreamEvent(w, websocket.StreamEvent{Type: websocket.MessageTypeLeaderboardUpdate, Data: data})
+	return nil
+}
+
+// writeStreamEvent writes one event; the data is single-line JSON
+func writeStreamEvent(w http.ResponseWriter, event websocket.StreamEvent) {
+	if event.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+}
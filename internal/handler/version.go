@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// versionHeader carries a leaderboard's version on writes and version-gated reads
+const versionHeader = "X-Leaderboard-Version"
+
+// awaitMinVersion holds a read until the leaderboard reaches the version given
+// in ?min_version=, the token returned by an earlier write, and returns that
+// version so cached read paths can be bypassed. It writes an error response
+// and returns false when the version does not become visible in time.
+func (h *Handler) awaitMinVersion(w http.ResponseWriter, r *http.Request, leaderboardID string) (int64, bool) {
+	minVersionStr := r.URL.Query().Get("min_version")
+	if minVersionStr == "" {
+		return 0, true
+	}
+
+	minVersion, err := strconv.ParseInt(minVersionStr, 10, 64)
+	if err != nil || minVersion < 0 {
+		h.writeError(w, http.StatusBadRequest, domain.ErrInvalidRequest)
+		return 0, false
+	}
+
+	version, err := h.service.WaitForVersion(r.Context(), leaderboardID, minVersion)
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionNotVisible) {
+			w.Header().Set("Retry-After", "1")
+			h.writeError(w, http.StatusServiceUnavailable, err)
+			return 0, false
+		}
+		h.logger.Error("failed to wait for leaderboard version", "error", err)
+		h.writeError(w, http.StatusInternalServerError, domain.ErrInternalError)
+		return 0, false
+	}
+
+	w.Header().Set(versionHeader, strconv.FormatInt(version, 10))
+	return minVersion, true
+}
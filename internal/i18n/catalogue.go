@@ -0,0 +1,151 @@
+// Package i18n holds the translated messages for API error codes and picks
+// the language a request asks for
+package i18n
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when a request accepts none of the catalogue's languages
+const DefaultLanguage = "en"
+
+//go:embed messages/*.json
+var messageFiles embed.FS
+
+// Catalogue maps error codes to messages per language. Codes missing from a
+// language fall back to the default language.
+type Catalogue struct {
+	messages  map[string]map[string]string // language -> code -> message
+	languages []string
+	version   string
+}
+
+// Load reads the catalogue embedded in the binary, one JSON file per language
+func Load() (*Catalogue, error) {
+	entries, err := messageFiles.ReadDir("messages")
+	if err != nil {
+		return nil, fmt.Errorf("reading message catalogue: %w", err)
+	}
+
+	c := &Catalogue{messages: make(map[string]map[string]string)}
+	hash := sha256.New()
+	for _, entry := range entries {
+		name := entry.Name()
+		lang := strings.TrimSuffix(name, path.Ext(name))
+		data, err := messageFiles.ReadFile("messages/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s messages: %w", lang, err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("parsing %s messages: %w", lang, err)
+		}
+		c.messages[lang] = messages
+		c.languages = append(c.languages, lang)
+		hash.Write([]byte(name))
+		hash.Write(data)
+	}
+	if _, ok := c.messages[DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("message catalogue has no %s messages", DefaultLanguage)
+	}
+	sort.Strings(c.languages)
+	c.version = hex.EncodeToString(hash.Sum(nil))[:16]
+	return c, nil
+}
+
+// Languages returns the catalogue's languages in alphabetical order
+func (c *Catalogue) Languages() []string {
+	return c.languages
+}
+
+// Version identifies the catalogue's contents, so clients can tell when their
+// copy is stale
+func (c *Catalogue) Version() string {
+	return c.version
+}
+
+// Has reports whether the catalogue has messages in a language
+func (c *Catalogue) Has(lang string) bool {
+	_, ok := c.messages[lang]
+	return ok
+}
+
+// Messages returns the messages of a language with default-language fallbacks
+// filled in, or nil for an unknown language
+func (c *Catalogue) Messages(lang string) map[string]string {
+	messages, ok := c.messages[lang]
+	if !ok {
+		return nil
+	}
+	merged := make(map[string]string, len(c.messages[DefaultLanguage]))
+	for code, message := range c.messages[DefaultLanguage] {
+		merged[code] = message
+	}
+	for code, message := range messages {
+		merged[code] = message
+	}
+	return merged
+}
+
+// Message returns the message for a code in a language, falling back to the
+// default language. It reports false when neither has the code.
+func (c *Catalogue) Message(lang, code string) (string, bool) {
+	if message, ok := c.messages[lang][code]; ok {
+		return message, true
+	}
+	message, ok := c.messages[DefaultLanguage][code]
+	return message, ok
+}
+
+// Negotiate picks the catalogue language that best matches an Accept-Language
+// header. Tags are tried in order of preference, each exactly and then by its
+// primary language, so "pt-BR" matches "pt". The default language is returned
+// when nothing matches.
+func (c *Catalogue) Negotiate(acceptLanguage string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var prefs []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			prefs = append(prefs, preference{tag: strings.ToLower(tag), quality: quality})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].quality > prefs[j].quality })
+
+	for _, pref := range prefs {
+		if pref.tag == "*" {
+			return DefaultLanguage
+		}
+		if _, ok := c.messages[pref.tag]; ok {
+			return pref.tag
+		}
+		primary, _, _ := strings.Cut(pref.tag, "-")
+		if _, ok := c.messages[primary]; ok {
+			return primary
+		}
+	}
+	return DefaultLanguage
+}
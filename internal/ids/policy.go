@@ -0,0 +1,217 @@
+// Package ids validates leaderboard IDs and generates them for create
+// requests that omit one. Policies are registered by name and selected in
+// config; every policy shares the prefix and length rules.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// ErrNoGenerator is returned by policies that cannot make up IDs, so the
+// create request has to supply one
+var ErrNoGenerator = errors.New("id is required by this policy")
+
+// Policy validates IDs and generates new ones. Generate may use the
+// leaderboard's name, e.g. to derive a readable slug.
+type Policy interface {
+	Validate(id string) error
+	Generate(name string) (string, error)
+}
+
+// Factory builds a policy from its config
+type Factory func(cfg *config.IDPolicyConfig) (Policy, error)
+
+// factories holds the registered policies, by name
+var factories = map[string]Factory{
+	config.IDPolicyFree:    func(*config.IDPolicyConfig) (Policy, error) { return freePolicy{}, nil },
+	config.IDPolicyUUID:    func(*config.IDPolicyConfig) (Policy, error) { return uuidPolicy{}, nil },
+	config.IDPolicySlug:    func(*config.IDPolicyConfig) (Policy, error) { return slugPolicy{}, nil },
+	config.IDPolicyPattern: newPatternPolicy,
+}
+
+// Register adds a policy under a name, replacing any policy already there
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// New builds the configured policy, wrapped with the prefix and length rules
+func New(cfg *config.IDPolicyConfig) (Policy, error) {
+	factory, ok := factories[cfg.Policy]
+	if !ok {
+		return nil, fmt.Errorf("unknown id policy %q (available: %v)", cfg.Policy, available())
+	}
+	policy, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &constrained{policy: policy, prefix: cfg.Prefix, maxLength: cfg.MaxLength}, nil
+}
+
+// Default returns the free policy with the default length cap, which accepts
+// any ID earlier releases accepted short of whitespace and control characters
+func Default() Policy {
+	return &constrained{policy: freePolicy{}, maxLength: 128}
+}
+
+// available lists the registered policy names
+func available() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// constrained applies the rules every policy shares: the prefix, the length
+// cap, and no whitespace or control characters, which break key patterns and logs
+type constrained struct {
+	policy    Policy
+	prefix    string
+	maxLength int
+}
+
+func (c *constrained) Validate(id string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty")
+	}
+	if c.maxLength > 0 && len(id) > c.maxLength {
+		return fmt.Errorf("id is longer than %d bytes", c.maxLength)
+	}
+	if strings.IndexFunc(id, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return fmt.Errorf("id contains whitespace or control characters")
+	}
+	rest, ok := strings.CutPrefix(id, c.prefix)
+	if !ok {
+		return fmt.Errorf("id must start with %q", c.prefix)
+	}
+	return c.policy.Validate(rest)
+}
+
+func (c *constrained) Generate(name string) (string, error) {
+	rest, err := c.policy.Generate(name)
+	if err != nil {
+		return "", err
+	}
+	id := c.prefix + rest
+	// A generated ID must pass the same checks as a supplied one
+	if err := c.Validate(id); err != nil {
+		return "", fmt.Errorf("generated %w", err)
+	}
+	return id, nil
+}
+
+// freePolicy accepts any ID and generates UUIDs
+type freePolicy struct{}
+
+func (freePolicy) Validate(id string) error {
+	if id == "" {
+		return fmt.Errorf("id is empty after the prefix")
+	}
+	return nil
+}
+
+func (freePolicy) Generate(string) (string, error) {
+	return uuid.NewString(), nil
+}
+
+// uuidPolicy accepts canonical lower-case UUIDs of any version
+type uuidPolicy struct{}
+
+func (uuidPolicy) Validate(id string) error {
+	u, err := uuid.Parse(id)
+	if err != nil || u.String() != id {
+		return fmt.Errorf("id must be a lower-case uuid such as %s", uuid.Nil)
+	}
+	return nil
+}
+
+func (uuidPolicy) Generate(string) (string, error) {
+	return uuid.NewString(), nil
+}
+
+// slugRe matches lower-case words joined by single hyphens
+var slugRe = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// slugPolicy accepts slugs like "weekly-race-eu" and generates them from the
+// leaderboard name plus a random suffix, so boards sharing a name do not collide
+type slugPolicy struct{}
+
+func (slugPolicy) Validate(id string) error {
+	if !slugRe.MatchString(id) {
+		return fmt.Errorf("id must be lower-case letters and digits joined by hyphens")
+	}
+	return nil
+}
+
+func (slugPolicy) Generate(name string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating id: %w", err)
+	}
+	if slug := slugify(name); slug != "" {
+		return slug + "-" + hex.EncodeToString(suffix), nil
+	}
+	return hex.EncodeToString(suffix), nil
+}
+
+// slugify lower-cases ASCII letters and digits and collapses everything else
+// into single hyphens, keeping at most 48 characters
+func slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+			if b.Len() >= 48 {
+				break
+			}
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
+
+// patternPolicy accepts IDs matching a configured regular expression. It has
+// no way to make up matching IDs, so creates must supply one.
+type patternPolicy struct {
+	re *regexp.Regexp
+}
+
+func newPatternPolicy(cfg *config.IDPolicyConfig) (Policy, error) {
+	if cfg.Pattern == "" {
+		return nil, fmt.Errorf("id policy pattern needs a pattern")
+	}
+	// Anchor the pattern so it has to match the whole ID
+	re, err := regexp.Compile(`^(?:` + cfg.Pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("id policy pattern: %w", err)
+	}
+	return &patternPolicy{re: re}, nil
+}
+
+func (p *patternPolicy) Validate(id string) error {
+	if !p.re.MatchString(id) {
+		return fmt.Errorf("id must match %s", p.re)
+	}
+	return nil
+}
+
+func (p *patternPolicy) Generate(string) (string, error) {
+	return "", ErrNoGenerator
+}
@@ -0,0 +1,63 @@
+// Package jsonenc selects the JSON encoder used for HTTP responses. The
+// standard library encoder is always available; faster drop-in encoders are
+// compiled in with build tags, e.g. -tags jsoniter or -tags sonic.
+package jsonenc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Std is the name of the encoding/json encoder
+const Std = "std"
+
+// Encoder writes v to w as JSON followed by a newline, matching json.Encoder
+type Encoder interface {
+	Encode(w io.Writer, v interface{}) error
+}
+
+// encoders holds the encoders compiled into this binary, by name
+var encoders = map[string]Encoder{
+	Std: stdEncoder{},
+}
+
+// register adds an encoder; called from init in build-tagged files
+func register(name string, e Encoder) {
+	encoders[name] = e
+}
+
+// Get returns the encoder with the given name, or Std when name is empty
+func Get(name string) (Encoder, error) {
+	if name == "" {
+		name = Std
+	}
+	e, ok := encoders[name]
+	if !ok {
+		return nil, fmt.Errorf("json encoder %q is not compiled in (available: %v); build with -tags %s", name, Available(), name)
+	}
+	return e, nil
+}
+
+// Default returns the encoding/json encoder
+func Default() Encoder {
+	return encoders[Std]
+}
+
+// Available lists the names of the encoders compiled into this binary
+func Available() []string {
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stdEncoder is encoding/json
+type stdEncoder struct{}
+
+func (stdEncoder) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
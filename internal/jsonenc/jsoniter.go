@@ -0,0 +1,22 @@
+//go:build jsoniter
+
+package jsonenc
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func init() {
+	register("jsoniter", jsoniterEncoder{api: jsoniter.ConfigCompatibleWithStandardLibrary})
+}
+
+// jsoniterEncoder is json-iterator configured to match encoding/json output
+type jsoniterEncoder struct {
+	api jsoniter.API
+}
+
+func (e jsoniterEncoder) Encode(w io.Writer, v interface{}) error {
+	return e.api.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,22 @@
+//go:build sonic
+
+package jsonenc
+
+import (
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+func init() {
+	register("sonic", sonicEncoder{api: sonic.ConfigStd})
+}
+
+// sonicEncoder is sonic configured to match encoding/json output
+type sonicEncoder struct {
+	api sonic.API
+}
+
+func (e sonicEncoder) Encode(w io.Writer, v interface{}) error {
+	return e.api.NewEncoder(w).Encode(v)
+}
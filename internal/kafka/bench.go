@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/telemetry"
+)
+
+// BenchmarkApplyBatch measures flushing one consumed batch of size
+// submissions spread over players distinct players through the worker pool
+// into handler, as the consumer does after each batch timeout
+func BenchmarkApplyBatch(handler ScoreHandler, leaderboardID string, workers, size, players int) func(b *testing.B) {
+	return func(b *testing.B) {
+		c := &Consumer{
+			config:   &config.KafkaConfig{Workers: workers},
+			handler:  handler,
+			logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+			reporter: telemetry.Nop(),
+		}
+
+		batch := make([]queuedScore, size)
+		for i := range batch {
+			batch[i].submission = domain.ScoreSubmission{
+				LeaderboardID: leaderboardID,
+				PlayerID:      fmt.Sprintf("player-%d", i%players),
+			}
+		}
+
+		ctx := context.Background()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := range batch {
+				batch[j].submission.Score = int64(i*size + j)
+			}
+			c.applyBatch(ctx, batch, nil)
+		}
+	}
+}
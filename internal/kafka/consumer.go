@@ -3,27 +3,39 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
 	"log/slog"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/leaderboard-redis/internal/config"
 	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/telemetry"
 )
 
 // ScoreHandler processes score submissions
 type ScoreHandler interface {
 	SubmitScore(ctx context.Context, submission domain.ScoreSubmission) error
-	SubmitScoreBatch(ctx context.Context, batch domain.BatchScoreSubmission) error
+	SubmitScoreBatchWithResult(ctx context.Context, batch domain.BatchScoreSubmission) domain.BatchResult
 }
 
+// errMissingIDs is the dead-letter error of a message without a player or
+// leaderboard ID
+var errMissingIDs = errors.New("player_id and leaderboard_id are required")
+
 // Consumer consumes score messages from Kafka
 type Consumer struct {
 	config        *config.KafkaConfig
+	topics        map[string]config.KafkaTopicConfig
 	handler       ScoreHandler
 	logger        *slog.Logger
 	consumerGroup sarama.ConsumerGroup
+	reporter      telemetry.Reporter
+	deadLetters   DeadLetterPublisher
 	ctx           context.Context
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
@@ -36,8 +48,17 @@ func NewConsumer(cfg *config.KafkaConfig, handler ScoreHandler, logger *slog.Log
 	saramaConfig.Version = sarama.V3_0_0_0
 	saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
 	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	if cfg.InitialOffset == config.KafkaOffsetOldest {
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+	saramaConfig.Consumer.Offsets.AutoCommit.Interval = cfg.CommitInterval
+	saramaConfig.Consumer.Offsets.AutoCommit.Enable = cfg.CommitMode != config.KafkaCommitManual
 	saramaConfig.Consumer.Return.Errors = true
 
+	if err := ensureTopics(cfg, saramaConfig); err != nil {
+		return nil, err
+	}
+
 	consumerGroup, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, saramaConfig)
 	if err != nil {
 		return nil, err
@@ -45,22 +66,54 @@ func NewConsumer(cfg *config.KafkaConfig, handler ScoreHandler, logger *slog.Log
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	topics := make(map[string]config.KafkaTopicConfig)
+	for _, spec := range cfg.TopicSpecs() {
+		if spec.UpdateMode != "" && !domain.UpdateMode(spec.UpdateMode).Valid() {
+			cancel()
+			return nil, fmt.Errorf("kafka topic %q: invalid update_mode %q", spec.Name, spec.UpdateMode)
+		}
+		topics[spec.Name] = spec
+	}
+
 	return &Consumer{
 		config:        cfg,
+		topics:        topics,
 		handler:       handler,
 		logger:        logger,
 		consumerGroup: consumerGroup,
+		reporter:      telemetry.Nop(),
 		ctx:           ctx,
 		cancel:        cancel,
 		ready:         make(chan bool),
 	}, nil
 }
 
+// SetErrorReporter sets the reporter that receives consumer failures and panics
+func (c *Consumer) SetErrorReporter(reporter telemetry.Reporter) {
+	c.reporter = reporter
+}
+
+// SetDeadLetterPublisher sets where messages that cannot be applied are
+// republished; without one they are logged and skipped
+func (c *Consumer) SetDeadLetterPublisher(publisher DeadLetterPublisher) {
+	c.deadLetters = publisher
+}
+
 // Start begins consuming messages from Kafka
 func (c *Consumer) Start() error {
+	topics := make([]string, 0, len(c.topics))
+	for name, spec := range c.topics {
+		topics = append(topics, name)
+		c.logger.Info("consuming Kafka topic",
+			"topic", name,
+			"batch_size", spec.BatchSize,
+			"batch_timeout", spec.BatchTimeout,
+			"priority", spec.Priority,
+		)
+	}
 	c.logger.Info("starting Kafka consumer",
 		"brokers", c.config.Brokers,
-		"topic", c.config.Topic,
+		"topics", topics,
 		"group_id", c.config.GroupID,
 	)
 
@@ -73,11 +126,12 @@ func (c *Consumer) Start() error {
 				ready:    c.ready,
 			}
 
-			if err := c.consumerGroup.Consume(c.ctx, []string{c.config.Topic}, handler); err != nil {
+			if err := c.consumerGroup.Consume(c.ctx, topics, handler); err != nil {
 				if err == sarama.ErrClosedConsumerGroup {
 					return
 				}
 				c.logger.Error("error from consumer", "error", err)
+				c.reporter.CaptureError(c.ctx, err, map[string]string{"component": "kafka", "group_id": c.config.GroupID})
 			}
 
 			// Check if context was cancelled
@@ -106,6 +160,7 @@ func (c *Consumer) Start() error {
 					return
 				}
 				c.logger.Error("consumer group error", "error", err)
+				c.reporter.CaptureError(c.ctx, err, map[string]string{"component": "kafka", "group_id": c.config.GroupID})
 			}
 		}
 	}()
@@ -138,14 +193,36 @@ func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
 	return nil
 }
 
-// ConsumeClaim processes messages from a topic partition
+// ConsumeClaim processes messages from a topic partition, batching them with
+// the settings of the claim's topic
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	cfg := h.consumer.config
-	batch := make([]domain.ScoreSubmission, 0, cfg.BatchSize)
+	cfg := h.consumer.topics[claim.Topic()]
+	batch := make([]queuedScore, 0, cfg.BatchSize)
 	batchTimer := time.NewTimer(cfg.BatchTimeout)
 	defer batchTimer.Stop()
 
+	// In manual commit mode offsets only advance once the batch holding them
+	// has been applied; otherwise they are marked as messages arrive
+	manual := h.consumer.config.CommitMode == config.KafkaCommitManual
+	var last *sarama.ConsumerMessage
+	mark := func(message *sarama.ConsumerMessage) {
+		if manual {
+			last = message
+			return
+		}
+		session.MarkMessage(message, "")
+	}
+	commit := func() {
+		if last == nil {
+			return
+		}
+		session.MarkMessage(last, "")
+		session.Commit()
+		last = nil
+	}
+
 	processBatch := func() {
+		defer commit()
 		if len(batch) == 0 {
 			return
 		}
@@ -153,14 +230,17 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		batchSubmission := domain.BatchScoreSubmission{Scores: batch}
-		if err := h.consumer.handler.SubmitScoreBatch(ctx, batchSubmission); err != nil {
-			h.consumer.logger.Error("failed to process batch", "error", err, "batch_size", len(batch))
-		} else {
-			h.consumer.logger.Debug("processed batch", "batch_size", len(batch))
+		tags := map[string]string{
+			"component":  "kafka",
+			"topic":      claim.Topic(),
+			"partition":  strconv.Itoa(int(claim.Partition())),
+			"batch_size": strconv.Itoa(len(batch)),
 		}
+		defer func() { batch = batch[:0] }()
+		// A panic must not take down the claim goroutine and the whole process with it
+		defer telemetry.Recover(ctx, h.consumer.reporter, tags)
 
-		batch = batch[:0]
+		h.consumer.applyBatch(domain.WithSubmissionSource(ctx, domain.SourceKafka), batch, tags)
 	}
 
 	for {
@@ -187,24 +267,29 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 					"offset", message.Offset,
 					"partition", message.Partition,
 				)
-				session.MarkMessage(message, "")
+				h.consumer.deadLetter(session.Context(), message, DeadLetterInvalidJSON, err, 1, nil)
+				mark(message)
 				continue
 			}
 
+			route(cfg, &submission)
+
 			// Validate submission
 			if submission.PlayerID == "" || submission.LeaderboardID == "" {
 				h.consumer.logger.Warn("invalid score submission",
 					"player_id", submission.PlayerID,
 					"leaderboard_id", submission.LeaderboardID,
 				)
-				session.MarkMessage(message, "")
+				h.consumer.deadLetter(session.Context(), message, DeadLetterInvalidSubmission, errMissingIDs, 1, nil)
+				mark(message)
 				continue
 			}
 
-			batch = append(batch, submission)
-			session.MarkMessage(message, "")
+			batch = append(batch, queuedScore{submission: submission, message: message})
+			mark(message)
 
-			if len(batch) >= cfg.BatchSize {
+			// Priority lanes flush once they have drained what is already fetched
+			if len(batch) >= cfg.BatchSize || (cfg.Priority && len(claim.Messages()) == 0) {
 				processBatch()
 				batchTimer.Reset(cfg.BatchTimeout)
 			}
@@ -212,6 +297,153 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 	}
 }
 
+// route applies a topic's routing rules to one of its messages
+func route(spec config.KafkaTopicConfig, submission *domain.ScoreSubmission) {
+	if submission.LeaderboardID == "" {
+		submission.LeaderboardID = spec.LeaderboardID
+	}
+	if spec.UpdateMode != "" {
+		submission.UpdateMode = domain.UpdateMode(spec.UpdateMode)
+	}
+}
+
+// queuedScore is a batched submission with the message it was read from
+type queuedScore struct {
+	submission domain.ScoreSubmission
+	message    *sarama.ConsumerMessage
+}
+
+// applyBatch submits a batch through the worker pool. Submissions are sharded
+// by leaderboard and player, so different players are applied concurrently
+// while each player's scores keep their partition order.
+func (c *Consumer) applyBatch(ctx context.Context, batch []queuedScore, tags map[string]string) {
+	shards := shardByPlayer(batch, c.config.Workers)
+	if len(shards) == 1 {
+		c.submitShard(ctx, shards[0], tags)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		if len(shard) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer telemetry.Recover(ctx, c.reporter, tags)
+			c.submitShard(ctx, shard, tags)
+		}()
+	}
+	wg.Wait()
+}
+
+// submitShard submits one shard of a batch. Submissions rejected for good are
+// dead-lettered at once; others are retried up to RetryAttempts times,
+// RetryDelay apart, before they are reported and dead-lettered.
+func (c *Consumer) submitShard(ctx context.Context, shard []queuedScore, tags map[string]string) {
+	pending := shard
+	for attempt := 1; ; attempt++ {
+		submissions := make([]domain.ScoreSubmission, len(pending))
+		for i, queued := range pending {
+			submissions[i] = queued.submission
+		}
+		result := c.handler.SubmitScoreBatchWithResult(ctx, domain.BatchScoreSubmission{Scores: submissions})
+		if len(result.Failed) == 0 {
+			c.logger.Debug("processed batch", "batch_size", len(pending), "attempt", attempt)
+			return
+		}
+
+		var retry []queuedScore
+		var lastErr error
+		for _, failure := range result.Failed {
+			queued := pending[failure.Index]
+			err := failure.Err
+			if err == nil {
+				err = errors.New(failure.Error)
+			}
+			if isPermanent(err) {
+				c.deadLetter(ctx, queued.message, DeadLetterRejected, err, attempt, tags)
+				continue
+			}
+			retry = append(retry, queued)
+			lastErr = err
+		}
+		if len(retry) == 0 {
+			return
+		}
+
+		if attempt >= c.config.RetryAttempts || !c.wait(ctx, c.config.RetryDelay) {
+			c.logger.Error("failed to process batch", "error", lastErr, "failed", len(retry), "attempts", attempt)
+			c.reporter.CaptureError(ctx, lastErr, tags)
+			for _, queued := range retry {
+				c.deadLetter(ctx, queued.message, DeadLetterProcessingFailed, lastErr, attempt, tags)
+			}
+			return
+		}
+		c.logger.Warn("retrying failed submissions", "error", lastErr, "failed", len(retry), "attempt", attempt)
+		pending = retry
+	}
+}
+
+// wait sleeps for d, returning false if ctx ends first
+func (c *Consumer) wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// deadLetter republishes a message that could not be applied, if a
+// dead-letter publisher is set. A message that cannot be dead-lettered is
+// reported and skipped like one without a publisher.
+func (c *Consumer) deadLetter(ctx context.Context, message *sarama.ConsumerMessage, reason string, cause error, attempts int, tags map[string]string) {
+	if c.deadLetters == nil || message == nil {
+		return
+	}
+	if err := c.deadLetters.PublishDeadLetter(ctx, message, reason, cause, attempts); err != nil {
+		c.logger.Error("failed to dead-letter message",
+			"error", err,
+			"reason", reason,
+			"topic", message.Topic,
+			"partition", message.Partition,
+			"offset", message.Offset,
+		)
+		c.reporter.CaptureError(ctx, err, tags)
+		return
+	}
+	c.logger.Warn("dead-lettered message",
+		"reason", reason,
+		"error", cause,
+		"topic", message.Topic,
+		"partition", message.Partition,
+		"offset", message.Offset,
+	)
+}
+
+// shardByPlayer splits a batch into up to workers shards by a hash of the
+// leaderboard and player, preserving order within each shard
+func shardByPlayer(batch []queuedScore, workers int) [][]queuedScore {
+	if workers <= 1 {
+		return [][]queuedScore{batch}
+	}
+
+	shards := make([][]queuedScore, workers)
+	for _, queued := range batch {
+		hash := fnv.New32a()
+		hash.Write([]byte(queued.submission.LeaderboardID))
+		hash.Write([]byte{0})
+		hash.Write([]byte(queued.submission.PlayerID))
+		i := hash.Sum32() % uint32(workers)
+		shards[i] = append(shards[i], queued)
+	}
+	return shards
+}
+
 // KafkaMessage represents the message format for Kafka
 type KafkaMessage struct {
 	PlayerID      string                 `json:"player_id"`
@@ -219,4 +451,6 @@ type KafkaMessage struct {
 	Score         int64                  `json:"score"`
 	GameID        string                 `json:"game_id,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	// IdempotencyKey matches the key sent on the HTTP copy of the same submission
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
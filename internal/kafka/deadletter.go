@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Dead-letter reasons, sent in the x-dlq-reason header
+const (
+	DeadLetterInvalidJSON       = "invalid_json"
+	DeadLetterInvalidSubmission = "invalid_submission"
+	DeadLetterRejected          = "rejected"
+	DeadLetterProcessingFailed  = "processing_failed"
+)
+
+// Headers added to dead-lettered messages; the original headers are kept
+const (
+	HeaderDeadLetterReason          = "x-dlq-reason"
+	HeaderDeadLetterError           = "x-dlq-error"
+	HeaderDeadLetterSourceTopic     = "x-dlq-source-topic"
+	HeaderDeadLetterSourcePartition = "x-dlq-source-partition"
+	HeaderDeadLetterSourceOffset    = "x-dlq-source-offset"
+	HeaderDeadLetterAttempts        = "x-dlq-attempts"
+	HeaderDeadLetterFailedAt        = "x-dlq-failed-at"
+)
+
+// DeadLetterPublisher republishes messages the consumer could not apply
+type DeadLetterPublisher interface {
+	PublishDeadLetter(ctx context.Context, message *sarama.ConsumerMessage, reason string, cause error, attempts int) error
+}
+
+// DeadLetterProducer publishes failed score messages to a dead-letter topic
+type DeadLetterProducer struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewDeadLetterProducer creates a producer for the configured dead-letter topic
+func NewDeadLetterProducer(brokers []string, topic string) (*DeadLetterProducer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Partitioner = sarama.NewHashPartitioner
+
+	producer, err := sarama.NewSyncProducer(brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating dead-letter producer: %w", err)
+	}
+	return &DeadLetterProducer{producer: producer, topic: topic}, nil
+}
+
+// PublishDeadLetter republishes a message unchanged, with its original key
+// and headers plus headers recording where it came from and why it failed
+func (p *DeadLetterProducer) PublishDeadLetter(ctx context.Context, message *sarama.ConsumerMessage, reason string, cause error, attempts int) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+7)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	causeText := ""
+	if cause != nil {
+		causeText = cause.Error()
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterReason), Value: []byte(reason)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterError), Value: []byte(causeText)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSourceTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSourcePartition), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterSourceOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterAttempts), Value: []byte(strconv.Itoa(attempts))},
+		sarama.RecordHeader{Key: []byte(HeaderDeadLetterFailedAt), Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+
+	produced := &sarama.ProducerMessage{
+		Topic:   p.topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		produced.Key = sarama.ByteEncoder(message.Key)
+	}
+	if _, _, err := p.producer.SendMessage(produced); err != nil {
+		return fmt.Errorf("producing dead letter: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying producer
+func (p *DeadLetterProducer) Close() error {
+	return p.producer.Close()
+}
+
+// isPermanent reports whether a submission failed in a way retrying cannot
+// fix, such as an unknown leaderboard or an invalid score
+func isPermanent(err error) bool {
+	var validation *domain.ValidationError
+	return domain.IsNotFoundError(err) || errors.As(err, &validation) ||
+		errors.Is(err, domain.ErrInvalidRequest) || errors.Is(err, domain.ErrInvalidScore) ||
+		errors.Is(err, domain.ErrInvalidLeaderboard) || errors.Is(err, domain.ErrSubmissionWindow) ||
+		errors.Is(err, domain.ErrQuotaExceeded) || errors.Is(err, domain.ErrPlayerMismatch)
+}
@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// PayoutProducer publishes computed prize payouts for downstream reward systems
+type PayoutProducer struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewPayoutProducer creates a producer for the configured payout topic
+func NewPayoutProducer(brokers []string, topic string) (*PayoutProducer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Partitioner = sarama.NewHashPartitioner
+
+	producer, err := sarama.NewSyncProducer(brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating payout producer: %w", err)
+	}
+	return &PayoutProducer{producer: producer, topic: topic}, nil
+}
+
+// PublishPayouts sends one payout report, keyed by leaderboard so a board's
+// reports stay in order
+func (p *PayoutProducer) PublishPayouts(ctx context.Context, report *domain.PayoutReport) error {
+	value, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("marshaling payout report: %w", err)
+	}
+	_, _, err = p.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: p.topic,
+		Key:   sarama.StringEncoder(report.LeaderboardID),
+		Value: sarama.ByteEncoder(value),
+	})
+	if err != nil {
+		return fmt.Errorf("producing payout report: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying producer
+func (p *PayoutProducer) Close() error {
+	return p.producer.Close()
+}
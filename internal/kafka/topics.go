@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// ErrTopicNotFound is returned at startup when a consumed topic does not exist
+// and auto-creation is disabled
+var ErrTopicNotFound = errors.New("kafka topic does not exist")
+
+// ensureTopics checks that every consumed topic, and the dead-letter topic when
+// enabled, exists, creating missing ones when auto_create_topics is set
+func ensureTopics(cfg *config.KafkaConfig, saramaConfig *sarama.Config) error {
+	admin, err := sarama.NewClusterAdmin(cfg.Brokers, saramaConfig)
+	if err != nil {
+		return fmt.Errorf("connecting kafka admin client: %w", err)
+	}
+	defer admin.Close()
+
+	existing, err := admin.ListTopics()
+	if err != nil {
+		return fmt.Errorf("listing kafka topics: %w", err)
+	}
+
+	var names []string
+	for _, spec := range cfg.TopicSpecs() {
+		names = append(names, spec.Name)
+	}
+	if cfg.DeadLetter.Enabled {
+		names = append(names, cfg.DeadLetter.Topic)
+	}
+
+	for _, name := range names {
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		if !cfg.AutoCreateTopics {
+			return fmt.Errorf("%w: %q (create it or set kafka.auto_create_topics)", ErrTopicNotFound, name)
+		}
+
+		detail := &sarama.TopicDetail{
+			NumPartitions:     cfg.Partitions,
+			ReplicationFactor: cfg.ReplicationFactor,
+		}
+		if err := admin.CreateTopic(name, detail, false); err != nil && !errors.Is(err, sarama.ErrTopicAlreadyExists) {
+			return fmt.Errorf("creating kafka topic %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// Manager builds module loggers and lets their levels change at runtime
+type Manager struct {
+	base   slog.Handler
+	output io.Closer
+	root   *slog.LevelVar
+
+	mu        sync.RWMutex
+	levels    map[string]*slog.LevelVar
+	overrides map[string]bool
+}
+
+// New creates a logging manager from configuration
+func New(cfg *config.LoggingConfig) (*Manager, error) {
+	rootLevel, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out    io.Writer
+		closer io.Closer
+	)
+	switch cfg.Output {
+	case "", "stdout":
+		out = os.Stdout
+	case "stderr":
+		out = os.Stderr
+	default:
+		file, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+		out = file
+		closer = file
+	}
+
+	// Level filtering happens per module, so the base handler accepts everything
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var base slog.Handler
+	switch cfg.Format {
+	case "", config.LogFormatJSON:
+		base = slog.NewJSONHandler(out, opts)
+	case config.LogFormatText:
+		base = slog.NewTextHandler(out, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	m := &Manager{
+		base:      base,
+		output:    closer,
+		root:      new(slog.LevelVar),
+		levels:    make(map[string]*slog.LevelVar),
+		overrides: make(map[string]bool),
+	}
+	m.root.Set(rootLevel)
+
+	for module, value := range cfg.Modules {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		m.levelVar(module).Set(level)
+		m.overrides[module] = true
+	}
+	return m, nil
+}
+
+// Logger returns the root logger
+func (m *Manager) Logger() *slog.Logger {
+	return slog.New(&levelHandler{inner: m.base, level: m.root})
+}
+
+// For returns a logger for a module, tagged with a "module" attribute
+func (m *Manager) For(module string) *slog.Logger {
+	m.mu.Lock()
+	level := m.levelVar(module)
+	m.mu.Unlock()
+
+	inner := m.base.WithAttrs([]slog.Attr{slog.String("module", module)})
+	return slog.New(&levelHandler{inner: inner, level: level})
+}
+
+// levelVar returns the level variable for a module, creating it from the root level.
+// Callers that may race must hold m.mu.
+func (m *Manager) levelVar(module string) *slog.LevelVar {
+	level, ok := m.levels[module]
+	if !ok {
+		level = new(slog.LevelVar)
+		level.Set(m.root.Level())
+		m.levels[module] = level
+	}
+	return level
+}
+
+// SetLevel changes the level of a module, or the root level when module is empty.
+// Changing the root level also changes modules without their own override.
+func (m *Manager) SetLevel(module string, level slog.Level) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if module == "" {
+		m.root.Set(level)
+		for name, moduleLevel := range m.levels {
+			if !m.overrides[name] {
+				moduleLevel.Set(level)
+			}
+		}
+		return
+	}
+
+	m.levelVar(module).Set(level)
+	m.overrides[module] = true
+}
+
+// Levels returns the root level and all module overrides
+func (m *Manager) Levels() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	levels := map[string]string{"root": strings.ToLower(m.root.Level().String())}
+	for name := range m.overrides {
+		levels[name] = strings.ToLower(m.levels[name].Level().String())
+	}
+	return levels
+}
+
+// Close releases the log file, if any
+func (m *Manager) Close() error {
+	if m.output == nil {
+		return nil
+	}
+	return m.output.Close()
+}
+
+// ParseLevel converts a level name such as "debug" or "warn" to a slog level
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("invalid log level %q", value)
+	}
+	return level, nil
+}
+
+// levelHandler filters records against a level that can change at runtime
+type levelHandler struct {
+	inner slog.Handler
+	level *slog.LevelVar
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.inner.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{inner: h.inner.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{inner: h.inner.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,116 @@
+package migration
+
+import "time"
+
+// Mismatch kinds
+const (
+	MismatchMissingInLegacy = "missing_in_legacy"
+	MismatchMissingInNew    = "missing_in_new"
+	MismatchScore           = "score"
+	MismatchRank            = "rank"
+)
+
+// Mismatch is one difference between the two systems' standings. Ranks are
+// 0 where the player is absent from that system's top entries.
+type Mismatch struct {
+	Kind        string `json:"kind"`
+	PlayerID    string `json:"player_id"`
+	NewRank     int    `json:"new_rank,omitempty"`
+	LegacyRank  int    `json:"legacy_rank,omitempty"`
+	NewScore    *int64 `json:"new_score,omitempty"`
+	LegacyScore *int64 `json:"legacy_score,omitempty"`
+}
+
+// Report is the outcome of comparing one leaderboard
+type Report struct {
+	LeaderboardID string     `json:"leaderboard_id"`
+	ComparedAt    time.Time  `json:"compared_at"`
+	TopN          int        `json:"top_n"`
+	Matched       int        `json:"matched"`
+	MismatchCount int        `json:"mismatch_count"`
+	Mismatches    []Mismatch `json:"mismatches,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// Compare diffs the top n entries of each system. Both lists are best
+// first. Ranks are competition ranks, so players tied on score compare equal
+// whatever order each system lists them in. newer may run past n, so a legacy
+// top player ranked just below the new cut is reported as a rank difference
+// rather than as missing.
+func Compare(newer, legacy []Entry, n, maxMismatches int) Report {
+	report := Report{TopN: n}
+	newRanks := competitionRanks(newer)
+	legacyRanks := competitionRanks(legacy)
+
+	add := func(m Mismatch) {
+		report.MismatchCount++
+		if len(report.Mismatches) < maxMismatches {
+			report.Mismatches = append(report.Mismatches, m)
+		}
+	}
+
+	for i, entry := range newer {
+		if i >= n {
+			break
+		}
+		newScore := entry.Score
+		legacyRank, ok := legacyRanks[entry.PlayerID]
+		if !ok {
+			add(Mismatch{Kind: MismatchMissingInLegacy, PlayerID: entry.PlayerID, NewRank: newRanks[entry.PlayerID].rank, NewScore: &newScore})
+			continue
+		}
+		legacyScore := legacyRank.score
+		switch {
+		case legacyScore != newScore:
+			add(Mismatch{Kind: MismatchScore, PlayerID: entry.PlayerID, NewRank: newRanks[entry.PlayerID].rank, LegacyRank: legacyRank.rank, NewScore: &newScore, LegacyScore: &legacyScore})
+		case legacyRank.rank != newRanks[entry.PlayerID].rank:
+			add(Mismatch{Kind: MismatchRank, PlayerID: entry.PlayerID, NewRank: newRanks[entry.PlayerID].rank, LegacyRank: legacyRank.rank, NewScore: &newScore, LegacyScore: &legacyScore})
+		default:
+			report.Matched++
+		}
+	}
+
+	for i, entry := range legacy {
+		if i >= n {
+			break
+		}
+		legacyScore := entry.Score
+		newRank, ok := newRanks[entry.PlayerID]
+		if !ok {
+			add(Mismatch{Kind: MismatchMissingInNew, PlayerID: entry.PlayerID, LegacyRank: legacyRanks[entry.PlayerID].rank, LegacyScore: &legacyScore})
+			continue
+		}
+		// Players in the new top n were compared above
+		if newRank.index < n {
+			continue
+		}
+		newScore := newRank.score
+		kind := MismatchRank
+		if newScore != legacyScore {
+			kind = MismatchScore
+		}
+		add(Mismatch{Kind: kind, PlayerID: entry.PlayerID, NewRank: newRank.rank, LegacyRank: legacyRanks[entry.PlayerID].rank, NewScore: &newScore, LegacyScore: &legacyScore})
+	}
+	return report
+}
+
+// standing is a player's position in one system's list
+type standing struct {
+	index int
+	rank  int
+	score int64
+}
+
+// competitionRanks ranks a best-first list, giving tied scores the rank of
+// the first of them
+func competitionRanks(entries []Entry) map[string]standing {
+	ranks := make(map[string]standing, len(entries))
+	rank := 0
+	for i, entry := range entries {
+		if i == 0 || entry.Score != entries[i-1].Score {
+			rank = i + 1
+		}
+		ranks[entry.PlayerID] = standing{index: i, rank: rank, score: entry.Score}
+	}
+	return ranks
+}
@@ -0,0 +1,229 @@
+// Package migration supports moving off a legacy leaderboard service: it
+// mirrors accepted submissions to the legacy service and compares the two
+// systems' standings
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// forwardAttempts is how many times a submission is offered to the legacy
+// service before it is counted as failed
+const forwardAttempts = 3
+
+// ForwarderStats reports dual-write throughput and loss
+type ForwarderStats struct {
+	URL       string `json:"url"`
+	Queued    int    `json:"queued"`
+	Forwarded int64  `json:"forwarded"`
+	Failed    int64  `json:"failed"`
+	Dropped   int64  `json:"dropped"`
+}
+
+// Forwarder posts accepted submissions to the legacy service. Forward never
+// blocks the write path: when the queue is full the submission is dropped
+// and counted.
+type Forwarder struct {
+	config    *config.DualWriteConfig
+	client    *http.Client
+	logger    *slog.Logger
+	queue     chan domain.ScoreSubmission
+	forwarded atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	running   bool
+}
+
+// NewForwarder creates a forwarder for the configured legacy endpoint
+func NewForwarder(cfg *config.DualWriteConfig, logger *slog.Logger) (*Forwarder, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("dual write needs a url")
+	}
+	return &Forwarder{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		logger: logger,
+		queue:  make(chan domain.ScoreSubmission, cfg.QueueSize),
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Forward enqueues a submission for the legacy service
+func (f *Forwarder) Forward(submission domain.ScoreSubmission) {
+	select {
+	case f.queue <- submission:
+	default:
+		if f.dropped.Add(1) == 1 {
+			f.logger.Warn("dual write queue full, dropping submissions",
+				"queue_size", f.config.QueueSize,
+			)
+		}
+	}
+}
+
+// Stats returns the forwarder's counters
+func (f *Forwarder) Stats() ForwarderStats {
+	return ForwarderStats{
+		URL:       f.config.URL,
+		Queued:    len(f.queue),
+		Forwarded: f.forwarded.Load(),
+		Failed:    f.failed.Load(),
+		Dropped:   f.dropped.Load(),
+	}
+}
+
+// Start begins forwarding with the configured number of workers
+func (f *Forwarder) Start(ctx context.Context) error {
+	f.mu.Lock()
+	if f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.running = true
+	f.mu.Unlock()
+
+	f.logger.Info("dual write forwarder started", "url", f.config.URL, "workers", f.config.Workers)
+
+	for i := 0; i < f.config.Workers; i++ {
+		f.wg.Add(1)
+		go f.run(ctx)
+	}
+	return nil
+}
+
+// Stop forwards what is still queued, within a grace period, and stops the workers
+func (f *Forwarder) Stop() error {
+	f.mu.Lock()
+	if !f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.mu.Unlock()
+
+	close(f.stopCh)
+	f.wg.Wait()
+
+	f.mu.Lock()
+	f.running = false
+	f.mu.Unlock()
+
+	f.logger.Info("dual write forwarder stopped",
+		"forwarded", f.forwarded.Load(),
+		"failed", f.failed.Load(),
+		"dropped", f.dropped.Load(),
+	)
+	return nil
+}
+
+// run is one worker's loop
+func (f *Forwarder) run(ctx context.Context) {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			f.drain()
+			return
+		case <-f.stopCh:
+			f.drain()
+			return
+		case submission := <-f.queue:
+			f.forward(ctx, submission)
+		}
+	}
+}
+
+// drain forwards whatever is still queued on shutdown
+func (f *Forwarder) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for {
+		select {
+		case submission := <-f.queue:
+			if ctx.Err() != nil {
+				f.dropped.Add(1)
+				continue
+			}
+			f.forward(ctx, submission)
+		default:
+			return
+		}
+	}
+}
+
+// forward posts one submission, retrying transient failures
+func (f *Forwarder) forward(ctx context.Context, submission domain.ScoreSubmission) {
+	body, err := json.Marshal(submission)
+	if err != nil {
+		f.failed.Add(1)
+		return
+	}
+
+	for attempt := 1; attempt <= forwardAttempts; attempt++ {
+		if err = f.post(ctx, body); err == nil {
+			f.forwarded.Add(1)
+			return
+		}
+		var permanent *permanentError
+		if ctx.Err() != nil || errors.As(err, &permanent) {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+	}
+	f.failed.Add(1)
+	f.logger.Warn("failed to forward submission to legacy service",
+		"player_id", submission.PlayerID,
+		"leaderboard_id", submission.LeaderboardID,
+		"error", err,
+	)
+}
+
+// post sends the request; 4xx answers other than 429 are not retried since
+// the legacy service will keep refusing the same body
+func (f *Forwarder) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range f.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("legacy service answered %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err}
+	}
+	return err
+}
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct{ error }
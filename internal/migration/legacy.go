@@ -0,0 +1,108 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// Entry is one player's standing in either system
+type Entry struct {
+	PlayerID string `json:"player_id"`
+	Score    int64  `json:"score"`
+}
+
+// LegacyClient reads the legacy service's top entries
+type LegacyClient struct {
+	config *config.CompareConfig
+	client *http.Client
+}
+
+// NewLegacyClient creates a client for the configured top-N endpoint
+func NewLegacyClient(cfg *config.CompareConfig) (*LegacyClient, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("comparison needs a legacy url")
+	}
+	return &LegacyClient{config: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// Top returns the legacy service's top n entries of a leaderboard, best first
+func (c *LegacyClient) Top(ctx context.Context, leaderboardID string, n int) ([]Entry, error) {
+	target := strings.NewReplacer(
+		"{id}", url.PathEscape(leaderboardID),
+		"{n}", strconv.Itoa(n),
+	).Replace(c.config.URL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building legacy request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range c.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading legacy standings: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading legacy standings: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("legacy service answered %d", resp.StatusCode)
+	}
+	return c.parse(body, n)
+}
+
+// parse extracts the entries at the configured path and fields
+func (c *LegacyClient) parse(body []byte, n int) ([]Entry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding legacy standings: %w", err)
+	}
+	if c.config.EntriesPath != "." {
+		for _, part := range strings.Split(c.config.EntriesPath, ".") {
+			object, ok := doc.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("legacy standings have no %s", c.config.EntriesPath)
+			}
+			doc = object[part]
+		}
+	}
+	items, ok := doc.([]any)
+	if !ok {
+		return nil, fmt.Errorf("legacy standings %s is not an array", c.config.EntriesPath)
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for i, item := range items {
+		if len(entries) == n {
+			break
+		}
+		object, _ := item.(map[string]any)
+		player := fmt.Sprint(object[c.config.PlayerField])
+		number, ok := object[c.config.ScoreField].(json.Number)
+		if object == nil || object[c.config.PlayerField] == nil || !ok {
+			return nil, fmt.Errorf("legacy entry %d lacks %s or a numeric %s", i, c.config.PlayerField, c.config.ScoreField)
+		}
+		score, err := number.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("legacy entry %d has score %s", i, number)
+		}
+		entries = append(entries, Entry{PlayerID: player, Score: int64(math.Round(score))})
+	}
+	return entries, nil
+}
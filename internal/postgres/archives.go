@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateArchive stores the final standings of a leaderboard season. Entries are
+// pulled from next, in rank order, until it returns an empty page, and copied in
+// the same transaction as the archive row.
+func (r *Repository) CreateArchive(ctx context.Context, archive domain.SeasonArchive, next func() ([]domain.LeaderboardEntry, error)) (*domain.SeasonArchive, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning season archive: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO leaderboard_archives (leaderboard_id, season, reset_period, period_start, version, player_count, archived_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (leaderboard_id, season) DO NOTHING
+		RETURNING id
+	`
+	archivedAt := archive.ArchivedAt.UTC()
+	err = tx.QueryRow(ctx, query, archive.LeaderboardID, archive.Season, string(archive.ResetPeriod),
+		archive.PeriodStart, archive.Version, archive.PlayerCount, archivedAt).Scan(&archive.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSeasonExists
+		}
+		return nil, fmt.Errorf("inserting season archive: %w", err)
+	}
+
+	var page []domain.LeaderboardEntry
+	rows := pgx.CopyFromFunc(func() ([]any, error) {
+		if len(page) == 0 {
+			var err error
+			if page, err = next(); err != nil || len(page) == 0 {
+				return nil, err
+			}
+		}
+		entry := page[0]
+		page = page[1:]
+		return []any{archive.ID, entry.Rank, entry.PlayerID, entry.Score}, nil
+	})
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"leaderboard_archive_entries"},
+		[]string{"archive_id", "rank", "player_id", "score"}, rows)
+	if err != nil {
+		return nil, fmt.Errorf("copying season archive entries: %w", err)
+	}
+
+	archive.PlayerCount = copied
+	_, err = tx.Exec(ctx, `UPDATE leaderboard_archives SET player_count = $2 WHERE id = $1`, archive.ID, archive.PlayerCount)
+	if err != nil {
+		return nil, fmt.Errorf("finishing season archive: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing season archive: %w", err)
+	}
+
+	archive.ArchivedAt = archivedAt
+	return &archive, nil
+}
+
+// archiveColumns lists the leaderboard_archives columns in the order scanArchive expects
+const archiveColumns = `id, leaderboard_id, season, reset_period, period_start, version, player_count, archived_at`
+
+// scanArchive scans a leaderboard_archives row selected with archiveColumns
+func scanArchive(row pgx.Row) (domain.SeasonArchive, error) {
+	var archive domain.SeasonArchive
+	err := row.Scan(&archive.ID, &archive.LeaderboardID, &archive.Season, &archive.ResetPeriod,
+		&archive.PeriodStart, &archive.Version, &archive.PlayerCount, &archive.ArchivedAt)
+	return archive, err
+}
+
+// ListArchives returns a leaderboard's archived seasons, newest first, without their entries
+func (r *Repository) ListArchives(ctx context.Context, leaderboardID string) ([]domain.SeasonArchive, error) {
+	query := `SELECT ` + archiveColumns + ` FROM leaderboard_archives
+		WHERE leaderboard_id = $1 ORDER BY archived_at DESC, id DESC`
+	rows, err := r.pool.Query(ctx, query, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing season archives: %w", err)
+	}
+	defer rows.Close()
+
+	archives := []domain.SeasonArchive{}
+	for rows.Next() {
+		archive, err := scanArchive(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning season archive: %w", err)
+		}
+		archives = append(archives, archive)
+	}
+	return archives, rows.Err()
+}
+
+// GetArchive returns an archived season with its entries in rank order
+func (r *Repository) GetArchive(ctx context.Context, leaderboardID, season string) (*domain.SeasonArchive, error) {
+	query := `SELECT ` + archiveColumns + ` FROM leaderboard_archives WHERE leaderboard_id = $1 AND season = $2`
+	archive, err := scanArchive(r.pool.QueryRow(ctx, query, leaderboardID, season))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSeasonNotFound
+		}
+		return nil, fmt.Errorf("getting season archive: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT rank, player_id, score FROM leaderboard_archive_entries WHERE archive_id = $1 ORDER BY rank`, archive.ID)
+	if err != nil {
+		return nil, fmt.Errorf("reading season archive entries: %w", err)
+	}
+	defer rows.Close()
+
+	archive.Entries = make([]domain.LeaderboardEntry, 0, archive.PlayerCount)
+	for rows.Next() {
+		var entry domain.LeaderboardEntry
+		if err := rows.Scan(&entry.Rank, &entry.PlayerID, &entry.Score); err != nil {
+			return nil, fmt.Errorf("scanning season archive entry: %w", err)
+		}
+		entry.IsGhost = domain.IsGhostID(entry.PlayerID)
+		archive.Entries = append(archive.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading season archive entries: %w", err)
+	}
+	return &archive, nil
+}
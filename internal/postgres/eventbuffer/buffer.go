@@ -0,0 +1,181 @@
+// Package eventbuffer writes score events to PostgreSQL behind the request
+// path, collecting them in a bounded queue and inserting them in batches.
+package eventbuffer
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// Buffer queues score events and writes them with batched COPY inserts.
+// Events recorded while it is stopped or full are inserted directly, so an
+// event is only lost if its write fails.
+type Buffer struct {
+	postgres *postgres.Repository
+	config   *config.EventBufferConfig
+	logger   *slog.Logger
+	clock    clock.Clock
+	events   chan domain.ScoreEvent
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// New creates a buffer holding up to the configured number of events
+func New(postgres *postgres.Repository, cfg *config.EventBufferConfig, logger *slog.Logger) *Buffer {
+	return &Buffer{
+		postgres: postgres,
+		config:   cfg,
+		logger:   logger,
+		clock:    clock.Real(),
+		events:   make(chan domain.ScoreEvent, cfg.Size),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (b *Buffer) SetClock(c clock.Clock) {
+	b.clock = c
+}
+
+// Start begins writing queued events in the background
+func (b *Buffer) Start(ctx context.Context) error {
+	b.mu.Lock()
+	if b.running {
+		b.mu.Unlock()
+		return nil
+	}
+	b.running = true
+	b.mu.Unlock()
+
+	b.logger.Info("score event buffer started",
+		"size", b.config.Size,
+		"batch_size", b.config.BatchSize,
+		"flush_interval", b.config.FlushInterval,
+	)
+
+	go b.run(ctx)
+	return nil
+}
+
+// Stop stops accepting events and returns once every queued event is written
+func (b *Buffer) Stop() error {
+	b.mu.Lock()
+	if !b.running {
+		b.mu.Unlock()
+		return nil
+	}
+	// Later events are inserted directly, so nothing lands in the queue after the drain
+	b.running = false
+	b.mu.Unlock()
+
+	close(b.stopCh)
+	<-b.doneCh
+
+	b.logger.Info("score event buffer stopped")
+	return nil
+}
+
+// RecordEvent queues an event, or inserts it directly when the buffer is
+// stopped or its queue is full
+func (b *Buffer) RecordEvent(ctx context.Context, event domain.ScoreEvent) error {
+	b.mu.Lock()
+	if b.running {
+		select {
+		case b.events <- event:
+			b.mu.Unlock()
+			return nil
+		default:
+		}
+	}
+	b.mu.Unlock()
+
+	return b.postgres.RecordEvent(ctx, event)
+}
+
+// Pending returns the number of events waiting in the queue
+func (b *Buffer) Pending() int {
+	return len(b.events)
+}
+
+// run is the main writer loop
+func (b *Buffer) run(ctx context.Context) {
+	defer close(b.doneCh)
+
+	ticker := b.clock.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.ScoreEvent, 0, b.config.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			b.drain(context.Background(), batch)
+			return
+		case <-b.stopCh:
+			b.drain(context.Background(), batch)
+			return
+		case event := <-b.events:
+			batch = append(batch, event)
+			if len(batch) >= b.config.BatchSize {
+				b.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C():
+			if len(batch) > 0 {
+				b.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// drain writes the current batch and everything still queued
+func (b *Buffer) drain(ctx context.Context, batch []domain.ScoreEvent) {
+	for {
+		select {
+		case event := <-b.events:
+			batch = append(batch, event)
+			if len(batch) >= b.config.BatchSize {
+				b.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				b.flush(ctx, batch)
+			}
+			return
+		}
+	}
+}
+
+// flush writes a batch with one COPY. A failed COPY is retried row by row so
+// one bad event does not take the rest of its batch with it.
+func (b *Buffer) flush(ctx context.Context, batch []domain.ScoreEvent) {
+	err := b.postgres.CopyEvents(ctx, batch)
+	if err == nil {
+		b.logger.Debug("wrote buffered score events", "events", len(batch))
+		return
+	}
+	b.logger.Warn("failed to copy buffered score events, inserting individually",
+		"events", len(batch),
+		"error", err,
+	)
+
+	for _, event := range batch {
+		if err := b.postgres.RecordEvent(ctx, event); err != nil {
+			b.logger.Error("failed to record buffered score event",
+				"leaderboard_id", event.LeaderboardID,
+				"player_id", event.PlayerID,
+				"error", err,
+			)
+		}
+	}
+}
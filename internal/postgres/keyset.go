@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Keyset is a position in a listing ordered by (created_at, id). Rows sharing
+// a timestamp are ordered by id, so a listing never skips or repeats a row
+// between pages however many rows are inserted meanwhile.
+type Keyset struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// Encode returns the position as an opaque cursor. Timestamps are kept to the
+// microsecond, the precision PostgreSQL stores.
+func (k Keyset) Encode() string {
+	raw := strconv.FormatInt(k.CreatedAt.UnixMicro(), 10) + ":" + strconv.FormatInt(k.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeKeyset parses a cursor returned by Keyset.Encode. An empty cursor
+// starts at the beginning of the listing.
+func DecodeKeyset(cursor string) (*Keyset, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidRequest)
+	}
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidRequest)
+	}
+	createdAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidRequest)
+	}
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidRequest)
+	}
+	return &Keyset{CreatedAt: time.UnixMicro(createdAt).UTC(), ID: rowID}, nil
+}
+
+// KeysetPage selects one page of a keyset listing: up to Limit rows after
+// the After position, oldest first unless Descending is set
+type KeysetPage struct {
+	After      *Keyset
+	Limit      int
+	Descending bool
+}
+
+// keysetQuery completes a query whose WHERE clause binds args with the page's
+// position predicate, ordering and limit. timeColumn and idColumn name the
+// keyset columns; an index on (..., timeColumn, idColumn) serves both
+// directions. One row beyond the limit is fetched so the caller can tell
+// whether another page follows.
+func keysetQuery(query string, args []interface{}, timeColumn, idColumn string, page KeysetPage) (string, []interface{}) {
+	op, direction := ">", "ASC"
+	if page.Descending {
+		op, direction = "<", "DESC"
+	}
+	if page.After != nil {
+		query += fmt.Sprintf(" AND (%s, %s) %s ($%d, $%d)", timeColumn, idColumn, op, len(args)+1, len(args)+2)
+		args = append(args, page.After.CreatedAt, page.After.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT $%d", timeColumn, direction, idColumn, direction, len(args)+1)
+	return query, append(args, page.Limit+1)
+}
+
+// nextKeyset trims the extra row fetched by keysetQuery and returns the
+// position after the last kept row, or nil on the last page
+func nextKeyset(count, limit int, last func(i int) Keyset) (int, *Keyset) {
+	if count <= limit || limit <= 0 {
+		return count, nil
+	}
+	next := last(limit - 1)
+	return limit, &next
+}
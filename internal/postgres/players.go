@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// playerColumns lists the players columns in the order scanPlayer expects
+const playerColumns = `id, username, email, avatar_url, metadata, created_at, updated_at`
+
+// scanPlayer scans a players row selected with playerColumns
+func scanPlayer(row pgx.Row) (domain.Player, error) {
+	var player domain.Player
+	var metadata []byte
+	err := row.Scan(&player.ID, &player.Username, &player.Email, &player.AvatarURL, &metadata,
+		&player.CreatedAt, &player.UpdatedAt)
+	if err != nil {
+		return player, err
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &player.Metadata); err != nil {
+			return player, fmt.Errorf("unmarshaling player metadata: %w", err)
+		}
+	}
+	return player, nil
+}
+
+// marshalMetadata encodes metadata for a JSONB column; nil stays NULL
+func marshalMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling metadata: %w", err)
+	}
+	return data, nil
+}
+
+// CreatePlayer stores a new player profile
+func (r *Repository) CreatePlayer(ctx context.Context, player domain.Player) error {
+	metadata, err := marshalMetadata(player.Metadata)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO players (id, username, email, avatar_url, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
+	`
+	result, err := r.pool.Exec(ctx, query, player.ID, player.Username, player.Email, player.AvatarURL, metadata,
+		player.CreatedAt.UTC(), player.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("creating player: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrProfileExists
+	}
+	return nil
+}
+
+// GetPlayer returns a player profile
+func (r *Repository) GetPlayer(ctx context.Context, playerID string) (*domain.Player, error) {
+	query := `SELECT ` + playerColumns + ` FROM players WHERE id = $1`
+	player, err := scanPlayer(r.pool.QueryRow(ctx, query, playerID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("getting player: %w", err)
+	}
+	return &player, nil
+}
+
+// GetPlayers returns the profiles of the given players that exist, by ID
+func (r *Repository) GetPlayers(ctx context.Context, playerIDs []string) (map[string]domain.Player, error) {
+	players := make(map[string]domain.Player, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return players, nil
+	}
+
+	query := `SELECT ` + playerColumns + ` FROM players WHERE id = ANY($1)`
+	rows, err := r.pool.Query(ctx, query, playerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("getting players: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		player, err := scanPlayer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning player: %w", err)
+		}
+		players[player.ID] = player
+	}
+	return players, rows.Err()
+}
+
+// UpdatePlayer replaces a player profile's fields
+func (r *Repository) UpdatePlayer(ctx context.Context, player domain.Player) error {
+	metadata, err := marshalMetadata(player.Metadata)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE players SET username = $2, email = $3, avatar_url = $4, metadata = $5, updated_at = $6
+		WHERE id = $1
+	`
+	result, err := r.pool.Exec(ctx, query, player.ID, player.Username, player.Email, player.AvatarURL, metadata,
+		player.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("updating player: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrProfileNotFound
+	}
+	return nil
+}
+
+// DeletePlayer removes a player profile; the player's scores are kept
+func (r *Repository) DeletePlayer(ctx context.Context, playerID string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM players WHERE id = $1`, playerID)
+	if err != nil {
+		return fmt.Errorf("deleting player: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrProfileNotFound
+	}
+	return nil
+}
+
+// ListPlayers returns up to limit player profiles with IDs after the given
+// one, in ID order
+func (r *Repository) ListPlayers(ctx context.Context, afterID string, limit int) ([]domain.Player, error) {
+	query := `SELECT ` + playerColumns + ` FROM players WHERE id > $1 ORDER BY id LIMIT $2`
+	rows, err := r.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing players: %w", err)
+	}
+	defer rows.Close()
+
+	players := []domain.Player{}
+	for rows.Next() {
+		player, err := scanPlayer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning player: %w", err)
+		}
+		players = append(players, player)
+	}
+	return players, rows.Err()
+}
+
+// RecordAdminAudit stores an admin audit log entry
+func (r *Repository) RecordAdminAudit(ctx context.Context, entry domain.AdminAuditEntry) error {
+	query := `
+		INSERT INTO admin_audit_log (actor, on_behalf_of, method, path, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.pool.Exec(ctx, query, entry.Actor, entry.OnBehalfOf, entry.Method, entry.Path, entry.RequestID,
+		entry.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("recording admin audit entry: %w", err)
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/leaderboard-redis/internal/config"
 	"github.com/leaderboard-redis/internal/domain"
@@ -19,8 +21,18 @@ type Repository struct {
 	logger *slog.Logger
 }
 
+// Option customizes how a Repository connects
+type Option func(*pgxpool.Config)
+
+// WithTracer installs a pgx tracer on every pooled connection
+func WithTracer(tracer pgx.QueryTracer) Option {
+	return func(poolConfig *pgxpool.Config) {
+		poolConfig.ConnConfig.Tracer = tracer
+	}
+}
+
 // NewRepository creates a new PostgreSQL repository
-func NewRepository(cfg *config.PostgresConfig, logger *slog.Logger) (*Repository, error) {
+func NewRepository(cfg *config.PostgresConfig, logger *slog.Logger, opts ...Option) (*Repository, error) {
 	poolConfig, err := pgxpool.ParseConfig(cfg.ConnectionString())
 	if err != nil {
 		return nil, fmt.Errorf("parsing connection string: %w", err)
@@ -30,6 +42,9 @@ func NewRepository(cfg *config.PostgresConfig, logger *slog.Logger) (*Repository
 	poolConfig.MinConns = int32(cfg.MinConnections)
 	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	for _, opt := range opts {
+		opt(poolConfig)
+	}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
@@ -93,6 +108,150 @@ func (r *Repository) RunMigrations(ctx context.Context) error {
 		`CREATE INDEX IF NOT EXISTS idx_player_scores_leaderboard ON player_scores(leaderboard_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_player_scores_score ON player_scores(leaderboard_id, score DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_score_events_player ON score_events(player_id, created_at DESC)`,
+		`ALTER TABLE score_events ADD COLUMN IF NOT EXISTS sample_rate INT NOT NULL DEFAULT 1`,
+		`ALTER TABLE score_events ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP`,
+		`CREATE INDEX IF NOT EXISTS idx_score_events_leaderboard ON score_events(leaderboard_id)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS shadow_id VARCHAR(64)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS update_throttle_ms BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS min_rank_change BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS min_score_change BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS last_reset_at TIMESTAMP`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS score_unit VARCHAR(32) NOT NULL DEFAULT ''`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS score_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS score_offset BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS score_rounding VARCHAR(10) NOT NULL DEFAULT 'round'`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS min_score BIGINT`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS max_score BIGINT`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT ''`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS open_at TIMESTAMP`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS close_at TIMESTAMP`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS min_submissions BIGINT NOT NULL DEFAULT 0`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_ghosts (
+			leaderboard_id VARCHAR(64) NOT NULL REFERENCES leaderboards(id) ON DELETE CASCADE,
+			ghost_id VARCHAR(64) NOT NULL,
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			score BIGINT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (leaderboard_id, ghost_id)
+		)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS review_threshold BIGINT`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS score_formula TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS script_version INT NOT NULL DEFAULT 0`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_scripts (
+			leaderboard_id VARCHAR(64) NOT NULL REFERENCES leaderboards(id) ON DELETE CASCADE,
+			version INT NOT NULL,
+			source TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (leaderboard_id, version)
+		)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS parent_id VARCHAR(64) REFERENCES leaderboards(id) ON DELETE SET NULL`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS filter TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX IF NOT EXISTS idx_leaderboards_parent ON leaderboards(parent_id) WHERE parent_id IS NOT NULL`,
+		`CREATE TABLE IF NOT EXISTS pending_scores (
+			id BIGSERIAL PRIMARY KEY,
+			leaderboard_id VARCHAR(64) NOT NULL REFERENCES leaderboards(id) ON DELETE CASCADE,
+			player_id VARCHAR(64) NOT NULL,
+			score BIGINT NOT NULL,
+			raw_score BIGINT NOT NULL,
+			game_id VARCHAR(64) NOT NULL DEFAULT '',
+			metadata JSONB,
+			status VARCHAR(10) NOT NULL DEFAULT 'pending',
+			submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			reviewed_at TIMESTAMP,
+			reviewer VARCHAR(255) NOT NULL DEFAULT '',
+			reason TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_pending_scores_status ON pending_scores(leaderboard_id, status, submitted_at)`,
+		`ALTER TABLE score_events ADD COLUMN IF NOT EXISTS replay_url TEXT`,
+		`ALTER TABLE score_events ADD COLUMN IF NOT EXISTS proof_ref TEXT`,
+		`ALTER TABLE pending_scores ADD COLUMN IF NOT EXISTS replay_url TEXT`,
+		`ALTER TABLE pending_scores ADD COLUMN IF NOT EXISTS proof_ref TEXT`,
+		`CREATE INDEX IF NOT EXISTS idx_score_events_keyset ON score_events(leaderboard_id, created_at, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_score_events_player_keyset ON score_events(leaderboard_id, player_id, created_at, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_pending_scores_keyset ON pending_scores(leaderboard_id, status, submitted_at, id)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS sensitive_fields TEXT[] NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS clamp_min BIGINT`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS clamp_max BIGINT`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_snapshots (
+			id BIGSERIAL PRIMARY KEY,
+			leaderboard_id VARCHAR(64) NOT NULL,
+			name VARCHAR(128) NOT NULL,
+			version BIGINT NOT NULL,
+			player_count BIGINT NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			UNIQUE(leaderboard_id, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_snapshot_entries (
+			snapshot_id BIGINT NOT NULL REFERENCES leaderboard_snapshots(id),
+			rank BIGINT NOT NULL,
+			player_id VARCHAR(64) NOT NULL,
+			score BIGINT NOT NULL,
+			PRIMARY KEY(snapshot_id, rank)
+		)`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_archives (
+			id BIGSERIAL PRIMARY KEY,
+			leaderboard_id VARCHAR(64) NOT NULL,
+			season VARCHAR(128) NOT NULL,
+			reset_period VARCHAR(20) NOT NULL,
+			period_start TIMESTAMP,
+			version BIGINT NOT NULL,
+			player_count BIGINT NOT NULL,
+			archived_at TIMESTAMP NOT NULL,
+			UNIQUE(leaderboard_id, season)
+		)`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_archive_entries (
+			archive_id BIGINT NOT NULL REFERENCES leaderboard_archives(id),
+			rank BIGINT NOT NULL,
+			player_id VARCHAR(64) NOT NULL,
+			score BIGINT NOT NULL,
+			PRIMARY KEY(archive_id, rank)
+		)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS inactivity_days INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS tie_break VARCHAR(20) NOT NULL DEFAULT ''`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_seedings (
+			id BIGSERIAL PRIMARY KEY,
+			leaderboard_id VARCHAR(64) NOT NULL,
+			name VARCHAR(128) NOT NULL,
+			format VARCHAR(20) NOT NULL,
+			division_count INT NOT NULL DEFAULT 0,
+			version BIGINT NOT NULL,
+			player_count BIGINT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			UNIQUE(leaderboard_id, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS leaderboard_seeding_players (
+			seeding_id BIGINT NOT NULL REFERENCES leaderboard_seedings(id),
+			seed BIGINT NOT NULL,
+			player_id VARCHAR(64) NOT NULL,
+			score BIGINT NOT NULL,
+			division INT NOT NULL DEFAULT 0,
+			PRIMARY KEY(seeding_id, seed)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_seeding_players_division ON leaderboard_seeding_players(seeding_id, division, seed)`,
+		`CREATE TABLE IF NOT EXISTS players (
+			id VARCHAR(64) PRIMARY KEY,
+			username VARCHAR(255) NOT NULL,
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			avatar_url TEXT NOT NULL DEFAULT '',
+			metadata JSONB,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS attempt_quota INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS quota_period VARCHAR(20) NOT NULL DEFAULT ''`,
+		`ALTER TABLE leaderboards ADD COLUMN IF NOT EXISTS quota_exceeded VARCHAR(20) NOT NULL DEFAULT ''`,
+		`CREATE TABLE IF NOT EXISTS admin_audit_log (
+			id BIGSERIAL PRIMARY KEY,
+			actor VARCHAR(255) NOT NULL,
+			on_behalf_of VARCHAR(64) NOT NULL,
+			method VARCHAR(10) NOT NULL,
+			path TEXT NOT NULL,
+			request_id VARCHAR(128) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_admin_audit_created ON admin_audit_log(created_at DESC)`,
 	}
 
 	for _, migration := range migrations {
@@ -106,50 +265,189 @@ func (r *Repository) RunMigrations(ctx context.Context) error {
 	return nil
 }
 
-// CreateLeaderboard creates a new leaderboard configuration
+// querier runs single statements; both the pool and a transaction are one
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// uniqueViolation is the PostgreSQL error code for a duplicate key
+const uniqueViolation = "23505"
+
+// CreateLeaderboard creates a new leaderboard configuration. An ID already in
+// use returns domain.ErrLeaderboardExists.
 func (r *Repository) CreateLeaderboard(ctx context.Context, config domain.LeaderboardConfig) error {
+	return insertLeaderboard(ctx, r.pool, config)
+}
+
+// CreateLeaderboardTx creates a leaderboard and, when script is not empty, its
+// first scoring script in one transaction, and returns the stored config.
+// beforeCommit runs inside the transaction once both are inserted; when it or
+// the commit fails, neither is kept. An ID already in use returns
+// domain.ErrLeaderboardExists.
+func (r *Repository) CreateLeaderboardTx(ctx context.Context, config domain.LeaderboardConfig, script string, beforeCommit func(domain.LeaderboardConfig) error) (domain.LeaderboardConfig, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return config, fmt.Errorf("beginning leaderboard creation: %w", err)
+	}
+	// A no-op once committed
+	defer tx.Rollback(ctx)
+
+	if err := insertLeaderboard(ctx, tx, config); err != nil {
+		return config, err
+	}
+	if script != "" {
+		created, err := insertScript(ctx, tx, config.ID, script, config.CreatedAt)
+		if err != nil {
+			return config, err
+		}
+		config.ScriptVersion = created.Version
+	}
+	if beforeCommit != nil {
+		if err := beforeCommit(config); err != nil {
+			return config, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return config, fmt.Errorf("committing leaderboard creation: %w", err)
+	}
+	return config, nil
+}
+
+// insertLeaderboard inserts a leaderboard row
+func insertLeaderboard(ctx context.Context, q querier, config domain.LeaderboardConfig) error {
 	query := `
-		INSERT INTO leaderboards (id, name, sort_order, reset_period, max_entries, update_mode, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO leaderboards (id, name, sort_order, reset_period, max_entries, update_mode, shadow_id,
+			update_throttle_ms, min_rank_change, min_score_change,
+			score_unit, score_multiplier, score_offset, score_rounding, min_score, max_score, timezone,
+			open_at, close_at, min_submissions, review_threshold, score_formula, parent_id, filter,
+			sensitive_fields, clamp_min, clamp_max, inactivity_days, tie_break,
+			attempt_quota, quota_period, quota_exceeded, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+			$18, $19, $20, $21, $22, NULLIF($23, ''), $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34)
 	`
-	now := time.Now()
-	_, err := r.pool.Exec(ctx, query,
+	createdAt := config.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	sensitiveFields := config.SensitiveFields
+	if sensitiveFields == nil {
+		sensitiveFields = []string{}
+	}
+	_, err := q.Exec(ctx, query,
 		config.ID,
 		config.Name,
 		string(config.SortOrder),
 		string(config.ResetPeriod),
 		config.MaxEntries,
 		string(config.UpdateMode),
-		now,
-		now,
+		config.ShadowID,
+		config.UpdateThrottleMs,
+		config.MinRankChange,
+		config.MinScoreChange,
+		config.ScoreUnit,
+		config.ScoreMultiplier,
+		config.ScoreOffset,
+		string(config.ScoreRounding),
+		config.MinScore,
+		config.MaxScore,
+		config.Timezone,
+		utcOrNil(config.OpenAt),
+		utcOrNil(config.CloseAt),
+		config.MinSubmissions,
+		config.ReviewThreshold,
+		config.ScoreFormula,
+		config.ParentID,
+		config.Filter,
+		sensitiveFields,
+		config.ClampMin,
+		config.ClampMax,
+		config.InactivityDays,
+		string(config.TieBreak),
+		config.AttemptQuota,
+		string(config.QuotaPeriod),
+		string(config.QuotaExceeded),
+		createdAt,
+		createdAt,
 	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.TableName == "leaderboards" {
+		return domain.ErrLeaderboardExists
+	}
 	if err != nil {
 		return fmt.Errorf("creating leaderboard: %w", err)
 	}
 	return nil
 }
 
-// GetLeaderboard retrieves a leaderboard configuration by ID
-func (r *Repository) GetLeaderboard(ctx context.Context, leaderboardID string) (*domain.LeaderboardConfig, error) {
-	query := `
-		SELECT id, name, sort_order, reset_period, max_entries, update_mode, created_at, updated_at
-		FROM leaderboards
-		WHERE id = $1
-	`
+// leaderboardColumns lists the leaderboards columns in the order scanLeaderboard expects
+const leaderboardColumns = `id, name, sort_order, reset_period, max_entries, update_mode, COALESCE(shadow_id, ''),
+	update_throttle_ms, min_rank_change, min_score_change,
+	score_unit, score_multiplier, score_offset, score_rounding, min_score, max_score, timezone,
+	open_at, close_at, min_submissions, review_threshold, score_formula, script_version,
+	COALESCE(parent_id, ''), filter, sensitive_fields, clamp_min, clamp_max, inactivity_days, tie_break,
+	attempt_quota, quota_period, quota_exceeded, last_reset_at, created_at, updated_at`
+
+// utcOrNil converts an optional time to UTC for TIMESTAMP columns, which drop the zone
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// scanLeaderboard scans a leaderboards row selected with leaderboardColumns
+func scanLeaderboard(row pgx.Row) (domain.LeaderboardConfig, error) {
 	var config domain.LeaderboardConfig
-	err := r.pool.QueryRow(ctx, query, leaderboardID).Scan(
+	err := row.Scan(
 		&config.ID,
 		&config.Name,
 		&config.SortOrder,
 		&config.ResetPeriod,
 		&config.MaxEntries,
 		&config.UpdateMode,
+		&config.ShadowID,
+		&config.UpdateThrottleMs,
+		&config.MinRankChange,
+		&config.MinScoreChange,
+		&config.ScoreUnit,
+		&config.ScoreMultiplier,
+		&config.ScoreOffset,
+		&config.ScoreRounding,
+		&config.MinScore,
+		&config.MaxScore,
+		&config.Timezone,
+		&config.OpenAt,
+		&config.CloseAt,
+		&config.MinSubmissions,
+		&config.ReviewThreshold,
+		&config.ScoreFormula,
+		&config.ScriptVersion,
+		&config.ParentID,
+		&config.Filter,
+		&config.SensitiveFields,
+		&config.ClampMin,
+		&config.ClampMax,
+		&config.InactivityDays,
+		&config.TieBreak,
+		&config.AttemptQuota,
+		&config.QuotaPeriod,
+		&config.QuotaExceeded,
+		&config.LastResetAt,
 		&config.CreatedAt,
 		&config.UpdatedAt,
 	)
+	return config, err
+}
+
+// GetLeaderboard retrieves a leaderboard configuration by ID
+func (r *Repository) GetLeaderboard(ctx context.Context, leaderboardID string) (*domain.LeaderboardConfig, error) {
+	query := `SELECT ` + leaderboardColumns + ` FROM leaderboards WHERE id = $1`
+	config, err := scanLeaderboard(r.pool.QueryRow(ctx, query, leaderboardID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, domain.ErrLeaderboardNotFound
+			return nil, domain.NewNotFoundError(domain.ResourceLeaderboard, leaderboardID)
 		}
 		return nil, fmt.Errorf("getting leaderboard: %w", err)
 	}
@@ -158,11 +456,7 @@ func (r *Repository) GetLeaderboard(ctx context.Context, leaderboardID string) (
 
 // ListLeaderboards retrieves all leaderboard configurations
 func (r *Repository) ListLeaderboards(ctx context.Context) ([]domain.LeaderboardConfig, error) {
-	query := `
-		SELECT id, name, sort_order, reset_period, max_entries, update_mode, created_at, updated_at
-		FROM leaderboards
-		ORDER BY created_at DESC
-	`
+	query := `SELECT ` + leaderboardColumns + ` FROM leaderboards ORDER BY created_at DESC`
 	rows, err := r.pool.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("listing leaderboards: %w", err)
@@ -171,17 +465,7 @@ func (r *Repository) ListLeaderboards(ctx context.Context) ([]domain.Leaderboard
 
 	var configs []domain.LeaderboardConfig
 	for rows.Next() {
-		var config domain.LeaderboardConfig
-		err := rows.Scan(
-			&config.ID,
-			&config.Name,
-			&config.SortOrder,
-			&config.ResetPeriod,
-			&config.MaxEntries,
-			&config.UpdateMode,
-			&config.CreatedAt,
-			&config.UpdatedAt,
-		)
+		config, err := scanLeaderboard(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scanning leaderboard: %w", err)
 		}
@@ -190,19 +474,107 @@ func (r *Repository) ListLeaderboards(ctx context.Context) ([]domain.Leaderboard
 	return configs, nil
 }
 
-// DeleteLeaderboard removes a leaderboard and all associated data
-func (r *Repository) DeleteLeaderboard(ctx context.Context, leaderboardID string) error {
-	query := `DELETE FROM leaderboards WHERE id = $1`
-	result, err := r.pool.Exec(ctx, query, leaderboardID)
+// ListDerivedLeaderboards returns the leaderboards derived from a parent leaderboard
+func (r *Repository) ListDerivedLeaderboards(ctx context.Context, parentID string) ([]domain.LeaderboardConfig, error) {
+	query := `SELECT ` + leaderboardColumns + ` FROM leaderboards WHERE parent_id = $1 ORDER BY id`
+	rows, err := r.pool.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("listing derived leaderboards: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []domain.LeaderboardConfig
+	for rows.Next() {
+		config, err := scanLeaderboard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning leaderboard: %w", err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, rows.Err()
+}
+
+// SetShadow sets or clears (empty shadowID) the shadow leaderboard of a leaderboard
+func (r *Repository) SetShadow(ctx context.Context, leaderboardID, shadowID string) error {
+	query := `UPDATE leaderboards SET shadow_id = NULLIF($2, ''), updated_at = $3 WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, leaderboardID, shadowID, time.Now())
 	if err != nil {
-		return fmt.Errorf("deleting leaderboard: %w", err)
+		return fmt.Errorf("setting shadow leaderboard: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return domain.ErrLeaderboardNotFound
+		return domain.NewNotFoundError(domain.ResourceLeaderboard, leaderboardID)
+	}
+	return nil
+}
+
+// SetLastReset records the start of the period a leaderboard was last reset into
+func (r *Repository) SetLastReset(ctx context.Context, leaderboardID string, periodStart time.Time) error {
+	query := `UPDATE leaderboards SET last_reset_at = $2 WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, leaderboardID, periodStart)
+	if err != nil {
+		return fmt.Errorf("setting last reset: %w", err)
 	}
 	return nil
 }
 
+// ShadowedBy returns the IDs of the leaderboards that use leaderboardID as their shadow
+func (r *Repository) ShadowedBy(ctx context.Context, leaderboardID string) ([]string, error) {
+	query := `SELECT id FROM leaderboards WHERE shadow_id = $1 ORDER BY id`
+	rows, err := r.pool.Query(ctx, query, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing shadowing leaderboards: %w", err)
+	}
+	return scanIDs(rows)
+}
+
+// scanIDs reads a single-column result of leaderboard IDs and closes rows
+func scanIDs(rows pgx.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning leaderboard id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteLeaderboard removes a leaderboard and all associated data. Leaderboards
+// that used it as their shadow are detached in the same transaction; their IDs
+// are returned.
+func (r *Repository) DeleteLeaderboard(ctx context.Context, leaderboardID string) ([]string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning leaderboard deletion: %w", err)
+	}
+	// A no-op once committed
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `UPDATE leaderboards SET shadow_id = NULL, updated_at = $2 WHERE shadow_id = $1 RETURNING id`, leaderboardID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("detaching shadowing leaderboards: %w", err)
+	}
+	detached, err := scanIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("detaching shadowing leaderboards: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM leaderboards WHERE id = $1`, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("deleting leaderboard: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, domain.NewNotFoundError(domain.ResourceLeaderboard, leaderboardID)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing leaderboard deletion: %w", err)
+	}
+	return detached, nil
+}
+
 // UpsertScore inserts or updates a player's score
 func (r *Repository) UpsertScore(ctx context.Context, leaderboardID, playerID string, score int64, metadata map[string]interface{}) error {
 	var metadataJSON []byte
@@ -289,33 +661,345 @@ func (r *Repository) IncrementScore(ctx context.Context, leaderboardID, playerID
 
 // RecordEvent records a score event for auditing
 func (r *Repository) RecordEvent(ctx context.Context, event domain.ScoreEvent) error {
+	row, err := eventRow(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO score_events (leaderboard_id, player_id, score, event_type, sample_rate, metadata, created_at,
+			replay_url, proof_ref)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err = r.pool.Exec(ctx, query, row...)
+	if err != nil {
+		return fmt.Errorf("recording event: %w", err)
+	}
+	return nil
+}
+
+// eventColumns are the score_events columns written by eventRow, in order
+var eventColumns = []string{"leaderboard_id", "player_id", "score", "event_type", "sample_rate", "metadata", "created_at",
+	"replay_url", "proof_ref"}
+
+// eventRow returns the values stored for an event, in eventColumns order
+func eventRow(event domain.ScoreEvent) ([]any, error) {
 	var metadataJSON []byte
 	var err error
 	if event.Metadata != nil {
 		metadataJSON, err = json.Marshal(event.Metadata)
 		if err != nil {
-			return fmt.Errorf("marshaling metadata: %w", err)
+			return nil, fmt.Errorf("marshaling metadata: %w", err)
 		}
 	}
 
-	query := `
-		INSERT INTO score_events (leaderboard_id, player_id, score, event_type, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-	_, err = r.pool.Exec(ctx, query,
+	sampleRate := event.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 1
+	}
+
+	replayURL, proofRef := proofColumns(event.Proof)
+	return []any{
 		event.LeaderboardID,
 		event.PlayerID,
 		event.Score,
 		event.EventType,
+		int32(sampleRate),
 		metadataJSON,
 		event.Timestamp,
+		replayURL,
+		proofRef,
+	}, nil
+}
+
+// CopyEvents writes a batch of score events with a single COPY. The batch
+// is written entirely or not at all.
+func (r *Repository) CopyEvents(ctx context.Context, events []domain.ScoreEvent) error {
+	rows := make([][]any, 0, len(events))
+	for _, event := range events {
+		row, err := eventRow(event)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	_, err := r.pool.CopyFrom(ctx, pgx.Identifier{"score_events"}, eventColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("copying events: %w", err)
+	}
+	return nil
+}
+
+// pendingScoreColumns is the column list scanned by scanPendingScore
+const pendingScoreColumns = `id, leaderboard_id, player_id, score, raw_score, game_id, metadata,
+	replay_url, proof_ref, status, submitted_at, reviewed_at, reviewer, reason`
+
+// scanPendingScore scans a row selected with pendingScoreColumns
+func scanPendingScore(row pgx.Row) (*domain.PendingScore, error) {
+	var pending domain.PendingScore
+	var metadataJSON []byte
+	var replayURL, proofRef *string
+	err := row.Scan(
+		&pending.ID,
+		&pending.LeaderboardID,
+		&pending.PlayerID,
+		&pending.Score,
+		&pending.RawScore,
+		&pending.GameID,
+		&metadataJSON,
+		&replayURL,
+		&proofRef,
+		&pending.Status,
+		&pending.SubmittedAt,
+		&pending.ReviewedAt,
+		&pending.Reviewer,
+		&pending.Reason,
 	)
 	if err != nil {
-		return fmt.Errorf("recording event: %w", err)
+		return nil, err
+	}
+	if metadataJSON != nil {
+		if err := json.Unmarshal(metadataJSON, &pending.Metadata); err != nil {
+			return nil, fmt.Errorf("unmarshaling metadata: %w", err)
+		}
+	}
+	if replayURL != nil || proofRef != nil {
+		pending.Proof = &domain.ScoreProof{}
+		if replayURL != nil {
+			pending.Proof.ReplayURL = *replayURL
+		}
+		if proofRef != nil {
+			pending.Proof.ProofRef = *proofRef
+		}
+	}
+	return &pending, nil
+}
+
+// proofColumns splits an optional proof into nullable replay_url and proof_ref values
+func proofColumns(proof *domain.ScoreProof) (*string, *string) {
+	if proof == nil {
+		return nil, nil
+	}
+	var replayURL, proofRef *string
+	if proof.ReplayURL != "" {
+		replayURL = &proof.ReplayURL
+	}
+	if proof.ProofRef != "" {
+		proofRef = &proof.ProofRef
+	}
+	return replayURL, proofRef
+}
+
+// CreatePendingScore stores a submission held for review and sets its ID
+func (r *Repository) CreatePendingScore(ctx context.Context, pending *domain.PendingScore) error {
+	var metadataJSON []byte
+	var err error
+	if pending.Metadata != nil {
+		metadataJSON, err = json.Marshal(pending.Metadata)
+		if err != nil {
+			return fmt.Errorf("marshaling metadata: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO pending_scores (leaderboard_id, player_id, score, raw_score, game_id, metadata,
+			replay_url, proof_ref, status, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	replayURL, proofRef := proofColumns(pending.Proof)
+	err = r.pool.QueryRow(ctx, query,
+		pending.LeaderboardID,
+		pending.PlayerID,
+		pending.Score,
+		pending.RawScore,
+		pending.GameID,
+		metadataJSON,
+		replayURL,
+		proofRef,
+		string(pending.Status),
+		pending.SubmittedAt,
+	).Scan(&pending.ID)
+	if err != nil {
+		return fmt.Errorf("creating pending score: %w", err)
 	}
 	return nil
 }
 
+// GetPendingScore retrieves a pending score by ID
+func (r *Repository) GetPendingScore(ctx context.Context, id int64) (*domain.PendingScore, error) {
+	query := `SELECT ` + pendingScoreColumns + ` FROM pending_scores WHERE id = $1`
+	pending, err := scanPendingScore(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrPendingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting pending score: %w", err)
+	}
+	return pending, nil
+}
+
+// ListPendingScores returns one page of a leaderboard's pending scores with the
+// given status, ordered by (submitted_at, id), and the position of the next page
+func (r *Repository) ListPendingScores(ctx context.Context, leaderboardID string, status domain.ReviewStatus, page KeysetPage) ([]domain.PendingScore, *Keyset, error) {
+	query, args := keysetQuery(`SELECT `+pendingScoreColumns+` FROM pending_scores
+		WHERE leaderboard_id = $1 AND status = $2`,
+		[]interface{}{leaderboardID, string(status)}, "submitted_at", "id", page)
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing pending scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := []domain.PendingScore{}
+	for rows.Next() {
+		pending, err := scanPendingScore(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scanning pending score: %w", err)
+		}
+		scores = append(scores, *pending)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("listing pending scores: %w", err)
+	}
+
+	n, next := nextKeyset(len(scores), page.Limit, func(i int) Keyset {
+		return Keyset{CreatedAt: scores[i].SubmittedAt, ID: scores[i].ID}
+	})
+	return scores[:n], next, nil
+}
+
+// scoreEventColumns is the column list scanned by scanScoreEvent
+const scoreEventColumns = `id, leaderboard_id, player_id, score, event_type, sample_rate, metadata,
+	replay_url, proof_ref, created_at`
+
+// scanScoreEvent scans a row selected with scoreEventColumns
+func scanScoreEvent(row pgx.Row) (*domain.ScoreEvent, error) {
+	var event domain.ScoreEvent
+	var metadataJSON []byte
+	var replayURL, proofRef *string
+	err := row.Scan(
+		&event.ID,
+		&event.LeaderboardID,
+		&event.PlayerID,
+		&event.Score,
+		&event.EventType,
+		&event.SampleRate,
+		&metadataJSON,
+		&replayURL,
+		&proofRef,
+		&event.Timestamp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if metadataJSON != nil {
+		if err := json.Unmarshal(metadataJSON, &event.Metadata); err != nil {
+			return nil, fmt.Errorf("unmarshaling metadata: %w", err)
+		}
+	}
+	if replayURL != nil || proofRef != nil {
+		event.Proof = &domain.ScoreProof{}
+		if replayURL != nil {
+			event.Proof.ReplayURL = *replayURL
+		}
+		if proofRef != nil {
+			event.Proof.ProofRef = *proofRef
+		}
+	}
+	return &event, nil
+}
+
+// ListScoreEvents returns one page of a leaderboard's score events, optionally
+// for a single player, ordered by (created_at, id), and the position of the
+// next page. Archived events are included.
+func (r *Repository) ListScoreEvents(ctx context.Context, leaderboardID, playerID string, page KeysetPage) ([]domain.ScoreEvent, *Keyset, error) {
+	query := `SELECT ` + scoreEventColumns + ` FROM score_events WHERE leaderboard_id = $1`
+	args := []interface{}{leaderboardID}
+	if playerID != "" {
+		query += ` AND player_id = $2`
+		args = append(args, playerID)
+	}
+	query, args = keysetQuery(query, args, "created_at", "id", page)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing score events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.ScoreEvent{}
+	for rows.Next() {
+		event, err := scanScoreEvent(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scanning score event: %w", err)
+		}
+		events = append(events, *event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("listing score events: %w", err)
+	}
+
+	n, next := nextKeyset(len(events), page.Limit, func(i int) Keyset {
+		return Keyset{CreatedAt: events[i].Timestamp, ID: events[i].ID}
+	})
+	return events[:n], next, nil
+}
+
+// ReviewPendingScore moves a pending score to approved or rejected. It returns
+// ErrAlreadyReviewed when another reviewer got there first.
+func (r *Repository) ReviewPendingScore(ctx context.Context, id int64, status domain.ReviewStatus, review domain.ReviewRequest, reviewedAt time.Time) error {
+	query := `
+		UPDATE pending_scores
+		SET status = $2, reviewer = $3, reason = $4, reviewed_at = $5
+		WHERE id = $1 AND status = 'pending'
+	`
+	result, err := r.pool.Exec(ctx, query, id, string(status), review.Reviewer, review.Reason, reviewedAt)
+	if err != nil {
+		return fmt.Errorf("reviewing pending score: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlreadyReviewed
+	}
+	return nil
+}
+
+// DeleteOrphanedEvents removes up to limit score events whose leaderboard no longer exists
+func (r *Repository) DeleteOrphanedEvents(ctx context.Context, limit int) (int64, error) {
+	query := `
+		DELETE FROM score_events
+		WHERE id IN (
+			SELECT e.id FROM score_events e
+			WHERE NOT EXISTS (SELECT 1 FROM leaderboards l WHERE l.id = e.leaderboard_id)
+			LIMIT $1
+		)
+	`
+	result, err := r.pool.Exec(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("deleting orphaned events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ArchiveOrphanedEvents marks up to limit unarchived score events whose leaderboard no longer exists
+func (r *Repository) ArchiveOrphanedEvents(ctx context.Context, limit int) (int64, error) {
+	query := `
+		UPDATE score_events SET archived_at = $2
+		WHERE id IN (
+			SELECT e.id FROM score_events e
+			WHERE e.archived_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM leaderboards l WHERE l.id = e.leaderboard_id)
+			LIMIT $1
+		)
+	`
+	result, err := r.pool.Exec(ctx, query, limit, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("archiving orphaned events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
 // GetLeaderboardEntries retrieves leaderboard entries with pagination
 func (r *Repository) GetLeaderboardEntries(ctx context.Context, leaderboardID string, limit, offset int, descending bool) ([]domain.LeaderboardEntry, error) {
 	var query string
@@ -378,7 +1062,7 @@ func (r *Repository) GetPlayerScore(ctx context.Context, leaderboardID, playerID
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, domain.ErrPlayerNotFound
+			return nil, domain.NewNotFoundError(domain.ResourcePlayer, playerID)
 		}
 		return nil, fmt.Errorf("getting player score: %w", err)
 	}
@@ -393,7 +1077,193 @@ func (r *Repository) RemovePlayer(ctx context.Context, leaderboardID, playerID s
 		return fmt.Errorf("removing player: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return domain.ErrPlayerNotFound
+		return domain.NewNotFoundError(domain.ResourcePlayer, playerID)
+	}
+	return nil
+}
+
+// RemovePlayers removes players from a leaderboard and returns how many rows were deleted
+func (r *Repository) RemovePlayers(ctx context.Context, leaderboardID string, playerIDs []string) (int64, error) {
+	query := `DELETE FROM player_scores WHERE leaderboard_id = $1 AND player_id = ANY($2)`
+	result, err := r.pool.Exec(ctx, query, leaderboardID, playerIDs)
+	if err != nil {
+		return 0, fmt.Errorf("removing players: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// PlayersWithEvent returns the players of a leaderboard with at least one
+// score event of the given type
+func (r *Repository) PlayersWithEvent(ctx context.Context, leaderboardID, eventType string) ([]string, error) {
+	query := `SELECT DISTINCT player_id FROM score_events WHERE leaderboard_id = $1 AND event_type = $2`
+	rows, err := r.pool.Query(ctx, query, leaderboardID, eventType)
+	if err != nil {
+		return nil, fmt.Errorf("listing players with %s events: %w", eventType, err)
+	}
+	defer rows.Close()
+
+	var playerIDs []string
+	for rows.Next() {
+		var playerID string
+		if err := rows.Scan(&playerID); err != nil {
+			return nil, fmt.Errorf("scanning player: %w", err)
+		}
+		playerIDs = append(playerIDs, playerID)
+	}
+	return playerIDs, rows.Err()
+}
+
+// UpsertGhost creates or replaces a ghost entry
+func (r *Repository) UpsertGhost(ctx context.Context, ghost domain.Ghost) error {
+	query := `
+		INSERT INTO leaderboard_ghosts (leaderboard_id, ghost_id, name, score, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (leaderboard_id, ghost_id)
+		DO UPDATE SET name = EXCLUDED.name, score = EXCLUDED.score, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.pool.Exec(ctx, query, ghost.LeaderboardID, ghost.ID, ghost.Name, ghost.Score, ghost.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upserting ghost: %w", err)
+	}
+	return nil
+}
+
+// DeleteGhost removes a ghost entry
+func (r *Repository) DeleteGhost(ctx context.Context, leaderboardID, ghostID string) error {
+	query := `DELETE FROM leaderboard_ghosts WHERE leaderboard_id = $1 AND ghost_id = $2`
+	result, err := r.pool.Exec(ctx, query, leaderboardID, ghostID)
+	if err != nil {
+		return fmt.Errorf("deleting ghost: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrGhostNotFound
+	}
+	return nil
+}
+
+// ListGhosts returns a leaderboard's ghost entries
+func (r *Repository) ListGhosts(ctx context.Context, leaderboardID string) ([]domain.Ghost, error) {
+	query := `
+		SELECT leaderboard_id, ghost_id, name, score, created_at, updated_at
+		FROM leaderboard_ghosts
+		WHERE leaderboard_id = $1
+		ORDER BY ghost_id
+	`
+	rows, err := r.pool.Query(ctx, query, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing ghosts: %w", err)
+	}
+	defer rows.Close()
+
+	ghosts := []domain.Ghost{}
+	for rows.Next() {
+		var ghost domain.Ghost
+		if err := rows.Scan(&ghost.LeaderboardID, &ghost.ID, &ghost.Name, &ghost.Score, &ghost.CreatedAt, &ghost.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning ghost: %w", err)
+		}
+		ghosts = append(ghosts, ghost)
+	}
+	return ghosts, rows.Err()
+}
+
+// CreateScript stores the next version of a leaderboard's scoring script and
+// makes it the active one
+func (r *Repository) CreateScript(ctx context.Context, leaderboardID, source string, createdAt time.Time) (*domain.ScoringScript, error) {
+	return insertScript(ctx, r.pool, leaderboardID, source, createdAt)
+}
+
+// insertScript inserts the next script version and activates it
+func insertScript(ctx context.Context, q querier, leaderboardID, source string, createdAt time.Time) (*domain.ScoringScript, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO leaderboard_scripts (leaderboard_id, version, source, created_at)
+			SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3
+			FROM leaderboard_scripts WHERE leaderboard_id = $1
+			RETURNING version
+		)
+		UPDATE leaderboards SET script_version = inserted.version, updated_at = $3
+		FROM inserted WHERE id = $1
+		RETURNING inserted.version
+	`
+	script := domain.ScoringScript{
+		LeaderboardID: leaderboardID,
+		Source:        source,
+		Active:        true,
+		CreatedAt:     createdAt,
+	}
+	if err := q.QueryRow(ctx, query, leaderboardID, source, createdAt).Scan(&script.Version); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.NewNotFoundError(domain.ResourceLeaderboard, leaderboardID)
+		}
+		return nil, fmt.Errorf("creating scoring script: %w", err)
+	}
+	return &script, nil
+}
+
+// GetScript returns one version of a leaderboard's scoring script
+func (r *Repository) GetScript(ctx context.Context, leaderboardID string, version int) (*domain.ScoringScript, error) {
+	query := `
+		SELECT s.leaderboard_id, s.version, s.source, s.version = l.script_version, s.created_at
+		FROM leaderboard_scripts s JOIN leaderboards l ON l.id = s.leaderboard_id
+		WHERE s.leaderboard_id = $1 AND s.version = $2
+	`
+	var script domain.ScoringScript
+	err := r.pool.QueryRow(ctx, query, leaderboardID, version).Scan(
+		&script.LeaderboardID, &script.Version, &script.Source, &script.Active, &script.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrScriptNotFound
+		}
+		return nil, fmt.Errorf("getting scoring script: %w", err)
+	}
+	return &script, nil
+}
+
+// ListScripts returns every version of a leaderboard's scoring script, newest first
+func (r *Repository) ListScripts(ctx context.Context, leaderboardID string) ([]domain.ScoringScript, error) {
+	query := `
+		SELECT s.leaderboard_id, s.version, s.source, s.version = l.script_version, s.created_at
+		FROM leaderboard_scripts s JOIN leaderboards l ON l.id = s.leaderboard_id
+		WHERE s.leaderboard_id = $1
+		ORDER BY s.version DESC
+	`
+	rows, err := r.pool.Query(ctx, query, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing scoring scripts: %w", err)
+	}
+	defer rows.Close()
+
+	scripts := []domain.ScoringScript{}
+	for rows.Next() {
+		var script domain.ScoringScript
+		if err := rows.Scan(&script.LeaderboardID, &script.Version, &script.Source, &script.Active, &script.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning scoring script: %w", err)
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts, rows.Err()
+}
+
+// SetScriptVersion selects a leaderboard's active script version; 0 disables scripting
+func (r *Repository) SetScriptVersion(ctx context.Context, leaderboardID string, version int) error {
+	query := `
+		UPDATE leaderboards SET script_version = $2, updated_at = $3
+		WHERE id = $1 AND ($2 = 0 OR EXISTS (
+			SELECT 1 FROM leaderboard_scripts WHERE leaderboard_id = $1 AND version = $2))
+	`
+	result, err := r.pool.Exec(ctx, query, leaderboardID, version, time.Now())
+	if err != nil {
+		return fmt.Errorf("setting script version: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		exists, err := r.LeaderboardExists(ctx, leaderboardID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.NewNotFoundError(domain.ResourceLeaderboard, leaderboardID)
+		}
+		return domain.ErrScriptNotFound
 	}
 	return nil
 }
@@ -482,3 +1352,45 @@ func (r *Repository) BatchUpsertScores(ctx context.Context, leaderboardID string
 	return nil
 }
 
+
+// BatchUpsertScoresIfOlder upserts scores but leaves rows that were updated in
+// PostgreSQL after the corresponding Redis write, so direct edits are not clobbered.
+// It returns the number of rows skipped.
+func (r *Repository) BatchUpsertScoresIfOlder(ctx context.Context, leaderboardID string, scores map[string]int64, writtenAt map[string]time.Time) (int, error) {
+	if len(scores) == 0 {
+		return 0, nil
+	}
+
+	batch := &pgx.Batch{}
+	query := `
+		INSERT INTO player_scores (leaderboard_id, player_id, score, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (leaderboard_id, player_id) 
+		DO UPDATE SET score = $3, updated_at = $4
+		WHERE player_scores.updated_at <= $5
+	`
+	now := time.Now()
+
+	for playerID, score := range scores {
+		watermark, ok := writtenAt[playerID]
+		if !ok {
+			watermark = now
+		}
+		batch.Queue(query, leaderboardID, playerID, score, now, watermark)
+	}
+
+	br := r.pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	skipped := 0
+	for range scores {
+		result, err := br.Exec()
+		if err != nil {
+			return skipped, fmt.Errorf("batch upserting scores: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			skipped++
+		}
+	}
+	return skipped, nil
+}
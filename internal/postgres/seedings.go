@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateSeeding stores a seeding and its players in one transaction
+func (r *Repository) CreateSeeding(ctx context.Context, seeding domain.Seeding, players []domain.SeededPlayer) (*domain.Seeding, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning seeding: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO leaderboard_seedings (leaderboard_id, name, format, division_count, version, player_count, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (leaderboard_id, name) DO NOTHING
+		RETURNING id
+	`
+	createdAt := seeding.CreatedAt.UTC()
+	err = tx.QueryRow(ctx, query, seeding.LeaderboardID, seeding.Name, string(seeding.Format), seeding.DivisionCount,
+		seeding.Version, len(players), createdAt).Scan(&seeding.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSeedingExists
+		}
+		return nil, fmt.Errorf("inserting seeding: %w", err)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"leaderboard_seeding_players"},
+		[]string{"seeding_id", "seed", "player_id", "score", "division"},
+		pgx.CopyFromSlice(len(players), func(i int) ([]any, error) {
+			p := players[i]
+			return []any{seeding.ID, p.Seed, p.PlayerID, p.Score, p.Division}, nil
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("copying seeded players: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing seeding: %w", err)
+	}
+
+	seeding.PlayerCount = int64(len(players))
+	seeding.CreatedAt = createdAt
+	return &seeding, nil
+}
+
+// seedingColumns lists the leaderboard_seedings columns in the order scanSeeding expects
+const seedingColumns = `id, leaderboard_id, name, format, division_count, version, player_count, created_at`
+
+// scanSeeding scans a leaderboard_seedings row selected with seedingColumns
+func scanSeeding(row pgx.Row) (domain.Seeding, error) {
+	var seeding domain.Seeding
+	err := row.Scan(&seeding.ID, &seeding.LeaderboardID, &seeding.Name, &seeding.Format,
+		&seeding.DivisionCount, &seeding.Version, &seeding.PlayerCount, &seeding.CreatedAt)
+	return seeding, err
+}
+
+// ListSeedings returns a leaderboard's seedings, newest first, without their players
+func (r *Repository) ListSeedings(ctx context.Context, leaderboardID string) ([]domain.Seeding, error) {
+	query := `SELECT ` + seedingColumns + ` FROM leaderboard_seedings
+		WHERE leaderboard_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.pool.Query(ctx, query, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing seedings: %w", err)
+	}
+	defer rows.Close()
+
+	seedings := []domain.Seeding{}
+	for rows.Next() {
+		seeding, err := scanSeeding(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning seeding: %w", err)
+		}
+		seedings = append(seedings, seeding)
+	}
+	return seedings, rows.Err()
+}
+
+// GetSeeding returns a seeding by name, without its players
+func (r *Repository) GetSeeding(ctx context.Context, leaderboardID, name string) (*domain.Seeding, error) {
+	query := `SELECT ` + seedingColumns + ` FROM leaderboard_seedings WHERE leaderboard_id = $1 AND name = $2`
+	seeding, err := scanSeeding(r.pool.QueryRow(ctx, query, leaderboardID, name))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSeedingNotFound
+		}
+		return nil, fmt.Errorf("getting seeding: %w", err)
+	}
+	return &seeding, nil
+}
+
+// GetSeededPlayers returns a seeding's players in seed order. A positive
+// division limits them to that division.
+func (r *Repository) GetSeededPlayers(ctx context.Context, seedingID int64, division int) ([]domain.SeededPlayer, error) {
+	query := `SELECT seed, player_id, score, division FROM leaderboard_seeding_players
+		WHERE seeding_id = $1 AND ($2 = 0 OR division = $2) ORDER BY seed`
+	rows, err := r.pool.Query(ctx, query, seedingID, division)
+	if err != nil {
+		return nil, fmt.Errorf("getting seeded players: %w", err)
+	}
+	defer rows.Close()
+
+	players := []domain.SeededPlayer{}
+	for rows.Next() {
+		var p domain.SeededPlayer
+		if err := rows.Scan(&p.Seed, &p.PlayerID, &p.Score, &p.Division); err != nil {
+			return nil, fmt.Errorf("scanning seeded player: %w", err)
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateSnapshot stores a frozen copy of a leaderboard's standings. Entries are
+// pulled from next, in rank order, until it returns an empty page, and copied in
+// the same transaction as the snapshot row, so a failed freeze leaves nothing
+// behind. The stored checksum is computed from the entries as they are written.
+func (r *Repository) CreateSnapshot(ctx context.Context, snapshot domain.Snapshot, next func() ([]domain.LeaderboardEntry, error)) (*domain.Snapshot, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginning snapshot: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO leaderboard_snapshots (leaderboard_id, name, version, player_count, checksum, created_at)
+		VALUES ($1, $2, $3, $4, '', $5)
+		ON CONFLICT (leaderboard_id, name) DO NOTHING
+		RETURNING id
+	`
+	createdAt := snapshot.CreatedAt.UTC()
+	err = tx.QueryRow(ctx, query, snapshot.LeaderboardID, snapshot.Name, snapshot.Version, snapshot.PlayerCount, createdAt).
+		Scan(&snapshot.ID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSnapshotExists
+		}
+		return nil, fmt.Errorf("inserting snapshot: %w", err)
+	}
+
+	checksum := domain.NewSnapshotChecksum()
+	var page []domain.LeaderboardEntry
+	rows := pgx.CopyFromFunc(func() ([]any, error) {
+		if len(page) == 0 {
+			var err error
+			if page, err = next(); err != nil || len(page) == 0 {
+				return nil, err
+			}
+		}
+		entry := page[0]
+		page = page[1:]
+		checksum.Add(entry)
+		return []any{snapshot.ID, entry.Rank, entry.PlayerID, entry.Score}, nil
+	})
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"leaderboard_snapshot_entries"},
+		[]string{"snapshot_id", "rank", "player_id", "score"}, rows)
+	if err != nil {
+		return nil, fmt.Errorf("copying snapshot entries: %w", err)
+	}
+
+	snapshot.PlayerCount = copied
+	snapshot.Checksum = checksum.Sum()
+	_, err = tx.Exec(ctx, `UPDATE leaderboard_snapshots SET player_count = $2, checksum = $3 WHERE id = $1`,
+		snapshot.ID, snapshot.PlayerCount, snapshot.Checksum)
+	if err != nil {
+		return nil, fmt.Errorf("finishing snapshot: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing snapshot: %w", err)
+	}
+
+	snapshot.CreatedAt = createdAt
+	return &snapshot, nil
+}
+
+// snapshotColumns lists the leaderboard_snapshots columns in the order scanSnapshot expects
+const snapshotColumns = `id, leaderboard_id, name, version, player_count, checksum, created_at`
+
+// scanSnapshot scans a leaderboard_snapshots row selected with snapshotColumns
+func scanSnapshot(row pgx.Row) (domain.Snapshot, error) {
+	var snapshot domain.Snapshot
+	err := row.Scan(&snapshot.ID, &snapshot.LeaderboardID, &snapshot.Name, &snapshot.Version,
+		&snapshot.PlayerCount, &snapshot.Checksum, &snapshot.CreatedAt)
+	return snapshot, err
+}
+
+// ListSnapshots returns a leaderboard's snapshots, newest first, without their entries
+func (r *Repository) ListSnapshots(ctx context.Context, leaderboardID string) ([]domain.Snapshot, error) {
+	query := `SELECT ` + snapshotColumns + ` FROM leaderboard_snapshots
+		WHERE leaderboard_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.pool.Query(ctx, query, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []domain.Snapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// GetSnapshot returns a snapshot by name, without its entries
+func (r *Repository) GetSnapshot(ctx context.Context, leaderboardID, name string) (*domain.Snapshot, error) {
+	query := `SELECT ` + snapshotColumns + ` FROM leaderboard_snapshots WHERE leaderboard_id = $1 AND name = $2`
+	snapshot, err := scanSnapshot(r.pool.QueryRow(ctx, query, leaderboardID, name))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrSnapshotNotFound
+		}
+		return nil, fmt.Errorf("getting snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// EachSnapshotEntry calls fn with every entry of a snapshot in rank order
+func (r *Repository) EachSnapshotEntry(ctx context.Context, snapshotID int64, fn func(domain.LeaderboardEntry) error) error {
+	query := `SELECT rank, player_id, score FROM leaderboard_snapshot_entries WHERE snapshot_id = $1 ORDER BY rank`
+	rows, err := r.pool.Query(ctx, query, snapshotID)
+	if err != nil {
+		return fmt.Errorf("reading snapshot entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.LeaderboardEntry
+		if err := rows.Scan(&entry.Rank, &entry.PlayerID, &entry.Score); err != nil {
+			return fmt.Errorf("scanning snapshot entry: %w", err)
+		}
+		entry.IsGhost = domain.IsGhostID(entry.PlayerID)
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
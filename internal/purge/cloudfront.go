@@ -0,0 +1,100 @@
+package purge
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/leaderboard-redis/internal/awsauth"
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// cloudFrontAPI is the CloudFront API base URL; the API is global and signed for us-east-1
+const cloudFrontAPI = "https://cloudfront.amazonaws.com/2020-05-31"
+
+// cloudFrontMaxPaths is the most paths one CloudFront invalidation accepts
+const cloudFrontMaxPaths = 3000
+
+// invalidationBatch is the body of a CreateInvalidation request
+type invalidationBatch struct {
+	XMLName         xml.Name          `xml:"http://cloudfront.amazonaws.com/doc/2020-05-31/ InvalidationBatch"`
+	Paths           invalidationPaths `xml:"Paths"`
+	CallerReference string            `xml:"CallerReference"`
+}
+
+type invalidationPaths struct {
+	Quantity int      `xml:"Quantity"`
+	Items    []string `xml:"Items>Path"`
+}
+
+// CloudFrontProvider creates invalidations on a CloudFront distribution
+type CloudFrontProvider struct {
+	client   *http.Client
+	endpoint string
+	signer   *awsauth.Signer
+	seq      atomic.Int64
+}
+
+// NewCloudFrontProvider creates a provider for the configured distribution
+func NewCloudFrontProvider(cfg *config.PurgeConfig) (*CloudFrontProvider, error) {
+	if cfg.CloudFront.DistributionID == "" {
+		return nil, fmt.Errorf("cloudfront purging needs a distribution_id")
+	}
+	if cfg.MaxBatch > cloudFrontMaxPaths {
+		return nil, fmt.Errorf("cloudfront invalidates at most %d paths per request, max_batch is %d", cloudFrontMaxPaths, cfg.MaxBatch)
+	}
+	creds, err := awsauth.ResolveCredentials(awsauth.Credentials{
+		AccessKeyID:     cfg.CloudFront.AccessKeyID,
+		SecretAccessKey: cfg.CloudFront.SecretAccessKey,
+		SessionToken:    cfg.CloudFront.SessionToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloudfront purging: %w", err)
+	}
+	return &CloudFrontProvider{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		endpoint: cloudFrontAPI + "/distribution/" + url.PathEscape(cfg.CloudFront.DistributionID) + "/invalidation",
+		signer:   &awsauth.Signer{Credentials: creds, Region: "us-east-1", Service: "cloudfront"},
+	}, nil
+}
+
+// Purge creates one invalidation for the paths
+func (c *CloudFrontProvider) Purge(ctx context.Context, paths []string) error {
+	batch := invalidationBatch{
+		Paths: invalidationPaths{Quantity: len(paths), Items: paths},
+		// Each invalidation needs a reference CloudFront has not seen before
+		CallerReference: strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatInt(c.seq.Add(1), 36),
+	}
+	body, err := xml.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshaling cloudfront invalidation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating cloudfront invalidation request: %w", err)
+	}
+	req.URL.RawPath = awsauth.EscapePath(req.URL.Path)
+	req.Header.Set("Content-Type", "application/xml")
+	c.signer.Sign(req, body)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("creating cloudfront invalidation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("cloudfront invalidation: status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
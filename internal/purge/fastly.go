@@ -0,0 +1,69 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// fastlyAPI is the Fastly API base URL
+const fastlyAPI = "https://api.fastly.com"
+
+// fastlyMaxKeys is the most surrogate keys Fastly purges in one request
+const fastlyMaxKeys = 256
+
+// FastlyProvider purges surrogate keys from a Fastly service. The CDN must tag
+// responses with the same keys, e.g. from the request path in VCL.
+type FastlyProvider struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	soft     bool
+}
+
+// NewFastlyProvider creates a provider for the configured Fastly service
+func NewFastlyProvider(cfg *config.PurgeConfig) (*FastlyProvider, error) {
+	if cfg.Fastly.ServiceID == "" || cfg.Fastly.APIToken == "" {
+		return nil, fmt.Errorf("fastly purging needs a service_id and api_token")
+	}
+	if cfg.MaxBatch > fastlyMaxKeys {
+		return nil, fmt.Errorf("fastly purges at most %d keys per request, max_batch is %d", fastlyMaxKeys, cfg.MaxBatch)
+	}
+	return &FastlyProvider{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		endpoint: fastlyAPI + "/service/" + url.PathEscape(cfg.Fastly.ServiceID) + "/purge",
+		token:    cfg.Fastly.APIToken,
+		soft:     cfg.Fastly.SoftPurge,
+	}, nil
+}
+
+// Purge purges the paths as surrogate keys in a single request
+func (f *FastlyProvider) Purge(ctx context.Context, paths []string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating fastly purge request: %w", err)
+	}
+	req.Header.Set("Fastly-Key", f.token)
+	req.Header.Set("Surrogate-Key", strings.Join(paths, " "))
+	req.Header.Set("Accept", "application/json")
+	if f.soft {
+		req.Header.Set("Fastly-Soft-Purge", "1")
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending fastly purge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("fastly purge: status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
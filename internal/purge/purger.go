@@ -0,0 +1,238 @@
+// Package purge invalidates CDN copies of leaderboard reads
+package purge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// Provider sends one purge request to a CDN
+type Provider interface {
+	Purge(ctx context.Context, paths []string) error
+}
+
+// NewProvider creates the configured provider
+func NewProvider(cfg *config.PurgeConfig) (Provider, error) {
+	switch cfg.Provider {
+	case config.PurgeProviderWebhook:
+		return NewWebhookProvider(cfg)
+	case config.PurgeProviderFastly:
+		return NewFastlyProvider(cfg)
+	case config.PurgeProviderCloudFront:
+		return NewCloudFrontProvider(cfg)
+	default:
+		return nil, fmt.Errorf("unknown purge provider %q", cfg.Provider)
+	}
+}
+
+// Stats reports purge throughput
+type Stats struct {
+	Provider string `json:"provider"`
+	Pending  int    `json:"pending"`
+	Sent     int64  `json:"sent"`
+	Failed   int64  `json:"failed"`
+}
+
+// Purger collects the paths of leaderboards whose cached reads went stale and
+// purges them in batches, sending at most one request per interval so provider
+// rate limits are respected. Invalidate never blocks the write path.
+type Purger struct {
+	config   *config.PurgeConfig
+	provider Provider
+	logger   *slog.Logger
+
+	pendingMu sync.Mutex
+	pending   []string
+	queued    map[string]bool
+
+	sent    atomic.Int64
+	failed  atomic.Int64
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	mu      sync.Mutex
+	running bool
+}
+
+// NewPurger creates a purger using the configured provider
+func NewPurger(cfg *config.PurgeConfig, logger *slog.Logger) (*Purger, error) {
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Purger{
+		config:   cfg,
+		provider: provider,
+		logger:   logger,
+		queued:   make(map[string]bool),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}, nil
+}
+
+// TopN returns how deep in the standings a change must reach to be purged
+func (p *Purger) TopN() int {
+	return p.config.TopN
+}
+
+// Invalidate queues the configured paths of a leaderboard for purging.
+// Paths already waiting are not queued twice.
+func (p *Purger) Invalidate(leaderboardID string) {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+
+	for _, template := range p.config.Paths {
+		path := strings.ReplaceAll(template, "{id}", leaderboardID)
+		if !p.queued[path] {
+			p.queued[path] = true
+			p.pending = append(p.pending, path)
+		}
+	}
+}
+
+// Stats returns the purger's counters
+func (p *Purger) Stats() Stats {
+	p.pendingMu.Lock()
+	pending := len(p.pending)
+	p.pendingMu.Unlock()
+
+	return Stats{
+		Provider: p.config.Provider,
+		Pending:  pending,
+		Sent:     p.sent.Load(),
+		Failed:   p.failed.Load(),
+	}
+}
+
+// Start begins sending queued purges
+func (p *Purger) Start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = true
+	p.mu.Unlock()
+
+	p.logger.Info("cdn purger started",
+		"provider", p.config.Provider,
+		"interval", p.config.Interval,
+		"max_batch", p.config.MaxBatch,
+	)
+
+	go p.run(ctx)
+	return nil
+}
+
+// Stop sends what is still queued and stops the purger
+func (p *Purger) Stop() error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	close(p.stopCh)
+	<-p.doneCh
+
+	p.mu.Lock()
+	p.running = false
+	p.mu.Unlock()
+
+	p.logger.Info("cdn purger stopped",
+		"sent", p.sent.Load(),
+		"failed", p.failed.Load(),
+	)
+	return nil
+}
+
+// run is the main purger loop
+func (p *Purger) run(ctx context.Context) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.drain()
+			return
+		case <-p.stopCh:
+			p.drain()
+			return
+		case <-ticker.C:
+			p.flush(ctx)
+		}
+	}
+}
+
+// drain sends the remaining batches on shutdown
+func (p *Purger) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for ctx.Err() == nil && p.flush(ctx) {
+	}
+}
+
+// flush sends one batch of queued paths and reports whether it succeeded. A
+// failed batch is queued again, ahead of paths added since.
+func (p *Purger) flush(ctx context.Context) bool {
+	batch := p.take()
+	if len(batch) == 0 {
+		return false
+	}
+
+	if err := p.provider.Purge(ctx, batch); err != nil {
+		p.failed.Add(int64(len(batch)))
+		p.logger.Warn("failed to purge cdn paths",
+			"provider", p.config.Provider,
+			"count", len(batch),
+			"error", err,
+		)
+		p.requeue(batch)
+		return false
+	}
+
+	p.sent.Add(int64(len(batch)))
+	p.logger.Debug("purged cdn paths", "provider", p.config.Provider, "count", len(batch))
+	return true
+}
+
+// take removes up to MaxBatch paths from the front of the queue
+func (p *Purger) take() []string {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+
+	n := min(len(p.pending), p.config.MaxBatch)
+	batch := make([]string, n)
+	copy(batch, p.pending)
+	p.pending = p.pending[n:]
+	for _, path := range batch {
+		delete(p.queued, path)
+	}
+	return batch
+}
+
+// requeue puts a failed batch back at the front of the queue
+func (p *Purger) requeue(batch []string) {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+
+	pending := make([]string, 0, len(batch)+len(p.pending))
+	for _, path := range batch {
+		if !p.queued[path] {
+			p.queued[path] = true
+			pending = append(pending, path)
+		}
+	}
+	p.pending = append(pending, p.pending...)
+}
@@ -0,0 +1,64 @@
+package purge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// webhookRequest is the body posted to a purge webhook
+type webhookRequest struct {
+	Paths []string `json:"paths"`
+}
+
+// WebhookProvider posts the paths to purge as JSON to a configured URL, for
+// CDNs without a built-in provider or an in-house purge service
+type WebhookProvider struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// NewWebhookProvider creates a provider for the configured webhook
+func NewWebhookProvider(cfg *config.PurgeConfig) (*WebhookProvider, error) {
+	if cfg.Webhook.URL == "" {
+		return nil, fmt.Errorf("purge webhook url is required for the webhook provider")
+	}
+	return &WebhookProvider{
+		client:  &http.Client{Timeout: cfg.Timeout},
+		url:     cfg.Webhook.URL,
+		headers: cfg.Webhook.Headers,
+	}, nil
+}
+
+// Purge posts {"paths": [...]}; any 2xx response counts as success
+func (w *WebhookProvider) Purge(ctx context.Context, paths []string) error {
+	body, err := json.Marshal(webhookRequest{Paths: paths})
+	if err != nil {
+		return fmt.Errorf("marshaling purge request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating purge request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting purge request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("purge webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
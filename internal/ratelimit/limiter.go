@@ -0,0 +1,326 @@
+// Package ratelimit enforces sliding-window limits on score submissions and
+// reads, shared by every instance through Redis
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript checks every bucket in KEYS against its limit and only
+// counts the request when all of them have room, so a denied batch consumes
+// nothing. A dry run (ARGV[2] is 1) counts nothing at all. Each bucket is a hash holding the current window index (w) and the
+// counts of the current (c) and previous (p) windows. The previous window is
+// weighted by how much of it still overlaps the sliding window. Redis time is
+// used so every instance sees the same windows.
+// ARGV[1] is the window in milliseconds and ARGV[2] the dry-run flag, followed
+// by a limit and cost per key.
+// Returns the 1-based index of the first denying bucket (0 when allowed), the
+// milliseconds elapsed in the current window, and each bucket's counts.
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local window = tonumber(ARGV[1])
+local idx = math.floor(now / window)
+local elapsed = now - idx * window
+local weight = (window - elapsed) / window
+
+local counts = {}
+local denied = 0
+for i, key in ipairs(KEYS) do
+	local limit = tonumber(ARGV[2 * i + 1])
+	local cost = tonumber(ARGV[2 * i + 2])
+	local state = redis.call('HMGET', key, 'w', 'c', 'p')
+	local w = tonumber(state[1]) or idx
+	local curr = tonumber(state[2]) or 0
+	local prev = tonumber(state[3]) or 0
+	if idx == w + 1 then
+		prev = curr
+		curr = 0
+	elseif idx ~= w then
+		prev = 0
+		curr = 0
+	end
+	counts[i] = {curr, prev}
+	if denied == 0 and prev * weight + curr + cost > limit then
+		denied = i
+	end
+end
+
+if denied == 0 and ARGV[2] ~= '1' then
+	for i, key in ipairs(KEYS) do
+		counts[i][1] = counts[i][1] + tonumber(ARGV[2 * i + 2])
+		redis.call('HSET', key, 'w', idx, 'c', counts[i][1], 'p', counts[i][2])
+		redis.call('PEXPIRE', key, window * 2)
+	end
+end
+
+local out = {denied, elapsed}
+for i = 1, #KEYS do
+	out[#out + 1] = counts[i][1]
+	out[#out + 1] = counts[i][2]
+end
+return out
+`)
+
+// Decision is the outcome of a rate limit check. Limit and Remaining describe
+// the bucket with the least room left; Reset is when its current window ends.
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Limiter applies the per-player and per-API-key submission limits and the
+// per-client read limit
+type Limiter struct {
+	client    *redis.Client
+	namespace string
+	config    *config.RateLimitConfig
+
+	// keys maps the SHA-256 of each registered API key to its key ID
+	keys map[string]string
+	// proxies are the trusted proxy ranges
+	proxies []netip.Prefix
+}
+
+// NewLimiter creates a limiter storing its windows under the key namespace
+func NewLimiter(client *redis.Client, namespace string, cfg *config.RateLimitConfig) (*Limiter, error) {
+	keys := make(map[string]string, len(cfg.Keys))
+	for id, hash := range cfg.Keys {
+		keys[strings.ToLower(hash)] = id
+	}
+	proxies := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("ratelimit: trusted proxy %q: %w", proxy, err)
+		}
+		proxies = append(proxies, prefix)
+	}
+	return &Limiter{client: client, namespace: namespace, config: cfg, keys: keys, proxies: proxies}, nil
+}
+
+// parseProxy parses a trusted proxy given as a CIDR range or a single address
+func parseProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Identify returns the ID of a registered API key; ok is false for a missing
+// or unknown key
+func (l *Limiter) Identify(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(key))
+	id, ok := l.keys[hex.EncodeToString(sum[:])]
+	return id, ok
+}
+
+// KeyClient returns the client identity of a registered API key
+func KeyClient(keyID string) string {
+	return "key:" + keyID
+}
+
+// IPClient returns the client identity of an IP address
+func IPClient(ip string) string {
+	return "ip:" + ip
+}
+
+// ClientIP returns the IP address to key a client on. peer is the address the
+// connection came from and forwardedFor the request's X-Forwarded-For entries.
+// Forwarded entries are client-supplied, so they are only read from right to
+// left while the hop that added them is a trusted proxy: a client cannot pick
+// its identity by sending the header itself.
+func (l *Limiter) ClientIP(peer string, forwardedFor []string) string {
+	var hops []string
+	for _, header := range forwardedFor {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := peer
+	for i := len(hops) - 1; i >= 0 && l.trusted(client); i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		client = hop
+	}
+	return client
+}
+
+// trusted reports whether ip belongs to a trusted proxy
+func (l *Limiter) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// bucket is one counter checked for a request
+type bucket struct {
+	key   string
+	limit int
+	cost  int
+}
+
+// AllowSubmissions counts the submissions against each player's limit on its
+// leaderboard and, when client is set, against the client's limit. Either all
+// of them are counted or, when any limit is exhausted, none are.
+func (l *Limiter) AllowSubmissions(ctx context.Context, client string, submissions []domain.ScoreSubmission) (Decision, error) {
+	return l.check(ctx, l.submissionBuckets(client, submissions), false)
+}
+
+// CheckSubmissions tells whether AllowSubmissions would allow the submissions
+// without counting them, for dry runs
+func (l *Limiter) CheckSubmissions(ctx context.Context, client string, submissions []domain.ScoreSubmission) (Decision, error) {
+	return l.check(ctx, l.submissionBuckets(client, submissions), true)
+}
+
+// submissionBuckets returns the player and client buckets submissions count against
+func (l *Limiter) submissionBuckets(client string, submissions []domain.ScoreSubmission) []bucket {
+	var buckets []bucket
+	index := make(map[string]int)
+	for _, submission := range submissions {
+		limit := l.playerLimit(submission.LeaderboardID)
+		if limit < 0 {
+			continue
+		}
+		key := l.namespace + fmt.Sprintf("ratelimit:player:%s:%s", submission.LeaderboardID, submission.PlayerID)
+		if i, ok := index[key]; ok {
+			buckets[i].cost++
+			continue
+		}
+		index[key] = len(buckets)
+		buckets = append(buckets, bucket{key: key, limit: limit, cost: 1})
+	}
+	if limit := l.clientLimit(client); client != "" && limit >= 0 {
+		buckets = append(buckets, bucket{
+			key:   l.namespace + "ratelimit:client:" + client,
+			limit: limit,
+			cost:  len(submissions),
+		})
+	}
+	return buckets
+}
+
+// AllowRead counts one read against the client's limit. The client is a
+// registered API key or, without one, an IP address; see KeyClient and IPClient.
+func (l *Limiter) AllowRead(ctx context.Context, client string) (Decision, error) {
+	if l.config.Reads < 0 {
+		return Decision{Allowed: true, Limit: -1}, nil
+	}
+	return l.check(ctx, []bucket{{
+		key:   l.namespace + "ratelimit:reads:" + client,
+		limit: l.config.Reads,
+		cost:  1,
+	}}, false)
+}
+
+// check runs the sliding-window script over the buckets, counting the request
+// unless dryRun is set. Without buckets the request is allowed and the
+// decision's Limit is -1.
+func (l *Limiter) check(ctx context.Context, buckets []bucket, dryRun bool) (Decision, error) {
+	if len(buckets) == 0 {
+		return Decision{Allowed: true, Limit: -1}, nil
+	}
+
+	window := l.config.Window.Milliseconds()
+	keys := make([]string, len(buckets))
+	args := make([]interface{}, 0, 2+2*len(buckets))
+	args = append(args, window, dryRun)
+	for i, b := range buckets {
+		keys[i] = b.key
+		args = append(args, b.limit, b.cost)
+	}
+	res, err := slidingWindowScript.Run(ctx, l.client, keys, args...).Int64Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("checking rate limits: %w", err)
+	}
+
+	denied := int(res[0])
+	elapsed := float64(res[1])
+	weight := (float64(window) - elapsed) / float64(window)
+	decision := Decision{
+		Allowed:   denied == 0,
+		Remaining: math.MaxInt,
+		Reset:     time.Duration(float64(window)-elapsed) * time.Millisecond,
+	}
+	for i, b := range buckets {
+		curr, prev := float64(res[2+2*i]), float64(res[3+2*i])
+		remaining := int(math.Max(0, math.Floor(float64(b.limit)-(prev*weight+curr))))
+		if remaining < decision.Remaining {
+			decision.Limit = b.limit
+			decision.Remaining = remaining
+		}
+		if i+1 == denied {
+			decision.RetryAfter = retryAfter(curr, prev, float64(b.cost), float64(b.limit), float64(window), elapsed)
+		}
+	}
+	return decision, nil
+}
+
+// playerLimit returns the per-player limit of a leaderboard; negative disables it
+func (l *Limiter) playerLimit(leaderboardID string) int {
+	if limit, ok := l.config.Leaderboards[leaderboardID]; ok {
+		return limit
+	}
+	return l.config.PerPlayer
+}
+
+// clientLimit returns the submission limit of a client, overridden for
+// registered API keys in APIKeys; negative disables it
+func (l *Limiter) clientLimit(client string) int {
+	if keyID, ok := strings.CutPrefix(client, "key:"); ok {
+		if limit, ok := l.config.APIKeys[keyID]; ok {
+			return limit
+		}
+	}
+	return l.config.PerAPIKey
+}
+
+// retryAfter returns how long until a denied bucket has room for cost. The
+// previous window's weight decays linearly, so the wait is solved for in the
+// current window, or in the next one when the current count alone is too high.
+// A cost above the limit never fits and waits a whole window.
+func retryAfter(curr, prev, cost, limit, window, elapsed float64) time.Duration {
+	var wait float64
+	switch room := limit - cost - curr; {
+	case cost > limit:
+		wait = window
+	case room >= 0 && prev > 0:
+		wait = window*(1-room/prev) - elapsed
+	default:
+		// Once this window ends its count becomes the decaying previous window
+		wait = window - elapsed + window*(1-(limit-cost)/curr)
+	}
+	return time.Duration(math.Max(1, math.Ceil(wait))) * time.Millisecond
+}
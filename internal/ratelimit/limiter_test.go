@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+func TestClientIP(t *testing.T) {
+	limiter, err := NewLimiter(nil, "", &config.RateLimitConfig{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.7"}})
+	if err != nil {
+		t.Fatalf("NewLimiter: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		peer         string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.5", nil, "203.0.113.5"},
+		{"direct client spoofing the header", "203.0.113.5", []string{"198.51.100.1"}, "203.0.113.5"},
+		{"behind a proxy", "10.1.2.3", []string{"203.0.113.5"}, "203.0.113.5"},
+		{"client prepending entries", "10.1.2.3", []string{"198.51.100.1, 203.0.113.5"}, "203.0.113.5"},
+		{"chained proxies", "10.1.2.3", []string{"203.0.113.5, 192.0.2.7"}, "203.0.113.5"},
+		{"split headers", "10.1.2.3", []string{"198.51.100.1", "203.0.113.5"}, "203.0.113.5"},
+		{"proxy without the header", "10.1.2.3", nil, "10.1.2.3"},
+		{"malformed entry", "10.1.2.3", []string{"not-an-ip"}, "10.1.2.3"},
+		{"mapped proxy address", "::ffff:10.1.2.3", []string{"203.0.113.5"}, "203.0.113.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limiter.ClientIP(tt.peer, tt.forwardedFor); got != tt.want {
+				t.Errorf("ClientIP(%q, %q) = %q, want %q", tt.peer, tt.forwardedFor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLimiterRejectsInvalidProxies(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "proxy.internal", ""} {
+		if _, err := NewLimiter(nil, "", &config.RateLimitConfig{TrustedProxies: []string{proxy}}); err == nil {
+			t.Errorf("NewLimiter accepted trusted proxy %q", proxy)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// removePlayersScript removes the players in ARGV[2..] from a leaderboard and
+// its per-player hashes. Each player that was ranked is returned as player,
+// old rank (read with ARGV[1]), and score, followed by the leaderboard version.
+var removePlayersScript = redis.NewScript(`
+local removed = {}
+for i = 2, #ARGV do
+	local rank = redis.call(ARGV[1], KEYS[1], ARGV[i])
+	if rank then
+		local score = redis.call('ZSCORE', KEYS[1], ARGV[i])
+		redis.call('ZREM', KEYS[1], ARGV[i])
+		table.insert(removed, ARGV[i])
+		table.insert(removed, rank + 1)
+		table.insert(removed, string.format('%.0f', math.floor(tonumber(score))))
+	end
+	redis.call('HDEL', KEYS[2], ARGV[i])
+	redis.call('HDEL', KEYS[3], ARGV[i])
+	redis.call('HDEL', KEYS[4], ARGV[i])
+	redis.call('HDEL', KEYS[5], ARGV[i])
+end
+local version
+if #removed > 0 then
+	version = redis.call('INCR', KEYS[6])
+else
+	version = tonumber(redis.call('GET', KEYS[6]) or '0')
+end
+table.insert(removed, version)
+return removed
+`)
+
+// RemovePlayers removes players from a leaderboard in one step. It returns
+// the players that were ranked with the rank and score they held, and the
+// leaderboard version after the removal.
+func (s *LeaderboardService) RemovePlayers(ctx context.Context, leaderboardID string, playerIDs []string, higherIsBetter bool) ([]domain.LeaderboardEntry, int64, error) {
+	if len(playerIDs) == 0 {
+		return nil, 0, nil
+	}
+	keys := []string{s.leaderboardKey(leaderboardID), s.writesKey(leaderboardID), s.submissionsKey(leaderboardID),
+		s.proofsKey(leaderboardID), s.submittedKey(leaderboardID), s.versionKey(leaderboardID)}
+	args := make([]interface{}, 0, len(playerIDs)+1)
+	args = append(args, rankCommand(higherIsBetter))
+	for _, playerID := range playerIDs {
+		args = append(args, playerID)
+	}
+	result, err := removePlayersScript.Run(ctx, s.client, keys, args...).Slice()
+	if err != nil {
+		return nil, 0, fmt.Errorf("removing players: %w", err)
+	}
+	removed, version := parseRemoved(result)
+	return removed, version, nil
+}
+
+// PlayersScoredAbove returns the players of a leaderboard whose score is
+// strictly greater than score. Ghost entries are left out.
+func (s *LeaderboardService) PlayersScoredAbove(ctx context.Context, leaderboardID string, score int64) ([]string, error) {
+	members, err := s.client.ZRangeByScore(ctx, s.leaderboardKey(leaderboardID), &redis.ZRangeBy{
+		Min: higherScores(score),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting players above score: %w", err)
+	}
+	return withoutGhosts(members), nil
+}
+
+// RankedPlayers returns the given players that are on the leaderboard, in
+// the order given. Ghost entries are left out.
+func (s *LeaderboardService) RankedPlayers(ctx context.Context, leaderboardID string, playerIDs []string) ([]string, error) {
+	if len(playerIDs) == 0 {
+		return nil, nil
+	}
+	key := s.leaderboardKey(leaderboardID)
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.FloatCmd, len(playerIDs))
+	for i, playerID := range playerIDs {
+		cmds[i] = pipe.ZScore(ctx, key, playerID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("checking ranked players: %w", err)
+	}
+
+	var ranked []string
+	for i, cmd := range cmds {
+		if cmd.Err() == nil {
+			ranked = append(ranked, playerIDs[i])
+		}
+	}
+	return withoutGhosts(ranked), nil
+}
+
+// withoutGhosts drops ghost entries from a list of members
+func withoutGhosts(members []string) []string {
+	players := members[:0]
+	for _, member := range members {
+		if !strings.HasPrefix(member, domain.GhostIDPrefix) {
+			players = append(players, member)
+		}
+	}
+	return players
+}
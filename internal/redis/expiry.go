@@ -0,0 +1,163 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// submittedKey returns the hash of per-player last submission times, in unix milliseconds
+func (s *LeaderboardService) submittedKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:submitted", leaderboardID)
+}
+
+// TouchSubmitted records when a player last submitted to a leaderboard
+func (s *LeaderboardService) TouchSubmitted(ctx context.Context, leaderboardID, playerID string, at time.Time) error {
+	if err := s.client.HSet(ctx, s.submittedKey(leaderboardID), playerID, at.UnixMilli()).Err(); err != nil {
+		return fmt.Errorf("recording submission time: %w", err)
+	}
+	return nil
+}
+
+// expireInactiveScript removes the players in ARGV[3..] whose last submission
+// is at or before ARGV[1], checked again here so a player who submitted since
+// the scan is kept. Each removed player is returned as player, old rank (read
+// with ARGV[2]), and score, followed by the leaderboard version.
+var expireInactiveScript = redis.NewScript(`
+local removed = {}
+for i = 3, #ARGV do
+	local at = redis.call('HGET', KEYS[2], ARGV[i])
+	if at and tonumber(at) <= tonumber(ARGV[1]) then
+		local rank = redis.call(ARGV[2], KEYS[1], ARGV[i])
+		if rank then
+			local score = redis.call('ZSCORE', KEYS[1], ARGV[i])
+			redis.call('ZREM', KEYS[1], ARGV[i])
+			table.insert(removed, ARGV[i])
+			table.insert(removed, rank + 1)
+			table.insert(removed, string.format('%.0f', math.floor(tonumber(score))))
+		end
+		redis.call('HDEL', KEYS[2], ARGV[i])
+		redis.call('HDEL', KEYS[3], ARGV[i])
+		redis.call('HDEL', KEYS[4], ARGV[i])
+		redis.call('HDEL', KEYS[5], ARGV[i])
+	end
+end
+local version
+if #removed > 0 then
+	version = redis.call('INCR', KEYS[6])
+else
+	version = tonumber(redis.call('GET', KEYS[6]) or '0')
+end
+table.insert(removed, version)
+return removed
+`)
+
+// ExpireInactive removes the players of a leaderboard who last submitted at or
+// before cutoff, scanning count members per step. Players with no recorded
+// submission, such as those restored from PostgreSQL, are stamped with now and
+// so get a full window from the first sweep that sees them. Ghost entries never
+// expire. It returns the removed players with the rank and score they held and
+// the leaderboard version after the last removal.
+func (s *LeaderboardService) ExpireInactive(ctx context.Context, leaderboardID string, cutoff, now time.Time, count int64, higherIsBetter bool) ([]domain.LeaderboardEntry, int64, error) {
+	boardKey := s.leaderboardKey(leaderboardID)
+	submittedKey := s.submittedKey(leaderboardID)
+	keys := []string{boardKey, submittedKey, s.writesKey(leaderboardID), s.submissionsKey(leaderboardID),
+		s.proofsKey(leaderboardID), s.versionKey(leaderboardID)}
+
+	var removed []domain.LeaderboardEntry
+	var version int64
+	var cursor uint64
+	for {
+		members, next, err := s.client.ZScan(ctx, boardKey, cursor, "", count).Result()
+		if err != nil {
+			return removed, version, fmt.Errorf("scanning leaderboard: %w", err)
+		}
+
+		// ZSCAN replies alternate member and score
+		var players []string
+		for i := 0; i < len(members); i += 2 {
+			if !strings.HasPrefix(members[i], domain.GhostIDPrefix) {
+				players = append(players, members[i])
+			}
+		}
+
+		stale, err := s.stalePlayers(ctx, submittedKey, players, cutoff, now)
+		if err != nil {
+			return removed, version, err
+		}
+		if len(stale) > 0 {
+			args := make([]interface{}, 0, len(stale)+2)
+			args = append(args, cutoff.UnixMilli(), rankCommand(higherIsBetter))
+			for _, playerID := range stale {
+				args = append(args, playerID)
+			}
+			result, err := expireInactiveScript.Run(ctx, s.client, keys, args...).Slice()
+			if err != nil {
+				return removed, version, fmt.Errorf("expiring inactive players: %w", err)
+			}
+			entries, v := parseRemoved(result)
+			removed = append(removed, entries...)
+			version = v
+		}
+
+		cursor = next
+		if cursor == 0 || ctx.Err() != nil {
+			return removed, version, ctx.Err()
+		}
+	}
+}
+
+// stalePlayers returns the players whose last submission is at or before
+// cutoff, stamping those without one with now
+func (s *LeaderboardService) stalePlayers(ctx context.Context, submittedKey string, players []string, cutoff, now time.Time) ([]string, error) {
+	if len(players) == 0 {
+		return nil, nil
+	}
+	values, err := s.client.HMGet(ctx, submittedKey, players...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting submission times: %w", err)
+	}
+
+	var stale []string
+	pipe := s.client.Pipeline()
+	for i, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			// NX keeps a submission recorded since the read
+			pipe.HSetNX(ctx, submittedKey, players[i], now.UnixMilli())
+			continue
+		}
+		if ms, err := strconv.ParseInt(str, 10, 64); err == nil && ms <= cutoff.UnixMilli() {
+			stale = append(stale, players[i])
+		}
+	}
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("stamping submission times: %w", err)
+		}
+	}
+	return stale, nil
+}
+
+// parseRemoved reads the removed players and version returned by
+// expireInactiveScript and trimScript
+func parseRemoved(result []interface{}) ([]domain.LeaderboardEntry, int64) {
+	if len(result) == 0 {
+		return nil, 0
+	}
+	version, _ := result[len(result)-1].(int64)
+	var entries []domain.LeaderboardEntry
+	for i := 0; i+2 < len(result); i += 3 {
+		playerID, _ := result[i].(string)
+		rank, _ := result[i+1].(int64)
+		total, _ := result[i+2].(string)
+		score, _ := strconv.ParseFloat(total, 64)
+		entries = append(entries, domain.LeaderboardEntry{Rank: rank, PlayerID: playerID, Score: int64(score)})
+	}
+	return entries, version
+}
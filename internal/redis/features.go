@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+)
+
+// featuresKey returns the hash of feature flag overrides, a JSON rule per flag
+func (s *LeaderboardService) featuresKey() string {
+	return s.namespace + "features"
+}
+
+// GetFeatureOverrides returns the stored feature flag rules by flag name
+func (s *LeaderboardService) GetFeatureOverrides(ctx context.Context) (map[string]string, error) {
+	overrides, err := s.client.HGetAll(ctx, s.featuresKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting feature overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// SetFeatureOverride stores the rule of one feature flag
+func (s *LeaderboardService) SetFeatureOverride(ctx context.Context, flag, rule string) error {
+	if err := s.client.HSet(ctx, s.featuresKey(), flag, rule).Err(); err != nil {
+		return fmt.Errorf("setting feature override: %w", err)
+	}
+	return nil
+}
+
+// DeleteFeatureOverride removes the stored rule of one feature flag
+func (s *LeaderboardService) DeleteFeatureOverride(ctx context.Context, flag string) error {
+	if err := s.client.HDel(ctx, s.featuresKey(), flag).Err(); err != nil {
+		return fmt.Errorf("deleting feature override: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/leaderboard-redis/internal/config"
 	"github.com/leaderboard-redis/internal/domain"
@@ -15,6 +18,15 @@ import (
 type LeaderboardService struct {
 	client *redis.Client
 	logger *slog.Logger
+
+	// namespace is prepended to every key; see config.RedisConfig.KeyNamespace
+	namespace string
+
+	// playerInfoTTL expires idle player info hashes; 0 or less keeps them
+	playerInfoTTL time.Duration
+
+	// pages configures the materialized windows serving deep range reads
+	pages pageCache
 }
 
 // NewLeaderboardService creates a new Redis leaderboard service
@@ -37,8 +49,15 @@ func NewLeaderboardService(cfg *config.RedisConfig, logger *slog.Logger) (*Leade
 	}
 
 	return &LeaderboardService{
-		client: client,
-		logger: logger,
+		client:        client,
+		logger:        logger,
+		namespace:     cfg.KeyNamespace(),
+		playerInfoTTL: cfg.Cache.PlayerInfoTTL,
+		pages: pageCache{
+			minStart: cfg.Cache.PageMinStart,
+			size:     cfg.Cache.PageSize,
+			ttl:      cfg.Cache.PageTTL,
+		},
 	}, nil
 }
 
@@ -54,82 +73,544 @@ func (s *LeaderboardService) Client() *redis.Client {
 
 // leaderboardKey returns the Redis key for a leaderboard's sorted set
 func (s *LeaderboardService) leaderboardKey(leaderboardID string) string {
-	return fmt.Sprintf("leaderboard:%s:realtime", leaderboardID)
+	return s.namespace + fmt.Sprintf("leaderboard:%s:realtime", leaderboardID)
 }
 
 // metaKey returns the Redis key for leaderboard metadata
 func (s *LeaderboardService) metaKey(leaderboardID string) string {
-	return fmt.Sprintf("leaderboard:%s:meta", leaderboardID)
+	return s.namespace + fmt.Sprintf("leaderboard:%s:meta", leaderboardID)
+}
+
+// writesKey returns the Redis key for the per-player last-write timestamps of a leaderboard
+func (s *LeaderboardService) writesKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:writes", leaderboardID)
+}
+
+// lastWriteKey returns the key holding when a submission was last applied to a
+// leaderboard, in Unix milliseconds
+func (s *LeaderboardService) lastWriteKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:last_write", leaderboardID)
+}
+
+// versionKey returns the Redis key for a leaderboard's write version counter
+func (s *LeaderboardService) versionKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:version", leaderboardID)
+}
+
+// submissionsKey returns the hash of per-player submission counts for a leaderboard
+func (s *LeaderboardService) submissionsKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:submissions", leaderboardID)
+}
+
+// ghostsKey returns the hash of ghost entry names for a leaderboard
+func (s *LeaderboardService) ghostsKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:ghosts", leaderboardID)
+}
+
+// pendingKey returns the sorted set of submissions awaiting review, keyed "<pending id>:<player id>"
+func (s *LeaderboardService) pendingKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:pending", leaderboardID)
+}
+
+// proofsKey returns the hash of proof references behind each player's standing score
+func (s *LeaderboardService) proofsKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:proofs", leaderboardID)
+}
+
+// feedKey returns the capped stream of notable events for a leaderboard
+func (s *LeaderboardService) feedKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:feed", leaderboardID)
+}
+
+// dedupKey returns the key marking an idempotency key as already applied to a leaderboard.
+// Dedup keys expire on their own and are not removed with the leaderboard.
+func (s *LeaderboardService) dedupKey(leaderboardID, idempotencyKey string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:dedup:%s", leaderboardID, idempotencyKey)
+}
+
+// activityKey returns the counter of submissions applied to a leaderboard during
+// one minute. Counters expire on their own and are not removed with the leaderboard.
+func (s *LeaderboardService) activityKey(leaderboardID string, minute int64) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:activity:%d", leaderboardID, minute)
+}
+
+// pageKey returns the materialized copy of one window of a leaderboard's ranks.
+// Pages expire on their own and are also dropped with the leaderboard.
+func (s *LeaderboardService) pageKey(leaderboardID string, window int) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:page:%d", leaderboardID, window)
+}
+
+// pagesKey returns the set of page keys currently materialized for a leaderboard
+func (s *LeaderboardService) pagesKey(leaderboardID string) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:pages", leaderboardID)
 }
 
 // playerInfoKey returns the Redis key for player info cache
 func (s *LeaderboardService) playerInfoKey(playerID string) string {
-	return fmt.Sprintf("player:%s:info", playerID)
+	return s.namespace + fmt.Sprintf("player:%s:info", playerID)
 }
 
-// SetScore sets a player's score in the leaderboard
-func (s *LeaderboardService) SetScore(ctx context.Context, leaderboardID, playerID string, score int64) error {
+// SetScore sets a player's score in the leaderboard and returns the new leaderboard
+// version. tie is the score's tie-break component, 0 on boards without one.
+func (s *LeaderboardService) SetScore(ctx context.Context, leaderboardID, playerID string, score int64, tie float64) (int64, error) {
 	key := s.leaderboardKey(leaderboardID)
-	err := s.client.ZAdd(ctx, key, redis.Z{
-		Score:  float64(score),
+	pipe := s.client.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  storedScore(score, tie),
 		Member: playerID,
-	}).Err()
+	})
+	pipe.HSet(ctx, s.writesKey(leaderboardID), playerID, time.Now().UnixMilli())
+	versionCmd := pipe.Incr(ctx, s.versionKey(leaderboardID))
+	_, err := pipe.Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("setting score: %w", err)
+		return 0, fmt.Errorf("setting score: %w", err)
 	}
-	return nil
+	return versionCmd.Val(), nil
 }
 
-// SetScoreIfBetter sets a player's score only if it's better than the current score
-func (s *LeaderboardService) SetScoreIfBetter(ctx context.Context, leaderboardID, playerID string, score int64, higherIsBetter bool) (bool, error) {
-	key := s.leaderboardKey(leaderboardID)
+// setIfBetterScript writes ARGV[3] as the player's stored score and bumps the
+// version, unless the player already holds a score (ARGV[2], without tie-break)
+// at least as good. ARGV[5] is 1 when higher scores are better. Comparing and
+// writing in one step keeps concurrent submissions, such as those from several
+// Kafka consumers, from overwriting a better score with a worse one.
+var setIfBetterScript = redis.NewScript(`
+local stored = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if stored then
+	local current = math.floor(tonumber(stored))
+	local score = tonumber(ARGV[2])
+	local better = score < current
+	if ARGV[5] == '1' then
+		better = score > current
+	end
+	if not better then
+		return {0, tonumber(redis.call('GET', KEYS[3]) or '0')}
+	end
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[4])
+return {1, redis.call('INCR', KEYS[3])}
+`)
 
-	// Get current score
-	currentScore, err := s.client.ZScore(ctx, key, playerID).Result()
-	if err != nil && err != redis.Nil {
-		return false, fmt.Errorf("getting current score: %w", err)
+// SetScoreIfBetter sets a player's score only if it's better than the current score.
+// An equal score keeps the tie-break component of the first. It returns whether
+// the score was written and the leaderboard version the caller must read at to
+// observe the player's best score.
+func (s *LeaderboardService) SetScoreIfBetter(ctx context.Context, leaderboardID, playerID string, score int64, tie float64, higherIsBetter bool) (bool, int64, error) {
+	keys := []string{s.leaderboardKey(leaderboardID), s.writesKey(leaderboardID), s.versionKey(leaderboardID)}
+	higher := "0"
+	if higherIsBetter {
+		higher = "1"
+	}
+	result, err := setIfBetterScript.Run(ctx, s.client, keys,
+		playerID, score, strconv.FormatFloat(storedScore(score, tie), 'f', -1, 64), time.Now().UnixMilli(), higher,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("setting best score: %w", err)
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("setting best score: unexpected reply %v", result)
 	}
+	return result[0] == 1, result[1], nil
+}
 
-	// If player doesn't exist, add them
+// IncrementScore increments a player's score by the given delta and returns
+// the player's new score and the new leaderboard version. The total takes the
+// tie-break component tie. A total beyond domain.MaxSafeScore is rejected with
+// domain.ErrInvalidScore.
+func (s *LeaderboardService) IncrementScore(ctx context.Context, leaderboardID, playerID string, delta int64, tie float64) (int64, int64, error) {
+	newScore, version, _, err := s.IncrementScoreClamped(ctx, leaderboardID, playerID, delta, tie, nil, nil)
+	return newScore, version, err
+}
+
+// clampedIncrementScript adds a delta to a player's score, clamps the total to
+// ARGV[3]..ARGV[4] (empty leaves a side open), and bumps the version in one step.
+// A total beyond ARGV[6] in either direction is not written and returns nil.
+// The total is stored with the tie-break component ARGV[7] in place of the
+// previous one, unless the total is too large to keep it exactly.
+// Scores come back as strings because Lua would truncate large numbers.
+var clampedIncrementScript = redis.NewScript(`
+local current = math.floor(tonumber(redis.call('ZSCORE', KEYS[1], ARGV[1]) or '0'))
+local total = current + tonumber(ARGV[2])
+local clamped = 0
+if ARGV[3] ~= '' and total < tonumber(ARGV[3]) then
+	total = tonumber(ARGV[3])
+	clamped = 1
+elseif ARGV[4] ~= '' and total > tonumber(ARGV[4]) then
+	total = tonumber(ARGV[4])
+	clamped = 1
+end
+if math.abs(total) > tonumber(ARGV[6]) then
+	return false
+end
+local stored = total + tonumber(ARGV[7])
+if math.floor(stored) ~= total then
+	stored = total
+end
+redis.call('ZADD', KEYS[1], stored, ARGV[1])
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[5])
+local version = redis.call('INCR', KEYS[3])
+return {string.format('%.0f', total), version, clamped}
+`)
+
+// IncrementScoreClamped increments a player's score like IncrementScore but keeps
+// the total within min..max, either of which may be nil. It also reports whether
+// the total had to be clamped.
+func (s *LeaderboardService) IncrementScoreClamped(ctx context.Context, leaderboardID, playerID string, delta int64, tie float64, min, max *int64) (int64, int64, bool, error) {
+	keys := []string{s.leaderboardKey(leaderboardID), s.writesKey(leaderboardID), s.versionKey(leaderboardID)}
+	result, err := clampedIncrementScript.Run(ctx, s.client, keys,
+		playerID, delta, formatOptionalInt(min), formatOptionalInt(max), time.Now().UnixMilli(), domain.MaxSafeScore,
+		strconv.FormatFloat(tie, 'g', -1, 64),
+	).Slice()
 	if err == redis.Nil {
-		return true, s.SetScore(ctx, leaderboardID, playerID, score)
+		return 0, 0, false, domain.ErrInvalidScore
+	}
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("incrementing score: %w", err)
+	}
+	if len(result) != 3 {
+		return 0, 0, false, fmt.Errorf("incrementing score: unexpected reply %v", result)
+	}
+	total, _ := result[0].(string)
+	newScore, err := strconv.ParseFloat(total, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("incrementing score: parsing total: %w", err)
+	}
+	version, _ := result[1].(int64)
+	clamped, _ := result[2].(int64)
+	return int64(newScore), version, clamped == 1, nil
+}
+
+// RemovePlayer removes a player from the leaderboard and returns the new leaderboard version
+func (s *LeaderboardService) RemovePlayer(ctx context.Context, leaderboardID, playerID string) (int64, error) {
+	key := s.leaderboardKey(leaderboardID)
+	pipe := s.client.TxPipeline()
+	pipe.ZRem(ctx, key, playerID)
+	pipe.HDel(ctx, s.writesKey(leaderboardID), playerID)
+	pipe.HDel(ctx, s.submissionsKey(leaderboardID), playerID)
+	pipe.HDel(ctx, s.proofsKey(leaderboardID), playerID)
+	pipe.HDel(ctx, s.submittedKey(leaderboardID), playerID)
+	versionCmd := pipe.Incr(ctx, s.versionKey(leaderboardID))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("removing player: %w", err)
 	}
+	return versionCmd.Val(), nil
+}
 
-	// Check if new score is better
-	isBetter := (higherIsBetter && float64(score) > currentScore) ||
-		(!higherIsBetter && float64(score) < currentScore)
+// IncrementSubmissions counts an accepted submission for a player and returns the new count
+func (s *LeaderboardService) IncrementSubmissions(ctx context.Context, leaderboardID, playerID string) (int64, error) {
+	count, err := s.client.HIncrBy(ctx, s.submissionsKey(leaderboardID), playerID, 1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("incrementing submission count: %w", err)
+	}
+	return count, nil
+}
 
-	if !isBetter {
-		return false, nil
+// GetSubmissionCounts returns the submission counts of the given players; missing players count 0
+func (s *LeaderboardService) GetSubmissionCounts(ctx context.Context, leaderboardID string, playerIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return counts, nil
 	}
 
-	return true, s.SetScore(ctx, leaderboardID, playerID, score)
+	values, err := s.client.HMGet(ctx, s.submissionsKey(leaderboardID), playerIDs...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting submission counts: %w", err)
+	}
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			counts[playerIDs[i]], _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+	return counts, nil
 }
 
-// IncrementScore increments a player's score by the given delta
-func (s *LeaderboardService) IncrementScore(ctx context.Context, leaderboardID, playerID string, delta int64) (int64, error) {
-	key := s.leaderboardKey(leaderboardID)
-	newScore, err := s.client.ZIncrBy(ctx, key, float64(delta), playerID).Result()
+// ClaimSubmission records an idempotency key for a leaderboard for ttl and reports
+// whether this call claimed it; false means the submission was already seen
+func (s *LeaderboardService) ClaimSubmission(ctx context.Context, leaderboardID, idempotencyKey string, ttl time.Duration) (bool, error) {
+	claimed, err := s.client.SetNX(ctx, s.dedupKey(leaderboardID, idempotencyKey), 1, ttl).Result()
 	if err != nil {
-		return 0, fmt.Errorf("incrementing score: %w", err)
+		return false, fmt.Errorf("claiming submission: %w", err)
 	}
-	return int64(newScore), nil
+	return claimed, nil
 }
 
-// RemovePlayer removes a player from the leaderboard
-func (s *LeaderboardService) RemovePlayer(ctx context.Context, leaderboardID, playerID string) error {
-	key := s.leaderboardKey(leaderboardID)
-	err := s.client.ZRem(ctx, key, playerID).Err()
+// ReleaseSubmission forgets a claimed idempotency key so the submission can be retried
+func (s *LeaderboardService) ReleaseSubmission(ctx context.Context, leaderboardID, idempotencyKey string) error {
+	if err := s.client.Del(ctx, s.dedupKey(leaderboardID, idempotencyKey)).Err(); err != nil {
+		return fmt.Errorf("releasing submission: %w", err)
+	}
+	return nil
+}
+
+// SetGhost adds or replaces a ghost entry and returns the new leaderboard version
+func (s *LeaderboardService) SetGhost(ctx context.Context, ghost domain.Ghost) (int64, error) {
+	pipe := s.client.TxPipeline()
+	pipe.ZAdd(ctx, s.leaderboardKey(ghost.LeaderboardID), redis.Z{
+		Score:  float64(ghost.Score),
+		Member: ghost.ID,
+	})
+	pipe.HSet(ctx, s.ghostsKey(ghost.LeaderboardID), ghost.ID, ghost.Name)
+	versionCmd := pipe.Incr(ctx, s.versionKey(ghost.LeaderboardID))
+	_, err := pipe.Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("removing player: %w", err)
+		return 0, fmt.Errorf("setting ghost: %w", err)
+	}
+	return versionCmd.Val(), nil
+}
+
+// RemoveGhost removes a ghost entry and returns the new leaderboard version
+func (s *LeaderboardService) RemoveGhost(ctx context.Context, leaderboardID, ghostID string) (int64, error) {
+	pipe := s.client.TxPipeline()
+	pipe.ZRem(ctx, s.leaderboardKey(leaderboardID), ghostID)
+	pipe.HDel(ctx, s.ghostsKey(leaderboardID), ghostID)
+	versionCmd := pipe.Incr(ctx, s.versionKey(leaderboardID))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("removing ghost: %w", err)
+	}
+	return versionCmd.Val(), nil
+}
+
+// GetGhostNames returns the display names of the given ghost entries
+func (s *LeaderboardService) GetGhostNames(ctx context.Context, leaderboardID string, ghostIDs []string) (map[string]string, error) {
+	names := make(map[string]string, len(ghostIDs))
+	if len(ghostIDs) == 0 {
+		return names, nil
+	}
+
+	values, err := s.client.HMGet(ctx, s.ghostsKey(leaderboardID), ghostIDs...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting ghost names: %w", err)
+	}
+	for i, v := range values {
+		if name, ok := v.(string); ok {
+			names[ghostIDs[i]] = name
+		}
+	}
+	return names, nil
+}
+
+// GetGhostCount returns how many ghost entries a leaderboard holds
+func (s *LeaderboardService) GetGhostCount(ctx context.Context, leaderboardID string) (int64, error) {
+	count, err := s.client.HLen(ctx, s.ghostsKey(leaderboardID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("getting ghost count: %w", err)
+	}
+	return count, nil
+}
+
+// SetProof records the proof behind a player's standing score; a nil proof clears it
+func (s *LeaderboardService) SetProof(ctx context.Context, leaderboardID, playerID string, proof *domain.ScoreProof) error {
+	key := s.proofsKey(leaderboardID)
+	if proof == nil {
+		if err := s.client.HDel(ctx, key, playerID).Err(); err != nil {
+			return fmt.Errorf("clearing proof: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(proof)
+	if err != nil {
+		return fmt.Errorf("marshaling proof: %w", err)
+	}
+	if err := s.client.HSet(ctx, key, playerID, data).Err(); err != nil {
+		return fmt.Errorf("setting proof: %w", err)
 	}
 	return nil
 }
 
-// GetTopN returns the top N players from the leaderboard (descending order)
-func (s *LeaderboardService) GetTopN(ctx context.Context, leaderboardID string, n int) ([]domain.LeaderboardEntry, error) {
+// GetProofs returns the proofs recorded for the given players; players without one are omitted
+func (s *LeaderboardService) GetProofs(ctx context.Context, leaderboardID string, playerIDs []string) (map[string]*domain.ScoreProof, error) {
+	proofs := make(map[string]*domain.ScoreProof)
+	if len(playerIDs) == 0 {
+		return proofs, nil
+	}
+
+	values, err := s.client.HMGet(ctx, s.proofsKey(leaderboardID), playerIDs...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting proofs: %w", err)
+	}
+	for i, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var proof domain.ScoreProof
+		if err := json.Unmarshal([]byte(data), &proof); err != nil {
+			s.logger.Warn("skipping malformed proof", "leaderboard_id", leaderboardID, "player_id", playerIDs[i], "error", err)
+			continue
+		}
+		proofs[playerIDs[i]] = &proof
+	}
+	return proofs, nil
+}
+
+// AppendFeedEvent adds an event to a leaderboard's feed, trimming the feed to
+// about maxLen events, and returns the event's stream ID
+func (s *LeaderboardService) AppendFeedEvent(ctx context.Context, event domain.FeedEvent, maxLen int64) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", fmt.Errorf("marshaling feed event: %w", err)
+	}
+
+	id, err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.feedKey(event.LeaderboardID),
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("appending feed event: %w", err)
+	}
+	return id, nil
+}
+
+// GetFeed returns up to limit feed events older than the before stream ID,
+// newest first; an empty before starts from the newest event
+func (s *LeaderboardService) GetFeed(ctx context.Context, leaderboardID, before string, limit int) ([]domain.FeedEvent, error) {
+	end := "+"
+	if before != "" {
+		end = "(" + before
+	}
+
+	messages, err := s.client.XRevRangeN(ctx, s.feedKey(leaderboardID), end, "-", int64(limit)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading feed: %w", err)
+	}
+
+	events := make([]domain.FeedEvent, 0, len(messages))
+	for _, message := range messages {
+		data, _ := message.Values["event"].(string)
+		var event domain.FeedEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			s.logger.Warn("skipping malformed feed event", "leaderboard_id", leaderboardID, "id", message.ID, "error", err)
+			continue
+		}
+		event.ID = message.ID
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// AddPendingScore lists a submission awaiting review
+func (s *LeaderboardService) AddPendingScore(ctx context.Context, pending domain.PendingScore) error {
+	err := s.client.ZAdd(ctx, s.pendingKey(pending.LeaderboardID), redis.Z{
+		Score:  float64(pending.Score),
+		Member: pendingMember(pending.ID, pending.PlayerID),
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("adding pending score: %w", err)
+	}
+	return nil
+}
+
+// RemovePendingScore drops a reviewed submission from the pending list
+func (s *LeaderboardService) RemovePendingScore(ctx context.Context, pending domain.PendingScore) error {
+	err := s.client.ZRem(ctx, s.pendingKey(pending.LeaderboardID), pendingMember(pending.ID, pending.PlayerID)).Err()
+	if err != nil {
+		return fmt.Errorf("removing pending score: %w", err)
+	}
+	return nil
+}
+
+// GetTopPending returns the n best submissions awaiting review, with Rank left unset
+func (s *LeaderboardService) GetTopPending(ctx context.Context, leaderboardID string, n int, higherIsBetter bool) ([]domain.LeaderboardEntry, error) {
+	key := s.pendingKey(leaderboardID)
+	var results []redis.Z
+	var err error
+	if higherIsBetter {
+		results, err = s.client.ZRevRangeWithScores(ctx, key, 0, int64(n-1)).Result()
+	} else {
+		results, err = s.client.ZRangeWithScores(ctx, key, 0, int64(n-1)).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting pending scores: %w", err)
+	}
+
+	entries := make([]domain.LeaderboardEntry, 0, len(results))
+	for _, result := range results {
+		idStr, playerID, ok := strings.Cut(result.Member.(string), ":")
+		if !ok {
+			continue
+		}
+		id, _ := strconv.ParseInt(idStr, 10, 64)
+		entries = append(entries, domain.LeaderboardEntry{
+			PlayerID:  playerID,
+			Score:     int64(result.Score),
+			PendingID: id,
+		})
+	}
+	return entries, nil
+}
+
+// pendingMember encodes a pending submission as a sorted set member
+func pendingMember(id int64, playerID string) string {
+	return strconv.FormatInt(id, 10) + ":" + playerID
+}
+
+// GetVersion returns a leaderboard's current write version; 0 means never written
+func (s *LeaderboardService) GetVersion(ctx context.Context, leaderboardID string) (int64, error) {
+	version, err := s.client.Get(ctx, s.versionKey(leaderboardID)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("getting version: %w", err)
+	}
+	return version, nil
+}
+
+// advanceVersionScript raises a version counter to ARGV[1] without ever lowering it
+var advanceVersionScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local target = tonumber(ARGV[1])
+if target > current then
+	redis.call('SET', KEYS[1], target)
+	return target
+end
+return current
+`)
+
+// AdvanceVersion raises a leaderboard's version to at least the given one,
+// so a replica reports the primary's version once its change is applied
+func (s *LeaderboardService) AdvanceVersion(ctx context.Context, leaderboardID string, version int64) (int64, error) {
+	current, err := advanceVersionScript.Run(ctx, s.client, []string{s.versionKey(leaderboardID)}, version).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("advancing version: %w", err)
+	}
+	return current, nil
+}
+
+// rangeBestFirst reads ranks start..stop of a sorted set best first: highest
+// score first where higher is better, lowest first otherwise
+func rangeBestFirst(ctx context.Context, c redis.Cmdable, key string, start, stop int64, higherIsBetter bool) *redis.ZSliceCmd {
+	if higherIsBetter {
+		return c.ZRevRangeWithScores(ctx, key, start, stop)
+	}
+	return c.ZRangeWithScores(ctx, key, start, stop)
+}
+
+// rankBestFirst reads a member's 0-based rank counted from the best score
+func rankBestFirst(ctx context.Context, c redis.Cmdable, key, member string, higherIsBetter bool) *redis.IntCmd {
+	if higherIsBetter {
+		return c.ZRevRank(ctx, key, member)
+	}
+	return c.ZRank(ctx, key, member)
+}
+
+// rankCommand names the command rankBestFirst uses, for scripts
+func rankCommand(higherIsBetter bool) string {
+	if higherIsBetter {
+		return "ZREVRANK"
+	}
+	return "ZRANK"
+}
+
+// GetTopN returns the best N players from the leaderboard
+func (s *LeaderboardService) GetTopN(ctx context.Context, leaderboardID string, n int, higherIsBetter bool) ([]domain.LeaderboardEntry, error) {
 	key := s.leaderboardKey(leaderboardID)
-	results, err := s.client.ZRevRangeWithScores(ctx, key, 0, int64(n-1)).Result()
+	results, err := rangeBestFirst(ctx, s.client, key, 0, int64(n-1), higherIsBetter).Result()
 	if err != nil {
 		return nil, fmt.Errorf("getting top n: %w", err)
 	}
@@ -139,21 +620,21 @@ func (s *LeaderboardService) GetTopN(ctx context.Context, leaderboardID string,
 		entries[i] = domain.LeaderboardEntry{
 			Rank:     int64(i + 1),
 			PlayerID: result.Member.(string),
-			Score:    int64(result.Score),
+			Score:    decodeScore(result.Score),
 		}
 	}
 	return entries, nil
 }
 
-// GetBottomN returns the bottom N players from the leaderboard (ascending order)
-func (s *LeaderboardService) GetBottomN(ctx context.Context, leaderboardID string, n int) ([]domain.LeaderboardEntry, error) {
+// GetBottomN returns the worst N players from the leaderboard, worst first
+func (s *LeaderboardService) GetBottomN(ctx context.Context, leaderboardID string, n int, higherIsBetter bool) ([]domain.LeaderboardEntry, error) {
 	key := s.leaderboardKey(leaderboardID)
 	totalCount, err := s.client.ZCard(ctx, key).Result()
 	if err != nil {
 		return nil, fmt.Errorf("getting count: %w", err)
 	}
 
-	results, err := s.client.ZRangeWithScores(ctx, key, 0, int64(n-1)).Result()
+	results, err := rangeBestFirst(ctx, s.client, key, 0, int64(n-1), !higherIsBetter).Result()
 	if err != nil {
 		return nil, fmt.Errorf("getting bottom n: %w", err)
 	}
@@ -163,25 +644,25 @@ func (s *LeaderboardService) GetBottomN(ctx context.Context, leaderboardID strin
 		entries[i] = domain.LeaderboardEntry{
 			Rank:     totalCount - int64(i),
 			PlayerID: result.Member.(string),
-			Score:    int64(result.Score),
+			Score:    decodeScore(result.Score),
 		}
 	}
 	return entries, nil
 }
 
 // GetPlayerRank returns a player's rank and score
-func (s *LeaderboardService) GetPlayerRank(ctx context.Context, leaderboardID, playerID string) (*domain.LeaderboardEntry, error) {
+func (s *LeaderboardService) GetPlayerRank(ctx context.Context, leaderboardID, playerID string, higherIsBetter bool) (*domain.LeaderboardEntry, error) {
 	key := s.leaderboardKey(leaderboardID)
 
 	// Use pipeline to get both rank and score
 	pipe := s.client.Pipeline()
-	rankCmd := pipe.ZRevRank(ctx, key, playerID)
+	rankCmd := rankBestFirst(ctx, pipe, key, playerID, higherIsBetter)
 	scoreCmd := pipe.ZScore(ctx, key, playerID)
 	_, err := pipe.Exec(ctx)
 
 	if err != nil {
 		if err == redis.Nil {
-			return nil, domain.ErrPlayerNotFound
+			return nil, domain.NewNotFoundError(domain.ResourcePlayer, playerID)
 		}
 		return nil, fmt.Errorf("getting player rank: %w", err)
 	}
@@ -189,7 +670,7 @@ func (s *LeaderboardService) GetPlayerRank(ctx context.Context, leaderboardID, p
 	rank, err := rankCmd.Result()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, domain.ErrPlayerNotFound
+			return nil, domain.NewNotFoundError(domain.ResourcePlayer, playerID)
 		}
 		return nil, fmt.Errorf("getting rank result: %w", err)
 	}
@@ -202,14 +683,159 @@ func (s *LeaderboardService) GetPlayerRank(ctx context.Context, leaderboardID, p
 	return &domain.LeaderboardEntry{
 		Rank:     rank + 1, // Convert 0-indexed to 1-indexed
 		PlayerID: playerID,
-		Score:    int64(score),
+		Score:    decodeScore(score),
 	}, nil
 }
 
+// GetPlayerRanks returns a player's rank and score on each of the given
+// leaderboards in one pipelined round trip. Boards the player is not on are
+// left out of the result.
+func (s *LeaderboardService) GetPlayerRanks(ctx context.Context, leaderboards []domain.LeaderboardConfig, playerID string) ([]domain.PlayerScore, error) {
+	type commands struct {
+		rank  *redis.IntCmd
+		score *redis.FloatCmd
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]commands, len(leaderboards))
+	for i := range leaderboards {
+		key := s.leaderboardKey(leaderboards[i].ID)
+		cmds[i] = commands{
+			rank:  rankBestFirst(ctx, pipe, key, playerID, leaderboards[i].HigherIsBetter()),
+			score: pipe.ZScore(ctx, key, playerID),
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("getting player ranks: %w", err)
+	}
+
+	var scores []domain.PlayerScore
+	for i, cmd := range cmds {
+		rank, err := cmd.rank.Result()
+		if err != nil {
+			continue
+		}
+		scores = append(scores, domain.PlayerScore{
+			PlayerID:      playerID,
+			LeaderboardID: leaderboards[i].ID,
+			Score:         decodeScore(cmd.score.Val()),
+			Rank:          rank + 1,
+		})
+	}
+	return scores, nil
+}
+
+// GetRanks returns the rank and score of each of the given players on one
+// leaderboard in one pipelined round trip. Players not on the leaderboard are
+// absent from the result.
+func (s *LeaderboardService) GetRanks(ctx context.Context, leaderboardID string, playerIDs []string, higherIsBetter bool) (map[string]domain.LeaderboardEntry, error) {
+	type commands struct {
+		rank  *redis.IntCmd
+		score *redis.FloatCmd
+	}
+
+	entries := make(map[string]domain.LeaderboardEntry, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return entries, nil
+	}
+
+	key := s.leaderboardKey(leaderboardID)
+	pipe := s.client.Pipeline()
+	cmds := make([]commands, len(playerIDs))
+	for i, playerID := range playerIDs {
+		cmds[i] = commands{
+			rank:  rankBestFirst(ctx, pipe, key, playerID, higherIsBetter),
+			score: pipe.ZScore(ctx, key, playerID),
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("getting ranks: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		rank, err := cmd.rank.Result()
+		if err != nil {
+			continue
+		}
+		entries[playerIDs[i]] = domain.LeaderboardEntry{
+			Rank:     rank + 1,
+			PlayerID: playerIDs[i],
+			Score:    decodeScore(cmd.score.Val()),
+		}
+	}
+	return entries, nil
+}
+
+// playerStandingScript reads a player's rank with ARGV[1] (ZREVRANK or ZRANK)
+// and the entries ranked just before and after it with ARGV[2] (ZREVRANGE or
+// ZRANGE), so neighbours follow the sorted set's order even between tied
+// scores. It returns false when the player has no score.
+var playerStandingScript = redis.NewScript(`
+local rank = redis.call(ARGV[1], KEYS[1], ARGV[3])
+if not rank then
+	return false
+end
+local start = rank - 1
+if start < 0 then
+	start = 0
+end
+return {rank, redis.call(ARGV[2], KEYS[1], start, rank + 1, 'WITHSCORES')}
+`)
+
+// GetPlayerStanding returns a player's rank along with the neighbouring entries
+// by rank, read in a single step
+func (s *LeaderboardService) GetPlayerStanding(ctx context.Context, leaderboardID, playerID string, higherIsBetter bool) (*domain.PlayerStanding, error) {
+	rangeCommand := "ZRANGE"
+	if higherIsBetter {
+		rangeCommand = "ZREVRANGE"
+	}
+	result, err := playerStandingScript.Run(ctx, s.client, []string{s.leaderboardKey(leaderboardID)},
+		rankCommand(higherIsBetter), rangeCommand, playerID).Slice()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, domain.NewNotFoundError(domain.ResourcePlayer, playerID)
+		}
+		return nil, fmt.Errorf("getting player standing: %w", err)
+	}
+	if len(result) != 2 {
+		return nil, fmt.Errorf("getting player standing: unexpected reply %v", result)
+	}
+	rank, _ := result[0].(int64)
+	window, _ := result[1].([]interface{})
+
+	standing := &domain.PlayerStanding{}
+	first := rank - 1
+	if first < 0 {
+		first = 0
+	}
+	for i := 0; i+1 < len(window); i += 2 {
+		member, _ := window[i].(string)
+		raw, _ := window[i+1].(string)
+		score, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("getting player standing: %w", err)
+		}
+		entry := domain.LeaderboardEntry{
+			Rank:     first + int64(i/2) + 1,
+			PlayerID: member,
+			Score:    decodeScore(score),
+		}
+		switch {
+		case entry.Rank == rank:
+			standing.Above = &entry
+		case entry.Rank == rank+1:
+			standing.Entry = entry
+		default:
+			standing.Below = &entry
+		}
+	}
+	return standing, nil
+}
+
 // GetAroundPlayer returns players around a specific player's rank
-func (s *LeaderboardService) GetAroundPlayer(ctx context.Context, leaderboardID, playerID string, count int) ([]domain.LeaderboardEntry, error) {
+func (s *LeaderboardService) GetAroundPlayer(ctx context.Context, leaderboardID, playerID string, count int, higherIsBetter bool) ([]domain.LeaderboardEntry, error) {
 	// First, get the player's rank
-	playerEntry, err := s.GetPlayerRank(ctx, leaderboardID, playerID)
+	playerEntry, err := s.GetPlayerRank(ctx, leaderboardID, playerID, higherIsBetter)
 	if err != nil {
 		return nil, err
 	}
@@ -221,13 +847,19 @@ func (s *LeaderboardService) GetAroundPlayer(ctx context.Context, leaderboardID,
 	}
 	end := playerEntry.Rank + int64(count) - 1 // -1 because rank is 1-indexed
 
-	return s.GetRange(ctx, leaderboardID, int(start), int(end))
+	return s.GetRange(ctx, leaderboardID, int(start), int(end), higherIsBetter)
 }
 
-// GetRange returns players within a specific rank range (0-indexed)
-func (s *LeaderboardService) GetRange(ctx context.Context, leaderboardID string, start, end int) ([]domain.LeaderboardEntry, error) {
-	key := s.leaderboardKey(leaderboardID)
-	results, err := s.client.ZRevRangeWithScores(ctx, key, int64(start), int64(end)).Result()
+// GetRange returns players within a specific rank range (0-indexed). Ranges
+// deep enough for the page cache are read from materialized windows.
+func (s *LeaderboardService) GetRange(ctx context.Context, leaderboardID string, start, end int, higherIsBetter bool) ([]domain.LeaderboardEntry, error) {
+	var results []redis.Z
+	var err error
+	if s.pages.serves(start) {
+		results, err = s.getCachedRange(ctx, leaderboardID, start, end, higherIsBetter)
+	} else {
+		results, err = rangeBestFirst(ctx, s.client, s.leaderboardKey(leaderboardID), int64(start), int64(end), higherIsBetter).Result()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("getting range: %w", err)
 	}
@@ -237,12 +869,77 @@ func (s *LeaderboardService) GetRange(ctx context.Context, leaderboardID string,
 		entries[i] = domain.LeaderboardEntry{
 			Rank:     int64(start + i + 1), // Convert to 1-indexed rank
 			PlayerID: result.Member.(string),
-			Score:    int64(result.Score),
+			Score:    decodeScore(result.Score),
 		}
 	}
 	return entries, nil
 }
 
+// GetScore returns a player's current score and whether the player is on the leaderboard
+func (s *LeaderboardService) GetScore(ctx context.Context, leaderboardID, playerID string) (int64, bool, error) {
+	key := s.leaderboardKey(leaderboardID)
+	score, err := s.client.ZScore(ctx, key, playerID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("getting score: %w", err)
+	}
+	return decodeScore(score), true, nil
+}
+
+// GetScores returns the current scores of the given players; players not on
+// the leaderboard are absent from the result
+func (s *LeaderboardService) GetScores(ctx context.Context, leaderboardID string, playerIDs []string) (map[string]int64, error) {
+	scores := make(map[string]int64, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return scores, nil
+	}
+
+	// ZMSCORE through Do, since the typed command reads missing members as 0
+	args := make([]interface{}, 0, len(playerIDs)+2)
+	args = append(args, "ZMSCORE", s.leaderboardKey(leaderboardID))
+	for _, playerID := range playerIDs {
+		args = append(args, playerID)
+	}
+	values, err := s.client.Do(ctx, args...).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("getting scores: %w", err)
+	}
+	for i, v := range values {
+		if i >= len(playerIDs) {
+			break
+		}
+		switch score := v.(type) {
+		case string:
+			f, err := strconv.ParseFloat(score, 64)
+			if err == nil {
+				scores[playerIDs[i]] = decodeScore(f)
+			}
+		case float64:
+			scores[playerIDs[i]] = decodeScore(score)
+		}
+	}
+	return scores, nil
+}
+
+// CountBetter returns the number of players with a strictly better score than the given one
+func (s *LeaderboardService) CountBetter(ctx context.Context, leaderboardID string, score int64, higherIsBetter bool) (int64, error) {
+	key := s.leaderboardKey(leaderboardID)
+
+	var count int64
+	var err error
+	if higherIsBetter {
+		count, err = s.client.ZCount(ctx, key, higherScores(score), "+inf").Result()
+	} else {
+		count, err = s.client.ZCount(ctx, key, "-inf", lowerScores(score)).Result()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("counting better scores: %w", err)
+	}
+	return count, nil
+}
+
 // GetCount returns the total number of players in the leaderboard
 func (s *LeaderboardService) GetCount(ctx context.Context, leaderboardID string) (int64, error) {
 	key := s.leaderboardKey(leaderboardID)
@@ -253,10 +950,10 @@ func (s *LeaderboardService) GetCount(ctx context.Context, leaderboardID string)
 	return count, nil
 }
 
-// GetAllScores returns all players and scores from the leaderboard
-func (s *LeaderboardService) GetAllScores(ctx context.Context, leaderboardID string) ([]domain.LeaderboardEntry, error) {
+// GetAllScores returns all players and scores from the leaderboard, best first
+func (s *LeaderboardService) GetAllScores(ctx context.Context, leaderboardID string, higherIsBetter bool) ([]domain.LeaderboardEntry, error) {
 	key := s.leaderboardKey(leaderboardID)
-	results, err := s.client.ZRevRangeWithScores(ctx, key, 0, -1).Result()
+	results, err := rangeBestFirst(ctx, s.client, key, 0, -1, higherIsBetter).Result()
 	if err != nil {
 		return nil, fmt.Errorf("getting all scores: %w", err)
 	}
@@ -266,12 +963,31 @@ func (s *LeaderboardService) GetAllScores(ctx context.Context, leaderboardID str
 		entries[i] = domain.LeaderboardEntry{
 			Rank:     int64(i + 1),
 			PlayerID: result.Member.(string),
-			Score:    int64(result.Score),
+			Score:    decodeScore(result.Score),
 		}
 	}
 	return entries, nil
 }
 
+// GetWriteTimes returns when each player's score was last written to Redis.
+// Players restored from PostgreSQL or written before tracking existed are absent.
+func (s *LeaderboardService) GetWriteTimes(ctx context.Context, leaderboardID string) (map[string]time.Time, error) {
+	result, err := s.client.HGetAll(ctx, s.writesKey(leaderboardID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("getting write times: %w", err)
+	}
+
+	times := make(map[string]time.Time, len(result))
+	for playerID, value := range result {
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		times[playerID] = time.UnixMilli(ms)
+	}
+	return times, nil
+}
+
 // DeleteLeaderboard removes an entire leaderboard
 func (s *LeaderboardService) DeleteLeaderboard(ctx context.Context, leaderboardID string) error {
 	key := s.leaderboardKey(leaderboardID)
@@ -280,23 +996,257 @@ func (s *LeaderboardService) DeleteLeaderboard(ctx context.Context, leaderboardI
 	pipe := s.client.Pipeline()
 	pipe.Del(ctx, key)
 	pipe.Del(ctx, metaKey)
+	pipe.Del(ctx, s.writesKey(leaderboardID))
+	pipe.Del(ctx, s.lastWriteKey(leaderboardID))
+	pipe.Del(ctx, s.versionKey(leaderboardID))
+	pipe.Del(ctx, s.submissionsKey(leaderboardID))
+	pipe.Del(ctx, s.ghostsKey(leaderboardID))
+	pipe.Del(ctx, s.pendingKey(leaderboardID))
+	pipe.Del(ctx, s.proofsKey(leaderboardID))
+	pipe.Del(ctx, s.feedKey(leaderboardID))
+	pipe.Del(ctx, s.submittedKey(leaderboardID))
 	_, err := pipe.Exec(ctx)
 	if err != nil {
 		return fmt.Errorf("deleting leaderboard: %w", err)
 	}
-	return nil
+	if err := s.deleteQuotaKeys(ctx, leaderboardID); err != nil {
+		return err
+	}
+	return s.dropPages(ctx, leaderboardID)
 }
 
-// ResetLeaderboard clears all entries from a leaderboard
-func (s *LeaderboardService) ResetLeaderboard(ctx context.Context, leaderboardID string) error {
+// ResetLeaderboard clears all entries from a leaderboard and returns the new leaderboard version
+func (s *LeaderboardService) ResetLeaderboard(ctx context.Context, leaderboardID string) (int64, error) {
 	key := s.leaderboardKey(leaderboardID)
-	err := s.client.Del(ctx, key).Err()
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, key, s.writesKey(leaderboardID), s.submissionsKey(leaderboardID), s.ghostsKey(leaderboardID),
+		s.proofsKey(leaderboardID), s.submittedKey(leaderboardID))
+	versionCmd := pipe.Incr(ctx, s.versionKey(leaderboardID))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("resetting leaderboard: %w", err)
+	}
+	if err := s.dropPages(ctx, leaderboardID); err != nil {
+		return 0, err
+	}
+	return versionCmd.Val(), nil
+}
+
+// scoreMomentsScript computes the mean and sum of squared deviations of a
+// leaderboard's scores with Welford's method, reading every score when the board
+// holds at most ARGV[1] players and a random sample of ARGV[1] otherwise.
+// Members starting with ARGV[2] (ghost entries) are skipped.
+// Floats are returned as strings because Lua numbers are truncated in replies.
+var scoreMomentsScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local values
+local sampled = 0
+if redis.call('ZCARD', KEYS[1]) <= limit then
+	values = redis.call('ZRANGE', KEYS[1], 0, -1, 'WITHSCORES')
+else
+	values = redis.call('ZRANDMEMBER', KEYS[1], limit, 'WITHSCORES')
+	sampled = 1
+end
+local n, mean, m2 = 0, 0, 0
+for i = 1, #values, 2 do
+	if string.sub(values[i], 1, #ARGV[2]) ~= ARGV[2] then
+		local x = math.floor(tonumber(values[i + 1]))
+		n = n + 1
+		local delta = x - mean
+		mean = mean + delta / n
+		m2 = m2 + delta * (x - mean)
+	end
+end
+return {n, tostring(mean), tostring(m2), sampled}
+`)
+
+// firstScoreScript returns the score of the first member, in ARGV[1] order
+// (ZRANGE or ZREVRANGE), that does not start with ARGV[2]
+var firstScoreScript = redis.NewScript(`
+local start = 0
+while true do
+	local batch = redis.call(ARGV[1], KEYS[1], start, start + 99, 'WITHSCORES')
+	if #batch == 0 then
+		return false
+	end
+	for i = 1, #batch, 2 do
+		if string.sub(batch[i], 1, #ARGV[2]) ~= ARGV[2] then
+			return batch[i + 1]
+		end
+	end
+	start = start + 100
+end
+`)
+
+// GetPlayerScoreBounds returns the highest and lowest player scores on a
+// leaderboard, ignoring ghost entries; both are nil when no player has a score
+func (s *LeaderboardService) GetPlayerScoreBounds(ctx context.Context, leaderboardID string) (*int64, *int64, error) {
+	key := []string{s.leaderboardKey(leaderboardID)}
+	first := func(command string) (*int64, error) {
+		return scriptScore(firstScoreScript.Run(ctx, s.client, key, command, domain.GhostIDPrefix))
+	}
+
+	highest, err := first("ZREVRANGE")
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting highest score: %w", err)
+	}
+	lowest, err := first("ZRANGE")
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting lowest score: %w", err)
+	}
+	return highest, lowest, nil
+}
+
+// GetLastWrites returns when a submission was last applied to each of the
+// given leaderboards; boards never written to are absent from the result
+func (s *LeaderboardService) GetLastWrites(ctx context.Context, leaderboardIDs []string) (map[string]time.Time, error) {
+	times := make(map[string]time.Time, len(leaderboardIDs))
+	if len(leaderboardIDs) == 0 {
+		return times, nil
+	}
+
+	keys := make([]string, len(leaderboardIDs))
+	for i, id := range leaderboardIDs {
+		keys[i] = s.lastWriteKey(id)
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
 	if err != nil {
-		return fmt.Errorf("resetting leaderboard: %w", err)
+		return nil, fmt.Errorf("getting last writes: %w", err)
+	}
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			times[leaderboardIDs[i]] = time.UnixMilli(ms)
+		}
+	}
+	return times, nil
+}
+
+// activityWindow is how many minutes of activity counters a summary sums
+const activityWindow = 60
+
+// RecordActivity counts a submission applied to a leaderboard in its minute
+// bucket and stamps it as the board's last write
+func (s *LeaderboardService) RecordActivity(ctx context.Context, leaderboardID string, at time.Time) error {
+	key := s.activityKey(leaderboardID, at.Unix()/60)
+	pipe := s.client.Pipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, (activityWindow+5)*time.Minute)
+	pipe.Set(ctx, s.lastWriteKey(leaderboardID), at.UnixMilli(), 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("recording activity: %w", err)
 	}
 	return nil
 }
 
+// BoardSummary is the overview of one leaderboard. Members includes ghosts, and
+// Top lists the best entries, which may include ghosts.
+type BoardSummary struct {
+	Members  int64
+	Ghosts   int64
+	Top      []domain.LeaderboardEntry
+	LastHour int64
+}
+
+// GetBoardSummaries reads the overview of several leaderboards in one pipelined
+// round trip: member and ghost counts, the best topN entries in each board's
+// sort order, and the number of submissions applied in the hour before now
+func (s *LeaderboardService) GetBoardSummaries(ctx context.Context, leaderboards []domain.LeaderboardConfig, topN int, now time.Time) ([]BoardSummary, error) {
+	type commands struct {
+		members  *redis.IntCmd
+		ghosts   *redis.IntCmd
+		top      *redis.ZSliceCmd
+		activity *redis.SliceCmd
+	}
+
+	minute := now.Unix() / 60
+	pipe := s.client.Pipeline()
+	cmds := make([]commands, len(leaderboards))
+	for i := range leaderboards {
+		id := leaderboards[i].ID
+		keys := make([]string, activityWindow)
+		for j := range keys {
+			keys[j] = s.activityKey(id, minute-int64(j))
+		}
+		cmds[i] = commands{
+			members:  pipe.ZCard(ctx, s.leaderboardKey(id)),
+			ghosts:   pipe.HLen(ctx, s.ghostsKey(id)),
+			top:      rangeBestFirst(ctx, pipe, s.leaderboardKey(id), 0, int64(topN-1), leaderboards[i].HigherIsBetter()),
+			activity: pipe.MGet(ctx, keys...),
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("reading leaderboard summaries: %w", err)
+	}
+
+	summaries := make([]BoardSummary, len(leaderboards))
+	for i, cmd := range cmds {
+		summary := BoardSummary{
+			Members: cmd.members.Val(),
+			Ghosts:  cmd.ghosts.Val(),
+		}
+		for rank, z := range cmd.top.Val() {
+			summary.Top = append(summary.Top, domain.LeaderboardEntry{
+				Rank:     int64(rank + 1),
+				PlayerID: z.Member.(string),
+				Score:    decodeScore(z.Score),
+			})
+		}
+		for _, value := range cmd.activity.Val() {
+			if count, ok := value.(string); ok {
+				n, _ := strconv.ParseInt(count, 10, 64)
+				summary.LastHour += n
+			}
+		}
+		summaries[i] = summary
+	}
+	return summaries, nil
+}
+
+// ScoreMoments summarizes the distribution of a leaderboard's scores
+type ScoreMoments struct {
+	Count   int64
+	Mean    float64
+	M2      float64 // sum of squared deviations from the mean
+	Sampled bool
+}
+
+// GetScoreMoments returns the mean and spread of a leaderboard's scores, read
+// exactly for boards up to sampleSize players and from a random sample above that
+func (s *LeaderboardService) GetScoreMoments(ctx context.Context, leaderboardID string, sampleSize int) (*ScoreMoments, error) {
+	result, err := scoreMomentsScript.Run(ctx, s.client, []string{s.leaderboardKey(leaderboardID)}, sampleSize, domain.GhostIDPrefix).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("computing score moments: %w", err)
+	}
+	if len(result) != 4 {
+		return nil, fmt.Errorf("computing score moments: unexpected reply length %d", len(result))
+	}
+
+	count, _ := result[0].(int64)
+	meanStr, _ := result[1].(string)
+	m2Str, _ := result[2].(string)
+	sampled, _ := result[3].(int64)
+
+	mean, err := strconv.ParseFloat(meanStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing score mean: %w", err)
+	}
+	m2, err := strconv.ParseFloat(m2Str, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing score deviation: %w", err)
+	}
+
+	return &ScoreMoments{
+		Count:   count,
+		Mean:    mean,
+		M2:      m2,
+		Sampled: sampled == 1,
+	}, nil
+}
+
 // SetLeaderboardMeta stores leaderboard metadata
 func (s *LeaderboardService) SetLeaderboardMeta(ctx context.Context, config domain.LeaderboardConfig) error {
 	key := s.metaKey(config.ID)
@@ -307,6 +1257,33 @@ func (s *LeaderboardService) SetLeaderboardMeta(ctx context.Context, config doma
 		"reset_period", string(config.ResetPeriod),
 		"max_entries", config.MaxEntries,
 		"update_mode", string(config.UpdateMode),
+		"shadow_id", config.ShadowID,
+		"parent_id", config.ParentID,
+		"filter", config.Filter,
+		"update_throttle_ms", config.UpdateThrottleMs,
+		"min_rank_change", config.MinRankChange,
+		"min_score_change", config.MinScoreChange,
+		"score_unit", config.ScoreUnit,
+		"score_multiplier", strconv.FormatFloat(config.ScoreMultiplier, 'g', -1, 64),
+		"score_offset", config.ScoreOffset,
+		"score_rounding", string(config.ScoreRounding),
+		"score_formula", config.ScoreFormula,
+		"script_version", config.ScriptVersion,
+		"min_score", formatOptionalInt(config.MinScore),
+		"max_score", formatOptionalInt(config.MaxScore),
+		"clamp_min", formatOptionalInt(config.ClampMin),
+		"clamp_max", formatOptionalInt(config.ClampMax),
+		"timezone", config.Timezone,
+		"open_at", formatOptionalTime(config.OpenAt),
+		"close_at", formatOptionalTime(config.CloseAt),
+		"min_submissions", config.MinSubmissions,
+		"inactivity_days", config.InactivityDays,
+		"attempt_quota", config.AttemptQuota,
+		"quota_period", string(config.QuotaPeriod),
+		"quota_exceeded", string(config.QuotaExceeded),
+		"tie_break", string(config.TieBreak),
+		"review_threshold", formatOptionalInt(config.ReviewThreshold),
+		"sensitive_fields", strings.Join(config.SensitiveFields, ","),
 	).Err()
 	if err != nil {
 		return fmt.Errorf("setting leaderboard meta: %w", err)
@@ -314,6 +1291,15 @@ func (s *LeaderboardService) SetLeaderboardMeta(ctx context.Context, config doma
 	return nil
 }
 
+// DeleteLeaderboardMeta removes only a leaderboard's metadata, undoing
+// SetLeaderboardMeta for a board whose creation failed
+func (s *LeaderboardService) DeleteLeaderboardMeta(ctx context.Context, leaderboardID string) error {
+	if err := s.client.Del(ctx, s.metaKey(leaderboardID)).Err(); err != nil {
+		return fmt.Errorf("deleting leaderboard meta: %w", err)
+	}
+	return nil
+}
+
 // GetLeaderboardMeta retrieves leaderboard metadata
 func (s *LeaderboardService) GetLeaderboardMeta(ctx context.Context, leaderboardID string) (*domain.LeaderboardConfig, error) {
 	key := s.metaKey(leaderboardID)
@@ -323,10 +1309,23 @@ func (s *LeaderboardService) GetLeaderboardMeta(ctx context.Context, leaderboard
 	}
 
 	if len(result) == 0 {
-		return nil, domain.ErrLeaderboardNotFound
+		return nil, domain.NewNotFoundError(domain.ResourceLeaderboard, leaderboardID)
 	}
+	return parseLeaderboardMeta(result), nil
+}
 
+// parseLeaderboardMeta decodes a leaderboard meta hash written by SetLeaderboardMeta
+func parseLeaderboardMeta(result map[string]string) *domain.LeaderboardConfig {
 	maxEntries, _ := strconv.Atoi(result["max_entries"])
+	updateThrottleMs, _ := strconv.ParseInt(result["update_throttle_ms"], 10, 64)
+	minRankChange, _ := strconv.ParseInt(result["min_rank_change"], 10, 64)
+	minScoreChange, _ := strconv.ParseInt(result["min_score_change"], 10, 64)
+	scoreMultiplier, _ := strconv.ParseFloat(result["score_multiplier"], 64)
+	scoreOffset, _ := strconv.ParseInt(result["score_offset"], 10, 64)
+	minSubmissions, _ := strconv.ParseInt(result["min_submissions"], 10, 64)
+	inactivityDays, _ := strconv.Atoi(result["inactivity_days"])
+	attemptQuota, _ := strconv.Atoi(result["attempt_quota"])
+	scriptVersion, _ := strconv.Atoi(result["script_version"])
 
 	return &domain.LeaderboardConfig{
 		ID:          result["id"],
@@ -335,47 +1334,246 @@ func (s *LeaderboardService) GetLeaderboardMeta(ctx context.Context, leaderboard
 		ResetPeriod: domain.ResetPeriod(result["reset_period"]),
 		MaxEntries:  maxEntries,
 		UpdateMode:  domain.UpdateMode(result["update_mode"]),
-	}, nil
+		ShadowID:    result["shadow_id"],
+		TieBreak:    domain.TieBreak(result["tie_break"]),
+		ParentID:    result["parent_id"],
+		Filter:      result["filter"],
+
+		UpdateThrottleMs: updateThrottleMs,
+		MinRankChange:    minRankChange,
+		MinScoreChange:   minScoreChange,
+
+		ScoreUnit:       result["score_unit"],
+		ScoreMultiplier: scoreMultiplier,
+		ScoreOffset:     scoreOffset,
+		ScoreRounding:   domain.ScoreRounding(result["score_rounding"]),
+		ScoreFormula:    result["score_formula"],
+		ScriptVersion:   scriptVersion,
+
+		MinScore: parseOptionalInt(result["min_score"]),
+		MaxScore: parseOptionalInt(result["max_score"]),
+		ClampMin: parseOptionalInt(result["clamp_min"]),
+		ClampMax: parseOptionalInt(result["clamp_max"]),
+		Timezone: result["timezone"],
+		OpenAt:   parseOptionalTime(result["open_at"]),
+		CloseAt:  parseOptionalTime(result["close_at"]),
+
+		MinSubmissions:  minSubmissions,
+		InactivityDays:  inactivityDays,
+		AttemptQuota:    attemptQuota,
+		QuotaPeriod:     domain.ResetPeriod(result["quota_period"]),
+		QuotaExceeded:   domain.QuotaExceeded(result["quota_exceeded"]),
+		ReviewThreshold: parseOptionalInt(result["review_threshold"]),
+		SensitiveFields: parseList(result["sensitive_fields"]),
+	}
+}
+
+// formatOptionalInt encodes an optional integer as a hash field value, empty when unset
+func formatOptionalInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// formatOptionalTime encodes an optional time as a hash field value, empty when unset
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
 }
 
-// SetPlayerInfo caches player information
-func (s *LeaderboardService) SetPlayerInfo(ctx context.Context, playerID, username string) error {
-	key := s.playerInfoKey(playerID)
-	err := s.client.HSet(ctx, key, "username", username).Err()
+// parseOptionalTime decodes a hash field value written by formatOptionalTime
+func parseOptionalTime(s string) *time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
 	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// parseOptionalInt decodes a hash field value written by formatOptionalInt
+func parseOptionalInt(s string) *int64 {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+// parseList decodes a comma-separated hash field value; empty means none
+func parseList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// SetPlayerInfo caches player information for the configured player info TTL
+func (s *LeaderboardService) SetPlayerInfo(ctx context.Context, info domain.PlayerInfo) error {
+	key := s.playerInfoKey(info.ID)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, "username", info.Username, "avatar_url", info.AvatarURL)
+	if s.playerInfoTTL > 0 {
+		pipe.Expire(ctx, key, s.playerInfoTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("setting player info: %w", err)
 	}
 	return nil
 }
 
-// GetPlayerInfo retrieves cached player information
+// SetPlayerInfos caches several players' information in one round trip
+func (s *LeaderboardService) SetPlayerInfos(ctx context.Context, infos []domain.PlayerInfo) error {
+	if len(infos) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	for _, info := range infos {
+		key := s.playerInfoKey(info.ID)
+		pipe.HSet(ctx, key, "username", info.Username, "avatar_url", info.AvatarURL)
+		if s.playerInfoTTL > 0 {
+			pipe.Expire(ctx, key, s.playerInfoTTL)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("setting player info: %w", err)
+	}
+	return nil
+}
+
+// GetPlayerInfo retrieves cached player information and extends its TTL, so
+// players that are still looked up never expire
 func (s *LeaderboardService) GetPlayerInfo(ctx context.Context, playerID string) (*domain.PlayerInfo, error) {
-	key := s.playerInfoKey(playerID)
-	result, err := s.client.HGetAll(ctx, key).Result()
+	infos, err := s.GetPlayerInfos(ctx, []string{playerID})
 	if err != nil {
+		return nil, err
+	}
+	info, ok := infos[playerID]
+	if !ok {
+		return nil, domain.NewNotFoundError(domain.ResourcePlayer, playerID)
+	}
+	return &info, nil
+}
+
+// GetPlayerInfos retrieves the cached information of several players in one
+// round trip and extends its TTL. Players without cached information are
+// missing from the result.
+func (s *LeaderboardService) GetPlayerInfos(ctx context.Context, playerIDs []string) (map[string]domain.PlayerInfo, error) {
+	infos := make(map[string]domain.PlayerInfo, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return infos, nil
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(playerIDs))
+	for i, playerID := range playerIDs {
+		key := s.playerInfoKey(playerID)
+		cmds[i] = pipe.HGetAll(ctx, key)
+		if s.playerInfoTTL > 0 {
+			pipe.Expire(ctx, key, s.playerInfoTTL)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return nil, fmt.Errorf("getting player info: %w", err)
 	}
 
-	if len(result) == 0 {
-		return nil, domain.ErrPlayerNotFound
+	for i, playerID := range playerIDs {
+		result := cmds[i].Val()
+		if len(result) == 0 {
+			continue
+		}
+		infos[playerID] = domain.PlayerInfo{
+			ID:        playerID,
+			Username:  result["username"],
+			AvatarURL: result["avatar_url"],
+		}
+	}
+	return infos, nil
+}
+
+// DeletePlayerInfo drops a player's cached information
+func (s *LeaderboardService) DeletePlayerInfo(ctx context.Context, playerID string) error {
+	if err := s.client.Del(ctx, s.playerInfoKey(playerID)).Err(); err != nil {
+		return fmt.Errorf("deleting player info: %w", err)
 	}
+	return nil
+}
 
-	return &domain.PlayerInfo{
-		ID:       playerID,
-		Username: result["username"],
-	}, nil
+// SweepPlayerInfo gives player info hashes written without a TTL, for example
+// before TTLs were configured, the player info TTL. It scans count keys per
+// step and returns how many keys it updated.
+func (s *LeaderboardService) SweepPlayerInfo(ctx context.Context, count int64) (int64, error) {
+	if s.playerInfoTTL <= 0 {
+		return 0, nil
+	}
+
+	var swept int64
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, s.namespace+"player:*:info", count).Result()
+		if err != nil {
+			return swept, fmt.Errorf("scanning player info: %w", err)
+		}
+
+		if len(keys) > 0 {
+			pipe := s.client.Pipeline()
+			for _, key := range keys {
+				// NX only sets an expiry on keys that have none
+				pipe.ExpireNX(ctx, key, s.playerInfoTTL)
+			}
+			cmds, err := pipe.Exec(ctx)
+			if err != nil {
+				return swept, fmt.Errorf("expiring player info: %w", err)
+			}
+			for _, cmd := range cmds {
+				if cmd.(*redis.BoolCmd).Val() {
+					swept++
+				}
+			}
+		}
+
+		cursor = next
+		if cursor == 0 || ctx.Err() != nil {
+			return swept, ctx.Err()
+		}
+	}
 }
 
-// BatchSetScores sets multiple scores using pipelining
+// restoreScoresScript sets the players and scores paired in ARGV, leaving a
+// player whose stored score already matches alone so its tie-break component,
+// which PostgreSQL does not keep, survives the restore
+var restoreScoresScript = redis.NewScript(`
+for i = 1, #ARGV, 2 do
+	local current = redis.call('ZSCORE', KEYS[1], ARGV[i])
+	if not current or math.floor(tonumber(current)) ~= tonumber(ARGV[i + 1]) then
+		redis.call('ZADD', KEYS[1], ARGV[i + 1], ARGV[i])
+	end
+end
+return 0
+`)
+
+// restoreBatch bounds the players set by one restoreScoresScript call
+const restoreBatch = 500
+
+// BatchSetScores sets multiple scores using pipelining. Players whose score is
+// unchanged keep their tie-break component.
 func (s *LeaderboardService) BatchSetScores(ctx context.Context, leaderboardID string, scores map[string]int64) error {
-	key := s.leaderboardKey(leaderboardID)
+	key := []string{s.leaderboardKey(leaderboardID)}
 	pipe := s.client.Pipeline()
 
+	args := make([]interface{}, 0, 2*restoreBatch)
 	for playerID, score := range scores {
-		pipe.ZAdd(ctx, key, redis.Z{
-			Score:  float64(score),
-			Member: playerID,
-		})
+		args = append(args, playerID, score)
+		if len(args) == cap(args) {
+			// Eval rather than Run: a NOSCRIPT from EVALSHA would only surface at Exec
+			restoreScoresScript.Eval(ctx, pipe, key, args...)
+			args = make([]interface{}, 0, 2*restoreBatch)
+		}
+	}
+	if len(args) > 0 {
+		restoreScoresScript.Eval(ctx, pipe, key, args...)
 	}
 
 	_, err := pipe.Exec(ctx)
@@ -385,6 +1583,20 @@ func (s *LeaderboardService) BatchSetScores(ctx context.Context, leaderboardID s
 	return nil
 }
 
+// ListLeaderboardIDs returns the IDs of all leaderboards that have a sorted set in Redis
+func (s *LeaderboardService) ListLeaderboardIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	iter := s.client.Scan(ctx, 0, s.namespace+"leaderboard:*:realtime", 1000).Iterator()
+	for iter.Next(ctx) {
+		id := strings.TrimPrefix(iter.Val(), s.namespace+"leaderboard:")
+		ids = append(ids, strings.TrimSuffix(id, ":realtime"))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("listing leaderboards: %w", err)
+	}
+	return ids, nil
+}
+
 // Exists checks if a leaderboard exists in Redis
 func (s *LeaderboardService) Exists(ctx context.Context, leaderboardID string) (bool, error) {
 	key := s.leaderboardKey(leaderboardID)
@@ -0,0 +1,82 @@
+//go:build integration
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/leaderboard-redis/internal/testutil"
+)
+
+// TestSetScoreIfBetterConcurrent races many submissions for one player and
+// checks that the best of them is the score left standing
+func TestSetScoreIfBetterConcurrent(t *testing.T) {
+	env := testutil.NewEnv(t)
+	ctx := context.Background()
+
+	for _, higherIsBetter := range []bool{true, false} {
+		t.Run(fmt.Sprintf("higher_is_better=%v", higherIsBetter), func(t *testing.T) {
+			leaderboardID := "race-" + uuid.New().String()[:8]
+			t.Cleanup(func() { _ = env.Redis.DeleteLeaderboard(context.Background(), leaderboardID) })
+
+			const workers, perWorker = 16, 50
+			scores := rand.Perm(workers * perWorker)
+			best := int64(scores[0])
+			for _, score := range scores {
+				if (higherIsBetter && int64(score) > best) || (!higherIsBetter && int64(score) < best) {
+					best = int64(score)
+				}
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			written := 0
+			errs := make(chan error, workers)
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(batch []int) {
+					defer wg.Done()
+					for _, score := range batch {
+						ok, _, err := env.Redis.SetScoreIfBetter(ctx, leaderboardID, "player", int64(score), 0, higherIsBetter)
+						if err != nil {
+							errs <- err
+							return
+						}
+						if ok {
+							mu.Lock()
+							written++
+							mu.Unlock()
+						}
+					}
+				}(scores[w*perWorker : (w+1)*perWorker])
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Fatalf("SetScoreIfBetter: %v", err)
+			}
+
+			entry, err := env.Redis.GetPlayerRank(ctx, leaderboardID, "player", higherIsBetter)
+			if err != nil {
+				t.Fatalf("GetPlayerRank: %v", err)
+			}
+			if entry.Score != best {
+				t.Errorf("stored score = %d, want best %d", entry.Score, best)
+			}
+
+			// Every accepted write bumps the version once, and only improvements are accepted
+			version, err := env.Redis.GetVersion(ctx, leaderboardID)
+			if err != nil {
+				t.Fatalf("GetVersion: %v", err)
+			}
+			if version != int64(written) {
+				t.Errorf("version = %d, want %d accepted writes", version, written)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// namespacedPatterns are the key families moved when the key namespace changes
+var namespacedPatterns = []string{"leaderboard:*", "player:*:info", "features"}
+
+// MigrateNamespace renames every key from the given namespace into this
+// service's namespace, for example after key_prefix or tenant is set on a
+// Redis instance that already holds data. Keys that already exist under the
+// new name are left alone and counted as skipped. With dryRun set nothing is
+// renamed and the counts describe what would happen.
+func (s *LeaderboardService) MigrateNamespace(ctx context.Context, from string, dryRun bool) (renamed, skipped int64, err error) {
+	if from == s.namespace {
+		return 0, 0, fmt.Errorf("source and target key namespace are both %q", from)
+	}
+
+	for _, pattern := range namespacedPatterns {
+		iter := s.client.Scan(ctx, 0, from+pattern, 1000).Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			// An empty source namespace also matches keys of the new one on the way back
+			if from == "" && s.namespace != "" && strings.HasPrefix(key, s.namespace) {
+				continue
+			}
+			target := s.namespace + strings.TrimPrefix(key, from)
+
+			if dryRun {
+				exists, err := s.client.Exists(ctx, target).Result()
+				if err != nil {
+					return renamed, skipped, fmt.Errorf("checking %s: %w", target, err)
+				}
+				if exists > 0 {
+					skipped++
+				} else {
+					renamed++
+				}
+				continue
+			}
+
+			ok, err := s.client.RenameNX(ctx, key, target).Result()
+			if err != nil {
+				return renamed, skipped, fmt.Errorf("renaming %s: %w", key, err)
+			}
+			if ok {
+				renamed++
+			} else {
+				s.logger.Warn("key already exists in target namespace, leaving source in place",
+					"key", key, "target", target)
+				skipped++
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return renamed, skipped, fmt.Errorf("scanning %s: %w", from+pattern, err)
+		}
+	}
+	return renamed, skipped, nil
+}
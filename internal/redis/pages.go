@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// pageCache describes the materialized windows that serve deep range reads.
+// A crawl walking start=100000, 100050, ... re-traverses the skiplist on every
+// request; copying a whole window once with ZRANGESTORE turns the following
+// requests into short reads of a small sorted set.
+type pageCache struct {
+	minStart int
+	size     int
+	ttl      time.Duration
+}
+
+// serves reports whether a range starting at start is read from the page cache
+func (p pageCache) serves(start int) bool {
+	return p.minStart > 0 && p.size > 0 && start >= p.minStart
+}
+
+// pageWindowScript returns ranks ARGV[3]..ARGV[4] of the window KEYS[2],
+// first copying ranks ARGV[1]..ARGV[2] of the leaderboard KEYS[1] into it when
+// the window is not materialized. Ranks count from the highest score when
+// ARGV[6] is 1 and from the lowest otherwise. New windows expire after ARGV[5]
+// milliseconds and are recorded in KEYS[3] so they can be dropped early.
+// Scores are returned as strings because Lua numbers are truncated in replies.
+var pageWindowScript = redis.NewScript(`
+local rev = ARGV[6] == '1'
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	local stored
+	if rev then
+		stored = redis.call('ZRANGESTORE', KEYS[2], KEYS[1], ARGV[1], ARGV[2], 'REV')
+	else
+		stored = redis.call('ZRANGESTORE', KEYS[2], KEYS[1], ARGV[1], ARGV[2])
+	end
+	if stored == 0 then
+		return {}
+	end
+	redis.call('PEXPIRE', KEYS[2], ARGV[5])
+	redis.call('SADD', KEYS[3], KEYS[2])
+	redis.call('PEXPIRE', KEYS[3], ARGV[5])
+end
+if rev then
+	return redis.call('ZRANGE', KEYS[2], ARGV[3], ARGV[4], 'REV', 'WITHSCORES')
+end
+return redis.call('ZRANGE', KEYS[2], ARGV[3], ARGV[4], 'WITHSCORES')
+`)
+
+// getCachedRange reads ranks start..end through the page cache, spanning as
+// many windows as the range touches. The result may lag the live leaderboard
+// by up to the page TTL. A board's sort order never changes, so its windows
+// are always materialized in that order.
+func (s *LeaderboardService) getCachedRange(ctx context.Context, leaderboardID string, start, end int, higherIsBetter bool) ([]redis.Z, error) {
+	size := s.pages.size
+	var results []redis.Z
+	for pos := start; pos <= end; {
+		window := pos / size
+		windowStart := window * size
+		stop := min(end, windowStart+size-1)
+
+		page, err := s.readPage(ctx, leaderboardID, window, pos-windowStart, stop-windowStart, higherIsBetter)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page...)
+		if len(page) < stop-pos+1 {
+			break // ran past the last rank
+		}
+		pos = stop + 1
+	}
+	return results, nil
+}
+
+// readPage returns offsets from..to of one window, materializing it if needed
+func (s *LeaderboardService) readPage(ctx context.Context, leaderboardID string, window, from, to int, higherIsBetter bool) ([]redis.Z, error) {
+	windowStart := window * s.pages.size
+	rev := 0
+	if higherIsBetter {
+		rev = 1
+	}
+	keys := []string{s.leaderboardKey(leaderboardID), s.pageKey(leaderboardID, window), s.pagesKey(leaderboardID)}
+	raw, err := pageWindowScript.Run(ctx, s.client, keys,
+		windowStart, windowStart+s.pages.size-1, from, to, s.pages.ttl.Milliseconds(), rev,
+	).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("reading page %d: %w", window, err)
+	}
+
+	results := make([]redis.Z, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		score, err := strconv.ParseFloat(raw[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing page score: %w", err)
+		}
+		results = append(results, redis.Z{Member: raw[i], Score: score})
+	}
+	return results, nil
+}
+
+// dropPages deletes every materialized window of a leaderboard, so a reset or
+// deleted board is not served from stale pages
+func (s *LeaderboardService) dropPages(ctx context.Context, leaderboardID string) error {
+	pagesKey := s.pagesKey(leaderboardID)
+	pages, err := s.client.SMembers(ctx, pagesKey).Result()
+	if err != nil {
+		return fmt.Errorf("listing cached pages: %w", err)
+	}
+	if err := s.client.Del(ctx, append(pages, pagesKey)...).Err(); err != nil {
+		return fmt.Errorf("dropping cached pages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// quotaKey returns the hash of per-player attempts used in the quota period
+// starting at periodStart, or the lifetime counter for a zero periodStart.
+// Periodic counters expire on their own; every counter of a leaderboard is
+// removed with it, see deleteQuotaKeys.
+func (s *LeaderboardService) quotaKey(leaderboardID string, periodStart time.Time) string {
+	var start int64
+	if !periodStart.IsZero() {
+		start = periodStart.Unix()
+	}
+	return s.namespace + fmt.Sprintf("leaderboard:%s:quota:%d", leaderboardID, start)
+}
+
+// deleteQuotaKeys removes a leaderboard's attempt counters for every period,
+// so a board recreated under the same ID starts with fresh quotas
+func (s *LeaderboardService) deleteQuotaKeys(ctx context.Context, leaderboardID string) error {
+	prefix := s.quotaKey(leaderboardID, time.Time{})
+	prefix = prefix[:len(prefix)-1]
+
+	var keys []string
+	iter := s.client.Scan(ctx, 0, escapeGlob(prefix)+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		// Skip keys of another board whose ID merely starts with this one's
+		if _, err := strconv.ParseInt(strings.TrimPrefix(iter.Val(), prefix), 10, 64); err == nil {
+			keys = append(keys, iter.Val())
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("listing quota counters: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := s.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("deleting quota counters: %w", err)
+	}
+	return nil
+}
+
+// escapeGlob escapes the characters SCAN's MATCH pattern treats specially
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// useAttemptScript counts one attempt for player ARGV[1] unless ARGV[2]
+// attempts are already used, and returns whether it was counted and the
+// attempts used. ARGV[3] is when the counter may expire, in unix
+// milliseconds, or empty to keep it.
+var useAttemptScript = redis.NewScript(`
+local used = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0')
+if used >= tonumber(ARGV[2]) then
+	return {0, used}
+end
+used = redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+if ARGV[3] ~= '' then
+	redis.call('PEXPIREAT', KEYS[1], ARGV[3])
+end
+return {1, used}
+`)
+
+// UseAttempt counts one of a player's quota attempts in the period starting
+// at periodStart and ending at periodEnd, both zero for a lifetime quota. It
+// returns whether an attempt was left and how many are used now.
+func (s *LeaderboardService) UseAttempt(ctx context.Context, leaderboardID, playerID string, quota int, periodStart, periodEnd time.Time) (bool, int, error) {
+	expireAt := ""
+	if !periodEnd.IsZero() {
+		// Kept a little past the period so a late request still finds it
+		expireAt = fmt.Sprint(periodEnd.Add(time.Hour).UnixMilli())
+	}
+	result, err := useAttemptScript.Run(ctx, s.client, []string{s.quotaKey(leaderboardID, periodStart)},
+		playerID, quota, expireAt,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("using attempt: %w", err)
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("using attempt: unexpected reply %v", result)
+	}
+	return result[0] == 1, int(result[1]), nil
+}
+
+// AttemptsUsed returns how many of a player's quota attempts are used in the
+// period starting at periodStart, without counting one
+func (s *LeaderboardService) AttemptsUsed(ctx context.Context, leaderboardID, playerID string, periodStart time.Time) (int, error) {
+	used, err := s.client.HGet(ctx, s.quotaKey(leaderboardID, periodStart), playerID).Int()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf("getting attempts used: %w", err)
+	}
+	return used, nil
+}
+
+// RefundAttempt gives back an attempt counted for a submission that then failed
+func (s *LeaderboardService) RefundAttempt(ctx context.Context, leaderboardID, playerID string, periodStart time.Time) error {
+	if err := s.client.HIncrBy(ctx, s.quotaKey(leaderboardID, periodStart), playerID, -1).Err(); err != nil {
+		return fmt.Errorf("refunding attempt: %w", err)
+	}
+	return nil
+}
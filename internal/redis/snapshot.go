@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// frozenTTL bounds how long a frozen copy outlives a freeze that never dropped it
+const frozenTTL = 10 * time.Minute
+
+// FrozenStandings is a private copy of a leaderboard's sorted set taken at one
+// moment, read in pages while the live board keeps changing
+type FrozenStandings struct {
+	key            string
+	higherIsBetter bool
+	Version        int64
+	Count          int64
+}
+
+// frozenKey returns the temporary copy of a leaderboard taken by a freeze
+func (s *LeaderboardService) frozenKey(leaderboardID string, at time.Time) string {
+	return s.namespace + fmt.Sprintf("leaderboard:%s:frozen:%d", leaderboardID, at.UnixNano())
+}
+
+// FreezeStandings copies a leaderboard's standings and reads its version in one
+// MULTI, so the copy is exactly the board at that version. The copy expires on
+// its own; callers should still release it with DropFrozen. The copy is read in
+// the board's sort order.
+func (s *LeaderboardService) FreezeStandings(ctx context.Context, leaderboardID string, higherIsBetter bool) (*FrozenStandings, error) {
+	frozen := &FrozenStandings{key: s.frozenKey(leaderboardID, time.Now()), higherIsBetter: higherIsBetter}
+
+	pipe := s.client.TxPipeline()
+	countCmd := pipe.ZRangeStore(ctx, frozen.key, redis.ZRangeArgs{
+		Key:   s.leaderboardKey(leaderboardID),
+		Start: 0,
+		Stop:  -1,
+	})
+	pipe.Expire(ctx, frozen.key, frozenTTL)
+	versionCmd := pipe.Get(ctx, s.versionKey(leaderboardID))
+	// A board that was never written has no version yet, which reads as redis.Nil
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("freezing standings: %w", err)
+	}
+	if err := countCmd.Err(); err != nil {
+		return nil, fmt.Errorf("freezing standings: %w", err)
+	}
+
+	frozen.Count = countCmd.Val()
+	frozen.Version, _ = versionCmd.Int64()
+	return frozen, nil
+}
+
+// ReadFrozen returns ranks start..stop (0-based, inclusive) of a frozen copy
+func (s *LeaderboardService) ReadFrozen(ctx context.Context, frozen *FrozenStandings, start, stop int64) ([]domain.LeaderboardEntry, error) {
+	results, err := rangeBestFirst(ctx, s.client, frozen.key, start, stop, frozen.higherIsBetter).Result()
+	if err != nil {
+		return nil, fmt.Errorf("reading frozen standings: %w", err)
+	}
+
+	entries := make([]domain.LeaderboardEntry, len(results))
+	for i, result := range results {
+		playerID := result.Member.(string)
+		entries[i] = domain.LeaderboardEntry{
+			Rank:     start + int64(i) + 1,
+			PlayerID: playerID,
+			Score:    decodeScore(result.Score),
+			IsGhost:  domain.IsGhostID(playerID),
+		}
+	}
+	return entries, nil
+}
+
+// DropFrozen releases a frozen copy
+func (s *LeaderboardService) DropFrozen(ctx context.Context, frozen *FrozenStandings) error {
+	if err := s.client.Del(ctx, frozen.key).Err(); err != nil {
+		return fmt.Errorf("dropping frozen standings: %w", err)
+	}
+	return nil
+}
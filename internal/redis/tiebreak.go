@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"math"
+	"strconv"
+)
+
+// storedScore is the sorted set score written for a score and its tie-break
+// component, a fraction below the whole score. A score too large for the
+// component to be kept exactly is stored whole.
+func storedScore(score int64, tie float64) float64 {
+	stored := float64(score) + tie
+	if math.Floor(stored) != float64(score) {
+		return float64(score)
+	}
+	return stored
+}
+
+// decodeScore reads a score back from a sorted set score, dropping any
+// tie-break component; whole scores are returned as they are
+func decodeScore(stored float64) int64 {
+	return int64(math.Floor(stored))
+}
+
+// higherScores is the inclusive lower bound of the sorted set scores above
+// score, whatever their tie-break component
+func higherScores(score int64) string {
+	return strconv.FormatInt(score+1, 10)
+}
+
+// lowerScores is the exclusive upper bound of the sorted set scores below score
+func lowerScores(score int64) string {
+	return "(" + strconv.FormatInt(score, 10)
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// lastTie is the largest tie-break component: the final of 2^20 slots
+const lastTie = float64(1<<20-1) / (1 << 20)
+
+func TestStoredScoreRoundTrip(t *testing.T) {
+	scores := []struct {
+		name  string
+		score int64
+	}{
+		{"zero", 0},
+		{"one", 1},
+		{"minus one", -1},
+		{"2^32", 1 << 32},
+		{"2^33 - 1", 1<<33 - 1},
+		{"-2^33", -(1 << 33)},
+		{"max safe", domain.MaxSafeScore},
+		{"min safe", -domain.MaxSafeScore},
+		{"max safe - 1", domain.MaxSafeScore - 1},
+		{"min safe + 1", -domain.MaxSafeScore + 1},
+	}
+	for _, tt := range scores {
+		for _, tie := range []float64{0, 0.5, lastTie} {
+			stored := storedScore(tt.score, tie)
+			if got := decodeScore(stored); got != tt.score {
+				t.Errorf("%s: decodeScore(storedScore(%d, %v)) = %d", tt.name, tt.score, tie, got)
+			}
+			if math.Floor(stored) != float64(tt.score) {
+				t.Errorf("%s: storedScore(%d, %v) = %v leaves the whole score", tt.name, tt.score, tie, stored)
+			}
+		}
+	}
+}
+
+func TestStoredScoreKeepsOrder(t *testing.T) {
+	for _, score := range []int64{0, -1, 1 << 32, -(1 << 32), domain.MaxSafeScore - 1, -domain.MaxSafeScore} {
+		best := storedScore(score, lastTie)
+		next := storedScore(score+1, 0)
+		if best >= next {
+			t.Errorf("storedScore(%d, %v) = %v, not below storedScore(%d, 0) = %v", score, lastTie, best, score+1, next)
+		}
+		if earlier, later := storedScore(score, 0), storedScore(score, 0.5); earlier > later {
+			t.Errorf("tie-break components of %d out of order: %v > %v", score, earlier, later)
+		}
+	}
+}
+
+func TestStoredScoreBeyondSafeRange(t *testing.T) {
+	// Scores past ±(2^53-1) are rejected before they reach Redis because
+	// float64 cannot hold them exactly; check that the round trip indeed fails
+	tests := []struct {
+		name  string
+		score int64
+	}{
+		{"2^53+1", 1<<53 + 1},
+		{"-(2^53+1)", -(1<<53 + 1)},
+	}
+	for _, tt := range tests {
+		if domain.IsSafeScore(tt.score) {
+			t.Errorf("%s: IsSafeScore(%d) = true", tt.name, tt.score)
+		}
+		if got := decodeScore(storedScore(tt.score, 0)); got == tt.score {
+			t.Errorf("%s: %d round-trips exactly; the safe range could be widened", tt.name, tt.score)
+		}
+	}
+	// 2^53 and MinInt64 are powers of two and round-trip, but they are still
+	// outside the window so that ±2^53 and ±(2^53+1) never collide. MaxInt64
+	// rounds to 2^63, which does not convert back to an int64 at all.
+	for _, score := range []int64{1 << 53, math.MinInt64, math.MaxInt64} {
+		if domain.IsSafeScore(score) {
+			t.Errorf("IsSafeScore(%d) = true", score)
+		}
+	}
+}
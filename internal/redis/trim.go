@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// trimBatch bounds the evictions of one script call, so a board far over its
+// cap does not block Redis while it is trimmed
+const trimBatch = 1000
+
+// trimScript evicts the worst players beyond the first ARGV[1] entries of a
+// leaderboard. ARGV[2] lists the board worst first (ZRANGE for boards where
+// higher is better, ZREVRANGE otherwise). Members starting with ARGV[3] (ghost
+// entries) are never evicted but still count toward the cap. At most ARGV[4]
+// players are evicted per call. Each evicted player is returned as player, old
+// rank (read with ARGV[5]), and score, followed by the leaderboard version.
+var trimScript = redis.NewScript(`
+local excess = math.min(redis.call('ZCARD', KEYS[1]) - tonumber(ARGV[1]), tonumber(ARGV[4]))
+local victims = {}
+local start = 0
+while excess > #victims do
+	local batch = redis.call(ARGV[2], KEYS[1], start, start + 99)
+	if #batch == 0 then
+		break
+	end
+	for i = 1, #batch do
+		if #victims < excess and string.sub(batch[i], 1, #ARGV[3]) ~= ARGV[3] then
+			table.insert(victims, batch[i])
+		end
+	end
+	start = start + 100
+end
+local removed = {}
+for _, player in ipairs(victims) do
+	local rank = redis.call(ARGV[5], KEYS[1], player)
+	local score = redis.call('ZSCORE', KEYS[1], player)
+	redis.call('ZREM', KEYS[1], player)
+	redis.call('HDEL', KEYS[2], player)
+	redis.call('HDEL', KEYS[3], player)
+	redis.call('HDEL', KEYS[4], player)
+	redis.call('HDEL', KEYS[5], player)
+	table.insert(removed, player)
+	table.insert(removed, rank + 1)
+	table.insert(removed, string.format('%.0f', math.floor(tonumber(score))))
+end
+local version
+if #removed > 0 then
+	version = redis.call('INCR', KEYS[6])
+else
+	version = tonumber(redis.call('GET', KEYS[6]) or '0')
+end
+table.insert(removed, version)
+return removed
+`)
+
+// TrimEntries caps a leaderboard at maxEntries by evicting its worst players.
+// Each batch of evictions is one step, so concurrent writes cannot slip in
+// between choosing and removing a player. Ghost entries are never evicted.
+// It returns the evicted players with the rank and score they held and the
+// leaderboard version after the last eviction.
+func (s *LeaderboardService) TrimEntries(ctx context.Context, leaderboardID string, maxEntries int, higherIsBetter bool) ([]domain.LeaderboardEntry, int64, error) {
+	worstFirst := "ZREVRANGE"
+	if higherIsBetter {
+		worstFirst = "ZRANGE"
+	}
+	keys := []string{s.leaderboardKey(leaderboardID), s.writesKey(leaderboardID), s.submissionsKey(leaderboardID),
+		s.proofsKey(leaderboardID), s.submittedKey(leaderboardID), s.versionKey(leaderboardID)}
+
+	var evicted []domain.LeaderboardEntry
+	var version int64
+	for {
+		result, err := trimScript.Run(ctx, s.client, keys, maxEntries, worstFirst, domain.GhostIDPrefix, trimBatch,
+			rankCommand(higherIsBetter)).Slice()
+		if err != nil {
+			return evicted, version, fmt.Errorf("trimming leaderboard: %w", err)
+		}
+		batch, v := parseRemoved(result)
+		evicted = append(evicted, batch...)
+		version = v
+		if len(batch) < trimBatch || ctx.Err() != nil {
+			return evicted, version, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// BoardView is a consistent snapshot of one leaderboard: its meta, counts,
+// player score bounds, and the best and worst entries in the board's sort
+// order. Members includes ghosts; Top and Bottom may include ghosts too.
+type BoardView struct {
+	// Meta is nil when the leaderboard has no meta hash in Redis
+	Meta    *domain.LeaderboardConfig
+	Version int64
+	Members int64
+	Ghosts  int64
+
+	// LastWrite is when a submission was last applied; nil when never
+	LastWrite *time.Time
+
+	// HighestScore and LowestScore ignore ghosts; both are nil when no player has a score
+	HighestScore *int64
+	LowestScore  *int64
+
+	Top    []domain.LeaderboardEntry
+	Bottom []domain.LeaderboardEntry
+}
+
+// GetBoardView reads a leaderboard's meta, version, counts, last write, score
+// bounds, and first and last n entries in a single MULTI round trip, so the
+// parts agree with each other. With n of zero the entries are skipped.
+func (s *LeaderboardService) GetBoardView(ctx context.Context, leaderboardID string, n int) (*BoardView, error) {
+	key := s.leaderboardKey(leaderboardID)
+
+	pipe := s.client.TxPipeline()
+	metaCmd := pipe.HGetAll(ctx, s.metaKey(leaderboardID))
+	versionCmd := pipe.Get(ctx, s.versionKey(leaderboardID))
+	membersCmd := pipe.ZCard(ctx, key)
+	ghostsCmd := pipe.HLen(ctx, s.ghostsKey(leaderboardID))
+	lastWriteCmd := pipe.Get(ctx, s.lastWriteKey(leaderboardID))
+	// Eval rather than Run: a NOSCRIPT from EVALSHA would only surface at Exec
+	highestCmd := firstScoreScript.Eval(ctx, pipe, []string{key}, "ZREVRANGE", domain.GhostIDPrefix)
+	lowestCmd := firstScoreScript.Eval(ctx, pipe, []string{key}, "ZRANGE", domain.GhostIDPrefix)
+	// The sort order is in the meta read by the same MULTI, so both ends are
+	// read and assigned once it is known
+	var highCmd, lowCmd *redis.ZSliceCmd
+	if n > 0 {
+		highCmd = pipe.ZRevRangeWithScores(ctx, key, 0, int64(n-1))
+		lowCmd = pipe.ZRangeWithScores(ctx, key, 0, int64(n-1))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("reading leaderboard view: %w", err)
+	}
+
+	view := &BoardView{
+		Members: membersCmd.Val(),
+		Ghosts:  ghostsCmd.Val(),
+	}
+	if meta := metaCmd.Val(); len(meta) > 0 {
+		view.Meta = parseLeaderboardMeta(meta)
+	}
+	if version, err := versionCmd.Int64(); err == nil {
+		view.Version = version
+	}
+	if ms, err := lastWriteCmd.Int64(); err == nil {
+		lastWrite := time.UnixMilli(ms)
+		view.LastWrite = &lastWrite
+	}
+
+	var err error
+	if view.HighestScore, err = scriptScore(highestCmd); err != nil {
+		return nil, fmt.Errorf("getting highest score: %w", err)
+	}
+	if view.LowestScore, err = scriptScore(lowestCmd); err != nil {
+		return nil, fmt.Errorf("getting lowest score: %w", err)
+	}
+
+	if n > 0 {
+		topCmd, bottomCmd := highCmd, lowCmd
+		if view.Meta != nil && !view.Meta.HigherIsBetter() {
+			topCmd, bottomCmd = lowCmd, highCmd
+		}
+		for i, z := range topCmd.Val() {
+			view.Top = append(view.Top, domain.LeaderboardEntry{
+				Rank:     int64(i + 1),
+				PlayerID: z.Member.(string),
+				Score:    decodeScore(z.Score),
+			})
+		}
+		// Bottom is listed best first, like every other ranking
+		bottom := bottomCmd.Val()
+		for i := len(bottom) - 1; i >= 0; i-- {
+			view.Bottom = append(view.Bottom, domain.LeaderboardEntry{
+				Rank:     view.Members - int64(i),
+				PlayerID: bottom[i].Member.(string),
+				Score:    decodeScore(bottom[i].Score),
+			})
+		}
+	}
+	return view, nil
+}
+
+// scriptScore decodes a firstScoreScript reply; nil means no player has a score
+func scriptScore(cmd *redis.Cmd) (*int64, error) {
+	result, err := cmd.Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	score, err := strconv.ParseFloat(result, 64)
+	if err != nil {
+		return nil, err
+	}
+	value := decodeScore(score)
+	return &value, nil
+}
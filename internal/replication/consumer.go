@@ -0,0 +1,184 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Applier applies changes replicated from the primary region
+type Applier interface {
+	ApplyReplicatedChanges(ctx context.Context, changes []domain.ReplicatedChange) domain.BatchResult
+}
+
+// Consumer applies changes from the replication topic on a replica region
+type Consumer struct {
+	config        *config.ReplicationConfig
+	applier       Applier
+	logger        *slog.Logger
+	consumerGroup sarama.ConsumerGroup
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewConsumer creates a replication consumer
+func NewConsumer(cfg *config.ReplicationConfig, applier Applier, logger *slog.Logger) (*Consumer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+	// A fresh replica must see every change still retained on the topic
+	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	saramaConfig.Consumer.Return.Errors = true
+
+	consumerGroup, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating replication consumer group: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Consumer{
+		config:        cfg,
+		applier:       applier,
+		logger:        logger,
+		consumerGroup: consumerGroup,
+		ctx:           ctx,
+		cancel:        cancel,
+	}, nil
+}
+
+// Start begins applying replicated changes
+func (c *Consumer) Start() error {
+	c.logger.Info("starting replication consumer",
+		"brokers", c.config.Brokers,
+		"topic", c.config.Topic,
+		"group_id", c.config.GroupID,
+	)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			if err := c.consumerGroup.Consume(c.ctx, []string{c.config.Topic}, &replicaHandler{consumer: c}); err != nil {
+				if err == sarama.ErrClosedConsumerGroup {
+					return
+				}
+				c.logger.Error("error from replication consumer", "error", err)
+			}
+			if c.ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case err, ok := <-c.consumerGroup.Errors():
+				if !ok {
+					return
+				}
+				c.logger.Error("replication consumer group error", "error", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop gracefully stops the consumer
+func (c *Consumer) Stop() error {
+	c.logger.Info("stopping replication consumer")
+	c.cancel()
+	c.wg.Wait()
+	return c.consumerGroup.Close()
+}
+
+// replicaHandler implements sarama.ConsumerGroupHandler
+type replicaHandler struct {
+	consumer *Consumer
+}
+
+// Setup is called at the beginning of a new session
+func (h *replicaHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup is called at the end of a session
+func (h *replicaHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim applies a partition's changes in order. Offsets are only
+// marked after a batch is applied, so a crash replays rather than skips
+// changes; replays are harmless because changes carry absolute scores.
+func (h *replicaHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	cfg := h.consumer.config
+	batch := make([]domain.ReplicatedChange, 0, cfg.BatchSize)
+	var last *sarama.ConsumerMessage
+	timer := time.NewTimer(cfg.FlushInterval)
+	defer timer.Stop()
+
+	apply := func() {
+		if len(batch) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			result := h.consumer.applier.ApplyReplicatedChanges(ctx, batch)
+			cancel()
+			h.consumer.logger.Debug("applied replicated changes",
+				"accepted", result.Accepted,
+				"failed", len(result.Failed),
+			)
+			batch = batch[:0]
+		}
+		if last != nil {
+			session.MarkMessage(last, "")
+			last = nil
+		}
+	}
+
+	for {
+		select {
+		case <-session.Context().Done():
+			apply()
+			return nil
+
+		case <-timer.C:
+			apply()
+			timer.Reset(cfg.FlushInterval)
+
+		case message, ok := <-claim.Messages():
+			if !ok {
+				apply()
+				return nil
+			}
+			last = message
+
+			var change domain.ReplicatedChange
+			if err := json.Unmarshal(message.Value, &change); err != nil {
+				h.consumer.logger.Warn("failed to unmarshal replicated change",
+					"error", err,
+					"offset", message.Offset,
+					"partition", message.Partition,
+				)
+				continue
+			}
+
+			batch = append(batch, change)
+			if len(batch) >= cfg.BatchSize {
+				apply()
+				timer.Reset(cfg.FlushInterval)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// sendAttempts is how many times a batch is offered to the transport before it is dropped
+const sendAttempts = 3
+
+// Transport delivers batches of applied changes to the secondary region
+type Transport interface {
+	Send(ctx context.Context, changes []domain.ReplicatedChange) error
+	Close() error
+}
+
+// Stats reports publisher throughput and loss
+type Stats struct {
+	Region    string `json:"region"`
+	Transport string `json:"transport"`
+	Queued    int    `json:"queued"`
+	Sent      int64  `json:"sent"`
+	Dropped   int64  `json:"dropped"`
+	Failed    int64  `json:"failed"`
+}
+
+// Publisher queues applied score changes and streams them to the secondary
+// region in batches. Publish never blocks the write path: when the queue is
+// full the change is dropped and counted.
+type Publisher struct {
+	config    *config.ReplicationConfig
+	transport Transport
+	logger    *slog.Logger
+	queue     chan domain.ReplicatedChange
+	sent      atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	mu        sync.Mutex
+	running   bool
+}
+
+// NewPublisher creates a publisher using the configured transport
+func NewPublisher(cfg *config.ReplicationConfig, logger *slog.Logger) (*Publisher, error) {
+	var transport Transport
+	var err error
+	switch cfg.Transport {
+	case config.ReplicationTransportKafka:
+		transport, err = NewKafkaTransport(cfg)
+	case config.ReplicationTransportHTTP:
+		transport, err = NewHTTPTransport(cfg)
+	default:
+		err = fmt.Errorf("unknown replication transport %q", cfg.Transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Publisher{
+		config:    cfg,
+		transport: transport,
+		logger:    logger,
+		queue:     make(chan domain.ReplicatedChange, cfg.QueueSize),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}, nil
+}
+
+// Publish enqueues an applied change for replication
+func (p *Publisher) Publish(change domain.ReplicatedChange) {
+	if change.Region == "" {
+		change.Region = p.config.Region
+	}
+
+	select {
+	case p.queue <- change:
+	default:
+		if p.dropped.Add(1) == 1 {
+			p.logger.Warn("replication queue full, dropping changes",
+				"queue_size", p.config.QueueSize,
+			)
+		}
+	}
+}
+
+// Stats returns the publisher's counters
+func (p *Publisher) Stats() Stats {
+	return Stats{
+		Region:    p.config.Region,
+		Transport: p.config.Transport,
+		Queued:    len(p.queue),
+		Sent:      p.sent.Load(),
+		Dropped:   p.dropped.Load(),
+		Failed:    p.failed.Load(),
+	}
+}
+
+// Start begins streaming queued changes
+func (p *Publisher) Start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = true
+	p.mu.Unlock()
+
+	p.logger.Info("replication publisher started",
+		"region", p.config.Region,
+		"transport", p.config.Transport,
+		"batch_size", p.config.BatchSize,
+		"flush_interval", p.config.FlushInterval,
+	)
+
+	go p.run(ctx)
+	return nil
+}
+
+// Stop flushes queued changes and closes the transport
+func (p *Publisher) Stop() error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return p.transport.Close()
+	}
+	p.mu.Unlock()
+
+	close(p.stopCh)
+	<-p.doneCh
+
+	p.mu.Lock()
+	p.running = false
+	p.mu.Unlock()
+
+	p.logger.Info("replication publisher stopped",
+		"sent", p.sent.Load(),
+		"dropped", p.dropped.Load(),
+		"failed", p.failed.Load(),
+	)
+	return p.transport.Close()
+}
+
+// run is the main publisher loop
+func (p *Publisher) run(ctx context.Context) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.ReplicatedChange, 0, p.config.BatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		p.send(ctx, batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.drain(batch)
+			return
+		case <-p.stopCh:
+			p.drain(batch)
+			return
+		case change := <-p.queue:
+			batch = append(batch, change)
+			if len(batch) >= p.config.BatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
+
+// drain sends the pending batch and whatever is still queued on shutdown
+func (p *Publisher) drain(batch []domain.ReplicatedChange) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for {
+		select {
+		case change := <-p.queue:
+			batch = append(batch, change)
+			if len(batch) >= p.config.BatchSize {
+				p.send(ctx, batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				p.send(ctx, batch)
+			}
+			return
+		}
+	}
+}
+
+// send delivers a batch, retrying transient failures before giving up on it
+func (p *Publisher) send(ctx context.Context, batch []domain.ReplicatedChange) {
+	var err error
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		if err = p.transport.Send(ctx, batch); err == nil {
+			p.sent.Add(int64(len(batch)))
+			return
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		p.logger.Warn("failed to send replication batch, retrying",
+			"attempt", attempt,
+			"count", len(batch),
+			"error", err,
+		)
+		time.Sleep(time.Duration(attempt) * p.config.FlushInterval)
+	}
+
+	p.failed.Add(int64(len(batch)))
+	p.logger.Error("dropping replication batch",
+		"count", len(batch),
+		"error", err,
+	)
+}
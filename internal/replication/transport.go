@@ -0,0 +1,119 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// TokenHeader carries the shared replication secret on HTTP apply requests
+const TokenHeader = "X-Replication-Token"
+
+// KafkaTransport publishes changes to a Kafka topic consumed by the replica region
+type KafkaTransport struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewKafkaTransport creates a Kafka transport for the configured replication topic
+func NewKafkaTransport(cfg *config.ReplicationConfig) (*KafkaTransport, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Partitioner = sarama.NewHashPartitioner
+
+	producer, err := sarama.NewSyncProducer(cfg.Brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating replication producer: %w", err)
+	}
+
+	return &KafkaTransport{producer: producer, topic: cfg.Topic}, nil
+}
+
+// Send publishes a batch of changes. Messages are keyed by leaderboard so
+// every change to a leaderboard, including resets, stays in order.
+func (t *KafkaTransport) Send(ctx context.Context, changes []domain.ReplicatedChange) error {
+	messages := make([]*sarama.ProducerMessage, 0, len(changes))
+	for _, change := range changes {
+		value, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("marshaling replicated change: %w", err)
+		}
+		messages = append(messages, &sarama.ProducerMessage{
+			Topic: t.topic,
+			Key:   sarama.StringEncoder(change.LeaderboardID),
+			Value: sarama.ByteEncoder(value),
+		})
+	}
+
+	if err := t.producer.SendMessages(messages); err != nil {
+		return fmt.Errorf("producing replicated changes: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying producer
+func (t *KafkaTransport) Close() error {
+	return t.producer.Close()
+}
+
+// HTTPTransport posts changes directly to the replica region's apply endpoint
+type HTTPTransport struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+// NewHTTPTransport creates an HTTP transport for the configured replica endpoint
+func NewHTTPTransport(cfg *config.ReplicationConfig) (*HTTPTransport, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("replication endpoint is required for the http transport")
+	}
+
+	return &HTTPTransport{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: cfg.Endpoint,
+		token:    cfg.Token,
+	}, nil
+}
+
+// Send posts a batch of changes; the replica applies them in order
+func (t *HTTPTransport) Send(ctx context.Context, changes []domain.ReplicatedChange) error {
+	body, err := json.Marshal(domain.ReplicationBatch{Changes: changes})
+	if err != nil {
+		return fmt.Errorf("marshaling replication batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating replication request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set(TokenHeader, t.token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting replication batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("replica responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close is a no-op for the HTTP transport
+func (t *HTTPTransport) Close() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// pseudonymPrefix marks pseudonymized player IDs so they are never mistaken for real ones
+const pseudonymPrefix = "anon_"
+
+// pseudonymizer maps player IDs to pseudonyms with an HMAC keyed by the
+// tenant's salt. A player always gets the same pseudonym under one salt, so
+// rows still join within and across exports, but real IDs cannot be recovered
+// or guessed without the salt.
+type pseudonymizer struct {
+	salt []byte
+}
+
+// pseudonym returns the stable pseudonym of a player ID
+func (p pseudonymizer) pseudonym(playerID string) string {
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(playerID))
+	return pseudonymPrefix + hex.EncodeToString(mac.Sum(nil)[:12])
+}
+
+// anonymizer returns the configured pseudonymizer, or an error when no salt is set
+func (s *LeaderboardService) anonymizer() (pseudonymizer, error) {
+	salt := s.config.Anonymization.Salt
+	if salt == "" {
+		return pseudonymizer{}, fmt.Errorf("%w: anonymization is not configured", domain.ErrInvalidRequest)
+	}
+	return pseudonymizer{salt: []byte(salt)}, nil
+}
+
+// AnonymizeEntries replaces the player IDs of ranking entries with pseudonyms
+// and drops usernames, avatars and proof links, which can identify a player.
+// Ghost entries are system-owned and keep their IDs.
+func (s *LeaderboardService) AnonymizeEntries(entries []domain.LeaderboardEntry) error {
+	p, err := s.anonymizer()
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].IsGhost {
+			continue
+		}
+		entries[i].PlayerID = p.pseudonym(entries[i].PlayerID)
+		entries[i].Username = ""
+		entries[i].AvatarURL = ""
+		entries[i].Proof = nil
+	}
+	return nil
+}
+
+// AnonymizeEvents replaces the player IDs of score events with pseudonyms and
+// drops their proof links. Metadata is passed through as submitted.
+func (s *LeaderboardService) AnonymizeEvents(events []domain.ScoreEvent) error {
+	p, err := s.anonymizer()
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		events[i].PlayerID = p.pseudonym(events[i].PlayerID)
+		events[i].Proof = nil
+	}
+	return nil
+}
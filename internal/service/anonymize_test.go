@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+func TestAnonymizeEntriesDropsProfileFields(t *testing.T) {
+	s := &LeaderboardService{config: &config.LeaderboardConfig{
+		Anonymization: config.AnonymizationConfig{Salt: "tenant-secret"},
+	}}
+
+	// As returned by a read after attachProfiles and include=proof
+	entries := []domain.LeaderboardEntry{
+		{
+			Rank:      1,
+			PlayerID:  "player1",
+			Score:     1500,
+			Username:  "Alice",
+			AvatarURL: "https://cdn.example.com/avatars/alice.png",
+			Proof:     &domain.ScoreProof{ReplayURL: "https://replays.example.com/alice/1", ProofRef: "run-1"},
+		},
+		{Rank: 2, PlayerID: "ghost:dev", Score: 1400, Username: "Dev time", IsGhost: true},
+	}
+	if err := s.AnonymizeEntries(entries); err != nil {
+		t.Fatalf("AnonymizeEntries: %v", err)
+	}
+
+	player := entries[0]
+	if player.PlayerID == "player1" || player.PlayerID == "" {
+		t.Errorf("player ID = %q, want a pseudonym", player.PlayerID)
+	}
+	if player.Username != "" || player.AvatarURL != "" || player.Proof != nil {
+		t.Errorf("identifying fields kept: username %q, avatar %q, proof %+v", player.Username, player.AvatarURL, player.Proof)
+	}
+	if player.Rank != 1 || player.Score != 1500 {
+		t.Errorf("rank and score = %d, %d, want 1, 1500", player.Rank, player.Score)
+	}
+	if ghost := entries[1]; ghost.PlayerID != "ghost:dev" || ghost.Username != "Dev time" {
+		t.Errorf("ghost entry changed: %+v", ghost)
+	}
+}
+
+func TestAnonymizeEntriesRequiresSalt(t *testing.T) {
+	s := &LeaderboardService{config: &config.LeaderboardConfig{}}
+	entries := []domain.LeaderboardEntry{{PlayerID: "player1", AvatarURL: "https://cdn.example.com/a.png"}}
+	if err := s.AnonymizeEntries(entries); err == nil {
+		t.Fatal("AnonymizeEntries without a salt succeeded")
+	}
+}
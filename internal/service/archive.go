@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// archiveSeason stores a leaderboard's standings as the final standings of the
+// season its scores belong to, just before a reset clears them. A season that
+// was already archived, as when a board is reset by hand mid-period, is stored
+// again under its name suffixed with the reset time. Empty boards are skipped.
+func (s *LeaderboardService) archiveSeason(ctx context.Context, lbConfig *domain.LeaderboardConfig) error {
+	frozen, err := s.redis.FreezeStandings(ctx, lbConfig.ID, lbConfig.HigherIsBetter())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := s.redis.DropFrozen(context.WithoutCancel(ctx), frozen); err != nil {
+			s.logger.Warn("failed to drop frozen standings", "leaderboard_id", lbConfig.ID, "error", err)
+		}
+	}()
+	if frozen.Count == 0 {
+		return nil
+	}
+
+	now := s.clock.Now()
+	schedule := s.schedule.For(lbConfig)
+	archive := domain.SeasonArchive{
+		LeaderboardID: lbConfig.ID,
+		ResetPeriod:   lbConfig.ResetPeriod,
+		Version:       frozen.Version,
+		PlayerCount:   frozen.Count,
+		ArchivedAt:    now,
+	}
+	var start time.Time
+	if _, periodic := schedule.PeriodStart(lbConfig.ResetPeriod, now); periodic {
+		start = schedule.ScoresPeriodStart(lbConfig)
+		archive.PeriodStart = &start
+	}
+	archive.Season = schedule.SeasonName(lbConfig.ResetPeriod, start, now)
+
+	stored, err := s.postgres.CreateArchive(ctx, archive, s.frozenPages(ctx, frozen))
+	if errors.Is(err, domain.ErrSeasonExists) && archive.PeriodStart != nil {
+		archive.Season += "-" + now.UTC().Format(snapshotNameLayout)
+		stored, err = s.postgres.CreateArchive(ctx, archive, s.frozenPages(ctx, frozen))
+	}
+	if err != nil {
+		return fmt.Errorf("archiving season: %w", err)
+	}
+
+	s.logger.Info("leaderboard season archived",
+		"leaderboard_id", lbConfig.ID,
+		"season", stored.Season,
+		"version", stored.Version,
+		"players", stored.PlayerCount,
+	)
+	return nil
+}
+
+// ListSeasons returns a leaderboard's archived seasons, newest first
+func (s *LeaderboardService) ListSeasons(ctx context.Context, leaderboardID string) ([]domain.SeasonArchive, error) {
+	return s.postgres.ListArchives(ctx, leaderboardID)
+}
+
+// GetSeason returns the final standings of an archived season
+func (s *LeaderboardService) GetSeason(ctx context.Context, leaderboardID, season string) (*domain.SeasonArchive, error) {
+	return s.postgres.GetArchive(ctx, leaderboardID, season)
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/websocket"
+)
+
+// scoreChange describes the effect of a submission on a player's standing
+type scoreChange struct {
+	leaderboardID string
+	playerID      string
+	config        *domain.LeaderboardConfig
+	oldRank       int64 // 0 when the player was not ranked or the rank was not captured
+	oldScore      int64
+	newScore      int64
+	changed       bool
+	version       int64         // leaderboard version at which the submission is visible
+	pendingID     int64         // set when the submission was held for review instead of applied
+	duplicate     bool          // set when the submission's idempotency key was already applied
+	ignored       bool          // set when the submission was beyond the player's attempt quota and dropped
+	attemptsLeft  *int          // attempts left in the quota period; nil on boards without a quota
+	derived       []scoreChange // changes the submission made on derived leaderboards
+}
+
+// broadcastThrottle limits how often leaderboard_update messages are sent per leaderboard.
+// Updates suppressed inside the window are collapsed into one trailing broadcast.
+type broadcastThrottle struct {
+	mu      sync.Mutex
+	last    map[string]time.Time
+	pending map[string]bool
+}
+
+// newBroadcastThrottle creates an empty throttle
+func newBroadcastThrottle() *broadcastThrottle {
+	return &broadcastThrottle{
+		last:    make(map[string]time.Time),
+		pending: make(map[string]bool),
+	}
+}
+
+// allow reports whether a broadcast may be sent now. When it may not, fire is
+// scheduled once for the end of the window.
+func (t *broadcastThrottle) allow(leaderboardID string, interval time.Duration, fire func()) bool {
+	if interval <= 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(t.last[leaderboardID])
+	if elapsed >= interval {
+		t.last[leaderboardID] = now
+		return true
+	}
+
+	if !t.pending[leaderboardID] {
+		t.pending[leaderboardID] = true
+		time.AfterFunc(interval-elapsed, func() {
+			t.mu.Lock()
+			t.pending[leaderboardID] = false
+			t.last[leaderboardID] = time.Now()
+			t.mu.Unlock()
+			fire()
+		})
+	}
+	return false
+}
+
+// hasSubscribers reports whether any WebSocket client is subscribed to a leaderboard
+func (s *LeaderboardService) hasSubscribers(leaderboardID string) bool {
+	return s.hub != nil && s.hub.GetSubscriberCount(leaderboardID) > 0
+}
+
+// broadcastUpdate broadcasts leaderboard update to WebSocket clients
+func (s *LeaderboardService) broadcastUpdate(ctx context.Context, leaderboardID string) {
+	if s.hub == nil {
+		return
+	}
+
+	if message := s.leaderboardUpdateMessage(ctx, leaderboardID); message != nil {
+		s.hub.BroadcastBatch([]*websocket.Message{message})
+	}
+}
+
+// broadcastRemoval tells subscribers a player was removed and sends the adjusted
+// snapshot in the same hub pass, so client caches drop the player at once
+func (s *LeaderboardService) broadcastRemoval(ctx context.Context, leaderboardID, playerID string, old *domain.LeaderboardEntry) {
+	if s.hub == nil {
+		return
+	}
+
+	var messages []*websocket.Message
+	if message := s.leaderboardUpdateMessage(ctx, leaderboardID); message != nil {
+		messages = append(messages, message)
+	}
+	messages = append(messages, playerRemovedMessage(leaderboardID, playerID, old))
+	s.hub.BroadcastBatch(messages)
+}
+
+// broadcastRemovals sends one player_removed message per removed player, with
+// the standing they held, and the adjusted snapshot in a single hub pass
+func (s *LeaderboardService) broadcastRemovals(ctx context.Context, leaderboardID string, removed []domain.LeaderboardEntry) {
+	if s.hub == nil {
+		return
+	}
+
+	messages := make([]*websocket.Message, 0, len(removed)+1)
+	if message := s.leaderboardUpdateMessage(ctx, leaderboardID); message != nil {
+		messages = append(messages, message)
+	}
+	for i := range removed {
+		messages = append(messages, playerRemovedMessage(leaderboardID, removed[i].PlayerID, &removed[i]))
+	}
+	s.hub.BroadcastBatch(messages)
+}
+
+// playerRemovedMessage builds a player_removed message; old is the player's
+// standing before the removal, or nil when it was not captured
+func playerRemovedMessage(leaderboardID, playerID string, old *domain.LeaderboardEntry) *websocket.Message {
+	removed := websocket.PlayerRemoved{
+		LeaderboardID: leaderboardID,
+		PlayerID:      playerID,
+	}
+	if old != nil {
+		removed.OldRank = old.Rank
+		removed.OldScore = old.Score
+	}
+	return websocket.NewPlayerRemovedMessage(removed)
+}
+
+// removalStanding captures a player's standing before removal when someone is
+// listening for it. A player who is not ranked yields nil.
+func (s *LeaderboardService) removalStanding(ctx context.Context, leaderboardID, playerID string) *domain.LeaderboardEntry {
+	if !s.hasSubscribers(leaderboardID) {
+		return nil
+	}
+	meta, err := s.readMeta(ctx, leaderboardID)
+	if err != nil {
+		s.logger.Warn("failed to get leaderboard meta", "error", err)
+		return nil
+	}
+	old, err := s.redis.GetPlayerRank(ctx, leaderboardID, playerID, meta.HigherIsBetter())
+	if err != nil && !errors.Is(err, domain.ErrPlayerNotFound) {
+		s.logger.Warn("failed to get old rank", "error", err)
+	}
+	return old
+}
+
+// leaderboardUpdateMessage builds the leaderboard_update message for a leaderboard
+func (s *LeaderboardService) leaderboardUpdateMessage(ctx context.Context, leaderboardID string) *websocket.Message {
+	// Get only top 10 entries for broadcast (efficient for large leaderboards)
+	entries, err := s.GetTopN(ctx, leaderboardID, 10)
+	if err != nil {
+		s.logger.Warn("failed to get entries for broadcast", "error", err)
+		return nil
+	}
+
+	count, _ := s.GetCount(ctx, leaderboardID)
+	return websocket.NewLeaderboardUpdateMessage(leaderboardID, entries, count)
+}
+
+// broadcastChanges sends leaderboard updates and player updates as one hub batch.
+// Leaderboards with a significance threshold only broadcast when at least one
+// change was significant, and leaderboard updates respect the update throttle.
+func (s *LeaderboardService) broadcastChanges(ctx context.Context, leaderboardIDs []string, changes []scoreChange) {
+	if s.hub == nil {
+		return
+	}
+	leaderboardIDs, changes = withDerived(leaderboardIDs, changes)
+
+	configs := make(map[string]*domain.LeaderboardConfig)
+	significant := make(map[string]bool)
+	var playerMessages []*websocket.Message
+	for _, change := range coalesceChanges(changes) {
+		configs[change.leaderboardID] = change.config
+		message, isSignificant := s.playerChangeMessage(ctx, change)
+		if isSignificant {
+			significant[change.leaderboardID] = true
+		}
+		if message != nil {
+			playerMessages = append(playerMessages, message)
+		}
+	}
+
+	messages := make([]*websocket.Message, 0, len(leaderboardIDs)+len(playerMessages))
+	for _, leaderboardID := range leaderboardIDs {
+		config := configs[leaderboardID]
+		if config != nil && config.HasSignificanceThreshold() && !significant[leaderboardID] {
+			continue
+		}
+		if config != nil {
+			id := leaderboardID
+			interval := time.Duration(config.UpdateThrottleMs) * time.Millisecond
+			if !s.throttle.allow(id, interval, func() { s.broadcastUpdate(context.Background(), id) }) {
+				continue
+			}
+		}
+		if message := s.leaderboardUpdateMessage(ctx, leaderboardID); message != nil {
+			messages = append(messages, message)
+		}
+	}
+	messages = append(messages, playerMessages...)
+	messages = append(messages, s.followerMessages(ctx, leaderboardIDs, configs, changes)...)
+
+	s.hub.BroadcastBatch(messages)
+}
+
+// coalesceChanges collapses repeated changes for the same player into one,
+// keeping the latest score and the earliest old standing
+func coalesceChanges(changes []scoreChange) []scoreChange {
+	type playerKey struct{ leaderboardID, playerID string }
+
+	index := make(map[playerKey]int, len(changes))
+	result := make([]scoreChange, 0, len(changes))
+	for _, change := range changes {
+		key := playerKey{change.leaderboardID, change.playerID}
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(result)
+			result = append(result, change)
+			continue
+		}
+		// An unchanged submission carries no reliable score, so keep the earlier one
+		if !change.changed {
+			continue
+		}
+		oldRank, oldScore := result[i].oldRank, result[i].oldScore
+		result[i] = change
+		result[i].oldRank = oldRank
+		result[i].oldScore = oldScore
+	}
+	return result
+}
+
+// playerChangeMessage builds a player_update message with the player's new standing.
+// It returns a nil message when nothing changed, nobody is listening, or the change
+// falls below the leaderboard's significance threshold, and reports whether the
+// change was significant.
+func (s *LeaderboardService) playerChangeMessage(ctx context.Context, change scoreChange) (*websocket.Message, bool) {
+	if !change.changed || !s.hasSubscribers(change.leaderboardID) {
+		return nil, false
+	}
+
+	standing, err := s.redis.GetPlayerStanding(ctx, change.leaderboardID, change.playerID, change.config.HigherIsBetter())
+	if err != nil {
+		s.logger.Warn("failed to get player standing for broadcast", "error", err)
+		return nil, false
+	}
+
+	if !isSignificant(change, standing.Entry.Rank) {
+		return nil, false
+	}
+
+	return websocket.NewPlayerUpdateMessage(websocket.PlayerUpdate{
+		LeaderboardID: change.leaderboardID,
+		PlayerID:      change.playerID,
+		Score:         standing.Entry.Score,
+		Rank:          standing.Entry.Rank,
+		OldRank:       change.oldRank,
+		Above:         standing.Above,
+		Below:         standing.Below,
+	}), true
+}
+
+// isSignificant reports whether a change moves the player by at least the
+// leaderboard's minimum rank or score delta. New players are always significant.
+func isSignificant(change scoreChange, newRank int64) bool {
+	if change.config == nil || !change.config.HasSignificanceThreshold() || change.oldRank == 0 {
+		return true
+	}
+
+	if threshold := change.config.MinRankChange; threshold > 0 && abs(newRank-change.oldRank) >= threshold {
+		return true
+	}
+	if threshold := change.config.MinScoreChange; threshold > 0 && abs(change.newScore-change.oldScore) >= threshold {
+		return true
+	}
+	return false
+}
+
+// abs returns the absolute value of n
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
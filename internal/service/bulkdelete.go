@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+const (
+	// bulkDeleteBatch is how many players one removal step deletes
+	bulkDeleteBatch = 500
+	// maxBulkDeleteJobs caps the jobs kept for status queries; the oldest
+	// finished ones are dropped first
+	maxBulkDeleteJobs = 100
+)
+
+// bulkDeleteJobs holds the bulk delete jobs started on this instance
+type bulkDeleteJobs struct {
+	mu    sync.Mutex
+	jobs  map[string]*domain.BulkDeleteJob
+	order []string
+}
+
+func newBulkDeleteJobs() *bulkDeleteJobs {
+	return &bulkDeleteJobs{jobs: make(map[string]*domain.BulkDeleteJob)}
+}
+
+// add registers a job, dropping the oldest finished job when full
+func (j *bulkDeleteJobs) add(job *domain.BulkDeleteJob) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.order) >= maxBulkDeleteJobs {
+		for i, id := range j.order {
+			if j.jobs[id].Status != domain.BulkDeleteRunning {
+				delete(j.jobs, id)
+				j.order = append(j.order[:i], j.order[i+1:]...)
+				break
+			}
+		}
+	}
+	j.jobs[job.ID] = job
+	j.order = append(j.order, job.ID)
+}
+
+// update applies fn to a job under the lock
+func (j *bulkDeleteJobs) update(id string, fn func(job *domain.BulkDeleteJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if job, ok := j.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// get returns a copy of a job
+func (j *bulkDeleteJobs) get(id string) (domain.BulkDeleteJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return domain.BulkDeleteJob{}, false
+	}
+	return *job, true
+}
+
+// list returns copies of every job, newest first
+func (j *bulkDeleteJobs) list() []domain.BulkDeleteJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	jobs := make([]domain.BulkDeleteJob, 0, len(j.order))
+	for i := len(j.order) - 1; i >= 0; i-- {
+		jobs = append(jobs, *j.jobs[j.order[i]])
+	}
+	return jobs
+}
+
+// PreviewBulkDelete reports which players a bulk delete would remove without
+// removing them
+func (s *LeaderboardService) PreviewBulkDelete(ctx context.Context, leaderboardID string, req domain.BulkDeleteRequest) (*domain.BulkDeletePreview, error) {
+	lbConfig, err := s.bulkDeleteTarget(ctx, leaderboardID, req)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := s.bulkDeleteMatches(ctx, lbConfig, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := matched
+	if len(sample) > domain.BulkDeletePreviewIDs {
+		sample = sample[:domain.BulkDeletePreviewIDs]
+	}
+	return &domain.BulkDeletePreview{
+		LeaderboardID: leaderboardID,
+		Matched:       len(matched),
+		Sample:        sample,
+	}, nil
+}
+
+// StartBulkDelete starts removing the players a request matches in the
+// background and returns the job tracking it. Players are removed in batches;
+// each removal is replicated and broadcast like a manual one.
+func (s *LeaderboardService) StartBulkDelete(ctx context.Context, leaderboardID string, req domain.BulkDeleteRequest) (*domain.BulkDeleteJob, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+	lbConfig, err := s.bulkDeleteTarget(ctx, leaderboardID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("generating job id: %w", err)
+	}
+	job := &domain.BulkDeleteJob{
+		ID:            hex.EncodeToString(id),
+		LeaderboardID: leaderboardID,
+		Request:       req,
+		Status:        domain.BulkDeleteRunning,
+		StartedAt:     s.clock.Now(),
+	}
+	s.bulkDeletes.add(job)
+	started := *job
+
+	// The job outlives the request that started it
+	go s.runBulkDelete(context.Background(), lbConfig, job.ID, req)
+	return &started, nil
+}
+
+// BulkDeleteJob returns a bulk delete job started on this instance
+func (s *LeaderboardService) BulkDeleteJob(id string) (*domain.BulkDeleteJob, error) {
+	job, ok := s.bulkDeletes.get(id)
+	if !ok {
+		return nil, domain.NewNotFoundError(domain.ResourceJob, id)
+	}
+	return &job, nil
+}
+
+// ListBulkDeleteJobs returns the bulk delete jobs started on this instance, newest first
+func (s *LeaderboardService) ListBulkDeleteJobs() []domain.BulkDeleteJob {
+	return s.bulkDeletes.list()
+}
+
+// bulkDeleteTarget validates a request and returns the leaderboard it targets
+func (s *LeaderboardService) bulkDeleteTarget(ctx context.Context, leaderboardID string, req domain.BulkDeleteRequest) (*domain.LeaderboardConfig, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.GetLeaderboard(ctx, leaderboardID)
+}
+
+// bulkDeleteMatches resolves a request to the ranked players it names, sorted
+func (s *LeaderboardService) bulkDeleteMatches(ctx context.Context, lbConfig *domain.LeaderboardConfig, req domain.BulkDeleteRequest) ([]string, error) {
+	if len(req.PlayerIDs) > 0 {
+		return s.sortedRanked(ctx, lbConfig.ID, req.PlayerIDs)
+	}
+
+	var flagged []string
+	if req.Filter.Flagged {
+		var err error
+		flagged, err = s.postgres.PlayersWithEvent(ctx, lbConfig.ID, suspiciousEventType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Filter.ScoreAbove == nil {
+		return s.sortedRanked(ctx, lbConfig.ID, flagged)
+	}
+
+	matched, err := s.redis.PlayersScoredAbove(ctx, lbConfig.ID, *req.Filter.ScoreAbove)
+	if err != nil {
+		return nil, err
+	}
+	if req.Filter.Flagged {
+		matched = intersect(matched, flagged)
+	}
+	sort.Strings(matched)
+	return matched, nil
+}
+
+// sortedRanked returns the given players that are ranked on a leaderboard,
+// sorted and without repeats
+func (s *LeaderboardService) sortedRanked(ctx context.Context, leaderboardID string, playerIDs []string) ([]string, error) {
+	ranked, err := s.redis.RankedPlayers(ctx, leaderboardID, playerIDs)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ranked)
+	return dedupe(ranked), nil
+}
+
+// runBulkDelete resolves and removes a job's players, recording progress on the job
+func (s *LeaderboardService) runBulkDelete(ctx context.Context, lbConfig *domain.LeaderboardConfig, jobID string, req domain.BulkDeleteRequest) {
+	finish := func(err error) {
+		now := s.clock.Now()
+		s.bulkDeletes.update(jobID, func(job *domain.BulkDeleteJob) {
+			job.FinishedAt = &now
+			job.Status = domain.BulkDeleteCompleted
+			if err != nil {
+				job.Status = domain.BulkDeleteFailed
+				job.Error = err.Error()
+			}
+		})
+	}
+
+	matched, err := s.bulkDeleteMatches(ctx, lbConfig, req)
+	if err != nil {
+		s.logger.Error("failed to resolve bulk delete", "job_id", jobID, "leaderboard_id", lbConfig.ID, "error", err)
+		finish(err)
+		return
+	}
+	s.bulkDeletes.update(jobID, func(job *domain.BulkDeleteJob) {
+		job.Matched = len(matched)
+	})
+
+	total := 0
+	for start := 0; start < len(matched); start += bulkDeleteBatch {
+		batch := matched[start:min(start+bulkDeleteBatch, len(matched))]
+		removed, err := s.removePlayerBatch(ctx, lbConfig, batch)
+		total += removed
+		s.bulkDeletes.update(jobID, func(job *domain.BulkDeleteJob) {
+			job.Removed = total
+		})
+		if err != nil {
+			s.logger.Error("bulk delete failed", "job_id", jobID, "leaderboard_id", lbConfig.ID, "removed", total, "error", err)
+			finish(err)
+			return
+		}
+	}
+
+	s.logger.Info("bulk delete completed", "job_id", jobID, "leaderboard_id", lbConfig.ID, "removed", total)
+	finish(nil)
+}
+
+// removePlayerBatch removes one batch of players from Redis and PostgreSQL and
+// announces the removals. It returns how many players were ranked.
+func (s *LeaderboardService) removePlayerBatch(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerIDs []string) (int, error) {
+	removed, version, err := s.redis.RemovePlayers(ctx, lbConfig.ID, playerIDs, lbConfig.HigherIsBetter())
+	if err != nil {
+		return 0, err
+	}
+
+	// Stored rows are removed even for players Redis had already lost
+	if _, err := s.postgres.RemovePlayers(ctx, lbConfig.ID, playerIDs); err != nil {
+		s.logger.Warn("failed to remove bulk deleted players from postgres", "leaderboard_id", lbConfig.ID, "error", err)
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	for _, entry := range removed {
+		s.replicate(domain.ReplicatedChange{
+			Op:            domain.ReplicationOpRemove,
+			LeaderboardID: lbConfig.ID,
+			PlayerID:      entry.PlayerID,
+			Version:       version,
+		})
+	}
+	s.stats.invalidate(lbConfig.ID)
+	s.broadcastRemovals(ctx, lbConfig.ID, removed)
+	s.purgeIfTopRemoved(lbConfig.ID, removed)
+	return len(removed), nil
+}
+
+// intersect returns the members of a that are also in b, in a's order
+func intersect(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, id := range b {
+		in[id] = true
+	}
+	var both []string
+	for _, id := range a {
+		if in[id] {
+			both = append(both, id)
+		}
+	}
+	return both
+}
+
+// dedupe drops repeated entries from a sorted list
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, id := range sorted {
+		if i == 0 || id != sorted[i-1] {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// suspiciousEventType marks score events flagged for anti-cheat review
+const suspiciousEventType = "suspicious"
+
+// recordClamped logs an increment whose total was clamped and records it as a
+// suspicious score event. The event bypasses sampling so none are lost.
+func (s *LeaderboardService) recordClamped(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string, delta, stored int64) {
+	s.logger.Warn("clamped increment submission",
+		"leaderboard_id", lbConfig.ID,
+		"player_id", playerID,
+		"delta", delta,
+		"stored_score", stored,
+	)
+
+	event := domain.ScoreEvent{
+		PlayerID:      playerID,
+		LeaderboardID: lbConfig.ID,
+		Score:         stored,
+		EventType:     suspiciousEventType,
+		Timestamp:     s.clock.Now(),
+		Metadata: map[string]interface{}{
+			"reason":          "clamped",
+			"requested_delta": delta,
+		},
+	}
+	if err := s.postgres.RecordEvent(ctx, event); err != nil {
+		s.logger.Warn("failed to record suspicious event", "leaderboard_id", lbConfig.ID, "player_id", playerID, "error", err)
+	}
+}
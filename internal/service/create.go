@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// replayCreate answers a create for an ID that is already taken. A retry of
+// the same definition gets the stored leaderboard back, so clients can retry
+// creates that timed out; its Redis metadata is rewritten in case an earlier
+// attempt stopped short. Any other definition is a conflict.
+func (s *LeaderboardService) replayCreate(ctx context.Context, req domain.CreateLeaderboardRequest, config domain.LeaderboardConfig) (*domain.LeaderboardConfig, error) {
+	existing, err := s.postgres.GetLeaderboard(ctx, config.ID)
+	if errors.Is(err, domain.ErrLeaderboardNotFound) {
+		return nil, domain.ErrLeaderboardExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	same, err := s.sameDefinition(ctx, config, req.Script, *existing)
+	if err != nil {
+		return nil, err
+	}
+	if !same {
+		return nil, domain.ErrLeaderboardExists
+	}
+
+	if err := s.redis.SetLeaderboardMeta(ctx, *existing); err != nil {
+		s.logger.Warn("failed to store leaderboard meta in redis", "leaderboard_id", existing.ID, "error", err)
+	}
+	s.logger.Debug("replayed leaderboard create", "leaderboard_id", existing.ID)
+	return existing, nil
+}
+
+// sameDefinition reports whether a requested board, with its script source,
+// matches a stored one, ignoring what the store fills in itself
+func (s *LeaderboardService) sameDefinition(ctx context.Context, requested domain.LeaderboardConfig, script string, stored domain.LeaderboardConfig) (bool, error) {
+	if (script == "") != (stored.ScriptVersion == 0) {
+		return false, nil
+	}
+	if script != "" {
+		active, err := s.postgres.GetScript(ctx, stored.ID, stored.ScriptVersion)
+		if err != nil {
+			return false, err
+		}
+		if active.Source != script {
+			return false, nil
+		}
+	}
+
+	a, err := json.Marshal(definition(requested))
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(definition(stored))
+	if err != nil {
+		return false, err
+	}
+	return string(a) == string(b), nil
+}
+
+// definition strips a config down to what a create request sets, with times
+// at the precision and zone PostgreSQL stores them in
+func definition(config domain.LeaderboardConfig) domain.LeaderboardConfig {
+	config.ScriptVersion = 0
+	config.LastResetAt = nil
+	config.NextResetAt = nil
+	config.CreatedAt = time.Time{}
+	config.UpdatedAt = time.Time{}
+	config.OpenAt = storedTime(config.OpenAt)
+	config.CloseAt = storedTime(config.CloseAt)
+	return config
+}
+
+// storedTime rounds an optional time the way a TIMESTAMP column keeps it
+func storedTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	stored := t.UTC().Truncate(time.Microsecond)
+	return &stored
+}
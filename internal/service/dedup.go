@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// DedupStats counts submissions checked against their idempotency key and the
+// duplicates suppressed, per source
+type DedupStats struct {
+	Checked    int64            `json:"checked"`
+	Suppressed map[string]int64 `json:"suppressed"`
+}
+
+// dedupCounters accumulates DedupStats
+type dedupCounters struct {
+	mu         sync.Mutex
+	checked    int64
+	suppressed map[string]int64
+}
+
+// newDedupCounters creates empty counters
+func newDedupCounters() *dedupCounters {
+	return &dedupCounters{suppressed: make(map[string]int64)}
+}
+
+// record counts one checked submission and whether it was a duplicate
+func (c *dedupCounters) record(source string, duplicate bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checked++
+	if duplicate {
+		c.suppressed[source]++
+	}
+}
+
+// DedupStats returns the submission dedup counters since startup
+func (s *LeaderboardService) DedupStats() DedupStats {
+	s.dedup.mu.Lock()
+	defer s.dedup.mu.Unlock()
+	stats := DedupStats{
+		Checked:    s.dedup.checked,
+		Suppressed: make(map[string]int64, len(s.dedup.suppressed)),
+	}
+	for source, count := range s.dedup.suppressed {
+		stats.Suppressed[source] = count
+	}
+	return stats
+}
+
+// claimSubmission reports whether a submission should be applied, claiming its
+// idempotency key so a copy arriving on another path is skipped. Submissions
+// without a key are always applied, and Redis errors fail open.
+func (s *LeaderboardService) claimSubmission(ctx context.Context, submission domain.ScoreSubmission) bool {
+	if submission.IdempotencyKey == "" {
+		return true
+	}
+
+	claimed, err := s.redis.ClaimSubmission(ctx, submission.LeaderboardID, submission.IdempotencyKey, s.config.DedupTTL)
+	if err != nil {
+		s.logger.Warn("failed to check idempotency key, applying submission",
+			"leaderboard_id", submission.LeaderboardID,
+			"idempotency_key", submission.IdempotencyKey,
+			"error", err,
+		)
+		return true
+	}
+
+	source := domain.SubmissionSource(ctx)
+	s.dedup.record(source, !claimed)
+	if !claimed {
+		s.logger.Debug("suppressed duplicate submission",
+			"leaderboard_id", submission.LeaderboardID,
+			"player_id", submission.PlayerID,
+			"idempotency_key", submission.IdempotencyKey,
+			"source", source,
+		)
+	}
+	return claimed
+}
+
+// releaseSubmission frees the idempotency key of a submission that failed to
+// apply, so a retry on either path is not mistaken for a duplicate
+func (s *LeaderboardService) releaseSubmission(ctx context.Context, submission domain.ScoreSubmission) {
+	if submission.IdempotencyKey == "" {
+		return
+	}
+	if err := s.redis.ReleaseSubmission(ctx, submission.LeaderboardID, submission.IdempotencyKey); err != nil {
+		s.logger.Warn("failed to release idempotency key",
+			"leaderboard_id", submission.LeaderboardID,
+			"idempotency_key", submission.IdempotencyKey,
+			"error", err,
+		)
+	}
+}
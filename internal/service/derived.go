@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/filter"
+)
+
+// derivedBoard is a derived leaderboard with its compiled filter
+type derivedBoard struct {
+	config domain.LeaderboardConfig
+	filter *filter.Filter
+}
+
+// derivedSet is the cached list of boards derived from one parent
+type derivedSet struct {
+	boards   []derivedBoard
+	loadedAt time.Time
+}
+
+// derivedCache holds each parent's derived boards for the configured refresh
+// interval, so fan-out does not query PostgreSQL on every submission
+type derivedCache struct {
+	mu       sync.Mutex
+	byParent map[string]derivedSet
+}
+
+func newDerivedCache() *derivedCache {
+	return &derivedCache{byParent: make(map[string]derivedSet)}
+}
+
+// invalidate drops a parent's cached derived boards
+func (c *derivedCache) invalidate(parentID string) {
+	c.mu.Lock()
+	delete(c.byParent, parentID)
+	c.mu.Unlock()
+}
+
+// clear drops every cached set, e.g. after a board that may be a parent or a
+// derived board is deleted
+func (c *derivedCache) clear() {
+	c.mu.Lock()
+	c.byParent = make(map[string]derivedSet)
+	c.mu.Unlock()
+}
+
+// derivedBoards returns the boards derived from a parent, loading them when the cache is stale
+func (s *LeaderboardService) derivedBoards(ctx context.Context, parentID string) ([]derivedBoard, error) {
+	now := s.clock.Now()
+	s.derived.mu.Lock()
+	set, ok := s.derived.byParent[parentID]
+	s.derived.mu.Unlock()
+	if ok && now.Sub(set.loadedAt) < s.config.DerivedRefresh {
+		return set.boards, nil
+	}
+
+	configs, err := s.postgres.ListDerivedLeaderboards(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]derivedBoard, 0, len(configs))
+	for _, config := range configs {
+		compiled, err := filter.Compile(config.Filter)
+		if err != nil {
+			s.logger.Warn("skipping derived leaderboard with invalid filter",
+				"leaderboard_id", config.ID,
+				"parent_id", parentID,
+				"error", err,
+			)
+			continue
+		}
+		boards = append(boards, derivedBoard{config: config, filter: compiled})
+	}
+
+	s.derived.mu.Lock()
+	s.derived.byParent[parentID] = derivedSet{boards: boards, loadedAt: now}
+	s.derived.mu.Unlock()
+	return boards, nil
+}
+
+// fanOut applies a submission accepted by a parent board to every derived board
+// whose filter matches it. Each derived board uses its own config; failures are
+// logged and never affect the parent submission.
+func (s *LeaderboardService) fanOut(ctx context.Context, parent *domain.LeaderboardConfig, submission domain.ScoreSubmission) []scoreChange {
+	boards, err := s.derivedBoards(ctx, parent.ID)
+	if err != nil {
+		s.logger.Warn("failed to load derived leaderboards", "leaderboard_id", parent.ID, "error", err)
+		return nil
+	}
+
+	var changes []scoreChange
+	resolve := submissionResolver(submission)
+	for i := range boards {
+		board := &boards[i]
+		if !board.filter.Match(resolve) {
+			continue
+		}
+		change, err := s.applyDerived(ctx, &board.config, submission)
+		if err != nil {
+			s.logger.Warn("failed to apply score to derived leaderboard",
+				"leaderboard_id", parent.ID,
+				"derived_id", board.config.ID,
+				"error", err,
+			)
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// applyDerived commits a submission to one derived board. Submission windows,
+// score transforms and bounds of the derived board apply; review does not.
+func (s *LeaderboardService) applyDerived(ctx context.Context, lbConfig *domain.LeaderboardConfig, submission domain.ScoreSubmission) (scoreChange, error) {
+	submission.LeaderboardID = lbConfig.ID
+	change := scoreChange{
+		leaderboardID: lbConfig.ID,
+		playerID:      submission.PlayerID,
+		config:        lbConfig,
+	}
+
+	if err := lbConfig.CheckWindow(s.clock.Now()); err != nil {
+		return change, err
+	}
+	score, err := s.deriveScore(ctx, lbConfig, submission)
+	if err != nil {
+		return change, err
+	}
+	if err := lbConfig.CheckScoreBounds(score); err != nil {
+		return change, err
+	}
+	return s.commitScore(ctx, change, submission, score, "derived")
+}
+
+// submissionResolver resolves filter paths against a submission: score and
+// player_id, and metadata fields with or without a metadata. prefix
+func submissionResolver(submission domain.ScoreSubmission) filter.Resolver {
+	return func(path string) (interface{}, bool) {
+		switch path {
+		case "score":
+			return float64(submission.Score), true
+		case "player_id":
+			return submission.PlayerID, true
+		}
+
+		var value interface{} = submission.Metadata
+		for _, key := range strings.Split(strings.TrimPrefix(path, "metadata."), ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object[key]; !ok {
+				return nil, false
+			}
+		}
+		if n, ok := numberValue(value); ok {
+			return n, true
+		}
+		return value, true
+	}
+}
+
+// validateDerived checks a new board's parent and filter
+func (s *LeaderboardService) validateDerived(ctx context.Context, config *domain.LeaderboardConfig) error {
+	if config.ParentID == "" {
+		if config.Filter != "" {
+			return domain.ErrInvalidLeaderboard
+		}
+		return nil
+	}
+	if config.ParentID == config.ID {
+		return domain.ErrInvalidLeaderboard
+	}
+	if _, err := filter.Compile(config.Filter); err != nil {
+		return fmt.Errorf("%w: filter: %v", domain.ErrInvalidLeaderboard, err)
+	}
+
+	parent, err := s.postgres.GetLeaderboard(ctx, config.ParentID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return domain.ErrInvalidLeaderboard
+		}
+		return err
+	}
+	// Chains of derived boards would fan out recursively
+	if parent.ParentID != "" {
+		return domain.ErrInvalidLeaderboard
+	}
+	// A derived board records the parent's submissions, so it keeps them as private
+	for _, field := range parent.SensitiveFields {
+		if !config.IsSensitive(field) {
+			config.SensitiveFields = append(config.SensitiveFields, field)
+		}
+	}
+	return nil
+}
+
+// withDerived adds the changes made on derived boards, and those boards, to a broadcast
+func withDerived(leaderboardIDs []string, changes []scoreChange) ([]string, []scoreChange) {
+	seen := make(map[string]bool, len(leaderboardIDs))
+	for _, id := range leaderboardIDs {
+		seen[id] = true
+	}
+	for _, change := range changes {
+		for _, derived := range change.derived {
+			if !seen[derived.leaderboardID] {
+				seen[derived.leaderboardID] = true
+				leaderboardIDs = append(leaderboardIDs, derived.leaderboardID)
+			}
+			changes = append(changes, derived)
+		}
+	}
+	return leaderboardIDs, changes
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// encryptedPrefix marks a sealed metadata value, stored as enc:<key id>:<base64 nonce+ciphertext>
+const encryptedPrefix = "enc:"
+
+var (
+	errUnknownKey      = errors.New("unknown encryption key")
+	errMalformedSealed = errors.New("malformed sealed value")
+)
+
+// MetadataCipher seals sensitive metadata values with AES-GCM. Each value is
+// bound to its leaderboard and field, so a sealed value copied to another row
+// or key fails to open.
+type MetadataCipher struct {
+	activeKey string
+	keys      map[string]cipher.AEAD
+}
+
+// NewMetadataCipher builds the cipher from the configured key ring
+func NewMetadataCipher(cfg *config.EncryptionConfig) (*MetadataCipher, error) {
+	c := &MetadataCipher{
+		activeKey: cfg.ActiveKey,
+		keys:      make(map[string]cipher.AEAD, len(cfg.Keys)),
+	}
+	for id, encoded := range cfg.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decoding encryption key %q: %w", id, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		c.keys[id] = aead
+	}
+	if _, ok := c.keys[c.activeKey]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", c.activeKey)
+	}
+	return c, nil
+}
+
+// seal encrypts one metadata value of a leaderboard with the active key
+func (c *MetadataCipher) seal(leaderboardID, field string, value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshaling %s: %w", field, err)
+	}
+	aead := c.keys[c.activeKey]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, sealingContext(leaderboardID, field))
+	return encryptedPrefix + c.activeKey + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a value produced by seal
+func (c *MetadataCipher) open(leaderboardID, field, sealed string) (interface{}, error) {
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(sealed, encryptedPrefix), ":")
+	if !ok {
+		return nil, errMalformedSealed
+	}
+	aead, ok := c.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownKey, keyID)
+	}
+	data, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, errMalformedSealed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, sealingContext(leaderboardID, field))
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, fmt.Errorf("unmarshaling %s: %w", field, err)
+	}
+	return value, nil
+}
+
+// sealingContext is the additional authenticated data binding a value to its place
+func sealingContext(leaderboardID, field string) []byte {
+	return []byte(leaderboardID + "\x00" + field)
+}
+
+// SetMetadataCipher sets the cipher that encrypts sensitive metadata fields
+func (s *LeaderboardService) SetMetadataCipher(c *MetadataCipher) {
+	s.cipher = c
+}
+
+// sealMetadata returns a copy of submission metadata with the leaderboard's
+// sensitive fields encrypted, ready for PostgreSQL. A field that cannot be
+// sealed, for example because no key is configured any more, is dropped
+// rather than stored in the clear.
+func (s *LeaderboardService) sealMetadata(lbConfig *domain.LeaderboardConfig, metadata map[string]interface{}) map[string]interface{} {
+	if len(lbConfig.SensitiveFields) == 0 || metadata == nil {
+		return metadata
+	}
+
+	sealed := make(map[string]interface{}, len(metadata))
+	for field, value := range metadata {
+		if !lbConfig.IsSensitive(field) {
+			sealed[field] = value
+			continue
+		}
+		if s.cipher == nil {
+			s.logger.Warn("dropping sensitive metadata field, no encryption key configured",
+				"leaderboard_id", lbConfig.ID, "field", field)
+			continue
+		}
+		ciphertext, err := s.cipher.seal(lbConfig.ID, field, value)
+		if err != nil {
+			s.logger.Warn("dropping sensitive metadata field", "leaderboard_id", lbConfig.ID, "field", field, "error", err)
+			continue
+		}
+		sealed[field] = ciphertext
+	}
+	return sealed
+}
+
+// openMetadata returns a copy of stored metadata with sealed values decrypted.
+// Values that fail to open are left sealed.
+func (s *LeaderboardService) openMetadata(leaderboardID string, metadata map[string]interface{}) map[string]interface{} {
+	if s.cipher == nil || metadata == nil {
+		return metadata
+	}
+
+	opened := make(map[string]interface{}, len(metadata))
+	for field, value := range metadata {
+		opened[field] = value
+		sealed, ok := value.(string)
+		if !ok || !strings.HasPrefix(sealed, encryptedPrefix) {
+			continue
+		}
+		plain, err := s.cipher.open(leaderboardID, field, sealed)
+		if err != nil {
+			s.logger.Warn("failed to decrypt metadata field", "leaderboard_id", leaderboardID, "field", field, "error", err)
+			continue
+		}
+		opened[field] = plain
+	}
+	return opened
+}
+
+// CanDecrypt reports whether a caller's token grants the plaintext of
+// sensitive metadata fields. No token is accepted until one is configured.
+func (s *LeaderboardService) CanDecrypt(token string) bool {
+	expected := s.config.Encryption.DecryptToken
+	if expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// DecryptEvents decrypts the sensitive metadata fields of score events
+func (s *LeaderboardService) DecryptEvents(events []domain.ScoreEvent) {
+	for i := range events {
+		events[i].Metadata = s.openMetadata(events[i].LeaderboardID, events[i].Metadata)
+	}
+}
+
+// DecryptPendingScores decrypts the sensitive metadata fields of held submissions
+func (s *LeaderboardService) DecryptPendingScores(scores []domain.PendingScore) {
+	for i := range scores {
+		scores[i].Metadata = s.openMetadata(scores[i].LeaderboardID, scores[i].Metadata)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// EventWriter stores score events; the repository inserts them directly and
+// an event buffer queues them for batched writes
+type EventWriter interface {
+	RecordEvent(ctx context.Context, event domain.ScoreEvent) error
+}
+
+// EventRecorder writes score events to PostgreSQL, applying the configured
+// sampling or aggregation policy for each leaderboard
+type EventRecorder struct {
+	writer EventWriter
+	config *config.EventsConfig
+	logger *slog.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	counters map[string]uint64
+	pending  map[aggregateKey]*aggregateEvent
+}
+
+// aggregateKey identifies a player's aggregation bucket
+type aggregateKey struct {
+	leaderboardID string
+	playerID      string
+}
+
+// aggregateEvent accumulates submissions for a player within one window
+type aggregateEvent struct {
+	event       domain.ScoreEvent
+	count       int
+	windowStart time.Time
+	window      time.Duration
+}
+
+// NewEventRecorder creates a new event recorder
+func NewEventRecorder(postgres *postgres.Repository, cfg *config.EventsConfig, logger *slog.Logger) *EventRecorder {
+	return &EventRecorder{
+		writer:   postgres,
+		config:   cfg,
+		logger:   logger,
+		clock:    clock.Real(),
+		counters: make(map[string]uint64),
+		pending:  make(map[aggregateKey]*aggregateEvent),
+	}
+}
+
+// SetClock replaces the wall clock used to close aggregation windows
+func (r *EventRecorder) SetClock(c clock.Clock) {
+	r.clock = c
+}
+
+// SetWriter replaces the repository as the destination of recorded events
+func (r *EventRecorder) SetWriter(w EventWriter) {
+	r.writer = w
+}
+
+// Record stores an event according to the leaderboard's sampling policy
+func (r *EventRecorder) Record(ctx context.Context, event domain.ScoreEvent) error {
+	// Events carrying proof are evidence and are never sampled away or merged
+	if event.Proof != nil {
+		return r.writer.RecordEvent(ctx, event)
+	}
+
+	sampling := r.config.ForLeaderboard(event.LeaderboardID)
+
+	switch sampling.Mode {
+	case config.EventSamplingSample:
+		if sampling.SampleRate <= 1 {
+			break
+		}
+		r.mu.Lock()
+		n := r.counters[event.LeaderboardID]
+		r.counters[event.LeaderboardID] = n + 1
+		r.mu.Unlock()
+		if n%uint64(sampling.SampleRate) != 0 {
+			return nil
+		}
+		event.SampleRate = sampling.SampleRate
+
+	case config.EventSamplingAggregate:
+		if flushed := r.aggregate(event, sampling.AggregateWindow); flushed != nil {
+			return r.writer.RecordEvent(ctx, *flushed)
+		}
+		return nil
+	}
+
+	if event.SampleRate == 0 {
+		event.SampleRate = 1
+	}
+	return r.writer.RecordEvent(ctx, event)
+}
+
+// aggregate folds an event into the player's current window and returns
+// the previous window's event if it has closed
+func (r *EventRecorder) aggregate(event domain.ScoreEvent, window time.Duration) *domain.ScoreEvent {
+	key := aggregateKey{leaderboardID: event.LeaderboardID, playerID: event.PlayerID}
+	windowStart := event.Timestamp.Truncate(window)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.pending[key]
+	if ok && current.windowStart.Equal(windowStart) {
+		current.count++
+		current.event.Score = event.Score
+		current.event.GameID = event.GameID
+		current.event.Timestamp = event.Timestamp
+		if event.Metadata != nil {
+			current.event.Metadata = event.Metadata
+		}
+		return nil
+	}
+
+	r.pending[key] = &aggregateEvent{
+		event:       event,
+		count:       1,
+		windowStart: windowStart,
+		window:      window,
+	}
+	if !ok {
+		return nil
+	}
+	flushed := current.finalize()
+	return &flushed
+}
+
+// finalize converts an aggregation bucket into the event that gets stored
+func (a *aggregateEvent) finalize() domain.ScoreEvent {
+	event := a.event
+	event.EventType = "submit_aggregate"
+	event.SampleRate = a.count
+	return event
+}
+
+// Run periodically flushes aggregation windows that have closed
+func (r *EventRecorder) Run(ctx context.Context) {
+	ticker := r.clock.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.Flush(context.Background(), true)
+			return
+		case <-ticker.C():
+			r.Flush(ctx, false)
+		}
+	}
+}
+
+// Flush writes aggregated events whose window has ended, or all of them when force is set
+func (r *EventRecorder) Flush(ctx context.Context, force bool) {
+	now := r.clock.Now()
+
+	r.mu.Lock()
+	var ready []domain.ScoreEvent
+	for key, agg := range r.pending {
+		if force || !now.Before(agg.windowStart.Add(agg.window)) {
+			ready = append(ready, agg.finalize())
+			delete(r.pending, key)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, event := range ready {
+		if err := r.writer.RecordEvent(ctx, event); err != nil {
+			r.logger.Warn("failed to record aggregated score event",
+				"leaderboard_id", event.LeaderboardID,
+				"player_id", event.PlayerID,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// touchSubmitted records a player's submission time on boards that drop inactive players
+func (s *LeaderboardService) touchSubmitted(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string) {
+	if lbConfig.InactivityDays <= 0 {
+		return
+	}
+	if err := s.redis.TouchSubmitted(ctx, lbConfig.ID, playerID, s.clock.Now()); err != nil {
+		s.logger.Warn("failed to record submission time",
+			"leaderboard_id", lbConfig.ID,
+			"player_id", playerID,
+			"error", err,
+		)
+	}
+}
+
+// ExpireInactive removes the players of a leaderboard who have not submitted
+// within its inactivity window, scanning batchSize entries per step. Each
+// removal is replicated and broadcast like a manual one. It returns how many
+// players were removed.
+func (s *LeaderboardService) ExpireInactive(ctx context.Context, lbConfig *domain.LeaderboardConfig, batchSize int) (int, error) {
+	if s.readOnly {
+		return 0, domain.ErrReadOnlyReplica
+	}
+	window := lbConfig.InactivityWindow()
+	if window <= 0 {
+		return 0, nil
+	}
+
+	now := s.clock.Now()
+	removed, version, err := s.redis.ExpireInactive(ctx, lbConfig.ID, now.Add(-window), now, int64(batchSize), lbConfig.HigherIsBetter())
+	// Players removed before a failure are still cleaned up and announced below
+	if len(removed) == 0 {
+		return 0, err
+	}
+
+	for _, entry := range removed {
+		if err := s.postgres.RemovePlayer(ctx, lbConfig.ID, entry.PlayerID); err != nil {
+			s.logger.Warn("failed to remove expired player from postgres",
+				"leaderboard_id", lbConfig.ID,
+				"player_id", entry.PlayerID,
+				"error", err,
+			)
+		}
+		s.replicate(domain.ReplicatedChange{
+			Op:            domain.ReplicationOpRemove,
+			LeaderboardID: lbConfig.ID,
+			PlayerID:      entry.PlayerID,
+			Version:       version,
+		})
+	}
+	s.stats.invalidate(lbConfig.ID)
+	s.broadcastRemovals(ctx, lbConfig.ID, removed)
+	s.purgeIfTopRemoved(lbConfig.ID, removed)
+
+	if err != nil {
+		return len(removed), fmt.Errorf("expiring inactive players: %w", err)
+	}
+	return len(removed), nil
+}
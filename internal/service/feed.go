@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// streamIDPattern matches Redis stream IDs accepted as feed cursors
+var streamIDPattern = regexp.MustCompile(`^\d+(-\d+)?$`)
+
+// feedEventFor classifies an applied score change as a feed event, or returns
+// nil when the change is not notable. newRank is the player's rank after the change.
+func (s *LeaderboardService) feedEventFor(change scoreChange, newRank int64) *domain.FeedEvent {
+	topN := s.config.Feed.TopN
+	improved := change.newScore > change.oldScore
+	if !change.config.HigherIsBetter() {
+		improved = change.newScore < change.oldScore
+	}
+
+	var eventType domain.FeedEventType
+	switch {
+	case newRank == 1 && (change.oldRank != 1 || improved):
+		eventType = domain.FeedRecord
+	case newRank <= topN && (change.oldRank == 0 || change.oldRank > topN):
+		eventType = domain.FeedTopEntry
+	default:
+		return nil
+	}
+
+	return &domain.FeedEvent{
+		Type:          eventType,
+		LeaderboardID: change.leaderboardID,
+		PlayerID:      change.playerID,
+		Score:         change.newScore,
+		Rank:          newRank,
+		PreviousRank:  change.oldRank,
+		Timestamp:     s.clock.Now(),
+	}
+}
+
+// recordFeed appends a feed event for an applied score change when it is notable
+// and forwards it to the global channel. Failures are logged and never fail the submission.
+func (s *LeaderboardService) recordFeed(ctx context.Context, change scoreChange) {
+	if !s.tracksNotable() || !change.changed {
+		return
+	}
+
+	entry, err := s.redis.GetPlayerRank(ctx, change.leaderboardID, change.playerID, change.config.HigherIsBetter())
+	if err != nil {
+		s.logger.Warn("failed to get rank for feed", "leaderboard_id", change.leaderboardID, "error", err)
+		return
+	}
+	event := s.feedEventFor(change, entry.Rank)
+	if event == nil {
+		return
+	}
+	if s.config.Feed.Enabled {
+		event.ID = s.appendFeed(ctx, *event)
+	}
+	s.publishGlobal(*event)
+}
+
+// appendFeed stores a feed event and returns its ID, logging failures
+func (s *LeaderboardService) appendFeed(ctx context.Context, event domain.FeedEvent) string {
+	id, err := s.redis.AppendFeedEvent(ctx, event, s.config.Feed.MaxLength)
+	if err != nil {
+		s.logger.Warn("failed to append feed event",
+			"leaderboard_id", event.LeaderboardID,
+			"type", event.Type,
+			"error", err,
+		)
+	}
+	return id
+}
+
+// GetFeed returns a page of a leaderboard's feed, newest first, continuing
+// after the before cursor when one is given
+func (s *LeaderboardService) GetFeed(ctx context.Context, leaderboardID, before string, limit int) (*domain.FeedPage, error) {
+	if before != "" && !streamIDPattern.MatchString(before) {
+		return nil, domain.ErrInvalidRequest
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > s.config.MaxLimit {
+		limit = s.config.MaxLimit
+	}
+
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrLeaderboardNotFound
+	}
+
+	events, err := s.redis.GetFeed(ctx, leaderboardID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FeedPage{Events: events}
+	if len(events) == limit {
+		page.NextBefore = events[len(events)-1].ID
+	}
+	return page, nil
+}
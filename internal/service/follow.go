@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/websocket"
+)
+
+// followedStandings remembers the last standing sent to the followers of each
+// followed player, by leaderboard ID then player ID
+type followedStandings struct {
+	mu   sync.Mutex
+	last map[string]map[string]domain.LeaderboardEntry
+}
+
+func newFollowedStandings() *followedStandings {
+	return &followedStandings{last: make(map[string]map[string]domain.LeaderboardEntry)}
+}
+
+// FollowPlayer returns a player's current standing for a WebSocket
+// subscribe_player command and records it as the baseline later followed
+// updates are compared with. A player without a score is followed too; the
+// error is then a player not-found error.
+func (s *LeaderboardService) FollowPlayer(ctx context.Context, leaderboardID, playerID string) (*domain.LeaderboardEntry, error) {
+	entry, err := s.GetPlayerRank(ctx, leaderboardID, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.follows.mu.Lock()
+	defer s.follows.mu.Unlock()
+	players, ok := s.follows.last[leaderboardID]
+	if !ok {
+		players = make(map[string]domain.LeaderboardEntry)
+		s.follows.last[leaderboardID] = players
+	}
+	// An existing baseline may already be newer than this read
+	if _, ok := players[playerID]; !ok {
+		players[playerID] = *entry
+	}
+	return entry, nil
+}
+
+// followerMessages re-ranks the followed players of the given leaderboards
+// after a batch of submissions and returns a followed player_update for each
+// one whose score or rank moved since the last update sent to their followers.
+// A submission of the followed player also supplies the standing it replaced.
+func (s *LeaderboardService) followerMessages(ctx context.Context, leaderboardIDs []string, configs map[string]*domain.LeaderboardConfig, changes []scoreChange) []*websocket.Message {
+	var messages []*websocket.Message
+	for _, leaderboardID := range leaderboardIDs {
+		players := s.hub.GetFollowedPlayers(leaderboardID)
+		if len(players) == 0 {
+			s.follows.mu.Lock()
+			delete(s.follows.last, leaderboardID)
+			s.follows.mu.Unlock()
+			continue
+		}
+
+		config := configs[leaderboardID]
+		if config == nil {
+			meta, err := s.readMeta(ctx, leaderboardID)
+			if err != nil {
+				s.logger.Warn("failed to get leaderboard meta for followers", "error", err)
+				continue
+			}
+			config = meta
+		}
+		current, err := s.redis.GetRanks(ctx, leaderboardID, players, config.HigherIsBetter())
+		if err != nil {
+			s.logger.Warn("failed to get followed player ranks", "leaderboard_id", leaderboardID, "error", err)
+			continue
+		}
+
+		own := make(map[string]scoreChange)
+		for _, change := range changes {
+			if change.leaderboardID == leaderboardID && change.changed {
+				if _, seen := own[change.playerID]; !seen {
+					own[change.playerID] = change
+				}
+			}
+		}
+
+		s.follows.mu.Lock()
+		last := s.follows.last[leaderboardID]
+		next := make(map[string]domain.LeaderboardEntry, len(players))
+		for _, playerID := range players {
+			entry, ranked := current[playerID]
+			if !ranked {
+				continue
+			}
+			next[playerID] = entry
+
+			prev, known := last[playerID]
+			if !known {
+				change, submitted := own[playerID]
+				if !submitted {
+					continue
+				}
+				prev = domain.LeaderboardEntry{Rank: change.oldRank, Score: change.oldScore}
+			}
+			if prev.Rank == entry.Rank && prev.Score == entry.Score {
+				continue
+			}
+			messages = append(messages, websocket.NewFollowedPlayerUpdateMessage(websocket.PlayerUpdate{
+				LeaderboardID: leaderboardID,
+				PlayerID:      playerID,
+				Score:         entry.Score,
+				Rank:          entry.Rank,
+				OldRank:       prev.Rank,
+				OldScore:      prev.Score,
+			}))
+		}
+		s.follows.last[leaderboardID] = next
+		s.follows.mu.Unlock()
+	}
+	return messages
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// SetGhost adds or replaces a system-owned ghost entry. Ghost scores are stored
+// as given: they skip score transforms, bounds, submission windows, and events.
+func (s *LeaderboardService) SetGhost(ctx context.Context, leaderboardID string, req domain.CreateGhostRequest) (*domain.Ghost, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+	if req.ID == "" {
+		return nil, domain.ErrInvalidRequest
+	}
+	if !domain.IsSafeScore(req.Score) {
+		return nil, domain.ErrInvalidScore
+	}
+
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrLeaderboardNotFound
+	}
+
+	now := s.clock.Now()
+	ghost := domain.Ghost{
+		LeaderboardID: leaderboardID,
+		ID:            req.GhostID(),
+		Name:          req.Name,
+		Score:         req.Score,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	// PostgreSQL is the source of truth that resets and restores re-seed from
+	if err := s.postgres.UpsertGhost(ctx, ghost); err != nil {
+		return nil, fmt.Errorf("saving ghost to postgres: %w", err)
+	}
+	if err := s.placeGhost(ctx, ghost); err != nil {
+		return nil, err
+	}
+	s.stats.invalidate(leaderboardID)
+	s.broadcastUpdate(ctx, leaderboardID)
+	s.purgeEdge(leaderboardID)
+
+	return &ghost, nil
+}
+
+// RemoveGhost deletes a ghost entry
+func (s *LeaderboardService) RemoveGhost(ctx context.Context, leaderboardID, ghostID string) error {
+	if s.readOnly {
+		return domain.ErrReadOnlyReplica
+	}
+	if !domain.IsGhostID(ghostID) {
+		ghostID = domain.GhostIDPrefix + ghostID
+	}
+
+	if err := s.postgres.DeleteGhost(ctx, leaderboardID, ghostID); err != nil {
+		return err
+	}
+	version, err := s.redis.RemoveGhost(ctx, leaderboardID, ghostID)
+	if err != nil {
+		return fmt.Errorf("removing ghost from redis: %w", err)
+	}
+	s.replicate(domain.ReplicatedChange{
+		Op:            domain.ReplicationOpRemove,
+		LeaderboardID: leaderboardID,
+		PlayerID:      ghostID,
+		Version:       version,
+	})
+	s.stats.invalidate(leaderboardID)
+	s.broadcastUpdate(ctx, leaderboardID)
+	s.purgeEdge(leaderboardID)
+
+	return nil
+}
+
+// ListGhosts returns a leaderboard's ghost entries
+func (s *LeaderboardService) ListGhosts(ctx context.Context, leaderboardID string) ([]domain.Ghost, error) {
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrLeaderboardNotFound
+	}
+	return s.postgres.ListGhosts(ctx, leaderboardID)
+}
+
+// placeGhost writes a ghost entry to Redis and replicates it
+func (s *LeaderboardService) placeGhost(ctx context.Context, ghost domain.Ghost) error {
+	version, err := s.redis.SetGhost(ctx, ghost)
+	if err != nil {
+		return fmt.Errorf("setting ghost in redis: %w", err)
+	}
+	s.replicate(domain.ReplicatedChange{
+		Op:            domain.ReplicationOpSet,
+		LeaderboardID: ghost.LeaderboardID,
+		PlayerID:      ghost.ID,
+		Score:         ghost.Score,
+		Version:       version,
+	})
+	return nil
+}
+
+// reseedGhosts puts a leaderboard's ghost entries back after its scores were cleared
+func (s *LeaderboardService) reseedGhosts(ctx context.Context, leaderboardID string) error {
+	ghosts, err := s.postgres.ListGhosts(ctx, leaderboardID)
+	if err != nil {
+		return err
+	}
+	for _, ghost := range ghosts {
+		if err := s.placeGhost(ctx, ghost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markGhosts flags ghost entries and fills in their display names
+func (s *LeaderboardService) markGhosts(ctx context.Context, leaderboardID string, entries []domain.LeaderboardEntry) error {
+	var ghostIDs []string
+	for i := range entries {
+		if domain.IsGhostID(entries[i].PlayerID) {
+			entries[i].IsGhost = true
+			ghostIDs = append(ghostIDs, entries[i].PlayerID)
+		}
+	}
+	if len(ghostIDs) == 0 {
+		return nil
+	}
+
+	names, err := s.redis.GetGhostNames(ctx, leaderboardID, ghostIDs)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].IsGhost {
+			entries[i].Username = names[entries[i].PlayerID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// SetGlobalEvents sets which event types are forwarded to the global WebSocket channel
+func (s *LeaderboardService) SetGlobalEvents(types []string) {
+	s.globalEvents = make(map[domain.FeedEventType]bool, len(types))
+	for _, t := range types {
+		s.globalEvents[domain.FeedEventType(t)] = true
+	}
+}
+
+// wantsGlobal reports whether events of the given type reach the global channel
+func (s *LeaderboardService) wantsGlobal(eventType domain.FeedEventType) bool {
+	return s.hub != nil && s.globalEvents[eventType]
+}
+
+// tracksNotable reports whether score changes need to be classified as feed
+// events, either for the feed itself or for the global channel
+func (s *LeaderboardService) tracksNotable() bool {
+	return s.config.Feed.Enabled || s.wantsGlobal(domain.FeedRecord) || s.wantsGlobal(domain.FeedTopEntry)
+}
+
+// publishGlobal sends an event to global channel subscribers when its type is enabled
+func (s *LeaderboardService) publishGlobal(event domain.FeedEvent) {
+	if !s.wantsGlobal(event.Type) {
+		return
+	}
+	s.hub.BroadcastGlobalEvent(event)
+}
+
+// NotifyWindow announces a leaderboard's submission window opening or closing
+// on the global channel
+func (s *LeaderboardService) NotifyWindow(leaderboardID string, eventType domain.FeedEventType, at time.Time) {
+	s.publishGlobal(domain.FeedEvent{
+		Type:          eventType,
+		LeaderboardID: leaderboardID,
+		Timestamp:     at,
+	})
+}
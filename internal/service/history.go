@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// GetHistory returns a page of a leaderboard's recorded score events, for one
+// player when playerID is set. Events are ordered by time and then id, oldest
+// first unless descending is set; cursor continues a previous page.
+func (s *LeaderboardService) GetHistory(ctx context.Context, leaderboardID, playerID, cursor string, limit int, descending bool) (*domain.HistoryPage, error) {
+	page, err := s.keysetPage(cursor, limit, descending)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrLeaderboardNotFound
+	}
+
+	events, next, err := s.postgres.ListScoreEvents(ctx, leaderboardID, playerID, page)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.HistoryPage{Events: events, NextCursor: encodeKeyset(next)}, nil
+}
+
+// keysetPage builds a keyset page request from a cursor and a requested limit
+func (s *LeaderboardService) keysetPage(cursor string, limit int, descending bool) (postgres.KeysetPage, error) {
+	after, err := postgres.DecodeKeyset(cursor)
+	if err != nil {
+		return postgres.KeysetPage{}, err
+	}
+	if limit <= 0 {
+		limit = s.config.DefaultLimit
+	}
+	if limit > s.config.MaxLimit {
+		limit = s.config.MaxLimit
+	}
+	return postgres.KeysetPage{After: after, Limit: limit, Descending: descending}, nil
+}
+
+// encodeKeyset returns the cursor for the next page, empty on the last page
+func encodeKeyset(next *postgres.Keyset) string {
+	if next == nil {
+		return ""
+	}
+	return next.Encode()
+}
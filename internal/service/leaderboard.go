@@ -2,17 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/leaderboard-redis/internal/clock"
 	"github.com/leaderboard-redis/internal/config"
 	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/ids"
 	"github.com/leaderboard-redis/internal/postgres"
 	"github.com/leaderboard-redis/internal/redis"
 	"github.com/leaderboard-redis/internal/websocket"
 )
 
+// versionPollInterval is how often WaitForVersion re-reads a leaderboard's version
+const versionPollInterval = 10 * time.Millisecond
+
 // LeaderboardService provides business logic for leaderboard operations
 type LeaderboardService struct {
 	redis    *redis.LeaderboardService
@@ -20,6 +26,51 @@ type LeaderboardService struct {
 	config   *config.LeaderboardConfig
 	logger   *slog.Logger
 	hub      *websocket.Hub
+	events   *EventRecorder
+	throttle *broadcastThrottle
+	stats    *statsCache
+	clock    clock.Clock
+	schedule domain.ResetSchedule
+
+	replicator Replicator
+	readOnly   bool
+
+	// Event types forwarded to the global WebSocket channel
+	globalEvents map[domain.FeedEventType]bool
+
+	dedup    *dedupCounters
+	derived  *derivedCache
+	overview *overviewCache
+
+	transformer ScoreTransformer
+	scripts     *scriptEngine
+
+	// cipher seals sensitive metadata fields; nil when no key is configured
+	cipher *MetadataCipher
+
+	// payouts receives computed prize payouts; nil disables publishing
+	payouts PayoutPublisher
+
+	// purger purges CDN copies of changed boards; nil disables purging
+	purger EdgePurger
+
+	// ids validates leaderboard IDs and generates them on create
+	ids ids.Policy
+
+	// legacy mirrors accepted submissions to the service being migrated
+	// from; nil disables dual writes
+	legacy LegacyForwarder
+
+	// bulkDeletes tracks bulk player deletions started on this instance
+	bulkDeletes *bulkDeleteJobs
+
+	// follows holds the last standings sent to WebSocket player followers
+	follows *followedStandings
+}
+
+// Replicator publishes applied score changes to a secondary region
+type Replicator interface {
+	Publish(change domain.ReplicatedChange)
 }
 
 // NewLeaderboardService creates a new leaderboard service
@@ -34,198 +85,694 @@ func NewLeaderboardService(
 		postgres: postgres,
 		config:   cfg,
 		logger:   logger,
+		throttle: newBroadcastThrottle(),
+		stats:    newStatsCache(),
+		dedup:    newDedupCounters(),
+		derived:  newDerivedCache(),
+		overview: &overviewCache{},
+		clock:    clock.Real(),
+
+		bulkDeletes: newBulkDeleteJobs(),
+		follows:     newFollowedStandings(),
+		schedule: domain.DefaultResetSchedule(),
+
+		transformer: newFormulaTransformer(),
+		scripts:     newScriptEngine(&cfg.Scripts),
+		ids:         ids.Default(),
 	}
 }
 
+// SetIDPolicy sets the policy leaderboard IDs are validated and generated with
+func (s *LeaderboardService) SetIDPolicy(policy ids.Policy) {
+	s.ids = policy
+}
+
 // SetHub sets the WebSocket hub for broadcasting updates
 func (s *LeaderboardService) SetHub(hub *websocket.Hub) {
 	s.hub = hub
 }
 
-// broadcastUpdate broadcasts leaderboard update to WebSocket clients
-func (s *LeaderboardService) broadcastUpdate(ctx context.Context, leaderboardID string) {
-	if s.hub == nil {
-		return
+// SetEventRecorder sets the recorder used to persist score events
+func (s *LeaderboardService) SetEventRecorder(recorder *EventRecorder) {
+	s.events = recorder
+}
+
+// SetClock replaces the wall clock, e.g. with a simulated one
+func (s *LeaderboardService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetResetSchedule sets the timezone and anchor used to compute next_reset_at
+func (s *LeaderboardService) SetResetSchedule(schedule domain.ResetSchedule) {
+	s.schedule = schedule
+}
+
+// withNextReset fills in a leaderboard's computed next reset time
+func (s *LeaderboardService) withNextReset(lbConfig *domain.LeaderboardConfig) {
+	if next, ok := s.schedule.For(lbConfig).NextReset(lbConfig.ResetPeriod, s.clock.Now()); ok {
+		lbConfig.NextResetAt = &next
 	}
+}
 
-	// Get only top 10 entries for broadcast (efficient for large leaderboards)
-	entries, err := s.redis.GetTopN(ctx, leaderboardID, 10)
-	if err != nil {
-		s.logger.Warn("failed to get entries for broadcast", "error", err)
+// SetReplicator sets the publisher that streams applied changes to a secondary region
+func (s *LeaderboardService) SetReplicator(replicator Replicator) {
+	s.replicator = replicator
+}
+
+// SetReadOnly makes the service reject local score writes, as on a replica region
+// that only applies changes replicated from the primary
+func (s *LeaderboardService) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
+
+// ReadOnly reports whether local score writes are rejected
+func (s *LeaderboardService) ReadOnly() bool {
+	return s.readOnly
+}
+
+// replicate publishes an applied change, if replication is configured
+func (s *LeaderboardService) replicate(change domain.ReplicatedChange) {
+	if s.replicator == nil {
 		return
 	}
+	change.AppliedAt = s.clock.Now()
+	s.replicator.Publish(change)
+}
 
-	count, _ := s.redis.GetCount(ctx, leaderboardID)
-	s.hub.BroadcastLeaderboardUpdate(leaderboardID, entries, count)
+// recordEvent persists a score event, applying sampling when a recorder is configured
+func (s *LeaderboardService) recordEvent(ctx context.Context, event domain.ScoreEvent) error {
+	if s.events == nil {
+		return s.postgres.RecordEvent(ctx, event)
+	}
+	return s.events.Record(ctx, event)
 }
 
 // SubmitScore submits a score for a player
 func (s *LeaderboardService) SubmitScore(ctx context.Context, submission domain.ScoreSubmission) error {
-	// Get leaderboard config
-	lbConfig, err := s.postgres.GetLeaderboard(ctx, submission.LeaderboardID)
+	_, err := s.SubmitScoreWithReceipt(ctx, submission)
+	return err
+}
+
+// SubmitScoreWithReceipt submits a score and returns the leaderboard version at
+// which it is visible, for use as a read-your-writes token, or the pending ID
+// when the score was held for review
+func (s *LeaderboardService) SubmitScoreWithReceipt(ctx context.Context, submission domain.ScoreSubmission) (domain.SubmitReceipt, error) {
+	change, err := s.submitScoreWithoutBroadcast(ctx, submission)
 	if err != nil {
-		return fmt.Errorf("getting leaderboard config: %w", err)
+		return domain.SubmitReceipt{}, err
 	}
-
-	// Apply score based on update mode
-	switch lbConfig.UpdateMode {
-	case domain.UpdateModeReplace:
-		if err := s.redis.SetScore(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score); err != nil {
-			return fmt.Errorf("setting score in redis: %w", err)
-		}
-	case domain.UpdateModeIncrement:
-		if _, err := s.redis.IncrementScore(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score); err != nil {
-			return fmt.Errorf("incrementing score in redis: %w", err)
-		}
-	case domain.UpdateModeBest:
-		higherIsBetter := lbConfig.SortOrder == domain.SortOrderDesc
-		if _, err := s.redis.SetScoreIfBetter(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score, higherIsBetter); err != nil {
-			return fmt.Errorf("setting best score in redis: %w", err)
-		}
-	default:
-		if err := s.redis.SetScore(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score); err != nil {
-			return fmt.Errorf("setting score in redis: %w", err)
-		}
+	if change.duplicate {
+		return domain.SubmitReceipt{Duplicate: true}, nil
 	}
-
-	// Record the event in PostgreSQL
-	event := domain.ScoreEvent{
-		PlayerID:      submission.PlayerID,
-		LeaderboardID: submission.LeaderboardID,
-		Score:         submission.Score,
-		GameID:        submission.GameID,
-		EventType:     "submit",
-		Timestamp:     time.Now(),
-		Metadata:      submission.Metadata,
+	if change.ignored {
+		return domain.SubmitReceipt{Ignored: true, AttemptsLeft: change.attemptsLeft}, nil
 	}
-	if err := s.postgres.RecordEvent(ctx, event); err != nil {
-		s.logger.Warn("failed to record score event", "error", err)
-		// Don't fail the request if event recording fails
+	if change.pendingID != 0 {
+		return domain.SubmitReceipt{PendingID: change.pendingID, AttemptsLeft: change.attemptsLeft}, nil
 	}
 
 	// Broadcast update to WebSocket clients
-	s.broadcastUpdate(ctx, submission.LeaderboardID)
+	s.broadcastChanges(ctx, []string{submission.LeaderboardID}, []scoreChange{change})
 
-	return nil
+	return domain.SubmitReceipt{Version: change.version, AttemptsLeft: change.attemptsLeft}, nil
 }
 
 // SubmitScoreBatch submits multiple scores
 func (s *LeaderboardService) SubmitScoreBatch(ctx context.Context, batch domain.BatchScoreSubmission) error {
+	if s.readOnly {
+		return domain.ErrReadOnlyReplica
+	}
+	s.SubmitScoreBatchWithResult(ctx, batch)
+	return nil
+}
+
+// SubmitScoreBatchWithResult submits multiple scores and reports which ones failed
+func (s *LeaderboardService) SubmitScoreBatchWithResult(ctx context.Context, batch domain.BatchScoreSubmission) domain.BatchResult {
 	// Track which leaderboards were updated
 	updatedLeaderboards := make(map[string]bool)
+	var leaderboardIDs []string
+	var changes []scoreChange
+	var result domain.BatchResult
 
-	for _, submission := range batch.Scores {
-		if err := s.submitScoreWithoutBroadcast(ctx, submission); err != nil {
+	for i, submission := range batch.Scores {
+		change, err := s.submitScoreWithoutBroadcast(ctx, submission)
+		if err != nil {
 			s.logger.Error("failed to submit score in batch",
 				"player_id", submission.PlayerID,
 				"leaderboard_id", submission.LeaderboardID,
 				"error", err,
 			)
+			result.Failed = append(result.Failed, domain.BatchFailure{
+				Index:         i,
+				PlayerID:      submission.PlayerID,
+				LeaderboardID: submission.LeaderboardID,
+				Error:         err.Error(),
+				Err:           err,
+			})
 			// Continue processing other scores
+		} else if change.duplicate {
+			result.Duplicates++
+		} else if change.ignored {
+			result.Ignored++
+		} else if change.pendingID != 0 {
+			result.Pending++
 		} else {
-			updatedLeaderboards[submission.LeaderboardID] = true
+			result.Accepted++
+			if change.version > 0 {
+				if result.Versions == nil {
+					result.Versions = make(map[string]int64)
+				}
+				if change.version > result.Versions[submission.LeaderboardID] {
+					result.Versions[submission.LeaderboardID] = change.version
+				}
+			}
+			if !updatedLeaderboards[submission.LeaderboardID] {
+				updatedLeaderboards[submission.LeaderboardID] = true
+				leaderboardIDs = append(leaderboardIDs, submission.LeaderboardID)
+			}
+			changes = append(changes, change)
 		}
 	}
 
-	// Broadcast updates for all affected leaderboards
-	for leaderboardID := range updatedLeaderboards {
-		s.broadcastUpdate(ctx, leaderboardID)
-	}
+	// Broadcast updates for all affected leaderboards in a single hub pass
+	s.broadcastChanges(ctx, leaderboardIDs, changes)
 
-	return nil
+	return result
 }
 
 // submitScoreWithoutBroadcast submits a score without broadcasting (for batch operations)
-func (s *LeaderboardService) submitScoreWithoutBroadcast(ctx context.Context, submission domain.ScoreSubmission) error {
-	// Get leaderboard config
-	lbConfig, err := s.postgres.GetLeaderboard(ctx, submission.LeaderboardID)
+func (s *LeaderboardService) submitScoreWithoutBroadcast(ctx context.Context, submission domain.ScoreSubmission) (scoreChange, error) {
+	change := scoreChange{
+		leaderboardID: submission.LeaderboardID,
+		playerID:      submission.PlayerID,
+	}
+
+	if s.readOnly {
+		return change, domain.ErrReadOnlyReplica
+	}
+
+	lbConfig, err := s.validateSubmission(ctx, submission)
 	if err != nil {
-		return fmt.Errorf("getting leaderboard config: %w", err)
+		return change, err
+	}
+	if submission.UpdateMode != "" {
+		routed := *lbConfig
+		routed.UpdateMode = submission.UpdateMode
+		lbConfig = &routed
 	}
 
-	// Apply score based on update mode
-	switch lbConfig.UpdateMode {
-	case domain.UpdateModeReplace:
-		if err := s.redis.SetScore(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score); err != nil {
-			return fmt.Errorf("setting score in redis: %w", err)
-		}
-	case domain.UpdateModeIncrement:
-		if _, err := s.redis.IncrementScore(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score); err != nil {
-			return fmt.Errorf("incrementing score in redis: %w", err)
+	change.config = lbConfig
+
+	if err := lbConfig.CheckWindow(s.clock.Now()); err != nil {
+		return change, err
+	}
+
+	score, err := s.deriveScore(ctx, lbConfig, submission)
+	if err != nil {
+		return change, err
+	}
+	if err := lbConfig.CheckScoreBounds(score); err != nil {
+		return change, err
+	}
+
+	// The same logical submission may arrive over both HTTP and Kafka
+	if !s.claimSubmission(ctx, submission) {
+		change.duplicate = true
+		return change, nil
+	}
+
+	// Only new submissions use one of the player's attempts. An ignored
+	// submission stays claimed, so its retries are duplicates.
+	attemptsLeft, ignored, err := s.useAttempt(ctx, lbConfig, submission.PlayerID)
+	if err != nil {
+		s.releaseSubmission(ctx, submission)
+		return change, err
+	}
+	change.attemptsLeft = attemptsLeft
+	if ignored {
+		change.ignored = true
+		return change, nil
+	}
+
+	// Record-breaking scores wait for manual review before they are applied
+	if lbConfig.NeedsReview(score) {
+		change.pendingID, err = s.holdForReview(ctx, lbConfig, submission, score)
+	} else {
+		change, err = s.commitScore(ctx, change, submission, score, "submit")
+	}
+	if err != nil {
+		s.refundAttempt(ctx, lbConfig, submission.PlayerID)
+		s.releaseSubmission(ctx, submission)
+		return change, err
+	}
+	s.forwardLegacy(submission)
+	return change, nil
+}
+
+// commitScore applies an accepted, transformed score to a leaderboard and
+// performs the follow-up writes: participation counts, replication, the shadow
+// board, and the score event
+func (s *LeaderboardService) commitScore(ctx context.Context, change scoreChange, submission domain.ScoreSubmission, score int64, eventType string) (scoreChange, error) {
+	lbConfig := change.config
+	var err error
+
+	// Capture the old standing only when someone is listening for player updates,
+	// the feed or global channel needs it to spot players entering the top ranks,
+	// or CDN purging needs it to spot players leaving them
+	if s.hasSubscribers(submission.LeaderboardID) || s.tracksNotable() || s.purger != nil {
+		old, err := s.redis.GetPlayerRank(ctx, submission.LeaderboardID, submission.PlayerID, lbConfig.HigherIsBetter())
+		if err != nil && !errors.Is(err, domain.ErrPlayerNotFound) {
+			s.logger.Warn("failed to get old rank", "error", err)
 		}
-	case domain.UpdateModeBest:
-		higherIsBetter := lbConfig.SortOrder == domain.SortOrderDesc
-		if _, err := s.redis.SetScoreIfBetter(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score, higherIsBetter); err != nil {
-			return fmt.Errorf("setting best score in redis: %w", err)
+		if old != nil {
+			change.oldRank = old.Rank
+			change.oldScore = old.Score
 		}
-	default:
-		if err := s.redis.SetScore(ctx, submission.LeaderboardID, submission.PlayerID, submission.Score); err != nil {
-			return fmt.Errorf("setting score in redis: %w", err)
+	}
+
+	tie := lbConfig.TieComponent(s.clock.Now())
+	change.newScore, change.changed, change.version, err = s.applyScore(ctx, lbConfig, submission.PlayerID, score, tie)
+	if err != nil {
+		return change, err
+	}
+	s.countSubmission(ctx, lbConfig, submission.PlayerID)
+	s.touchSubmitted(ctx, lbConfig, submission.PlayerID)
+	s.recordActivity(ctx, lbConfig.ID)
+	s.recordFeed(ctx, change)
+	s.purgeIfTopChanged(ctx, change)
+	if change.changed {
+		// The stored proof always belongs to the score that currently stands
+		if err := s.redis.SetProof(ctx, lbConfig.ID, submission.PlayerID, submission.Proof()); err != nil {
+			s.logger.Warn("failed to store score proof", "leaderboard_id", lbConfig.ID, "player_id", submission.PlayerID, "error", err)
 		}
+		s.replicate(domain.ReplicatedChange{
+			Op:            domain.ReplicationOpSet,
+			LeaderboardID: lbConfig.ID,
+			PlayerID:      submission.PlayerID,
+			Score:         change.newScore,
+			Tie:           tie,
+			Version:       change.version,
+		})
+		s.enforceMaxEntries(ctx, lbConfig)
+	}
+
+	// Mirror the submission onto the shadow leaderboard, if any
+	if lbConfig.ShadowID != "" {
+		s.applyShadow(ctx, lbConfig.ShadowID, submission)
+	}
+	// Derived boards cannot have derived boards of their own
+	if lbConfig.ParentID == "" {
+		change.derived = s.fanOut(ctx, lbConfig, submission)
 	}
 
 	// Record the event in PostgreSQL
 	event := domain.ScoreEvent{
 		PlayerID:      submission.PlayerID,
 		LeaderboardID: submission.LeaderboardID,
-		Score:         submission.Score,
+		Score:         score,
 		GameID:        submission.GameID,
-		EventType:     "submit",
-		Timestamp:     time.Now(),
-		Metadata:      submission.Metadata,
+		EventType:     eventType,
+		Timestamp:     s.clock.Now(),
+		Metadata:      s.sealMetadata(lbConfig, submission.Metadata),
+		Proof:         submission.Proof(),
 	}
-	if err := s.postgres.RecordEvent(ctx, event); err != nil {
+	if err := s.recordEvent(ctx, event); err != nil {
 		s.logger.Warn("failed to record score event", "error", err)
+		// Don't fail the request if event recording fails
 	}
 
-	return nil
+	return change, nil
+}
+
+// validateSubmission checks a submission and returns the target leaderboard's config
+func (s *LeaderboardService) validateSubmission(ctx context.Context, submission domain.ScoreSubmission) (*domain.LeaderboardConfig, error) {
+	invalid := &domain.ValidationError{}
+	if submission.PlayerID == "" {
+		invalid.Fields = append(invalid.Fields, domain.FieldError{Field: "player_id", Message: "is required"})
+	}
+	if submission.LeaderboardID == "" {
+		invalid.Fields = append(invalid.Fields, domain.FieldError{Field: "leaderboard_id", Message: "is required"})
+	}
+	if len(invalid.Fields) > 0 {
+		return nil, invalid
+	}
+	if err := s.ids.Validate(submission.LeaderboardID); err != nil {
+		return nil, domain.NewValidationError(domain.ErrInvalidRequest, "leaderboard_id", err.Error())
+	}
+
+	// Ghost IDs belong to system-owned entries managed through the ghosts API
+	if domain.IsGhostID(submission.PlayerID) {
+		return nil, domain.NewValidationError(domain.ErrInvalidRequest, "player_id", "is reserved for ghost entries")
+	}
+	if proof := submission.Proof(); proof != nil {
+		if err := proof.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get leaderboard config
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, submission.LeaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("getting leaderboard config: %w", err)
+	}
+
+	return lbConfig, nil
+}
+
+// isInvalidSubmission reports whether err rejects the submission itself rather
+// than reporting a failure to evaluate it
+func isInvalidSubmission(err error) bool {
+	var validation *domain.ValidationError
+	return errors.Is(err, domain.ErrInvalidScore) || errors.Is(err, domain.ErrInvalidRequest) ||
+		errors.As(err, &validation)
+}
+
+// ValidateScore runs all submission validation, including the attempt quota,
+// and projects the resulting score and rank without persisting anything or
+// using an attempt. Rate limits are checked by the handler.
+func (s *LeaderboardService) ValidateScore(ctx context.Context, submission domain.ScoreSubmission) (*domain.ScoreValidation, error) {
+	result := &domain.ScoreValidation{
+		LeaderboardID: submission.LeaderboardID,
+		PlayerID:      submission.PlayerID,
+	}
+
+	lbConfig, err := s.validateSubmission(ctx, submission)
+	if err != nil {
+		if isInvalidSubmission(err) || domain.IsNotFoundError(err) {
+			result.Errors = append(result.Errors, err.Error())
+			return result, nil
+		}
+		return nil, err
+	}
+	result.UpdateMode = string(lbConfig.UpdateMode)
+
+	if err := lbConfig.CheckWindow(s.clock.Now()); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+
+	// Only a rejected score is reported as invalid; failing to load or
+	// compile the scoring script is the server's error
+	score, err := s.deriveScore(ctx, lbConfig, submission)
+	if err == nil {
+		result.NeedsReview = lbConfig.NeedsReview(score)
+		err = lbConfig.CheckScoreBounds(score)
+	}
+	if err != nil {
+		if isInvalidSubmission(err) {
+			result.Errors = append(result.Errors, err.Error())
+			return result, nil
+		}
+		return nil, fmt.Errorf("deriving score: %w", err)
+	}
+
+	// Reads the quota without using an attempt
+	result.AttemptsLeft, err = s.checkAttempt(ctx, lbConfig, submission.PlayerID)
+	if err != nil {
+		var quotaErr *domain.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			result.Errors = append(result.Errors, err.Error())
+			return result, nil
+		}
+		return nil, err
+	}
+
+	current, exists, err := s.redis.GetScore(ctx, lbConfig.ID, submission.PlayerID)
+	if err != nil {
+		return nil, fmt.Errorf("getting current score: %w", err)
+	}
+
+	higherIsBetter := lbConfig.HigherIsBetter()
+	projected := score
+	if lbConfig.UpdateMode == domain.UpdateModeIncrement {
+		projected, _ = lbConfig.ClampTotal(score)
+	}
+	if exists {
+		result.CurrentScore = &current
+		switch lbConfig.UpdateMode {
+		case domain.UpdateModeIncrement:
+			projected, _ = lbConfig.ClampTotal(current + score)
+		case domain.UpdateModeBest:
+			if (higherIsBetter && current >= score) || (!higherIsBetter && current <= score) {
+				projected = current
+			}
+		}
+
+		currentBetter, err := s.redis.CountBetter(ctx, lbConfig.ID, current, higherIsBetter)
+		if err != nil {
+			return nil, err
+		}
+		currentRank := currentBetter + 1
+		result.CurrentRank = &currentRank
+	}
+
+	// An incremented total can leave the range Redis stores exactly
+	if !domain.IsSafeScore(projected) {
+		result.Errors = append(result.Errors, domain.ErrInvalidScore.Error())
+		return result, nil
+	}
+
+	better, err := s.redis.CountBetter(ctx, lbConfig.ID, projected, higherIsBetter)
+	if err != nil {
+		return nil, err
+	}
+	// The player's own current entry must not count against the projected rank
+	if exists && ((higherIsBetter && current > projected) || (!higherIsBetter && current < projected)) {
+		better--
+	}
+
+	result.Valid = true
+	result.ProjectedScore = projected
+	result.ProjectedRank = better + 1
+	result.WouldChange = !exists || projected != current
+
+	return result, nil
+}
+
+// applyScore writes a score to Redis according to the leaderboard's update mode,
+// with the tie-break component tie. It returns the player's resulting score,
+// whether the stored score changed, and the leaderboard version at which the
+// result is visible.
+func (s *LeaderboardService) applyScore(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string, score int64, tie float64) (int64, bool, int64, error) {
+	switch lbConfig.UpdateMode {
+	case domain.UpdateModeIncrement:
+		if lbConfig.HasClamp() {
+			newScore, version, clamped, err := s.redis.IncrementScoreClamped(ctx, lbConfig.ID, playerID, score, tie, lbConfig.ClampMin, lbConfig.ClampMax)
+			if err != nil {
+				return 0, false, 0, fmt.Errorf("incrementing score in redis: %w", err)
+			}
+			if clamped {
+				s.recordClamped(ctx, lbConfig, playerID, score, newScore)
+			}
+			return newScore, true, version, nil
+		}
+		newScore, version, err := s.redis.IncrementScore(ctx, lbConfig.ID, playerID, score, tie)
+		if err != nil {
+			return 0, false, 0, fmt.Errorf("incrementing score in redis: %w", err)
+		}
+		return newScore, true, version, nil
+	case domain.UpdateModeBest:
+		higherIsBetter := lbConfig.HigherIsBetter()
+		updated, version, err := s.redis.SetScoreIfBetter(ctx, lbConfig.ID, playerID, score, tie, higherIsBetter)
+		if err != nil {
+			return 0, false, 0, fmt.Errorf("setting best score in redis: %w", err)
+		}
+		return score, updated, version, nil
+	default:
+		version, err := s.redis.SetScore(ctx, lbConfig.ID, playerID, score, tie)
+		if err != nil {
+			return 0, false, 0, fmt.Errorf("setting score in redis: %w", err)
+		}
+		return score, true, version, nil
+	}
+}
+
+// applyShadow applies a submission to a shadow leaderboard using the shadow's own config.
+// Failures are logged and never affect the primary submission.
+func (s *LeaderboardService) applyShadow(ctx context.Context, shadowID string, submission domain.ScoreSubmission) {
+	shadowConfig, err := s.postgres.GetLeaderboard(ctx, shadowID)
+	if err != nil {
+		s.logger.Warn("failed to get shadow leaderboard config",
+			"leaderboard_id", submission.LeaderboardID,
+			"shadow_id", shadowID,
+			"error", err,
+		)
+		return
+	}
+
+	score, err := s.deriveScore(ctx, shadowConfig, submission)
+	if err == nil {
+		err = shadowConfig.CheckScoreBounds(score)
+	}
+	if err != nil {
+		s.logger.Warn("failed to transform score for shadow leaderboard",
+			"leaderboard_id", submission.LeaderboardID,
+			"shadow_id", shadowID,
+			"error", err,
+		)
+		return
+	}
+
+	tie := shadowConfig.TieComponent(s.clock.Now())
+	newScore, changed, version, err := s.applyScore(ctx, shadowConfig, submission.PlayerID, score, tie)
+	if err != nil {
+		s.logger.Warn("failed to apply score to shadow leaderboard",
+			"leaderboard_id", submission.LeaderboardID,
+			"shadow_id", shadowID,
+			"error", err,
+		)
+		return
+	}
+	s.countSubmission(ctx, shadowConfig, submission.PlayerID)
+	s.touchSubmitted(ctx, shadowConfig, submission.PlayerID)
+	if changed {
+		s.replicate(domain.ReplicatedChange{
+			Op:            domain.ReplicationOpSet,
+			LeaderboardID: shadowID,
+			PlayerID:      submission.PlayerID,
+			Score:         newScore,
+			Tie:           tie,
+			Version:       version,
+		})
+		s.enforceMaxEntries(ctx, shadowConfig)
+	}
 }
 
-// GetTopN returns the top N players from a leaderboard
+// WaitForVersion blocks until a leaderboard has reached minVersion, so a read
+// issued afterwards observes every write that returned that version token.
+// It returns the visible version, or ErrVersionNotVisible after the configured timeout.
+func (s *LeaderboardService) WaitForVersion(ctx context.Context, leaderboardID string, minVersion int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.VersionWaitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(versionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		version, err := s.redis.GetVersion(ctx, leaderboardID)
+		if err != nil && ctx.Err() == nil {
+			return 0, err
+		}
+		if version >= minVersion {
+			return version, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return version, domain.ErrVersionNotVisible
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetTopN returns the top N players from a leaderboard. On boards with a
+// participation threshold, provisional players are left out and ranks count
+// established players only.
 func (s *LeaderboardService) GetTopN(ctx context.Context, leaderboardID string, n int) ([]domain.LeaderboardEntry, error) {
-	// Validate limit
-	if n <= 0 {
-		n = s.config.DefaultLimit
+	return s.getTopN(ctx, leaderboardID, n, false)
+}
+
+// GetTopNWithProvisional returns the top N players including provisional ones, flagged
+func (s *LeaderboardService) GetTopNWithProvisional(ctx context.Context, leaderboardID string, n int) ([]domain.LeaderboardEntry, error) {
+	return s.getTopN(ctx, leaderboardID, n, true)
+}
+
+func (s *LeaderboardService) getTopN(ctx context.Context, leaderboardID string, n int, includeProvisional bool) ([]domain.LeaderboardEntry, error) {
+	n = s.ListLimit(n)
+
+	meta, err := s.readMeta(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
 	}
-	if n > s.config.MaxLimit {
-		n = s.config.MaxLimit
+	if meta.MinSubmissions > 0 && !includeProvisional {
+		return s.publicRange(ctx, meta, 0, n)
 	}
 
-	entries, err := s.redis.GetTopN(ctx, leaderboardID, n)
+	entries, err := s.redis.GetTopN(ctx, leaderboardID, n, meta.HigherIsBetter())
 	if err != nil {
 		return nil, fmt.Errorf("getting top n from redis: %w", err)
 	}
+	if err := s.annotateEntries(ctx, leaderboardID, meta.MinSubmissions, entries); err != nil {
+		return nil, err
+	}
 
 	return entries, nil
 }
 
 // GetPlayerRank returns a player's rank and score
 func (s *LeaderboardService) GetPlayerRank(ctx context.Context, leaderboardID, playerID string) (*domain.LeaderboardEntry, error) {
-	entry, err := s.redis.GetPlayerRank(ctx, leaderboardID, playerID)
+	meta, err := s.readMeta(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.redis.GetPlayerRank(ctx, leaderboardID, playerID, meta.HigherIsBetter())
 	if err != nil {
 		return nil, err
 	}
-	return entry, nil
+
+	entries := []domain.LeaderboardEntry{*entry}
+	if err := s.annotateEntries(ctx, leaderboardID, meta.MinSubmissions, entries); err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
 }
 
-// GetAroundPlayer returns players around a specific player's rank
-func (s *LeaderboardService) GetAroundPlayer(ctx context.Context, leaderboardID, playerID string, count int) ([]domain.LeaderboardEntry, error) {
+// ListLimit returns how many entries a top-N read asking for n returns at
+// most: the default limit when n is not positive, capped at the maximum
+func (s *LeaderboardService) ListLimit(n int) int {
+	if n <= 0 {
+		n = s.config.DefaultLimit
+	}
+	if n > s.config.MaxLimit {
+		n = s.config.MaxLimit
+	}
+	return n
+}
+
+// AroundCount returns how many players on each side GetAroundPlayer returns at
+// most when asked for count
+func AroundCount(count int) int {
 	if count <= 0 {
 		count = 5
 	}
 	if count > 50 {
 		count = 50
 	}
+	return count
+}
+
+// GetAroundPlayer returns players around a specific player's rank. Entries keep
+// their board rank and provisional players are flagged rather than left out.
+func (s *LeaderboardService) GetAroundPlayer(ctx context.Context, leaderboardID, playerID string, count int) ([]domain.LeaderboardEntry, error) {
+	count = AroundCount(count)
 
-	entries, err := s.redis.GetAroundPlayer(ctx, leaderboardID, playerID, count)
+	meta, err := s.readMeta(ctx, leaderboardID)
 	if err != nil {
 		return nil, err
 	}
+	entries, err := s.redis.GetAroundPlayer(ctx, leaderboardID, playerID, count, meta.HigherIsBetter())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.annotateEntries(ctx, leaderboardID, meta.MinSubmissions, entries); err != nil {
+		return nil, err
+	}
 	return entries, nil
 }
 
-// GetRange returns players within a specific rank range
+// GetRange returns players within a specific rank range. Like GetTopN, it
+// counts established players only on boards with a participation threshold.
 func (s *LeaderboardService) GetRange(ctx context.Context, leaderboardID string, start, end int) ([]domain.LeaderboardEntry, error) {
+	return s.getRange(ctx, leaderboardID, start, end, false)
+}
+
+// GetRangeWithProvisional returns players within a rank range including provisional ones, flagged
+func (s *LeaderboardService) GetRangeWithProvisional(ctx context.Context, leaderboardID string, start, end int) ([]domain.LeaderboardEntry, error) {
+	return s.getRange(ctx, leaderboardID, start, end, true)
+}
+
+func (s *LeaderboardService) getRange(ctx context.Context, leaderboardID string, start, end int, includeProvisional bool) ([]domain.LeaderboardEntry, error) {
 	// Validate range
 	if start < 0 {
 		start = 0
@@ -237,22 +784,48 @@ func (s *LeaderboardService) GetRange(ctx context.Context, leaderboardID string,
 		end = start + s.config.MaxLimit
 	}
 
-	entries, err := s.redis.GetRange(ctx, leaderboardID, start, end)
+	meta, err := s.readMeta(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+	if meta.MinSubmissions > 0 && !includeProvisional {
+		return s.publicRange(ctx, meta, start, end-start+1)
+	}
+
+	entries, err := s.redis.GetRange(ctx, leaderboardID, start, end, meta.HigherIsBetter())
 	if err != nil {
 		return nil, fmt.Errorf("getting range from redis: %w", err)
 	}
+	if err := s.annotateEntries(ctx, leaderboardID, meta.MinSubmissions, entries); err != nil {
+		return nil, err
+	}
 	return entries, nil
 }
 
-// GetCount returns the total number of players in a leaderboard
+// GetCount returns the total number of players in a leaderboard, not counting ghost entries
 func (s *LeaderboardService) GetCount(ctx context.Context, leaderboardID string) (int64, error) {
-	return s.redis.GetCount(ctx, leaderboardID)
+	count, err := s.redis.GetCount(ctx, leaderboardID)
+	if err != nil {
+		return 0, fmt.Errorf("getting count: %w", err)
+	}
+	ghosts, err := s.redis.GetGhostCount(ctx, leaderboardID)
+	if err != nil {
+		return 0, err
+	}
+	return count - ghosts, nil
 }
 
 // RemovePlayer removes a player from a leaderboard
 func (s *LeaderboardService) RemovePlayer(ctx context.Context, leaderboardID, playerID string) error {
+	if s.readOnly {
+		return domain.ErrReadOnlyReplica
+	}
+
+	old := s.removalStanding(ctx, leaderboardID, playerID)
+
 	// Remove from Redis
-	if err := s.redis.RemovePlayer(ctx, leaderboardID, playerID); err != nil {
+	version, err := s.redis.RemovePlayer(ctx, leaderboardID, playerID)
+	if err != nil {
 		return fmt.Errorf("removing from redis: %w", err)
 	}
 
@@ -262,8 +835,16 @@ func (s *LeaderboardService) RemovePlayer(ctx context.Context, leaderboardID, pl
 		s.logger.Warn("failed to remove player from postgres", "error", err)
 	}
 
-	// Broadcast update
-	s.broadcastUpdate(ctx, leaderboardID)
+	s.replicate(domain.ReplicatedChange{
+		Op:            domain.ReplicationOpRemove,
+		LeaderboardID: leaderboardID,
+		PlayerID:      playerID,
+		Version:       version,
+	})
+
+	// Broadcast the removal with the adjusted snapshot
+	s.broadcastRemoval(ctx, leaderboardID, playerID, old)
+	s.purgeEdge(leaderboardID)
 
 	return nil
 }
@@ -271,8 +852,62 @@ func (s *LeaderboardService) RemovePlayer(ctx context.Context, leaderboardID, pl
 // CreateLeaderboard creates a new leaderboard
 func (s *LeaderboardService) CreateLeaderboard(ctx context.Context, req domain.CreateLeaderboardRequest) (*domain.LeaderboardConfig, error) {
 	// Validate request
-	if req.ID == "" || req.Name == "" {
-		return nil, domain.ErrInvalidLeaderboard
+	if req.Name == "" {
+		return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "name", "is required")
+	}
+	if req.ID == "" {
+		id, err := s.ids.Generate(req.Name)
+		if err != nil {
+			return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "id", err.Error())
+		}
+		req.ID = id
+	} else if err := s.ids.Validate(req.ID); err != nil {
+		return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "id", err.Error())
+	}
+
+	// Convert to config with defaults
+	config := req.ToConfig()
+	if err := config.ValidateScoring(); err != nil {
+		return nil, err
+	}
+	if err := s.transformer.Validate(&config); err != nil {
+		return nil, err
+	}
+	if err := s.validateDerived(ctx, &config); err != nil {
+		return nil, err
+	}
+	if req.Script != "" {
+		// A script replaces the formula, so setting both is ambiguous
+		if config.ScoreFormula != "" {
+			return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "script", "cannot be combined with score_formula")
+		}
+		if err := s.scripts.Validate(req.Script); err != nil {
+			return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "script", err.Error())
+		}
+	}
+	if err := config.ValidateWindow(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateParticipation(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateQuota(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateTieBreak(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateSensitiveFields(); err != nil {
+		return nil, err
+	}
+	// Sensitive fields are never stored in the clear, so they need a key
+	if len(config.SensitiveFields) > 0 && s.cipher == nil {
+		return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "sensitive_fields", "requires an encryption key")
+	}
+	if config.Timezone != "" {
+		if _, err := domain.LoadLocation(config.Timezone); err != nil {
+			return nil, domain.NewValidationError(domain.ErrInvalidLeaderboard, "timezone", "is not a known time zone")
+		}
 	}
 
 	// Check if leaderboard exists
@@ -281,33 +916,129 @@ func (s *LeaderboardService) CreateLeaderboard(ctx context.Context, req domain.C
 		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
 	}
 	if exists {
-		return nil, domain.ErrLeaderboardExists
+		return s.replayCreate(ctx, req, config)
 	}
 
-	// Convert to config with defaults
-	config := req.ToConfig()
+	if req.ShadowID != "" {
+		if err := s.checkShadow(ctx, req.ID, req.ShadowID); err != nil {
+			return nil, err
+		}
+	}
+
+	config.CreatedAt = s.clock.Now()
+	config.UpdatedAt = config.CreatedAt
+
+	// Create the board and its script in one PostgreSQL transaction. Redis
+	// metadata is written before the commit, so a board is never committed
+	// without it, and removed again if the commit fails.
+	metaWritten := false
+	config, err = s.postgres.CreateLeaderboardTx(ctx, config, req.Script, func(stored domain.LeaderboardConfig) error {
+		if err := s.redis.SetLeaderboardMeta(ctx, stored); err != nil {
+			return fmt.Errorf("storing leaderboard meta in redis: %w", err)
+		}
+		metaWritten = true
+		return nil
+	})
+	if errors.Is(err, domain.ErrLeaderboardExists) {
+		// Another request created the same ID since the check above
+		return s.replayCreate(ctx, req, config)
+	}
+	if err != nil {
+		if metaWritten {
+			if err := s.redis.DeleteLeaderboardMeta(ctx, config.ID); err != nil {
+				s.logger.Warn("failed to remove leaderboard meta after failed create", "leaderboard_id", config.ID, "error", err)
+			}
+		}
+		return nil, fmt.Errorf("creating leaderboard: %w", err)
+	}
+	if config.ParentID != "" {
+		s.derived.invalidate(config.ParentID)
+	}
+
+	s.publishGlobal(domain.FeedEvent{
+		Type:          domain.FeedLeaderboardCreated,
+		LeaderboardID: config.ID,
+		Timestamp:     config.CreatedAt,
+	})
+
+	return &config, nil
+}
+
+// SetShadow attaches a shadow leaderboard that mirrors all submissions to leaderboardID.
+// An empty shadowID detaches the current shadow.
+func (s *LeaderboardService) SetShadow(ctx context.Context, leaderboardID, shadowID string) (*domain.LeaderboardConfig, error) {
+	if shadowID != "" {
+		if err := s.checkShadow(ctx, leaderboardID, shadowID); err != nil {
+			return nil, err
+		}
+	}
 
-	// Create in PostgreSQL
-	if err := s.postgres.CreateLeaderboard(ctx, config); err != nil {
-		return nil, fmt.Errorf("creating leaderboard in postgres: %w", err)
+	if err := s.postgres.SetShadow(ctx, leaderboardID, shadowID); err != nil {
+		return nil, err
 	}
 
-	// Store metadata in Redis
-	if err := s.redis.SetLeaderboardMeta(ctx, config); err != nil {
+	config, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.redis.SetLeaderboardMeta(ctx, *config); err != nil {
 		s.logger.Warn("failed to store leaderboard meta in redis", "error", err)
 	}
 
-	return &config, nil
+	return config, nil
+}
+
+// checkShadow validates shadowID as the shadow of leaderboardID. The shadow must
+// exist and neither board may take part in another shadow link that would form
+// a chain, since chained shadows would silently fan out writes.
+func (s *LeaderboardService) checkShadow(ctx context.Context, leaderboardID, shadowID string) error {
+	if shadowID == leaderboardID {
+		return domain.NewValidationError(domain.ErrInvalidLeaderboard, "shadow_id", "cannot be the leaderboard itself")
+	}
+
+	shadow, err := s.postgres.GetLeaderboard(ctx, shadowID)
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			return domain.NewValidationError(domain.ErrInvalidLeaderboard, "shadow_id", "must name an existing leaderboard")
+		}
+		return fmt.Errorf("getting shadow leaderboard: %w", err)
+	}
+	if shadow.ShadowID != "" {
+		return domain.NewValidationError(domain.ErrInvalidLeaderboard, "shadow_id", "must not have a shadow of its own")
+	}
+
+	shadowing, err := s.postgres.ShadowedBy(ctx, leaderboardID)
+	if err != nil {
+		return err
+	}
+	if len(shadowing) > 0 {
+		return domain.NewValidationError(domain.ErrInvalidLeaderboard, "shadow_id",
+			fmt.Sprintf("cannot be set on a leaderboard that is the shadow of %s", shadowing[0]))
+	}
+	return nil
 }
 
 // ListLeaderboards returns all leaderboards
 func (s *LeaderboardService) ListLeaderboards(ctx context.Context) ([]domain.LeaderboardConfig, error) {
-	return s.postgres.ListLeaderboards(ctx)
+	leaderboards, err := s.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range leaderboards {
+		s.withNextReset(&leaderboards[i])
+	}
+	return leaderboards, nil
 }
 
 // GetLeaderboard returns a leaderboard by ID
 func (s *LeaderboardService) GetLeaderboard(ctx context.Context, leaderboardID string) (*domain.LeaderboardConfig, error) {
-	return s.postgres.GetLeaderboard(ctx, leaderboardID)
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+	s.withNextReset(lbConfig)
+	return lbConfig, nil
 }
 
 // DeleteLeaderboard deletes a leaderboard
@@ -317,27 +1048,40 @@ func (s *LeaderboardService) DeleteLeaderboard(ctx context.Context, leaderboardI
 		s.logger.Warn("failed to delete leaderboard from redis", "error", err)
 	}
 
-	// Delete from PostgreSQL
-	if err := s.postgres.DeleteLeaderboard(ctx, leaderboardID); err != nil {
+	// Delete from PostgreSQL; boards it shadowed stop mirroring to it
+	detached, err := s.postgres.DeleteLeaderboard(ctx, leaderboardID)
+	if err != nil {
 		return fmt.Errorf("deleting leaderboard from postgres: %w", err)
 	}
+	for _, id := range detached {
+		s.refreshMeta(ctx, id)
+	}
+	s.stats.invalidate(leaderboardID)
+	s.derived.clear()
 
 	return nil
 }
 
-// ResetLeaderboard clears all scores from a leaderboard
+// ResetLeaderboard archives a leaderboard's standings as a finished season and
+// clears all of its scores
 func (s *LeaderboardService) ResetLeaderboard(ctx context.Context, leaderboardID string) error {
-	// Check if leaderboard exists
-	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if s.readOnly {
+		return domain.ErrReadOnlyReplica
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
 	if err != nil {
-		return fmt.Errorf("checking leaderboard existence: %w", err)
+		return err
 	}
-	if !exists {
-		return domain.ErrLeaderboardNotFound
+
+	// Keep the final standings; a board that cannot be archived is not reset
+	if err := s.archiveSeason(ctx, lbConfig); err != nil {
+		return err
 	}
 
 	// Reset in Redis
-	if err := s.redis.ResetLeaderboard(ctx, leaderboardID); err != nil {
+	version, err := s.redis.ResetLeaderboard(ctx, leaderboardID)
+	if err != nil {
 		return fmt.Errorf("resetting leaderboard in redis: %w", err)
 	}
 
@@ -345,36 +1089,54 @@ func (s *LeaderboardService) ResetLeaderboard(ctx context.Context, leaderboardID
 	if err := s.postgres.ResetLeaderboard(ctx, leaderboardID); err != nil {
 		return fmt.Errorf("resetting leaderboard in postgres: %w", err)
 	}
+	s.stats.invalidate(leaderboardID)
+
+	s.replicate(domain.ReplicatedChange{
+		Op:            domain.ReplicationOpReset,
+		LeaderboardID: leaderboardID,
+		Version:       version,
+	})
+
+	// Ghost entries outlive resets
+	if err := s.reseedGhosts(ctx, leaderboardID); err != nil {
+		return fmt.Errorf("reseeding ghosts: %w", err)
+	}
+
+	// The feed outlives resets too, and records them
+	resetEvent := domain.FeedEvent{
+		Type:          domain.FeedReset,
+		LeaderboardID: leaderboardID,
+		Timestamp:     s.clock.Now(),
+	}
+	if s.config.Feed.Enabled {
+		resetEvent.ID = s.appendFeed(ctx, resetEvent)
+	}
+	s.publishGlobal(resetEvent)
 
 	// Broadcast update
 	s.broadcastUpdate(ctx, leaderboardID)
+	s.purgeEdge(leaderboardID)
 
 	return nil
 }
 
-// GetStats returns statistics for a leaderboard
-func (s *LeaderboardService) GetStats(ctx context.Context, leaderboardID string) (*domain.LeaderboardStats, error) {
-	count, err := s.redis.GetCount(ctx, leaderboardID)
+// WithMeta wraps ranking entries with the leaderboard's configuration, its next
+// reset time, and the total player count
+func (s *LeaderboardService) WithMeta(ctx context.Context, leaderboardID string, entries []domain.LeaderboardEntry) (*domain.RankingResponse, error) {
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
 	if err != nil {
-		return nil, fmt.Errorf("getting count: %w", err)
-	}
-
-	stats := &domain.LeaderboardStats{
-		LeaderboardID: leaderboardID,
-		TotalPlayers:  count,
-	}
-
-	// Get top score
-	top, err := s.redis.GetTopN(ctx, leaderboardID, 1)
-	if err == nil && len(top) > 0 {
-		stats.TopScore = top[0].Score
+		return nil, err
 	}
+	s.withNextReset(lbConfig)
 
-	// Get bottom score
-	bottom, err := s.redis.GetBottomN(ctx, leaderboardID, 1)
-	if err == nil && len(bottom) > 0 {
-		stats.LowestScore = bottom[0].Score
+	count, err := s.GetCount(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
 	}
 
-	return stats, nil
+	return &domain.RankingResponse{
+		Leaderboard:  lbConfig,
+		TotalPlayers: count,
+		Entries:      entries,
+	}, nil
 }
@@ -0,0 +1,24 @@
+package service
+
+import "github.com/leaderboard-redis/internal/domain"
+
+// LegacyForwarder mirrors submissions to the leaderboard service being
+// migrated from
+type LegacyForwarder interface {
+	Forward(submission domain.ScoreSubmission)
+}
+
+// SetLegacyForwarder sets the forwarder every accepted submission is mirrored
+// to while dual writes are on
+func (s *LeaderboardService) SetLegacyForwarder(forwarder LegacyForwarder) {
+	s.legacy = forwarder
+}
+
+// forwardLegacy mirrors a submission as received, before any transform, so
+// the legacy service applies its own rules to it
+func (s *LeaderboardService) forwardLegacy(submission domain.ScoreSubmission) {
+	if s.legacy == nil {
+		return
+	}
+	s.legacy.Forward(submission)
+}
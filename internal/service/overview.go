@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// overviewTopN is how many leading entries are read per board to find the top
+// player past any ghosts
+const overviewTopN = 5
+
+// overviewCache holds the last computed overview
+type overviewCache struct {
+	mu       sync.Mutex
+	overview *domain.Overview
+	expires  time.Time
+}
+
+// recordActivity counts an applied submission for the overview
+func (s *LeaderboardService) recordActivity(ctx context.Context, leaderboardID string) {
+	if err := s.redis.RecordActivity(ctx, leaderboardID, s.clock.Now()); err != nil {
+		s.logger.Warn("failed to record leaderboard activity", "leaderboard_id", leaderboardID, "error", err)
+	}
+}
+
+// GetOverview summarizes every leaderboard: player counts, submissions in the
+// last hour, and the top player, read from Redis in one pipeline and cached for
+// the configured TTL. WebSocket subscriber counts are always current.
+func (s *LeaderboardService) GetOverview(ctx context.Context) (*domain.Overview, error) {
+	now := s.clock.Now()
+	s.overview.mu.Lock()
+	cached := s.overview.overview
+	if cached != nil && !now.Before(s.overview.expires) {
+		cached = nil
+	}
+	s.overview.mu.Unlock()
+
+	if cached == nil {
+		var err error
+		if cached, err = s.buildOverview(ctx, now); err != nil {
+			return nil, err
+		}
+		s.overview.mu.Lock()
+		s.overview.overview = cached
+		s.overview.expires = now.Add(s.config.OverviewCacheTTL)
+		s.overview.mu.Unlock()
+	}
+
+	// Copy the rows so live subscriber counts never touch the cached overview
+	overview := *cached
+	overview.Leaderboards = append([]domain.LeaderboardOverview(nil), cached.Leaderboards...)
+	if s.hub != nil {
+		overview.TotalConnections = s.hub.GetTotalConnections()
+		for i := range overview.Leaderboards {
+			overview.Leaderboards[i].Subscribers = s.hub.GetSubscriberCount(overview.Leaderboards[i].LeaderboardID)
+		}
+	}
+	return &overview, nil
+}
+
+// buildOverview reads the overview of every leaderboard
+func (s *LeaderboardService) buildOverview(ctx context.Context, now time.Time) (*domain.Overview, error) {
+	leaderboards, err := s.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.redis.GetBoardSummaries(ctx, leaderboards, overviewTopN, now)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &domain.Overview{
+		Leaderboards: make([]domain.LeaderboardOverview, len(leaderboards)),
+		GeneratedAt:  now,
+	}
+	for i, lb := range leaderboards {
+		summary := summaries[i]
+		row := domain.LeaderboardOverview{
+			LeaderboardID:       lb.ID,
+			Name:                lb.Name,
+			TotalPlayers:        summary.Members - summary.Ghosts,
+			SubmissionsLastHour: summary.LastHour,
+		}
+		for _, entry := range summary.Top {
+			if !domain.IsGhostID(entry.PlayerID) {
+				top := entry
+				row.TopPlayer = &top
+				break
+			}
+		}
+		overview.Leaderboards[i] = row
+	}
+	return overview, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// countSubmission records an accepted submission toward the board's participation threshold
+func (s *LeaderboardService) countSubmission(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string) {
+	if lbConfig.MinSubmissions <= 0 {
+		return
+	}
+	if _, err := s.redis.IncrementSubmissions(ctx, lbConfig.ID, playerID); err != nil {
+		s.logger.Warn("failed to count submission",
+			"leaderboard_id", lbConfig.ID,
+			"player_id", playerID,
+			"error", err,
+		)
+	}
+}
+
+// readMeta returns a leaderboard's Redis metadata, which read paths use for the
+// participation threshold and sort order. Unknown boards have no threshold and
+// rank higher scores first.
+func (s *LeaderboardService) readMeta(ctx context.Context, leaderboardID string) (*domain.LeaderboardConfig, error) {
+	meta, err := s.redis.GetLeaderboardMeta(ctx, leaderboardID)
+	if errors.Is(err, domain.ErrLeaderboardNotFound) {
+		return &domain.LeaderboardConfig{ID: leaderboardID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// annotateEntries flags ghost and provisional entries in ranking results and
+// attaches player profiles
+func (s *LeaderboardService) annotateEntries(ctx context.Context, leaderboardID string, threshold int64, entries []domain.LeaderboardEntry) error {
+	if err := s.markGhosts(ctx, leaderboardID, entries); err != nil {
+		return err
+	}
+	s.attachProfiles(ctx, entries)
+	return s.markProvisional(ctx, leaderboardID, threshold, entries)
+}
+
+// markProvisional flags entries whose players have fewer than threshold
+// submissions; ghost entries are never provisional
+func (s *LeaderboardService) markProvisional(ctx context.Context, leaderboardID string, threshold int64, entries []domain.LeaderboardEntry) error {
+	if threshold <= 0 || len(entries) == 0 {
+		return nil
+	}
+
+	playerIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsGhost {
+			playerIDs = append(playerIDs, entry.PlayerID)
+		}
+	}
+	counts, err := s.redis.GetSubmissionCounts(ctx, leaderboardID, playerIDs)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		entries[i].Provisional = !entries[i].IsGhost && counts[entries[i].PlayerID] < threshold
+	}
+	return nil
+}
+
+// publicRange returns up to n established players starting at the skip-th
+// established player, ranked among established players only. It pages through
+// the board, so deep offsets on boards with many provisional players cost more.
+func (s *LeaderboardService) publicRange(ctx context.Context, meta *domain.LeaderboardConfig, skip, n int) ([]domain.LeaderboardEntry, error) {
+	leaderboardID, threshold := meta.ID, meta.MinSubmissions
+	pageSize := max(2*n, 100)
+	entries := make([]domain.LeaderboardEntry, 0, n)
+	seen := 0
+
+	for offset := 0; len(entries) < n; offset += pageSize {
+		page, err := s.redis.GetRange(ctx, leaderboardID, offset, offset+pageSize-1, meta.HigherIsBetter())
+		if err != nil {
+			return nil, fmt.Errorf("getting range from redis: %w", err)
+		}
+		if err := s.annotateEntries(ctx, leaderboardID, threshold, page); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range page {
+			if entry.Provisional {
+				continue
+			}
+			seen++
+			if seen <= skip {
+				continue
+			}
+			entry.Rank = int64(seen)
+			entries = append(entries, entry)
+			if len(entries) == n {
+				break
+			}
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+	}
+	return entries, nil
+}
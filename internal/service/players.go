@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Profile field limits, matching the players table
+const (
+	maxPlayerIDLength = 64
+	maxUsernameLength = 255
+)
+
+// validateProfile checks the fields a stored profile needs
+func validateProfile(player domain.Player) error {
+	invalid := &domain.ValidationError{}
+	if player.ID == "" || len(player.ID) > maxPlayerIDLength {
+		invalid.Fields = append(invalid.Fields, domain.FieldError{Field: "id", Message: fmt.Sprintf("must be 1 to %d characters", maxPlayerIDLength)})
+	}
+	if player.Username == "" || len(player.Username) > maxUsernameLength {
+		invalid.Fields = append(invalid.Fields, domain.FieldError{Field: "username", Message: fmt.Sprintf("must be 1 to %d characters", maxUsernameLength)})
+	}
+	if len(invalid.Fields) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// CreatePlayer stores a player profile and caches the fields shown on leaderboard entries
+func (s *LeaderboardService) CreatePlayer(ctx context.Context, req domain.CreatePlayerRequest) (*domain.Player, error) {
+	now := s.clock.Now()
+	player := domain.Player{
+		ID:        req.ID,
+		Username:  req.Username,
+		Email:     req.Email,
+		AvatarURL: req.AvatarURL,
+		Metadata:  req.Metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := validateProfile(player); err != nil {
+		return nil, err
+	}
+
+	if err := s.postgres.CreatePlayer(ctx, player); err != nil {
+		return nil, err
+	}
+	s.cacheProfile(ctx, player)
+	return &player, nil
+}
+
+// GetPlayer returns a player profile
+func (s *LeaderboardService) GetPlayer(ctx context.Context, playerID string) (*domain.Player, error) {
+	return s.postgres.GetPlayer(ctx, playerID)
+}
+
+// UpdatePlayer changes the fields of a player profile set in the request
+func (s *LeaderboardService) UpdatePlayer(ctx context.Context, playerID string, req domain.UpdatePlayerRequest) (*domain.Player, error) {
+	current, err := s.postgres.GetPlayer(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	player := req.Apply(*current)
+	player.UpdatedAt = s.clock.Now()
+	if err := validateProfile(player); err != nil {
+		return nil, err
+	}
+
+	if err := s.postgres.UpdatePlayer(ctx, player); err != nil {
+		return nil, err
+	}
+	s.cacheProfile(ctx, player)
+	return &player, nil
+}
+
+// DeletePlayer removes a player profile. The player's scores stay on their
+// leaderboards, shown without a username.
+func (s *LeaderboardService) DeletePlayer(ctx context.Context, playerID string) error {
+	if err := s.postgres.DeletePlayer(ctx, playerID); err != nil {
+		return err
+	}
+	if err := s.redis.DeletePlayerInfo(ctx, playerID); err != nil {
+		s.logger.Warn("failed to drop cached player info", "player_id", playerID, "error", err)
+	}
+	return nil
+}
+
+// ListPlayers returns a page of player profiles in ID order
+func (s *LeaderboardService) ListPlayers(ctx context.Context, cursor string, limit int) (*domain.PlayerPage, error) {
+	afterID := ""
+	if cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", domain.ErrInvalidRequest)
+		}
+		afterID = string(raw)
+	}
+	if limit <= 0 {
+		limit = s.config.DefaultLimit
+	}
+	if limit > s.config.MaxLimit {
+		limit = s.config.MaxLimit
+	}
+
+	// One extra row tells whether another page follows
+	players, err := s.postgres.ListPlayers(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.PlayerPage{Players: players}
+	if len(players) > limit {
+		page.Players = players[:limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(page.Players[limit-1].ID))
+	}
+	return page, nil
+}
+
+// GetPlayerScores returns a player's score and rank on every leaderboard the
+// player is on, in leaderboard order
+func (s *LeaderboardService) GetPlayerScores(ctx context.Context, playerID string) ([]domain.PlayerScore, error) {
+	leaderboards, err := s.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.redis.GetPlayerRanks(ctx, leaderboards, playerID)
+}
+
+// RecordAdminAudit writes an entry to the admin audit log, stamped with the current time
+func (s *LeaderboardService) RecordAdminAudit(ctx context.Context, entry domain.AdminAuditEntry) error {
+	entry.CreatedAt = s.clock.Now()
+	return s.postgres.RecordAdminAudit(ctx, entry)
+}
+
+// cacheProfile writes a profile's display fields to the player info cache
+func (s *LeaderboardService) cacheProfile(ctx context.Context, player domain.Player) {
+	if err := s.redis.SetPlayerInfo(ctx, player.Info()); err != nil {
+		s.logger.Warn("failed to cache player info", "player_id", player.ID, "error", err)
+	}
+}
+
+// attachProfiles fills in the username and avatar of ranked players from the
+// player info cache. Players missing from the cache are loaded from PostgreSQL
+// and cached; players without a profile are cached with an empty username, so
+// they are not looked up again until the entry expires. Failures leave the
+// entries without profile fields.
+func (s *LeaderboardService) attachProfiles(ctx context.Context, entries []domain.LeaderboardEntry) {
+	playerIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsGhost {
+			playerIDs = append(playerIDs, entry.PlayerID)
+		}
+	}
+	if len(playerIDs) == 0 {
+		return
+	}
+
+	infos, err := s.redis.GetPlayerInfos(ctx, playerIDs)
+	if err != nil {
+		s.logger.Warn("failed to get cached player info", "error", err)
+		return
+	}
+
+	var missing []string
+	for _, playerID := range playerIDs {
+		if _, ok := infos[playerID]; !ok {
+			missing = append(missing, playerID)
+		}
+	}
+	if len(missing) > 0 {
+		profiles, err := s.postgres.GetPlayers(ctx, missing)
+		if err != nil {
+			s.logger.Warn("failed to load player profiles", "error", err)
+		} else {
+			loaded := make([]domain.PlayerInfo, 0, len(missing))
+			for _, playerID := range missing {
+				info := domain.PlayerInfo{ID: playerID}
+				if profile, ok := profiles[playerID]; ok {
+					info = profile.Info()
+				}
+				infos[playerID] = info
+				loaded = append(loaded, info)
+			}
+			if err := s.redis.SetPlayerInfos(ctx, loaded); err != nil {
+				s.logger.Warn("failed to cache player info", "error", err)
+			}
+		}
+	}
+
+	for i := range entries {
+		if entries[i].IsGhost {
+			continue
+		}
+		if info, ok := infos[entries[i].PlayerID]; ok {
+			entries[i].Username = info.Username
+			entries[i].AvatarURL = info.AvatarURL
+		}
+	}
+}
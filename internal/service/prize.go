@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// errEnoughEntries stops reading standings once a payout has every player it needs
+var errEnoughEntries = errors.New("enough entries")
+
+// PayoutPublisher publishes computed payout reports, e.g. to Kafka
+type PayoutPublisher interface {
+	PublishPayouts(ctx context.Context, report *domain.PayoutReport) error
+}
+
+// SetPayoutPublisher sets where computed payouts are published
+func (s *LeaderboardService) SetPayoutPublisher(publisher PayoutPublisher) {
+	s.payouts = publisher
+}
+
+// prizeCollector gathers the players a prize structure needs, best first and
+// skipping ghosts: the first need players and everyone tied with the last of them
+type prizeCollector struct {
+	need    int64
+	entries []domain.LeaderboardEntry
+}
+
+// add takes the next entry in rank order and reports whether more are needed
+func (c *prizeCollector) add(entry domain.LeaderboardEntry) bool {
+	if entry.IsGhost {
+		return true
+	}
+	if n := int64(len(c.entries)); n >= c.need && entry.Score != c.entries[n-1].Score {
+		return false
+	}
+	c.entries = append(c.entries, entry)
+	return true
+}
+
+// ComputePayouts distributes a prize structure over a leaderboard's standings,
+// taken from a frozen snapshot when one is named and from the live board
+// otherwise. The report is published when a publisher is configured.
+func (s *LeaderboardService) ComputePayouts(ctx context.Context, leaderboardID string, req domain.PayoutRequest) (*domain.PayoutReport, error) {
+	structure := req.PrizeStructure
+	if req.Structure != "" {
+		configured, ok := s.config.Prizes.Structures[req.Structure]
+		if !ok || len(structure.Brackets) > 0 {
+			return nil, domain.ErrInvalidRequest
+		}
+		structure = prizeStructure(configured)
+	}
+	if err := structure.Validate(); err != nil {
+		return nil, err
+	}
+	if structure.TiePolicy == "" {
+		structure.TiePolicy = domain.TiePolicySplit
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.PayoutReport{
+		LeaderboardID: leaderboardID,
+		Structure:     req.Structure,
+		Snapshot:      req.Snapshot,
+		TiePolicy:     structure.TiePolicy,
+		ComputedAt:    s.clock.Now(),
+	}
+	collector := &prizeCollector{need: structure.MaxRank()}
+	if req.Snapshot != "" {
+		report.Version, err = s.collectSnapshot(ctx, leaderboardID, req.Snapshot, collector)
+	} else {
+		report.Version, err = s.collectLive(ctx, lbConfig, collector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Payouts = structure.Distribute(collector.entries)
+	for _, payout := range report.Payouts {
+		report.Total += payout.Amount
+	}
+
+	if s.payouts != nil {
+		if err := s.payouts.PublishPayouts(ctx, report); err != nil {
+			s.logger.Warn("failed to publish payouts", "leaderboard_id", leaderboardID, "error", err)
+		} else {
+			report.Published = true
+		}
+	}
+	return report, nil
+}
+
+// collectSnapshot feeds a frozen snapshot's entries to the collector and
+// returns the snapshot's version
+func (s *LeaderboardService) collectSnapshot(ctx context.Context, leaderboardID, name string, collector *prizeCollector) (int64, error) {
+	snapshot, err := s.postgres.GetSnapshot(ctx, leaderboardID, name)
+	if err != nil {
+		return 0, err
+	}
+	err = s.postgres.EachSnapshotEntry(ctx, snapshot.ID, func(entry domain.LeaderboardEntry) error {
+		if !collector.add(entry) {
+			return errEnoughEntries
+		}
+		return nil
+	})
+	if err != nil && err != errEnoughEntries {
+		return 0, err
+	}
+	return snapshot.Version, nil
+}
+
+// collectLive feeds the live standings to the collector from a frozen copy, so
+// the payout reflects a single version of the board, and returns that version
+func (s *LeaderboardService) collectLive(ctx context.Context, lbConfig *domain.LeaderboardConfig, collector *prizeCollector) (int64, error) {
+	leaderboardID := lbConfig.ID
+	frozen, err := s.redis.FreezeStandings(ctx, leaderboardID, lbConfig.HigherIsBetter())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := s.redis.DropFrozen(context.WithoutCancel(ctx), frozen); err != nil {
+			s.logger.Warn("failed to drop frozen standings", "leaderboard_id", leaderboardID, "error", err)
+		}
+	}()
+
+	for pos := int64(0); pos < frozen.Count; pos += snapshotPageSize {
+		page, err := s.redis.ReadFrozen(ctx, frozen, pos, pos+snapshotPageSize-1)
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range page {
+			if !collector.add(entry) {
+				return frozen.Version, nil
+			}
+		}
+	}
+	return frozen.Version, nil
+}
+
+// prizeStructure converts a configured prize structure
+func prizeStructure(cfg config.PrizeStructureConfig) domain.PrizeStructure {
+	structure := domain.PrizeStructure{TiePolicy: domain.TiePolicy(cfg.TiePolicy)}
+	for _, b := range cfg.Brackets {
+		structure.Brackets = append(structure.Brackets, domain.PrizeBracket{
+			FromRank: b.FromRank,
+			ToRank:   b.ToRank,
+			Amount:   b.Amount,
+			Label:    b.Label,
+		})
+	}
+	return structure
+}
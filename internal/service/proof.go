@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// WithProofs attaches the proof behind each entry's standing score, where one was submitted
+func (s *LeaderboardService) WithProofs(ctx context.Context, leaderboardID string, entries []domain.LeaderboardEntry) error {
+	playerIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		// Pending entries carry submitted, not standing, scores
+		if !entry.IsGhost && entry.PendingID == 0 {
+			playerIDs = append(playerIDs, entry.PlayerID)
+		}
+	}
+
+	proofs, err := s.redis.GetProofs(ctx, leaderboardID, playerIDs)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].PendingID == 0 {
+			entries[i].Proof = proofs[entries[i].PlayerID]
+		}
+	}
+	return nil
+}
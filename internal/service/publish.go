@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// PublishedStandings renders a leaderboard's public top n, as GetTopN returns
+// it, for the static publisher
+func (s *LeaderboardService) PublishedStandings(ctx context.Context, lbConfig *domain.LeaderboardConfig, n int) (*domain.PublishedStandings, error) {
+	version, err := s.redis.GetVersion(ctx, lbConfig.ID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.GetTopN(ctx, lbConfig.ID, n)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.GetCount(ctx, lbConfig.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PublishedStandings{
+		LeaderboardID: lbConfig.ID,
+		Name:          lbConfig.Name,
+		SortOrder:     lbConfig.SortOrder,
+		ScoreUnit:     lbConfig.ScoreUnit,
+		Version:       version,
+		TotalPlayers:  total,
+		Entries:       entries,
+		PublishedAt:   s.clock.Now(),
+	}, nil
+}
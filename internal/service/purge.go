@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// EdgePurger purges CDN copies of a leaderboard's reads
+type EdgePurger interface {
+	Invalidate(leaderboardID string)
+	// TopN is how deep in the standings a change must reach to purge
+	TopN() int
+}
+
+// SetEdgePurger sets the purger notified when a board's top entries change or
+// it is reset
+func (s *LeaderboardService) SetEdgePurger(purger EdgePurger) {
+	s.purger = purger
+}
+
+// purgeEdge queues a leaderboard's CDN paths for purging, if purging is configured
+func (s *LeaderboardService) purgeEdge(leaderboardID string) {
+	if s.purger == nil {
+		return
+	}
+	s.purger.Invalidate(leaderboardID)
+}
+
+// purgeIfTopChanged purges a leaderboard when an applied change moved a player
+// into, out of, or within its top entries
+func (s *LeaderboardService) purgeIfTopChanged(ctx context.Context, change scoreChange) {
+	if s.purger == nil || !change.changed {
+		return
+	}
+	topN := int64(s.purger.TopN())
+	if change.oldRank > 0 && change.oldRank <= topN {
+		s.purgeEdge(change.leaderboardID)
+		return
+	}
+
+	entry, err := s.redis.GetPlayerRank(ctx, change.leaderboardID, change.playerID, change.config.HigherIsBetter())
+	if err != nil {
+		// Without the new rank a stale CDN copy is the worse outcome
+		s.logger.Warn("failed to get rank for cdn purge", "leaderboard_id", change.leaderboardID, "error", err)
+		s.purgeEdge(change.leaderboardID)
+		return
+	}
+	if entry.Rank <= topN {
+		s.purgeEdge(change.leaderboardID)
+	}
+}
+
+// purgeIfTopRemoved purges a leaderboard when removed players held top entries
+func (s *LeaderboardService) purgeIfTopRemoved(leaderboardID string, removed []domain.LeaderboardEntry) {
+	if s.purger == nil {
+		return
+	}
+	topN := int64(s.purger.TopN())
+	for _, entry := range removed {
+		if entry.Rank <= topN {
+			s.purgeEdge(leaderboardID)
+			return
+		}
+	}
+}
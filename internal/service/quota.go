@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// quotaPeriod returns the bounds of a board's current quota period, both zero
+// for a quota that never renews
+func (s *LeaderboardService) quotaPeriod(lbConfig *domain.LeaderboardConfig) (time.Time, time.Time) {
+	schedule := s.schedule.For(lbConfig)
+	now := s.clock.Now()
+	start, ok := schedule.PeriodStart(lbConfig.QuotaPeriod, now)
+	if !ok {
+		return time.Time{}, time.Time{}
+	}
+	end, _ := schedule.NextReset(lbConfig.QuotaPeriod, now)
+	return start, end
+}
+
+// useAttempt counts a submission against its player's attempt quota and
+// returns the attempts left, nil on boards without a quota. A submission
+// beyond the quota fails with a *domain.QuotaExceededError, or reports
+// ignored on boards that drop such submissions silently.
+func (s *LeaderboardService) useAttempt(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string) (left *int, ignored bool, err error) {
+	if lbConfig.AttemptQuota <= 0 {
+		return nil, false, nil
+	}
+	start, end := s.quotaPeriod(lbConfig)
+	allowed, used, err := s.redis.UseAttempt(ctx, lbConfig.ID, playerID, lbConfig.AttemptQuota, start, end)
+	if err != nil {
+		return nil, false, err
+	}
+	remaining := max(lbConfig.AttemptQuota-used, 0)
+	if allowed {
+		return &remaining, false, nil
+	}
+	if lbConfig.QuotaExceeded == domain.QuotaExceededIgnore {
+		return &remaining, true, nil
+	}
+	quotaErr := &domain.QuotaExceededError{LeaderboardID: lbConfig.ID, Quota: lbConfig.AttemptQuota}
+	if !end.IsZero() {
+		quotaErr.ResetsAt = &end
+	}
+	return nil, false, quotaErr
+}
+
+// checkAttempt reports the attempts a player has left without using one, nil
+// on boards without a quota. It fails with a *domain.QuotaExceededError when
+// none are left, whether the board rejects or ignores such submissions.
+func (s *LeaderboardService) checkAttempt(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string) (*int, error) {
+	if lbConfig.AttemptQuota <= 0 {
+		return nil, nil
+	}
+	start, end := s.quotaPeriod(lbConfig)
+	used, err := s.redis.AttemptsUsed(ctx, lbConfig.ID, playerID, start)
+	if err != nil {
+		return nil, err
+	}
+	remaining := max(lbConfig.AttemptQuota-used, 0)
+	if remaining > 0 {
+		return &remaining, nil
+	}
+	quotaErr := &domain.QuotaExceededError{LeaderboardID: lbConfig.ID, Quota: lbConfig.AttemptQuota}
+	if !end.IsZero() {
+		quotaErr.ResetsAt = &end
+	}
+	return &remaining, quotaErr
+}
+
+// refundAttempt gives back the attempt of a submission that failed after
+// using it
+func (s *LeaderboardService) refundAttempt(ctx context.Context, lbConfig *domain.LeaderboardConfig, playerID string) {
+	if lbConfig.AttemptQuota <= 0 {
+		return
+	}
+	start, _ := s.quotaPeriod(lbConfig)
+	if err := s.redis.RefundAttempt(ctx, lbConfig.ID, playerID, start); err != nil {
+		s.logger.Warn("failed to refund attempt", "leaderboard_id", lbConfig.ID, "player_id", playerID, "error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/websocket"
+)
+
+// ApplyReplicatedChanges applies changes streamed from the primary region.
+// Changes carry absolute scores, so they are written as-is regardless of the
+// leaderboard's update mode, and they are never republished.
+func (s *LeaderboardService) ApplyReplicatedChanges(ctx context.Context, changes []domain.ReplicatedChange) domain.BatchResult {
+	var result domain.BatchResult
+	var leaderboardIDs []string
+	touched := make(map[string]bool)
+
+	for i, change := range changes {
+		if err := s.applyReplicatedChange(ctx, change); err != nil {
+			s.logger.Error("failed to apply replicated change",
+				"op", change.Op,
+				"leaderboard_id", change.LeaderboardID,
+				"player_id", change.PlayerID,
+				"region", change.Region,
+				"error", err,
+			)
+			result.Failed = append(result.Failed, domain.BatchFailure{
+				Index:         i,
+				PlayerID:      change.PlayerID,
+				LeaderboardID: change.LeaderboardID,
+				Error:         err.Error(),
+				Err:           err,
+			})
+			continue
+		}
+		result.Accepted++
+		if !touched[change.LeaderboardID] {
+			touched[change.LeaderboardID] = true
+			leaderboardIDs = append(leaderboardIDs, change.LeaderboardID)
+		}
+	}
+
+	for _, leaderboardID := range leaderboardIDs {
+		s.broadcastUpdate(ctx, leaderboardID)
+		s.purgeEdge(leaderboardID)
+	}
+
+	return result
+}
+
+// applyReplicatedChange writes a single replicated change to Redis and, for
+// removals and resets, PostgreSQL
+func (s *LeaderboardService) applyReplicatedChange(ctx context.Context, change domain.ReplicatedChange) error {
+	if change.LeaderboardID == "" {
+		return domain.ErrInvalidRequest
+	}
+
+	// Leaderboard definitions are managed per region and are not replicated
+	exists, err := s.postgres.LeaderboardExists(ctx, change.LeaderboardID)
+	if err != nil {
+		return fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return domain.ErrLeaderboardNotFound
+	}
+
+	switch change.Op {
+	case domain.ReplicationOpSet:
+		if change.PlayerID == "" {
+			return domain.ErrInvalidRequest
+		}
+		if _, err := s.redis.SetScore(ctx, change.LeaderboardID, change.PlayerID, change.Score, change.Tie); err != nil {
+			return fmt.Errorf("setting score in redis: %w", err)
+		}
+	case domain.ReplicationOpRemove:
+		if change.PlayerID == "" {
+			return domain.ErrInvalidRequest
+		}
+		old := s.removalStanding(ctx, change.LeaderboardID, change.PlayerID)
+		if _, err := s.redis.RemovePlayer(ctx, change.LeaderboardID, change.PlayerID); err != nil {
+			return fmt.Errorf("removing from redis: %w", err)
+		}
+		if err := s.postgres.RemovePlayer(ctx, change.LeaderboardID, change.PlayerID); err != nil {
+			s.logger.Warn("failed to remove player from postgres", "error", err)
+		}
+		// The adjusted snapshot follows once the whole batch is applied
+		if s.hub != nil {
+			s.hub.BroadcastBatch([]*websocket.Message{playerRemovedMessage(change.LeaderboardID, change.PlayerID, old)})
+		}
+	case domain.ReplicationOpReset:
+		if _, err := s.redis.ResetLeaderboard(ctx, change.LeaderboardID); err != nil {
+			return fmt.Errorf("resetting leaderboard in redis: %w", err)
+		}
+		if err := s.postgres.ResetLeaderboard(ctx, change.LeaderboardID); err != nil {
+			return fmt.Errorf("resetting leaderboard in postgres: %w", err)
+		}
+		s.stats.invalidate(change.LeaderboardID)
+	default:
+		return domain.ErrInvalidRequest
+	}
+
+	// Carry the primary's version so read-your-writes tokens issued there are honored here
+	if change.Version > 0 {
+		if _, err := s.redis.AdvanceVersion(ctx, change.LeaderboardID, change.Version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// holdForReview stores a submission that beat the review threshold instead of applying it
+func (s *LeaderboardService) holdForReview(ctx context.Context, lbConfig *domain.LeaderboardConfig, submission domain.ScoreSubmission, score int64) (int64, error) {
+	pending := domain.PendingScore{
+		LeaderboardID: lbConfig.ID,
+		PlayerID:      submission.PlayerID,
+		Score:         score,
+		RawScore:      submission.Score,
+		GameID:        submission.GameID,
+		Metadata:      s.sealMetadata(lbConfig, submission.Metadata),
+		Proof:         submission.Proof(),
+		Status:        domain.ReviewPending,
+		SubmittedAt:   s.clock.Now(),
+	}
+	if err := s.postgres.CreatePendingScore(ctx, &pending); err != nil {
+		return 0, err
+	}
+
+	// PostgreSQL holds the review queue; the Redis copy only backs include=pending
+	if err := s.redis.AddPendingScore(ctx, pending); err != nil {
+		s.logger.Warn("failed to list pending score", "pending_id", pending.ID, "error", err)
+	}
+
+	s.logger.Info("score held for review",
+		"pending_id", pending.ID,
+		"leaderboard_id", pending.LeaderboardID,
+		"player_id", pending.PlayerID,
+		"score", pending.Score,
+	)
+	return pending.ID, nil
+}
+
+// ListPendingScores returns a page of a leaderboard's review queue, oldest
+// first, and the cursor of the next page
+func (s *LeaderboardService) ListPendingScores(ctx context.Context, leaderboardID string, status domain.ReviewStatus, cursor string, limit int) ([]domain.PendingScore, string, error) {
+	if status == "" {
+		status = domain.ReviewPending
+	}
+	if !status.IsValid() {
+		return nil, "", domain.ErrInvalidRequest
+	}
+	page, err := s.keysetPage(cursor, limit, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, "", fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, "", domain.ErrLeaderboardNotFound
+	}
+
+	scores, next, err := s.postgres.ListPendingScores(ctx, leaderboardID, status, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return scores, encodeKeyset(next), nil
+}
+
+// ApprovePendingScore applies a held submission to its leaderboard and returns
+// the reviewed record with the leaderboard version that includes it
+func (s *LeaderboardService) ApprovePendingScore(ctx context.Context, id int64, review domain.ReviewRequest) (*domain.PendingScore, int64, error) {
+	if s.readOnly {
+		return nil, 0, domain.ErrReadOnlyReplica
+	}
+
+	pending, err := s.reviewPendingScore(ctx, id, domain.ReviewApproved, review)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, pending.LeaderboardID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting leaderboard config: %w", err)
+	}
+
+	change := scoreChange{
+		leaderboardID: pending.LeaderboardID,
+		playerID:      pending.PlayerID,
+		config:        lbConfig,
+	}
+	// The submission is replayed with its metadata in the clear; the record
+	// returned to the reviewer stays sealed
+	submission := pending.Submission()
+	submission.Metadata = s.openMetadata(pending.LeaderboardID, submission.Metadata)
+	change, err = s.commitScore(ctx, change, submission, pending.Score, "verified")
+	if err != nil {
+		return nil, 0, err
+	}
+	s.broadcastChanges(ctx, []string{pending.LeaderboardID}, []scoreChange{change})
+
+	return pending, change.version, nil
+}
+
+// RejectPendingScore discards a held submission
+func (s *LeaderboardService) RejectPendingScore(ctx context.Context, id int64, review domain.ReviewRequest) (*domain.PendingScore, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+	return s.reviewPendingScore(ctx, id, domain.ReviewRejected, review)
+}
+
+// reviewPendingScore records a review decision and drops the submission from
+// the Redis pending list
+func (s *LeaderboardService) reviewPendingScore(ctx context.Context, id int64, status domain.ReviewStatus, review domain.ReviewRequest) (*domain.PendingScore, error) {
+	pending, err := s.postgres.GetPendingScore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Status != domain.ReviewPending {
+		return nil, domain.ErrAlreadyReviewed
+	}
+
+	reviewedAt := s.clock.Now()
+	if err := s.postgres.ReviewPendingScore(ctx, id, status, review, reviewedAt); err != nil {
+		return nil, err
+	}
+	pending.Status = status
+	pending.ReviewedAt = &reviewedAt
+	pending.Reviewer = review.Reviewer
+	pending.Reason = review.Reason
+
+	if err := s.redis.RemovePendingScore(ctx, *pending); err != nil {
+		s.logger.Warn("failed to unlist pending score", "pending_id", id, "error", err)
+	}
+
+	s.logger.Info("pending score reviewed",
+		"pending_id", id,
+		"leaderboard_id", pending.LeaderboardID,
+		"player_id", pending.PlayerID,
+		"status", status,
+		"reviewer", review.Reviewer,
+	)
+	return pending, nil
+}
+
+// MergePending adds the best submissions awaiting review to a top-n ranking.
+// A player appears once, with whichever of their entries ranks higher, and
+// ranks are positions in the merged list.
+func (s *LeaderboardService) MergePending(ctx context.Context, leaderboardID string, entries []domain.LeaderboardEntry, n int) ([]domain.LeaderboardEntry, error) {
+	meta, err := s.redis.GetLeaderboardMeta(ctx, leaderboardID)
+	if errors.Is(err, domain.ErrLeaderboardNotFound) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	higherIsBetter := meta.HigherIsBetter()
+
+	n = max(n, len(entries))
+	pending, err := s.redis.GetTopPending(ctx, leaderboardID, n, higherIsBetter)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return entries, nil
+	}
+
+	// Live entries come first so they keep their place on ties
+	merged := append(append([]domain.LeaderboardEntry{}, entries...), pending...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		if higherIsBetter {
+			return merged[i].Score > merged[j].Score
+		}
+		return merged[i].Score < merged[j].Score
+	})
+
+	result := make([]domain.LeaderboardEntry, 0, n)
+	seen := make(map[string]bool, len(merged))
+	for _, entry := range merged {
+		if seen[entry.PlayerID] {
+			continue
+		}
+		seen[entry.PlayerID] = true
+		entry.Rank = int64(len(result) + 1)
+		result = append(result, entry)
+		if len(result) == n {
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// Initial size of a script's value stack; it grows up to RegistryMaxSize
+const scriptRegistrySize = 1024
+
+// Strings shorter than this are not charged to a run's allocation budget;
+// the instruction cap bounds how many of them a run can create
+const scriptMinChargedString = 32
+
+// Base library functions removed from the script sandbox: loading code,
+// reaching the file system, and inspecting or tampering with the runtime
+var unsafeScriptGlobals = []string{
+	"dofile", "loadfile", "load", "loadstring", "require", "module",
+	"collectgarbage", "getfenv", "setfenv", "print", "newproxy", "_printregs",
+}
+
+// scriptEngine compiles and runs Lua scoring scripts. Compiled versions are
+// cached; versions never change once stored, and the key includes the board's
+// creation time so a recreated board never reuses an old board's scripts.
+type scriptEngine struct {
+	config *config.ScriptConfig
+
+	mu       sync.RWMutex
+	compiled map[string]*lua.FunctionProto
+}
+
+func newScriptEngine(cfg *config.ScriptConfig) *scriptEngine {
+	return &scriptEngine{
+		config:   cfg,
+		compiled: make(map[string]*lua.FunctionProto),
+	}
+}
+
+// compile parses a script, rejecting sources over the size limit
+func (e *scriptEngine) compile(name, source string) (*lua.FunctionProto, error) {
+	if e.config.MaxSourceBytes > 0 && len(source) > e.config.MaxSourceBytes {
+		return nil, fmt.Errorf("script longer than %d bytes", e.config.MaxSourceBytes)
+	}
+	chunk, err := parse.Parse(strings.NewReader(source), name)
+	if err != nil {
+		return nil, err
+	}
+	return lua.Compile(chunk, name)
+}
+
+// Validate checks that a script compiles within the sandbox's limits
+func (e *scriptEngine) Validate(source string) error {
+	if strings.TrimSpace(source) == "" {
+		return fmt.Errorf("empty script")
+	}
+	_, err := e.compile("script", source)
+	return err
+}
+
+// scriptKey identifies a leaderboard's active script version in the cache
+func scriptKey(lbConfig *domain.LeaderboardConfig) string {
+	return fmt.Sprintf("%s@%d@%d", lbConfig.ID, lbConfig.ScriptVersion, lbConfig.CreatedAt.UnixNano())
+}
+
+// cached returns a compiled script version without touching storage
+func (e *scriptEngine) cached(key string) (*lua.FunctionProto, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	proto, ok := e.compiled[key]
+	return proto, ok
+}
+
+// store compiles a script version and caches it under key
+func (e *scriptEngine) store(key, source string) (*lua.FunctionProto, error) {
+	proto, err := e.compile(key, source)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.compiled[key] = proto
+	e.mu.Unlock()
+	return proto, nil
+}
+
+// Run executes a compiled script for a submission in a fresh sandboxed state.
+// The script sees score, player_id, leaderboard_id and metadata as globals and
+// must return the number to rank.
+func (e *scriptEngine) Run(ctx context.Context, proto *lua.FunctionProto, submission domain.ScoreSubmission) (int64, error) {
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   e.config.CallStackSize,
+		RegistrySize:    scriptRegistrySize,
+		RegistryMaxSize: e.config.RegistryMaxSize,
+	})
+	defer L.Close()
+
+	if e.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout)
+		defer cancel()
+	}
+	budget := newScriptBudget(ctx, L, e.config)
+	openScriptLibs(L, budget)
+	L.SetContext(budget)
+
+	L.SetGlobal("score", lua.LNumber(submission.Score))
+	L.SetGlobal("player_id", lua.LString(submission.PlayerID))
+	L.SetGlobal("leaderboard_id", lua.LString(submission.LeaderboardID))
+	L.SetGlobal("metadata", toLua(L, submission.Metadata))
+
+	L.Push(L.NewFunctionFromProto(proto))
+	if err := L.PCall(0, 1, nil); err != nil {
+		if budget.err != nil {
+			return 0, fmt.Errorf("%w: %v", domain.ErrInvalidScore, budget.err)
+		}
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("%w: script exceeded its %s time limit", domain.ErrInvalidScore, e.config.Timeout)
+		}
+		// Report the Lua error without its stack traceback
+		var apiErr *lua.ApiError
+		if errors.As(err, &apiErr) && apiErr.Object != nil {
+			return 0, fmt.Errorf("%w: script: %s", domain.ErrInvalidScore, apiErr.Object.String())
+		}
+		return 0, fmt.Errorf("%w: script: %v", domain.ErrInvalidScore, err)
+	}
+
+	result, ok := L.Get(-1).(lua.LNumber)
+	if !ok {
+		return 0, fmt.Errorf("%w: script must return a number, got %s", domain.ErrInvalidScore, L.Get(-1).Type())
+	}
+	value := math.Round(float64(result))
+	if math.IsNaN(value) || value >= math.MaxInt64 || value < math.MinInt64 {
+		return 0, domain.ErrInvalidScore
+	}
+	return int64(value), nil
+}
+
+// scriptBudget caps the instructions a run executes and the bytes of strings
+// it creates. gopher-lua polls its context's Done before every instruction, so
+// the budget wraps the run's context and checks itself there: it charges each
+// new string found in the running function's registers, which is where every
+// string a script builds lands, and reports itself done once a cap is passed.
+// Done is only ever called on the goroutine running the script.
+type scriptBudget struct {
+	context.Context
+	L      *lua.LState
+	config *config.ScriptConfig
+
+	steps   int
+	alloc   int
+	charged map[*byte]struct{}
+	err     error
+}
+
+// closedScriptDone is returned by Done once a budget is exhausted
+var closedScriptDone = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+func newScriptBudget(ctx context.Context, L *lua.LState, cfg *config.ScriptConfig) *scriptBudget {
+	return &scriptBudget{
+		Context: ctx,
+		L:       L,
+		config:  cfg,
+		charged: make(map[*byte]struct{}),
+	}
+}
+
+// Done counts an instruction and charges the strings it may have created
+func (b *scriptBudget) Done() <-chan struct{} {
+	if b.err == nil {
+		b.step()
+	}
+	if b.err != nil {
+		return closedScriptDone
+	}
+	return b.Context.Done()
+}
+
+// Err reports an exhausted budget before the wrapped context's error
+func (b *scriptBudget) Err() error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.Context.Err()
+}
+
+func (b *scriptBudget) step() {
+	b.steps++
+	if b.config.MaxInstructions > 0 && b.steps > b.config.MaxInstructions {
+		b.err = fmt.Errorf("script exceeded its %d instruction limit", b.config.MaxInstructions)
+		return
+	}
+	top := b.L.GetTop()
+	for i := 1; i <= top; i++ {
+		if str, ok := b.L.Get(i).(lua.LString); ok && len(str) >= scriptMinChargedString {
+			data := unsafe.StringData(string(str))
+			if _, seen := b.charged[data]; !seen {
+				b.charged[data] = struct{}{}
+				b.charge(len(str))
+			}
+		}
+	}
+}
+
+// charge adds bytes to the allocation budget
+func (b *scriptBudget) charge(bytes int) {
+	b.alloc += bytes
+	if b.config.MaxAllocBytes > 0 && b.alloc > b.config.MaxAllocBytes {
+		b.err = fmt.Errorf("script exceeded its %d byte allocation limit", b.config.MaxAllocBytes)
+	}
+}
+
+// fits reports whether a string of the given size can still be created, so
+// library calls that build one in a single step are refused up front
+func (b *scriptBudget) fits(bytes int) bool {
+	return b.config.MaxAllocBytes <= 0 || b.alloc+bytes <= b.config.MaxAllocBytes
+}
+
+// openScriptLibs opens the libraries scripts may use and strips unsafe globals.
+// string.rep and string.gsub are removed since a single call can allocate
+// without bound; table.concat and string.format are wrapped to refuse results
+// larger than what is left of the run's allocation budget. Lua patterns
+// backtrack inside one Go call, where neither the timeout nor the instruction
+// cap can interrupt them, so string.match and string.gmatch are removed and
+// string.find only searches for plain text.
+func openScriptLibs(L *lua.LState, budget *scriptBudget) {
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range unsafeScriptGlobals {
+		L.SetGlobal(name, lua.LNil)
+	}
+	if str, ok := L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		str.RawSetString("rep", lua.LNil)
+		str.RawSetString("gsub", lua.LNil)
+		str.RawSetString("match", lua.LNil)
+		str.RawSetString("gmatch", lua.LNil)
+		wrapScriptFunction(L, str, "find", func(L *lua.LState) {
+			if L.GetTop() >= 4 && lua.LVAsBool(L.Get(4)) {
+				return
+			}
+			if strings.ContainsAny(L.CheckString(2), luaPatternSpecials) {
+				L.RaiseError("string.find only supports plain searches; pass true as its fourth argument")
+			}
+			// A pattern without special characters is plain text; searching
+			// it as such runs in linear time
+			L.SetTop(3)
+			L.Push(lua.LTrue)
+		})
+		wrapScriptFunction(L, str, "format", func(L *lua.LState) {
+			if width := formatWidth(L.CheckString(1)); width > maxScriptFormatWidth {
+				L.RaiseError("format width %d exceeds %d", width, maxScriptFormatWidth)
+			}
+		})
+	}
+	if tbl, ok := L.GetGlobal(lua.TabLibName).(*lua.LTable); ok {
+		wrapScriptFunction(L, tbl, "concat", func(L *lua.LState) {
+			if size := concatSize(L); !budget.fits(size) {
+				L.RaiseError("concat result of %d bytes exceeds the allocation limit", size)
+			}
+		})
+	}
+}
+
+// wrapScriptFunction replaces a library function with one that runs check
+// first; check may adjust the arguments, or raise a Lua error to refuse the call
+func wrapScriptFunction(L *lua.LState, lib *lua.LTable, name string, check func(*lua.LState)) {
+	original, ok := lib.RawGetString(name).(*lua.LFunction)
+	if !ok || original.GFunction == nil {
+		return
+	}
+	lib.RawSetString(name, L.NewFunction(func(L *lua.LState) int {
+		check(L)
+		return original.GFunction(L)
+	}))
+}
+
+// Characters that make a string.find pattern more than plain text
+const luaPatternSpecials = "^$()%.[]*+-?"
+
+// Largest width or precision string.format accepts
+const maxScriptFormatWidth = 99
+
+// formatWidth returns the largest width or precision in a format string
+func formatWidth(format string) int {
+	largest, current := 0, 0
+	inVerb := false
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case !inVerb:
+			inVerb = c == '%'
+		case c >= '0' && c <= '9':
+			current = min(current*10+int(c-'0'), math.MaxInt32)
+			largest = max(largest, current)
+		case c == '.' || c == '-' || c == '+' || c == ' ' || c == '#':
+			current = 0
+		default:
+			inVerb, current = false, 0
+		}
+	}
+	return largest
+}
+
+// concatSize returns an upper bound on the length of table.concat's result
+// for the arguments on the stack
+func concatSize(L *lua.LState) int {
+	tbl := L.CheckTable(1)
+	sep := len(L.OptString(2, ""))
+	first := max(L.OptInt(3, 1), 1)
+	last := min(L.OptInt(4, tbl.Len()), tbl.Len())
+	size := 0
+	for i := first; i <= last; i++ {
+		switch value := tbl.RawGetInt(i).(type) {
+		case lua.LString:
+			size += len(value)
+		case lua.LNumber:
+			size += len(value.String())
+		}
+		if i != last {
+			size += sep
+		}
+	}
+	return size
+}
+
+// toLua converts decoded JSON metadata into Lua values
+func toLua(L *lua.LState, value interface{}) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case int:
+		return lua.LNumber(v)
+	case int64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []interface{}:
+		table := L.NewTable()
+		for _, item := range v {
+			table.Append(toLua(L, item))
+		}
+		return table
+	case map[string]interface{}:
+		table := L.NewTable()
+		for key, item := range v {
+			table.RawSetString(key, toLua(L, item))
+		}
+		return table
+	}
+	return lua.LString(fmt.Sprint(value))
+}
+
+// scriptScore runs a leaderboard's active scoring script for a submission
+func (s *LeaderboardService) scriptScore(ctx context.Context, lbConfig *domain.LeaderboardConfig, submission domain.ScoreSubmission) (int64, error) {
+	key := scriptKey(lbConfig)
+	proto, ok := s.scripts.cached(key)
+	if !ok {
+		script, err := s.postgres.GetScript(ctx, lbConfig.ID, lbConfig.ScriptVersion)
+		if err != nil {
+			return 0, fmt.Errorf("getting scoring script: %w", err)
+		}
+		if proto, err = s.scripts.store(key, script.Source); err != nil {
+			return 0, fmt.Errorf("compiling scoring script version %d: %w", lbConfig.ScriptVersion, err)
+		}
+	}
+	return s.scripts.Run(ctx, proto, submission)
+}
+
+// CreateScript stores a new version of a leaderboard's scoring script and activates it
+func (s *LeaderboardService) CreateScript(ctx context.Context, leaderboardID string, req domain.CreateScriptRequest) (*domain.ScoringScript, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+	if err := s.scripts.Validate(req.Source); err != nil {
+		return nil, fmt.Errorf("%w: script: %v", domain.ErrInvalidRequest, err)
+	}
+
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrLeaderboardNotFound
+	}
+
+	script, err := s.postgres.CreateScript(ctx, leaderboardID, req.Source, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.refreshMeta(ctx, leaderboardID)
+	return script, nil
+}
+
+// ListScripts returns every version of a leaderboard's scoring script
+func (s *LeaderboardService) ListScripts(ctx context.Context, leaderboardID string) ([]domain.ScoringScript, error) {
+	exists, err := s.postgres.LeaderboardExists(ctx, leaderboardID)
+	if err != nil {
+		return nil, fmt.Errorf("checking leaderboard existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrLeaderboardNotFound
+	}
+	return s.postgres.ListScripts(ctx, leaderboardID)
+}
+
+// ActivateScript switches a leaderboard to a stored script version, e.g. to
+// roll back; version 0 disables scripting
+func (s *LeaderboardService) ActivateScript(ctx context.Context, leaderboardID string, version int) (*domain.LeaderboardConfig, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+	if version < 0 {
+		return nil, domain.ErrInvalidRequest
+	}
+	if err := s.postgres.SetScriptVersion(ctx, leaderboardID, version); err != nil {
+		return nil, err
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.SetLeaderboardMeta(ctx, *lbConfig); err != nil {
+		s.logger.Warn("failed to store leaderboard meta in redis", "error", err)
+	}
+	return lbConfig, nil
+}
+
+// refreshMeta copies a leaderboard's config from PostgreSQL to its Redis metadata
+func (s *LeaderboardService) refreshMeta(ctx context.Context, leaderboardID string) {
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err == nil {
+		err = s.redis.SetLeaderboardMeta(ctx, *lbConfig)
+	}
+	if err != nil {
+		s.logger.Warn("failed to store leaderboard meta in redis", "leaderboard_id", leaderboardID, "error", err)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// runScript compiles and runs source with the default sandbox limits
+func runScript(t *testing.T, source string) (int64, error) {
+	t.Helper()
+	engine := newScriptEngine(&config.ScriptConfig{
+		Timeout:         time.Second,
+		MaxSourceBytes:  16384,
+		CallStackSize:   64,
+		RegistryMaxSize: 65536,
+		MaxInstructions: 100000,
+		MaxAllocBytes:   1 << 20,
+	})
+	proto, err := engine.compile("test", source)
+	if err != nil {
+		t.Fatalf("compiling script: %v", err)
+	}
+	return engine.Run(context.Background(), proto, domain.ScoreSubmission{PlayerID: "player1", Score: 10})
+}
+
+func TestScriptFindSearchesPlainText(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int64
+	}{
+		{"literal pattern", `return (string.find("hello world", "world"))`, 7},
+		{"method call", `return (("hello world"):find("o", 6))`, 8},
+		{"explicit plain", `return (string.find("a.b", ".", 1, true))`, 2},
+		{"explicit plain with specials", `return (string.find("x(y", "(", 1, true))`, 2},
+		{"not found", `if string.find(player_id, "zzz") == nil then return 1 end return 0`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runScript(t, tt.source)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("result = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptRejectsPatterns(t *testing.T) {
+	backtracking := `local s = "" for i = 1, 64 do s = s .. "aaaaaaaaaaaaaaaa" end `
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"find with a pattern", `return (string.find("abc", "b+"))`},
+		{"backtracking find", backtracking + `return (s:find("a*a*a*a*a*a*a*b")) or 0`},
+		{"backtracking find with init", backtracking + `return (s:find("a*a*a*a*a*a*a*b", 1)) or 0`},
+		{"match", `return tonumber(string.match("x42", "%d+"))`},
+		{"gmatch", `for n in ("1 2"):gmatch("%d") do end return 0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			_, err := runScript(t, tt.source)
+			if !errors.Is(err, domain.ErrInvalidScore) {
+				t.Fatalf("Run error = %v, want ErrInvalidScore", err)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("rejecting the script took %s", elapsed)
+			}
+			if strings.Contains(err.Error(), "time limit") {
+				t.Errorf("script ran into the time limit instead of being refused: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateSeeding splits a leaderboard's current standings into divisions or a
+// single-elimination bracket and stores the assignment. Players are seeded by
+// rank from a copy of the board frozen at one version; ghost entries are left out.
+func (s *LeaderboardService) CreateSeeding(ctx context.Context, leaderboardID string, req domain.SeedingRequest) (*domain.Seeding, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	name := req.Name
+	if name == "" {
+		name = now.UTC().Format(snapshotNameLayout)
+	}
+	if !domain.ValidSnapshotName(name) {
+		return nil, domain.ErrInvalidRequest
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen, err := s.redis.FreezeStandings(ctx, leaderboardID, lbConfig.HigherIsBetter())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := s.redis.DropFrozen(context.WithoutCancel(ctx), frozen); err != nil {
+			s.logger.Warn("failed to drop frozen standings", "leaderboard_id", leaderboardID, "error", err)
+		}
+	}()
+
+	var players []domain.SeededPlayer
+	next := s.frozenPages(ctx, frozen)
+	for len(players) < req.Players {
+		page, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, entry := range page {
+			if entry.IsGhost || len(players) == req.Players {
+				continue
+			}
+			players = append(players, domain.SeededPlayer{
+				Seed:     int64(len(players) + 1),
+				PlayerID: entry.PlayerID,
+				Score:    entry.Score,
+			})
+		}
+	}
+
+	seeding := domain.Seeding{
+		LeaderboardID: leaderboardID,
+		Name:          name,
+		Format:        req.Format,
+		Version:       frozen.Version,
+		CreatedAt:     now,
+	}
+	if req.Format == domain.SeedingDivisions {
+		seeding.DivisionCount = req.Divisions
+		domain.AssignDivisions(players, req.Divisions)
+	}
+
+	stored, err := s.postgres.CreateSeeding(ctx, seeding, players)
+	if err != nil {
+		return nil, err
+	}
+	s.withAssignment(stored, players)
+
+	s.logger.Info("leaderboard seeded",
+		"leaderboard_id", leaderboardID,
+		"seeding", stored.Name,
+		"format", stored.Format,
+		"version", stored.Version,
+		"players", stored.PlayerCount,
+	)
+	return stored, nil
+}
+
+// ListSeedings returns a leaderboard's seedings, newest first
+func (s *LeaderboardService) ListSeedings(ctx context.Context, leaderboardID string) ([]domain.Seeding, error) {
+	return s.postgres.ListSeedings(ctx, leaderboardID)
+}
+
+// GetSeeding returns a seeding with its divisions or first-round matches
+func (s *LeaderboardService) GetSeeding(ctx context.Context, leaderboardID, name string) (*domain.Seeding, error) {
+	seeding, err := s.postgres.GetSeeding(ctx, leaderboardID, name)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.postgres.GetSeededPlayers(ctx, seeding.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.withAssignment(seeding, players)
+	return seeding, nil
+}
+
+// withAssignment fills in a seeding's divisions or bracket from its players
+func (s *LeaderboardService) withAssignment(seeding *domain.Seeding, players []domain.SeededPlayer) {
+	switch seeding.Format {
+	case domain.SeedingDivisions:
+		seeding.Divisions = domain.GroupDivisions(players, seeding.DivisionCount)
+	case domain.SeedingBracket:
+		seeding.Matches = domain.BracketMatches(players)
+	}
+}
+
+// GetDivision returns the live standings of one division of a seeding: its
+// players ranked among themselves by their current scores
+func (s *LeaderboardService) GetDivision(ctx context.Context, leaderboardID, name string, division int) (*domain.DivisionView, error) {
+	seeding, err := s.postgres.GetSeeding(ctx, leaderboardID, name)
+	if err != nil {
+		return nil, err
+	}
+	if seeding.Format != domain.SeedingDivisions || division < 1 || division > seeding.DivisionCount {
+		return nil, domain.ErrDivisionNotFound
+	}
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := s.postgres.GetSeededPlayers(ctx, seeding.ID, division)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(players))
+	for i, p := range players {
+		ids[i] = p.PlayerID
+	}
+	scores, err := s.redis.GetScores(ctx, leaderboardID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.DivisionView{
+		LeaderboardID: leaderboardID,
+		Seeding:       seeding.Name,
+		Division:      division,
+		Entries:       domain.RankDivision(players, scores, lbConfig.HigherIsBetter()),
+	}, nil
+}
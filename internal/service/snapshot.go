@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/redis"
+)
+
+// snapshotPageSize is how many frozen entries a freeze reads from Redis at a time
+const snapshotPageSize = 1000
+
+// snapshotNameLayout names snapshots frozen without a name after the freeze time
+const snapshotNameLayout = "20060102T150405Z"
+
+// FreezeLeaderboard stores an immutable snapshot of a leaderboard's full
+// standings as they stand right now. The standings are copied inside Redis in
+// one step, so writes arriving while the copy is stored are not included.
+func (s *LeaderboardService) FreezeLeaderboard(ctx context.Context, leaderboardID string, req domain.FreezeRequest) (*domain.Snapshot, error) {
+	if s.readOnly {
+		return nil, domain.ErrReadOnlyReplica
+	}
+
+	now := s.clock.Now()
+	name := req.Name
+	if name == "" {
+		name = now.UTC().Format(snapshotNameLayout)
+	}
+	if !domain.ValidSnapshotName(name) {
+		return nil, domain.ErrInvalidRequest
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen, err := s.redis.FreezeStandings(ctx, leaderboardID, lbConfig.HigherIsBetter())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := s.redis.DropFrozen(context.WithoutCancel(ctx), frozen); err != nil {
+			s.logger.Warn("failed to drop frozen standings", "leaderboard_id", leaderboardID, "error", err)
+		}
+	}()
+
+	snapshot, err := s.postgres.CreateSnapshot(ctx, domain.Snapshot{
+		LeaderboardID: leaderboardID,
+		Name:          name,
+		Version:       frozen.Version,
+		PlayerCount:   frozen.Count,
+		CreatedAt:     now,
+	}, s.frozenPages(ctx, frozen))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("leaderboard frozen",
+		"leaderboard_id", leaderboardID,
+		"snapshot", snapshot.Name,
+		"version", snapshot.Version,
+		"players", snapshot.PlayerCount,
+	)
+	return snapshot, nil
+}
+
+// frozenPages returns a reader of a frozen copy's entries, one page per call,
+// that returns an empty page once the copy is exhausted
+func (s *LeaderboardService) frozenPages(ctx context.Context, frozen *redis.FrozenStandings) func() ([]domain.LeaderboardEntry, error) {
+	var pos int64
+	return func() ([]domain.LeaderboardEntry, error) {
+		if pos >= frozen.Count {
+			return nil, nil
+		}
+		page, err := s.redis.ReadFrozen(ctx, frozen, pos, pos+snapshotPageSize-1)
+		pos += snapshotPageSize
+		return page, err
+	}
+}
+
+// ListSnapshots returns a leaderboard's snapshots, newest first
+func (s *LeaderboardService) ListSnapshots(ctx context.Context, leaderboardID string) ([]domain.Snapshot, error) {
+	return s.postgres.ListSnapshots(ctx, leaderboardID)
+}
+
+// GetSnapshot returns a snapshot's details without its entries
+func (s *LeaderboardService) GetSnapshot(ctx context.Context, leaderboardID, name string) (*domain.Snapshot, error) {
+	return s.postgres.GetSnapshot(ctx, leaderboardID, name)
+}
+
+// EachSnapshotEntry calls fn with every entry of a snapshot in rank order
+func (s *LeaderboardService) EachSnapshotEntry(ctx context.Context, snapshot *domain.Snapshot, fn func(domain.LeaderboardEntry) error) error {
+	return s.postgres.EachSnapshotEntry(ctx, snapshot.ID, fn)
+}
+
+// DiffSnapshots compares two snapshots of a leaderboard: players added and
+// removed, and the rank and score changes of players in both. limit caps the
+// length of each list; 0 lists every player.
+func (s *LeaderboardService) DiffSnapshots(ctx context.Context, leaderboardID, fromName, toName string, limit int) (*domain.SnapshotDiff, error) {
+	from, err := s.postgres.GetSnapshot(ctx, leaderboardID, fromName)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.postgres.GetSnapshot(ctx, leaderboardID, toName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hold the older standings in memory and stream the newer ones past them
+	previous := make(map[string]domain.LeaderboardEntry, from.PlayerCount)
+	err = s.postgres.EachSnapshotEntry(ctx, from.ID, func(entry domain.LeaderboardEntry) error {
+		previous[entry.PlayerID] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &domain.SnapshotDiff{
+		LeaderboardID: leaderboardID,
+		From:          from.Ref(),
+		To:            to.Ref(),
+		Added:         []domain.LeaderboardEntry{},
+		Removed:       []domain.LeaderboardEntry{},
+		Changed:       []domain.SnapshotEntryDiff{},
+	}
+	listed := func(n int64) bool { return limit <= 0 || n <= int64(limit) }
+	err = s.postgres.EachSnapshotEntry(ctx, to.ID, func(entry domain.LeaderboardEntry) error {
+		old, ok := previous[entry.PlayerID]
+		delete(previous, entry.PlayerID)
+		switch {
+		case !ok:
+			diff.Counts.Added++
+			if listed(diff.Counts.Added) {
+				diff.Added = append(diff.Added, entry)
+			}
+		case old.Rank != entry.Rank || old.Score != entry.Score:
+			diff.Counts.Changed++
+			if listed(diff.Counts.Changed) {
+				diff.Changed = append(diff.Changed, domain.SnapshotEntryDiff{
+					PlayerID:    entry.PlayerID,
+					OldRank:     old.Rank,
+					Rank:        entry.Rank,
+					RankChange:  old.Rank - entry.Rank,
+					OldScore:    old.Score,
+					Score:       entry.Score,
+					ScoreChange: entry.Score - old.Score,
+				})
+			}
+		default:
+			diff.Counts.Unchanged++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Whatever is left of the older standings has dropped off the board
+	removed := make([]domain.LeaderboardEntry, 0, len(previous))
+	for _, entry := range previous {
+		removed = append(removed, entry)
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Rank < removed[j].Rank })
+	diff.Counts.Removed = int64(len(removed))
+	if limit > 0 && len(removed) > limit {
+		removed = removed[:limit]
+	}
+	diff.Removed = removed
+	return diff, nil
+}
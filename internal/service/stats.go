@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// statsCache holds recently computed stats per leaderboard
+type statsCache struct {
+	mu      sync.Mutex
+	entries map[string]statsCacheEntry
+}
+
+type statsCacheEntry struct {
+	stats   *domain.LeaderboardStats
+	expires time.Time
+}
+
+func newStatsCache() *statsCache {
+	return &statsCache{entries: make(map[string]statsCacheEntry)}
+}
+
+// get returns cached stats that are still fresh and include minVersion
+func (c *statsCache) get(leaderboardID string, now time.Time, minVersion int64) *domain.LeaderboardStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[leaderboardID]
+	if !ok || !now.Before(entry.expires) || entry.stats.Version < minVersion {
+		return nil
+	}
+	return entry.stats
+}
+
+func (c *statsCache) put(leaderboardID string, stats *domain.LeaderboardStats, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[leaderboardID] = statsCacheEntry{stats: stats, expires: expires}
+}
+
+func (c *statsCache) invalidate(leaderboardID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, leaderboardID)
+}
+
+// GetStats returns statistics for a leaderboard. Results are cached for the
+// configured TTL; a cached result older than minVersion is recomputed.
+func (s *LeaderboardService) GetStats(ctx context.Context, leaderboardID string, minVersion int64) (*domain.LeaderboardStats, error) {
+	if stats := s.stats.get(leaderboardID, s.clock.Now(), minVersion); stats != nil {
+		return stats, nil
+	}
+
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Version, counts, and bounds come from one MULTI, so they describe the same state
+	view, err := s.redis.GetBoardView(ctx, leaderboardID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.LeaderboardStats{
+		LeaderboardID: leaderboardID,
+		SortOrder:     lbConfig.SortOrder,
+		TotalPlayers:  view.Members - view.Ghosts,
+		Version:       view.Version,
+		ComputedAt:    s.clock.Now(),
+	}
+	if next, ok := s.schedule.For(lbConfig).NextReset(lbConfig.ResetPeriod, stats.ComputedAt); ok {
+		stats.NextResetAt = &next
+	}
+	if view.LastWrite != nil {
+		idle := max(0, int64(stats.ComputedAt.Sub(*view.LastWrite).Seconds()))
+		stats.LastWriteAt = view.LastWrite
+		stats.IdleSeconds = &idle
+	}
+
+	// The highest and lowest values map to first and last rank by sort order
+	if lbConfig.HigherIsBetter() {
+		stats.TopScore, stats.LowestScore = view.HighestScore, view.LowestScore
+	} else {
+		stats.TopScore, stats.LowestScore = view.LowestScore, view.HighestScore
+	}
+
+	// Average and standard deviation, sampled on large boards
+	moments, err := s.redis.GetScoreMoments(ctx, leaderboardID, s.config.StatsSampleSize)
+	if err != nil {
+		s.logger.Warn("failed to compute score moments", "leaderboard_id", leaderboardID, "error", err)
+	} else if moments.Count > 0 {
+		average := moments.Mean
+		stddev := math.Sqrt(moments.M2 / float64(moments.Count))
+		stats.Average = &average
+		stats.StdDev = &stddev
+		stats.Sampled = moments.Sampled
+		if moments.Sampled {
+			stats.SampleSize = moments.Count
+		}
+	}
+
+	s.stats.put(leaderboardID, stats, s.clock.Now().Add(s.config.StatsCacheTTL))
+
+	return stats, nil
+}
+
+// GetLeaderboardView returns a leaderboard's live settings, counts, score bounds,
+// and its first and last limit entries, read from Redis in one round trip.
+// Settings fall back to PostgreSQL when Redis holds no meta for the board.
+func (s *LeaderboardService) GetLeaderboardView(ctx context.Context, leaderboardID string, limit int) (*domain.LeaderboardView, error) {
+	if limit <= 0 {
+		limit = s.config.DefaultLimit
+	}
+	if limit > s.config.MaxLimit {
+		limit = s.config.MaxLimit
+	}
+
+	view, err := s.redis.GetBoardView(ctx, leaderboardID, limit)
+	if err != nil {
+		return nil, err
+	}
+	lbConfig := view.Meta
+	if lbConfig == nil {
+		if lbConfig, err = s.postgres.GetLeaderboard(ctx, leaderboardID); err != nil {
+			return nil, err
+		}
+	}
+	s.withNextReset(lbConfig)
+
+	result := &domain.LeaderboardView{
+		Leaderboard:  lbConfig,
+		TotalPlayers: view.Members - view.Ghosts,
+		Version:      view.Version,
+		Top:          view.Top,
+		Bottom:       view.Bottom,
+	}
+	if lbConfig.HigherIsBetter() {
+		result.TopScore, result.LowestScore = view.HighestScore, view.LowestScore
+	} else {
+		result.TopScore, result.LowestScore = view.LowestScore, view.HighestScore
+	}
+	for _, entries := range [][]domain.LeaderboardEntry{result.Top, result.Bottom} {
+		if err := s.annotateEntries(ctx, leaderboardID, lbConfig.MinSubmissions, entries); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/formula"
+)
+
+// ScoreTransformer derives the score to rank from a submission before the
+// leaderboard's multiplier, offset and bounds are applied. The default
+// evaluates the leaderboard's score formula; other implementations can be
+// installed with SetScoreTransformer.
+type ScoreTransformer interface {
+	// Validate checks a leaderboard's transform settings at creation
+	Validate(config *domain.LeaderboardConfig) error
+	// Transform returns the submission's score for the leaderboard
+	Transform(config *domain.LeaderboardConfig, submission domain.ScoreSubmission) (int64, error)
+}
+
+// SetScoreTransformer replaces the formula-based score transformer
+func (s *LeaderboardService) SetScoreTransformer(t ScoreTransformer) {
+	s.transformer = t
+}
+
+// deriveScore runs the ingestion transform stage for a submission. An active
+// scoring script takes the place of the score transformer.
+func (s *LeaderboardService) deriveScore(ctx context.Context, config *domain.LeaderboardConfig, submission domain.ScoreSubmission) (int64, error) {
+	var raw int64
+	var err error
+	if config.ScriptVersion > 0 {
+		raw, err = s.scriptScore(ctx, config, submission)
+	} else {
+		raw, err = s.transformer.Transform(config, submission)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return config.TransformScore(raw)
+}
+
+// formulaTransformer evaluates LeaderboardConfig.ScoreFormula against the
+// submission. Boards without a formula rank the submitted score.
+type formulaTransformer struct {
+	mu       sync.RWMutex
+	compiled map[string]*formula.Expr
+}
+
+func newFormulaTransformer() *formulaTransformer {
+	return &formulaTransformer{compiled: make(map[string]*formula.Expr)}
+}
+
+// Validate compiles the leaderboard's formula
+func (t *formulaTransformer) Validate(config *domain.LeaderboardConfig) error {
+	if config.ScoreFormula == "" {
+		return nil
+	}
+	if _, err := t.compile(config.ScoreFormula); err != nil {
+		return fmt.Errorf("%w: score_formula: %v", domain.ErrInvalidLeaderboard, err)
+	}
+	return nil
+}
+
+// Transform evaluates the formula with score bound to the submitted score and
+// every other variable read from the submission metadata
+func (t *formulaTransformer) Transform(config *domain.LeaderboardConfig, submission domain.ScoreSubmission) (int64, error) {
+	if config.ScoreFormula == "" {
+		return submission.Score, nil
+	}
+	expr, err := t.compile(config.ScoreFormula)
+	if err != nil {
+		return 0, fmt.Errorf("%w: score_formula: %v", domain.ErrInvalidScore, err)
+	}
+
+	value, err := expr.Eval(func(name string) (float64, bool) {
+		if name == "score" {
+			return float64(submission.Score), true
+		}
+		return metadataNumber(submission.Metadata, name)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", domain.ErrInvalidScore, err)
+	}
+
+	value = math.Round(value)
+	if math.IsNaN(value) || math.IsInf(value, 0) || value >= math.MaxInt64 || value < math.MinInt64 {
+		return 0, domain.ErrInvalidScore
+	}
+	return int64(value), nil
+}
+
+// compile returns the cached compiled form of a formula
+func (t *formulaTransformer) compile(source string) (*formula.Expr, error) {
+	t.mu.RLock()
+	expr, ok := t.compiled[source]
+	t.mu.RUnlock()
+	if ok {
+		return expr, nil
+	}
+
+	expr, err := formula.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.compiled[source] = expr
+	t.mu.Unlock()
+	return expr, nil
+}
+
+// metadataNumber reads a numeric metadata value, following dotted paths into nested objects
+func metadataNumber(metadata map[string]interface{}, path string) (float64, bool) {
+	var value interface{} = metadata
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		if value, ok = object[key]; !ok {
+			return 0, false
+		}
+	}
+	return numberValue(value)
+}
+
+// numberValue converts a decoded JSON value to a number
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
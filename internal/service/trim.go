@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// evictedEventType marks score events recording a player pushed off a capped leaderboard
+const evictedEventType = "evicted"
+
+// enforceMaxEntries trims a leaderboard back to its max_entries after a write.
+// Failures are logged; the board is trimmed again on the next write or sync.
+func (s *LeaderboardService) enforceMaxEntries(ctx context.Context, lbConfig *domain.LeaderboardConfig) {
+	if _, err := s.trimEntries(ctx, lbConfig); err != nil {
+		s.logger.Warn("failed to enforce max entries", "leaderboard_id", lbConfig.ID, "error", err)
+	}
+}
+
+// TrimLeaderboard evicts the worst players of a leaderboard beyond its
+// max_entries and returns how many were evicted
+func (s *LeaderboardService) TrimLeaderboard(ctx context.Context, leaderboardID string) (int, error) {
+	lbConfig, err := s.postgres.GetLeaderboard(ctx, leaderboardID)
+	if err != nil {
+		return 0, err
+	}
+	return s.trimEntries(ctx, lbConfig)
+}
+
+// trimEntries evicts players beyond a leaderboard's cap. Each eviction is
+// removed from PostgreSQL, replicated, broadcast as player_removed, and
+// recorded as a score event that bypasses sampling. Replicas apply the
+// primary's evictions instead of trimming on their own.
+func (s *LeaderboardService) trimEntries(ctx context.Context, lbConfig *domain.LeaderboardConfig) (int, error) {
+	if s.readOnly || lbConfig.MaxEntries <= 0 {
+		return 0, nil
+	}
+
+	evicted, version, err := s.redis.TrimEntries(ctx, lbConfig.ID, lbConfig.MaxEntries, lbConfig.HigherIsBetter())
+	if len(evicted) == 0 {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	for _, entry := range evicted {
+		if err := s.postgres.RemovePlayer(ctx, lbConfig.ID, entry.PlayerID); err != nil {
+			s.logger.Warn("failed to remove evicted player from postgres",
+				"leaderboard_id", lbConfig.ID,
+				"player_id", entry.PlayerID,
+				"error", err,
+			)
+		}
+		s.replicate(domain.ReplicatedChange{
+			Op:            domain.ReplicationOpRemove,
+			LeaderboardID: lbConfig.ID,
+			PlayerID:      entry.PlayerID,
+			Version:       version,
+		})
+
+		event := domain.ScoreEvent{
+			PlayerID:      entry.PlayerID,
+			LeaderboardID: lbConfig.ID,
+			Score:         entry.Score,
+			EventType:     evictedEventType,
+			Timestamp:     now,
+			Metadata: map[string]interface{}{
+				"rank":        entry.Rank,
+				"max_entries": lbConfig.MaxEntries,
+			},
+		}
+		if err := s.postgres.RecordEvent(ctx, event); err != nil {
+			s.logger.Warn("failed to record eviction event", "leaderboard_id", lbConfig.ID, "player_id", entry.PlayerID, "error", err)
+		}
+	}
+	s.stats.invalidate(lbConfig.ID)
+	s.broadcastRemovals(ctx, lbConfig.ID, evicted)
+	s.purgeIfTopRemoved(lbConfig.ID, evicted)
+
+	s.logger.Info("evicted players beyond max entries",
+		"leaderboard_id", lbConfig.ID,
+		"max_entries", lbConfig.MaxEntries,
+		"count", len(evicted),
+	)
+	if err != nil {
+		return len(evicted), fmt.Errorf("trimming leaderboard: %w", err)
+	}
+	return len(evicted), nil
+}
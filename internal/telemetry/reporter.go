@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// Reporter sends errors and recovered panics to an error tracking service.
+// The shape mirrors the Sentry SDK so a Sentry client can be dropped in.
+type Reporter interface {
+	// CaptureError reports an error with context tags such as leaderboard_id
+	CaptureError(ctx context.Context, err error, tags map[string]string)
+	// CapturePanic reports a recovered panic value and its stack trace
+	CapturePanic(ctx context.Context, value interface{}, stack []byte, tags map[string]string)
+	// Flush waits up to timeout for queued reports to be delivered
+	Flush(timeout time.Duration) bool
+}
+
+// New creates the reporter selected by configuration
+func New(cfg *config.ErrorReportingConfig, logger *slog.Logger) (Reporter, error) {
+	if !cfg.Enabled {
+		return Nop(), nil
+	}
+
+	switch cfg.Provider {
+	case config.ErrorReporterLog:
+		return NewLogReporter(logger), nil
+	case config.ErrorReporterSentry:
+		return NewSentryReporter(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unknown error reporter %q", cfg.Provider)
+	}
+}
+
+// Recover reports a panic in the calling goroutine and swallows it.
+// It must be called directly by defer.
+func Recover(ctx context.Context, reporter Reporter, tags map[string]string) {
+	if value := recover(); value != nil {
+		reporter.CapturePanic(ctx, value, debug.Stack(), tags)
+	}
+}
+
+// nopReporter discards all reports
+type nopReporter struct{}
+
+// Nop returns a reporter that discards all reports
+func Nop() Reporter {
+	return nopReporter{}
+}
+
+func (nopReporter) CaptureError(context.Context, error, map[string]string) {}
+
+func (nopReporter) CapturePanic(context.Context, interface{}, []byte, map[string]string) {}
+
+func (nopReporter) Flush(time.Duration) bool { return true }
+
+// LogReporter writes reports to the structured log
+type LogReporter struct {
+	logger *slog.Logger
+}
+
+// NewLogReporter creates a reporter that logs errors and panics
+func NewLogReporter(logger *slog.Logger) *LogReporter {
+	return &LogReporter{logger: logger}
+}
+
+// CaptureError logs an error with its tags
+func (r *LogReporter) CaptureError(ctx context.Context, err error, tags map[string]string) {
+	r.logger.ErrorContext(ctx, "error reported", "error", err, "tags", tags)
+}
+
+// CapturePanic logs a recovered panic with its stack trace
+func (r *LogReporter) CapturePanic(ctx context.Context, value interface{}, stack []byte, tags map[string]string) {
+	r.logger.ErrorContext(ctx, "panic recovered",
+		"panic", fmt.Sprint(value),
+		"stack", string(stack),
+		"tags", tags,
+	)
+}
+
+// Flush is a no-op because logs are written synchronously
+func (r *LogReporter) Flush(time.Duration) bool { return true }
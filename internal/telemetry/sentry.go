@@ -0,0 +1,179 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// SentryReporter posts events to a Sentry-compatible store endpoint
+type SentryReporter struct {
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	client      *http.Client
+	logger      *slog.Logger
+	queue       chan sentryEvent
+	wg          sync.WaitGroup
+}
+
+// sentryEvent is the subset of the Sentry event payload we send
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Exception   *sentryExceptions `json:"exception,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// NewSentryReporter creates a reporter from a DSN of the form
+// https://<key>@<host>/<project_id>
+func NewSentryReporter(cfg *config.ErrorReportingConfig, logger *slog.Logger) (*SentryReporter, error) {
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil || dsn.User == nil || dsn.Host == "" {
+		return nil, fmt.Errorf("invalid sentry dsn")
+	}
+	projectID := strings.Trim(dsn.Path, "/")
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid sentry dsn: missing project id")
+	}
+
+	r := &SentryReporter{
+		endpoint:    fmt.Sprintf("%s://%s/api/%s/store/", dsn.Scheme, dsn.Host, projectID),
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=leaderboard-redis/1.0, sentry_key=%s", dsn.User.Username()),
+		environment: cfg.Environment,
+		release:     cfg.Release,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		logger:      logger,
+		queue:       make(chan sentryEvent, 100),
+	}
+
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// CaptureError queues an error event
+func (r *SentryReporter) CaptureError(_ context.Context, err error, tags map[string]string) {
+	r.enqueue(sentryEvent{
+		Level: "error",
+		Tags:  tags,
+		Exception: &sentryExceptions{Values: []sentryException{
+			{Type: fmt.Sprintf("%T", err), Value: err.Error()},
+		}},
+	})
+}
+
+// CapturePanic queues a fatal event carrying the stack trace
+func (r *SentryReporter) CapturePanic(_ context.Context, value interface{}, stack []byte, tags map[string]string) {
+	r.enqueue(sentryEvent{
+		Level: "fatal",
+		Tags:  tags,
+		Exception: &sentryExceptions{Values: []sentryException{
+			{Type: "panic", Value: fmt.Sprint(value)},
+		}},
+		Extra: map[string]string{"stack": string(stack)},
+	})
+}
+
+// Flush stops accepting events and waits up to timeout for the queue to drain
+func (r *SentryReporter) Flush(timeout time.Duration) bool {
+	close(r.queue)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// enqueue fills in common fields and queues the event without blocking the caller
+func (r *SentryReporter) enqueue(event sentryEvent) {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	event.EventID = hex.EncodeToString(id)
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	event.Platform = "go"
+	event.Environment = r.environment
+	event.Release = r.release
+
+	defer func() {
+		// The queue is closed after Flush; late reports are dropped
+		_ = recover()
+	}()
+
+	select {
+	case r.queue <- event:
+	default:
+		r.logger.Warn("error report queue full, dropping event")
+	}
+}
+
+// run delivers queued events
+func (r *SentryReporter) run() {
+	defer r.wg.Done()
+	for event := range r.queue {
+		if err := r.send(event); err != nil {
+			r.logger.Warn("failed to send error report", "error", err)
+		}
+	}
+}
+
+// send posts a single event to the store endpoint
+func (r *SentryReporter) send(event sentryEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting event: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+//go:build integration
+
+package testutil
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// container is a throwaway Docker container started for a test run
+type container struct {
+	id string
+}
+
+// runContainer starts a detached container and removes it when the test finishes.
+// args are passed to `docker run` before the image name.
+func runContainer(t testing.TB, image string, args ...string) *container {
+	t.Helper()
+
+	cmdArgs := append([]string{"run", "-d", "--rm"}, args...)
+	cmdArgs = append(cmdArgs, image)
+	out, err := docker(cmdArgs...)
+	if err != nil {
+		t.Fatalf("starting %s: %v", image, err)
+	}
+
+	c := &container{id: strings.TrimSpace(out)}
+	t.Cleanup(func() {
+		if _, err := docker("rm", "-f", c.id); err != nil {
+			t.Logf("removing container %s: %v", c.id, err)
+		}
+	})
+	return c
+}
+
+// hostAddr returns the host address published for a container port
+func (c *container) hostAddr(t testing.TB, port string) string {
+	t.Helper()
+
+	out, err := docker("port", c.id, port)
+	if err != nil {
+		t.Fatalf("looking up port %s: %v", port, err)
+	}
+	// docker port may list IPv4 and IPv6 bindings; the first is enough
+	addr := strings.TrimSpace(strings.SplitN(out, "\n", 2)[0])
+	return strings.Replace(addr, "0.0.0.0", "127.0.0.1", 1)
+}
+
+// docker runs a docker CLI command and returns its stdout
+func docker(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// freePort returns a host port that is free at the time of the call
+func freePort(t testing.TB) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("finding free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// waitFor polls check until it succeeds or the timeout expires
+func waitFor(t testing.TB, what string, timeout time.Duration, check func() error) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("waiting for %s: %v", what, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,181 @@
+//go:build integration
+
+// Package testutil starts Redis, PostgreSQL, and Kafka in Docker and wires the
+// service against them for end-to-end tests. Build with -tags=integration.
+//
+// Set TEST_REDIS_ADDR, TEST_POSTGRES_HOST/TEST_POSTGRES_PORT, or
+// TEST_KAFKA_BROKERS to reuse existing services (for example CI service
+// containers) instead of starting new ones.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/postgres"
+	"github.com/leaderboard-redis/internal/redis"
+	"github.com/leaderboard-redis/internal/service"
+	"github.com/leaderboard-redis/internal/worker"
+)
+
+const startupTimeout = 90 * time.Second
+
+// Env is a running set of backing services with the application wired on top
+type Env struct {
+	Config   *config.Config
+	Logger   *slog.Logger
+	Redis    *redis.LeaderboardService
+	Postgres *postgres.Repository
+	Service  *service.LeaderboardService
+	Sync     *worker.SyncWorker
+	Resets   *worker.ResetWorker
+
+	// Clock drives the service and workers; advance it to fast-forward resets
+	Clock *clock.Simulated
+}
+
+// NewEnv starts Redis and PostgreSQL, runs migrations, and builds the service and sync worker
+func NewEnv(t testing.TB) *Env {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if os.Getenv("TEST_VERBOSE") != "" {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	cfg.Redis.Addr = startRedis(t)
+	cfg.Postgres.Host, cfg.Postgres.Port = startPostgres(t)
+	cfg.Postgres.User = "leaderboard"
+	cfg.Postgres.Password = "secret"
+	cfg.Postgres.Database = "leaderboard"
+	cfg.Postgres.SSLMode = "disable"
+
+	var redisService *redis.LeaderboardService
+	waitFor(t, "redis", startupTimeout, func() error {
+		var err error
+		redisService, err = redis.NewLeaderboardService(&cfg.Redis, logger)
+		return err
+	})
+	t.Cleanup(func() { redisService.Close() })
+
+	var repo *postgres.Repository
+	waitFor(t, "postgres", startupTimeout, func() error {
+		var err error
+		repo, err = postgres.NewRepository(&cfg.Postgres, logger)
+		return err
+	})
+	t.Cleanup(repo.Close)
+
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("running migrations: %v", err)
+	}
+
+	simClock := clock.NewSimulated(time.Now())
+	svc := service.NewLeaderboardService(redisService, repo, &cfg.Leaderboard, logger)
+	svc.SetClock(simClock)
+	syncWorker := worker.NewSyncWorker(redisService, repo, &cfg.Sync, logger)
+	syncWorker.SetClock(simClock)
+	resetWorker := worker.NewResetWorker(svc, repo, &cfg.Reset, logger)
+	resetWorker.SetClock(simClock)
+
+	return &Env{
+		Config:   cfg,
+		Logger:   logger,
+		Redis:    redisService,
+		Postgres: repo,
+		Service:  svc,
+		Sync:     syncWorker,
+		Resets:   resetWorker,
+		Clock:    simClock,
+	}
+}
+
+// StartKafka starts a single-node Kafka broker and points the env's config at it
+func (e *Env) StartKafka(t testing.TB) []string {
+	t.Helper()
+
+	brokers := startKafka(t)
+	e.Config.Kafka.Enabled = true
+	e.Config.Kafka.Brokers = brokers
+	e.Config.Kafka.Topic = "scores-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	e.Config.Kafka.GroupID = e.Config.Kafka.Topic + "-consumer"
+
+	waitFor(t, "kafka", startupTimeout, func() error {
+		client, err := sarama.NewClient(brokers, sarama.NewConfig())
+		if err != nil {
+			return err
+		}
+		return client.Close()
+	})
+	return brokers
+}
+
+// startRedis returns the address of a Redis server for the test
+func startRedis(t testing.TB) string {
+	if addr := os.Getenv("TEST_REDIS_ADDR"); addr != "" {
+		return addr
+	}
+	c := runContainer(t, "redis:7-alpine", "-p", "127.0.0.1::6379")
+	return c.hostAddr(t, "6379/tcp")
+}
+
+// startPostgres returns the host and port of a PostgreSQL server for the test
+func startPostgres(t testing.TB) (string, int) {
+	if host := os.Getenv("TEST_POSTGRES_HOST"); host != "" {
+		port, _ := strconv.Atoi(os.Getenv("TEST_POSTGRES_PORT"))
+		if port == 0 {
+			port = 5432
+		}
+		return host, port
+	}
+
+	c := runContainer(t, "postgres:15-alpine",
+		"-p", "127.0.0.1::5432",
+		"-e", "POSTGRES_USER=leaderboard",
+		"-e", "POSTGRES_PASSWORD=secret",
+		"-e", "POSTGRES_DB=leaderboard",
+	)
+	addr := c.hostAddr(t, "5432/tcp")
+	host, portStr, _ := strings.Cut(addr, ":")
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("parsing postgres port %q: %v", addr, err)
+	}
+	return host, port
+}
+
+// startKafka returns the bootstrap brokers of a Kafka cluster for the test
+func startKafka(t testing.TB) []string {
+	if brokers := os.Getenv("TEST_KAFKA_BROKERS"); brokers != "" {
+		return strings.Split(brokers, ",")
+	}
+
+	// The advertised listener must match the published host port, so pick it up front
+	port := freePort(t)
+	runContainer(t, "apache/kafka:3.7.0",
+		"-p", fmt.Sprintf("127.0.0.1:%d:9092", port),
+		"-e", "KAFKA_NODE_ID=1",
+		"-e", "KAFKA_PROCESS_ROLES=broker,controller",
+		"-e", "KAFKA_LISTENERS=PLAINTEXT://0.0.0.0:9092,CONTROLLER://0.0.0.0:9093",
+		"-e", fmt.Sprintf("KAFKA_ADVERTISED_LISTENERS=PLAINTEXT://127.0.0.1:%d", port),
+		"-e", "KAFKA_CONTROLLER_LISTENER_NAMES=CONTROLLER",
+		"-e", "KAFKA_LISTENER_SECURITY_PROTOCOL_MAP=CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+		"-e", "KAFKA_CONTROLLER_QUORUM_VOTERS=1@localhost:9093",
+		"-e", "KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR=1",
+		"-e", "KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR=1",
+		"-e", "KAFKA_TRANSACTION_STATE_LOG_MIN_ISR=1",
+		"-e", "KAFKA_AUTO_CREATE_TOPICS_ENABLE=true",
+	)
+	return []string{fmt.Sprintf("127.0.0.1:%d", port)}
+}
@@ -0,0 +1,105 @@
+//go:build integration
+
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// CreateBoard creates a leaderboard with a unique ID and deletes it when the test finishes.
+// Fields left empty in req get defaults.
+func (e *Env) CreateBoard(t testing.TB, req domain.CreateLeaderboardRequest) string {
+	t.Helper()
+
+	if req.ID == "" {
+		req.ID = "test-" + uuid.New().String()[:8]
+	}
+	if req.Name == "" {
+		req.Name = req.ID
+	}
+
+	ctx := context.Background()
+	if _, err := e.Service.CreateLeaderboard(ctx, req); err != nil {
+		t.Fatalf("creating leaderboard %s: %v", req.ID, err)
+	}
+	t.Cleanup(func() {
+		_ = e.Service.DeleteLeaderboard(context.Background(), req.ID)
+	})
+	return req.ID
+}
+
+// SubmitScores submits one score per player through the service
+func (e *Env) SubmitScores(t testing.TB, leaderboardID string, scores map[string]int64) {
+	t.Helper()
+
+	ctx := context.Background()
+	for playerID, score := range scores {
+		err := e.Service.SubmitScore(ctx, domain.ScoreSubmission{
+			PlayerID:      playerID,
+			LeaderboardID: leaderboardID,
+			Score:         score,
+		})
+		if err != nil {
+			t.Fatalf("submitting score for %s: %v", playerID, err)
+		}
+	}
+}
+
+// Top returns the top n entries of a leaderboard
+func (e *Env) Top(t testing.TB, leaderboardID string, n int) []domain.LeaderboardEntry {
+	t.Helper()
+
+	entries, err := e.Service.GetTopN(context.Background(), leaderboardID, n)
+	if err != nil {
+		t.Fatalf("getting top %d of %s: %v", n, leaderboardID, err)
+	}
+	return entries
+}
+
+// ProduceScores publishes submissions to the env's Kafka topic. StartKafka must be called first.
+func (e *Env) ProduceScores(t testing.TB, submissions ...domain.ScoreSubmission) {
+	t.Helper()
+
+	cfg := sarama.NewConfig()
+	cfg.Producer.Return.Successes = true
+	producer, err := sarama.NewSyncProducer(e.Config.Kafka.Brokers, cfg)
+	if err != nil {
+		t.Fatalf("creating kafka producer: %v", err)
+	}
+	defer producer.Close()
+
+	for _, submission := range submissions {
+		value, err := json.Marshal(submission)
+		if err != nil {
+			t.Fatalf("encoding submission: %v", err)
+		}
+		_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+			Topic: e.Config.Kafka.Topic,
+			Key:   sarama.StringEncoder(submission.PlayerID),
+			Value: sarama.ByteEncoder(value),
+		})
+		if err != nil {
+			t.Fatalf("producing submission: %v", err)
+		}
+	}
+}
+
+// Eventually polls check until it returns true or the timeout expires
+func Eventually(t testing.TB, timeout time.Duration, what string, check func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
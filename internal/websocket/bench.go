@@ -0,0 +1,55 @@
+package websocket
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// benchLeaderboardID is the leaderboard every benchmark client subscribes to
+const benchLeaderboardID = "bench"
+
+// BenchmarkBroadcast measures fanning one changed 100-entry leaderboard update
+// out to n subscribed clients using the given entry encoding. Clients have no
+// connection; their queues are drained inside the timed loop, which adds a
+// cost far below the fan-out itself.
+func BenchmarkBroadcast(n int, encoding string) func(b *testing.B) {
+	return func(b *testing.B) {
+		h := NewHub(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		subscribers := make(map[*Client]*Subscription, n)
+		clients := make([]*Client, n)
+		for i := range clients {
+			c := NewClient(h, nil, h.logger)
+			c.encoding = encoding
+			clients[i] = c
+			h.allClients[c] = true
+			subscribers[c] = nil
+		}
+		h.clients[benchLeaderboardID] = subscribers
+		if encoding == EncodingCompact {
+			h.compactClients = n
+		}
+
+		entries := make([]domain.LeaderboardEntry, 100)
+		for i := range entries {
+			entries[i] = domain.LeaderboardEntry{Rank: int64(i + 1), PlayerID: fmt.Sprintf("player-%d", i)}
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// Change the top score so the update is never skipped as unchanged
+			entries[0].Score = int64(i)
+			h.broadcastMessage(NewLeaderboardUpdateMessage(benchLeaderboardID, entries, int64(len(entries))))
+			for _, c := range clients {
+				select {
+				case <-c.send:
+				default:
+				}
+			}
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/telemetry"
 )
 
 const (
@@ -40,12 +45,39 @@ type Client struct {
 	conn   *websocket.Conn
 	send   chan []byte
 	logger *slog.Logger
+
+	// Address the connection was admitted for by the hub's connection limiter
+	ip string
+
+	// Negotiated protocol version, and under v2 the leaderboards this client
+	// holds a snapshot of; synced is owned by the hub's Run goroutine
+	protocol int
+	synced   map[string]bool
+
+	// Negotiated encoding of leaderboard entries in broadcasts
+	encoding string
+
+	// Players this client follows, keyed by followKey; owned by readPump
+	following map[string]bool
 }
 
+// Time allowed for a query command to complete
+const queryTimeout = 5 * time.Second
+
 // ClientMessage represents a message from the client
 type ClientMessage struct {
-	Type          string `json:"type"`
-	LeaderboardID string `json:"leaderboard_id,omitempty"`
+	Type          string   `json:"type"`
+	RequestID     string   `json:"request_id,omitempty"`
+	LeaderboardID string   `json:"leaderboard_id,omitempty"`
+	Channel       string   `json:"channel,omitempty"`
+	PlayerID      string   `json:"player_id,omitempty"`
+	Limit         int      `json:"limit,omitempty"`
+	Threshold     int64    `json:"threshold,omitempty"`
+	Watch         []string `json:"watch,omitempty"`
+	Ranks         []int64  `json:"ranks,omitempty"`
+
+	// ProtocolVersion, when set, must match the version negotiated at connect
+	ProtocolVersion int `json:"protocol_version,omitempty"`
 }
 
 // NewClient creates a new WebSocket client
@@ -56,6 +88,12 @@ func NewClient(hub *Hub, conn *websocket.Conn, logger *slog.Logger) *Client {
 		conn:   conn,
 		send:   make(chan []byte, 256),
 		logger: logger,
+
+		protocol: ProtocolVersion,
+		synced:   make(map[string]bool),
+		encoding: EncodingJSON,
+
+		following: make(map[string]bool),
 	}
 }
 
@@ -96,17 +134,30 @@ func (c *Client) readPump() {
 
 // handleMessage processes incoming client messages
 func (c *Client) handleMessage(msg *ClientMessage) {
+	if msg.ProtocolVersion != 0 && msg.ProtocolVersion != c.protocol {
+		c.sendError(fmt.Sprintf("protocol_version %d does not match negotiated version %d", msg.ProtocolVersion, c.protocol))
+		return
+	}
+
 	switch msg.Type {
 	case MessageTypeSubscribe:
-		if msg.LeaderboardID != "" {
-			c.hub.Subscribe(c, msg.LeaderboardID)
+		if msg.Channel == ChannelGlobal {
+			c.hub.SubscribeGlobal(c)
+			c.sendChannelAck("subscribed", ChannelGlobal)
+		} else if msg.Channel != "" {
+			c.sendError("unknown channel: " + msg.Channel)
+		} else if msg.LeaderboardID != "" {
+			c.hub.SubscribeWithOptions(c, msg.LeaderboardID, NewSubscription(msg.Threshold, msg.Watch))
 			c.sendAck("subscribed", msg.LeaderboardID)
 		} else {
 			c.sendError("leaderboard_id required for subscribe")
 		}
 
 	case MessageTypeUnsubscribe:
-		if msg.LeaderboardID != "" {
+		if msg.Channel == ChannelGlobal {
+			c.hub.UnsubscribeGlobal(c)
+			c.sendChannelAck("unsubscribed", ChannelGlobal)
+		} else if msg.LeaderboardID != "" {
 			c.hub.Unsubscribe(c, msg.LeaderboardID)
 			c.sendAck("unsubscribed", msg.LeaderboardID)
 		}
@@ -114,11 +165,127 @@ func (c *Client) handleMessage(msg *ClientMessage) {
 	case MessageTypePing:
 		c.sendPong()
 
+	case MessageTypeGetTop, MessageTypeGetRank:
+		c.handleQuery(msg)
+
+	case MessageTypeSubscribePlayer:
+		c.handleFollow(msg)
+
+	case MessageTypeUnsubscribePlayer:
+		if msg.LeaderboardID == "" || msg.PlayerID == "" {
+			c.sendError("leaderboard_id and player_id required for unsubscribe_player")
+			return
+		}
+		delete(c.following, followKey(msg.LeaderboardID, msg.PlayerID))
+		c.hub.UnfollowPlayer(c, msg.LeaderboardID, msg.PlayerID)
+		c.sendPlayerAck("unsubscribed", msg, nil)
+
 	default:
 		c.logger.Debug("unknown message type", "type", msg.Type)
 	}
 }
 
+// handleQuery answers a get_top or get_rank command inline, echoing the request ID
+func (c *Client) handleQuery(msg *ClientMessage) {
+	if c.hub.queries == nil {
+		c.sendResponse(msg, nil, "queries not supported")
+		return
+	}
+	if msg.LeaderboardID == "" {
+		c.sendResponse(msg, nil, "leaderboard_id required for "+msg.Type)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	defer telemetry.Recover(ctx, c.hub.reporter, map[string]string{
+		"component":      "websocket",
+		"client_id":      c.id,
+		"message_type":   msg.Type,
+		"leaderboard_id": msg.LeaderboardID,
+	})
+
+	var data interface{}
+	var err error
+	switch msg.Type {
+	case MessageTypeGetTop:
+		limit := msg.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		data, err = c.hub.queries.GetTopN(ctx, msg.LeaderboardID, limit)
+	case MessageTypeGetRank:
+		if msg.PlayerID == "" {
+			c.sendResponse(msg, nil, "player_id required for get_rank")
+			return
+		}
+		data, err = c.hub.queries.GetPlayerRank(ctx, msg.LeaderboardID, msg.PlayerID)
+	}
+
+	if err != nil {
+		if domain.IsNotFoundError(err) {
+			c.sendResponse(msg, nil, err.Error())
+			return
+		}
+		c.logger.Error("websocket query failed", "type", msg.Type, "error", err)
+		c.sendResponse(msg, nil, domain.ErrInternalError.Error())
+		return
+	}
+
+	c.sendResponse(msg, data, "")
+}
+
+// handleFollow starts following a player and acknowledges with the player's
+// current standing, which is null while they have no score on the board
+func (c *Client) handleFollow(msg *ClientMessage) {
+	if msg.LeaderboardID == "" || msg.PlayerID == "" {
+		c.sendError("leaderboard_id and player_id required for subscribe_player")
+		return
+	}
+	if IsPattern(msg.LeaderboardID) {
+		c.sendError("subscribe_player needs an exact leaderboard_id")
+		return
+	}
+	if c.hub.queries == nil {
+		c.sendError("subscribe_player not supported")
+		return
+	}
+	key := followKey(msg.LeaderboardID, msg.PlayerID)
+	if !c.following[key] && len(c.following) >= maxFollowsPerClient {
+		c.sendError(fmt.Sprintf("cannot follow more than %d players", maxFollowsPerClient))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	defer telemetry.Recover(ctx, c.hub.reporter, map[string]string{
+		"component":      "websocket",
+		"client_id":      c.id,
+		"message_type":   msg.Type,
+		"leaderboard_id": msg.LeaderboardID,
+	})
+
+	entry, err := c.hub.queries.FollowPlayer(ctx, msg.LeaderboardID, msg.PlayerID)
+	if err != nil && !errors.Is(err, domain.ErrPlayerNotFound) {
+		if domain.IsNotFoundError(err) {
+			c.sendError(err.Error())
+			return
+		}
+		c.logger.Error("websocket follow failed", "leaderboard_id", msg.LeaderboardID, "error", err)
+		c.sendError(domain.ErrInternalError.Error())
+		return
+	}
+
+	c.following[key] = true
+	c.hub.FollowPlayer(c, msg.LeaderboardID, msg.PlayerID, NewPlayerFollow(msg.Ranks))
+	c.sendPlayerAck("subscribed", msg, entry)
+}
+
+// followKey identifies a followed player across leaderboards
+func followKey(leaderboardID, playerID string) string {
+	return leaderboardID + "\x00" + playerID
+}
+
 // writePump pumps messages from the hub to the WebSocket connection
 func (c *Client) writePump() {
 	ticker := time.NewTicker(pingPeriod)
@@ -192,6 +359,92 @@ func (c *Client) sendAck(action, leaderboardID string) {
 	}
 }
 
+// sendPlayerAck acknowledges a follow change, with the player's standing when following
+func (c *Client) sendPlayerAck(action string, req *ClientMessage, entry *domain.LeaderboardEntry) {
+	msg := Message{
+		Type:          action,
+		RequestID:     req.RequestID,
+		LeaderboardID: req.LeaderboardID,
+		Data: map[string]interface{}{
+			"status":    "ok",
+			"player_id": req.PlayerID,
+			"entry":     entry,
+		},
+		Timestamp: time.Now(),
+	}
+	data, _ := json.Marshal(msg)
+	select {
+	case c.send <- data:
+	default:
+	}
+}
+
+// sendChannelAck acknowledges a subscription change on a named channel
+func (c *Client) sendChannelAck(action, channel string) {
+	msg := Message{
+		Type:      action,
+		Channel:   channel,
+		Data:      map[string]string{"status": "ok"},
+		Timestamp: time.Now(),
+	}
+	data, _ := json.Marshal(msg)
+	select {
+	case c.send <- data:
+	default:
+	}
+}
+
+// sendResponse sends the result of a query command, correlated by request ID
+func (c *Client) sendResponse(req *ClientMessage, data interface{}, errMsg string) {
+	msg := Message{
+		Type:          MessageTypeResponse,
+		RequestID:     req.RequestID,
+		LeaderboardID: req.LeaderboardID,
+		Data:          data,
+		Timestamp:     time.Now(),
+	}
+	if errMsg != "" {
+		msg.Type = MessageTypeError
+		msg.Data = map[string]string{"error": errMsg}
+	}
+	payload, _ := json.Marshal(msg)
+	select {
+	case c.send <- payload:
+	default:
+	}
+}
+
+// sendHello greets a new connection with its ID and the protocol it speaks
+func (c *Client) sendHello() {
+	now := time.Now()
+	hello := Hello{
+		ClientID:           c.id,
+		ProtocolVersion:    c.protocol,
+		SupportedVersions:  SupportedProtocolVersions,
+		Encoding:           c.encoding,
+		SupportedEncodings: SupportedEncodings,
+		ServerTime:         now,
+		PingIntervalMs:     pingPeriod.Milliseconds(),
+		PongTimeoutMs:      pongWait.Milliseconds(),
+		MessageTypes:       clientMessageTypes,
+		EventTypes:         serverMessageTypes,
+		Channels:           []string{ChannelGlobal},
+	}
+	if c.encoding == EncodingCompact {
+		hello.EntryColumns = CompactEntryColumns
+	}
+	msg := Message{
+		Type:      MessageTypeHello,
+		Data:      hello,
+		Timestamp: now,
+	}
+	data, _ := json.Marshal(msg)
+	select {
+	case c.send <- data:
+	default:
+	}
+}
+
 // sendPong sends a pong response
 func (c *Client) sendPong() {
 	msg := Message{
@@ -207,13 +460,48 @@ func (c *Client) sendPong() {
 
 // ServeWs handles WebSocket requests from peers
 func ServeWs(hub *Hub, logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	protocol, err := negotiateProtocol(r)
+	if err != nil {
+		writeRejection(w, http.StatusBadRequest, err)
+		return
+	}
+	encoding, err := negotiateEncoding(r)
+	if err != nil {
+		writeRejection(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Refuse connection floods before paying for the upgrade
+	var ip string
+	if hub.limiter != nil {
+		ip = hub.limiter.clientIP(r)
+		if err := hub.limiter.Acquire(ip); err != nil {
+			status := http.StatusTooManyRequests
+			if errors.Is(err, ErrTooManyConnections) {
+				status = http.StatusServiceUnavailable
+			}
+			logger.Debug("websocket connection rejected", "remote_ip", ip, "error", err)
+			writeRejection(w, status, err)
+			return
+		}
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		if hub.limiter != nil {
+			hub.limiter.Release(ip)
+		}
 		logger.Error("websocket upgrade failed", "error", err)
 		return
 	}
 
 	client := NewClient(hub, conn, logger)
+	client.ip = ip
+	client.protocol = protocol
+	client.encoding = encoding
+
+	// Queue the hello before registering so it is the first message the client reads
+	client.sendHello()
 	hub.Register(client)
 
 	// Start client goroutines
@@ -223,3 +511,13 @@ func ServeWs(hub *Hub, logger *slog.Logger, w http.ResponseWriter, r *http.Reque
 	logger.Debug("new websocket connection", "client_id", client.id)
 }
 
+
+// writeRejection answers a refused handshake in the REST API's error shape
+func writeRejection(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,122 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+)
+
+// coalescer holds leaderboard_update messages that arrive faster than their
+// board's rate allows. Only the latest held update of a board is kept and it
+// is sent when the board's interval has passed. Only used from the Run goroutine.
+type coalescer struct {
+	config *config.CoalesceConfig
+
+	// next is when each board may send again, set while a flush timer is
+	// armed for it; pending is its held update and held the rank changes
+	// broadcast while the update was held, for threshold subscribers
+	next    map[string]time.Time
+	pending map[string]*Message
+	held    map[string][]PlayerUpdate
+
+	// replaced counts updates superseded by a later one before they were sent
+	replaced atomic.Int64
+}
+
+func newCoalescer(cfg *config.CoalesceConfig) *coalescer {
+	return &coalescer{
+		config:  cfg,
+		next:    make(map[string]time.Time),
+		pending: make(map[string]*Message),
+		held:    make(map[string][]PlayerUpdate),
+	}
+}
+
+// SetCoalescing caps how often leaderboard updates are sent per board; call before Run
+func (h *Hub) SetCoalescing(cfg *config.CoalesceConfig) {
+	h.coalescer = newCoalescer(cfg)
+}
+
+// GetCoalescedUpdates returns how many leaderboard updates were superseded
+// by a later one before they were sent
+func (h *Hub) GetCoalescedUpdates() int64 {
+	if h.coalescer == nil {
+		return 0
+	}
+	return h.coalescer.replaced.Load()
+}
+
+// coalesce drops the leaderboard updates that must wait for their board's
+// interval from messages, holding the latest of each. Only called from the
+// Run goroutine.
+func (h *Hub) coalesce(messages []*Message) []*Message {
+	if h.coalescer == nil {
+		return messages
+	}
+	c := h.coalescer
+	now := time.Now()
+
+	var changes map[string][]PlayerUpdate
+	kept := messages[:0:0]
+	for _, message := range messages {
+		if message.Type != MessageTypeLeaderboardUpdate || message.LeaderboardID == "" {
+			kept = append(kept, message)
+			continue
+		}
+		id := message.LeaderboardID
+		interval := c.config.Interval(id)
+		if interval <= 0 {
+			kept = append(kept, message)
+			continue
+		}
+
+		// A board without a flush timer sends now and is limited until it fires
+		if _, limited := c.next[id]; !limited {
+			c.next[id] = now.Add(interval)
+			h.scheduleFlush(id, interval)
+			kept = append(kept, message)
+			continue
+		}
+
+		if _, waiting := c.pending[id]; waiting {
+			c.replaced.Add(1)
+		}
+		if changes == nil {
+			changes = rankChanges(messages)
+		}
+		c.pending[id] = message
+		c.held[id] = append(c.held[id], changes[id]...)
+	}
+	return kept
+}
+
+// scheduleFlush asks the Run goroutine to flush a board after d
+func (h *Hub) scheduleFlush(leaderboardID string, d time.Duration) {
+	time.AfterFunc(d, func() {
+		select {
+		case h.flushes <- leaderboardID:
+		case <-h.ctx.Done():
+		}
+	})
+}
+
+// flushHeld sends a board's held update once its interval has passed, with
+// the rank changes seen while it was held. A board with nothing held is no
+// longer limited. Only called from the Run goroutine.
+func (h *Hub) flushHeld(leaderboardID string) {
+	c := h.coalescer
+	message, ok := c.pending[leaderboardID]
+	if !ok {
+		delete(c.next, leaderboardID)
+		return
+	}
+	delete(c.pending, leaderboardID)
+	message.held = c.held[leaderboardID]
+	delete(c.held, leaderboardID)
+
+	interval := c.config.Interval(leaderboardID)
+	c.next[leaderboardID] = time.Now().Add(interval)
+	h.scheduleFlush(leaderboardID, interval)
+	h.sendMessages([]*Message{message})
+}
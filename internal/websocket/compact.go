@@ -0,0 +1,282 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Entry encodings. json sends leaderboard entries as objects with field names;
+// compact sends each entry of a broadcast as an array in CompactEntryColumns
+// order, which is far smaller for large boards and cheaper to encode.
+const (
+	EncodingJSON    = "json"
+	EncodingCompact = "compact"
+)
+
+// SupportedEncodings lists the encodings a client may negotiate
+var SupportedEncodings = []string{EncodingJSON, EncodingCompact}
+
+// CompactEntryColumns names the positions of a compact entry. Trailing columns
+// are left out when empty, so most entries are [rank, player_id, score].
+var CompactEntryColumns = []string{"rank", "player_id", "score", "username", "flags"}
+
+// Bits of the flags column of a compact entry
+const (
+	CompactFlagProvisional = 1 << iota
+	CompactFlagGhost
+)
+
+// negotiateEncoding reads the entry encoding a client asked for with
+// ?encoding= on the handshake, defaulting to json
+func negotiateEncoding(r *http.Request) (string, error) {
+	encoding := r.URL.Query().Get("encoding")
+	if encoding == "" {
+		return EncodingJSON, nil
+	}
+	for _, supported := range SupportedEncodings {
+		if encoding == supported {
+			return encoding, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported encoding %q", encoding)
+}
+
+// Precomputed fragments of the compact message envelope
+var (
+	fragmentType          = []byte(`{"type":`)
+	fragmentLeaderboardID = []byte(`,"leaderboard_id":`)
+	fragmentSeq           = []byte(`,"seq":`)
+	fragmentData          = []byte(`,"data":{"leaderboard_id":`)
+	fragmentEntries       = []byte(`,"entries":`)
+	fragmentBaseSeq       = []byte(`,"base_seq":`)
+	fragmentUpserts       = []byte(`,"upserts":`)
+	fragmentRemoved       = []byte(`,"removed":`)
+	fragmentEntered       = []byte(`,"entered":`)
+	fragmentMoved         = []byte(`,"moved":`)
+	fragmentTotalPlayers  = []byte(`,"total_players":`)
+	fragmentTimestamp     = []byte(`},"timestamp":"`)
+
+	fragmentRequestID    = []byte(`,"request_id":`)
+	fragmentChannel      = []byte(`,"channel":`)
+	fragmentRawData      = []byte(`,"data":`)
+	fragmentRawTimestamp = []byte(`,"timestamp":"`)
+)
+
+// encodeBuffers recycles the scratch space used to encode broadcasts, which
+// otherwise dominates the hub's allocations
+var encodeBuffers = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 4096)
+		return &b
+	},
+}
+
+// encodePooled builds a payload in a pooled scratch buffer and returns an
+// exact-size copy, since payloads outlive the call in client queues
+func encodePooled(build func(dst []byte) []byte) []byte {
+	bp := encodeBuffers.Get().(*[]byte)
+	b := build((*bp)[:0])
+	out := make([]byte, len(b))
+	copy(out, b)
+	*bp = b
+	encodeBuffers.Put(bp)
+	return out
+}
+
+// marshalPooled is json.Marshal writing through a pooled buffer
+func marshalPooled(v interface{}) ([]byte, error) {
+	bp := encodeBuffers.Get().(*[]byte)
+	buf := bytes.NewBuffer((*bp)[:0])
+	err := json.NewEncoder(buf).Encode(v)
+	var out []byte
+	if err == nil {
+		// Encode appends a newline that Marshal does not
+		b := bytes.TrimSuffix(buf.Bytes(), []byte{'\n'})
+		out = make([]byte, len(b))
+		copy(out, b)
+	}
+	*bp = buf.Bytes()
+	encodeBuffers.Put(bp)
+	return out, err
+}
+
+// encodeCompactUpdate encodes a leaderboard_update with compact entries
+func encodeCompactUpdate(message *Message, seq uint64, update LeaderboardUpdate) []byte {
+	return encodePooled(func(b []byte) []byte {
+		b = appendEnvelopeStart(b, message, seq)
+		b = append(b, fragmentEntries...)
+		b = appendCompactEntries(b, update.Entries)
+		b = append(b, fragmentTotalPlayers...)
+		b = strconv.AppendInt(b, update.TotalPlayers, 10)
+		return appendEnvelopeEnd(b, message.Timestamp)
+	})
+}
+
+// encodeCompactDelta encodes a leaderboard_delta with compact upserts and
+// moves as [player_id, old_rank, rank]
+func encodeCompactDelta(message *Message, seq uint64, delta LeaderboardDelta) []byte {
+	return encodePooled(func(b []byte) []byte {
+		b = appendEnvelopeStart(b, message, seq)
+		b = append(b, fragmentBaseSeq...)
+		b = strconv.AppendUint(b, delta.BaseSeq, 10)
+		if len(delta.Upserts) > 0 {
+			b = append(b, fragmentUpserts...)
+			b = appendCompactEntries(b, delta.Upserts)
+		}
+		if len(delta.Removed) > 0 {
+			b = append(b, fragmentRemoved...)
+			b = appendJSONStrings(b, delta.Removed)
+		}
+		if len(delta.Entered) > 0 {
+			b = append(b, fragmentEntered...)
+			b = appendJSONStrings(b, delta.Entered)
+		}
+		if len(delta.Moved) > 0 {
+			b = append(b, fragmentMoved...)
+			b = append(b, '[')
+			for i, move := range delta.Moved {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = append(b, '[')
+				b = appendJSONString(b, move.PlayerID)
+				b = append(b, ',')
+				b = strconv.AppendInt(b, move.OldRank, 10)
+				b = append(b, ',')
+				b = strconv.AppendInt(b, move.Rank, 10)
+				b = append(b, ']')
+			}
+			b = append(b, ']')
+		}
+		b = append(b, fragmentTotalPlayers...)
+		b = strconv.AppendInt(b, delta.TotalPlayers, 10)
+		return appendEnvelopeEnd(b, message.Timestamp)
+	})
+}
+
+// encodeWithData encodes a message around its already marshaled data,
+// producing the same bytes as marshaling the message itself
+func encodeWithData(message *Message, data []byte) []byte {
+	return encodePooled(func(b []byte) []byte {
+		b = append(b, fragmentType...)
+		b = appendJSONString(b, message.Type)
+		if message.RequestID != "" {
+			b = append(b, fragmentRequestID...)
+			b = appendJSONString(b, message.RequestID)
+		}
+		if message.LeaderboardID != "" {
+			b = append(b, fragmentLeaderboardID...)
+			b = appendJSONString(b, message.LeaderboardID)
+		}
+		if message.Channel != "" {
+			b = append(b, fragmentChannel...)
+			b = appendJSONString(b, message.Channel)
+		}
+		if message.Seq > 0 {
+			b = append(b, fragmentSeq...)
+			b = strconv.AppendUint(b, message.Seq, 10)
+		}
+		b = append(b, fragmentRawData...)
+		b = append(b, data...)
+		b = append(b, fragmentRawTimestamp...)
+		b = message.Timestamp.AppendFormat(b, time.RFC3339Nano)
+		return append(b, '"', '}')
+	})
+}
+
+// appendEnvelopeStart writes the message fields up to the open data object
+func appendEnvelopeStart(b []byte, message *Message, seq uint64) []byte {
+	b = append(b, fragmentType...)
+	b = appendJSONString(b, message.Type)
+	b = append(b, fragmentLeaderboardID...)
+	b = appendJSONString(b, message.LeaderboardID)
+	if seq > 0 {
+		b = append(b, fragmentSeq...)
+		b = strconv.AppendUint(b, seq, 10)
+	}
+	b = append(b, fragmentData...)
+	return appendJSONString(b, message.LeaderboardID)
+}
+
+// appendEnvelopeEnd closes the data object and writes the timestamp as time.Time marshals it
+func appendEnvelopeEnd(b []byte, timestamp time.Time) []byte {
+	b = append(b, fragmentTimestamp...)
+	b = timestamp.AppendFormat(b, time.RFC3339Nano)
+	return append(b, '"', '}')
+}
+
+// appendCompactEntries writes entries as an array of compact entry arrays
+func appendCompactEntries(b []byte, entries []domain.LeaderboardEntry) []byte {
+	b = append(b, '[')
+	for i := range entries {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendCompactEntry(b, &entries[i])
+	}
+	return append(b, ']')
+}
+
+// appendCompactEntry writes one entry as [rank, player_id, score, username, flags],
+// dropping trailing columns that are empty
+func appendCompactEntry(b []byte, entry *domain.LeaderboardEntry) []byte {
+	var flags int
+	if entry.Provisional {
+		flags |= CompactFlagProvisional
+	}
+	if entry.IsGhost {
+		flags |= CompactFlagGhost
+	}
+
+	b = append(b, '[')
+	b = strconv.AppendInt(b, entry.Rank, 10)
+	b = append(b, ',')
+	b = appendJSONString(b, entry.PlayerID)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, entry.Score, 10)
+	if entry.Username != "" || flags != 0 {
+		b = append(b, ',')
+		b = appendJSONString(b, entry.Username)
+	}
+	if flags != 0 {
+		b = append(b, ',')
+		b = strconv.AppendInt(b, int64(flags), 10)
+	}
+	return append(b, ']')
+}
+
+// appendJSONStrings writes an array of JSON strings
+func appendJSONStrings(b []byte, values []string) []byte {
+	b = append(b, '[')
+	for i, s := range values {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, s)
+	}
+	return append(b, ']')
+}
+
+// appendJSONString writes s as a JSON string. Plain printable ASCII, which
+// covers nearly every player ID, is copied directly; anything else goes
+// through encoding/json so escaping matches the rest of the protocol.
+func appendJSONString(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			quoted, _ := json.Marshal(s)
+			return append(b, quoted...)
+		}
+	}
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"')
+}
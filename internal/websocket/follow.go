@@ -0,0 +1,133 @@
+package websocket
+
+import "sort"
+
+// maxFollowsPerClient caps how many players one connection may follow, since
+// every followed player is re-ranked on each submission to their board
+const maxFollowsPerClient = 20
+
+// PlayerFollow holds a client's delivery options for one followed player.
+// The zero value delivers every score and rank change.
+type PlayerFollow struct {
+	// Ranks limits rank-only changes to those crossing one of these ranks,
+	// e.g. [1, 10, 100] for entering or leaving the top 1, 10 and 100
+	Ranks []int64
+}
+
+// NewPlayerFollow creates follow options from a client's subscribe_player request
+func NewPlayerFollow(ranks []int64) *PlayerFollow {
+	follow := &PlayerFollow{}
+	for _, rank := range ranks {
+		if rank > 0 {
+			follow.Ranks = append(follow.Ranks, rank)
+		}
+	}
+	sort.Slice(follow.Ranks, func(i, j int) bool { return follow.Ranks[i] < follow.Ranks[j] })
+	return follow
+}
+
+// wants reports whether an update of the followed player should be delivered:
+// always when the score changed, otherwise when the rank crossed one of the
+// follower's ranks, or changed at all when none were given
+func (f *PlayerFollow) wants(update PlayerUpdate) bool {
+	if update.Score != update.OldScore {
+		return true
+	}
+	if len(f.Ranks) == 0 {
+		return update.Rank != update.OldRank
+	}
+	for _, rank := range f.Ranks {
+		wasIn := update.OldRank > 0 && update.OldRank <= rank
+		isIn := update.Rank > 0 && update.Rank <= rank
+		if wasIn != isIn {
+			return true
+		}
+	}
+	return false
+}
+
+// NewFollowedPlayerUpdateMessage builds a player_update message delivered only
+// to the followers of the update's player. OldScore must be set so score
+// changes can be told from rank-only ones.
+func NewFollowedPlayerUpdateMessage(update PlayerUpdate) *Message {
+	message := NewPlayerUpdateMessage(update)
+	message.follow = true
+	return message
+}
+
+// FollowPlayer delivers a player's updates on a leaderboard to a client,
+// replacing the options of an earlier follow of the same player
+func (h *Hub) FollowPlayer(client *Client, leaderboardID, playerID string, follow *PlayerFollow) {
+	h.subscribe <- &subscriptionRequest{
+		client:        client,
+		leaderboardID: leaderboardID,
+		playerID:      playerID,
+		follow:        follow,
+	}
+}
+
+// UnfollowPlayer stops delivering a player's updates to a client
+func (h *Hub) UnfollowPlayer(client *Client, leaderboardID, playerID string) {
+	h.unsubscribe <- &subscriptionRequest{
+		client:        client,
+		leaderboardID: leaderboardID,
+		playerID:      playerID,
+	}
+}
+
+// GetFollowedPlayers returns the players of a leaderboard followed by at least one client
+func (h *Hub) GetFollowedPlayers(leaderboardID string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	players := make([]string, 0, len(h.followers[leaderboardID]))
+	for playerID := range h.followers[leaderboardID] {
+		players = append(players, playerID)
+	}
+	return players
+}
+
+// addFollower records a follow. Only called from the Run goroutine.
+func (h *Hub) addFollower(req *subscriptionRequest) {
+	h.mu.Lock()
+	players, ok := h.followers[req.leaderboardID]
+	if !ok {
+		players = make(map[string]map[*Client]*PlayerFollow)
+		h.followers[req.leaderboardID] = players
+	}
+	if _, ok := players[req.playerID]; !ok {
+		players[req.playerID] = make(map[*Client]*PlayerFollow)
+	}
+	players[req.playerID][req.client] = req.follow
+	h.mu.Unlock()
+	h.logger.Debug("client followed player", "client_id", req.client.id, "leaderboard_id", req.leaderboardID, "player_id", req.playerID)
+}
+
+// removeFollower drops a follow. Only called from the Run goroutine with h.mu held.
+func (h *Hub) removeFollower(client *Client, leaderboardID, playerID string) {
+	players := h.followers[leaderboardID]
+	clients, ok := players[playerID]
+	if !ok {
+		return
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(players, playerID)
+		if len(players) == 0 {
+			delete(h.followers, leaderboardID)
+		}
+	}
+}
+
+// deliverFollowers sends a followed-player update to the clients following
+// that player whose options accept it. Called with h.mu held for reading.
+func (h *Hub) deliverFollowers(message *Message, payload []byte) {
+	update, ok := message.Data.(PlayerUpdate)
+	if !ok {
+		return
+	}
+	for client, follow := range h.followers[message.LeaderboardID][update.PlayerID] {
+		if follow == nil || follow.wants(update) {
+			h.deliver(client, payload)
+		}
+	}
+}
@@ -1,32 +1,109 @@
 package websocket
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/leaderboard-redis/internal/chaos"
 	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/features"
+	"github.com/leaderboard-redis/internal/telemetry"
 )
 
 // Message types
 const (
 	MessageTypeLeaderboardUpdate = "leaderboard_update"
 	MessageTypePlayerUpdate      = "player_update"
+	MessageTypePlayerRemoved     = "player_removed"
 	MessageTypeSubscribe         = "subscribe"
 	MessageTypeUnsubscribe       = "unsubscribe"
 	MessageTypePing              = "ping"
 	MessageTypePong              = "pong"
 	MessageTypeError             = "error"
+	MessageTypeGetTop            = "get_top"
+	MessageTypeGetRank           = "get_rank"
+	MessageTypeResponse          = "response"
+	MessageTypeGlobalEvent       = "global_event"
+	MessageTypeHello             = "hello"
+	MessageTypeSubscribePlayer   = "subscribe_player"
+	MessageTypeUnsubscribePlayer = "unsubscribe_player"
 )
 
+// ProtocolVersion is the protocol spoken by clients that do not negotiate one
+const ProtocolVersion = ProtocolV1
+
+// clientMessageTypes are the commands the server accepts from clients
+var clientMessageTypes = []string{
+	MessageTypeSubscribe,
+	MessageTypeUnsubscribe,
+	MessageTypePing,
+	MessageTypeGetTop,
+	MessageTypeGetRank,
+	MessageTypeSubscribePlayer,
+	MessageTypeUnsubscribePlayer,
+}
+
+// serverMessageTypes are the messages the server may send to clients
+var serverMessageTypes = []string{
+	MessageTypeHello,
+	MessageTypeLeaderboardUpdate,
+	MessageTypeLeaderboardDelta,
+	MessageTypePlayerUpdate,
+	MessageTypePlayerRemoved,
+	MessageTypeGlobalEvent,
+	MessageTypeResponse,
+	MessageTypePong,
+	MessageTypeError,
+}
+
+// ChannelGlobal is the service-wide channel carrying events from every leaderboard
+const ChannelGlobal = "global"
+
+// QueryHandler answers leaderboard queries sent over the socket
+type QueryHandler interface {
+	GetTopN(ctx context.Context, leaderboardID string, n int) ([]domain.LeaderboardEntry, error)
+	GetPlayerRank(ctx context.Context, leaderboardID, playerID string) (*domain.LeaderboardEntry, error)
+
+	// FollowPlayer returns a player's current standing for a subscribe_player
+	// command and starts tracking it for followed-player updates
+	FollowPlayer(ctx context.Context, leaderboardID, playerID string) (*domain.LeaderboardEntry, error)
+}
+
 // Message represents a WebSocket message
 type Message struct {
 	Type          string      `json:"type"`
+	RequestID     string      `json:"request_id,omitempty"`
 	LeaderboardID string      `json:"leaderboard_id,omitempty"`
+	Channel       string      `json:"channel,omitempty"`
+	Seq           uint64      `json:"seq,omitempty"`
 	Data          interface{} `json:"data,omitempty"`
 	Timestamp     time.Time   `json:"timestamp"`
+
+	// payload caches the encoded message once it has been serialized
+	payload []byte
+
+	// follow routes a player_update to the player's followers only
+	follow bool
+
+	// held lists the rank changes broadcast while a coalesced
+	// leaderboard_update was held back
+	held []PlayerUpdate
+}
+
+// encode serializes the message, reusing the cached payload on repeated calls
+func (m *Message) encode() ([]byte, error) {
+	if m.payload != nil {
+		return m.payload, nil
+	}
+	data, err := marshalPooled(m)
+	if err != nil {
+		return nil, err
+	}
+	m.payload = data
+	return data, nil
 }
 
 // LeaderboardUpdate contains leaderboard data for broadcast
@@ -36,14 +113,74 @@ type LeaderboardUpdate struct {
 	TotalPlayers  int64                    `json:"total_players"`
 }
 
+// PlayerUpdate describes a player's rank change along with their immediate neighbours.
+// OldScore is only set on updates sent to the player's followers.
+type PlayerUpdate struct {
+	LeaderboardID string                   `json:"leaderboard_id"`
+	PlayerID      string                   `json:"player_id"`
+	Score         int64                    `json:"score"`
+	Rank          int64                    `json:"rank"`
+	OldRank       int64                    `json:"old_rank,omitempty"`
+	OldScore      int64                    `json:"old_score,omitempty"`
+	Above         *domain.LeaderboardEntry `json:"above,omitempty"`
+	Below         *domain.LeaderboardEntry `json:"below,omitempty"`
+}
+
+// PlayerRemoved tells clients to drop a player removed from a leaderboard.
+// OldRank and OldScore describe the player's last standing, when known.
+type PlayerRemoved struct {
+	LeaderboardID string `json:"leaderboard_id"`
+	PlayerID      string `json:"player_id"`
+	OldRank       int64  `json:"old_rank,omitempty"`
+	OldScore      int64  `json:"old_score,omitempty"`
+}
+
+// Hello is sent once on connect so SDKs can negotiate features and detect
+// protocol mismatches before subscribing
+type Hello struct {
+	ClientID        string `json:"client_id"`
+	ProtocolVersion int    `json:"protocol_version"`
+	// SupportedVersions can be requested with ?protocol_version= on the handshake
+	SupportedVersions []int `json:"supported_versions"`
+	// Encoding of leaderboard entries in broadcasts, chosen with ?encoding=;
+	// EntryColumns names the positions of a compact entry
+	Encoding           string    `json:"encoding"`
+	SupportedEncodings []string  `json:"supported_encodings"`
+	EntryColumns       []string  `json:"entry_columns,omitempty"`
+	ServerTime         time.Time `json:"server_time"`
+	// PingIntervalMs is how often the server pings; a connection that does not
+	// answer within PongTimeoutMs is closed
+	PingIntervalMs int64    `json:"ping_interval_ms"`
+	PongTimeoutMs  int64    `json:"pong_timeout_ms"`
+	MessageTypes   []string `json:"message_types"`
+	EventTypes     []string `json:"event_types"`
+	Channels       []string `json:"channels"`
+}
+
 // Hub maintains the set of active clients and broadcasts messages
 type Hub struct {
 	// Registered clients by leaderboard ID
-	clients map[string]map[*Client]bool
+	clients map[string]map[*Client]*Subscription
 
 	// All connected clients
 	allClients map[*Client]bool
 
+	// Clients subscribed to the global channel
+	global map[*Client]bool
+
+	// Wildcard subscriptions by pattern, e.g. game1-*
+	patterns map[string]map[*Client]*Subscription
+
+	// Followed players by leaderboard ID, then player ID
+	followers map[string]map[string]map[*Client]*PlayerFollow
+
+	// Server-sent events streams by leaderboard ID
+	streams map[string]map[*Stream]bool
+
+	// Patterns matching each leaderboard ID routed so far, guarded by matchMu
+	patternMatches map[string][]string
+	matchMu        sync.Mutex
+
 	// Register requests from clients
 	register chan *Client
 
@@ -53,15 +190,56 @@ type Hub struct {
 	// Inbound messages from clients
 	broadcast chan *Message
 
+	// Batches of messages delivered in a single hub pass
+	broadcastBatch chan []*Message
+
 	// Subscription requests
 	subscribe chan *subscriptionRequest
 
 	// Unsubscription requests
 	unsubscribe chan *subscriptionRequest
 
+	// Last leaderboard_update data sent per leaderboard, owned by the Run goroutine
+	lastUpdates map[string][]byte
+
+	// Protocol v2 sequence numbers and the entries and totals they describe,
+	// per leaderboard, owned by the Run goroutine
+	seqs        map[string]uint64
+	lastEntries map[string][]domain.LeaderboardEntry
+	lastTotals  map[string]int64
+
+	// Connected clients using the compact encoding, owned by the Run goroutine
+	compactClients int
+
+	// Recent events of streamed leaderboards for Last-Event-ID resumption,
+	// owned by the Run goroutine, and the event ID prefix of this run
+	streamRings  map[string]*streamRing
+	streamEpoch  string
+	openStreams  chan *streamRequest
+	closeStreams chan *Stream
+
 	// Mutex for thread-safe operations
 	mu sync.RWMutex
 
+	// Answers client query commands; nil disables them
+	queries QueryHandler
+
+	// Receives hub errors and recovered panics
+	reporter telemetry.Reporter
+
+	// Drops broadcasts during resilience testing; nil disables it
+	faults *chaos.Injector
+
+	// Gates delta broadcasts per leaderboard; nil uses the flag defaults
+	features *features.Provider
+
+	// Admits new connections; nil accepts every handshake
+	limiter *ConnectionLimiter
+
+	// Holds updates of busy boards to their configured rate; nil sends every update
+	coalescer *coalescer
+	flushes   chan string
+
 	// Logger
 	logger *slog.Logger
 
@@ -73,22 +251,44 @@ type Hub struct {
 type subscriptionRequest struct {
 	client        *Client
 	leaderboardID string
+	options       *Subscription
+	global        bool
+
+	// playerID and follow are set for subscribe_player commands
+	playerID string
+	follow   *PlayerFollow
 }
 
 // NewHub creates a new Hub
 func NewHub(logger *slog.Logger) *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Hub{
-		clients:     make(map[string]map[*Client]bool),
-		allClients:  make(map[*Client]bool),
-		register:    make(chan *Client),
-		unregister:  make(chan *Client),
-		broadcast:   make(chan *Message, 256),
-		subscribe:   make(chan *subscriptionRequest, 64),
-		unsubscribe: make(chan *subscriptionRequest, 64),
-		logger:      logger,
-		ctx:         ctx,
-		cancel:      cancel,
+		clients:        make(map[string]map[*Client]*Subscription),
+		allClients:     make(map[*Client]bool),
+		global:         make(map[*Client]bool),
+		patterns:       make(map[string]map[*Client]*Subscription),
+		followers:      make(map[string]map[string]map[*Client]*PlayerFollow),
+		streams:        make(map[string]map[*Stream]bool),
+		streamRings:    make(map[string]*streamRing),
+		streamEpoch:    newStreamEpoch(),
+		openStreams:    make(chan *streamRequest),
+		closeStreams:   make(chan *Stream),
+		patternMatches: make(map[string][]string),
+		register:       make(chan *Client),
+		unregister:     make(chan *Client),
+		broadcast:      make(chan *Message, 256),
+		broadcastBatch: make(chan []*Message, 64),
+		flushes:        make(chan string, 64),
+		lastUpdates:    make(map[string][]byte),
+		seqs:           make(map[string]uint64),
+		lastEntries:    make(map[string][]domain.LeaderboardEntry),
+		lastTotals:     make(map[string]int64),
+		subscribe:      make(chan *subscriptionRequest, 64),
+		unsubscribe:    make(chan *subscriptionRequest, 64),
+		reporter:       telemetry.Nop(),
+		logger:         logger,
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 }
 
@@ -105,12 +305,19 @@ func (h *Hub) Run() {
 			h.mu.Lock()
 			h.allClients[client] = true
 			h.mu.Unlock()
+			if client.encoding == EncodingCompact {
+				h.compactClients++
+			}
 			h.logger.Debug("client registered", "client_id", client.id)
 
 		case client := <-h.unregister:
 			h.mu.Lock()
 			if _, ok := h.allClients[client]; ok {
 				delete(h.allClients, client)
+				delete(h.global, client)
+				if client.encoding == EncodingCompact {
+					h.compactClients--
+				}
 				// Remove from all leaderboard subscriptions
 				for lbID, clients := range h.clients {
 					if _, ok := clients[client]; ok {
@@ -120,21 +327,76 @@ func (h *Hub) Run() {
 						}
 					}
 				}
+				for pattern, clients := range h.patterns {
+					if _, ok := clients[client]; ok {
+						delete(clients, client)
+						if len(clients) == 0 {
+							delete(h.patterns, pattern)
+							h.resetPatternMatches()
+						}
+					}
+				}
+				for lbID, players := range h.followers {
+					for playerID, clients := range players {
+						if _, ok := clients[client]; ok {
+							h.removeFollower(client, lbID, playerID)
+						}
+					}
+				}
 				close(client.send)
+				if h.limiter != nil {
+					h.limiter.Release(client.ip)
+				}
 			}
 			h.mu.Unlock()
 			h.logger.Debug("client unregistered", "client_id", client.id)
 
 		case req := <-h.subscribe:
+			if req.playerID != "" {
+				h.addFollower(req)
+				continue
+			}
+			if req.global {
+				h.mu.Lock()
+				h.global[req.client] = true
+				h.mu.Unlock()
+				h.logger.Debug("client subscribed", "client_id", req.client.id, "channel", ChannelGlobal)
+				continue
+			}
+			if IsPattern(req.leaderboardID) {
+				h.subscribePattern(req)
+				continue
+			}
 			h.mu.Lock()
 			if _, ok := h.clients[req.leaderboardID]; !ok {
-				h.clients[req.leaderboardID] = make(map[*Client]bool)
+				h.clients[req.leaderboardID] = make(map[*Client]*Subscription)
 			}
-			h.clients[req.leaderboardID][req.client] = true
+			h.clients[req.leaderboardID][req.client] = req.options
 			h.mu.Unlock()
+			// Let the next update through so the new subscriber gets a snapshot
+			delete(h.lastUpdates, req.leaderboardID)
+			delete(req.client.synced, req.leaderboardID)
 			h.logger.Debug("client subscribed", "client_id", req.client.id, "leaderboard_id", req.leaderboardID)
 
 		case req := <-h.unsubscribe:
+			if req.playerID != "" {
+				h.mu.Lock()
+				h.removeFollower(req.client, req.leaderboardID, req.playerID)
+				h.mu.Unlock()
+				h.logger.Debug("client unfollowed player", "client_id", req.client.id, "leaderboard_id", req.leaderboardID, "player_id", req.playerID)
+				continue
+			}
+			if req.global {
+				h.mu.Lock()
+				delete(h.global, req.client)
+				h.mu.Unlock()
+				h.logger.Debug("client unsubscribed", "client_id", req.client.id, "channel", ChannelGlobal)
+				continue
+			}
+			if IsPattern(req.leaderboardID) {
+				h.unsubscribePattern(req)
+				continue
+			}
 			h.mu.Lock()
 			if clients, ok := h.clients[req.leaderboardID]; ok {
 				delete(clients, req.client)
@@ -147,6 +409,18 @@ func (h *Hub) Run() {
 
 		case message := <-h.broadcast:
 			h.broadcastMessage(message)
+
+		case messages := <-h.broadcastBatch:
+			h.broadcastMessages(messages)
+
+		case leaderboardID := <-h.flushes:
+			h.flushHeld(leaderboardID)
+
+		case req := <-h.openStreams:
+			h.openStream(req)
+
+		case stream := <-h.closeStreams:
+			h.closeStream(stream)
 		}
 	}
 }
@@ -156,44 +430,245 @@ func (h *Hub) Stop() {
 	h.cancel()
 }
 
+// SetQueryHandler sets the handler used to answer get_top and get_rank commands
+func (h *Hub) SetQueryHandler(queries QueryHandler) {
+	h.queries = queries
+}
+
+// SetErrorReporter sets the reporter that receives hub errors and panics
+func (h *Hub) SetErrorReporter(reporter telemetry.Reporter) {
+	h.reporter = reporter
+}
+
+// SetFaultInjector enables broadcast drop injection for resilience testing
+func (h *Hub) SetFaultInjector(faults *chaos.Injector) {
+	h.faults = faults
+}
+
+// SetFeatures sets the feature flags consulted for rolled-out behavior
+func (h *Hub) SetFeatures(flags *features.Provider) {
+	h.features = flags
+}
+
+// SetConnectionLimiter sets the limiter that admits new connections; call before serving
+func (h *Hub) SetConnectionLimiter(limiter *ConnectionLimiter) {
+	h.limiter = limiter
+}
+
 // broadcastMessage sends a message to all subscribed clients
 func (h *Hub) broadcastMessage(message *Message) {
+	h.broadcastMessages([]*Message{message})
+}
+
+// broadcastMessages sends a batch of messages, holding back leaderboard
+// updates of boards over their coalescing rate
+func (h *Hub) broadcastMessages(messages []*Message) {
+	h.sendMessages(h.coalesce(messages))
+}
+
+// rankChanges collects the rank changes of a batch by leaderboard, which
+// threshold subscribers check leaderboard updates against
+func rankChanges(messages []*Message) map[string][]PlayerUpdate {
+	updates := make(map[string][]PlayerUpdate)
+	for _, message := range messages {
+		if update, ok := message.Data.(PlayerUpdate); ok && !message.follow {
+			updates[message.LeaderboardID] = append(updates[message.LeaderboardID], update)
+		}
+		// A removal leaves the rankings like a drop to no rank at all
+		if removed, ok := message.Data.(PlayerRemoved); ok {
+			updates[message.LeaderboardID] = append(updates[message.LeaderboardID], PlayerUpdate{
+				LeaderboardID: removed.LeaderboardID,
+				PlayerID:      removed.PlayerID,
+				OldRank:       removed.OldRank,
+			})
+		}
+	}
+	return updates
+}
+
+// sendMessages sends a batch of messages while holding the lock once.
+// Serialization happens before the lock is taken and each message is encoded
+// a single time regardless of how many clients receive it.
+func (h *Hub) sendMessages(messages []*Message) {
+	payloads := make([]updatePayloads, len(messages))
+	updates := rankChanges(messages)
+	for i, message := range messages {
+		if len(message.held) > 0 {
+			updates[message.LeaderboardID] = append(message.held, updates[message.LeaderboardID]...)
+		}
+		update, isUpdate := message.Data.(LeaderboardUpdate)
+		payloads[i].unchanged = h.isUnchangedUpdate(message)
+		data, err := message.encode()
+		if err == nil && isUpdate && message.Type == MessageTypeLeaderboardUpdate {
+			payloads[i].deltas = h.features.Enabled(features.DeltaBroadcasts, message.LeaderboardID)
+			err = h.encodeV2(message, update, &payloads[i])
+			if h.compactClients > 0 {
+				payloads[i].compactV1 = encodeCompactUpdate(message, message.Seq, update)
+			}
+		}
+		if err != nil {
+			h.logger.Error("failed to marshal message", "error", err)
+			h.reporter.CaptureError(h.ctx, err, map[string]string{
+				"component":      "websocket",
+				"message_type":   message.Type,
+				"leaderboard_id": message.LeaderboardID,
+			})
+			continue
+		}
+		payloads[i].v1 = data
+	}
+
+	// Streams that fell behind are closed once the read lock below is released
+	var behind []*Stream
+	defer func() {
+		for _, stream := range behind {
+			h.closeStream(stream)
+		}
+	}()
+
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	data, err := json.Marshal(message)
-	if err != nil {
-		h.logger.Error("failed to marshal message", "error", err)
-		return
-	}
+	for i, message := range messages {
+		if payloads[i].v1 == nil || h.faults.Drop(chaos.TargetBroadcast) {
+			continue
+		}
 
-	// If message has a leaderboard ID, only send to subscribed clients
-	if message.LeaderboardID != "" {
-		if clients, ok := h.clients[message.LeaderboardID]; ok {
-			for client := range clients {
-				select {
-				case client.send <- data:
-				default:
-					// Client's buffer is full, skip
-					h.logger.Warn("client buffer full, skipping", "client_id", client.id)
-				}
+		// Followed-player updates only reach the player's followers
+		if message.follow {
+			h.deliverFollowers(message, payloads[i].v1)
+			continue
+		}
+
+		// Global events only reach clients subscribed to the global channel
+		if message.Channel == ChannelGlobal {
+			for client := range h.global {
+				h.deliver(client, payloads[i].v1)
 			}
+			continue
 		}
-	} else {
-		// Broadcast to all clients
-		for client := range h.allClients {
-			select {
-			case client.send <- data:
-			default:
-				h.logger.Warn("client buffer full, skipping", "client_id", client.id)
+
+		// Broadcast to all clients when the message has no leaderboard ID
+		if message.LeaderboardID == "" {
+			for client := range h.allClients {
+				h.deliver(client, payloads[i].v1)
+			}
+			continue
+		}
+
+		// Event streams get every change of their board in the v1 encoding
+		if isStreamed(message.Type) && !payloads[i].unchanged {
+			behind = append(behind, h.streamMessage(message, payloads[i].v1)...)
+		}
+
+		// Otherwise only send to subscribed clients whose options accept it
+		subscribers := h.clients[message.LeaderboardID]
+		for client, sub := range subscribers {
+			h.deliverTo(client, sub, message, &payloads[i], updates[message.LeaderboardID])
+		}
+
+		// Then to wildcard subscribers, once per client however many patterns match
+		patterns := h.matchingPatterns(message.LeaderboardID)
+		if len(patterns) == 0 {
+			continue
+		}
+		seen := make(map[*Client]bool)
+		for _, pattern := range patterns {
+			for client, sub := range h.patterns[pattern] {
+				if _, exact := subscribers[client]; exact || seen[client] {
+					continue
+				}
+				seen[client] = true
+				h.deliverTo(client, sub, message, &payloads[i], updates[message.LeaderboardID])
 			}
 		}
 	}
 }
 
-// BroadcastLeaderboardUpdate sends a leaderboard update to all subscribed clients
-func (h *Hub) BroadcastLeaderboardUpdate(leaderboardID string, entries []domain.LeaderboardEntry, totalPlayers int64) {
-	message := &Message{
+// deliverTo sends a leaderboard message to one subscriber, in the client's
+// protocol version, if its options accept it
+func (h *Hub) deliverTo(client *Client, sub *Subscription, message *Message, payloads *updatePayloads, updates []PlayerUpdate) {
+	if sub != nil && sub.filtered() && !sub.wants(message, updates) {
+		return
+	}
+	if payload := payloads.payloadFor(client, message.LeaderboardID, sub); payload != nil {
+		h.deliver(client, payload)
+	}
+}
+
+// subscribePattern adds a wildcard subscription. Only called from the Run goroutine.
+func (h *Hub) subscribePattern(req *subscriptionRequest) {
+	h.mu.Lock()
+	if _, ok := h.patterns[req.leaderboardID]; !ok {
+		h.patterns[req.leaderboardID] = make(map[*Client]*Subscription)
+		h.resetPatternMatches()
+	}
+	h.patterns[req.leaderboardID][req.client] = req.options
+	h.mu.Unlock()
+
+	// Let the next update of every matching board through as a snapshot
+	for lbID := range h.lastUpdates {
+		if matchPattern(req.leaderboardID, lbID) {
+			delete(h.lastUpdates, lbID)
+		}
+	}
+	for lbID := range req.client.synced {
+		if matchPattern(req.leaderboardID, lbID) {
+			delete(req.client.synced, lbID)
+		}
+	}
+	h.logger.Debug("client subscribed", "client_id", req.client.id, "pattern", req.leaderboardID)
+}
+
+// unsubscribePattern removes a wildcard subscription. Only called from the Run goroutine.
+func (h *Hub) unsubscribePattern(req *subscriptionRequest) {
+	h.mu.Lock()
+	if clients, ok := h.patterns[req.leaderboardID]; ok {
+		delete(clients, req.client)
+		if len(clients) == 0 {
+			delete(h.patterns, req.leaderboardID)
+			h.resetPatternMatches()
+		}
+	}
+	h.mu.Unlock()
+	h.logger.Debug("client unsubscribed", "client_id", req.client.id, "pattern", req.leaderboardID)
+}
+
+// deliver queues a payload on a client without blocking the hub
+func (h *Hub) deliver(client *Client, payload []byte) {
+	select {
+	case client.send <- payload:
+	default:
+		// Client's buffer is full, skip
+		h.logger.Warn("client buffer full, skipping", "client_id", client.id)
+	}
+}
+
+// isUnchangedUpdate reports whether a leaderboard_update carries the same data as
+// the last one sent for its leaderboard. The data is marshaled once: the bytes
+// are compared and then wrapped into the message's cached payload, so encode
+// does not marshal it again. Only called from the Run goroutine.
+func (h *Hub) isUnchangedUpdate(message *Message) bool {
+	if message.Type != MessageTypeLeaderboardUpdate || message.LeaderboardID == "" || message.payload != nil {
+		return false
+	}
+
+	data, err := marshalPooled(message.Data)
+	if err != nil {
+		return false
+	}
+	message.payload = encodeWithData(message, data)
+
+	if bytes.Equal(h.lastUpdates[message.LeaderboardID], data) {
+		return true
+	}
+	h.lastUpdates[message.LeaderboardID] = data
+	return false
+}
+
+// NewLeaderboardUpdateMessage builds a leaderboard_update message
+func NewLeaderboardUpdateMessage(leaderboardID string, entries []domain.LeaderboardEntry, totalPlayers int64) *Message {
+	return &Message{
 		Type:          MessageTypeLeaderboardUpdate,
 		LeaderboardID: leaderboardID,
 		Data: LeaderboardUpdate{
@@ -203,6 +678,55 @@ func (h *Hub) BroadcastLeaderboardUpdate(leaderboardID string, entries []domain.
 		},
 		Timestamp: time.Now(),
 	}
+}
+
+// NewPlayerUpdateMessage builds a player_update message
+func NewPlayerUpdateMessage(update PlayerUpdate) *Message {
+	return &Message{
+		Type:          MessageTypePlayerUpdate,
+		LeaderboardID: update.LeaderboardID,
+		Data:          update,
+		Timestamp:     time.Now(),
+	}
+}
+
+// NewPlayerRemovedMessage builds a player_removed message
+func NewPlayerRemovedMessage(removed PlayerRemoved) *Message {
+	return &Message{
+		Type:          MessageTypePlayerRemoved,
+		LeaderboardID: removed.LeaderboardID,
+		Data:          removed,
+		Timestamp:     time.Now(),
+	}
+}
+
+// NewGlobalEventMessage builds a global_event message for the global channel
+func NewGlobalEventMessage(event domain.FeedEvent) *Message {
+	return &Message{
+		Type:          MessageTypeGlobalEvent,
+		LeaderboardID: event.LeaderboardID,
+		Channel:       ChannelGlobal,
+		Data:          event,
+		Timestamp:     time.Now(),
+	}
+}
+
+// BroadcastBatch delivers several messages, possibly spanning leaderboards, in one hub pass
+func (h *Hub) BroadcastBatch(messages []*Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	select {
+	case h.broadcastBatch <- messages:
+	default:
+		h.logger.Warn("broadcast batch channel full, dropping messages", "count", len(messages))
+	}
+}
+
+// BroadcastLeaderboardUpdate sends a leaderboard update to all subscribed clients
+func (h *Hub) BroadcastLeaderboardUpdate(leaderboardID string, entries []domain.LeaderboardEntry, totalPlayers int64) {
+	message := NewLeaderboardUpdateMessage(leaderboardID, entries, totalPlayers)
 
 	select {
 	case h.broadcast <- message:
@@ -212,13 +736,8 @@ func (h *Hub) BroadcastLeaderboardUpdate(leaderboardID string, entries []domain.
 }
 
 // BroadcastPlayerUpdate sends a player update notification
-func (h *Hub) BroadcastPlayerUpdate(leaderboardID string, entry domain.LeaderboardEntry) {
-	message := &Message{
-		Type:          MessageTypePlayerUpdate,
-		LeaderboardID: leaderboardID,
-		Data:          entry,
-		Timestamp:     time.Now(),
-	}
+func (h *Hub) BroadcastPlayerUpdate(update PlayerUpdate) {
+	message := NewPlayerUpdateMessage(update)
 
 	select {
 	case h.broadcast <- message:
@@ -239,9 +758,17 @@ func (h *Hub) Unregister(client *Client) {
 
 // Subscribe adds a client to a leaderboard subscription
 func (h *Hub) Subscribe(client *Client, leaderboardID string) {
+	h.SubscribeWithOptions(client, leaderboardID, nil)
+}
+
+// SubscribeWithOptions adds a client to a leaderboard subscription with delivery filters.
+// A leaderboard ID containing * subscribes to every matching board. Subscribing
+// again replaces the previous options.
+func (h *Hub) SubscribeWithOptions(client *Client, leaderboardID string, options *Subscription) {
 	h.subscribe <- &subscriptionRequest{
 		client:        client,
 		leaderboardID: leaderboardID,
+		options:       options,
 	}
 }
 
@@ -253,14 +780,44 @@ func (h *Hub) Unsubscribe(client *Client, leaderboardID string) {
 	}
 }
 
-// GetSubscriberCount returns the number of subscribers for a leaderboard
+// SubscribeGlobal adds a client to the global channel
+func (h *Hub) SubscribeGlobal(client *Client) {
+	h.subscribe <- &subscriptionRequest{client: client, global: true}
+}
+
+// UnsubscribeGlobal removes a client from the global channel
+func (h *Hub) UnsubscribeGlobal(client *Client) {
+	h.unsubscribe <- &subscriptionRequest{client: client, global: true}
+}
+
+// BroadcastGlobalEvent sends a service-wide event to global channel subscribers
+func (h *Hub) BroadcastGlobalEvent(event domain.FeedEvent) {
+	message := NewGlobalEventMessage(event)
+
+	select {
+	case h.broadcast <- message:
+	default:
+		h.logger.Warn("broadcast channel full, dropping message")
+	}
+}
+
+// GetGlobalSubscriberCount returns the number of clients on the global channel
+func (h *Hub) GetGlobalSubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.global)
+}
+
+// GetSubscriberCount returns the number of subscribers for a leaderboard,
+// counting event streams and wildcard subscriptions whose pattern matches it
 func (h *Hub) GetSubscriberCount(leaderboardID string) int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	if clients, ok := h.clients[leaderboardID]; ok {
-		return len(clients)
+	count := len(h.clients[leaderboardID]) + len(h.streams[leaderboardID])
+	for _, pattern := range h.matchingPatterns(leaderboardID) {
+		count += len(h.patterns[pattern])
 	}
-	return 0
+	return count
 }
 
 // GetTotalConnections returns the total number of connected clients
@@ -0,0 +1,153 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// ErrTooManyConnections is returned when the server-wide connection cap is reached
+var ErrTooManyConnections = errors.New("too many websocket connections")
+
+// How often idle handshake buckets are swept
+const bucketSweepInterval = time.Minute
+
+// ConnectionLimiter admits WebSocket handshakes, capping total connections,
+// connections per IP, and the handshake rate per IP. Zero limits are not enforced.
+type ConnectionLimiter struct {
+	config *config.ConnectionLimitsConfig
+
+	mu        sync.Mutex
+	total     int
+	perIP     map[string]int
+	buckets   map[string]*handshakeBucket
+	lastSweep time.Time
+}
+
+// handshakeBucket is a token bucket refilled at the configured handshake rate
+type handshakeBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewConnectionLimiter creates a limiter from the connection limits config
+func NewConnectionLimiter(cfg *config.ConnectionLimitsConfig) *ConnectionLimiter {
+	return &ConnectionLimiter{
+		config:    cfg,
+		perIP:     make(map[string]int),
+		buckets:   make(map[string]*handshakeBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Acquire admits a connection from ip, returning ErrTooManyConnections when the
+// server is full or domain.ErrRateLimited when the IP is over its limits.
+// Admitted connections must be released when they close.
+func (l *ConnectionLimiter) Acquire(ip string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.config.MaxConnections > 0 && l.total >= l.config.MaxConnections {
+		return ErrTooManyConnections
+	}
+	if l.config.MaxPerIP > 0 && l.perIP[ip] >= l.config.MaxPerIP {
+		return domain.ErrRateLimited
+	}
+	if l.config.HandshakeRate > 0 && !l.take(ip, time.Now()) {
+		return domain.ErrRateLimited
+	}
+
+	l.total++
+	l.perIP[ip]++
+	return nil
+}
+
+// Release frees a connection admitted by Acquire
+func (l *ConnectionLimiter) Release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total--
+	if l.perIP[ip]--; l.perIP[ip] <= 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// take spends one handshake token for ip; callers hold l.mu
+func (l *ConnectionLimiter) take(ip string, now time.Time) bool {
+	burst := float64(l.config.HandshakeBurst)
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		l.sweep(now, burst)
+	}
+
+	bucket, ok := l.buckets[ip]
+	if !ok {
+		bucket = &handshakeBucket{tokens: burst, last: now}
+		l.buckets[ip] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.config.HandshakeRate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// sweep drops buckets that have refilled completely, since a fresh bucket is equivalent
+func (l *ConnectionLimiter) sweep(now time.Time, burst float64) {
+	for ip, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.config.HandshakeRate >= burst {
+			delete(l.buckets, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// peerAddrKey is the context key of a request's connection address
+type peerAddrKey struct{}
+
+// WithPeerAddr records the address of the connection a request arrived on.
+// Middleware such as RealIP rewrites RemoteAddr from client-supplied headers,
+// so the router records it before that runs.
+func WithPeerAddr(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, peerAddrKey{}, addr)
+}
+
+// clientIP returns the address a handshake came from, preferring the first
+// X-Forwarded-For entry when the proxy in front of the server is trusted.
+// Otherwise the connection's own address is used, never forwarding headers.
+func (l *ConnectionLimiter) clientIP(r *http.Request) string {
+	if l.config.TrustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	return PeerIP(r)
+}
+
+// PeerIP returns the IP address of the connection a request arrived on, as
+// recorded by WithPeerAddr, ignoring any forwarding headers
+func PeerIP(r *http.Request) string {
+	addr, ok := r.Context().Value(peerAddrKey{}).(string)
+	if !ok {
+		addr = r.RemoteAddr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
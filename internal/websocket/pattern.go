@@ -0,0 +1,72 @@
+package websocket
+
+import "strings"
+
+// patternWildcard matches any run of characters in a leaderboard subscription pattern
+const patternWildcard = "*"
+
+// IsPattern reports whether a subscription target is a wildcard pattern such as game1-*
+func IsPattern(leaderboardID string) bool {
+	return strings.Contains(leaderboardID, patternWildcard)
+}
+
+// matchPattern reports whether a leaderboard ID matches a wildcard pattern.
+// Each * matches any run of characters, including none.
+func matchPattern(pattern, leaderboardID string) bool {
+	parts := strings.Split(pattern, patternWildcard)
+	if len(parts) == 1 {
+		return pattern == leaderboardID
+	}
+
+	// The first and last literal parts are anchored to the ends of the ID
+	first, last := parts[0], parts[len(parts)-1]
+	if !strings.HasPrefix(leaderboardID, first) {
+		return false
+	}
+	rest := leaderboardID[len(first):]
+	if len(rest) < len(last) || !strings.HasSuffix(rest, last) {
+		return false
+	}
+	rest = rest[:len(rest)-len(last)]
+
+	// Middle parts are matched left to right, each as early as possible
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return true
+}
+
+// matchingPatterns returns the subscribed patterns that match a leaderboard ID.
+// Results are cached per ID until the set of patterns changes, so routing a
+// message costs one map lookup after the first. Callers hold h.mu.
+func (h *Hub) matchingPatterns(leaderboardID string) []string {
+	if len(h.patterns) == 0 {
+		return nil
+	}
+
+	h.matchMu.Lock()
+	defer h.matchMu.Unlock()
+	if matches, ok := h.patternMatches[leaderboardID]; ok {
+		return matches
+	}
+
+	var matches []string
+	for pattern := range h.patterns {
+		if matchPattern(pattern, leaderboardID) {
+			matches = append(matches, pattern)
+		}
+	}
+	h.patternMatches[leaderboardID] = matches
+	return matches
+}
+
+// resetPatternMatches drops cached pattern matches; callers hold h.mu for writing
+func (h *Hub) resetPatternMatches() {
+	h.matchMu.Lock()
+	h.patternMatches = make(map[string][]string)
+	h.matchMu.Unlock()
+}
@@ -0,0 +1,189 @@
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// Protocol versions. v1 sends every leaderboard_update in full. v2 numbers each
+// board's updates with seq and, once a client holds a snapshot, sends only
+// leaderboard_delta messages describing what changed.
+const (
+	ProtocolV1 = 1
+	ProtocolV2 = 2
+)
+
+// SupportedProtocolVersions lists the versions a client may negotiate
+var SupportedProtocolVersions = []int{ProtocolV1, ProtocolV2}
+
+// MessageTypeLeaderboardDelta carries the changes since the previous update in protocol v2
+const MessageTypeLeaderboardDelta = "leaderboard_delta"
+
+// LeaderboardDelta lists the entries that changed since the update numbered
+// BaseSeq. Clients whose last seq differs have missed an update and should
+// subscribe again to receive a fresh snapshot. Entered, Moved and Removed
+// describe the same change as rank movements: players who joined the list,
+// players whose rank changed, and players who left it.
+type LeaderboardDelta struct {
+	LeaderboardID string                    `json:"leaderboard_id"`
+	BaseSeq       uint64                    `json:"base_seq"`
+	Upserts       []domain.LeaderboardEntry `json:"upserts,omitempty"`
+	Removed       []string                  `json:"removed,omitempty"`
+	Entered       []string                  `json:"entered,omitempty"`
+	Moved         []RankMove                `json:"moved,omitempty"`
+	TotalPlayers  int64                     `json:"total_players"`
+}
+
+// RankMove is a listed player's rank change between two updates
+type RankMove struct {
+	PlayerID string `json:"player_id"`
+	OldRank  int64  `json:"old_rank"`
+	Rank     int64  `json:"rank"`
+}
+
+// negotiateProtocol reads the protocol version a client asked for with
+// ?protocol_version= on the handshake, defaulting to ProtocolVersion
+func negotiateProtocol(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("protocol_version")
+	if raw == "" {
+		return ProtocolVersion, nil
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid protocol_version %q", raw)
+	}
+	for _, supported := range SupportedProtocolVersions {
+		if version == supported {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported protocol_version %d", version)
+}
+
+// updatePayloads holds a leaderboard_update encoded for each protocol version
+type updatePayloads struct {
+	v1 []byte
+	// full is the v2 snapshot and delta the v2 change set; delta is nil when nothing changed
+	full  []byte
+	delta []byte
+	// unchanged is set when the update repeats the last one sent for its board
+	unchanged bool
+	// deltas is set when delta broadcasts are on for the board
+	deltas bool
+
+	// Counterparts of v1, full and delta for clients that negotiated the
+	// compact encoding, only built while such clients are connected
+	compactV1    []byte
+	compactFull  []byte
+	compactDelta []byte
+}
+
+// encodeV2 numbers a leaderboard_update and encodes its v2 snapshot and delta
+// against the previous update for the board. Only called from the Run goroutine.
+func (h *Hub) encodeV2(message *Message, update LeaderboardUpdate, payloads *updatePayloads) error {
+	id := message.LeaderboardID
+	seq := h.seqs[id]
+
+	diff := diffEntries(h.lastEntries[id], update.Entries)
+	if seq == 0 || len(diff.Upserts) > 0 || len(diff.Removed) > 0 || update.TotalPlayers != h.lastTotals[id] {
+		delta := *message
+		delta.payload = nil
+		delta.Type = MessageTypeLeaderboardDelta
+		delta.Seq = seq + 1
+		deltaData := diff
+		deltaData.LeaderboardID = id
+		deltaData.BaseSeq = seq
+		deltaData.TotalPlayers = update.TotalPlayers
+		delta.Data = deltaData
+		data, err := marshalPooled(&delta)
+		if err != nil {
+			return err
+		}
+		payloads.delta = data
+		if h.compactClients > 0 {
+			payloads.compactDelta = encodeCompactDelta(&delta, delta.Seq, deltaData)
+		}
+
+		seq++
+		h.seqs[id] = seq
+		h.lastEntries[id] = update.Entries
+		h.lastTotals[id] = update.TotalPlayers
+	}
+
+	full := *message
+	full.payload = nil
+	full.Seq = seq
+	data, err := marshalPooled(&full)
+	if err != nil {
+		return err
+	}
+	payloads.full = data
+	if h.compactClients > 0 {
+		payloads.compactFull = encodeCompactUpdate(&full, seq, update)
+	}
+	return nil
+}
+
+// diffEntries compares two entry lists of a board: the entries of next that
+// are new or changed, the players in prev that are no longer listed, and the
+// players who entered the list or changed rank
+func diffEntries(prev, next []domain.LeaderboardEntry) LeaderboardDelta {
+	previous := make(map[string]domain.LeaderboardEntry, len(prev))
+	for _, entry := range prev {
+		previous[entry.PlayerID] = entry
+	}
+
+	var diff LeaderboardDelta
+	for _, entry := range next {
+		old, ok := previous[entry.PlayerID]
+		switch {
+		case !ok:
+			diff.Entered = append(diff.Entered, entry.PlayerID)
+		case old.Rank != entry.Rank:
+			diff.Moved = append(diff.Moved, RankMove{PlayerID: entry.PlayerID, OldRank: old.Rank, Rank: entry.Rank})
+		}
+		if !ok || !reflect.DeepEqual(old, entry) {
+			diff.Upserts = append(diff.Upserts, entry)
+		}
+		delete(previous, entry.PlayerID)
+	}
+
+	for playerID := range previous {
+		diff.Removed = append(diff.Removed, playerID)
+	}
+	return diff
+}
+
+// payloadFor picks the encoding of a leaderboard message for one client, or nil
+// when the client should not receive it. Only called from the Run goroutine.
+func (p *updatePayloads) payloadFor(client *Client, leaderboardID string, sub *Subscription) []byte {
+	v1, full, delta := p.v1, p.full, p.delta
+	if client.encoding == EncodingCompact && p.compactV1 != nil {
+		v1, full, delta = p.compactV1, p.compactFull, p.compactDelta
+	}
+
+	if client.protocol < ProtocolV2 || full == nil {
+		if p.unchanged && (sub == nil || !sub.filtered()) {
+			return nil
+		}
+		return v1
+	}
+
+	// Filtered subscribers skip updates, so deltas would not line up for them
+	if sub != nil && sub.filtered() {
+		return full
+	}
+	if !client.synced[leaderboardID] {
+		client.synced[leaderboardID] = true
+		return full
+	}
+	// With delta broadcasts off for the board, each change is sent in full
+	if !p.deltas && delta != nil {
+		return full
+	}
+	return delta
+}
@@ -0,0 +1,229 @@
+package websocket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// streamBufferSize is how many recent events are kept per leaderboard for
+	// streams resuming with Last-Event-ID
+	streamBufferSize = 128
+
+	// streamSendBuffer is how many events may queue for one stream; a stream
+	// that falls further behind is closed and resumes on reconnect
+	streamSendBuffer = 64
+)
+
+// StreamEvent is one leaderboard message as sent to a server-sent events
+// stream. ID is "<epoch>-<seq>", where the epoch changes when the server
+// restarts so IDs from an earlier run are never mistaken for current ones.
+type StreamEvent struct {
+	ID   string
+	Type string
+	Data []byte
+}
+
+// Stream receives the leaderboard_update, player_update and player_removed
+// messages of one leaderboard for a server-sent events connection. Events is
+// closed when the stream is closed by the hub, including when it falls behind.
+type Stream struct {
+	leaderboardID string
+	events        chan StreamEvent
+}
+
+// Events returns the channel the stream's events are delivered on
+func (s *Stream) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// streamRing holds the most recent events of one leaderboard
+type streamRing struct {
+	events []StreamEvent
+	seqs   []uint64
+	next   int
+	seq    uint64
+}
+
+// add appends an event, numbering it and overwriting the oldest when full
+func (r *streamRing) add(epoch, eventType string, data []byte) StreamEvent {
+	r.seq++
+	event := StreamEvent{ID: epoch + "-" + strconv.FormatUint(r.seq, 10), Type: eventType, Data: data}
+	if len(r.events) < streamBufferSize {
+		r.events = append(r.events, event)
+		r.seqs = append(r.seqs, r.seq)
+		return event
+	}
+	r.events[r.next] = event
+	r.seqs[r.next] = r.seq
+	r.next = (r.next + 1) % streamBufferSize
+	return event
+}
+
+// since returns the events after seq, oldest first. ok is false when events
+// after seq have already been overwritten, or seq is ahead of the ring.
+func (r *streamRing) since(seq uint64) ([]StreamEvent, bool) {
+	if seq > r.seq {
+		return nil, false
+	}
+	oldest := r.seq - uint64(len(r.events)) + 1
+	if len(r.events) == 0 {
+		oldest = r.seq + 1
+	}
+	if seq+1 < oldest {
+		return nil, false
+	}
+	var events []StreamEvent
+	for i := 0; i < len(r.events); i++ {
+		j := (r.next + i) % len(r.events)
+		if r.seqs[j] > seq {
+			events = append(events, r.events[j])
+		}
+	}
+	return events, true
+}
+
+// streamRequest opens a stream, replaying what it missed after lastEventID
+type streamRequest struct {
+	stream      *Stream
+	lastEventID string
+	reply       chan streamReplay
+}
+
+// streamReplay is the outcome of opening a stream
+type streamReplay struct {
+	events  []StreamEvent
+	resumed bool
+}
+
+// OpenStream subscribes a server-sent events stream to a leaderboard. With the
+// ID of the last event a client saw, the events it missed are returned and
+// resumed is true; otherwise, or when those events are no longer buffered,
+// resumed is false and the caller should send a fresh snapshot first.
+func (h *Hub) OpenStream(leaderboardID, lastEventID string) (*Stream, []StreamEvent, bool, error) {
+	stream := &Stream{
+		leaderboardID: leaderboardID,
+		events:        make(chan StreamEvent, streamSendBuffer),
+	}
+	req := &streamRequest{stream: stream, lastEventID: lastEventID, reply: make(chan streamReplay, 1)}
+
+	select {
+	case h.openStreams <- req:
+	case <-h.ctx.Done():
+		return nil, nil, false, fmt.Errorf("websocket hub stopped")
+	}
+	select {
+	case replay := <-req.reply:
+		return stream, replay.events, replay.resumed, nil
+	case <-h.ctx.Done():
+		return nil, nil, false, fmt.Errorf("websocket hub stopped")
+	}
+}
+
+// CloseStream unsubscribes a stream; closing a stream twice is harmless
+func (h *Hub) CloseStream(stream *Stream) {
+	select {
+	case h.closeStreams <- stream:
+	case <-h.ctx.Done():
+	}
+}
+
+// GetStreamCount returns the number of open event streams
+func (h *Hub) GetStreamCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	count := 0
+	for _, streams := range h.streams {
+		count += len(streams)
+	}
+	return count
+}
+
+// openStream registers a stream and replays what it missed. Only called from
+// the Run goroutine.
+func (h *Hub) openStream(req *streamRequest) {
+	id := req.stream.leaderboardID
+	h.mu.Lock()
+	if _, ok := h.streams[id]; !ok {
+		h.streams[id] = make(map[*Stream]bool)
+	}
+	h.streams[id][req.stream] = true
+	h.mu.Unlock()
+
+	ring, ok := h.streamRings[id]
+	if !ok {
+		ring = &streamRing{}
+		h.streamRings[id] = ring
+	}
+	// Let the next update through so the stream is sent a current snapshot
+	delete(h.lastUpdates, id)
+
+	var replay streamReplay
+	if seq, ok := h.parseEventID(req.lastEventID); ok {
+		replay.events, replay.resumed = ring.since(seq)
+	}
+	req.reply <- replay
+	h.logger.Debug("stream opened", "leaderboard_id", id, "resumed", replay.resumed, "replayed", len(replay.events))
+}
+
+// closeStream unregisters a stream. Only called from the Run goroutine.
+func (h *Hub) closeStream(stream *Stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	streams, ok := h.streams[stream.leaderboardID]
+	if !ok || !streams[stream] {
+		return
+	}
+	delete(streams, stream)
+	if len(streams) == 0 {
+		delete(h.streams, stream.leaderboardID)
+	}
+	close(stream.events)
+}
+
+// parseEventID returns the sequence number of an event ID issued in this run
+func (h *Hub) parseEventID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != h.streamEpoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
+
+// streamMessage buffers a leaderboard message for resuming streams and sends
+// it to the board's open streams. Streams that cannot keep up are closed.
+// Only called from the Run goroutine, with h.mu held for reading.
+func (h *Hub) streamMessage(message *Message, payload []byte) []*Stream {
+	ring, ok := h.streamRings[message.LeaderboardID]
+	if !ok {
+		return nil
+	}
+	event := ring.add(h.streamEpoch, message.Type, payload)
+
+	var behind []*Stream
+	for stream := range h.streams[message.LeaderboardID] {
+		select {
+		case stream.events <- event:
+		default:
+			behind = append(behind, stream)
+		}
+	}
+	return behind
+}
+
+// isStreamed reports whether a message type is sent to event streams
+func isStreamed(messageType string) bool {
+	switch messageType {
+	case MessageTypeLeaderboardUpdate, MessageTypePlayerUpdate, MessageTypePlayerRemoved:
+		return true
+	}
+	return false
+}
+
+// newStreamEpoch returns the event ID prefix of this run
+func newStreamEpoch() string {
+	return strconv.FormatInt(time.Now().UnixMilli(), 36)
+}
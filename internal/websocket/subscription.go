@@ -0,0 +1,74 @@
+package websocket
+
+// Subscription holds a client's delivery options for one leaderboard.
+// The zero value delivers every update.
+type Subscription struct {
+	// Threshold limits delivery to changes in top-N membership
+	Threshold int64
+
+	// Watch lists players whose updates are always delivered
+	Watch map[string]bool
+}
+
+// NewSubscription creates subscription options from a client's subscribe request
+func NewSubscription(threshold int64, watch []string) *Subscription {
+	sub := &Subscription{Threshold: threshold}
+	if len(watch) > 0 {
+		sub.Watch = make(map[string]bool, len(watch))
+		for _, playerID := range watch {
+			sub.Watch[playerID] = true
+		}
+	}
+	return sub
+}
+
+// filtered reports whether the subscription restricts delivery
+func (s *Subscription) filtered() bool {
+	return s.Threshold > 0 || len(s.Watch) > 0
+}
+
+// wants reports whether a message should be delivered to the subscriber.
+// updates holds the player updates broadcast in the same batch for the message's leaderboard.
+func (s *Subscription) wants(message *Message, updates []PlayerUpdate) bool {
+	if !s.filtered() {
+		return true
+	}
+
+	switch message.Type {
+	case MessageTypePlayerUpdate:
+		update, ok := message.Data.(PlayerUpdate)
+		if !ok {
+			return true
+		}
+		return s.Watch[update.PlayerID] || s.crosses(update)
+
+	case MessageTypePlayerRemoved:
+		removed, ok := message.Data.(PlayerRemoved)
+		if !ok {
+			return true
+		}
+		return s.Watch[removed.PlayerID] || s.crosses(PlayerUpdate{OldRank: removed.OldRank})
+
+	case MessageTypeLeaderboardUpdate:
+		// Only refresh the widget when top-N membership changed in this batch
+		for _, update := range updates {
+			if s.crosses(update) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return true
+	}
+}
+
+// crosses reports whether a player entered or left the subscriber's top N
+func (s *Subscription) crosses(update PlayerUpdate) bool {
+	if s.Threshold <= 0 {
+		return false
+	}
+	wasIn := update.OldRank > 0 && update.OldRank <= s.Threshold
+	isIn := update.Rank > 0 && update.Rank <= s.Threshold
+	return wasIn != isIn
+}
@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/redis"
+)
+
+// CacheSweepWorker periodically gives cache keys written without a TTL one,
+// so entries left over from before TTLs were configured still expire
+type CacheSweepWorker struct {
+	redis   *redis.LeaderboardService
+	config  *config.CacheConfig
+	logger  *slog.Logger
+	clock   clock.Clock
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	mu      sync.Mutex
+	running bool
+}
+
+// NewCacheSweepWorker creates a new cache sweep worker
+func NewCacheSweepWorker(
+	redis *redis.LeaderboardService,
+	cfg *config.CacheConfig,
+	logger *slog.Logger,
+) *CacheSweepWorker {
+	return &CacheSweepWorker{
+		redis:  redis,
+		config: cfg,
+		logger: logger,
+		clock:  clock.Real(),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *CacheSweepWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins the background sweep process
+func (w *CacheSweepWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("cache sweep worker started",
+		"interval", w.config.SweepInterval,
+		"player_info_ttl", w.config.PlayerInfoTTL,
+	)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background sweep process
+func (w *CacheSweepWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("cache sweep worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *CacheSweepWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	// Sweep once at startup so a fresh deploy doesn't wait a full interval
+	w.RunOnce(ctx)
+
+	ticker := w.clock.NewTicker(w.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce sets the configured TTL on every cache key that has none
+func (w *CacheSweepWorker) RunOnce(ctx context.Context) {
+	startTime := w.clock.Now()
+
+	swept, err := w.redis.SweepPlayerInfo(ctx, int64(w.config.SweepBatch))
+	if err != nil {
+		w.logger.Error("failed to sweep player info cache", "error", err, "swept", swept)
+		return
+	}
+
+	if swept > 0 {
+		w.logger.Info("swept player info cache",
+			"count", swept,
+			"duration", w.clock.Since(startTime),
+		)
+	}
+}
@@ -0,0 +1,223 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/migration"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// TopReader reads a leaderboard's top entries
+type TopReader interface {
+	GetTopN(ctx context.Context, leaderboardID string, n int) ([]domain.LeaderboardEntry, error)
+}
+
+// LegacyReader reads the legacy service's top entries
+type LegacyReader interface {
+	Top(ctx context.Context, leaderboardID string, n int) ([]migration.Entry, error)
+}
+
+// CompareWorker periodically diffs each leaderboard's top entries with the
+// legacy service's and keeps the latest report per board
+type CompareWorker struct {
+	reader   TopReader
+	legacy   LegacyReader
+	postgres *postgres.Repository
+	config   *config.CompareConfig
+	logger   *slog.Logger
+	clock    clock.Clock
+	reports  map[string]migration.Report // leaderboard ID -> latest report
+	reportMu sync.RWMutex
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewCompareWorker creates a new comparison worker
+func NewCompareWorker(
+	reader TopReader,
+	legacy LegacyReader,
+	postgres *postgres.Repository,
+	cfg *config.CompareConfig,
+	logger *slog.Logger,
+) *CompareWorker {
+	return &CompareWorker{
+		reader:   reader,
+		legacy:   legacy,
+		postgres: postgres,
+		config:   cfg,
+		logger:   logger,
+		clock:    clock.Real(),
+		reports:  make(map[string]migration.Report),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *CompareWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins the background comparison process
+func (w *CompareWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("compare worker started", "interval", w.config.Interval, "top_n", w.config.TopN)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background comparison process
+func (w *CompareWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("compare worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *CompareWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	ticker := w.clock.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce compares every configured leaderboard and returns the reports
+func (w *CompareWorker) RunOnce(ctx context.Context) []migration.Report {
+	leaderboards, err := w.leaderboards(ctx)
+	if err != nil {
+		w.logger.Error("failed to list leaderboards for comparison", "error", err)
+		return nil
+	}
+
+	reports := make([]migration.Report, 0, len(leaderboards))
+	for _, id := range leaderboards {
+		if ctx.Err() != nil {
+			break
+		}
+		report := w.compare(ctx, id)
+		w.reportMu.Lock()
+		w.reports[id] = report
+		w.reportMu.Unlock()
+		reports = append(reports, report)
+
+		switch {
+		case report.Error != "":
+			w.logger.Error("failed to compare leaderboard", "leaderboard_id", id, "error", report.Error)
+		case report.MismatchCount > 0:
+			w.logger.Warn("leaderboard differs from legacy service",
+				"leaderboard_id", id,
+				"mismatches", report.MismatchCount,
+				"matched", report.Matched,
+			)
+		}
+	}
+	return reports
+}
+
+// Reports returns the latest report of every compared leaderboard
+func (w *CompareWorker) Reports() []migration.Report {
+	w.reportMu.RLock()
+	defer w.reportMu.RUnlock()
+
+	reports := make([]migration.Report, 0, len(w.reports))
+	for _, report := range w.reports {
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].LeaderboardID < reports[j].LeaderboardID })
+	return reports
+}
+
+// leaderboards returns the configured boards, or every board when none are listed
+func (w *CompareWorker) leaderboards(ctx context.Context) ([]string, error) {
+	if len(w.config.Leaderboards) > 0 {
+		return w.config.Leaderboards, nil
+	}
+	leaderboards, err := w.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(leaderboards))
+	for i, lb := range leaderboards {
+		ids[i] = lb.ID
+	}
+	return ids, nil
+}
+
+// compare diffs one leaderboard. Our side is read twice as deep, so players
+// just below the cut show up as rank differences rather than as missing.
+func (w *CompareWorker) compare(ctx context.Context, leaderboardID string) migration.Report {
+	n := w.config.TopN
+	fail := func(err error) migration.Report {
+		return migration.Report{LeaderboardID: leaderboardID, ComparedAt: w.clock.Now(), TopN: n, Error: err.Error()}
+	}
+
+	top, err := w.reader.GetTopN(ctx, leaderboardID, 2*n)
+	if err != nil {
+		return fail(err)
+	}
+	legacy, err := w.legacy.Top(ctx, leaderboardID, n)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Ghosts only exist here, so they are left out of our side
+	entries := make([]migration.Entry, 0, len(top))
+	for _, entry := range top {
+		if entry.IsGhost {
+			continue
+		}
+		entries = append(entries, migration.Entry{PlayerID: entry.PlayerID, Score: entry.Score})
+	}
+
+	report := migration.Compare(entries, legacy, n, w.config.MaxMismatches)
+	report.LeaderboardID = leaderboardID
+	report.ComparedAt = w.clock.Now()
+	return report
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *CompareWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// Expirer removes the players of a leaderboard who stopped submitting
+type Expirer interface {
+	ExpireInactive(ctx context.Context, lbConfig *domain.LeaderboardConfig, batchSize int) (int, error)
+}
+
+// ExpiryWorker periodically drops players inactive longer than their board's inactivity window
+type ExpiryWorker struct {
+	expirer  Expirer
+	postgres *postgres.Repository
+	config   *config.ExpiryConfig
+	logger   *slog.Logger
+	clock    clock.Clock
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewExpiryWorker creates a new expiry worker
+func NewExpiryWorker(
+	expirer Expirer,
+	postgres *postgres.Repository,
+	cfg *config.ExpiryConfig,
+	logger *slog.Logger,
+) *ExpiryWorker {
+	return &ExpiryWorker{
+		expirer:  expirer,
+		postgres: postgres,
+		config:   cfg,
+		logger:   logger,
+		clock:    clock.Real(),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *ExpiryWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins the background expiry process
+func (w *ExpiryWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("expiry worker started", "interval", w.config.Interval)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background expiry process
+func (w *ExpiryWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("expiry worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *ExpiryWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	ticker := w.clock.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce sweeps every leaderboard that has an inactivity window
+func (w *ExpiryWorker) RunOnce(ctx context.Context) {
+	leaderboards, err := w.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		w.logger.Error("failed to list leaderboards for expiry", "error", err)
+		return
+	}
+
+	for i := range leaderboards {
+		lb := &leaderboards[i]
+		if lb.InactivityDays <= 0 {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		startTime := w.clock.Now()
+		removed, err := w.expirer.ExpireInactive(ctx, lb, w.config.BatchSize)
+		if err != nil {
+			w.logger.Error("failed to expire inactive players",
+				"leaderboard_id", lb.ID,
+				"removed", removed,
+				"error", err,
+			)
+			continue
+		}
+		if removed > 0 {
+			w.logger.Info("expired inactive players",
+				"leaderboard_id", lb.ID,
+				"inactivity_days", lb.InactivityDays,
+				"count", removed,
+				"duration", w.clock.Since(startTime),
+			)
+		}
+	}
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *ExpiryWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
@@ -0,0 +1,348 @@
+package worker
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// BatchSubmitter applies batches of score submissions
+type BatchSubmitter interface {
+	SubmitScoreBatchWithResult(ctx context.Context, batch domain.BatchScoreSubmission) domain.BatchResult
+}
+
+// IngestReport summarizes the import of one CSV file. It is written next to
+// the archived file as <name>.report.json.
+type IngestReport struct {
+	File       string        `json:"file"`
+	StartedAt  time.Time     `json:"started_at"`
+	DurationMs int64         `json:"duration_ms"`
+	Rows       int           `json:"rows"`
+	Applied    int           `json:"applied"`
+	Rejected   int           `json:"rejected"`
+	Errors     []IngestError `json:"errors,omitempty"`
+	FatalError string        `json:"fatal_error,omitempty"`
+}
+
+// IngestError describes a CSV row that was not applied
+type IngestError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// maxReportErrors caps the row errors kept in a report
+const maxReportErrors = 1000
+
+// IngestWorker polls a directory for CSV score dumps and applies them
+type IngestWorker struct {
+	submitter BatchSubmitter
+	config    *config.IngestConfig
+	logger    *slog.Logger
+	clock     clock.Clock
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	mu        sync.Mutex
+	running   bool
+}
+
+// NewIngestWorker creates a new CSV ingestion worker
+func NewIngestWorker(
+	submitter BatchSubmitter,
+	cfg *config.IngestConfig,
+	logger *slog.Logger,
+) *IngestWorker {
+	return &IngestWorker{
+		submitter: submitter,
+		config:    cfg,
+		logger:    logger,
+		clock:     clock.Real(),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *IngestWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins watching the ingest directory
+func (w *IngestWorker) Start(ctx context.Context) error {
+	for _, dir := range []string{w.config.Dir, w.config.ArchiveDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating ingest directory: %w", err)
+		}
+	}
+
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("ingest worker started",
+		"dir", w.config.Dir,
+		"archive_dir", w.config.ArchiveDir,
+		"poll_interval", w.config.PollInterval,
+	)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops watching the ingest directory
+func (w *IngestWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("ingest worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *IngestWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	ticker := w.clock.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce imports every settled file in the ingest directory, oldest first
+func (w *IngestWorker) RunOnce(ctx context.Context) {
+	files, err := w.pendingFiles()
+	if err != nil {
+		w.logger.Error("failed to list ingest directory", "dir", w.config.Dir, "error", err)
+		return
+	}
+
+	for _, path := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		report := w.ingestFile(ctx, path)
+		if err := w.archive(path, report); err != nil {
+			w.logger.Error("failed to archive ingested file", "file", path, "error", err)
+			continue
+		}
+
+		w.logger.Info("ingested score file",
+			"file", report.File,
+			"rows", report.Rows,
+			"applied", report.Applied,
+			"rejected", report.Rejected,
+			"fatal_error", report.FatalError,
+			"duration_ms", report.DurationMs,
+		)
+	}
+}
+
+// pendingFiles returns matching files that have not been modified for the settle time
+func (w *IngestWorker) pendingFiles() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(w.config.Dir, w.config.Pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	type pending struct {
+		path    string
+		modTime time.Time
+	}
+	var files []pending
+	now := time.Now()
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		// Skip files that may still be being written
+		if now.Sub(info.ModTime()) < w.config.SettleTime {
+			continue
+		}
+		files = append(files, pending{path: path, modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// ingestFile parses a CSV of player_id,leaderboard_id,score rows and applies them in batches
+func (w *IngestWorker) ingestFile(ctx context.Context, path string) *IngestReport {
+	report := &IngestReport{
+		File:      filepath.Base(path),
+		StartedAt: w.clock.Now(),
+	}
+	startTime := time.Now()
+	defer func() {
+		report.DurationMs = time.Since(startTime).Milliseconds()
+	}()
+
+	f, err := os.Open(path)
+	if err != nil {
+		report.FatalError = err.Error()
+		return report
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	batch := make([]domain.ScoreSubmission, 0, w.config.BatchSize)
+	lines := make([]int, 0, w.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		result := w.submitter.SubmitScoreBatchWithResult(ctx, domain.BatchScoreSubmission{Scores: batch})
+		report.Applied += result.Accepted
+		for _, failure := range result.Failed {
+			report.addError(lines[failure.Index], errors.New(failure.Error))
+		}
+		batch = batch[:0]
+		lines = lines[:0]
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				report.Rows++
+				report.addError(parseErr.Line, err)
+				continue
+			}
+			report.FatalError = err.Error()
+			break
+		}
+		line, _ := reader.FieldPos(0)
+
+		// An optional header row is recognized by its first column
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "player_id") {
+			continue
+		}
+
+		report.Rows++
+		submission, err := parseScoreRecord(record)
+		if err != nil {
+			report.addError(line, err)
+			continue
+		}
+
+		batch = append(batch, submission)
+		lines = append(lines, line)
+		if len(batch) >= w.config.BatchSize {
+			flush()
+		}
+	}
+	flush()
+
+	return report
+}
+
+// addError records a rejected row, keeping at most maxReportErrors details
+func (r *IngestReport) addError(line int, err error) {
+	r.Rejected++
+	if len(r.Errors) < maxReportErrors {
+		r.Errors = append(r.Errors, IngestError{Line: line, Error: err.Error()})
+	}
+}
+
+// parseScoreRecord converts a player_id,leaderboard_id,score[,replay_url] row into a submission
+func parseScoreRecord(record []string) (domain.ScoreSubmission, error) {
+	if len(record) != 3 && len(record) != 4 {
+		return domain.ScoreSubmission{}, fmt.Errorf("expected 3 or 4 columns, got %d", len(record))
+	}
+
+	playerID := strings.TrimSpace(record[0])
+	leaderboardID := strings.TrimSpace(record[1])
+	if playerID == "" || leaderboardID == "" {
+		return domain.ScoreSubmission{}, domain.ErrInvalidRequest
+	}
+
+	score, err := strconv.ParseInt(strings.TrimSpace(record[2]), 10, 64)
+	if err != nil {
+		return domain.ScoreSubmission{}, fmt.Errorf("invalid score %q", record[2])
+	}
+
+	submission := domain.ScoreSubmission{
+		PlayerID:      playerID,
+		LeaderboardID: leaderboardID,
+		Score:         score,
+	}
+	if len(record) == 4 {
+		submission.ReplayURL = strings.TrimSpace(record[3])
+	}
+	return submission, nil
+}
+
+// archive moves a processed file into the archive directory and writes its report beside it
+func (w *IngestWorker) archive(path string, report *IngestReport) error {
+	stamp := w.clock.Now().UTC().Format("20060102T150405")
+	dest := filepath.Join(w.config.ArchiveDir, stamp+"-"+filepath.Base(path))
+
+	if err := os.Rename(path, dest); err != nil {
+		return fmt.Errorf("moving file: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+	if err := os.WriteFile(dest+".report.json", data, 0o644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *IngestWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
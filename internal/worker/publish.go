@@ -0,0 +1,223 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/blobstore"
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// StandingsRenderer renders the standings the publisher writes
+type StandingsRenderer interface {
+	PublishedStandings(ctx context.Context, lbConfig *domain.LeaderboardConfig, n int) (*domain.PublishedStandings, error)
+}
+
+// Invalidator purges CDN copies of a leaderboard's files
+type Invalidator interface {
+	Invalidate(leaderboardID string)
+}
+
+// PublishedKey returns the object key a leaderboard's standings are published
+// under, before the store's prefix
+func PublishedKey(leaderboardID string) string {
+	return fmt.Sprintf("leaderboards/%s/top.json", leaderboardID)
+}
+
+// PublishWorker periodically writes each leaderboard's top entries to a blob
+// store as static JSON. Boards whose version has not moved since their last
+// upload are skipped.
+type PublishWorker struct {
+	renderer  StandingsRenderer
+	store     blobstore.Store
+	postgres  *postgres.Repository
+	config    *config.PublishConfig
+	logger    *slog.Logger
+	clock     clock.Clock
+	purger    Invalidator
+	published map[string]int64 // leaderboard ID -> last published version
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	mu        sync.Mutex
+	running   bool
+}
+
+// NewPublishWorker creates a new publish worker
+func NewPublishWorker(
+	renderer StandingsRenderer,
+	store blobstore.Store,
+	postgres *postgres.Repository,
+	cfg *config.PublishConfig,
+	logger *slog.Logger,
+) *PublishWorker {
+	return &PublishWorker{
+		renderer:  renderer,
+		store:     store,
+		postgres:  postgres,
+		config:    cfg,
+		logger:    logger,
+		clock:     clock.Real(),
+		published: make(map[string]int64),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *PublishWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// SetPurger sets the purger told about every uploaded file, so CDN copies are
+// refreshed before their max-age runs out
+func (w *PublishWorker) SetPurger(purger Invalidator) {
+	w.purger = purger
+}
+
+// Start begins the background publish process
+func (w *PublishWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("publish worker started", "interval", w.config.Interval, "top_n", w.config.TopN)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background publish process
+func (w *PublishWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("publish worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *PublishWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	// Publish right away so the files exist before the first tick
+	w.RunOnce(ctx)
+
+	ticker := w.clock.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce publishes every configured leaderboard whose standings changed
+func (w *PublishWorker) RunOnce(ctx context.Context) {
+	leaderboards, err := w.leaderboards(ctx)
+	if err != nil {
+		w.logger.Error("failed to list leaderboards for publishing", "error", err)
+		return
+	}
+
+	for i := range leaderboards {
+		if ctx.Err() != nil {
+			return
+		}
+		lb := &leaderboards[i]
+		if err := w.publish(ctx, lb); err != nil {
+			w.logger.Error("failed to publish leaderboard", "leaderboard_id", lb.ID, "error", err)
+		}
+	}
+}
+
+// leaderboards returns the configured boards, or every board when none are listed
+func (w *PublishWorker) leaderboards(ctx context.Context) ([]domain.LeaderboardConfig, error) {
+	if len(w.config.Leaderboards) == 0 {
+		return w.postgres.ListLeaderboards(ctx)
+	}
+
+	leaderboards := make([]domain.LeaderboardConfig, 0, len(w.config.Leaderboards))
+	for _, id := range w.config.Leaderboards {
+		lb, err := w.postgres.GetLeaderboard(ctx, id)
+		if errors.Is(err, domain.ErrLeaderboardNotFound) {
+			w.logger.Warn("skipping unknown leaderboard for publishing", "leaderboard_id", id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		leaderboards = append(leaderboards, *lb)
+	}
+	return leaderboards, nil
+}
+
+// publish renders and uploads one leaderboard unless its version is already published
+func (w *PublishWorker) publish(ctx context.Context, lb *domain.LeaderboardConfig) error {
+	standings, err := w.renderer.PublishedStandings(ctx, lb, w.config.TopN)
+	if err != nil {
+		return err
+	}
+	if version, ok := w.published[lb.ID]; ok && version == standings.Version {
+		return nil
+	}
+
+	body, err := json.Marshal(standings)
+	if err != nil {
+		return fmt.Errorf("encoding standings: %w", err)
+	}
+	err = w.store.Put(ctx, blobstore.Object{
+		Key:          PublishedKey(lb.ID),
+		Body:         body,
+		ContentType:  "application/json",
+		CacheControl: fmt.Sprintf("public, max-age=%d", int(w.config.CacheMaxAge.Seconds())),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.published[lb.ID] = standings.Version
+	if w.purger != nil {
+		w.purger.Invalidate(lb.ID)
+	}
+	w.logger.Debug("published leaderboard",
+		"leaderboard_id", lb.ID,
+		"version", standings.Version,
+		"entries", len(standings.Entries),
+	)
+	return nil
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *PublishWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/leaderboard-redis/internal/domain"
+)
+
+// StartupReport summarizes the PostgreSQL to Redis restore performed at boot
+type StartupReport struct {
+	StartedAt       time.Time          `json:"started_at"`
+	DurationMs      int64              `json:"duration_ms"`
+	BoardsFound     int                `json:"boards_found"`
+	BoardsRestored  int                `json:"boards_restored"`
+	BoardsFailed    int                `json:"boards_failed"`
+	PlayersRestored int                `json:"players_restored"`
+	Discrepancies   []BoardDiscrepancy `json:"discrepancies"`
+	OrphanedBoards  []string           `json:"orphaned_boards"`
+}
+
+// BoardDiscrepancy describes differences between PostgreSQL and leftover Redis data for a board
+type BoardDiscrepancy struct {
+	LeaderboardID    string `json:"leaderboard_id"`
+	RedisOnlyPlayers int    `json:"redis_only_players"`
+	ScoreMismatches  int    `json:"score_mismatches"`
+}
+
+// compareScores reports how leftover Redis scores differ from the PostgreSQL copy
+func (w *SyncWorker) compareScores(ctx context.Context, leaderboardID string, scores map[string]int64) (BoardDiscrepancy, error) {
+	discrepancy := BoardDiscrepancy{LeaderboardID: leaderboardID}
+
+	entries, err := w.redis.GetAllScores(ctx, leaderboardID, w.higherIsBetter(ctx, leaderboardID))
+	if err != nil {
+		return discrepancy, err
+	}
+
+	for _, entry := range entries {
+		if domain.IsGhostID(entry.PlayerID) {
+			continue
+		}
+		score, ok := scores[entry.PlayerID]
+		switch {
+		case !ok:
+			discrepancy.RedisOnlyPlayers++
+		case score != entry.Score:
+			discrepancy.ScoreMismatches++
+		}
+	}
+	return discrepancy, nil
+}
+
+// findOrphanedBoards returns Redis leaderboards with no PostgreSQL definition
+func (w *SyncWorker) findOrphanedBoards(ctx context.Context, known map[string]bool) ([]string, error) {
+	ids, err := w.redis.ListLeaderboardIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orphaned := []string{}
+	for _, id := range ids {
+		if !known[id] {
+			orphaned = append(orphaned, id)
+		}
+	}
+	return orphaned, nil
+}
+
+// StartupReport returns the reconciliation report from the last boot, or nil if none ran
+func (w *SyncWorker) StartupReport() *StartupReport {
+	w.statusMu.RLock()
+	defer w.statusMu.RUnlock()
+	return w.startupReport
+}
@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// Resetter clears a leaderboard's scores
+type Resetter interface {
+	ResetLeaderboard(ctx context.Context, leaderboardID string) error
+}
+
+// ResetWorker resets daily, weekly, and monthly leaderboards when their period rolls over
+type ResetWorker struct {
+	resetter Resetter
+	postgres *postgres.Repository
+	config   *config.ResetConfig
+	schedule domain.ResetSchedule
+	logger   *slog.Logger
+	clock    clock.Clock
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewResetWorker creates a new reset worker
+func NewResetWorker(
+	resetter Resetter,
+	postgres *postgres.Repository,
+	cfg *config.ResetConfig,
+	logger *slog.Logger,
+) *ResetWorker {
+	return &ResetWorker{
+		resetter: resetter,
+		postgres: postgres,
+		config:   cfg,
+		schedule: domain.DefaultResetSchedule(),
+		logger:   logger,
+		clock:    clock.Real(),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *ResetWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// SetSchedule sets the timezone and anchor that period boundaries follow; call before Start
+func (w *ResetWorker) SetSchedule(schedule domain.ResetSchedule) {
+	w.schedule = schedule
+}
+
+// Start begins the background reset process
+func (w *ResetWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("reset worker started", "check_interval", w.config.CheckInterval)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background reset process
+func (w *ResetWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("reset worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *ResetWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	// Catch up on resets missed while the service was down
+	w.RunOnce(ctx)
+
+	ticker := w.clock.NewTicker(w.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce resets every leaderboard whose current period started after its last reset
+func (w *ResetWorker) RunOnce(ctx context.Context) {
+	leaderboards, err := w.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		w.logger.Error("failed to list leaderboards for reset", "error", err)
+		return
+	}
+
+	now := w.clock.Now()
+	for _, lb := range leaderboards {
+		schedule := w.schedule.For(&lb)
+		current, ok := schedule.PeriodStart(lb.ResetPeriod, now)
+		if !ok {
+			continue
+		}
+
+		if !w.schedule.ScoresPeriodStart(&lb).Before(current) {
+			continue
+		}
+
+		if err := w.resetter.ResetLeaderboard(ctx, lb.ID); err != nil {
+			w.logger.Error("failed to reset leaderboard",
+				"leaderboard_id", lb.ID,
+				"reset_period", lb.ResetPeriod,
+				"error", err,
+			)
+			continue
+		}
+		if err := w.postgres.SetLastReset(ctx, lb.ID, current); err != nil {
+			w.logger.Error("failed to record leaderboard reset",
+				"leaderboard_id", lb.ID,
+				"error", err,
+			)
+			continue
+		}
+
+		next, _ := schedule.NextReset(lb.ResetPeriod, now)
+		w.logger.Info("reset leaderboard for new period",
+			"leaderboard_id", lb.ID,
+			"reset_period", lb.ResetPeriod,
+			"period_start", current,
+			"timezone", schedule.Location,
+			"next_reset_at", next,
+		)
+	}
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *ResetWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
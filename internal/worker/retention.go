@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// RetentionWorker periodically cleans up score events left behind by deleted leaderboards
+type RetentionWorker struct {
+	postgres *postgres.Repository
+	config   *config.RetentionConfig
+	logger   *slog.Logger
+	clock    clock.Clock
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewRetentionWorker creates a new retention worker
+func NewRetentionWorker(
+	postgres *postgres.Repository,
+	cfg *config.RetentionConfig,
+	logger *slog.Logger,
+) *RetentionWorker {
+	return &RetentionWorker{
+		postgres: postgres,
+		config:   cfg,
+		logger:   logger,
+		clock:    clock.Real(),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *RetentionWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins the background retention process
+func (w *RetentionWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("retention worker started",
+		"interval", w.config.Interval,
+		"orphan_policy", w.config.OrphanPolicy,
+	)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background retention process
+func (w *RetentionWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("retention worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *RetentionWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	ticker := w.clock.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce applies the orphan policy to all orphaned score events
+func (w *RetentionWorker) RunOnce(ctx context.Context) {
+	var apply func(context.Context, int) (int64, error)
+	switch w.config.OrphanPolicy {
+	case config.OrphanPolicyDelete:
+		apply = w.postgres.DeleteOrphanedEvents
+	case config.OrphanPolicyArchive:
+		apply = w.postgres.ArchiveOrphanedEvents
+	default:
+		return
+	}
+
+	startTime := w.clock.Now()
+	var total int64
+
+	// Work in batches so a large backlog doesn't hold long locks
+	for {
+		n, err := apply(ctx, w.config.BatchSize)
+		if err != nil {
+			w.logger.Error("failed to clean up orphaned score events",
+				"orphan_policy", w.config.OrphanPolicy,
+				"error", err,
+			)
+			return
+		}
+		total += n
+		if n < int64(w.config.BatchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.logger.Info("cleaned up orphaned score events",
+			"orphan_policy", w.config.OrphanPolicy,
+			"count", total,
+			"duration", w.clock.Since(startTime),
+		)
+	}
+}
@@ -0,0 +1,337 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// LastWriteReader reads when submissions were last applied to leaderboards
+type LastWriteReader interface {
+	GetLastWrites(ctx context.Context, leaderboardIDs []string) (map[string]time.Time, error)
+}
+
+// SubscriberCounter counts the live subscribers of a leaderboard
+type SubscriberCounter interface {
+	GetSubscriberCount(leaderboardID string) int
+}
+
+// Staleness alert event types posted to the webhook
+const (
+	StaleEventStale     = "board_stale"
+	StaleEventRecovered = "board_recovered"
+)
+
+// StaleBoard is a watched leaderboard that has gone without a write for longer
+// than allowed. LastWriteAt is nil when the board was never written to.
+type StaleBoard struct {
+	LeaderboardID string     `json:"leaderboard_id"`
+	LastWriteAt   *time.Time `json:"last_write_at,omitempty"`
+	IdleSeconds   int64      `json:"idle_seconds"`
+	Subscribers   int        `json:"subscribers"`
+	StaleSince    time.Time  `json:"stale_since"`
+}
+
+// StalenessStatus reports the latest check and alert counts
+type StalenessStatus struct {
+	CheckedAt       *time.Time   `json:"checked_at,omitempty"`
+	Watched         int          `json:"watched"`
+	Stale           []StaleBoard `json:"stale"`
+	Alerts          int64        `json:"alerts"`
+	Recoveries      int64        `json:"recoveries"`
+	WebhookFailures int64        `json:"webhook_failures"`
+}
+
+// staleEvent is the body posted to the staleness webhook
+type staleEvent struct {
+	Type          string     `json:"type"`
+	LeaderboardID string     `json:"leaderboard_id"`
+	LastWriteAt   *time.Time `json:"last_write_at,omitempty"`
+	IdleSeconds   int64      `json:"idle_seconds"`
+	Subscribers   int        `json:"subscribers"`
+	Timestamp     time.Time  `json:"timestamp"`
+}
+
+// StalenessWorker periodically flags leaderboards that have subscribers but
+// received no submission for longer than their staleness window, which
+// usually means the pipeline feeding them broke. Each board alerts once when
+// it goes stale and once when it recovers.
+type StalenessWorker struct {
+	writes      LastWriteReader
+	subscribers SubscriberCounter
+	postgres    *postgres.Repository
+	config      *config.StalenessConfig
+	logger      *slog.Logger
+	clock       clock.Clock
+	client      *http.Client
+	startedAt   time.Time
+
+	// stale holds the boards currently flagged, keyed by leaderboard ID
+	stale     map[string]StaleBoard
+	checkedAt *time.Time
+	watched   int
+	statusMu  sync.RWMutex
+
+	alerts          atomic.Int64
+	recoveries      atomic.Int64
+	webhookFailures atomic.Int64
+
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	mu      sync.Mutex
+	running bool
+}
+
+// NewStalenessWorker creates a new staleness worker
+func NewStalenessWorker(
+	writes LastWriteReader,
+	subscribers SubscriberCounter,
+	postgres *postgres.Repository,
+	cfg *config.StalenessConfig,
+	logger *slog.Logger,
+) *StalenessWorker {
+	return &StalenessWorker{
+		writes:      writes,
+		subscribers: subscribers,
+		postgres:    postgres,
+		config:      cfg,
+		logger:      logger,
+		clock:       clock.Real(),
+		client:      &http.Client{Timeout: cfg.Webhook.Timeout},
+		stale:       make(map[string]StaleBoard),
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *StalenessWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins the background staleness checks
+func (w *StalenessWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.startedAt = w.clock.Now()
+	w.mu.Unlock()
+
+	w.logger.Info("staleness worker started", "interval", w.config.Interval, "after", w.config.After)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops the background staleness checks
+func (w *StalenessWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("staleness worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *StalenessWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	ticker := w.clock.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce checks every leaderboard with subscribers and an open submission
+// window, alerting on boards that went stale or recovered since the last check
+func (w *StalenessWorker) RunOnce(ctx context.Context) {
+	leaderboards, err := w.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		w.logger.Error("failed to list leaderboards for staleness check", "error", err)
+		return
+	}
+	ids := make([]string, len(leaderboards))
+	for i, lb := range leaderboards {
+		ids[i] = lb.ID
+	}
+	lastWrites, err := w.writes.GetLastWrites(ctx, ids)
+	if err != nil {
+		w.logger.Error("failed to read last writes for staleness check", "error", err)
+		return
+	}
+
+	now := w.clock.Now()
+	w.mu.Lock()
+	startedAt := w.startedAt
+	w.mu.Unlock()
+
+	watched := 0
+	stale := make(map[string]StaleBoard)
+	for i := range leaderboards {
+		lb := &leaderboards[i]
+		subscribers := w.subscribers.GetSubscriberCount(lb.ID)
+		if subscribers < w.config.MinSubscribers || lb.CheckWindow(now) != nil {
+			continue
+		}
+		watched++
+
+		// Boards never written to are measured from their creation, or from
+		// when the worker started for boards older than the last-write stamp
+		board := StaleBoard{LeaderboardID: lb.ID, Subscribers: subscribers}
+		since := lb.CreatedAt
+		if lastWrite, ok := lastWrites[lb.ID]; ok {
+			board.LastWriteAt = &lastWrite
+			since = lastWrite
+		} else if since.Before(startedAt) {
+			since = startedAt
+		}
+		idle := now.Sub(since)
+		if idle <= w.config.StaleAfter(lb.ID) {
+			continue
+		}
+		board.IdleSeconds = int64(idle.Seconds())
+		board.StaleSince = since.Add(w.config.StaleAfter(lb.ID))
+		stale[lb.ID] = board
+	}
+
+	w.statusMu.Lock()
+	previous := w.stale
+	w.stale = stale
+	w.checkedAt = &now
+	w.watched = watched
+	w.statusMu.Unlock()
+
+	for id, board := range stale {
+		if _, known := previous[id]; known {
+			continue
+		}
+		w.alerts.Add(1)
+		w.logger.Warn("leaderboard stopped receiving scores",
+			"leaderboard_id", id,
+			"idle", time.Duration(board.IdleSeconds)*time.Second,
+			"subscribers", board.Subscribers,
+		)
+		w.notify(ctx, StaleEventStale, board, now)
+	}
+	for id, board := range previous {
+		if _, still := stale[id]; still {
+			continue
+		}
+		w.recoveries.Add(1)
+		w.logger.Info("leaderboard no longer stale", "leaderboard_id", id)
+		board.IdleSeconds = 0
+		board.Subscribers = w.subscribers.GetSubscriberCount(id)
+		if lastWrite, ok := lastWrites[id]; ok {
+			board.LastWriteAt = &lastWrite
+		}
+		w.notify(ctx, StaleEventRecovered, board, now)
+	}
+}
+
+// Status returns the boards flagged by the latest check and the alert counts
+func (w *StalenessWorker) Status() StalenessStatus {
+	w.statusMu.RLock()
+	defer w.statusMu.RUnlock()
+
+	status := StalenessStatus{
+		CheckedAt:       w.checkedAt,
+		Watched:         w.watched,
+		Stale:           make([]StaleBoard, 0, len(w.stale)),
+		Alerts:          w.alerts.Load(),
+		Recoveries:      w.recoveries.Load(),
+		WebhookFailures: w.webhookFailures.Load(),
+	}
+	for _, board := range w.stale {
+		status.Stale = append(status.Stale, board)
+	}
+	sort.Slice(status.Stale, func(i, j int) bool { return status.Stale[i].LeaderboardID < status.Stale[j].LeaderboardID })
+	return status
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *StalenessWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
+
+// notify posts an alert event to the configured webhook, if any
+func (w *StalenessWorker) notify(ctx context.Context, eventType string, board StaleBoard, now time.Time) {
+	if w.config.Webhook.URL == "" {
+		return
+	}
+	event := staleEvent{
+		Type:          eventType,
+		LeaderboardID: board.LeaderboardID,
+		LastWriteAt:   board.LastWriteAt,
+		IdleSeconds:   board.IdleSeconds,
+		Subscribers:   board.Subscribers,
+		Timestamp:     now,
+	}
+	if err := w.post(ctx, event); err != nil {
+		w.webhookFailures.Add(1)
+		w.logger.Error("failed to post staleness alert", "leaderboard_id", board.LeaderboardID, "type", eventType, "error", err)
+	}
+}
+
+// post sends one event as JSON; any 2xx response counts as success
+func (w *StalenessWorker) post(ctx context.Context, event staleEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling staleness event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating staleness request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.config.Webhook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting staleness event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("staleness webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
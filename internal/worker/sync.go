@@ -2,25 +2,67 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/leaderboard-redis/internal/clock"
 	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
 	"github.com/leaderboard-redis/internal/postgres"
 	"github.com/leaderboard-redis/internal/redis"
 )
 
+// maxRestoredPending caps how many queued reviews are relisted in Redis per board on restore
+const maxRestoredPending = 10000
+
+// Trimmer caps a leaderboard at its max_entries
+type Trimmer interface {
+	TrimLeaderboard(ctx context.Context, leaderboardID string) (int, error)
+}
+
 // SyncWorker handles periodic synchronization between Redis and PostgreSQL
 type SyncWorker struct {
 	redis      *redis.LeaderboardService
 	postgres   *postgres.Repository
+	trimmer    Trimmer
 	config     *config.SyncConfig
 	logger     *slog.Logger
 	stopCh     chan struct{}
 	doneCh     chan struct{}
 	mu         sync.Mutex
 	running    bool
+	clock      clock.Clock
+
+	statusMu      sync.RWMutex
+	status        SyncStatus
+	startupReport *StartupReport
+}
+
+// SyncStatus describes the sync worker's recent activity
+type SyncStatus struct {
+	WorkerRunning      bool                       `json:"worker_running"`
+	CycleRunning       bool                       `json:"cycle_running"`
+	CurrentLeaderboard string                     `json:"current_leaderboard,omitempty"`
+	Cycles             int64                      `json:"cycles"`
+	LastRunAt          *time.Time                 `json:"last_run_at,omitempty"`
+	LastDurationMs     int64                      `json:"last_duration_ms"`
+	LastSynced         int                        `json:"last_synced"`
+	LastErrors         int                        `json:"last_errors"`
+	TotalErrors        int64                      `json:"total_errors"`
+	Leaderboards       map[string]BoardSyncStatus `json:"leaderboards"`
+}
+
+// BoardSyncStatus describes the most recent sync of a single leaderboard
+type BoardSyncStatus struct {
+	LastSyncedAt time.Time `json:"last_synced_at"`
+	PlayerCount  int       `json:"player_count"`
+	Skipped      int       `json:"skipped"`
+	DurationMs   int64     `json:"duration_ms"`
+	Errors       int64     `json:"errors"`
+	LastError    string    `json:"last_error,omitempty"`
 }
 
 // NewSyncWorker creates a new sync worker
@@ -35,11 +77,49 @@ func NewSyncWorker(
 		postgres: postgres,
 		config:   cfg,
 		logger:   logger,
+		clock:    clock.Real(),
 		stopCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
+		status: SyncStatus{
+			Leaderboards: make(map[string]BoardSyncStatus),
+		},
 	}
 }
 
+// SetClock replaces the clock that schedules sync cycles; call before Start.
+// Staggering within a cycle always uses real time since it paces database load.
+func (w *SyncWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// SetTrimmer sets what caps leaderboards at their max_entries during sync; call before Start
+func (w *SyncWorker) SetTrimmer(t Trimmer) {
+	w.trimmer = t
+}
+
+// trim caps a leaderboard before its scores are copied, so entries beyond
+// max_entries, for example from before the cap was enforced, are evicted
+func (w *SyncWorker) trim(ctx context.Context, leaderboardID string) {
+	if w.trimmer == nil {
+		return
+	}
+	if _, err := w.trimmer.TrimLeaderboard(ctx, leaderboardID); err != nil && !errors.Is(err, domain.ErrLeaderboardNotFound) {
+		w.logger.Warn("failed to trim leaderboard", "leaderboard_id", leaderboardID, "error", err)
+	}
+}
+
+// higherIsBetter reads a leaderboard's sort order from its Redis metadata,
+// falling back to PostgreSQL; boards found in neither rank highest first
+func (w *SyncWorker) higherIsBetter(ctx context.Context, leaderboardID string) bool {
+	if lbConfig, err := w.redis.GetLeaderboardMeta(ctx, leaderboardID); err == nil {
+		return lbConfig.HigherIsBetter()
+	}
+	if lbConfig, err := w.postgres.GetLeaderboard(ctx, leaderboardID); err == nil {
+		return lbConfig.HigherIsBetter()
+	}
+	return true
+}
+
 // Start begins the background sync process
 func (w *SyncWorker) Start(ctx context.Context) error {
 	w.mu.Lock()
@@ -50,7 +130,10 @@ func (w *SyncWorker) Start(ctx context.Context) error {
 	w.running = true
 	w.mu.Unlock()
 
-	w.logger.Info("sync worker started", "interval", w.config.Interval)
+	w.logger.Info("sync worker started",
+		"interval", w.config.Interval,
+		"conflict_policy", w.config.ConflictPolicy,
+	)
 
 	go w.run(ctx)
 	return nil
@@ -80,7 +163,7 @@ func (w *SyncWorker) Stop() error {
 func (w *SyncWorker) run(ctx context.Context) {
 	defer close(w.doneCh)
 
-	ticker := time.NewTicker(w.config.Interval)
+	ticker := w.clock.NewTicker(w.config.Interval)
 	defer ticker.Stop()
 
 	for {
@@ -89,7 +172,7 @@ func (w *SyncWorker) run(ctx context.Context) {
 			return
 		case <-w.stopCh:
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			w.syncAll(ctx)
 		}
 	}
@@ -100,29 +183,69 @@ func (w *SyncWorker) syncAll(ctx context.Context) {
 	w.logger.Info("starting sync cycle")
 	startTime := time.Now()
 
+	w.statusMu.Lock()
+	w.status.CycleRunning = true
+	w.statusMu.Unlock()
+
 	// Get all leaderboards from PostgreSQL
 	leaderboards, err := w.postgres.ListLeaderboards(ctx)
 	if err != nil {
 		w.logger.Error("failed to list leaderboards for sync", "error", err)
+		w.finishCycle(startTime, 0, 1)
 		return
 	}
 
-	syncedCount := 0
-	errorCount := 0
+	concurrency := w.config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
 
-	for _, lb := range leaderboards {
-		if err := w.SyncToDatabase(ctx, lb.ID); err != nil {
-			w.logger.Error("failed to sync leaderboard",
-				"leaderboard_id", lb.ID,
-				"error", err,
+	var (
+		wg          sync.WaitGroup
+		countMu     sync.Mutex
+		syncedCount int
+		errorCount  int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for i, lb := range leaderboards {
+		if !w.waitForSlot(ctx, startTime.Add(w.slotOffset(i, len(leaderboards)))) {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(leaderboardID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			boardStart := time.Now()
+			err := w.SyncToDatabase(ctx, leaderboardID)
+
+			countMu.Lock()
+			defer countMu.Unlock()
+			if err != nil {
+				w.logger.Error("failed to sync leaderboard",
+					"leaderboard_id", leaderboardID,
+					"duration", time.Since(boardStart),
+					"error", err,
+				)
+				errorCount++
+				return
+			}
+			w.logger.Info("synced leaderboard",
+				"leaderboard_id", leaderboardID,
+				"duration", time.Since(boardStart),
 			)
-			errorCount++
-		} else {
 			syncedCount++
-		}
+		}(lb.ID)
 	}
+	wg.Wait()
 
 	duration := time.Since(startTime)
+	w.finishCycle(startTime, syncedCount, errorCount)
 	w.logger.Info("sync cycle completed",
 		"duration", duration,
 		"synced", syncedCount,
@@ -130,24 +253,128 @@ func (w *SyncWorker) syncAll(ctx context.Context) {
 	)
 }
 
+// slotOffset returns when, relative to the cycle start, the i-th of n boards should sync
+func (w *SyncWorker) slotOffset(i, n int) time.Duration {
+	var offset time.Duration
+	if w.config.SpreadWindow > 0 && n > 0 {
+		offset = w.config.SpreadWindow * time.Duration(i) / time.Duration(n)
+	}
+	if w.config.Jitter > 0 {
+		offset += time.Duration(rand.Int63n(int64(w.config.Jitter)))
+	}
+	return offset
+}
+
+// waitForSlot blocks until the given time, returning false if the worker is stopping
+func (w *SyncWorker) waitForSlot(ctx context.Context, at time.Time) bool {
+	delay := time.Until(at)
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.stopCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// finishCycle records the outcome of a sync cycle
+func (w *SyncWorker) finishCycle(startTime time.Time, synced, errors int) {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+
+	w.status.CycleRunning = false
+	w.status.CurrentLeaderboard = ""
+	w.status.Cycles++
+	w.status.LastRunAt = &startTime
+	w.status.LastDurationMs = time.Since(startTime).Milliseconds()
+	w.status.LastSynced = synced
+	w.status.LastErrors = errors
+	w.status.TotalErrors += int64(errors)
+}
+
+// Status returns a snapshot of the worker's sync status
+func (w *SyncWorker) Status() SyncStatus {
+	w.statusMu.RLock()
+	defer w.statusMu.RUnlock()
+
+	status := w.status
+	status.WorkerRunning = w.IsRunning()
+	status.Leaderboards = make(map[string]BoardSyncStatus, len(w.status.Leaderboards))
+	for id, board := range w.status.Leaderboards {
+		status.Leaderboards[id] = board
+	}
+	return status
+}
+
 // SyncToDatabase syncs a leaderboard from Redis to PostgreSQL
 func (w *SyncWorker) SyncToDatabase(ctx context.Context, leaderboardID string) error {
+	startTime := time.Now()
+
+	w.statusMu.Lock()
+	w.status.CurrentLeaderboard = leaderboardID
+	w.statusMu.Unlock()
+
+	count, skipped, err := w.syncToDatabase(ctx, leaderboardID)
+
+	w.statusMu.Lock()
+	board := w.status.Leaderboards[leaderboardID]
+	board.LastSyncedAt = startTime
+	board.DurationMs = time.Since(startTime).Milliseconds()
+	if err != nil {
+		board.Errors++
+		board.LastError = err.Error()
+	} else {
+		board.PlayerCount = count
+		board.Skipped = skipped
+		board.LastError = ""
+	}
+	w.status.Leaderboards[leaderboardID] = board
+	w.statusMu.Unlock()
+
+	return err
+}
+
+// syncToDatabase copies a leaderboard's Redis scores to PostgreSQL and returns
+// the player count and how many rows were left alone because PostgreSQL was newer
+func (w *SyncWorker) syncToDatabase(ctx context.Context, leaderboardID string) (int, int, error) {
 	w.logger.Debug("syncing leaderboard to database", "leaderboard_id", leaderboardID)
+	w.trim(ctx, leaderboardID)
 
 	// Get all scores from Redis
-	entries, err := w.redis.GetAllScores(ctx, leaderboardID)
+	entries, err := w.redis.GetAllScores(ctx, leaderboardID, w.higherIsBetter(ctx, leaderboardID))
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	if len(entries) == 0 {
 		w.logger.Debug("no scores to sync", "leaderboard_id", leaderboardID)
-		return nil
+		return 0, 0, nil
 	}
 
-	// Convert to map for batch upsert
+	// Under newer_wins, rows edited in PostgreSQL after the player's last
+	// Redis write are kept instead of being overwritten
+	var writtenAt map[string]time.Time
+	if w.config.ConflictPolicy == config.SyncConflictNewerWins {
+		writtenAt, err = w.redis.GetWriteTimes(ctx, leaderboardID)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// Convert to map for batch upsert; ghost entries live in their own table
 	scores := make(map[string]int64, len(entries))
 	for _, entry := range entries {
+		if domain.IsGhostID(entry.PlayerID) {
+			continue
+		}
 		scores[entry.PlayerID] = entry.Score
 	}
 
@@ -160,15 +387,18 @@ func (w *SyncWorker) SyncToDatabase(ctx context.Context, leaderboardID string) e
 
 	batch := make(map[string]int64, batchSize)
 	count := 0
+	skipped := 0
 
 	for playerID, score := range scores {
 		batch[playerID] = score
 		count++
 
 		if count >= batchSize {
-			if err := w.postgres.BatchUpsertScores(ctx, leaderboardID, batch); err != nil {
-				return err
+			n, err := w.upsertBatch(ctx, leaderboardID, batch, writtenAt)
+			if err != nil {
+				return 0, 0, err
 			}
+			skipped += n
 			batch = make(map[string]int64, batchSize)
 			count = 0
 		}
@@ -176,9 +406,18 @@ func (w *SyncWorker) SyncToDatabase(ctx context.Context, leaderboardID string) e
 
 	// Process remaining batch
 	if len(batch) > 0 {
-		if err := w.postgres.BatchUpsertScores(ctx, leaderboardID, batch); err != nil {
-			return err
+		n, err := w.upsertBatch(ctx, leaderboardID, batch, writtenAt)
+		if err != nil {
+			return 0, 0, err
 		}
+		skipped += n
+	}
+
+	if skipped > 0 {
+		w.logger.Info("kept newer database scores during sync",
+			"leaderboard_id", leaderboardID,
+			"skipped", skipped,
+		)
 	}
 
 	w.logger.Debug("synced leaderboard to database",
@@ -186,54 +425,115 @@ func (w *SyncWorker) SyncToDatabase(ctx context.Context, leaderboardID string) e
 		"player_count", len(entries),
 	)
 
-	return nil
+	return len(entries), skipped, nil
+}
+
+// upsertBatch writes a batch of scores according to the conflict policy and returns the skipped count
+func (w *SyncWorker) upsertBatch(ctx context.Context, leaderboardID string, batch map[string]int64, writtenAt map[string]time.Time) (int, error) {
+	if w.config.ConflictPolicy != config.SyncConflictNewerWins {
+		return 0, w.postgres.BatchUpsertScores(ctx, leaderboardID, batch)
+	}
+	return w.postgres.BatchUpsertScoresIfOlder(ctx, leaderboardID, batch, writtenAt)
 }
 
 // SyncFromDatabase syncs a leaderboard from PostgreSQL to Redis
 // This is useful for recovery or initialization
 func (w *SyncWorker) SyncFromDatabase(ctx context.Context, leaderboardID string) error {
+	_, err := w.syncFromDatabase(ctx, leaderboardID, nil)
+	return err
+}
+
+// syncFromDatabase restores a leaderboard's scores into Redis and returns the player count.
+// When discrepancy is non-nil it is filled in by comparing leftover Redis data first.
+func (w *SyncWorker) syncFromDatabase(ctx context.Context, leaderboardID string, discrepancy *BoardDiscrepancy) (int, error) {
 	w.logger.Debug("syncing leaderboard from database", "leaderboard_id", leaderboardID)
 
 	// Get all scores from PostgreSQL
 	scores, err := w.postgres.GetAllScores(ctx, leaderboardID)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if discrepancy != nil {
+		*discrepancy, err = w.compareScores(ctx, leaderboardID, scores)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	ghosts, err := w.postgres.ListGhosts(ctx, leaderboardID)
+	if err != nil {
+		return 0, err
+	}
+	for _, ghost := range ghosts {
+		if _, err := w.redis.SetGhost(ctx, ghost); err != nil {
+			return 0, err
+		}
+	}
+
+	pending, _, err := w.postgres.ListPendingScores(ctx, leaderboardID, domain.ReviewPending, postgres.KeysetPage{Limit: maxRestoredPending})
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range pending {
+		if err := w.redis.AddPendingScore(ctx, p); err != nil {
+			return 0, err
+		}
 	}
 
 	if len(scores) == 0 {
 		w.logger.Debug("no scores to sync from database", "leaderboard_id", leaderboardID)
-		return nil
+		return 0, nil
 	}
 
 	// Batch set scores in Redis
 	if err := w.redis.BatchSetScores(ctx, leaderboardID, scores); err != nil {
-		return err
+		return 0, err
 	}
+	w.trim(ctx, leaderboardID)
 
 	w.logger.Debug("synced leaderboard from database",
 		"leaderboard_id", leaderboardID,
 		"player_count", len(scores),
 	)
 
-	return nil
+	return len(scores), nil
 }
 
 // SyncAllFromDatabase syncs all leaderboards from PostgreSQL to Redis
+// and records a reconciliation report available through StartupReport
 func (w *SyncWorker) SyncAllFromDatabase(ctx context.Context) error {
 	w.logger.Info("syncing all leaderboards from database")
+	report := &StartupReport{
+		StartedAt:     time.Now(),
+		Discrepancies: []BoardDiscrepancy{},
+	}
 
 	leaderboards, err := w.postgres.ListLeaderboards(ctx)
 	if err != nil {
 		return err
 	}
+	report.BoardsFound = len(leaderboards)
 
+	known := make(map[string]bool, len(leaderboards))
 	for _, lb := range leaderboards {
-		if err := w.SyncFromDatabase(ctx, lb.ID); err != nil {
+		known[lb.ID] = true
+
+		var discrepancy BoardDiscrepancy
+		restored, err := w.syncFromDatabase(ctx, lb.ID, &discrepancy)
+		if err != nil {
 			w.logger.Error("failed to sync leaderboard from database",
 				"leaderboard_id", lb.ID,
 				"error", err,
 			)
+			report.BoardsFailed++
 			// Continue with other leaderboards
+		} else {
+			report.BoardsRestored++
+			report.PlayersRestored += restored
+			if discrepancy.RedisOnlyPlayers > 0 || discrepancy.ScoreMismatches > 0 {
+				report.Discrepancies = append(report.Discrepancies, discrepancy)
+			}
 		}
 
 		// Also sync metadata
@@ -245,7 +545,26 @@ func (w *SyncWorker) SyncAllFromDatabase(ctx context.Context) error {
 		}
 	}
 
-	w.logger.Info("completed syncing all leaderboards from database", "count", len(leaderboards))
+	report.OrphanedBoards, err = w.findOrphanedBoards(ctx, known)
+	if err != nil {
+		w.logger.Warn("failed to check for orphaned Redis leaderboards", "error", err)
+		report.OrphanedBoards = []string{}
+	}
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+
+	w.statusMu.Lock()
+	w.startupReport = report
+	w.statusMu.Unlock()
+
+	w.logger.Info("completed syncing all leaderboards from database",
+		"count", len(leaderboards),
+		"boards_restored", report.BoardsRestored,
+		"boards_failed", report.BoardsFailed,
+		"players_restored", report.PlayersRestored,
+		"discrepancies", report.Discrepancies,
+		"orphaned_boards", report.OrphanedBoards,
+		"duration", time.Since(report.StartedAt),
+	)
 	return nil
 }
 
@@ -0,0 +1,152 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/leaderboard-redis/internal/clock"
+	"github.com/leaderboard-redis/internal/config"
+	"github.com/leaderboard-redis/internal/domain"
+	"github.com/leaderboard-redis/internal/postgres"
+)
+
+// WindowNotifier announces submission windows opening and closing
+type WindowNotifier interface {
+	NotifyWindow(leaderboardID string, eventType domain.FeedEventType, at time.Time)
+}
+
+// WindowWorker watches leaderboard submission windows and announces each
+// open_at and close_at as it passes, so lobbies learn when tournaments start
+type WindowWorker struct {
+	notifier  WindowNotifier
+	postgres  *postgres.Repository
+	config    *config.GlobalChannelConfig
+	logger    *slog.Logger
+	clock     clock.Clock
+	lastCheck time.Time
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	mu        sync.Mutex
+	running   bool
+}
+
+// NewWindowWorker creates a new window worker
+func NewWindowWorker(
+	notifier WindowNotifier,
+	postgres *postgres.Repository,
+	cfg *config.GlobalChannelConfig,
+	logger *slog.Logger,
+) *WindowWorker {
+	return &WindowWorker{
+		notifier: notifier,
+		postgres: postgres,
+		config:   cfg,
+		logger:   logger,
+		clock:    clock.Real(),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// SetClock replaces the wall clock; call before Start
+func (w *WindowWorker) SetClock(c clock.Clock) {
+	w.clock = c
+}
+
+// Start begins watching submission windows
+func (w *WindowWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	w.logger.Info("window worker started", "check_interval", w.config.WindowCheckInterval)
+
+	go w.run(ctx)
+	return nil
+}
+
+// Stop stops watching submission windows
+func (w *WindowWorker) Stop() error {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return nil
+	}
+	w.mu.Unlock()
+
+	close(w.stopCh)
+	<-w.doneCh
+
+	w.mu.Lock()
+	w.running = false
+	w.mu.Unlock()
+
+	w.logger.Info("window worker stopped")
+	return nil
+}
+
+// run is the main worker loop
+func (w *WindowWorker) run(ctx context.Context) {
+	defer close(w.doneCh)
+
+	// Boundaries passed while the service was down are not announced late
+	w.lastCheck = w.clock.Now()
+
+	ticker := w.clock.NewTicker(w.config.WindowCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		case <-ticker.C():
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce announces every window boundary passed since the previous check
+func (w *WindowWorker) RunOnce(ctx context.Context) {
+	leaderboards, err := w.postgres.ListLeaderboards(ctx)
+	if err != nil {
+		w.logger.Error("failed to list leaderboards for window check", "error", err)
+		return
+	}
+
+	now := w.clock.Now()
+	since := w.lastCheck
+	if since.IsZero() {
+		since = now
+	}
+	for _, lb := range leaderboards {
+		if passed(lb.OpenAt, since, now) {
+			w.notifier.NotifyWindow(lb.ID, domain.FeedWindowOpen, *lb.OpenAt)
+			w.logger.Info("submission window opened", "leaderboard_id", lb.ID, "open_at", *lb.OpenAt)
+		}
+		if passed(lb.CloseAt, since, now) {
+			w.notifier.NotifyWindow(lb.ID, domain.FeedWindowClose, *lb.CloseAt)
+			w.logger.Info("submission window closed", "leaderboard_id", lb.ID, "close_at", *lb.CloseAt)
+		}
+	}
+	w.lastCheck = now
+}
+
+// passed reports whether an optional boundary falls in (since, now]
+func passed(at *time.Time, since, now time.Time) bool {
+	return at != nil && at.After(since) && !at.After(now)
+}
+
+// IsRunning returns whether the worker is currently running
+func (w *WindowWorker) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.running
+}
@@ -0,0 +1,240 @@
+// Package producer publishes score submissions to the leaderboard Kafka topic
+// in the format the consumer expects: one JSON submission per message, keyed
+// by player ID so each player's scores keep their order.
+//
+//	p, err := producer.New(producer.Config{
+//		Brokers: []string{"kafka:9092"},
+//		Topic:   "leaderboard-scores",
+//		OnDelivery: func(d producer.Delivery) {
+//			if d.Err != nil {
+//				log.Printf("score for %s lost: %v", d.Submission.PlayerID, d.Err)
+//			}
+//		},
+//	})
+//	...
+//	err = p.Submit(ctx, producer.ScoreSubmission{PlayerID: "p1", LeaderboardID: "game1", Score: 420})
+//
+// Submit queues the message and returns; messages are sent in batches and
+// each outcome is reported to OnDelivery.
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+)
+
+// Defaults for unset Config fields
+const (
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = 100 * time.Millisecond
+)
+
+var (
+	// ErrClosed is returned by Submit after Close
+	ErrClosed = errors.New("producer is closed")
+	// ErrMissingPlayer is returned by Submit for a submission without a player ID
+	ErrMissingPlayer = errors.New("submission has no player_id")
+)
+
+// ScoreSubmission is one score as read by the leaderboard consumer.
+// LeaderboardID may be left empty on topics routed to a single leaderboard.
+type ScoreSubmission struct {
+	PlayerID      string                 `json:"player_id"`
+	LeaderboardID string                 `json:"leaderboard_id,omitempty"`
+	Score         int64                  `json:"score"`
+	GameID        string                 `json:"game_id,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	ReplayURL     string                 `json:"replay_url,omitempty"`
+	ProofRef      string                 `json:"proof_ref,omitempty"`
+
+	// IdempotencyKey deduplicates redelivered copies, and copies sent over
+	// HTTP as well. Submit generates one when it is empty.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// Delivery is the outcome of one submission. Err is nil once the broker has
+// acknowledged the message.
+type Delivery struct {
+	Submission ScoreSubmission
+	Partition  int32
+	Offset     int64
+	Err        error
+}
+
+// Config configures a Producer
+type Config struct {
+	Brokers []string
+	Topic   string
+
+	// BatchSize and FlushInterval bound how long a message waits to be sent:
+	// a batch goes out when it holds BatchSize messages or FlushInterval passes
+	BatchSize     int
+	FlushInterval time.Duration
+
+	// OnDelivery, when set, is called for every submission once it is
+	// acknowledged or has failed. Calls come from the producer's goroutines
+	// and must not block for long.
+	OnDelivery func(Delivery)
+
+	// Sarama, when set, is used as the base client configuration
+	Sarama *sarama.Config
+}
+
+// Stats counts submissions by outcome
+type Stats struct {
+	Submitted int64 `json:"submitted"`
+	Delivered int64 `json:"delivered"`
+	Failed    int64 `json:"failed"`
+}
+
+// Producer publishes score submissions. It is safe for concurrent use.
+type Producer struct {
+	config   Config
+	producer sarama.AsyncProducer
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+
+	submitted atomic.Int64
+	delivered atomic.Int64
+	failed    atomic.Int64
+}
+
+// New connects a producer to the brokers
+func New(cfg Config) (*Producer, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("producer: no brokers")
+	}
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("producer: no topic")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+
+	saramaConfig := cfg.Sarama
+	if saramaConfig == nil {
+		saramaConfig = sarama.NewConfig()
+		saramaConfig.Producer.RequiredAcks = sarama.WaitForLocal
+		saramaConfig.Producer.Compression = sarama.CompressionSnappy
+	}
+	saramaConfig.Producer.Flush.Messages = cfg.BatchSize
+	saramaConfig.Producer.Flush.Frequency = cfg.FlushInterval
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+
+	asyncProducer, err := sarama.NewAsyncProducer(cfg.Brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("producer: connecting to kafka: %w", err)
+	}
+
+	p := &Producer{config: cfg, producer: asyncProducer}
+	p.wg.Add(2)
+	go p.readSuccesses()
+	go p.readErrors()
+	return p, nil
+}
+
+// Submit queues a submission for delivery, generating its idempotency key if
+// it has none. It blocks only while the send buffer is full, until ctx is done.
+func (p *Producer) Submit(ctx context.Context, submission ScoreSubmission) error {
+	if submission.PlayerID == "" {
+		return ErrMissingPlayer
+	}
+	if submission.IdempotencyKey == "" {
+		submission.IdempotencyKey = uuid.NewString()
+	}
+	value, err := json.Marshal(submission)
+	if err != nil {
+		return fmt.Errorf("producer: encoding submission: %w", err)
+	}
+	msg := &sarama.ProducerMessage{
+		Topic:    p.config.Topic,
+		Key:      sarama.StringEncoder(submission.PlayerID),
+		Value:    sarama.ByteEncoder(value),
+		Metadata: submission,
+	}
+
+	// Hold the read lock so Close cannot shut the input while sending
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.producer.Input() <- msg:
+		p.submitted.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the producer's counters
+func (p *Producer) Stats() Stats {
+	return Stats{
+		Submitted: p.submitted.Load(),
+		Delivered: p.delivered.Load(),
+		Failed:    p.failed.Load(),
+	}
+}
+
+// Close sends queued submissions, reports their deliveries and disconnects
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.producer.AsyncClose()
+	p.wg.Wait()
+	return nil
+}
+
+// readSuccesses reports acknowledged messages until the producer closes
+func (p *Producer) readSuccesses() {
+	defer p.wg.Done()
+	for msg := range p.producer.Successes() {
+		p.delivered.Add(1)
+		p.report(Delivery{
+			Submission: msg.Metadata.(ScoreSubmission),
+			Partition:  msg.Partition,
+			Offset:     msg.Offset,
+		})
+	}
+}
+
+// readErrors reports failed messages until the producer closes
+func (p *Producer) readErrors() {
+	defer p.wg.Done()
+	for err := range p.producer.Errors() {
+		p.failed.Add(1)
+		p.report(Delivery{
+			Submission: err.Msg.Metadata.(ScoreSubmission),
+			Partition:  err.Msg.Partition,
+			Offset:     err.Msg.Offset,
+			Err:        err.Err,
+		})
+	}
+}
+
+func (p *Producer) report(d Delivery) {
+	if p.config.OnDelivery != nil {
+		p.config.OnDelivery(d)
+	}
+}